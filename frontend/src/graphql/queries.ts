@@ -156,26 +156,35 @@ export const FETCH_SOURCE = gql`
     fetchSource(sourceID: $sourceID) {
       id
       sourceID
-      sourceType
       status
+      phase
+      itemsTotal
+      itemsProcessed
+      iocsExtracted
+      historyID
+      error
+      createdAt
       startedAt
-      completedAt
-      processingTime
-      urls
-      itemsFetched
-      ioCsExtracted
-      ioCsCreated
-      ioCsUpdated
-      ioCsUnchanged
-      errorCount
-      errors {
-        message
-        values {
-          key
-          value
-        }
-      }
+      finishedAt
+    }
+  }
+`
+
+export const GET_JOB = gql`
+  query GetJob($id: ID!) {
+    job(id: $id) {
+      id
+      sourceID
+      status
+      phase
+      itemsTotal
+      itemsProcessed
+      iocsExtracted
+      historyID
+      error
       createdAt
+      startedAt
+      finishedAt
     }
   }
 `
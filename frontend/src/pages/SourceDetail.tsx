@@ -1,7 +1,7 @@
-import { useLazyQuery, useMutation, useQuery } from '@apollo/client'
+import { useMutation, useQuery } from '@apollo/client'
 import { useEffect, useState } from 'react'
 import { Link, useParams } from 'react-router-dom'
-import { FETCH_SOURCE, GET_HISTORY, GET_SOURCE, LIST_HISTORIES } from '../graphql/queries'
+import { FETCH_SOURCE, GET_JOB, GET_SOURCE, LIST_HISTORIES } from '../graphql/queries'
 import styles from './SourceDetail.module.css'
 
 interface SourceState {
@@ -62,12 +62,40 @@ interface ListHistoriesData {
   }
 }
 
-interface GetHistoryData {
-  getHistory: History | null
+interface Job {
+  id: string
+  sourceID: string
+  status: string
+  phase?: string
+  itemsTotal: number
+  itemsProcessed: number
+  iocsExtracted: number
+  historyID?: string
+  error?: string
+  createdAt: string
+  startedAt?: string
+  finishedAt?: string
+}
+
+interface GetJobData {
+  job: Job | null
 }
 
 interface FetchSourceData {
-  fetchSource: History
+  fetchSource: Job
+}
+
+const jobDone = (status: string) =>
+  status === 'succeeded' || status === 'failed' || status === 'canceled'
+
+// Describes a queued or running job, e.g. "Fetching (extract 3/10)..."
+function jobProgress(job: Job): string {
+  if (job.status === 'queued') return 'Queued...'
+  if (!job.phase) return 'Fetching...'
+  if (job.itemsTotal > 0) {
+    return `Fetching (${job.phase} ${job.itemsProcessed}/${job.itemsTotal})...`
+  }
+  return `Fetching (${job.phase})...`
 }
 
 // CSS class mappings for status badges
@@ -80,7 +108,7 @@ const statusBadgeClasses: Record<string, string> = {
 
 function SourceDetail() {
   const { id } = useParams<{ id: string }>()
-  const [pollingHistoryId, setPollingHistoryId] = useState<string | null>(null)
+  const [jobId, setJobId] = useState<string | null>(null)
   const [fetchError, setFetchError] = useState<string | null>(null)
 
   const { loading: sourceLoading, error: sourceError, data: sourceData } = useQuery<GetSourceData>(
@@ -106,13 +134,12 @@ function SourceDetail() {
     {
       onCompleted: (data) => {
         setFetchError(null)
-        const history = data.fetchSource
-        // Start polling if status is not completed
-        if (history.status !== 'success' && history.status !== 'error') {
-          setPollingHistoryId(history.id)
-        } else {
-          // If already completed, just refetch histories
+        const job = data.fetchSource
+        if (jobDone(job.status)) {
           refetchHistories()
+        } else {
+          // Follow the job until it finishes
+          setJobId(job.id)
         }
       },
       onError: (error) => {
@@ -121,39 +148,31 @@ function SourceDetail() {
     }
   )
 
-  const [getHistoryQuery, { stopPolling }] = useLazyQuery<GetHistoryData>(GET_HISTORY, {
+  const { data: jobData } = useQuery<GetJobData>(GET_JOB, {
+    variables: { id: jobId! },
+    skip: !jobId,
+    pollInterval: 2000,
     fetchPolicy: 'network-only',
-    onCompleted: (data) => {
-      if (data.getHistory) {
-        // Stop polling if status is completed
-        if (data.getHistory.status === 'success' || data.getHistory.status === 'error') {
-          setPollingHistoryId(null)
-          stopPolling()
-          refetchHistories()
-        }
-      }
-    },
   })
+  const job = jobId ? jobData?.job : undefined
 
-  // Polling effect
   useEffect(() => {
-    if (!pollingHistoryId || !id) return
-
-    // Start polling with 2 second interval
-    const intervalId = setInterval(() => {
-      getHistoryQuery({
-        variables: {
-          sourceID: id,
-          id: pollingHistoryId,
-        },
-      })
-    }, 2000)
-
-    return () => {
-      clearInterval(intervalId)
-      stopPolling()
+    if (!jobId || !jobData) return
+    const current = jobData.job
+    if (!current) {
+      // Finished jobs are pruned after the retention period
+      setJobId(null)
+      refetchHistories()
+      return
+    }
+    if (jobDone(current.status)) {
+      setJobId(null)
+      if (current.status === 'failed') {
+        setFetchError(current.error || 'Fetch failed')
+      }
+      refetchHistories()
     }
-  }, [pollingHistoryId, id, getHistoryQuery, stopPolling])
+  }, [jobId, jobData, refetchHistories])
 
   const handleFetch = () => {
     if (!id) return
@@ -324,15 +343,17 @@ function SourceDetail() {
         <div className={styles.fetchSection}>
           <h2 className={styles.sectionTitle}>Fetch History</h2>
           <div className={styles.fetchControls}>
-            {pollingHistoryId && (
-              <span className={styles.fetchingMessage}>Fetching...</span>
+            {jobId && (
+              <span className={styles.fetchingMessage}>
+                {job ? jobProgress(job) : 'Fetching...'}
+              </span>
             )}
             {fetchError && (
               <span className={styles.errorMessage}>{fetchError}</span>
             )}
             <button
               onClick={handleFetch}
-              disabled={fetchLoading || !!pollingHistoryId}
+              disabled={fetchLoading || !!jobId}
               className={styles.fetchButton}
             >
               Fetch Now
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"log/slog"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/cli/config"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/service/audit"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+// newAuditor creates the auditor of state-changing operations writing to repo
+// and the configured mirror. The returned function closes the mirror.
+func newAuditor(logger *slog.Logger, cfg *config.Audit, repo interfaces.AuditRepository) (*usecase.Auditor, func(), error) {
+	if cfg.File == "" {
+		return usecase.NewAuditor(repo), func() {}, nil
+	}
+
+	mirror, err := audit.NewJSONL(cfg.File)
+	if err != nil {
+		return nil, nil, goerr.Wrap(err, "failed to create audit log mirror")
+	}
+	logger.Info("mirroring audit log", "file", cfg.File)
+
+	return usecase.NewAuditor(repo, usecase.WithAuditMirror(mirror)), func() {
+		if err := mirror.Close(); err != nil {
+			logger.Error("failed to close audit log mirror", "error", err)
+		}
+	}, nil
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/cli/config"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+	"github.com/urfave/cli/v3"
+)
+
+func cmdCache() *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "Manage the LLM extraction cache",
+		Commands: []*cli.Command{
+			cmdCachePurge(),
+		},
+	}
+}
+
+func cmdCachePurge() *cli.Command {
+	var (
+		firestoreCfg config.Firestore
+		cacheCfg     config.ExtractionCache
+		sourceID     string
+		expired      bool
+		all          bool
+	)
+
+	return &cli.Command{
+		Name:  "purge",
+		Usage: "Delete cached LLM extraction results",
+		Flags: append(append(cacheCfg.Flags(), firestoreCfg.Flags()...),
+			&cli.StringFlag{
+				Name:        "source",
+				Usage:       "Delete entries stored by this source",
+				Destination: &sourceID,
+			},
+			&cli.BoolFlag{
+				Name:        "expired",
+				Usage:       "Delete entries past their TTL",
+				Destination: &expired,
+			},
+			&cli.BoolFlag{
+				Name:        "all",
+				Usage:       "Delete all entries",
+				Destination: &all,
+			},
+		),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			logger := logging.Default()
+
+			if !cacheCfg.Enabled() {
+				return goerr.New("llm-cache is not configured", goerr.V("backend", cacheCfg.Backend))
+			}
+			if sourceID == "" && !expired && !all {
+				return goerr.New("one of --source, --expired or --all is required")
+			}
+
+			opts := &model.ExtractionCachePurgeOptions{SourceID: sourceID}
+			if expired {
+				opts.Before = time.Now()
+			}
+
+			var repo interfaces.ExtractionCacheRepository
+			if cacheCfg.Backend == config.CacheBackendRepository {
+				if firestoreCfg.ProjectID == "" {
+					return goerr.New("firestore-project-id is required for the repository cache backend")
+				}
+
+				fsOpts := []firestoreRepo.Option{}
+				if firestoreCfg.DatabaseID != "" {
+					fsOpts = append(fsOpts, firestoreRepo.WithDatabaseID(firestoreCfg.DatabaseID))
+				}
+				fsRepo, err := firestoreRepo.New(ctx, firestoreCfg.ProjectID, fsOpts...)
+				if err != nil {
+					return goerr.Wrap(err, "failed to create Firestore repository",
+						goerr.V("project_id", firestoreCfg.ProjectID),
+						goerr.V("database_id", firestoreCfg.DatabaseID))
+				}
+				defer func() {
+					if err := fsRepo.Close(); err != nil {
+						logger.Error("failed to close Firestore client", "error", err)
+					}
+				}()
+				repo = fsRepo
+			}
+
+			cache, err := cacheCfg.New(repo)
+			if err != nil {
+				return goerr.Wrap(err, "failed to open LLM cache")
+			}
+
+			deleted, err := cache.PurgeExtractionCache(ctx, opts)
+			if err != nil {
+				return goerr.Wrap(err, "failed to purge LLM cache", goerr.V("deleted", deleted))
+			}
+
+			logger.Info("purged LLM extraction cache",
+				"backend", cacheCfg.Backend,
+				"source_id", sourceID,
+				"expired", expired,
+				"deleted", deleted)
+			return nil
+		},
+	}
+}
@@ -10,13 +10,15 @@ import (
 
 func Run(ctx context.Context, args []string, version string) error {
 	var loggerCfg config.Logger
+	var tracingCfg config.Tracing
 	var closer func()
+	var shutdownTracing func(context.Context) error
 
 	app := &cli.Command{
 		Name:    "beehive",
 		Usage:   "Beehive IoC (Indicator of Compromise) management system",
 		Version: version,
-		Flags:   loggerCfg.Flags(),
+		Flags:   append(loggerCfg.Flags(), tracingCfg.Flags()...),
 		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
 			f, err := loggerCfg.Configure()
 			if err != nil {
@@ -24,10 +26,22 @@ func Run(ctx context.Context, args []string, version string) error {
 			}
 			closer = f
 
-			logging.Default().Info("Starting beehive", "logger", loggerCfg)
+			shutdown, err := tracingCfg.Configure(ctx)
+			if err != nil {
+				return ctx, err
+			}
+			shutdownTracing = shutdown
+
+			logging.Default().Info("Starting beehive", "logger", loggerCfg, "tracing", tracingCfg)
 			return ctx, nil
 		},
 		After: func(ctx context.Context, c *cli.Command) error {
+			// Flush buffered spans before the log file is closed
+			if shutdownTracing != nil {
+				if err := shutdownTracing(context.WithoutCancel(ctx)); err != nil {
+					logging.Default().Warn("failed to shut down tracing", "error", err)
+				}
+			}
 			if closer != nil {
 				closer()
 			}
@@ -37,6 +51,12 @@ func Run(ctx context.Context, args []string, version string) error {
 			cmdServe(),
 			cmdFetch(),
 			cmdMigrate(),
+			cmdSweep(),
+			cmdNotify(),
+			cmdCache(),
+			cmdEval(),
+			cmdReembed(),
+			cmdSource(),
 		},
 	}
 
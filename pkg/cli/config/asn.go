@@ -0,0 +1,54 @@
+package config
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/service/asn"
+)
+
+// ASN represents the IP-to-ASN lookup matching ASN watch patterns against the
+// hosts of IP, domain and URL IoCs
+type ASN struct {
+	// IP-to-ASN table in the TSV format of iptoasn.com, optionally gzipped
+	// (e.g. ip2asn-combined.tsv.gz). ASN patterns only match AS numbers
+	// mentioned in IoCs if unset.
+	Table string `toml:"table,omitempty"`
+	// Resolve domain names with DNS to look up their ASNs
+	ResolveHosts bool `toml:"resolve_hosts,omitempty"`
+}
+
+// Validate validates ASN lookup configuration
+func (a *ASN) Validate() error {
+	if a.Table == "" {
+		if a.ResolveHosts {
+			return goerr.New("resolve_hosts requires table")
+		}
+		return nil
+	}
+	if _, err := os.Stat(filepath.Clean(a.Table)); err != nil {
+		return goerr.Wrap(err, "ASN table not found", goerr.V("table", a.Table))
+	}
+	return nil
+}
+
+// NewResolver loads the table and creates the resolver. Returns nil if no
+// table is configured.
+func (a *ASN) NewResolver() (*asn.Resolver, error) {
+	if a.Table == "" {
+		return nil, nil
+	}
+
+	table, err := asn.LoadTable(a.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []asn.Option
+	if a.ResolveHosts {
+		opts = append(opts, asn.WithHostResolution(net.DefaultResolver))
+	}
+	return asn.New(table, opts...), nil
+}
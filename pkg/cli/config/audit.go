@@ -0,0 +1,23 @@
+package config
+
+import (
+	"github.com/urfave/cli/v3"
+)
+
+// Audit represents the configuration of the audit log. Records are always
+// stored in the repository; File adds a JSONL mirror.
+type Audit struct {
+	File string
+}
+
+// Flags returns CLI flags for the audit log configuration
+func (a *Audit) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "audit-log-file",
+			Usage:       "Append a copy of every audit record to a JSONL file",
+			Destination: &a.File,
+			Sources:     cli.EnvVars("BEEHIVE_AUDIT_LOG_FILE"),
+		},
+	}
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/service/auth"
+)
+
+// Auth represents authentication of the HTTP server. Without any
+// authenticator, the server is open and every request is an admin.
+type Auth struct {
+	AnonymousRole string      `toml:"anonymous_role,omitempty"` // Role of requests without credentials, rejected if empty
+	JWT           *JWTAuth    `toml:"jwt,omitempty"`
+	Header        *HeaderAuth `toml:"header,omitempty"`
+	Tokens        []AuthToken `toml:"tokens,omitempty"`
+}
+
+// JWTAuth represents OIDC/JWT bearer token validation
+type JWTAuth struct {
+	JWKSURL      string            `toml:"jwks_url"`
+	Issuer       string            `toml:"issuer,omitempty"`
+	Audience     string            `toml:"audience,omitempty"`
+	SubjectClaim string            `toml:"subject_claim,omitempty"` // Default: sub
+	RoleClaim    string            `toml:"role_claim,omitempty"`    // Default: role
+	Roles        map[string]string `toml:"roles,omitempty"`         // Role claim value to role
+	DefaultRole  string            `toml:"default_role,omitempty"`  // Role of tokens without a known role
+}
+
+// HeaderAuth represents users authenticated by a trusted proxy such as IAP
+type HeaderAuth struct {
+	Name        string            `toml:"name"`                   // e.g. X-Goog-Authenticated-User-Email
+	Roles       map[string]string `toml:"roles,omitempty"`        // User to role
+	DefaultRole string            `toml:"default_role,omitempty"` // Role of users not in roles
+}
+
+// AuthToken represents a static API token. Only its hash is configured.
+type AuthToken struct {
+	Name string `toml:"name"`
+	Hash string `toml:"hash"` // sha256:<hex> of the token
+	Role string `toml:"role"`
+}
+
+// Enabled reports whether any authenticator is configured
+func (a *Auth) Enabled() bool {
+	return a.JWT != nil || a.Header != nil || len(a.Tokens) > 0
+}
+
+// Validate validates authentication configuration
+func (a *Auth) Validate() error {
+	if a.AnonymousRole != "" && !a.Enabled() {
+		return goerr.New("anonymous_role requires an authenticator")
+	}
+	if _, err := a.New(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// New creates the authenticator of the server. authenticators that are not
+// configured in the file, e.g. of API tokens in the repository, are tried
+// first. Returns nil if no authenticator is configured.
+func (a *Auth) New(authenticators ...interfaces.Authenticator) (interfaces.Authenticator, error) {
+	if !a.Enabled() {
+		return nil, nil
+	}
+
+	anonymous, err := parseOptionalRole(a.AnonymousRole)
+	if err != nil {
+		return nil, goerr.Wrap(err, "invalid anonymous_role")
+	}
+
+	if len(a.Tokens) > 0 {
+		tokens := make([]auth.StaticToken, len(a.Tokens))
+		for i, t := range a.Tokens {
+			role, err := model.ParseRole(t.Role)
+			if err != nil {
+				return nil, goerr.Wrap(err, "invalid token role", goerr.V("name", t.Name))
+			}
+			tokens[i] = auth.StaticToken{Name: t.Name, Hash: t.Hash, Role: role}
+		}
+		authn, err := auth.NewStaticTokens(tokens)
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid auth tokens")
+		}
+		authenticators = append(authenticators, authn)
+	}
+
+	if a.JWT != nil {
+		authn, err := a.JWT.New()
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid auth jwt")
+		}
+		authenticators = append(authenticators, authn)
+	}
+
+	if a.Header != nil {
+		authn, err := a.Header.New()
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid auth header")
+		}
+		authenticators = append(authenticators, authn)
+	}
+
+	return auth.NewChain(anonymous, authenticators...), nil
+}
+
+// New creates the JWT authenticator
+func (j *JWTAuth) New() (*auth.JWT, error) {
+	if j.JWKSURL == "" {
+		return nil, goerr.New("jwks_url is required")
+	}
+	defaultRole, err := parseOptionalRole(j.DefaultRole)
+	if err != nil {
+		return nil, goerr.Wrap(err, "invalid default_role")
+	}
+	opts := []auth.JWTOption{
+		auth.WithIssuer(j.Issuer),
+		auth.WithAudience(j.Audience),
+		auth.WithSubjectClaim(j.SubjectClaim),
+		auth.WithRoleClaim(j.RoleClaim),
+		auth.WithJWTDefaultRole(defaultRole),
+	}
+	if len(j.Roles) > 0 {
+		roles, err := parseRoles(j.Roles)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, auth.WithRoleMapping(roles))
+	}
+	return auth.NewJWT(j.JWKSURL, opts...), nil
+}
+
+// New creates the trusted header authenticator
+func (h *HeaderAuth) New() (*auth.TrustedHeader, error) {
+	if h.Name == "" {
+		return nil, goerr.New("header name is required")
+	}
+	defaultRole, err := parseOptionalRole(h.DefaultRole)
+	if err != nil {
+		return nil, goerr.Wrap(err, "invalid default_role")
+	}
+	roles, err := parseRoles(h.Roles)
+	if err != nil {
+		return nil, err
+	}
+	return auth.NewTrustedHeader(h.Name,
+		auth.WithHeaderDefaultRole(defaultRole),
+		auth.WithHeaderRoles(roles),
+	), nil
+}
+
+func parseOptionalRole(s string) (model.Role, error) {
+	if s == "" {
+		return "", nil
+	}
+	return model.ParseRole(s)
+}
+
+func parseRoles(raw map[string]string) (map[string]model.Role, error) {
+	roles := make(map[string]model.Role, len(raw))
+	for name, s := range raw {
+		role, err := model.ParseRole(s)
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid role", goerr.V("name", name))
+		}
+		roles[name] = role
+	}
+	return roles, nil
+}
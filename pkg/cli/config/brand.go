@@ -0,0 +1,48 @@
+package config
+
+import (
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/brand"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// Brand represents brand protection configuration. Newly ingested domain and URL
+// IoCs imitating the protected domains are recorded as lookalike matches.
+type Brand struct {
+	Domains    []string `toml:"domains,omitempty"`    // Protected registrable domains, e.g. example.com
+	Techniques []string `toml:"techniques,omitempty"` // Enabled techniques (default: all)
+	MinScore   float64  `toml:"min_score,omitempty"`  // Minimum similarity score, 0.0-1.0
+}
+
+// Validate validates brand protection configuration
+func (b *Brand) Validate() error {
+	if b.MinScore < 0 || b.MinScore > 1 {
+		return goerr.New("min_score must be between 0 and 1", goerr.V("min_score", b.MinScore))
+	}
+	if _, err := b.NewDetector(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewDetector creates the lookalike detector. Returns nil if no domain is protected.
+func (b *Brand) NewDetector() (*brand.Detector, error) {
+	if len(b.Domains) == 0 {
+		return nil, nil
+	}
+
+	opts := []brand.Option{brand.WithMinScore(b.MinScore)}
+	if len(b.Techniques) > 0 {
+		techniques := make([]model.LookalikeTechnique, len(b.Techniques))
+		for i, t := range b.Techniques {
+			techniques[i] = model.LookalikeTechnique(t)
+		}
+		opts = append(opts, brand.WithTechniques(techniques...))
+	}
+
+	detector, err := brand.New(b.Domains, opts...)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create lookalike detector")
+	}
+	return detector, nil
+}
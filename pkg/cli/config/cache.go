@@ -0,0 +1,80 @@
+package config
+
+import (
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/repository/filecache"
+	"github.com/urfave/cli/v3"
+)
+
+// Extraction cache backends
+const (
+	CacheBackendNone       = "none"
+	CacheBackendRepository = "repository"
+	CacheBackendDir        = "dir"
+)
+
+var (
+	errUnsupportedCacheBackend = goerr.New("unsupported LLM cache backend")
+)
+
+// ExtractionCache represents the configuration of the LLM extraction cache
+type ExtractionCache struct {
+	Backend string
+	Dir     string
+	TTL     time.Duration
+}
+
+// Flags returns CLI flags for the extraction cache configuration
+func (c *ExtractionCache) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "llm-cache",
+			Usage:       "Cache of LLM extraction results (none, repository, dir)",
+			Value:       CacheBackendNone,
+			Destination: &c.Backend,
+			Sources:     cli.EnvVars("BEEHIVE_LLM_CACHE"),
+		},
+		&cli.StringFlag{
+			Name:        "llm-cache-dir",
+			Usage:       "Directory of the LLM cache for the dir backend",
+			Value:       ".beehive/cache",
+			Destination: &c.Dir,
+			Sources:     cli.EnvVars("BEEHIVE_LLM_CACHE_DIR"),
+		},
+		&cli.DurationFlag{
+			Name:        "llm-cache-ttl",
+			Usage:       "Lifetime of cached LLM extraction results (0 keeps them until purged)",
+			Value:       30 * 24 * time.Hour,
+			Destination: &c.TTL,
+			Sources:     cli.EnvVars("BEEHIVE_LLM_CACHE_TTL"),
+		},
+	}
+}
+
+// Enabled returns true if a cache backend is configured
+func (c *ExtractionCache) Enabled() bool {
+	return c.Backend != "" && c.Backend != CacheBackendNone
+}
+
+// New returns the configured cache. The repository backend stores entries in
+// repo. Returns nil if the cache is disabled.
+func (c *ExtractionCache) New(repo interfaces.ExtractionCacheRepository) (interfaces.ExtractionCacheRepository, error) {
+	switch c.Backend {
+	case "", CacheBackendNone:
+		return nil, nil
+	case CacheBackendRepository:
+		return repo, nil
+	case CacheBackendDir:
+		cache, err := filecache.New(c.Dir)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to create LLM cache directory", goerr.V("dir", c.Dir))
+		}
+		return cache, nil
+	default:
+		return nil, goerr.Wrap(errUnsupportedCacheBackend, "invalid LLM cache backend",
+			goerr.V("backend", c.Backend))
+	}
+}
@@ -4,9 +4,15 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
 	"github.com/secmon-lab/beehive/pkg/domain/types"
 )
 
@@ -14,15 +20,57 @@ import (
 type Config struct {
 	RSS  map[string]RSSSource  `toml:"rss"`
 	Feed map[string]FeedSource `toml:"feed"`
+
+	// Default IoC TTL per type, overriding model.DefaultIoCTTLs
+	TTL    map[model.IoCType]time.Duration `toml:"-"` // Not directly unmarshaled
+	RawTTL map[string]string               `toml:"ttl,omitempty"`
+
+	Confidence Confidence `toml:"confidence,omitempty"`
+
+	Notify Notify `toml:"notify,omitempty"`
+
+	Brand Brand `toml:"brand,omitempty"`
+
+	ASN ASN `toml:"asn,omitempty"`
+
+	Auth Auth `toml:"auth,omitempty"`
+}
+
+// Confidence represents confidence scoring parameters. Unset values use model defaults.
+type Confidence struct {
+	DefaultReliability *float64       `toml:"default_reliability,omitempty"`
+	LLMFactor          *float64       `toml:"llm_factor,omitempty"`
+	HalfLife           *time.Duration `toml:"-"` // Not directly unmarshaled
+	RawHalfLife        string         `toml:"half_life,omitempty"`
 }
 
 // RSSSource represents RSS-specific configuration
 type RSSSource struct {
-	URL         string     `toml:"url"`
-	Tags        types.Tags `toml:"-"` // Not directly unmarshaled
-	RawTags     []string   `toml:"tags,omitempty"`
-	Disabled    bool       `toml:"disabled,omitempty"`
-	MaxArticles int        `toml:"max_articles,omitempty"`
+	URL         string         `toml:"url"`
+	Description string         `toml:"description,omitempty"`
+	Tags        types.Tags     `toml:"-"` // Not directly unmarshaled
+	RawTags     []string       `toml:"tags,omitempty"`
+	Disabled    bool           `toml:"disabled,omitempty"`
+	MaxArticles int            `toml:"max_articles,omitempty"`
+	TTL         *time.Duration `toml:"-"` // Not directly unmarshaled
+	RawTTL      string         `toml:"ttl,omitempty"`
+	Reliability *float64       `toml:"reliability,omitempty"` // 0-1, used for confidence scoring
+	// Extraction mode for articles: llm (default), regex, prefilter or crosscheck
+	Extraction model.ExtractionMode `toml:"extraction,omitempty"`
+
+	// LLM extraction prompt customization (llm and crosscheck modes). Prompt or
+	// the file PromptFile replaces the built-in template; Instructions,
+	// Examples and Language are rendered into it.
+	Prompt       string                `toml:"prompt,omitempty"`
+	PromptFile   string                `toml:"prompt_file,omitempty"`
+	Instructions string                `toml:"instructions,omitempty"`
+	Examples     []model.PromptExample `toml:"examples,omitempty"`
+	Language     string                `toml:"language,omitempty"`
+	// Types restricts extraction to these IoC types in all modes
+	Types    []model.IoCType `toml:"-"` // Not directly unmarshaled
+	RawTypes []string        `toml:"types,omitempty"`
+	// ExtractionPrompt is the validated prompt customization, nil if not customized
+	ExtractionPrompt *model.ExtractionPrompt `toml:"-"`
 }
 
 // FeedSource represents feed-specific configuration
@@ -34,10 +82,50 @@ type FeedSource struct {
 	RawTags   []string         `toml:"tags,omitempty"`
 	Disabled  bool             `toml:"disabled,omitempty"`
 	MaxItems  int              `toml:"max_items,omitempty"`
+	TTL       *time.Duration   `toml:"-"` // Not directly unmarshaled
+	RawTTL    string           `toml:"ttl,omitempty"`
+	// Reliability of the source (0-1), used for confidence scoring
+	Reliability *float64 `toml:"reliability,omitempty"`
+	// Description of the source shown in the UI
+	Description string `toml:"description,omitempty"`
 }
 
 // Validate validates the entire configuration
 func (c *Config) Validate() error {
+	// Per-type TTL validation and conversion
+	c.TTL = make(map[model.IoCType]time.Duration, len(c.RawTTL))
+	for rawType, rawTTL := range c.RawTTL {
+		iocType := model.IoCType(rawType)
+		if !iocType.IsValid() {
+			return goerr.New("unknown IoC type in ttl", goerr.V("type", rawType))
+		}
+		ttl, err := ParseTTL(rawTTL)
+		if err != nil {
+			return goerr.Wrap(err, "invalid ttl", goerr.V("type", rawType))
+		}
+		c.TTL[iocType] = ttl
+	}
+
+	if err := c.Confidence.Validate(); err != nil {
+		return goerr.Wrap(err, "invalid confidence config")
+	}
+
+	if err := c.Notify.Validate(); err != nil {
+		return goerr.Wrap(err, "invalid notify config")
+	}
+
+	if err := c.Brand.Validate(); err != nil {
+		return goerr.Wrap(err, "invalid brand config")
+	}
+
+	if err := c.ASN.Validate(); err != nil {
+		return goerr.Wrap(err, "invalid asn config")
+	}
+
+	if err := c.Auth.Validate(); err != nil {
+		return goerr.Wrap(err, "invalid auth config")
+	}
+
 	// Check for duplicate source IDs across RSS and Feed
 	seenIDs := make(map[string]bool)
 
@@ -94,6 +182,75 @@ func (r *RSSSource) Validate() error {
 		return goerr.New("max_articles must be >= 0", goerr.V("max_articles", r.MaxArticles))
 	}
 
+	// TTL override (if specified)
+	if r.RawTTL != "" {
+		ttl, err := ParseTTL(r.RawTTL)
+		if err != nil {
+			return goerr.Wrap(err, "invalid ttl")
+		}
+		r.TTL = &ttl
+	}
+
+	if err := validateRatio("reliability", r.Reliability); err != nil {
+		return err
+	}
+
+	if r.Extraction != "" && !r.Extraction.IsValid() {
+		return goerr.New("invalid extraction mode", goerr.V("extraction", r.Extraction))
+	}
+
+	if err := r.validatePrompt(); err != nil {
+		return goerr.Wrap(err, "invalid extraction prompt")
+	}
+
+	return nil
+}
+
+// validatePrompt builds ExtractionPrompt from the prompt settings and checks that
+// its template renders, so that template errors are found at config load
+func (r *RSSSource) validatePrompt() error {
+	r.Types = nil
+	for _, raw := range r.RawTypes {
+		iocType := model.IoCType(raw)
+		if !iocType.IsValid() {
+			return goerr.New("unknown IoC type in types", goerr.V("type", raw))
+		}
+		r.Types = append(r.Types, iocType)
+	}
+
+	if r.Prompt != "" && r.PromptFile != "" {
+		return goerr.New("prompt and prompt_file cannot be used together")
+	}
+	tmpl := r.Prompt
+	if r.PromptFile != "" {
+		data, err := os.ReadFile(filepath.Clean(r.PromptFile))
+		if err != nil {
+			return goerr.Wrap(err, "failed to read prompt file", goerr.V("path", r.PromptFile))
+		}
+		tmpl = string(data)
+	}
+
+	customized := tmpl != "" || r.Instructions != "" || len(r.Examples) > 0 || r.Language != ""
+	// The prompt is not used in regex mode, and prefilter mode has its own prompt
+	if customized && (r.Extraction == model.ExtractionModeRegex || r.Extraction == model.ExtractionModePrefilter) {
+		return goerr.New("prompt settings require llm or crosscheck extraction mode",
+			goerr.V("extraction", r.Extraction))
+	}
+	if !customized && len(r.Types) == 0 {
+		r.ExtractionPrompt = nil
+		return nil
+	}
+
+	r.ExtractionPrompt = &model.ExtractionPrompt{
+		Template:     tmpl,
+		Instructions: r.Instructions,
+		Examples:     r.Examples,
+		Types:        r.Types,
+		Language:     r.Language,
+	}
+	if _, err := extractor.NewPrompt(r.ExtractionPrompt); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -131,6 +288,19 @@ func (f *FeedSource) Validate() error {
 		return goerr.New("max_items must be >= 0", goerr.V("max_items", f.MaxItems))
 	}
 
+	// TTL override (if specified)
+	if f.RawTTL != "" {
+		ttl, err := ParseTTL(f.RawTTL)
+		if err != nil {
+			return goerr.Wrap(err, "invalid ttl")
+		}
+		f.TTL = &ttl
+	}
+
+	if err := validateRatio("reliability", f.Reliability); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -144,6 +314,113 @@ func (f *FeedSource) GetURL() string {
 	return f.Schema.DefaultURL()
 }
 
+// TTLPolicy returns the IoC TTL policy with configured per-type overrides applied.
+// This method assumes the config has been validated.
+func (c *Config) TTLPolicy() model.TTLPolicy {
+	return model.NewTTLPolicy(c.TTL)
+}
+
+// SourcesRequiringLLM returns the sorted IDs of the enabled RSS sources whose
+// extraction mode uses the LLM.
+// This method assumes the config has been validated.
+func (c *Config) SourcesRequiringLLM() []string {
+	var ids []string
+	for id, src := range c.RSS {
+		mode := src.Extraction
+		if mode == "" {
+			mode = model.ExtractionModeLLM
+		}
+		if !src.Disabled && mode.UsesLLM() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Validate validates confidence parameters and converts raw values
+func (c *Confidence) Validate() error {
+	if err := validateRatio("default_reliability", c.DefaultReliability); err != nil {
+		return err
+	}
+	if err := validateRatio("llm_factor", c.LLMFactor); err != nil {
+		return err
+	}
+
+	if c.RawHalfLife != "" {
+		halfLife, err := ParseTTL(c.RawHalfLife)
+		if err != nil {
+			return goerr.Wrap(err, "invalid half_life")
+		}
+		c.HalfLife = &halfLife
+	}
+
+	return nil
+}
+
+// ConfidencePolicy returns the confidence scoring policy with configured
+// source reliabilities and parameters applied.
+// This method assumes the config has been validated.
+func (c *Config) ConfidencePolicy() *model.ConfidencePolicy {
+	reliability := make(map[string]float64)
+	for id, src := range c.RSS {
+		if src.Reliability != nil {
+			reliability[id] = *src.Reliability
+		}
+	}
+	for id, src := range c.Feed {
+		if src.Reliability != nil {
+			reliability[id] = *src.Reliability
+		}
+	}
+
+	policy := model.NewConfidencePolicy(reliability)
+	if c.Confidence.DefaultReliability != nil {
+		policy.DefaultReliability = *c.Confidence.DefaultReliability
+	}
+	if c.Confidence.LLMFactor != nil {
+		policy.LLMFactor = *c.Confidence.LLMFactor
+	}
+	if c.Confidence.HalfLife != nil {
+		policy.HalfLife = *c.Confidence.HalfLife
+	}
+	return policy
+}
+
+// validateRatio checks that an optional value is within [0, 1]
+func validateRatio(name string, v *float64) error {
+	if v != nil && (*v < 0 || *v > 1) {
+		return goerr.New(name+" must be between 0 and 1", goerr.V(name, *v))
+	}
+	return nil
+}
+
+// ParseTTL parses a TTL string. In addition to Go durations ("720h"),
+// a day suffix ("30d") is accepted. "0" means never expire.
+func ParseTTL(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+
+	var ttl time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, goerr.Wrap(err, "invalid day count", goerr.V("ttl", s))
+		}
+		ttl = time.Duration(n) * model.Day
+	} else {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, goerr.Wrap(err, "invalid duration", goerr.V("ttl", s))
+		}
+		ttl = d
+	}
+
+	if ttl < 0 {
+		return 0, goerr.New("ttl must be >= 0", goerr.V("ttl", s))
+	}
+	return ttl, nil
+}
+
 // LoadConfig loads configuration from a TOML file
 func LoadConfig(path string) (*Config, error) {
 	// Clean the path to prevent directory traversal attacks
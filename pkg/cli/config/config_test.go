@@ -1,10 +1,15 @@
 package config_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/m-mizutani/gt"
 	"github.com/secmon-lab/beehive/pkg/cli/config"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/service/auth"
 )
 
 func TestRSSSourceValidate(t *testing.T) {
@@ -52,6 +57,46 @@ func TestRSSSourceValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid ttl in days",
+			src: config.RSSSource{
+				URL:    "https://example.com/feed",
+				RawTTL: "14d",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid ttl",
+			src: config.RSSSource{
+				URL:    "https://example.com/feed",
+				RawTTL: "two weeks",
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid extraction mode",
+			src: config.RSSSource{
+				URL:        "https://example.com/feed",
+				Extraction: model.ExtractionModePrefilter,
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown extraction mode",
+			src: config.RSSSource{
+				URL:        "https://example.com/feed",
+				Extraction: "magic",
+			},
+			wantErr: true,
+		},
+		{
+			name: "reliability out of range",
+			src: config.RSSSource{
+				URL:         "https://example.com/feed",
+				Reliability: ptr(1.5),
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -66,6 +111,70 @@ func TestRSSSourceValidate(t *testing.T) {
 	}
 }
 
+func TestRSSSourceValidate_Prompt(t *testing.T) {
+	dir := t.TempDir()
+	promptFile := filepath.Join(dir, "prompt.md")
+	gt.NoError(t, os.WriteFile(promptFile, []byte("Extract IoCs in {{.Language}} from:\n{{.Content}}"), 0o600))
+
+	configFile := filepath.Join(dir, "config.toml")
+	gt.NoError(t, os.WriteFile(configFile, []byte(`
+[rss.jp-vendor]
+url = "https://example.jp/feed"
+prompt_file = "`+promptFile+`"
+language = "Japanese"
+types = ["sha256", "domain"]
+instructions = "IoCs are listed in the appendix table."
+
+[[rss.jp-vendor.examples]]
+input = "| C2 | evil-example[.]net |"
+
+[[rss.jp-vendor.examples.iocs]]
+type = "domain"
+value = "evil-example.net"
+description = "C2 server"
+
+[rss.plain]
+url = "https://example.com/feed"
+`), 0o600))
+
+	cfg, err := config.LoadConfig(configFile)
+	gt.NoError(t, err)
+	gt.Equal(t, cfg.RSS["plain"].ExtractionPrompt, nil)
+
+	prompt := cfg.RSS["jp-vendor"].ExtractionPrompt
+	gt.NotEqual(t, prompt, nil)
+	gt.Equal(t, prompt.Template, "Extract IoCs in {{.Language}} from:\n{{.Content}}")
+	gt.Equal(t, prompt.Language, "Japanese")
+	gt.Equal(t, prompt.Types, []model.IoCType{model.IoCTypeSHA256, model.IoCTypeDomain})
+	gt.A(t, prompt.Examples).Length(1).At(0, func(t testing.TB, ex model.PromptExample) {
+		gt.Equal(t, ex.IoCs, []model.ExampleIoC{{Type: model.IoCTypeDomain, Value: "evil-example.net", Description: "C2 server"}})
+	})
+
+	t.Run("types only in regex mode", func(t *testing.T) {
+		src := config.RSSSource{URL: "https://example.com/feed", Extraction: model.ExtractionModeRegex, RawTypes: []string{"ipv4"}}
+		gt.NoError(t, src.Validate())
+		gt.Equal(t, src.ExtractionPrompt.Types, []model.IoCType{model.IoCTypeIPv4})
+	})
+
+	for name, src := range map[string]config.RSSSource{
+		"template error":         {Prompt: "{{.Content"},
+		"unknown template field": {Prompt: "{{.Body}}"},
+		"prompt and prompt_file": {Prompt: "{{.Content}}", PromptFile: promptFile},
+		"missing prompt_file":    {PromptFile: filepath.Join(dir, "missing.md")},
+		"unknown type":           {RawTypes: []string{"hostname"}},
+		"example type not in types": {
+			RawTypes: []string{"sha256"},
+			Examples: []model.PromptExample{{Input: "x", IoCs: []model.ExampleIoC{{Type: model.IoCTypeDomain, Value: "evil.example"}}}},
+		},
+		"prompt in regex mode": {Extraction: model.ExtractionModeRegex, Language: "Japanese"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			src.URL = "https://example.com/feed"
+			gt.Error(t, src.Validate())
+		})
+	}
+}
+
 func TestFeedSourceValidate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -124,6 +233,22 @@ func TestFeedSourceValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid ttl as duration",
+			src: config.FeedSource{
+				RawSchema: "abuse_ch_urlhaus",
+				RawTTL:    "72h",
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative ttl",
+			src: config.FeedSource{
+				RawSchema: "abuse_ch_urlhaus",
+				RawTTL:    "-1d",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -188,6 +313,22 @@ func TestConfigValidate(t *testing.T) {
 		gt.NoError(t, cfg.Validate())
 	})
 
+	t.Run("sources requiring an LLM", func(t *testing.T) {
+		cfg := &config.Config{
+			RSS: map[string]config.RSSSource{
+				"vendor-blog":  {URL: "https://example.com/rss"},
+				"crosscheck":   {URL: "https://example.com/rss", Extraction: model.ExtractionModeCrossCheck},
+				"regex-only":   {URL: "https://example.com/rss", Extraction: model.ExtractionModeRegex},
+				"disabled-llm": {URL: "https://example.com/rss", Disabled: true},
+			},
+			Feed: map[string]config.FeedSource{
+				"urlhaus": {RawSchema: "abuse_ch_urlhaus"},
+			},
+		}
+		gt.NoError(t, cfg.Validate())
+		gt.Equal(t, cfg.SourcesRequiringLLM(), []string{"crosscheck", "vendor-blog"})
+	})
+
 	t.Run("duplicate source ID", func(t *testing.T) {
 		cfg := &config.Config{
 			RSS: map[string]config.RSSSource{
@@ -258,4 +399,308 @@ func TestConfigValidate(t *testing.T) {
 		gt.A(t, feedSrc.Tags).Length(1).Describe("Feed tags should be populated")
 		gt.S(t, feedSrc.Tags[0].String()).Equal("threat-intel").Describe("Feed tag")
 	})
+	t.Run("ttl overrides", func(t *testing.T) {
+		cfg := &config.Config{
+			RawTTL: map[string]string{
+				"domain": "7d",
+				"ipv4":   "0",
+			},
+			RSS: map[string]config.RSSSource{
+				"blog": {
+					URL:    "https://example.com/feed",
+					RawTTL: "14d",
+				},
+			},
+		}
+		gt.NoError(t, cfg.Validate())
+
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		policy := cfg.TTLPolicy()
+		gt.Equal(t, policy.ExpiresAt(nil, model.IoCTypeDomain, from), from.Add(7*model.Day))
+		gt.True(t, policy.ExpiresAt(nil, model.IoCTypeIPv4, from).IsZero())
+		gt.Equal(t, policy.ExpiresAt(nil, model.IoCTypeURL, from), from.Add(30*model.Day))
+		gt.True(t, policy.ExpiresAt(nil, model.IoCTypeSHA256, from).IsZero())
+
+		sourceTTL := cfg.RSS["blog"].TTL
+		gt.NotEqual(t, sourceTTL, nil)
+		gt.Equal(t, policy.ExpiresAt(sourceTTL, model.IoCTypeSHA256, from), from.Add(14*model.Day))
+	})
+
+	t.Run("ttl for unknown IoC type", func(t *testing.T) {
+		cfg := &config.Config{
+			RawTTL: map[string]string{"hostname": "7d"},
+		}
+		gt.Error(t, cfg.Validate())
+	})
+	t.Run("confidence policy", func(t *testing.T) {
+		cfg := &config.Config{
+			Confidence: config.Confidence{
+				DefaultReliability: ptr(0.3),
+				RawHalfLife:        "7d",
+			},
+			Feed: map[string]config.FeedSource{
+				"threatfox": {
+					RawSchema:   "abuse_ch_threatfox",
+					Reliability: ptr(0.9),
+				},
+			},
+		}
+		gt.NoError(t, cfg.Validate())
+
+		policy := cfg.ConfidencePolicy()
+		gt.Equal(t, policy.Reliability("threatfox"), 0.9)
+		gt.Equal(t, policy.Reliability("other"), 0.3)
+		gt.Equal(t, policy.HalfLife, 7*model.Day)
+		gt.Equal(t, policy.LLMFactor, model.DefaultLLMExtractionFactor)
+	})
+
+	t.Run("invalid confidence config", func(t *testing.T) {
+		cfg := &config.Config{
+			Confidence: config.Confidence{LLMFactor: ptr(-0.1)},
+		}
+		gt.Error(t, cfg.Validate())
+	})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestNotifyValidate(t *testing.T) {
+	validChannels := map[string]config.NotifyChannel{
+		"hook": {Type: "webhook", URL: "https://example.com/hook"},
+	}
+
+	testCases := []struct {
+		name    string
+		notify  config.Notify
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			notify: config.Notify{
+				Channels: validChannels,
+				Rules: []config.NotifyRule{
+					{Name: "fail", Type: "source_failure", Channels: []string{"hook"}},
+					{Name: "watch", Type: "watchlist", Channels: []string{"hook"}, Patterns: []string{"*.evil.example"}, Types: []string{"domain"}},
+				},
+				RawBackoff: "500ms",
+			},
+		},
+		{
+			name: "unknown channel type",
+			notify: config.Notify{
+				Channels: map[string]config.NotifyChannel{"x": {Type: "pager", URL: "https://example.com"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "email without recipients",
+			notify: config.Notify{
+				Channels: map[string]config.NotifyChannel{"mail": {Type: "email", SMTPHost: "smtp.example.com", From: "a@example.com"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rule with unknown channel",
+			notify: config.Notify{
+				Channels: validChannels,
+				Rules:    []config.NotifyRule{{Name: "fail", Type: "source_failure", Channels: []string{"missing"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate rule name",
+			notify: config.Notify{
+				Channels: validChannels,
+				Rules: []config.NotifyRule{
+					{Name: "fail", Type: "source_failure", Channels: []string{"hook"}},
+					{Name: "fail", Type: "source_failure", Channels: []string{"hook"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "watchlist with unknown IoC type",
+			notify: config.Notify{
+				Channels: validChannels,
+				Rules:    []config.NotifyRule{{Name: "watch", Type: "watchlist", Channels: []string{"hook"}, Patterns: []string{"*"}, Types: []string{"fqdn"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "lookalike",
+			notify: config.Notify{
+				Channels: validChannels,
+				Rules:    []config.NotifyRule{{Name: "brand", Type: "lookalike", Channels: []string{"hook"}, Techniques: []string{"idn"}, MinScore: 0.7}},
+			},
+		},
+		{
+			name: "lookalike with unknown technique",
+			notify: config.Notify{
+				Channels: validChannels,
+				Rules:    []config.NotifyRule{{Name: "brand", Type: "lookalike", Channels: []string{"hook"}, Techniques: []string{"soundalike"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid backoff",
+			notify:  config.Notify{RawBackoff: "soon"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.notify.Validate()
+			if tc.wantErr {
+				gt.Error(t, err)
+			} else {
+				gt.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("rules and channels", func(t *testing.T) {
+		cfg := &config.Config{
+			Notify: config.Notify{
+				Channels: map[string]config.NotifyChannel{
+					"hook":  {Type: "webhook", URL: "https://example.com/hook"},
+					"slack": {Type: "slack", URL: "https://hooks.example.com/x"},
+				},
+				Rules: []config.NotifyRule{
+					{Name: "fail", Type: "source_failure", Channels: []string{"hook", "slack"}},
+				},
+			},
+		}
+		gt.NoError(t, cfg.Validate())
+
+		rules := cfg.NotificationRules()
+		gt.A(t, rules).Length(1).At(0, func(t testing.TB, r *model.NotificationRule) {
+			gt.Equal(t, r.Kind, model.NotificationRuleSourceFailure)
+			gt.Equal(t, r.Threshold, model.DefaultFailureThreshold)
+		})
+		gt.Equal(t, len(cfg.NotificationChannels()), 2)
+	})
+}
+
+func TestBrandValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		brand   config.Brand
+		wantErr bool
+	}{
+		{name: "empty", brand: config.Brand{}},
+		{name: "valid", brand: config.Brand{Domains: []string{"example.com", "example.co.uk"}, Techniques: []string{"typosquat", "idn"}, MinScore: 0.5}},
+		{name: "subdomain is not registrable", brand: config.Brand{Domains: []string{"www.example.com"}}, wantErr: true},
+		{name: "unknown technique", brand: config.Brand{Domains: []string{"example.com"}, Techniques: []string{"soundalike"}}, wantErr: true},
+		{name: "score out of range", brand: config.Brand{Domains: []string{"example.com"}, MinScore: 1.5}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.brand.Validate()
+			if tc.wantErr {
+				gt.Error(t, err)
+			} else {
+				gt.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("no detector without domains", func(t *testing.T) {
+		detector, err := (&config.Brand{}).NewDetector()
+		gt.NoError(t, err)
+		gt.V(t, detector).Nil()
+	})
+}
+
+func TestASNValidate(t *testing.T) {
+	table := filepath.Join(t.TempDir(), "ip2asn.tsv")
+	gt.NoError(t, os.WriteFile(table, []byte("198.51.100.0\t198.51.100.255\t64500\tUS\tEXAMPLE\n"), 0o600))
+
+	testCases := []struct {
+		name    string
+		asn     config.ASN
+		wantErr bool
+	}{
+		{name: "empty", asn: config.ASN{}},
+		{name: "valid", asn: config.ASN{Table: table, ResolveHosts: true}},
+		{name: "missing table", asn: config.ASN{Table: table + ".missing"}, wantErr: true},
+		{name: "resolve hosts without table", asn: config.ASN{ResolveHosts: true}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.asn.Validate()
+			if tc.wantErr {
+				gt.Error(t, err)
+			} else {
+				gt.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("resolver", func(t *testing.T) {
+		resolver, err := (&config.ASN{}).NewResolver()
+		gt.NoError(t, err)
+		gt.V(t, resolver).Nil()
+
+		resolver, err = (&config.ASN{Table: table}).NewResolver()
+		gt.NoError(t, err)
+		asns, err := resolver.LookupASNs(t.Context(), "198.51.100.1")
+		gt.NoError(t, err)
+		gt.A(t, asns).Equal([]uint32{64500})
+	})
+}
+
+func TestAuthValidate(t *testing.T) {
+	hash := auth.HashToken("s3cret")
+	testCases := []struct {
+		name    string
+		auth    config.Auth
+		wantErr bool
+	}{
+		{name: "disabled", auth: config.Auth{}},
+		{name: "tokens", auth: config.Auth{Tokens: []config.AuthToken{{Name: "ci", Hash: hash, Role: "analyst"}}}},
+		{name: "jwt", auth: config.Auth{AnonymousRole: "viewer", JWT: &config.JWTAuth{JWKSURL: "https://idp.example/jwks", Roles: map[string]string{"soc": "analyst"}}}},
+		{name: "header", auth: config.Auth{Header: &config.HeaderAuth{Name: "X-Goog-Authenticated-User-Email", DefaultRole: "viewer"}}},
+		{name: "plain token", auth: config.Auth{Tokens: []config.AuthToken{{Name: "ci", Hash: "s3cret", Role: "analyst"}}}, wantErr: true},
+		{name: "unknown token role", auth: config.Auth{Tokens: []config.AuthToken{{Name: "ci", Hash: hash, Role: "root"}}}, wantErr: true},
+		{name: "jwt without jwks", auth: config.Auth{JWT: &config.JWTAuth{}}, wantErr: true},
+		{name: "unknown mapped role", auth: config.Auth{JWT: &config.JWTAuth{JWKSURL: "https://idp.example/jwks", Roles: map[string]string{"soc": "owner"}}}, wantErr: true},
+		{name: "header without name", auth: config.Auth{Header: &config.HeaderAuth{}}, wantErr: true},
+		{name: "anonymous without authenticator", auth: config.Auth{AnonymousRole: "viewer"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.auth.Validate()
+			if tc.wantErr {
+				gt.Error(t, err)
+			} else {
+				gt.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("loaded from config file", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.toml")
+		gt.NoError(t, os.WriteFile(configFile, []byte(`
+[auth]
+anonymous_role = "viewer"
+
+[[auth.tokens]]
+name = "ci"
+hash = "`+hash+`"
+role = "analyst"
+`), 0o600))
+
+		cfg, err := config.LoadConfig(configFile)
+		gt.NoError(t, err)
+		gt.True(t, cfg.Auth.Enabled())
+		authn, err := cfg.Auth.New()
+		gt.NoError(t, err)
+		gt.V(t, authn).NotNil()
+	})
 }
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"context"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/domain/vectorizer"
+	"github.com/secmon-lab/beehive/pkg/service/embedding"
+	"github.com/urfave/cli/v3"
+)
+
+// Embedding providers
+const (
+	EmbeddingProviderNGram            = "ngram"
+	EmbeddingProviderLLM              = "llm"
+	EmbeddingProviderOpenAICompatible = "openai-compatible"
+)
+
+var (
+	errUnsupportedEmbeddingProvider = goerr.New("unsupported embedding provider")
+)
+
+// Embedding represents the configuration of IoC embeddings for semantic search
+type Embedding struct {
+	Provider      string
+	Model         string
+	Dimension     int
+	BaseURL       string
+	APIKey        string
+	DimensionsArg bool
+}
+
+// Flags returns CLI flags for the embedding configuration
+func (e *Embedding) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "embedding-provider",
+			Usage:       "Embedding provider (ngram, llm, openai-compatible). llm uses the embedding API of --llm-provider",
+			Value:       EmbeddingProviderNGram,
+			Destination: &e.Provider,
+			Sources:     cli.EnvVars("BEEHIVE_EMBEDDING_PROVIDER"),
+		},
+		&cli.StringFlag{
+			Name:        "embedding-model",
+			Usage:       "Embedding model name (provider default if not specified)",
+			Destination: &e.Model,
+			Sources:     cli.EnvVars("BEEHIVE_EMBEDDING_MODEL"),
+		},
+		&cli.IntFlag{
+			Name:        "embedding-dimension",
+			Usage:       "Embedding dimension; changing it requires migrate and reembed",
+			Value:       model.EmbeddingDimension,
+			Destination: &e.Dimension,
+			Sources:     cli.EnvVars("BEEHIVE_EMBEDDING_DIMENSION"),
+		},
+		&cli.StringFlag{
+			Name:        "embedding-base-url",
+			Usage:       "Base URL of the OpenAI-compatible embedding server (e.g. http://localhost:11434/v1)",
+			Destination: &e.BaseURL,
+			Sources:     cli.EnvVars("BEEHIVE_EMBEDDING_BASE_URL"),
+		},
+		&cli.StringFlag{
+			Name:        "embedding-api-key",
+			Usage:       "API key of the OpenAI-compatible embedding server",
+			Destination: &e.APIKey,
+			Sources:     cli.EnvVars("BEEHIVE_EMBEDDING_API_KEY"),
+		},
+		&cli.BoolFlag{
+			Name:        "embedding-dimensions-param",
+			Usage:       "Send the dimension to the OpenAI-compatible server, for models that can shorten embeddings",
+			Destination: &e.DimensionsArg,
+			Sources:     cli.EnvVars("BEEHIVE_EMBEDDING_DIMENSIONS_PARAM"),
+		},
+	}
+}
+
+// Validate checks the embedding configuration
+func (e *Embedding) Validate() error {
+	if e.Dimension <= 0 {
+		return goerr.New("embedding-dimension must be > 0", goerr.V("dimension", e.Dimension))
+	}
+	switch e.Provider {
+	case EmbeddingProviderNGram, EmbeddingProviderLLM:
+	case EmbeddingProviderOpenAICompatible:
+		if e.BaseURL == "" {
+			return goerr.New("embedding-base-url is required for the openai-compatible provider")
+		}
+		if e.Model == "" {
+			return goerr.New("embedding-model is required for the openai-compatible provider")
+		}
+	default:
+		return goerr.Wrap(errUnsupportedEmbeddingProvider, "invalid embedding provider",
+			goerr.V("provider", e.Provider))
+	}
+	return nil
+}
+
+// New creates the configured embedder. The llm provider creates a client of llm.
+func (e *Embedding) New(ctx context.Context, llm *LLM) (interfaces.Embedder, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch e.Provider {
+	case EmbeddingProviderLLM:
+		client, err := llm.NewEmbeddingClient(ctx, e.Model)
+		if err != nil {
+			return nil, err
+		}
+		modelID := llm.EffectiveProvider() + "/default"
+		if e.Model != "" {
+			modelID = llm.EffectiveProvider() + "/" + e.Model
+		}
+		return embedding.NewLLM(client, modelID, e.Dimension), nil
+
+	case EmbeddingProviderOpenAICompatible:
+		opts := []embedding.OpenAICompatibleOption{}
+		if e.APIKey != "" {
+			opts = append(opts, embedding.WithAPIKey(e.APIKey))
+		}
+		if e.DimensionsArg {
+			opts = append(opts, embedding.WithDimensionsParameter())
+		}
+		return embedding.NewOpenAICompatible(e.BaseURL, e.Model, e.Dimension, opts...), nil
+
+	default:
+		return vectorizer.NewNGramVectorizer(vectorizer.WithDimension(e.Dimension)), nil
+	}
+}
@@ -0,0 +1,10 @@
+package config
+
+import "github.com/m-mizutani/gollem"
+
+// Export internal functions for testing
+
+// NewLazyClient is exported for testing
+func NewLazyClient(create func() (gollem.LLMClient, error)) gollem.LLMClient {
+	return &lazyClient{create: create}
+}
@@ -2,17 +2,33 @@ package config
 
 import (
 	"context"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/m-mizutani/goerr/v2"
 	"github.com/m-mizutani/gollem"
 	"github.com/m-mizutani/gollem/llm/claude"
 	"github.com/m-mizutani/gollem/llm/gemini"
 	"github.com/m-mizutani/gollem/llm/openai"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/service/openaicompat"
 	"github.com/urfave/cli/v3"
 )
 
+// LLM providers
+const (
+	LLMProviderGemini           = "gemini"
+	LLMProviderOpenAI           = "openai"
+	LLMProviderClaude           = "claude"
+	LLMProviderOpenAICompatible = "openai-compatible"
+	LLMProviderNone             = "none"
+)
+
 var (
 	errUnsupportedProvider = goerr.New("unsupported LLM provider")
+	errNoProvider          = goerr.New("no LLM provider configured")
 )
 
 // LLM represents LLM configuration
@@ -23,6 +39,21 @@ type LLM struct {
 	OpenAIAPIKey   string
 	ClaudeAPIKey   string
 	Model          string
+
+	// Self-hosted OpenAI-compatible server (Ollama, vLLM, llama.cpp server)
+	BaseURL    string
+	APIKey     string
+	Timeout    time.Duration
+	Headers    []string
+	JSONSchema bool
+
+	// How RSS sources requiring an LLM are fetched without LLM (skip, regex)
+	RawFallback string
+
+	// Extraction of long articles
+	ChunkTokens  int
+	ChunkOverlap int
+	Parallelism  int
 }
 
 // Flags returns CLI flags for LLM configuration
@@ -30,8 +61,7 @@ func (l *LLM) Flags() []cli.Flag {
 	return []cli.Flag{
 		&cli.StringFlag{
 			Name:        "llm-provider",
-			Usage:       "LLM provider (gemini, openai, claude)",
-			Value:       "gemini",
+			Usage:       "LLM provider (gemini, openai, claude, openai-compatible, none). Defaults to gemini if gemini-project is set, none otherwise. An LLM is only required by RSS sources",
 			Destination: &l.Provider,
 			Sources:     cli.EnvVars("BEEHIVE_LLM_PROVIDER"),
 		},
@@ -66,19 +96,241 @@ func (l *LLM) Flags() []cli.Flag {
 			Destination: &l.Model,
 			Sources:     cli.EnvVars("BEEHIVE_LLM_MODEL"),
 		},
+		&cli.StringFlag{
+			Name:        "llm-base-url",
+			Usage:       "Base URL of the OpenAI-compatible server (e.g. http://localhost:11434/v1)",
+			Destination: &l.BaseURL,
+			Sources:     cli.EnvVars("BEEHIVE_LLM_BASE_URL"),
+		},
+		&cli.StringFlag{
+			Name:        "llm-api-key",
+			Usage:       "API key of the OpenAI-compatible server",
+			Destination: &l.APIKey,
+			Sources:     cli.EnvVars("BEEHIVE_LLM_API_KEY"),
+		},
+		&cli.DurationFlag{
+			Name:        "llm-timeout",
+			Usage:       "Timeout of a request to the OpenAI-compatible server",
+			Value:       openaicompat.DefaultTimeout,
+			Destination: &l.Timeout,
+			Sources:     cli.EnvVars("BEEHIVE_LLM_TIMEOUT"),
+		},
+		&cli.StringSliceFlag{
+			Name:        "llm-header",
+			Usage:       "Additional header sent to the OpenAI-compatible server, as 'Name: value' (repeatable)",
+			Destination: &l.Headers,
+			Sources:     cli.EnvVars("BEEHIVE_LLM_HEADER"),
+		},
+		&cli.BoolFlag{
+			Name:        "llm-json-schema",
+			Usage:       "Send the response JSON schema to the OpenAI-compatible server; disable for servers without structured output support to enforce JSON by prompt",
+			Value:       true,
+			Destination: &l.JSONSchema,
+			Sources:     cli.EnvVars("BEEHIVE_LLM_JSON_SCHEMA"),
+		},
+		&cli.StringFlag{
+			Name:        "llm-fallback",
+			Usage:       "How RSS sources requiring an LLM are fetched when no LLM is configured (skip, regex)",
+			Value:       string(model.LLMFallbackSkip),
+			Destination: &l.RawFallback,
+			Sources:     cli.EnvVars("BEEHIVE_LLM_FALLBACK"),
+		},
+		&cli.IntFlag{
+			Name:        "llm-chunk-tokens",
+			Usage:       "Maximum size of an article chunk sent to the LLM, in estimated tokens",
+			Value:       extractor.DefaultChunkTokens,
+			Destination: &l.ChunkTokens,
+			Sources:     cli.EnvVars("BEEHIVE_LLM_CHUNK_TOKENS"),
+		},
+		&cli.IntFlag{
+			Name:        "llm-chunk-overlap",
+			Usage:       "Number of estimated tokens repeated between consecutive chunks",
+			Value:       extractor.DefaultChunkOverlap,
+			Destination: &l.ChunkOverlap,
+			Sources:     cli.EnvVars("BEEHIVE_LLM_CHUNK_OVERLAP"),
+		},
+		&cli.IntFlag{
+			Name:        "llm-parallel",
+			Usage:       "Number of chunks of an article extracted concurrently (1 = sequential)",
+			Value:       extractor.DefaultParallelism,
+			Destination: &l.Parallelism,
+			Sources:     cli.EnvVars("BEEHIVE_LLM_PARALLEL"),
+		},
+	}
+}
+
+// ExtractorOptions returns the extractor options for chunked extraction
+func (l *LLM) ExtractorOptions() []extractor.Option {
+	return []extractor.Option{
+		extractor.WithChunking(l.ChunkTokens, l.ChunkOverlap),
+		extractor.WithParallelism(l.Parallelism),
+	}
+}
+
+// Validate checks the LLM settings that are not checked when the client is
+// created, so that they are reported at startup. A provider set explicitly
+// requires its credentials; only an unset provider runs without LLM.
+func (l *LLM) Validate() error {
+	switch l.Provider {
+	case "", LLMProviderNone:
+	case LLMProviderGemini:
+		if l.GeminiProject == "" {
+			return goerr.New("gemini-project is required for the gemini LLM provider")
+		}
+	case LLMProviderOpenAI:
+		if l.OpenAIAPIKey == "" {
+			return goerr.New("openai-api-key is required for the openai LLM provider")
+		}
+	case LLMProviderClaude:
+		if l.ClaudeAPIKey == "" {
+			return goerr.New("claude-api-key is required for the claude LLM provider")
+		}
+	case LLMProviderOpenAICompatible:
+		if l.BaseURL == "" {
+			return goerr.New("llm-base-url is required for the openai-compatible LLM provider")
+		}
+	default:
+		return goerr.Wrap(errUnsupportedProvider, "invalid llm-provider", goerr.V("provider", l.Provider))
+	}
+	if _, err := l.Fallback(); err != nil {
+		return err
+	}
+	if _, err := l.headers(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Fallback returns how RSS sources requiring an LLM are fetched when no LLM is configured
+func (l *LLM) Fallback() (model.LLMFallback, error) {
+	if l.RawFallback == "" {
+		return model.LLMFallbackSkip, nil
+	}
+	fallback := model.LLMFallback(l.RawFallback)
+	if !fallback.IsValid() {
+		return "", goerr.New("invalid llm-fallback, expected skip or regex", goerr.V("fallback", l.RawFallback))
+	}
+	return fallback, nil
+}
+
+// EffectiveProvider returns the provider of the LLM client: the provider set
+// explicitly, or gemini if only gemini-project is set
+func (l *LLM) EffectiveProvider() string {
+	if l.Provider == "" && l.GeminiProject != "" {
+		return LLMProviderGemini
+	}
+	return l.Provider
+}
+
+// Configured returns true if the settings of the provider are given. Settings
+// of a provider set explicitly are required by Validate.
+func (l *LLM) Configured() bool {
+	switch l.EffectiveProvider() {
+	case "", LLMProviderNone:
+		return false
+	case LLMProviderGemini:
+		return l.GeminiProject != ""
+	case LLMProviderOpenAI:
+		return l.OpenAIAPIKey != ""
+	case LLMProviderClaude:
+		return l.ClaudeAPIKey != ""
+	case LLMProviderOpenAICompatible:
+		return l.BaseURL != ""
+	default:
+		// Rejected by Validate
+		return false
 	}
 }
 
+// ModelID identifies the provider and model in LLM cache keys
+func (l *LLM) ModelID() string {
+	if l.Model == "" {
+		return l.EffectiveProvider() + "/default"
+	}
+	return l.EffectiveProvider() + "/" + l.Model
+}
+
 // NewLLMClient creates a new LLM client based on the configuration
 func (l *LLM) NewLLMClient(ctx context.Context) (gollem.LLMClient, error) {
-	switch l.Provider {
-	case "gemini":
+	return l.newClient(ctx, "")
+}
+
+// NewLazyLLMClient returns a client that is created on first use, or nil if
+// no LLM is configured. Creation is retried by the next call after an error.
+func (l *LLM) NewLazyLLMClient(ctx context.Context) gollem.LLMClient {
+	if !l.Configured() {
+		return nil
+	}
+	return &lazyClient{create: func() (gollem.LLMClient, error) {
+		return l.NewLLMClient(ctx)
+	}}
+}
+
+// lazyClient creates the wrapped client on first successful use
+type lazyClient struct {
+	create func() (gollem.LLMClient, error)
+	mu     sync.Mutex
+	client gollem.LLMClient
+}
+
+func (c *lazyClient) get() (gollem.LLMClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client != nil {
+		return c.client, nil
+	}
+	client, err := c.create()
+	if err != nil {
+		return nil, err
+	}
+	c.client = client
+	return client, nil
+}
+
+func (c *lazyClient) NewSession(ctx context.Context, options ...gollem.SessionOption) (gollem.Session, error) {
+	client, err := c.get()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create LLM client")
+	}
+	return client.NewSession(ctx, options...)
+}
+
+func (c *lazyClient) GenerateEmbedding(ctx context.Context, dimension int, input []string) ([][]float64, error) {
+	client, err := c.get()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create LLM client")
+	}
+	return client.GenerateEmbedding(ctx, dimension, input)
+}
+
+// NewEmbeddingClient creates a client of the provider generating embeddings
+// with embeddingModel (provider default if empty)
+func (l *LLM) NewEmbeddingClient(ctx context.Context, embeddingModel string) (gollem.LLMClient, error) {
+	provider := l.EffectiveProvider()
+	if provider == LLMProviderClaude {
+		return nil, goerr.Wrap(errUnsupportedProvider, "claude does not provide embeddings, use another embedding provider")
+	}
+	if provider == LLMProviderOpenAICompatible && embeddingModel == "" {
+		return nil, goerr.New("embedding-model is required for embeddings of the openai-compatible LLM provider")
+	}
+	return l.newClient(ctx, embeddingModel)
+}
+
+func (l *LLM) newClient(ctx context.Context, embeddingModel string) (gollem.LLMClient, error) {
+	switch l.EffectiveProvider() {
+	case "", LLMProviderNone:
+		return nil, goerr.Wrap(errNoProvider, "set llm-provider")
+
+	case LLMProviderGemini:
 		opts := []gemini.Option{
 			gemini.WithThinkingBudget(0),
 		}
 		if l.Model != "" {
 			opts = append(opts, gemini.WithModel(l.Model))
 		}
+		if embeddingModel != "" {
+			opts = append(opts, gemini.WithEmbeddingModel(embeddingModel))
+		}
 		client, err := gemini.New(ctx, l.GeminiProject, l.GeminiLocation, opts...)
 		if err != nil {
 			return nil, goerr.Wrap(err, "failed to create Gemini client",
@@ -87,18 +339,21 @@ func (l *LLM) NewLLMClient(ctx context.Context) (gollem.LLMClient, error) {
 		}
 		return client, nil
 
-	case "openai":
+	case LLMProviderOpenAI:
 		opts := []openai.Option{}
 		if l.Model != "" {
 			opts = append(opts, openai.WithModel(l.Model))
 		}
+		if embeddingModel != "" {
+			opts = append(opts, openai.WithEmbeddingModel(embeddingModel))
+		}
 		client, err := openai.New(ctx, l.OpenAIAPIKey, opts...)
 		if err != nil {
 			return nil, goerr.Wrap(err, "failed to create OpenAI client")
 		}
 		return client, nil
 
-	case "claude":
+	case LLMProviderClaude:
 		opts := []claude.Option{}
 		if l.Model != "" {
 			opts = append(opts, claude.WithModel(l.Model))
@@ -109,8 +364,48 @@ func (l *LLM) NewLLMClient(ctx context.Context) (gollem.LLMClient, error) {
 		}
 		return client, nil
 
+	case LLMProviderOpenAICompatible:
+		headers, err := l.headers()
+		if err != nil {
+			return nil, err
+		}
+		opts := []openaicompat.Option{
+			openaicompat.WithHeaders(headers),
+			openaicompat.WithJSONSchema(l.JSONSchema),
+		}
+		if l.APIKey != "" {
+			opts = append(opts, openaicompat.WithAPIKey(l.APIKey))
+		}
+		if l.Timeout > 0 {
+			opts = append(opts, openaicompat.WithTimeout(l.Timeout))
+		}
+		if embeddingModel != "" {
+			opts = append(opts, openaicompat.WithEmbeddingModel(embeddingModel))
+		}
+		client, err := openaicompat.New(l.BaseURL, l.Model, opts...)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to create OpenAI-compatible client",
+				goerr.V("base_url", l.BaseURL),
+				goerr.V("model", l.Model))
+		}
+		return client, nil
+
 	default:
 		return nil, goerr.Wrap(errUnsupportedProvider, "invalid provider",
 			goerr.V("provider", l.Provider))
 	}
 }
+
+// headers parses the --llm-header values
+func (l *LLM) headers() (map[string]string, error) {
+	headers := make(map[string]string, len(l.Headers))
+	for _, h := range l.Headers {
+		name, value, ok := strings.Cut(h, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, goerr.New("invalid llm-header, expected 'Name: value'", goerr.V("header", h))
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
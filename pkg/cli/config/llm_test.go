@@ -0,0 +1,233 @@
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m-mizutani/gollem"
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/cli/config"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/service/openaicompat"
+)
+
+func TestLLM_OpenAICompatible(t *testing.T) {
+	ctx := context.Background()
+
+	var received struct {
+		Model          string         `json:"model"`
+		ResponseFormat map[string]any `json:"response_format"`
+	}
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gt.Equal(t, r.URL.Path, "/v1/chat/completions")
+		header = r.Header.Clone()
+		gt.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		gt.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"role": "assistant", "content": `{"ok": true}`}}},
+		}))
+	}))
+	defer server.Close()
+
+	newConfig := func() *config.LLM {
+		return &config.LLM{
+			Provider:   config.LLMProviderOpenAICompatible,
+			BaseURL:    server.URL + "/v1",
+			Model:      "qwen2.5:14b",
+			Headers:    []string{"X-Tenant: soc", "X-Empty:"},
+			JSONSchema: true,
+		}
+	}
+
+	generate := func(t *testing.T, cfg *config.LLM) {
+		client, err := cfg.NewLLMClient(ctx)
+		gt.NoError(t, err)
+		session, err := client.NewSession(ctx,
+			gollem.WithSessionContentType(gollem.ContentTypeJSON),
+			gollem.WithSessionResponseSchema(&gollem.Parameter{
+				Type:       gollem.TypeObject,
+				Properties: map[string]*gollem.Parameter{"ok": {Type: gollem.TypeBoolean}},
+			}),
+		)
+		gt.NoError(t, err)
+		resp, err := session.GenerateContent(ctx, gollem.Text("ping"))
+		gt.NoError(t, err)
+		gt.Equal(t, resp.Texts, []string{`{"ok": true}`})
+	}
+
+	t.Run("json schema", func(t *testing.T) {
+		cfg := newConfig()
+		gt.Equal(t, cfg.ModelID(), "openai-compatible/qwen2.5:14b")
+		generate(t, cfg)
+		gt.Equal(t, received.Model, "qwen2.5:14b")
+		gt.Equal(t, received.ResponseFormat["type"], any("json_schema"))
+		gt.Equal(t, header.Get("X-Tenant"), "soc")
+	})
+
+	t.Run("prompt-enforced JSON", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.JSONSchema = false
+		received.ResponseFormat = nil
+		generate(t, cfg)
+		gt.Nil(t, received.ResponseFormat)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Headers = []string{"no separator"}
+		_, err := cfg.NewLLMClient(ctx)
+		gt.Error(t, err)
+	})
+
+	t.Run("base URL and model are required", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.BaseURL = ""
+		_, err := cfg.NewLLMClient(ctx)
+		gt.Error(t, err)
+
+		cfg = newConfig()
+		cfg.Model = ""
+		_, err = cfg.NewLLMClient(ctx)
+		gt.Error(t, err)
+	})
+
+	t.Run("embedding model is required for embeddings", func(t *testing.T) {
+		_, err := newConfig().NewEmbeddingClient(ctx, "")
+		gt.Error(t, err)
+		_, err = newConfig().NewEmbeddingClient(ctx, "nomic-embed-text")
+		gt.NoError(t, err)
+	})
+}
+
+func TestLLM_Configured(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.LLM
+		want bool
+	}{
+		{name: "no settings", cfg: config.LLM{}, want: false},
+		{name: "none", cfg: config.LLM{Provider: config.LLMProviderNone, GeminiProject: "p"}, want: false},
+		{name: "gemini without project", cfg: config.LLM{Provider: config.LLMProviderGemini}, want: false},
+		{name: "gemini", cfg: config.LLM{Provider: config.LLMProviderGemini, GeminiProject: "p"}, want: true},
+		{name: "gemini by project", cfg: config.LLM{GeminiProject: "p"}, want: true},
+		{name: "openai without key", cfg: config.LLM{Provider: config.LLMProviderOpenAI}, want: false},
+		{name: "openai", cfg: config.LLM{Provider: config.LLMProviderOpenAI, OpenAIAPIKey: "k"}, want: true},
+		{name: "claude", cfg: config.LLM{Provider: config.LLMProviderClaude, ClaudeAPIKey: "k"}, want: true},
+		{name: "openai-compatible", cfg: config.LLM{Provider: config.LLMProviderOpenAICompatible, BaseURL: "http://localhost:11434/v1"}, want: true},
+		{name: "unknown provider", cfg: config.LLM{Provider: "mystery"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gt.Equal(t, tt.cfg.Configured(), tt.want)
+		})
+	}
+}
+
+func TestLLM_NewLazyLLMClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not configured", func(t *testing.T) {
+		cfg := config.LLM{}
+		gt.Nil(t, cfg.NewLazyLLMClient(ctx))
+	})
+
+	t.Run("unknown provider is not configured", func(t *testing.T) {
+		cfg := config.LLM{Provider: "mystery"}
+		gt.Nil(t, cfg.NewLazyLLMClient(ctx))
+	})
+
+	t.Run("error is returned on use", func(t *testing.T) {
+		cfg := config.LLM{Provider: config.LLMProviderOpenAICompatible, BaseURL: "http://localhost:11434/v1", Headers: []string{"missing separator"}}
+		client := cfg.NewLazyLLMClient(ctx)
+		gt.NotNil(t, client)
+
+		_, err := client.NewSession(ctx)
+		gt.Error(t, err)
+		_, err = client.GenerateEmbedding(ctx, 3, []string{"x"})
+		gt.Error(t, err)
+	})
+
+	t.Run("creation is retried after an error", func(t *testing.T) {
+		created, err := openaicompat.New("http://localhost:11434/v1", "m")
+		gt.NoError(t, err)
+
+		calls := 0
+		client := config.NewLazyClient(func() (gollem.LLMClient, error) {
+			calls++
+			if calls == 1 {
+				return nil, errors.New("temporarily unavailable")
+			}
+			return created, nil
+		})
+
+		_, err = client.NewSession(ctx)
+		gt.Error(t, err)
+		_, err = client.NewSession(ctx)
+		gt.NoError(t, err)
+		_, err = client.NewSession(ctx)
+		gt.NoError(t, err)
+		gt.Equal(t, calls, 2)
+	})
+}
+
+func TestLLM_Validate(t *testing.T) {
+	cfg := config.LLM{}
+	gt.NoError(t, cfg.Validate())
+	fallback, err := cfg.Fallback()
+	gt.NoError(t, err)
+	gt.Equal(t, fallback, model.LLMFallbackSkip)
+
+	cfg.RawFallback = "regex"
+	gt.NoError(t, cfg.Validate())
+	fallback, err = cfg.Fallback()
+	gt.NoError(t, err)
+	gt.Equal(t, fallback, model.LLMFallbackRegex)
+
+	cfg.RawFallback = "guess"
+	gt.Error(t, cfg.Validate())
+
+	cfg = config.LLM{Headers: []string{"missing separator"}}
+	gt.Error(t, cfg.Validate())
+
+	cfg = config.LLM{Provider: "mystery"}
+	gt.Error(t, cfg.Validate())
+
+	t.Run("provider set explicitly requires credentials", func(t *testing.T) {
+		for _, cfg := range []config.LLM{
+			{Provider: config.LLMProviderGemini},
+			{Provider: config.LLMProviderOpenAI},
+			{Provider: config.LLMProviderClaude},
+			{Provider: config.LLMProviderOpenAICompatible},
+		} {
+			gt.Error(t, cfg.Validate())
+		}
+
+		for _, cfg := range []config.LLM{
+			{Provider: config.LLMProviderNone},
+			{Provider: config.LLMProviderGemini, GeminiProject: "p"},
+			{Provider: config.LLMProviderOpenAI, OpenAIAPIKey: "k"},
+			{Provider: config.LLMProviderClaude, ClaudeAPIKey: "k"},
+			{Provider: config.LLMProviderOpenAICompatible, BaseURL: "http://localhost:11434/v1"},
+		} {
+			gt.NoError(t, cfg.Validate())
+		}
+	})
+
+	t.Run("unset provider defaults to gemini with a project", func(t *testing.T) {
+		cfg := config.LLM{GeminiProject: "p"}
+		gt.NoError(t, cfg.Validate())
+		gt.Equal(t, cfg.EffectiveProvider(), config.LLMProviderGemini)
+		gt.Equal(t, cfg.ModelID(), "gemini/default")
+
+		cfg = config.LLM{}
+		gt.NoError(t, cfg.Validate())
+		gt.False(t, cfg.Configured())
+		_, err := cfg.NewLLMClient(context.Background())
+		gt.Error(t, err)
+	})
+}
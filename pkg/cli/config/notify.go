@@ -0,0 +1,215 @@
+package config
+
+import (
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/service/notify"
+)
+
+// Notification channel types
+const (
+	NotifyChannelWebhook = "webhook"
+	NotifyChannelSlack   = "slack"
+	NotifyChannelEmail   = "email"
+)
+
+// Notify represents notification configuration
+type Notify struct {
+	Channels map[string]NotifyChannel `toml:"channels,omitempty"`
+	Rules    []NotifyRule             `toml:"rules,omitempty"`
+
+	RetryAttempts int            `toml:"retry_attempts,omitempty"` // Delivery attempts per channel (default 3)
+	RetryBackoff  *time.Duration `toml:"-"`                        // Not directly unmarshaled
+	RawBackoff    string         `toml:"retry_backoff,omitempty"`  // Initial backoff, doubled after each failure
+}
+
+// NotifyChannel represents a notification destination.
+// url, headers and password are expanded with environment variables ($VAR or ${VAR}).
+type NotifyChannel struct {
+	Type    string            `toml:"type"`
+	URL     string            `toml:"url,omitempty"`     // webhook, slack
+	Headers map[string]string `toml:"headers,omitempty"` // webhook
+
+	SMTPHost string   `toml:"smtp_host,omitempty"` // email
+	SMTPPort int      `toml:"smtp_port,omitempty"`
+	Username string   `toml:"username,omitempty"`
+	Password string   `toml:"password,omitempty"`
+	From     string   `toml:"from,omitempty"`
+	To       []string `toml:"to,omitempty"`
+}
+
+// NotifyRule represents a notification rule
+type NotifyRule struct {
+	Name     string   `toml:"name"`
+	Type     string   `toml:"type"` // source_failure, watchlist, lookalike, digest
+	Channels []string `toml:"channels"`
+
+	Threshold int      `toml:"threshold,omitempty"` // source_failure
+	Sources   []string `toml:"sources,omitempty"`   // source_failure
+
+	Patterns      []string `toml:"patterns,omitempty"`       // watchlist
+	Watchlists    []string `toml:"watchlists,omitempty"`     // watchlist, registered watchlist names
+	Types         []string `toml:"types,omitempty"`          // watchlist
+	MinConfidence int      `toml:"min_confidence,omitempty"` // watchlist
+
+	Brands     []string `toml:"brands,omitempty"`     // lookalike, protected domains
+	Techniques []string `toml:"techniques,omitempty"` // lookalike
+	MinScore   float64  `toml:"min_score,omitempty"`  // lookalike
+
+	Tags []string `toml:"tags,omitempty"` // digest
+	Hour int      `toml:"hour,omitempty"` // digest, UTC
+
+	Subject string `toml:"subject,omitempty"` // text/template override
+	Body    string `toml:"body,omitempty"`    // text/template override
+}
+
+// Validate validates notification configuration
+func (n *Notify) Validate() error {
+	for name, ch := range n.Channels {
+		if err := ch.Validate(); err != nil {
+			return goerr.Wrap(err, "invalid notification channel", goerr.V("channel", name))
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, r := range n.Rules {
+		if names[r.Name] {
+			return goerr.New("duplicate notification rule name", goerr.V("rule", r.Name))
+		}
+		names[r.Name] = true
+
+		for _, ch := range r.Channels {
+			if _, ok := n.Channels[ch]; !ok {
+				return goerr.New("unknown notification channel in rule",
+					goerr.V("rule", r.Name), goerr.V("channel", ch))
+			}
+		}
+		if err := r.toModel().Validate(); err != nil {
+			return goerr.Wrap(err, "invalid notification rule", goerr.V("rule", r.Name))
+		}
+	}
+
+	if n.RetryAttempts < 0 {
+		return goerr.New("retry_attempts must be >= 0", goerr.V("retry_attempts", n.RetryAttempts))
+	}
+	if n.RawBackoff != "" {
+		d, err := time.ParseDuration(n.RawBackoff)
+		if err != nil {
+			return goerr.Wrap(err, "invalid retry_backoff", goerr.V("retry_backoff", n.RawBackoff))
+		}
+		if d < 0 {
+			return goerr.New("retry_backoff must be >= 0", goerr.V("retry_backoff", n.RawBackoff))
+		}
+		n.RetryBackoff = &d
+	}
+
+	return nil
+}
+
+// Validate validates a notification channel
+func (c *NotifyChannel) Validate() error {
+	switch c.Type {
+	case NotifyChannelWebhook, NotifyChannelSlack:
+		if c.URL == "" {
+			return goerr.New("url is required", goerr.V("type", c.Type))
+		}
+		if _, err := url.Parse(c.URL); err != nil {
+			return goerr.Wrap(err, "invalid url")
+		}
+	case NotifyChannelEmail:
+		if c.SMTPHost == "" {
+			return goerr.New("smtp_host is required")
+		}
+		if c.SMTPPort < 0 || c.SMTPPort > 65535 {
+			return goerr.New("invalid smtp_port", goerr.V("smtp_port", c.SMTPPort))
+		}
+		if c.From == "" {
+			return goerr.New("from is required")
+		}
+		if len(c.To) == 0 {
+			return goerr.New("to is required")
+		}
+	default:
+		return goerr.New("unknown notification channel type", goerr.V("type", c.Type))
+	}
+	return nil
+}
+
+// NewChannel creates the notification channel
+func (c *NotifyChannel) NewChannel() interfaces.NotificationChannel {
+	switch c.Type {
+	case NotifyChannelSlack:
+		return notify.NewSlack(os.ExpandEnv(c.URL))
+	case NotifyChannelEmail:
+		return notify.NewEmail(notify.SMTPConfig{
+			Host:     c.SMTPHost,
+			Port:     c.SMTPPort,
+			Username: c.Username,
+			Password: os.ExpandEnv(c.Password),
+			From:     c.From,
+			To:       c.To,
+		})
+	default:
+		headers := make(map[string]string, len(c.Headers))
+		for k, v := range c.Headers {
+			headers[k] = os.ExpandEnv(v)
+		}
+		return notify.NewWebhook(os.ExpandEnv(c.URL), headers)
+	}
+}
+
+func (r *NotifyRule) toModel() *model.NotificationRule {
+	types := make([]model.IoCType, len(r.Types))
+	for i, t := range r.Types {
+		types[i] = model.IoCType(t)
+	}
+	techniques := make([]model.LookalikeTechnique, len(r.Techniques))
+	for i, t := range r.Techniques {
+		techniques[i] = model.LookalikeTechnique(t)
+	}
+
+	return &model.NotificationRule{
+		Name:          r.Name,
+		Kind:          model.NotificationRuleKind(r.Type),
+		Channels:      r.Channels,
+		Threshold:     r.Threshold,
+		Sources:       r.Sources,
+		Patterns:      r.Patterns,
+		Watchlists:    r.Watchlists,
+		Types:         types,
+		MinConfidence: r.MinConfidence,
+		Brands:        r.Brands,
+		Techniques:    techniques,
+		MinScore:      r.MinScore,
+		Tags:          r.Tags,
+		Hour:          r.Hour,
+		Subject:       r.Subject,
+		Body:          r.Body,
+	}
+}
+
+// NotificationRules returns the validated notification rules
+func (c *Config) NotificationRules() []*model.NotificationRule {
+	rules := make([]*model.NotificationRule, 0, len(c.Notify.Rules))
+	for _, r := range c.Notify.Rules {
+		rule := r.toModel()
+		// Validated at load time; Validate also fills in defaults
+		_ = rule.Validate()
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// NotificationChannels creates the configured notification channels
+func (c *Config) NotificationChannels() map[string]interfaces.NotificationChannel {
+	channels := make(map[string]interfaces.NotificationChannel, len(c.Notify.Channels))
+	for name, ch := range c.Notify.Channels {
+		channels[name] = ch.NewChannel()
+	}
+	return channels
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"time"
+
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// Source converts the RSS source to a source definition.
+// This method assumes the source has been validated.
+func (r *RSSSource) Source(id string) model.Source {
+	return model.Source{
+		ID:          id,
+		Type:        model.SourceTypeRSS,
+		URL:         r.URL,
+		Description: r.Description,
+		Tags:        r.Tags.Strings(),
+		Enabled:     !r.Disabled,
+		TTL:         r.TTL,
+		RSSConfig: &model.RSSConfig{
+			MaxArticles: r.MaxArticles,
+			Extraction:  r.Extraction,
+			Prompt:      r.ExtractionPrompt,
+		},
+		Reliability: r.Reliability,
+	}
+}
+
+// Source converts the feed source to a source definition.
+// This method assumes the source has been validated.
+func (f *FeedSource) Source(id string) model.Source {
+	return model.Source{
+		ID:          id,
+		Type:        model.SourceTypeFeed,
+		URL:         f.GetURL(),
+		Description: f.Description,
+		Tags:        f.Tags.Strings(),
+		Enabled:     !f.Disabled,
+		TTL:         f.TTL,
+		FeedConfig: &model.FeedConfig{
+			Schema:   f.Schema.String(),
+			MaxItems: f.MaxItems,
+		},
+		Reliability: f.Reliability,
+	}
+}
+
+// Sources returns the definitions of all configured sources, including
+// disabled ones, keyed by source ID.
+// This method assumes the config has been validated.
+func (c *Config) Sources() map[string]model.Source {
+	sources := make(map[string]model.Source, len(c.RSS)+len(c.Feed))
+	for id, src := range c.RSS {
+		sources[id] = src.Source(id)
+	}
+	for id, src := range c.Feed {
+		sources[id] = src.Source(id)
+	}
+	return sources
+}
+
+// NewRSSSource converts an RSS source definition back to its configuration so
+// that changes to it can be validated by RSSSource.Validate
+func NewRSSSource(src *model.Source) RSSSource {
+	r := RSSSource{
+		URL:         src.URL,
+		Description: src.Description,
+		RawTags:     append([]string(nil), src.Tags...),
+		Disabled:    !src.Enabled,
+		RawTTL:      rawTTL(src.TTL),
+		Reliability: src.Reliability,
+	}
+	if src.RSSConfig != nil {
+		r.MaxArticles = src.RSSConfig.MaxArticles
+		r.Extraction = src.RSSConfig.Extraction
+		if p := src.RSSConfig.Prompt; p != nil {
+			r.Prompt = p.Template
+			r.Instructions = p.Instructions
+			r.Examples = p.Examples
+			r.Language = p.Language
+			for _, t := range p.Types {
+				r.RawTypes = append(r.RawTypes, string(t))
+			}
+		}
+	}
+	return r
+}
+
+// NewFeedSource converts a feed source definition back to its configuration so
+// that changes to it can be validated by FeedSource.Validate
+func NewFeedSource(src *model.Source) FeedSource {
+	f := FeedSource{
+		URL:         src.URL,
+		Description: src.Description,
+		RawTags:     append([]string(nil), src.Tags...),
+		Disabled:    !src.Enabled,
+		RawTTL:      rawTTL(src.TTL),
+		Reliability: src.Reliability,
+	}
+	if src.FeedConfig != nil {
+		f.RawSchema = src.FeedConfig.Schema
+		f.MaxItems = src.FeedConfig.MaxItems
+	}
+	return f
+}
+
+// rawTTL formats a TTL so that ParseTTL returns it unchanged
+func rawTTL(ttl *time.Duration) string {
+	if ttl == nil {
+		return ""
+	}
+	return ttl.String()
+}
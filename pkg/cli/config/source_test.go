@@ -0,0 +1,84 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/cli/config"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func TestConfigSources(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.toml")
+	gt.NoError(t, os.WriteFile(configFile, []byte(`
+[rss.vendor-blog]
+url = "https://blog.example/feed"
+description = "Vendor research"
+tags = ["vendor"]
+ttl = "14d"
+reliability = 0.7
+extraction = "crosscheck"
+instructions = "Ignore sample domains"
+types = ["domain"]
+
+[feed.urlhaus]
+schema = "abuse_ch_urlhaus"
+url = "https://urlhaus.example/csv"
+disabled = true
+max_items = 100
+`), 0o600))
+
+	cfg, err := config.LoadConfig(configFile)
+	gt.NoError(t, err)
+
+	sources := cfg.Sources()
+	gt.Equal(t, len(sources), 2)
+
+	rss := sources["vendor-blog"]
+	gt.Equal(t, rss.ID, "vendor-blog")
+	gt.Equal(t, rss.Type, model.SourceTypeRSS)
+	gt.Equal(t, rss.Description, "Vendor research")
+	gt.Equal(t, *rss.TTL, 14*model.Day)
+	gt.Equal(t, *rss.Reliability, 0.7)
+	gt.Equal(t, rss.RSSConfig.Extraction, model.ExtractionModeCrossCheck)
+	gt.Equal(t, rss.RSSConfig.Prompt.Instructions, "Ignore sample domains")
+
+	feed := sources["urlhaus"]
+	gt.False(t, feed.Enabled)
+	gt.Equal(t, feed.FeedConfig.Schema, "abuse_ch_urlhaus")
+	gt.Equal(t, feed.FeedConfig.MaxItems, 100)
+
+	t.Run("definitions convert back to equal configs", func(t *testing.T) {
+		rssCfg := config.NewRSSSource(&rss)
+		gt.NoError(t, rssCfg.Validate())
+		back := rssCfg.Source("vendor-blog")
+		gt.A(t, model.DiffSources(&rss, &back)).Length(0)
+
+		feedCfg := config.NewFeedSource(&feed)
+		gt.NoError(t, feedCfg.Validate())
+		backFeed := feedCfg.Source("urlhaus")
+		gt.A(t, model.DiffSources(&feed, &backFeed)).Length(0)
+	})
+
+	t.Run("changes are validated by the config rules", func(t *testing.T) {
+		rssCfg := config.NewRSSSource(&rss)
+		rssCfg.Extraction = model.ExtractionModeRegex
+		gt.Error(t, rssCfg.Validate()) // prompt settings require an LLM mode
+
+		feedCfg := config.NewFeedSource(&feed)
+		feedCfg.RawTTL = "-1h"
+		gt.Error(t, feedCfg.Validate())
+	})
+
+	t.Run("zero TTL is kept", func(t *testing.T) {
+		ttl := time.Duration(0)
+		src := feed
+		src.TTL = &ttl
+		feedCfg := config.NewFeedSource(&src)
+		gt.NoError(t, feedCfg.Validate())
+		gt.Equal(t, *feedCfg.Source("urlhaus").TTL, time.Duration(0))
+	})
+}
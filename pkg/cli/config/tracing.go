@@ -0,0 +1,111 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/urfave/cli/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	TraceExporterNone   = "none"
+	TraceExporterOTLP   = "otlp"
+	TraceExporterStdout = "stdout"
+)
+
+// Tracing represents the configuration of OpenTelemetry tracing. The OTLP
+// endpoint, headers and protocol options are read from the standard
+// OTEL_EXPORTER_OTLP_* environment variables.
+type Tracing struct {
+	exporter    string
+	sampleRatio float64
+}
+
+// Flags returns CLI flags for the tracing configuration
+func (x *Tracing) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "trace-exporter",
+			Category:    "tracing",
+			Sources:     cli.EnvVars("BEEHIVE_TRACE_EXPORTER"),
+			Usage:       "Export OpenTelemetry spans [none|otlp|stdout]",
+			Value:       TraceExporterNone,
+			Destination: &x.exporter,
+		},
+		&cli.Float64Flag{
+			Name:        "trace-sample-ratio",
+			Category:    "tracing",
+			Sources:     cli.EnvVars("BEEHIVE_TRACE_SAMPLE_RATIO"),
+			Usage:       "Ratio of traces to sample, between 0 and 1",
+			Value:       1,
+			Destination: &x.sampleRatio,
+		},
+	}
+}
+
+func (x Tracing) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("exporter", x.exporter),
+		slog.Float64("sample_ratio", x.sampleRatio),
+	)
+}
+
+// Configure installs the global tracer provider and returns a function that
+// flushes and shuts it down. Without an exporter spans are not recorded and
+// shutdown does nothing.
+func (x *Tracing) Configure(ctx context.Context) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+
+	if x.sampleRatio < 0 || x.sampleRatio > 1 {
+		return noop, goerr.New("trace sample ratio must be between 0 and 1", goerr.V("ratio", x.sampleRatio))
+	}
+
+	var exporter sdktrace.SpanExporter
+	switch x.exporter {
+	case "", TraceExporterNone:
+		return noop, nil
+	case TraceExporterOTLP:
+		exp, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return noop, goerr.Wrap(err, "failed to create OTLP trace exporter")
+		}
+		exporter = exp
+	case TraceExporterStdout:
+		exp, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return noop, goerr.Wrap(err, "failed to create stdout trace exporter")
+		}
+		exporter = exp
+	default:
+		return noop, goerr.New("invalid trace exporter", goerr.V("exporter", x.exporter))
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the defaults
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", "beehive")),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return noop, goerr.Wrap(err, "failed to create trace resource")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(x.sampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
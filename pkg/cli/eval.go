@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"context"
+	"os"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/gollem"
+	"github.com/secmon-lab/beehive/pkg/cli/config"
+	"github.com/secmon-lab/beehive/pkg/domain/eval"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/service/llmrecord"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+	"github.com/urfave/cli/v3"
+)
+
+var errEvalRegressed = goerr.New("extraction quality regressed from baseline")
+
+func cmdEval() *cli.Command {
+	var (
+		llmCfg           config.LLM
+		goldenDir        string
+		mode             string
+		outputPath       string
+		baselinePath     string
+		recordDir        string
+		replayDir        string
+		failOnRegression bool
+	)
+
+	return &cli.Command{
+		Name:  "eval",
+		Usage: "Evaluate IoC extraction against golden articles",
+		Flags: append(llmCfg.Flags(),
+			&cli.StringFlag{
+				Name:        "golden",
+				Aliases:     []string{"g"},
+				Usage:       "Directory of golden articles (<name>.toml with labeled IoCs and <name>.md)",
+				Value:       "examples/golden",
+				Destination: &goldenDir,
+			},
+			&cli.StringFlag{
+				Name:        "mode",
+				Usage:       "Extraction mode for all articles (llm, regex, prefilter, crosscheck); default is the mode of each article",
+				Destination: &mode,
+			},
+			&cli.StringFlag{
+				Name:        "output",
+				Aliases:     []string{"o"},
+				Usage:       "Write the evaluation report as JSON to this file, for use as a baseline",
+				Destination: &outputPath,
+			},
+			&cli.StringFlag{
+				Name:        "baseline",
+				Aliases:     []string{"b"},
+				Usage:       "Compare with a report of a previous run",
+				Destination: &baselinePath,
+			},
+			&cli.StringFlag{
+				Name:        "record",
+				Usage:       "Record LLM responses to this directory",
+				Destination: &recordDir,
+			},
+			&cli.StringFlag{
+				Name:        "replay",
+				Usage:       "Replay LLM responses recorded in this directory instead of calling the LLM",
+				Destination: &replayDir,
+			},
+			&cli.BoolFlag{
+				Name:        "fail-on-regression",
+				Usage:       "Exit with an error if IoCs are newly missed or new false positives appear compared with the baseline",
+				Destination: &failOnRegression,
+			},
+		),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			logger := logging.Default()
+
+			if recordDir != "" && replayDir != "" {
+				return goerr.New("record and replay cannot be used together")
+			}
+			if mode != "" && !model.ExtractionMode(mode).IsValid() {
+				return goerr.New("invalid extraction mode", goerr.V("mode", mode))
+			}
+			if failOnRegression && baselinePath == "" {
+				return goerr.New("fail-on-regression requires a baseline")
+			}
+
+			cases, err := eval.LoadCases(goldenDir)
+			if err != nil {
+				return goerr.Wrap(err, "failed to load golden articles")
+			}
+
+			var baseline *eval.Report
+			if baselinePath != "" {
+				if baseline, err = eval.LoadReport(baselinePath); err != nil {
+					return goerr.Wrap(err, "failed to load baseline")
+				}
+			}
+
+			var llmClient gollem.LLMClient
+			llmModel := llmCfg.ModelID()
+			if replayDir != "" {
+				if llmClient, err = llmrecord.NewReplayer(replayDir); err != nil {
+					return goerr.Wrap(err, "failed to create replayer")
+				}
+				llmModel = "replay"
+			} else if mode == string(model.ExtractionModeRegex) {
+				llmModel = "none"
+			} else {
+				if llmClient, err = llmCfg.NewLLMClient(ctx); err != nil {
+					return goerr.Wrap(err, "failed to create LLM client")
+				}
+				if recordDir != "" {
+					if llmClient, err = llmrecord.NewRecorder(llmClient, recordDir); err != nil {
+						return goerr.Wrap(err, "failed to create recorder")
+					}
+				}
+			}
+
+			logger.Info("evaluating extraction",
+				"golden", goldenDir,
+				"cases", len(cases),
+				"model", llmModel,
+				"record", recordDir,
+				"replay", replayDir)
+
+			ctx = logging.With(ctx, logger)
+			uc := usecase.NewEvalUseCase(llmClient,
+				usecase.WithEvalModel(llmModel),
+				usecase.WithEvalMode(model.ExtractionMode(mode)),
+				usecase.WithEvalExtractorOptions(llmCfg.ExtractorOptions()...),
+			)
+			report := uc.Run(ctx, cases)
+
+			if err := report.WriteText(os.Stdout); err != nil {
+				return err
+			}
+			if outputPath != "" {
+				if err := report.Save(outputPath); err != nil {
+					return goerr.Wrap(err, "failed to save evaluation report")
+				}
+				logger.Info("saved evaluation report", "path", outputPath)
+			}
+
+			if baseline != nil {
+				diff := eval.Compare(baseline, report)
+				_, _ = os.Stdout.WriteString("\n")
+				if err := diff.WriteText(os.Stdout); err != nil {
+					return err
+				}
+				if failOnRegression && diff.Regressed() {
+					return goerr.Wrap(errEvalRegressed, "evaluation failed", goerr.V("baseline", baselinePath))
+				}
+			}
+
+			return nil
+		},
+	}
+}
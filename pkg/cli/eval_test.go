@@ -0,0 +1,46 @@
+package cli_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/cli"
+)
+
+const goldenDir = "../../examples/golden"
+
+func runEval(ctx context.Context, golden string) error {
+	return cli.Run(ctx, []string{
+		"beehive", "eval",
+		"--golden", golden,
+		"--replay", filepath.Join(goldenDir, "recordings"),
+		"--baseline", filepath.Join(goldenDir, "baseline.json"),
+		"--fail-on-regression",
+	}, "test")
+}
+
+func TestEval_ReplayGolden(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("recorded responses do not regress from the baseline", func(t *testing.T) {
+		gt.NoError(t, runEval(ctx, goldenDir))
+	})
+
+	t.Run("newly missed IoC fails the run", func(t *testing.T) {
+		dir := t.TempDir()
+		gt.NoError(t, os.CopyFS(dir, os.DirFS(goldenDir)))
+
+		// A malicious IoC that the recorded response does not contain
+		path := filepath.Join(dir, "malware_campaign.toml")
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+		gt.NoError(t, err)
+		_, err = f.WriteString("\n[[iocs]]\ntype = \"domain\"\nvalue = \"secure-banking-verify.net\"\nlabel = \"malicious\"\n")
+		gt.NoError(t, err)
+		gt.NoError(t, f.Close())
+
+		gt.Error(t, runEval(ctx, dir))
+	})
+}
@@ -8,8 +8,10 @@ import (
 
 	"github.com/m-mizutani/goerr/v2"
 	"github.com/secmon-lab/beehive/pkg/cli/config"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
 	"github.com/secmon-lab/beehive/pkg/domain/model"
 	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/repository/instrumented"
 	"github.com/secmon-lab/beehive/pkg/repository/memory"
 	"github.com/secmon-lab/beehive/pkg/usecase"
 	"github.com/secmon-lab/beehive/pkg/utils/logging"
@@ -19,16 +21,20 @@ import (
 func cmdFetch() *cli.Command {
 	var (
 		llmCfg       config.LLM
+		cacheCfg     config.ExtractionCache
+		embeddingCfg config.Embedding
 		firestoreCfg config.Firestore
+		auditCfg     config.Audit
 		configPath   string
 		tags         []string
 		dryRun       bool
+		refreshCache []string
 	)
 
 	return &cli.Command{
 		Name:  "fetch",
 		Usage: "Fetch IoCs from configured sources",
-		Flags: append(append(llmCfg.Flags(), firestoreCfg.Flags()...),
+		Flags: append(append(append(append(append(llmCfg.Flags(), cacheCfg.Flags()...), embeddingCfg.Flags()...), firestoreCfg.Flags()...), auditCfg.Flags()...),
 			&cli.StringFlag{
 				Name:        "config",
 				Aliases:     []string{"c"},
@@ -48,6 +54,11 @@ func cmdFetch() *cli.Command {
 				Usage:       "Dry run mode (fetch but don't save to database)",
 				Destination: &dryRun,
 			},
+			&cli.StringSliceFlag{
+				Name:        "refresh-cache",
+				Usage:       "Ignore cached LLM extraction results of a source, e.g. after changing its prompt (can be specified multiple times)",
+				Destination: &refreshCache,
+			},
 		),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			logger := logging.Default()
@@ -76,7 +87,7 @@ func cmdFetch() *cli.Command {
 
 			if dryRun {
 				// Use in-memory storage for dry-run
-				memRepo = memory.New()
+				memRepo = memory.New(memory.WithEmbeddingDimension(embeddingCfg.Dimension))
 				logger.Info("using in-memory storage (dry-run mode)")
 			} else {
 				// Use Firestore for production
@@ -84,7 +95,9 @@ func cmdFetch() *cli.Command {
 					return goerr.New("firestore-project-id is required for production mode")
 				}
 
-				opts := []firestoreRepo.Option{}
+				opts := []firestoreRepo.Option{
+					firestoreRepo.WithEmbeddingDimension(embeddingCfg.Dimension),
+				}
 				if firestoreCfg.DatabaseID != "" {
 					opts = append(opts, firestoreRepo.WithDatabaseID(firestoreCfg.DatabaseID))
 				}
@@ -107,24 +120,96 @@ func cmdFetch() *cli.Command {
 					"database_id", firestoreCfg.DatabaseID)
 			}
 
-			// Create LLM client
-			llmClient, err := llmCfg.NewLLMClient(ctx)
-			if err != nil {
-				return goerr.Wrap(err, "failed to create LLM client")
+			// Create LLM client on first use; RSS sources requiring an LLM
+			// are skipped or fall back to deterministic extraction without it
+			if err := llmCfg.Validate(); err != nil {
+				return goerr.Wrap(err, "invalid LLM config")
+			}
+			llmFallback, _ := llmCfg.Fallback()
+			llmClient := llmCfg.NewLazyLLMClient(ctx)
+			if llmClient != nil {
+				logger.Info("configured LLM client", "provider", llmCfg.EffectiveProvider(), "model", llmCfg.Model)
+			} else {
+				warnSourcesRequiringLLM(logger, cfg, llmFallback)
 			}
 
-			logger.Info("initialized LLM client", "provider", llmCfg.Provider, "model", llmCfg.Model)
-
-			// Convert config to sources map (model.Source)
-			sourcesMap := convertConfigToSourcesMap(cfg)
-			logger.Info("converted sources from config", "total", len(sourcesMap))
+			embedder, err := embeddingCfg.New(ctx, &llmCfg)
+			if err != nil {
+				return goerr.Wrap(err, "failed to create embedder")
+			}
+			logger.Info("initialized embedder", "model", embedder.Model(), "dimension", embedder.Dimension())
 
 			// Initialize FetchUseCase
 			var fetchUC *usecase.FetchUseCase
+			fetchOpts := []usecase.FetchOption{
+				usecase.WithTTLPolicy(cfg.TTLPolicy()),
+				usecase.WithFetchConfidencePolicy(cfg.ConfidencePolicy()),
+				usecase.WithExtractorOptions(llmCfg.ExtractorOptions()...),
+				usecase.WithFetchEmbedder(embedder),
+				usecase.WithLLMFallback(llmFallback),
+			}
+			detector, err := cfg.Brand.NewDetector()
+			if err != nil {
+				return goerr.Wrap(err, "failed to create lookalike detector")
+			}
+			if detector != nil {
+				fetchOpts = append(fetchOpts, usecase.WithBrandDetector(detector))
+			}
+			asnResolver, err := cfg.ASN.NewResolver()
+			if err != nil {
+				return goerr.Wrap(err, "failed to create ASN resolver")
+			}
+			if asnResolver != nil {
+				fetchOpts = append(fetchOpts, usecase.WithASNResolver(asnResolver))
+			}
+			var cacheRepo interfaces.ExtractionCacheRepository = repo
+			if dryRun {
+				cacheRepo = memRepo
+			}
+			cache, err := cacheCfg.New(cacheRepo)
+			if err != nil {
+				return goerr.Wrap(err, "failed to create LLM cache")
+			}
+			if cache != nil {
+				fetchOpts = append(fetchOpts,
+					usecase.WithExtractionCache(cache, llmCfg.ModelID(), cacheCfg.TTL),
+					usecase.WithCacheRefresh(refreshCache...))
+				logger.Info("using LLM extraction cache", "backend", cacheCfg.Backend, "ttl", cacheCfg.TTL, "refresh", refreshCache)
+			}
+			var storage interfaces.Repository = repo
+			if dryRun {
+				storage = memRepo
+			}
+
+			// Audit records of a dry run stay in memory and are not mirrored
+			var auditor *usecase.Auditor
+			if dryRun {
+				auditor = usecase.NewAuditor(memRepo)
+			} else {
+				var closeAudit func()
+				auditor, closeAudit, err = newAuditor(logger, &auditCfg, repo)
+				if err != nil {
+					return err
+				}
+				defer closeAudit()
+			}
+			fetchOpts = append(fetchOpts, usecase.WithFetchAuditor(auditor))
+
+			// Sources are fetched from the repository, seeded by the config
+			sourcesMap, err := importSources(logging.With(ctx, logger), logger, usecase.New(storage, usecase.WithAuditor(auditor)), cfg, false)
+			if err != nil {
+				return err
+			}
+
+			// Repository operations are traced as part of the fetch
 			if dryRun {
-				fetchUC = usecase.NewFetchUseCase(memRepo, llmClient)
+				fetchUC = usecase.NewFetchUseCase(instrumented.New(memRepo, nil), llmClient, fetchOpts...)
 			} else {
-				fetchUC = usecase.NewFetchUseCase(repo, llmClient)
+				// Notifications are not sent in dry-run mode
+				if notifier := newNotificationUseCase(cfg, repo); notifier != nil {
+					fetchOpts = append(fetchOpts, usecase.WithNotifier(notifier))
+				}
+				fetchUC = usecase.NewFetchUseCase(instrumented.New(repo, nil), llmClient, fetchOpts...)
 			}
 
 			// Execute fetch via usecase
@@ -143,6 +228,22 @@ func cmdFetch() *cli.Command {
 	}
 }
 
+// warnSourcesRequiringLLM reports the RSS sources of cfg that require an LLM
+// when no LLM is configured
+func warnSourcesRequiringLLM(logger *slog.Logger, cfg *config.Config, fallback model.LLMFallback) {
+	ids := cfg.SourcesRequiringLLM()
+	switch {
+	case len(ids) == 0:
+		logger.Info("LLM is not configured")
+	case fallback == model.LLMFallbackRegex:
+		logger.Warn("LLM is not configured, IoCs of RSS sources requiring an LLM are extracted deterministically",
+			"sources", ids)
+	default:
+		logger.Warn("LLM is not configured, RSS sources requiring an LLM are skipped; set --llm-provider or --llm-fallback=regex",
+			"sources", ids)
+	}
+}
+
 // findConfigFile searches for the configuration file in multiple locations
 func findConfigFile(path string) (string, error) {
 	// If absolute path is provided, use it directly
@@ -172,46 +273,6 @@ func findConfigFile(path string) (string, error) {
 		goerr.V("searched_paths", searchPaths))
 }
 
-// convertConfigToSourcesMap converts config to model.Source map
-func convertConfigToSourcesMap(cfg *config.Config) map[string]model.Source {
-	sourcesMap := make(map[string]model.Source)
-
-	// Add RSS sources
-	for id, rssSrc := range cfg.RSS {
-		if rssSrc.Disabled {
-			continue
-		}
-		sourcesMap[id] = model.Source{
-			Type:    model.SourceTypeRSS,
-			URL:     rssSrc.URL,
-			Tags:    rssSrc.Tags.Strings(),
-			Enabled: !rssSrc.Disabled,
-			RSSConfig: &model.RSSConfig{
-				MaxArticles: rssSrc.MaxArticles,
-			},
-		}
-	}
-
-	// Add Feed sources
-	for id, feedSrc := range cfg.Feed {
-		if feedSrc.Disabled {
-			continue
-		}
-		sourcesMap[id] = model.Source{
-			Type:    model.SourceTypeFeed,
-			URL:     feedSrc.GetURL(),
-			Tags:    feedSrc.Tags.Strings(),
-			Enabled: !feedSrc.Disabled,
-			FeedConfig: &model.FeedConfig{
-				Schema:   feedSrc.Schema.String(),
-				MaxItems: feedSrc.MaxItems,
-			},
-		}
-	}
-
-	return sourcesMap
-}
-
 // printFetchResults prints the fetch results using structured logging
 func printFetchResults(histories []*model.History) {
 	logger := logging.Default()
@@ -11,12 +11,15 @@ import (
 )
 
 func cmdMigrate() *cli.Command {
-	var firestoreCfg config.Firestore
+	var (
+		firestoreCfg config.Firestore
+		embeddingCfg config.Embedding
+	)
 
 	return &cli.Command{
 		Name:  "migrate",
 		Usage: "Migrate Firestore schema (create vector indexes)",
-		Flags: firestoreCfg.Flags(),
+		Flags: append(firestoreCfg.Flags(), embeddingCfg.Flags()...),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			logger := logging.From(ctx)
 
@@ -24,11 +27,17 @@ func cmdMigrate() *cli.Command {
 				return goerr.New("firestore-project-id is required")
 			}
 
+			if err := embeddingCfg.Validate(); err != nil {
+				return goerr.Wrap(err, "invalid embedding config")
+			}
+
 			logger.Info("Starting Firestore migration",
 				"project_id", firestoreCfg.ProjectID,
-				"database_id", firestoreCfg.DatabaseID)
+				"database_id", firestoreCfg.DatabaseID,
+				"embedding_dimension", embeddingCfg.Dimension)
 
-			if err := migration.MigrateFirestore(ctx, firestoreCfg.ProjectID, firestoreCfg.DatabaseID); err != nil {
+			if err := migration.MigrateFirestore(ctx, firestoreCfg.ProjectID, firestoreCfg.DatabaseID,
+				migration.WithEmbeddingDimension(embeddingCfg.Dimension)); err != nil {
 				return goerr.Wrap(err, "migration failed")
 			}
 
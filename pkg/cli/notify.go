@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/cli/config"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+	"github.com/urfave/cli/v3"
+)
+
+// digestCheckInterval is how often serve checks for a completed digest window
+const digestCheckInterval = 15 * time.Minute
+
+// newNotificationUseCase creates the notification use case from configuration.
+// Returns nil if no notification rules are configured.
+func newNotificationUseCase(cfg *config.Config, repo interfaces.Repository) *usecase.NotificationUseCase {
+	if len(cfg.Notify.Rules) == 0 {
+		return nil
+	}
+
+	var opts []usecase.NotificationOption
+	if cfg.Notify.RetryAttempts > 0 || cfg.Notify.RetryBackoff != nil {
+		attempts := cfg.Notify.RetryAttempts
+		if attempts == 0 {
+			attempts = 3
+		}
+		backoff := 2 * time.Second
+		if cfg.Notify.RetryBackoff != nil {
+			backoff = *cfg.Notify.RetryBackoff
+		}
+		opts = append(opts, usecase.WithNotificationRetry(attempts, backoff))
+	}
+
+	return usecase.NewNotificationUseCase(repo, cfg.NotificationChannels(), cfg.NotificationRules(), opts...)
+}
+
+func cmdNotify() *cli.Command {
+	var (
+		firestoreCfg config.Firestore
+		configPath   string
+	)
+
+	return &cli.Command{
+		Name:  "notify",
+		Usage: "Notification operations",
+		Commands: []*cli.Command{
+			{
+				Name:  "digest",
+				Usage: "Send daily digests for the last completed window that have not been sent yet",
+				Flags: append(firestoreCfg.Flags(),
+					&cli.StringFlag{
+						Name:        "config",
+						Aliases:     []string{"c"},
+						Usage:       "Path to configuration file",
+						Value:       "config/config.toml",
+						Destination: &configPath,
+						Sources:     cli.EnvVars("BEEHIVE_CONFIG"),
+					},
+				),
+				Action: func(ctx context.Context, c *cli.Command) error {
+					logger := logging.Default()
+
+					if firestoreCfg.ProjectID == "" {
+						return goerr.New("firestore-project-id is required")
+					}
+
+					cfgPath, err := findConfigFile(configPath)
+					if err != nil {
+						return goerr.Wrap(err, "failed to find config file",
+							goerr.V("config_path", configPath))
+					}
+					cfg, err := config.LoadConfig(cfgPath)
+					if err != nil {
+						return goerr.Wrap(err, "failed to load config", goerr.V("path", cfgPath))
+					}
+
+					opts := []firestoreRepo.Option{}
+					if firestoreCfg.DatabaseID != "" {
+						opts = append(opts, firestoreRepo.WithDatabaseID(firestoreCfg.DatabaseID))
+					}
+
+					repo, err := firestoreRepo.New(ctx, firestoreCfg.ProjectID, opts...)
+					if err != nil {
+						return goerr.Wrap(err, "failed to create Firestore repository",
+							goerr.V("project_id", firestoreCfg.ProjectID),
+							goerr.V("database_id", firestoreCfg.DatabaseID))
+					}
+					defer func() {
+						if err := repo.Close(); err != nil {
+							logger.Error("failed to close Firestore client", "error", err)
+						}
+					}()
+
+					notifier := newNotificationUseCase(cfg, repo)
+					if notifier == nil || !notifier.HasDigestRules() {
+						logger.Info("no digest rules configured")
+						return nil
+					}
+
+					ctx = logging.With(ctx, logger)
+					sources, err := usecase.New(repo).SourcesMap(ctx)
+					if err != nil {
+						return goerr.Wrap(err, "failed to list sources")
+					}
+					sent, err := notifier.SendDigests(ctx, sources, time.Now())
+					if err != nil {
+						return goerr.Wrap(err, "failed to send digests")
+					}
+
+					logger.Info("digest completed", "sent", sent)
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/cli/config"
+	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+	"github.com/urfave/cli/v3"
+)
+
+func cmdReembed() *cli.Command {
+	var (
+		firestoreCfg config.Firestore
+		llmCfg       config.LLM
+		embeddingCfg config.Embedding
+		all          bool
+	)
+
+	return &cli.Command{
+		Name:  "reembed",
+		Usage: "Generate embeddings of stored IoCs with the configured embedding model (backfill after changing it)",
+		Flags: append(append(append(firestoreCfg.Flags(), llmCfg.Flags()...), embeddingCfg.Flags()...),
+			&cli.BoolFlag{
+				Name:        "all",
+				Usage:       "Re-embed all IoCs, not only those without an embedding of the configured model and dimension",
+				Destination: &all,
+			},
+		),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			logger := logging.Default()
+
+			if firestoreCfg.ProjectID == "" {
+				return goerr.New("firestore-project-id is required")
+			}
+
+			embedder, err := embeddingCfg.New(ctx, &llmCfg)
+			if err != nil {
+				return goerr.Wrap(err, "failed to create embedder")
+			}
+
+			opts := []firestoreRepo.Option{
+				firestoreRepo.WithEmbeddingDimension(embeddingCfg.Dimension),
+			}
+			if firestoreCfg.DatabaseID != "" {
+				opts = append(opts, firestoreRepo.WithDatabaseID(firestoreCfg.DatabaseID))
+			}
+
+			repo, err := firestoreRepo.New(ctx, firestoreCfg.ProjectID, opts...)
+			if err != nil {
+				return goerr.Wrap(err, "failed to create Firestore repository",
+					goerr.V("project_id", firestoreCfg.ProjectID),
+					goerr.V("database_id", firestoreCfg.DatabaseID))
+			}
+			defer func() {
+				if err := repo.Close(); err != nil {
+					logger.Error("failed to close Firestore client", "error", err)
+				}
+			}()
+
+			logger.Info("re-embedding IoCs",
+				"model", embedder.Model(),
+				"dimension", embedder.Dimension(),
+				"all", all)
+
+			ctx = logging.With(ctx, logger)
+			uc := usecase.New(repo, usecase.WithEmbedder(embedder))
+			embedded, err := uc.ReembedIoCs(ctx, all)
+			if err != nil {
+				return goerr.Wrap(err, "failed to re-embed IoCs", goerr.V("embedded", embedded))
+			}
+
+			logger.Info("re-embedding completed", "embedded", embedded)
+			return nil
+		},
+	}
+}
@@ -15,7 +15,10 @@ import (
 	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
 	"github.com/secmon-lab/beehive/pkg/domain/model"
 	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/repository/instrumented"
 	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/service/auth"
+	"github.com/secmon-lab/beehive/pkg/service/metrics"
 	"github.com/secmon-lab/beehive/pkg/usecase"
 	"github.com/secmon-lab/beehive/pkg/utils/logging"
 	"github.com/urfave/cli/v3"
@@ -28,13 +31,21 @@ func cmdServe() *cli.Command {
 		configPath     string
 		firestoreCfg   config.Firestore
 		llmCfg         config.LLM
+		cacheCfg       config.ExtractionCache
+		embeddingCfg   config.Embedding
+		auditCfg       config.Audit
+		enableMetrics  bool
+		sweepInterval  time.Duration
+		watchInterval  time.Duration
+		fetchInterval  time.Duration
+		jobConcurrency int
 	)
 
 	return &cli.Command{
 		Name:    "serve",
 		Aliases: []string{"s"},
 		Usage:   "Start HTTP server",
-		Flags: append(append(firestoreCfg.Flags(), llmCfg.Flags()...),
+		Flags: append(append(append(append(append(firestoreCfg.Flags(), llmCfg.Flags()...), cacheCfg.Flags()...), embeddingCfg.Flags()...), auditCfg.Flags()...),
 			&cli.StringFlag{
 				Name:        "addr",
 				Usage:       "HTTP server address",
@@ -49,6 +60,13 @@ func cmdServe() *cli.Command {
 				Sources:     cli.EnvVars("BEEHIVE_GRAPHIQL"),
 				Destination: &enableGraphiQL,
 			},
+			&cli.BoolFlag{
+				Name:        "metrics",
+				Usage:       "Serve Prometheus metrics at /metrics",
+				Value:       true,
+				Sources:     cli.EnvVars("BEEHIVE_METRICS"),
+				Destination: &enableMetrics,
+			},
 			&cli.StringFlag{
 				Name:        "config",
 				Aliases:     []string{"c"},
@@ -57,6 +75,33 @@ func cmdServe() *cli.Command {
 				Destination: &configPath,
 				Sources:     cli.EnvVars("BEEHIVE_CONFIG"),
 			},
+			&cli.DurationFlag{
+				Name:        "sweep-interval",
+				Usage:       "Interval to expire IoCs past their TTL and rescore confidence (0 disables the sweeper)",
+				Sources:     cli.EnvVars("BEEHIVE_SWEEP_INTERVAL"),
+				Destination: &sweepInterval,
+			},
+			&cli.DurationFlag{
+				Name:        "config-watch-interval",
+				Usage:       "Interval to check the configuration file for changes to reload its sources (0 disables the check; SIGHUP always reloads)",
+				Value:       5 * time.Second,
+				Sources:     cli.EnvVars("BEEHIVE_CONFIG_WATCH_INTERVAL"),
+				Destination: &watchInterval,
+			},
+			&cli.DurationFlag{
+				Name:        "fetch-interval",
+				Usage:       "Expected interval between fetches of a source; /status reports sources not fetched within twice the interval as stale",
+				Value:       httpctrl.DefaultFetchInterval,
+				Sources:     cli.EnvVars("BEEHIVE_FETCH_INTERVAL"),
+				Destination: &fetchInterval,
+			},
+			&cli.IntFlag{
+				Name:        "fetch-job-concurrency",
+				Usage:       "Number of fetch jobs started through the API running at the same time; further jobs are queued",
+				Value:       2,
+				Sources:     cli.EnvVars("BEEHIVE_FETCH_JOB_CONCURRENCY"),
+				Destination: &jobConcurrency,
+			},
 		),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			logger := logging.Default()
@@ -68,15 +113,24 @@ func cmdServe() *cli.Command {
 				"config_path", configPath,
 				"firestore_project", firestoreCfg.ProjectID,
 				"firestore_database", firestoreCfg.DatabaseID,
-				"llm_provider", llmCfg.Provider,
+				"llm_provider", llmCfg.EffectiveProvider(),
 				"llm_model", llmCfg.Model,
+				"embedding_provider", embeddingCfg.Provider,
+				"embedding_dimension", embeddingCfg.Dimension,
+				"sweep_interval", sweepInterval,
+				"config_watch_interval", watchInterval,
+				"fetch_job_concurrency", jobConcurrency,
+				"audit_log_file", auditCfg.File,
+				"metrics", enableMetrics,
 			)
 
 			// Initialize repository
 			var repo interfaces.Repository
 			if firestoreCfg.ProjectID != "" {
 				// Use Firestore
-				opts := []firestoreRepo.Option{}
+				opts := []firestoreRepo.Option{
+					firestoreRepo.WithEmbeddingDimension(embeddingCfg.Dimension),
+				}
 				if firestoreCfg.DatabaseID != "" {
 					opts = append(opts, firestoreRepo.WithDatabaseID(firestoreCfg.DatabaseID))
 				}
@@ -96,7 +150,7 @@ func cmdServe() *cli.Command {
 				logger.Info("using Firestore repository", "project_id", firestoreCfg.ProjectID, "database_id", firestoreCfg.DatabaseID)
 			} else {
 				// Use in-memory repository with sample data
-				memRepo := memory.New()
+				memRepo := memory.New(memory.WithEmbeddingDimension(embeddingCfg.Dimension))
 				if err := addSampleData(ctx, memRepo); err != nil {
 					logger.Warn("Failed to add sample data", "error", err)
 				}
@@ -104,24 +158,177 @@ func cmdServe() *cli.Command {
 				logger.Info("using in-memory repository with sample data")
 			}
 
-			// Initialize LLM client
-			llmClient, err := llmCfg.NewLLMClient(ctx)
+			// Metrics of repository operations, fetches, LLM calls and GraphQL.
+			// Repository operations are traced even without metrics.
+			var prom *metrics.Prometheus
+			var repoMetrics interfaces.Metrics
+			if enableMetrics {
+				prom = metrics.New()
+				repoMetrics = prom
+			}
+			repo = instrumented.New(repo, repoMetrics)
+
+			// Create LLM client on first use; serve runs without LLM
+			if err := llmCfg.Validate(); err != nil {
+				return goerr.Wrap(err, "invalid LLM config")
+			}
+			llmFallback, _ := llmCfg.Fallback()
+			llmClient := llmCfg.NewLazyLLMClient(ctx)
+
+			embedder, err := embeddingCfg.New(ctx, &llmCfg)
 			if err != nil {
-				return goerr.Wrap(err, "failed to create LLM client")
+				return goerr.Wrap(err, "failed to create embedder")
+			}
+
+			// Load TTL and confidence policies from configuration
+			ttlPolicy := model.NewTTLPolicy(nil)
+			confidencePolicy := model.NewConfidencePolicy(nil)
+			var cfg *config.Config
+			if configPath != "" {
+				cfg, err = config.LoadConfig(configPath)
+				if err != nil {
+					return goerr.Wrap(err, "failed to load config", goerr.V("path", configPath))
+				}
+				ttlPolicy = cfg.TTLPolicy()
+				confidencePolicy = cfg.ConfidencePolicy()
+				if llmClient == nil {
+					warnSourcesRequiringLLM(logger, cfg, llmFallback)
+				}
 			}
 
 			// Initialize use cases
-			uc := usecase.New(repo)
-			fetchUC := usecase.NewFetchUseCase(repo, llmClient)
+			auditor, closeAudit, err := newAuditor(logger, &auditCfg, repo)
+			if err != nil {
+				return err
+			}
+			defer closeAudit()
 
-			// Initialize GraphQL resolver
-			gqlResolver, err := graphql.NewResolver(repo, uc, fetchUC, configPath)
+			ucOpts := []usecase.Option{usecase.WithConfidencePolicy(confidencePolicy), usecase.WithAuditor(auditor)}
+			if cfg == nil || !cfg.Auth.Enabled() {
+				ucOpts = append(ucOpts, usecase.WithOpenAccess())
+			}
+			uc := usecase.New(repo, ucOpts...)
+
+			// Seed stored sources from the config and reload them when the
+			// file changes or on SIGHUP
+			var resolverOpts []graphql.ResolverOption
+			if cfg != nil {
+				reloader := usecase.NewSourceConfigReloader(uc, configPath, loadConfigSources)
+				result, err := reloader.Load(logging.With(ctx, logger))
+				if err != nil {
+					return goerr.Wrap(err, "failed to import sources")
+				}
+				logImportResult(logger, result)
+				resolverOpts = append(resolverOpts, graphql.WithConfigStatus(reloader.Status))
+
+				reloadCtx, cancelReload := context.WithCancel(logging.With(ctx, logger))
+				defer cancelReload()
+				go reloader.Run(reloadCtx, watchInterval, notifyHangup(reloadCtx))
+				logger.Info("watching config file for source changes", "path", configPath, "interval", watchInterval)
+			}
+
+			fetchOpts := []usecase.FetchOption{
+				usecase.WithTTLPolicy(ttlPolicy),
+				usecase.WithFetchConfidencePolicy(confidencePolicy),
+				usecase.WithExtractorOptions(llmCfg.ExtractorOptions()...),
+				usecase.WithFetchEmbedder(embedder),
+				usecase.WithLLMFallback(llmFallback),
+				usecase.WithFetchAuditor(auditor),
+			}
+			if prom != nil {
+				fetchOpts = append(fetchOpts, usecase.WithFetchMetrics(prom))
+			}
+			cache, err := cacheCfg.New(repo)
 			if err != nil {
-				return goerr.Wrap(err, "failed to create GraphQL resolver")
+				return goerr.Wrap(err, "failed to create LLM cache")
+			}
+			if cache != nil {
+				fetchOpts = append(fetchOpts, usecase.WithExtractionCache(cache, llmCfg.ModelID(), cacheCfg.TTL))
+				logger.Info("using LLM extraction cache", "backend", cacheCfg.Backend, "ttl", cacheCfg.TTL)
+			}
+
+			// Lookalike detection, ASN lookup, notifications on fetch results and daily digests
+			if cfg != nil {
+				detector, err := cfg.Brand.NewDetector()
+				if err != nil {
+					return goerr.Wrap(err, "failed to create lookalike detector")
+				}
+				if detector != nil {
+					fetchOpts = append(fetchOpts, usecase.WithBrandDetector(detector))
+					logger.Info("enabled lookalike domain detection", "domains", detector.Domains())
+				}
+
+				asnResolver, err := cfg.ASN.NewResolver()
+				if err != nil {
+					return goerr.Wrap(err, "failed to create ASN resolver")
+				}
+				if asnResolver != nil {
+					fetchOpts = append(fetchOpts, usecase.WithASNResolver(asnResolver))
+					logger.Info("enabled ASN lookup of IoC hosts", "table", cfg.ASN.Table, "resolve_hosts", cfg.ASN.ResolveHosts)
+				}
+
+				if notifier := newNotificationUseCase(cfg, repo); notifier != nil {
+					fetchOpts = append(fetchOpts, usecase.WithNotifier(notifier))
+
+					if notifier.HasDigestRules() {
+						digestCtx, cancelDigest := context.WithCancel(logging.With(ctx, logger))
+						defer cancelDigest()
+						go notifier.RunDigestScheduler(digestCtx, digestCheckInterval,
+							func() map[string]model.Source {
+								sources, err := uc.SourcesMap(digestCtx)
+								if err != nil {
+									logger.Error("failed to list sources for digest", "error", err)
+								}
+								return sources
+							})
+						logger.Info("started notification digest scheduler", "interval", digestCheckInterval)
+					}
+				}
 			}
 
+			fetchUC := usecase.NewFetchUseCase(repo, llmClient, fetchOpts...)
+
+			// Start expiry sweeper
+			if sweepInterval > 0 {
+				sweepCtx, cancelSweep := context.WithCancel(logging.With(ctx, logger))
+				defer cancelSweep()
+				go uc.RunSweeper(sweepCtx, sweepInterval)
+				logger.Info("started IoC expiry sweeper", "interval", sweepInterval)
+			}
+
+			// Fetches requested through the API run as background jobs,
+			// stopped when the server shuts down
+			fetchJobs := usecase.NewFetchJobUseCase(fetchUC, usecase.WithJobConcurrency(jobConcurrency))
+			defer fetchJobs.Shutdown()
+			resolverOpts = append(resolverOpts, graphql.WithFetchJobs(fetchJobs))
+
+			// Initialize GraphQL resolver
+			gqlResolver := graphql.NewResolver(repo, uc, fetchUC, resolverOpts...)
+
 			// Create HTTP server
-			handler := httpctrl.New(gqlResolver, httpctrl.WithGraphiQL(enableGraphiQL))
+			httpOpts := []httpctrl.Options{
+				httpctrl.WithGraphiQL(enableGraphiQL),
+				httpctrl.WithFetchInterval(fetchInterval),
+			}
+			if prom != nil {
+				httpOpts = append(httpOpts, httpctrl.WithMetrics(prom))
+			}
+			if cfg != nil && cfg.Auth.Enabled() {
+				// API tokens minted through GraphQL are stored in the repository
+				authn, err := cfg.Auth.New(auth.NewAPITokens(repo))
+				if err != nil {
+					return goerr.Wrap(err, "failed to create authenticator")
+				}
+				httpOpts = append(httpOpts, httpctrl.WithAuthenticator(authn))
+				logger.Info("enabled authentication",
+					"jwt", cfg.Auth.JWT != nil,
+					"header", cfg.Auth.Header != nil,
+					"tokens", len(cfg.Auth.Tokens),
+					"anonymous_role", cfg.Auth.AnonymousRole)
+			} else {
+				logger.Warn("authentication is not configured, every request is served as admin and API tokens cannot be minted; configure [auth] in the config file")
+			}
+			handler := httpctrl.New(gqlResolver, httpOpts...)
 			server := &http.Server{
 				Addr:              addr,
 				Handler:           handler,
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/cli/config"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+	"github.com/urfave/cli/v3"
+)
+
+func cmdSource() *cli.Command {
+	return &cli.Command{
+		Name:  "source",
+		Usage: "Manage stored source definitions",
+		Commands: []*cli.Command{
+			cmdSourceImport(),
+		},
+	}
+}
+
+func cmdSourceImport() *cli.Command {
+	var (
+		firestoreCfg config.Firestore
+		configPath   string
+		overwrite    bool
+	)
+
+	return &cli.Command{
+		Name:  "import",
+		Usage: "Import the sources of the configuration file into the repository",
+		Flags: append(firestoreCfg.Flags(),
+			&cli.StringFlag{
+				Name:        "config",
+				Aliases:     []string{"c"},
+				Usage:       "Path to configuration file",
+				Value:       "config/config.toml",
+				Destination: &configPath,
+				Sources:     cli.EnvVars("BEEHIVE_CONFIG"),
+			},
+			&cli.BoolFlag{
+				Name:        "overwrite",
+				Usage:       "Also replace stored sources changed through the API that differ from the configuration file",
+				Destination: &overwrite,
+			},
+		),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			logger := logging.Default()
+
+			if firestoreCfg.ProjectID == "" {
+				return goerr.New("firestore-project-id is required")
+			}
+
+			cfgPath, err := findConfigFile(configPath)
+			if err != nil {
+				return goerr.Wrap(err, "failed to find config file",
+					goerr.V("config_path", configPath))
+			}
+			cfg, err := config.LoadConfig(cfgPath)
+			if err != nil {
+				return goerr.Wrap(err, "failed to load config", goerr.V("path", cfgPath))
+			}
+
+			opts := []firestoreRepo.Option{}
+			if firestoreCfg.DatabaseID != "" {
+				opts = append(opts, firestoreRepo.WithDatabaseID(firestoreCfg.DatabaseID))
+			}
+			repo, err := firestoreRepo.New(ctx, firestoreCfg.ProjectID, opts...)
+			if err != nil {
+				return goerr.Wrap(err, "failed to create Firestore repository",
+					goerr.V("project_id", firestoreCfg.ProjectID),
+					goerr.V("database_id", firestoreCfg.DatabaseID))
+			}
+			defer func() {
+				if err := repo.Close(); err != nil {
+					logger.Error("failed to close Firestore client", "error", err)
+				}
+			}()
+
+			_, err = importSources(logging.With(ctx, logger), logger, usecase.New(repo), cfg, overwrite)
+			return err
+		},
+	}
+}
+
+// importSources stores the sources of cfg in the repository and returns all
+// stored sources. Without overwrite, sources changed through the API are kept
+// and their differences from the config are logged.
+func importSources(ctx context.Context, logger *slog.Logger, uc *usecase.UseCases, cfg *config.Config, overwrite bool) (map[string]model.Source, error) {
+	result, err := uc.ImportSources(ctx, cfg.Sources(), overwrite)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to import sources")
+	}
+	logImportResult(logger, result)
+
+	sources, err := uc.SourcesMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+func logImportResult(logger *slog.Logger, result *usecase.ImportSourcesResult) {
+	logger.Info("imported sources from config",
+		"created", result.Created,
+		"updated", result.Updated,
+		"unchanged", len(result.Unchanged))
+	for _, id := range result.Skipped {
+		logger.Warn("stored source was changed through the API and differs from config, keeping it; use 'source import --overwrite' to replace it",
+			"source", id,
+			"fields", result.Drift[id])
+	}
+}
+
+// loadConfigSources loads the sources of a config file for
+// usecase.SourceConfigReloader
+func loadConfigSources(path string) (map[string]model.Source, error) {
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Sources(), nil
+}
+
+// notifyHangup returns a channel receiving a value on each SIGHUP until ctx is done
+func notifyHangup(ctx context.Context) <-chan struct{} {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+
+	hup := make(chan struct{}, 1)
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigCh:
+				select {
+				case hup <- struct{}{}:
+				default: // A reload is already pending
+				}
+			}
+		}
+	}()
+	return hup
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/cli/config"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+	"github.com/urfave/cli/v3"
+)
+
+func cmdSweep() *cli.Command {
+	var (
+		firestoreCfg config.Firestore
+		configPath   string
+	)
+
+	return &cli.Command{
+		Name:  "sweep",
+		Usage: "Mark IoCs past their TTL as expired and rescore confidence",
+		Flags: append(firestoreCfg.Flags(),
+			&cli.StringFlag{
+				Name:        "config",
+				Aliases:     []string{"c"},
+				Usage:       "Path to configuration file (for source reliability)",
+				Value:       "config/config.toml",
+				Destination: &configPath,
+				Sources:     cli.EnvVars("BEEHIVE_CONFIG"),
+			},
+		),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			logger := logging.Default()
+
+			if firestoreCfg.ProjectID == "" {
+				return goerr.New("firestore-project-id is required")
+			}
+
+			confidencePolicy := model.NewConfidencePolicy(nil)
+			if cfgPath, err := findConfigFile(configPath); err != nil {
+				logger.Warn("config file not found, using default confidence policy", "config_path", configPath)
+			} else {
+				cfg, err := config.LoadConfig(cfgPath)
+				if err != nil {
+					return goerr.Wrap(err, "failed to load config", goerr.V("path", cfgPath))
+				}
+				confidencePolicy = cfg.ConfidencePolicy()
+			}
+
+			opts := []firestoreRepo.Option{}
+			if firestoreCfg.DatabaseID != "" {
+				opts = append(opts, firestoreRepo.WithDatabaseID(firestoreCfg.DatabaseID))
+			}
+
+			repo, err := firestoreRepo.New(ctx, firestoreCfg.ProjectID, opts...)
+			if err != nil {
+				return goerr.Wrap(err, "failed to create Firestore repository",
+					goerr.V("project_id", firestoreCfg.ProjectID),
+					goerr.V("database_id", firestoreCfg.DatabaseID))
+			}
+			defer func() {
+				if err := repo.Close(); err != nil {
+					logger.Error("failed to close Firestore client", "error", err)
+				}
+			}()
+
+			ctx = logging.With(ctx, logger)
+			uc := usecase.New(repo, usecase.WithConfidencePolicy(confidencePolicy))
+			now := time.Now()
+
+			expired, err := uc.SweepExpiredIoCs(ctx, now)
+			if err != nil {
+				return goerr.Wrap(err, "failed to sweep expired IoCs")
+			}
+
+			rescored, err := uc.RescoreIoCs(ctx, now)
+			if err != nil {
+				return goerr.Wrap(err, "failed to rescore IoCs")
+			}
+
+			logger.Info("sweep completed", "expired", expired, "rescored", rescored)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,122 @@
+package graphql_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	gqlcontroller "github.com/secmon-lab/beehive/pkg/controller/graphql"
+	httpcontroller "github.com/secmon-lab/beehive/pkg/controller/http"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/service/auth"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+func TestGraphQL_AuditLog(t *testing.T) {
+	jwksURL, sign := newTokenSigner(t)
+	repo := memory.New()
+	uc := usecase.New(repo)
+	resolver := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil))
+	server := httpcontroller.New(resolver, httpcontroller.WithAuthenticator(auth.NewJWT(jwksURL)))
+
+	analyst := sign("alice", model.RoleAnalyst)
+	viewer := sign("victor", model.RoleViewer)
+
+	// execute runs a query with a request ID set by the client
+	execute := func(token, requestID, query string) *graphQLResponse {
+		t.Helper()
+		body, err := json.Marshal(map[string]any{"query": query})
+		gt.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-Request-Id", requestID)
+
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		gt.N(t, w.Code).Equal(http.StatusOK)
+		var resp graphQLResponse
+		gt.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return &resp
+	}
+
+	resp := execute(analyst, "req-create", `mutation { createIoC(input: {type: "domain", value: "evil.example"}) { id } }`)
+	gt.N(t, len(resp.Errors)).Equal(0)
+	var created struct {
+		CreateIoC struct {
+			ID string `json:"id"`
+		} `json:"createIoC"`
+	}
+	gt.NoError(t, json.Unmarshal(resp.Data, &created))
+
+	resp = execute(analyst, "req-update",
+		`mutation { updateIoC(id: "`+created.CreateIoC.ID+`", input: {status: "false_positive"}) { id } }`)
+	gt.N(t, len(resp.Errors)).Equal(0)
+
+	type auditRecords struct {
+		ListAuditRecords []struct {
+			Actor      string  `json:"actor"`
+			Action     string  `json:"action"`
+			TargetType string  `json:"targetType"`
+			TargetID   string  `json:"targetID"`
+			RequestID  *string `json:"requestID"`
+			Changes    []struct {
+				Field  string  `json:"field"`
+				Before *string `json:"before"`
+				After  *string `json:"after"`
+			} `json:"changes"`
+		} `json:"listAuditRecords"`
+	}
+	query := `{ listAuditRecords(filter: {targetType: "ioc", %s}) {
+		actor action targetType targetID requestID changes { field before after } } }`
+
+	t.Run("filter by request ID", func(t *testing.T) {
+		resp := execute(analyst, "req-list", fmt.Sprintf(query, `requestID: "req-update"`))
+		gt.N(t, len(resp.Errors)).Equal(0)
+		var data auditRecords
+		gt.NoError(t, json.Unmarshal(resp.Data, &data))
+		gt.A(t, data.ListAuditRecords).Length(1)
+
+		record := data.ListAuditRecords[0]
+		gt.S(t, record.Actor).Equal("user:alice")
+		gt.S(t, record.Action).Equal("ioc.update")
+		gt.S(t, record.TargetID).Equal(created.CreateIoC.ID)
+		gt.V(t, record.RequestID).NotNil()
+		gt.S(t, *record.RequestID).Equal("req-update")
+
+		var found bool
+		for _, c := range record.Changes {
+			if c.Field == "Status" {
+				found = true
+				gt.S(t, *c.Before).Equal(`"active"`)
+				gt.S(t, *c.After).Equal(`"false_positive"`)
+			}
+		}
+		gt.True(t, found)
+	})
+
+	t.Run("filter by action and limit", func(t *testing.T) {
+		resp := execute(analyst, "req-list", fmt.Sprintf(query, `action: "ioc.create"`))
+		gt.N(t, len(resp.Errors)).Equal(0)
+		var data auditRecords
+		gt.NoError(t, json.Unmarshal(resp.Data, &data))
+		gt.A(t, data.ListAuditRecords).Length(1)
+		gt.V(t, data.ListAuditRecords[0].Changes[0].Before).Nil()
+
+		resp = execute(analyst, "req-list", fmt.Sprintf(query, `limit: 1`))
+		gt.N(t, len(resp.Errors)).Equal(0)
+		gt.NoError(t, json.Unmarshal(resp.Data, &data))
+		gt.A(t, data.ListAuditRecords).Length(1)
+		gt.S(t, data.ListAuditRecords[0].Action).Equal("ioc.update")
+	})
+
+	t.Run("viewers cannot read the audit log", func(t *testing.T) {
+		resp := execute(viewer, "req-list", fmt.Sprintf(query, `limit: 1`))
+		gt.True(t, isForbidden(resp))
+	})
+}
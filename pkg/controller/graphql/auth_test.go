@@ -0,0 +1,408 @@
+package graphql_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/m-mizutani/gt"
+	gqlcontroller "github.com/secmon-lab/beehive/pkg/controller/graphql"
+	httpcontroller "github.com/secmon-lab/beehive/pkg/controller/http"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/service/auth"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+// newTokenSigner returns a JWKS URL and a function signing tokens of a user
+// with a role using a local key
+func newTokenSigner(t *testing.T) (string, func(user string, role model.Role) string) {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	gt.NoError(t, err)
+	key := jose.JSONWebKey{Key: priv, KeyID: "test", Algorithm: string(jose.RS256)}
+
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{key.Public()}})
+	}))
+	t.Cleanup(jwks.Close)
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, nil)
+	gt.NoError(t, err)
+	return jwks.URL, func(user string, role model.Role) string {
+		token, err := jwt.Signed(signer).Claims(map[string]any{
+			"sub":  user,
+			"role": string(role),
+			"exp":  time.Now().Add(time.Hour).Unix(),
+		}).Serialize()
+		gt.NoError(t, err)
+		return token
+	}
+}
+
+// executeGraphQLWithToken executes a GraphQL query with a bearer token and
+// returns the HTTP status
+func executeGraphQLWithToken(t *testing.T, server *httpcontroller.Server, token, query string, variables map[string]any) (*graphQLResponse, int) {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	gt.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		return nil, w.Code
+	}
+
+	var resp graphQLResponse
+	gt.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return &resp, w.Code
+}
+
+func isForbidden(resp *graphQLResponse) bool {
+	for _, e := range resp.Errors {
+		if strings.Contains(string(e), `"FORBIDDEN"`) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGraphQL_Authorization(t *testing.T) {
+	jwksURL, sign := newTokenSigner(t)
+	tokens, err := auth.NewStaticTokens([]auth.StaticToken{
+		{Name: "ci", Hash: auth.HashToken("ci-token"), Role: model.RoleAnalyst},
+	})
+	gt.NoError(t, err)
+
+	repo := memory.New()
+	uc := usecase.New(repo)
+	resolver := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil),
+		gqlcontroller.WithConfigStatus(func() *model.ConfigStatus {
+			return &model.ConfigStatus{Path: "config.toml", CheckedAt: time.Now()}
+		}))
+	server := httpcontroller.New(resolver,
+		httpcontroller.WithAuthenticator(auth.NewChain("", tokens, auth.NewJWT(jwksURL))))
+
+	viewer := sign("victor", model.RoleViewer)
+	analyst := sign("alice", model.RoleAnalyst)
+	admin := sign("root", model.RoleAdmin)
+
+	t.Run("requests without valid credentials are rejected", func(t *testing.T) {
+		_, status := executeGraphQLWithToken(t, server, "", `{ health }`, nil)
+		gt.N(t, status).Equal(http.StatusUnauthorized)
+		_, status = executeGraphQLWithToken(t, server, "wrong-token", `{ health }`, nil)
+		gt.N(t, status).Equal(http.StatusUnauthorized)
+	})
+
+	t.Run("me", func(t *testing.T) {
+		resp, status := executeGraphQLWithToken(t, server, viewer, `{ me { subject role method } }`, nil)
+		gt.N(t, status).Equal(http.StatusOK)
+		gt.N(t, len(resp.Errors)).Equal(0)
+		var data struct {
+			Me struct {
+				Subject string `json:"subject"`
+				Role    string `json:"role"`
+				Method  string `json:"method"`
+			} `json:"me"`
+		}
+		gt.NoError(t, json.Unmarshal(resp.Data, &data))
+		gt.S(t, data.Me.Subject).Equal("victor")
+		gt.S(t, data.Me.Role).Equal("VIEWER")
+		gt.S(t, data.Me.Method).Equal("jwt")
+	})
+
+	createIoC := `mutation { createIoC(input: {type: "domain", value: "evil.example", description: "C2"}) { id } }`
+	createSource := `mutation { createSource(input: {id: "blog", type: "rss", url: "https://blog.example/feed"}) { id } }`
+
+	t.Run("viewer", func(t *testing.T) {
+		resp, _ := executeGraphQLWithToken(t, server, viewer, `{ listSources { id } listIoCs { total } }`, nil)
+		gt.N(t, len(resp.Errors)).Equal(0)
+
+		for _, query := range []string{
+			createIoC,
+			createSource,
+			`mutation { fetchSource(sourceID: "blog") { id } }`,
+			`{ configStatus { path } }`,
+			`{ listSourceChanges { id } }`,
+		} {
+			resp, _ := executeGraphQLWithToken(t, server, viewer, query, nil)
+			gt.True(t, isForbidden(resp)).Describef("viewer should not run %s", query)
+		}
+	})
+
+	t.Run("analyst", func(t *testing.T) {
+		resp, _ := executeGraphQLWithToken(t, server, analyst, createIoC, nil)
+		gt.N(t, len(resp.Errors)).Equal(0)
+		var data struct {
+			CreateIoC struct {
+				ID string `json:"id"`
+			} `json:"createIoC"`
+		}
+		gt.NoError(t, json.Unmarshal(resp.Data, &data))
+
+		// Changes are recorded with the authenticated user
+		transitions, err := repo.ListStatusTransitions(context.Background(), data.CreateIoC.ID)
+		gt.NoError(t, err)
+		gt.A(t, transitions).Length(1)
+		gt.S(t, transitions[0].Actor).Equal("user:alice")
+
+		resp, _ = executeGraphQLWithToken(t, server, analyst, createSource, nil)
+		gt.True(t, isForbidden(resp))
+		resp, _ = executeGraphQLWithToken(t, server, analyst, `{ configStatus { path } }`, nil)
+		gt.True(t, isForbidden(resp))
+	})
+
+	t.Run("static token", func(t *testing.T) {
+		resp, status := executeGraphQLWithToken(t, server, "ci-token", `{ listSourceChanges { id } }`, nil)
+		gt.N(t, status).Equal(http.StatusOK)
+		gt.N(t, len(resp.Errors)).Equal(0)
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		resp, _ := executeGraphQLWithToken(t, server, admin, createSource, nil)
+		gt.N(t, len(resp.Errors)).Equal(0)
+		resp, _ = executeGraphQLWithToken(t, server, admin, `{ configStatus { path } }`, nil)
+		gt.N(t, len(resp.Errors)).Equal(0)
+
+		changes, err := uc.ListSourceChanges(context.Background(), "blog", 0)
+		gt.NoError(t, err)
+		gt.A(t, changes).Length(1)
+		gt.S(t, changes[0].Actor).Equal("user:root")
+	})
+
+	t.Run("websocket credentials in connection_init", func(t *testing.T) {
+		c := client.New(server, client.Path("/graphql"))
+		query := `subscription { jobProgress(id: "missing") { id } }`
+		var resp struct{}
+
+		sub := c.WebsocketWithPayload(query, nil)
+		err := sub.Next(&resp)
+		_ = sub.Close()
+		gt.Error(t, err)
+
+		sub = c.WebsocketWithPayload(query, map[string]any{"Authorization": "Bearer " + viewer})
+		err = sub.Next(&resp)
+		_ = sub.Close()
+		gt.Error(t, err)
+		gt.S(t, err.Error()).Contains("FORBIDDEN")
+
+		sub = c.WebsocketWithPayload(query, map[string]any{"Authorization": "Bearer " + analyst})
+		err = sub.Next(&resp)
+		_ = sub.Close()
+		gt.Error(t, err)
+		gt.S(t, err.Error()).Contains("job not found")
+	})
+
+}
+
+func TestGraphQL_APITokens(t *testing.T) {
+	ctx := context.Background()
+	jwksURL, sign := newTokenSigner(t)
+
+	repo := memory.New()
+	uc := usecase.New(repo)
+	resolver := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil))
+	server := httpcontroller.New(resolver,
+		httpcontroller.WithAuthenticator(auth.NewChain("", auth.NewAPITokens(repo), auth.NewJWT(jwksURL))))
+
+	admin := sign("root", model.RoleAdmin)
+	analyst := sign("alice", model.RoleAnalyst)
+
+	source := &model.Source{ID: "blog", Type: model.SourceTypeRSS, URL: "https://blog.example/feed",
+		Tags: []string{"blocklist"}, Enabled: true, RSSConfig: &model.RSSConfig{}}
+	gt.NoError(t, repo.PutSource(ctx, source))
+	for _, value := range []string{"evil.example", "c2.example"} {
+		gt.NoError(t, repo.PutIoC(ctx, &model.IoC{
+			ID:       model.GenerateID("blog", model.IoCTypeDomain, value, ""),
+			SourceID: "blog",
+			Type:     model.IoCTypeDomain,
+			Value:    value,
+			Status:   model.IoCStatusActive,
+		}))
+	}
+
+	mint := func(t *testing.T, name string, scopes ...string) (string, string) {
+		t.Helper()
+		resp, _ := executeGraphQLWithToken(t, server, admin,
+			`mutation($input: CreateAPITokenInput!) { createAPIToken(input: $input) { token apiToken { id name scopes createdBy active } } }`,
+			map[string]any{"input": map[string]any{"name": name, "scopes": scopes}})
+		gt.N(t, len(resp.Errors)).Equal(0)
+		var data struct {
+			CreateAPIToken struct {
+				Token    string `json:"token"`
+				APIToken struct {
+					ID        string   `json:"id"`
+					Name      string   `json:"name"`
+					Scopes    []string `json:"scopes"`
+					CreatedBy string   `json:"createdBy"`
+					Active    bool     `json:"active"`
+				} `json:"apiToken"`
+			} `json:"createAPIToken"`
+		}
+		gt.NoError(t, json.Unmarshal(resp.Data, &data))
+		gt.Equal(t, data.CreateAPIToken.APIToken.Scopes, scopes)
+		gt.S(t, data.CreateAPIToken.APIToken.CreatedBy).Equal("user:root")
+		gt.True(t, data.CreateAPIToken.APIToken.Active)
+		return data.CreateAPIToken.APIToken.ID, data.CreateAPIToken.Token
+	}
+
+	export := func(t *testing.T, token, path string) (int, string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w.Code, w.Body.String()
+	}
+
+	t.Run("only admins manage tokens", func(t *testing.T) {
+		resp, _ := executeGraphQLWithToken(t, server, analyst,
+			`mutation { createAPIToken(input: {name: "x", scopes: ["read:iocs"]}) { token } }`, nil)
+		gt.True(t, isForbidden(resp))
+		resp, _ = executeGraphQLWithToken(t, server, analyst, `{ listAPITokens { id } }`, nil)
+		gt.True(t, isForbidden(resp))
+
+		resp, _ = executeGraphQLWithToken(t, server, admin,
+			`mutation { createAPIToken(input: {name: "x", scopes: ["write:iocs"]}) { token } }`, nil)
+		gt.N(t, len(resp.Errors)).Equal(1)
+	})
+
+	t.Run("read:iocs", func(t *testing.T) {
+		_, token := mint(t, "soar", "read:iocs")
+
+		resp, status := executeGraphQLWithToken(t, server, token, `{ me { subject role method scopes } listIoCs { total } }`, nil)
+		gt.N(t, status).Equal(http.StatusOK)
+		gt.N(t, len(resp.Errors)).Equal(0)
+		var data struct {
+			Me struct {
+				Subject string   `json:"subject"`
+				Role    *string  `json:"role"`
+				Method  string   `json:"method"`
+				Scopes  []string `json:"scopes"`
+			} `json:"me"`
+			ListIoCs struct {
+				Total int `json:"total"`
+			} `json:"listIoCs"`
+		}
+		gt.NoError(t, json.Unmarshal(resp.Data, &data))
+		gt.S(t, data.Me.Subject).Equal("soar")
+		gt.V(t, data.Me.Role).Nil()
+		gt.S(t, data.Me.Method).Equal("api_token")
+		gt.Equal(t, data.Me.Scopes, []string{"read:iocs"})
+		gt.N(t, data.ListIoCs.Total).Equal(2)
+
+		for _, query := range []string{
+			`{ listSources { id } }`,
+			`{ listWatchlists { id } }`,
+			`mutation { createIoC(input: {type: "domain", value: "x.example"}) { id } }`,
+			`mutation { fetchSource(sourceID: "blog") { id } }`,
+			`{ listAPITokens { id } }`,
+		} {
+			resp, _ := executeGraphQLWithToken(t, server, token, query, nil)
+			gt.True(t, isForbidden(resp)).Describef("read:iocs token should not run %s", query)
+		}
+
+		// read:iocs exports any tag
+		status, _ = export(t, token, "/api/export/blocklist")
+		gt.N(t, status).Equal(http.StatusOK)
+	})
+
+	t.Run("fetch:trigger", func(t *testing.T) {
+		_, token := mint(t, "ci", "fetch:trigger")
+		resp, _ := executeGraphQLWithToken(t, server, token, `{ job(id: "missing") { id } }`, nil)
+		gt.N(t, len(resp.Errors)).Equal(0)
+		resp, _ = executeGraphQLWithToken(t, server, token, `{ listIoCs { total } }`, nil)
+		gt.True(t, isForbidden(resp))
+		status, _ := export(t, token, "/api/export/blocklist")
+		gt.N(t, status).Equal(http.StatusForbidden)
+	})
+
+	t.Run("export:<tag>", func(t *testing.T) {
+		id, token := mint(t, "firewall", "export:blocklist")
+
+		status, body := export(t, token, "/api/export/blocklist")
+		gt.N(t, status).Equal(http.StatusOK)
+		gt.S(t, body).Equal("c2.example\nevil.example\n")
+
+		status, body = export(t, token, "/api/export/blocklist?format=json&type=domain")
+		gt.N(t, status).Equal(http.StatusOK)
+		var exported []struct {
+			Type     string `json:"type"`
+			Value    string `json:"value"`
+			SourceID string `json:"source_id"`
+		}
+		gt.NoError(t, json.Unmarshal([]byte(body), &exported))
+		gt.A(t, exported).Length(2)
+		gt.S(t, exported[0].SourceID).Equal("blog")
+
+		status, _ = export(t, token, "/api/export/blocklist?type=ipv4")
+		gt.N(t, status).Equal(http.StatusOK)
+		status, _ = export(t, token, "/api/export/blocklist?type=unknown")
+		gt.N(t, status).Equal(http.StatusBadRequest)
+		status, _ = export(t, token, "/api/export/blocklist?min_confidence=101")
+		gt.N(t, status).Equal(http.StatusBadRequest)
+		status, body = export(t, token, "/api/export/blocklist?min_confidence=100")
+		gt.N(t, status).Equal(http.StatusOK)
+		gt.S(t, body).Equal("")
+		status, _ = export(t, token, "/api/export/internal")
+		gt.N(t, status).Equal(http.StatusForbidden)
+		resp, _ := executeGraphQLWithToken(t, server, token, `{ listIoCs { total } }`, nil)
+		gt.True(t, isForbidden(resp))
+
+		// The use of the token is recorded
+		stored, err := repo.GetAPIToken(ctx, id)
+		gt.NoError(t, err)
+		gt.False(t, stored.LastUsedAt.IsZero())
+
+		// Revoked tokens are rejected
+		resp, _ = executeGraphQLWithToken(t, server, admin,
+			`mutation($id: ID!) { revokeAPIToken(id: $id) { active revokedAt } }`, map[string]any{"id": id})
+		gt.N(t, len(resp.Errors)).Equal(0)
+		status, _ = export(t, token, "/api/export/blocklist")
+		gt.N(t, status).Equal(http.StatusUnauthorized)
+	})
+
+	t.Run("export requires authentication", func(t *testing.T) {
+		status, _ := export(t, "", "/api/export/blocklist")
+		gt.N(t, status).Equal(http.StatusUnauthorized)
+		status, _ = export(t, analyst, "/api/export/blocklist")
+		gt.N(t, status).Equal(http.StatusOK)
+	})
+
+	t.Run("list tokens", func(t *testing.T) {
+		resp, _ := executeGraphQLWithToken(t, server, admin, `{ listAPITokens { name active lastUsedAt revokedAt } }`, nil)
+		gt.N(t, len(resp.Errors)).Equal(0)
+		var data struct {
+			ListAPITokens []struct {
+				Name       string     `json:"name"`
+				Active     bool       `json:"active"`
+				LastUsedAt *time.Time `json:"lastUsedAt"`
+				RevokedAt  *time.Time `json:"revokedAt"`
+			} `json:"listAPITokens"`
+		}
+		gt.NoError(t, json.Unmarshal(resp.Data, &data))
+		gt.A(t, data.ListAPITokens).Length(3)
+		gt.S(t, data.ListAPITokens[0].Name).Equal("firewall")
+		gt.False(t, data.ListAPITokens[0].Active)
+		gt.V(t, data.ListAPITokens[0].LastUsedAt).NotNil()
+		gt.V(t, data.ListAPITokens[0].RevokedAt).NotNil()
+	})
+}
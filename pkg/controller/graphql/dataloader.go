@@ -6,6 +6,8 @@ import (
 	"github.com/graph-gophers/dataloader/v7"
 	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
 	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type contextKey string
@@ -32,12 +34,16 @@ func NewLoaders(repo interfaces.Repository) *Loaders {
 
 // batchGetSourceStates fetches multiple source states in a single batch
 func batchGetSourceStates(ctx context.Context, repo interfaces.Repository, sourceIDs []string) []*dataloader.Result[*model.SourceState] {
+	ctx, span := tracing.Start(ctx, "dataloader.SourceState", attribute.Int("batch.size", len(sourceIDs)))
+	defer span.End()
+
 	// Create result slice with same length as input
 	results := make([]*dataloader.Result[*model.SourceState], len(sourceIDs))
 
 	// Batch fetch all states in a single repository call
 	statesMap, err := repo.BatchGetStates(ctx, sourceIDs)
 	if err != nil {
+		span.RecordError(err)
 		// If batch operation fails, return error for all items
 		for i := range results {
 			results[i] = &dataloader.Result[*model.SourceState]{Error: err}
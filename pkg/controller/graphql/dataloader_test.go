@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/cli/config"
 	gqlcontroller "github.com/secmon-lab/beehive/pkg/controller/graphql"
 	httpcontroller "github.com/secmon-lab/beehive/pkg/controller/http"
 	"github.com/secmon-lab/beehive/pkg/domain/model"
@@ -121,8 +122,11 @@ tags = ["test"]
 
 	uc := usecase.New(countingRepo)
 	fetchUC := usecase.NewFetchUseCase(countingRepo, nil)
-	resolver, err := gqlcontroller.NewResolver(countingRepo, uc, fetchUC, configPath)
+	cfg, err := config.LoadConfig(configPath)
 	gt.NoError(t, err)
+	_, err = uc.ImportSources(ctx, cfg.Sources(), false)
+	gt.NoError(t, err)
+	resolver := gqlcontroller.NewResolver(countingRepo, uc, fetchUC)
 	server := httpcontroller.New(resolver)
 
 	query := `
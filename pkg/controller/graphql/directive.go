@@ -0,0 +1,99 @@
+package graphql
+
+import (
+	"context"
+	"slices"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	graphql1 "github.com/secmon-lab/beehive/pkg/domain/model/graphql"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// errForbidden returns the error of fields the principal of a request has no
+// role for. A new error is created each time because gqlgen sets its path.
+func errForbidden() *gqlerror.Error {
+	return &gqlerror.Error{
+		Message:    "forbidden",
+		Extensions: map[string]any{"code": "FORBIDDEN"},
+	}
+}
+
+// NewConfig creates the schema configuration of the resolver, enforcing the
+// @hasRole directive with the principal set by the HTTP server
+func NewConfig(r *Resolver) Config {
+	return Config{
+		Resolvers: r,
+		Directives: DirectiveRoot{
+			HasRole: hasRole,
+		},
+	}
+}
+
+var graphQLRoles = map[graphql1.Role]model.Role{
+	graphql1.RoleViewer:  model.RoleViewer,
+	graphql1.RoleAnalyst: model.RoleAnalyst,
+	graphql1.RoleAdmin:   model.RoleAdmin,
+}
+
+func hasRole(ctx context.Context, obj any, next graphql.Resolver, role graphql1.Role) (any, error) {
+	p := model.PrincipalFrom(ctx)
+	if p != nil && p.Scoped() {
+		// API tokens have no role; AuthorizeScopes has allowed the field
+		return next(ctx)
+	}
+	if p == nil || !p.Role.Includes(graphQLRoles[role]) {
+		var subject string
+		if p != nil {
+			subject = p.Subject
+		}
+		logging.From(ctx).Info("access denied",
+			"field", graphql.GetFieldContext(ctx).Field.Name,
+			"subject", subject,
+			"required_role", role)
+		return nil, errForbidden()
+	}
+	return next(ctx)
+}
+
+// scopeRootFields are the root fields allowed by each scope of API tokens
+var scopeRootFields = map[model.TokenScope][]string{
+	model.ScopeReadIoCs:     {"Query.listIoCs", "Query.getIoC"},
+	model.ScopeFetchTrigger: {"Mutation.fetchSource", "Mutation.cancelJob", "Query.job", "Subscription.jobProgress"},
+}
+
+// publicRootFields are allowed to every API token
+var publicRootFields = []string{"Query.health", "Query.me", "Query.__schema", "Query.__type"}
+
+// AuthorizeScopes is a field middleware restricting API tokens, which have no
+// role, to the root fields allowed by their scopes. Principals with a role are
+// left to @hasRole.
+func AuthorizeScopes(ctx context.Context, next graphql.Resolver) (any, error) {
+	p := model.PrincipalFrom(ctx)
+	fc := graphql.GetFieldContext(ctx)
+	if p == nil || !p.Scoped() || fc == nil {
+		return next(ctx)
+	}
+	switch fc.Object {
+	case "Query", "Mutation", "Subscription":
+	default:
+		return next(ctx)
+	}
+
+	field := fc.Object + "." + fc.Field.Name
+	if slices.Contains(publicRootFields, field) {
+		return next(ctx)
+	}
+	for _, scope := range p.Scopes {
+		if slices.Contains(scopeRootFields[scope], field) {
+			return next(ctx)
+		}
+	}
+
+	logging.From(ctx).Info("access denied",
+		"field", field,
+		"subject", p.Subject,
+		"scopes", p.Scopes)
+	return nil, errForbidden()
+}
@@ -4,14 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/99designs/gqlgen/client"
 	"github.com/m-mizutani/gt"
 	gqlcontroller "github.com/secmon-lab/beehive/pkg/controller/graphql"
 	httpcontroller "github.com/secmon-lab/beehive/pkg/controller/http"
+	"github.com/secmon-lab/beehive/pkg/domain/brand"
 	"github.com/secmon-lab/beehive/pkg/domain/model"
 	"github.com/secmon-lab/beehive/pkg/repository/memory"
 	"github.com/secmon-lab/beehive/pkg/usecase"
@@ -55,8 +58,7 @@ func TestGraphQL_Health(t *testing.T) {
 	repo := memory.New()
 	uc := usecase.New(repo)
 	fetchUC := usecase.NewFetchUseCase(repo, nil)
-	resolver, err := gqlcontroller.NewResolver(repo, uc, fetchUC, "")
-	gt.NoError(t, err)
+	resolver := gqlcontroller.NewResolver(repo, uc, fetchUC)
 	server := httpcontroller.New(resolver)
 
 	query := `query { health }`
@@ -67,7 +69,7 @@ func TestGraphQL_Health(t *testing.T) {
 	var data struct {
 		Health string `json:"health"`
 	}
-	err = json.Unmarshal(resp.Data, &data)
+	err := json.Unmarshal(resp.Data, &data)
 	gt.NoError(t, err)
 	gt.S(t, data.Health).Equal("OK").Describe("health check should return OK")
 }
@@ -109,8 +111,7 @@ func TestGraphQL_ListIoCs(t *testing.T) {
 	}
 
 	uc := usecase.New(repo)
-	resolver, err := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil), "")
-	gt.NoError(t, err)
+	resolver := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil))
 	server := httpcontroller.New(resolver)
 
 	query := `
@@ -145,7 +146,7 @@ func TestGraphQL_ListIoCs(t *testing.T) {
 			} `json:"items"`
 		} `json:"listIoCs"`
 	}
-	err = json.Unmarshal(resp.Data, &data)
+	err := json.Unmarshal(resp.Data, &data)
 	gt.NoError(t, err)
 
 	gt.N(t, data.ListIoCs.Total).Equal(2).Describe("total should be 2")
@@ -185,6 +186,41 @@ func TestGraphQL_ListIoCs(t *testing.T) {
 	}) bool {
 		return item.SourceID == "source-1" && item.Status == "active"
 	}).Describe("all IoCs should have correct sourceID and status")
+
+	t.Run("filter by type", func(t *testing.T) {
+		gt.NoError(t, repo.UpsertIoC(ctx, &model.IoC{
+			ID:         "ioc-003",
+			SourceID:   "source-1",
+			SourceType: "rss",
+			Type:       model.IoCTypeRegKey,
+			Value:      `HKEY_CURRENT_USER\Software\Microsoft\Windows\CurrentVersion\Run\SvcHelper`,
+			Status:     model.IoCStatusActive,
+		}))
+
+		resp := executeGraphQL(t, server, `
+			query($types: [IoCType!]) {
+				listIoCs(options: {types: $types}) {
+					total
+					items { type value }
+				}
+			}
+		`, map[string]interface{}{"types": []string{"REGISTRY_KEY", "IPV4"}})
+		gt.A(t, resp.Errors).Length(0)
+
+		var data struct {
+			ListIoCs struct {
+				Total int `json:"total"`
+				Items []struct {
+					Type string `json:"type"`
+				} `json:"items"`
+			} `json:"listIoCs"`
+		}
+		gt.NoError(t, json.Unmarshal(resp.Data, &data))
+		gt.Equal(t, data.ListIoCs.Total, 2)
+		for _, item := range data.ListIoCs.Items {
+			gt.True(t, item.Type == "registry-key" || item.Type == "ipv4")
+		}
+	})
 }
 
 func TestGraphQL_GetIoC(t *testing.T) {
@@ -208,8 +244,7 @@ func TestGraphQL_GetIoC(t *testing.T) {
 	gt.NoError(t, err)
 
 	uc := usecase.New(repo)
-	resolver, err := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil), "")
-	gt.NoError(t, err)
+	resolver := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil))
 	server := httpcontroller.New(resolver)
 
 	query := `
@@ -304,8 +339,7 @@ func TestGraphQL_ListHistories(t *testing.T) {
 	gt.NoError(t, err)
 
 	uc := usecase.New(repo)
-	resolver, err := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil), "")
-	gt.NoError(t, err)
+	resolver := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil))
 	server := httpcontroller.New(resolver)
 
 	query := `
@@ -429,8 +463,7 @@ func TestGraphQL_GetHistory(t *testing.T) {
 	gt.NoError(t, err)
 
 	uc := usecase.New(repo)
-	resolver, err := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil), "")
-	gt.NoError(t, err)
+	resolver := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil))
 	server := httpcontroller.New(resolver)
 
 	query := `
@@ -481,3 +514,795 @@ func TestGraphQL_GetHistory(t *testing.T) {
 	gt.N(t, data.GetHistory.IoCsUpdated).Equal(5).Describe("history IoCs updated")
 	gt.N(t, data.GetHistory.ErrorCount).Equal(0).Describe("history error count")
 }
+
+func TestGraphQL_IoCCuration(t *testing.T) {
+	repo := memory.New()
+	uc := usecase.New(repo)
+	resolver := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil))
+	server := httpcontroller.New(resolver)
+
+	createMutation := `
+		mutation($input: CreateIoCInput!) {
+			createIoC(input: $input) {
+				id
+				sourceID
+				sourceType
+				value
+				status
+				tags
+				notes
+			}
+		}
+	`
+
+	resp := executeGraphQL(t, server, createMutation, map[string]interface{}{
+		"input": map[string]interface{}{
+			"type":        "domain",
+			"value":       "phish.example.net",
+			"description": "Reported by user",
+			"notes":       "INC-42",
+			"tags":        []string{"incident"},
+		},
+	})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+
+	var created struct {
+		CreateIoC struct {
+			ID         string   `json:"id"`
+			SourceID   string   `json:"sourceID"`
+			SourceType string   `json:"sourceType"`
+			Value      string   `json:"value"`
+			Status     string   `json:"status"`
+			Tags       []string `json:"tags"`
+			Notes      string   `json:"notes"`
+		} `json:"createIoC"`
+	}
+	gt.NoError(t, json.Unmarshal(resp.Data, &created))
+	gt.S(t, created.CreateIoC.SourceID).Equal(model.ManualSourceID)
+	gt.S(t, created.CreateIoC.SourceType).Equal("manual")
+	gt.S(t, created.CreateIoC.Status).Equal("active")
+	gt.S(t, created.CreateIoC.Notes).Equal("INC-42")
+	gt.A(t, created.CreateIoC.Tags).Length(1)
+
+	updateMutation := `
+		mutation($id: ID!, $input: UpdateIoCInput!) {
+			updateIoC(id: $id, input: $input) {
+				id
+				status
+			}
+		}
+	`
+	resp = executeGraphQL(t, server, updateMutation, map[string]interface{}{
+		"id":    created.CreateIoC.ID,
+		"input": map[string]interface{}{"status": "false_positive"},
+	})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+
+	var updated struct {
+		UpdateIoC struct {
+			Status string `json:"status"`
+		} `json:"updateIoC"`
+	}
+	gt.NoError(t, json.Unmarshal(resp.Data, &updated))
+	gt.S(t, updated.UpdateIoC.Status).Equal("false_positive")
+
+	// Invalid status is rejected
+	resp = executeGraphQL(t, server, updateMutation, map[string]interface{}{
+		"id":    created.CreateIoC.ID,
+		"input": map[string]interface{}{"status": "bogus"},
+	})
+	gt.N(t, len(resp.Errors)).NotEqual(0).Describe("invalid status should be rejected")
+
+	deleteMutation := `mutation($id: ID!) { deleteIoC(id: $id) }`
+	resp = executeGraphQL(t, server, deleteMutation, map[string]interface{}{
+		"id": created.CreateIoC.ID,
+	})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+
+	_, err := repo.GetIoC(context.Background(), created.CreateIoC.ID)
+	gt.Error(t, err)
+}
+
+func TestGraphQL_IoCStatusHistory(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	uc := usecase.New(repo)
+	resolver := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil))
+	server := httpcontroller.New(resolver)
+
+	created, err := uc.CreateIoC(ctx, &usecase.CreateIoCInput{Type: model.IoCTypeIPv4, Value: "198.51.100.7"})
+	gt.NoError(t, err)
+
+	updateMutation := `
+		mutation($id: ID!, $input: UpdateIoCInput!) {
+			updateIoC(id: $id, input: $input) { id }
+		}
+	`
+	resp := executeGraphQL(t, server, updateMutation, map[string]interface{}{
+		"id":    created.ID,
+		"input": map[string]interface{}{"status": "revoked", "reason": "sinkholed"},
+	})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+
+	query := `
+		query($id: ID!) {
+			getIoC(id: $id) {
+				status
+				expiresAt
+				statusHistory {
+					from
+					to
+					actor
+					reason
+				}
+			}
+		}
+	`
+	resp = executeGraphQL(t, server, query, map[string]interface{}{"id": created.ID})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+
+	var result struct {
+		GetIoC struct {
+			Status        string  `json:"status"`
+			ExpiresAt     *string `json:"expiresAt"`
+			StatusHistory []struct {
+				From   string `json:"from"`
+				To     string `json:"to"`
+				Actor  string `json:"actor"`
+				Reason string `json:"reason"`
+			} `json:"statusHistory"`
+		} `json:"getIoC"`
+	}
+	gt.NoError(t, json.Unmarshal(resp.Data, &result))
+	gt.S(t, result.GetIoC.Status).Equal("revoked")
+	gt.V(t, result.GetIoC.ExpiresAt).Nil()
+	gt.A(t, result.GetIoC.StatusHistory).Length(2)
+	gt.S(t, result.GetIoC.StatusHistory[0].To).Equal("active")
+	gt.S(t, result.GetIoC.StatusHistory[1].From).Equal("active")
+	gt.S(t, result.GetIoC.StatusHistory[1].To).Equal("revoked")
+	gt.S(t, result.GetIoC.StatusHistory[1].Actor).Equal(model.ActorAnalyst)
+	gt.S(t, result.GetIoC.StatusHistory[1].Reason).Equal("sinkholed")
+}
+
+func TestGraphQL_Watchlists(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	uc := usecase.New(repo)
+	resolver := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil))
+	server := httpcontroller.New(resolver)
+
+	createMutation := `
+		mutation($input: CreateWatchlistInput!) {
+			createWatchlist(input: $input) {
+				id
+				name
+				enabled
+				patterns { kind value }
+			}
+		}
+	`
+	resp := executeGraphQL(t, server, createMutation, map[string]interface{}{
+		"input": map[string]interface{}{
+			"name": "brand",
+			"patterns": []map[string]interface{}{
+				{"kind": "domain", "value": "*.Acme.example"},
+			},
+		},
+	})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+
+	var created struct {
+		CreateWatchlist struct {
+			ID       string `json:"id"`
+			Name     string `json:"name"`
+			Enabled  bool   `json:"enabled"`
+			Patterns []struct {
+				Kind  string `json:"kind"`
+				Value string `json:"value"`
+			} `json:"patterns"`
+		} `json:"createWatchlist"`
+	}
+	gt.NoError(t, json.Unmarshal(resp.Data, &created))
+	gt.True(t, created.CreateWatchlist.Enabled)
+	gt.A(t, created.CreateWatchlist.Patterns).Length(1)
+	gt.S(t, created.CreateWatchlist.Patterns[0].Value).Equal("acme.example")
+
+	// Invalid patterns are rejected
+	resp = executeGraphQL(t, server, createMutation, map[string]interface{}{
+		"input": map[string]interface{}{
+			"name":     "broken",
+			"patterns": []map[string]interface{}{{"kind": "regex", "value": "(["}},
+		},
+	})
+	gt.N(t, len(resp.Errors)).NotEqual(0).Describe("invalid regex should be rejected")
+
+	ioc, err := uc.CreateIoC(ctx, &usecase.CreateIoCInput{Type: model.IoCTypeDomain, Value: "login.acme.example"})
+	gt.NoError(t, err)
+	watchlist, err := repo.GetWatchlist(ctx, created.CreateWatchlist.ID)
+	gt.NoError(t, err)
+	matcher, err := model.NewWatchlistMatcher([]*model.Watchlist{watchlist})
+	gt.NoError(t, err)
+	hits := matcher.Match(ioc, nil, time.Now())
+	gt.A(t, hits).Length(1)
+	_, err = repo.SaveWatchlistHits(ctx, hits)
+	gt.NoError(t, err)
+
+	listQuery := `
+		query($options: WatchlistHitListOptions) {
+			listWatchlistHits(options: $options) {
+				total
+				items {
+					id
+					watchlistName
+					iocValue
+					acknowledged
+					ioc { id value }
+				}
+			}
+		}
+	`
+	resp = executeGraphQL(t, server, listQuery, map[string]interface{}{
+		"options": map[string]interface{}{"watchlistID": created.CreateWatchlist.ID},
+	})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+
+	type hitConnection struct {
+		ListWatchlistHits struct {
+			Total int `json:"total"`
+			Items []struct {
+				ID            string `json:"id"`
+				WatchlistName string `json:"watchlistName"`
+				IoCValue      string `json:"iocValue"`
+				Acknowledged  bool   `json:"acknowledged"`
+				IoC           *struct {
+					ID    string `json:"id"`
+					Value string `json:"value"`
+				} `json:"ioc"`
+			} `json:"items"`
+		} `json:"listWatchlistHits"`
+	}
+	var listed hitConnection
+	gt.NoError(t, json.Unmarshal(resp.Data, &listed))
+	gt.N(t, listed.ListWatchlistHits.Total).Equal(1)
+	hit := listed.ListWatchlistHits.Items[0]
+	gt.S(t, hit.WatchlistName).Equal("brand")
+	gt.False(t, hit.Acknowledged)
+	gt.V(t, hit.IoC).NotNil()
+	gt.S(t, hit.IoC.ID).Equal(ioc.ID)
+
+	ackMutation := `
+		mutation($ids: [ID!]!) {
+			acknowledgeWatchlistHits(ids: $ids) { id acknowledged acknowledgedBy }
+		}
+	`
+	resp = executeGraphQL(t, server, ackMutation, map[string]interface{}{"ids": []string{hit.ID}})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+
+	var acked struct {
+		AcknowledgeWatchlistHits []struct {
+			Acknowledged   bool   `json:"acknowledged"`
+			AcknowledgedBy string `json:"acknowledgedBy"`
+		} `json:"acknowledgeWatchlistHits"`
+	}
+	gt.NoError(t, json.Unmarshal(resp.Data, &acked))
+	gt.A(t, acked.AcknowledgeWatchlistHits).Length(1)
+	gt.True(t, acked.AcknowledgeWatchlistHits[0].Acknowledged)
+	gt.S(t, acked.AcknowledgeWatchlistHits[0].AcknowledgedBy).Equal(model.ActorAnalyst)
+
+	// Hits outlive their IoC and watchlist
+	gt.NoError(t, uc.DeleteIoC(ctx, ioc.ID))
+	resp = executeGraphQL(t, server, `mutation($id: ID!) { deleteWatchlist(id: $id) }`,
+		map[string]interface{}{"id": created.CreateWatchlist.ID})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+
+	resp = executeGraphQL(t, server, listQuery, map[string]interface{}{
+		"options": map[string]interface{}{"acknowledged": true},
+	})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+	listed = hitConnection{}
+	gt.NoError(t, json.Unmarshal(resp.Data, &listed))
+	gt.N(t, listed.ListWatchlistHits.Total).Equal(1)
+	gt.V(t, listed.ListWatchlistHits.Items[0].IoC).Nil()
+
+	resp = executeGraphQL(t, server, `{ listWatchlists { id } }`, nil)
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+	gt.S(t, string(resp.Data)).Equal(`{"listWatchlists":[]}`)
+}
+
+func TestGraphQL_BrandMatches(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	uc := usecase.New(repo)
+	detector, err := brand.New([]string{"example.com"})
+	gt.NoError(t, err)
+	fetchUC := usecase.NewFetchUseCase(repo, nil, usecase.WithBrandDetector(detector))
+	resolver := gqlcontroller.NewResolver(repo, uc, fetchUC)
+	server := httpcontroller.New(resolver)
+
+	ioc, err := uc.CreateIoC(ctx, &usecase.CreateIoCInput{Type: model.IoCTypeDomain, Value: "examlpe.com"})
+	gt.NoError(t, err)
+	matches := detector.Detect(ioc, time.Now())
+	gt.A(t, matches).Length(1)
+	_, err = repo.SaveBrandMatches(ctx, matches)
+	gt.NoError(t, err)
+
+	query := `
+		query($options: BrandMatchListOptions) {
+			protectedDomains
+			listBrandMatches(options: $options) {
+				total
+				items {
+					protectedDomain
+					domain
+					technique
+					editDistance
+					ioc { id }
+				}
+			}
+		}
+	`
+	resp := executeGraphQL(t, server, query, map[string]interface{}{
+		"options": map[string]interface{}{"technique": "typosquat"},
+	})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+
+	var result struct {
+		ProtectedDomains []string `json:"protectedDomains"`
+		ListBrandMatches struct {
+			Total int `json:"total"`
+			Items []struct {
+				ProtectedDomain string `json:"protectedDomain"`
+				Domain          string `json:"domain"`
+				Technique       string `json:"technique"`
+				EditDistance    int    `json:"editDistance"`
+				IoC             *struct {
+					ID string `json:"id"`
+				} `json:"ioc"`
+			} `json:"items"`
+		} `json:"listBrandMatches"`
+	}
+	gt.NoError(t, json.Unmarshal(resp.Data, &result))
+	gt.A(t, result.ProtectedDomains).Length(1)
+	gt.S(t, result.ProtectedDomains[0]).Equal("example.com")
+	gt.N(t, result.ListBrandMatches.Total).Equal(1)
+	m := result.ListBrandMatches.Items[0]
+	gt.S(t, m.ProtectedDomain).Equal("example.com")
+	gt.S(t, m.Domain).Equal("examlpe.com")
+	gt.S(t, m.Technique).Equal("typosquat")
+	gt.N(t, m.EditDistance).Equal(1)
+	gt.V(t, m.IoC).NotNil()
+	gt.S(t, m.IoC.ID).Equal(ioc.ID)
+
+	resp = executeGraphQL(t, server, query, map[string]interface{}{
+		"options": map[string]interface{}{"technique": "bogus"},
+	})
+	gt.N(t, len(resp.Errors)).NotEqual(0).Describe("unknown technique should be rejected")
+}
+
+func TestGraphQL_Reports(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	uc := usecase.New(repo)
+	resolver := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil))
+	server := httpcontroller.New(resolver)
+
+	report := &model.Report{
+		ID:               model.GenerateReportID("blog", "post-1"),
+		SourceID:         "blog",
+		Title:            "APT29 targets diplomats",
+		URL:              "https://blog.example.com/post-1",
+		GUID:             "post-1",
+		PublishedAt:      time.Date(2025, 12, 24, 7, 24, 23, 0, time.UTC),
+		Summary:          "APT29 phished diplomats.",
+		ThreatActors:     []string{"APT29"},
+		CVEs:             []string{"CVE-2024-3400"},
+		AttackTechniques: []string{"T1566.001"},
+	}
+	gt.NoError(t, repo.PutReport(ctx, report))
+	gt.NoError(t, repo.UpsertIoC(ctx, &model.IoC{
+		ID:         "ioc-report",
+		SourceID:   "blog",
+		SourceType: "rss",
+		Type:       model.IoCTypeDomain,
+		Value:      "docs-share.top",
+		Status:     model.IoCStatusActive,
+		ReportID:   report.ID,
+	}))
+
+	t.Run("list reports with IoCs", func(t *testing.T) {
+		resp := executeGraphQL(t, server, `
+			query {
+				listReports(options: {sourceID: "blog"}) {
+					total
+					items {
+						id
+						title
+						summary
+						threatActors
+						malwareFamilies
+						cves
+						attackTechniques
+						iocs { id value }
+					}
+				}
+			}
+		`, nil)
+		gt.A(t, resp.Errors).Length(0)
+
+		var data struct {
+			ListReports struct {
+				Total int `json:"total"`
+				Items []struct {
+					ID               string   `json:"id"`
+					Title            string   `json:"title"`
+					Summary          string   `json:"summary"`
+					ThreatActors     []string `json:"threatActors"`
+					MalwareFamilies  []string `json:"malwareFamilies"`
+					CVEs             []string `json:"cves"`
+					AttackTechniques []string `json:"attackTechniques"`
+					IoCs             []struct {
+						ID    string `json:"id"`
+						Value string `json:"value"`
+					} `json:"iocs"`
+				} `json:"items"`
+			} `json:"listReports"`
+		}
+		gt.NoError(t, json.Unmarshal(resp.Data, &data))
+		gt.Equal(t, data.ListReports.Total, 1)
+		item := data.ListReports.Items[0]
+		gt.Equal(t, item.ID, report.ID)
+		gt.Equal(t, item.ThreatActors, []string{"APT29"})
+		gt.A(t, item.MalwareFamilies).Length(0)
+		gt.Equal(t, item.CVEs, []string{"CVE-2024-3400"})
+		gt.Equal(t, item.AttackTechniques, []string{"T1566.001"})
+		gt.A(t, item.IoCs).Length(1).At(0, func(t testing.TB, ioc struct {
+			ID    string `json:"id"`
+			Value string `json:"value"`
+		}) {
+			gt.Equal(t, ioc.Value, "docs-share.top")
+		})
+	})
+
+	t.Run("IoC links to its report", func(t *testing.T) {
+		resp := executeGraphQL(t, server, `
+			query {
+				getIoC(id: "ioc-report") { reportID report { title publishedAt } }
+				getReport(id: "unknown") { id }
+			}
+		`, nil)
+		gt.A(t, resp.Errors).Length(0)
+
+		var data struct {
+			GetIoC struct {
+				ReportID string `json:"reportID"`
+				Report   struct {
+					Title       string    `json:"title"`
+					PublishedAt time.Time `json:"publishedAt"`
+				} `json:"report"`
+			} `json:"getIoC"`
+			GetReport *struct{} `json:"getReport"`
+		}
+		gt.NoError(t, json.Unmarshal(resp.Data, &data))
+		gt.Equal(t, data.GetIoC.ReportID, report.ID)
+		gt.Equal(t, data.GetIoC.Report.Title, "APT29 targets diplomats")
+		gt.True(t, data.GetIoC.Report.PublishedAt.Equal(report.PublishedAt))
+		gt.Nil(t, data.GetReport)
+	})
+}
+
+func TestGraphQL_Sources(t *testing.T) {
+	repo := memory.New()
+	uc := usecase.New(repo)
+	resolver := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil))
+	server := httpcontroller.New(resolver)
+
+	type source struct {
+		ID          string   `json:"id"`
+		Type        string   `json:"type"`
+		URL         string   `json:"url"`
+		Tags        []string `json:"tags"`
+		Enabled     bool     `json:"enabled"`
+		TTL         *string  `json:"ttl"`
+		Reliability *float64 `json:"reliability"`
+		Extraction  *string  `json:"extraction"`
+		Schema      *string  `json:"schema"`
+		CreatedAt   *string  `json:"createdAt"`
+	}
+	const sourceFields = `id type url tags enabled ttl reliability extraction schema createdAt`
+
+	createMutation := `
+		mutation($input: CreateSourceInput!) {
+			createSource(input: $input) { ` + sourceFields + ` }
+		}
+	`
+	resp := executeGraphQL(t, server, createMutation, map[string]interface{}{
+		"input": map[string]interface{}{
+			"id":          "vendor-blog",
+			"type":        "rss",
+			"url":         "https://blog.example/feed",
+			"tags":        []string{"vendor"},
+			"ttl":         "14d",
+			"reliability": 0.7,
+			"extraction":  "regex",
+		},
+	})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+
+	var created struct {
+		CreateSource source `json:"createSource"`
+	}
+	gt.NoError(t, json.Unmarshal(resp.Data, &created))
+	gt.S(t, created.CreateSource.Type).Equal("rss")
+	gt.True(t, created.CreateSource.Enabled)
+	gt.S(t, *created.CreateSource.TTL).Equal("336h0m0s")
+	gt.Equal(t, *created.CreateSource.Reliability, 0.7)
+	gt.S(t, *created.CreateSource.Extraction).Equal("regex")
+	gt.V(t, created.CreateSource.CreatedAt).NotNil()
+
+	t.Run("config rules are applied", func(t *testing.T) {
+		invalid := []map[string]interface{}{
+			{"id": "bad-ttl", "type": "rss", "url": "https://blog.example/feed", "ttl": "soon"},
+			{"id": "bad-reliability", "type": "rss", "url": "https://blog.example/feed", "reliability": 1.5},
+			{"id": "bad-extraction", "type": "rss", "url": "https://blog.example/feed", "extraction": "guess"},
+			{"id": "no-url", "type": "rss"},
+			{"id": "bad-schema", "type": "feed", "schema": "unknown"},
+			{"id": "rss-schema", "type": "rss", "url": "https://blog.example/feed", "schema": "abuse_ch_urlhaus"},
+			{"id": "vendor-blog", "type": "rss", "url": "https://blog.example/feed"},
+		}
+		for _, input := range invalid {
+			resp := executeGraphQL(t, server, createMutation, map[string]interface{}{"input": input})
+			gt.N(t, len(resp.Errors)).Greater(0).Describef("%v should be rejected", input["id"])
+		}
+	})
+
+	resp = executeGraphQL(t, server, createMutation, map[string]interface{}{
+		"input": map[string]interface{}{"id": "urlhaus", "type": "feed", "schema": "abuse_ch_urlhaus", "enabled": false},
+	})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+
+	resp = executeGraphQL(t, server, `
+		mutation($id: ID!, $input: UpdateSourceInput!) {
+			updateSource(id: $id, input: $input) { `+sourceFields+` }
+		}
+	`, map[string]interface{}{
+		"id":    "vendor-blog",
+		"input": map[string]interface{}{"url": "https://blog.example/rss", "ttl": ""},
+	})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+	var updated struct {
+		UpdateSource source `json:"updateSource"`
+	}
+	gt.NoError(t, json.Unmarshal(resp.Data, &updated))
+	gt.S(t, updated.UpdateSource.URL).Equal("https://blog.example/rss")
+	gt.V(t, updated.UpdateSource.TTL).Nil()
+	gt.Equal(t, updated.UpdateSource.Tags, []string{"vendor"})
+	gt.S(t, *updated.UpdateSource.Extraction).Equal("regex")
+
+	resp = executeGraphQL(t, server, `
+		mutation($id: ID!) { setSourceEnabled(id: $id, enabled: true) { enabled } }
+	`, map[string]interface{}{"id": "urlhaus"})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+
+	resp = executeGraphQL(t, server, `{ listSources { `+sourceFields+` } }`, nil)
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+	var list struct {
+		ListSources []source `json:"listSources"`
+	}
+	gt.NoError(t, json.Unmarshal(resp.Data, &list))
+	gt.A(t, list.ListSources).Length(2)
+	gt.S(t, list.ListSources[0].ID).Equal("urlhaus")
+	gt.True(t, list.ListSources[0].Enabled)
+	gt.S(t, *list.ListSources[0].Schema).Equal("abuse_ch_urlhaus")
+	gt.S(t, list.ListSources[1].ID).Equal("vendor-blog")
+
+	resp = executeGraphQL(t, server, `mutation($id: ID!) { deleteSource(id: $id) }`,
+		map[string]interface{}{"id": "vendor-blog"})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+
+	resp = executeGraphQL(t, server, `mutation($id: String!) { fetchSource(sourceID: $id) { id } }`,
+		map[string]interface{}{"id": "vendor-blog"})
+	gt.N(t, len(resp.Errors)).Greater(0).Describe("deleted source should not be fetched")
+
+	resp = executeGraphQL(t, server, `
+		query($sourceID: String) {
+			listSourceChanges(sourceID: $sourceID) {
+				sourceID
+				action
+				actor
+				fields
+				before { url }
+				after { url }
+			}
+		}
+	`, map[string]interface{}{"sourceID": "vendor-blog"})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+	var changes struct {
+		ListSourceChanges []struct {
+			SourceID string   `json:"sourceID"`
+			Action   string   `json:"action"`
+			Actor    string   `json:"actor"`
+			Fields   []string `json:"fields"`
+			Before   *source  `json:"before"`
+			After    *source  `json:"after"`
+		} `json:"listSourceChanges"`
+	}
+	gt.NoError(t, json.Unmarshal(resp.Data, &changes))
+	gt.A(t, changes.ListSourceChanges).Length(3)
+	gt.S(t, changes.ListSourceChanges[0].Action).Equal("delete")
+	gt.V(t, changes.ListSourceChanges[0].After).Nil()
+	gt.S(t, changes.ListSourceChanges[1].Action).Equal("update")
+	gt.S(t, changes.ListSourceChanges[1].Actor).Equal(model.ActorAnalyst)
+	gt.Equal(t, changes.ListSourceChanges[1].Fields, []string{"url", "ttl"})
+	gt.S(t, changes.ListSourceChanges[1].Before.URL).Equal("https://blog.example/feed")
+	gt.S(t, changes.ListSourceChanges[1].After.URL).Equal("https://blog.example/rss")
+	gt.S(t, changes.ListSourceChanges[2].Action).Equal("create")
+
+	resp = executeGraphQL(t, server, `{ listSourceChanges(limit: 10) { sourceID } }`, nil)
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+	gt.NoError(t, json.Unmarshal(resp.Data, &changes))
+	gt.A(t, changes.ListSourceChanges).Length(5)
+}
+
+func TestGraphQL_ConfigStatus(t *testing.T) {
+	repo := memory.New()
+	uc := usecase.New(repo)
+	fetchUC := usecase.NewFetchUseCase(repo, nil)
+	query := `{ configStatus { path trigger loadedAt ok error sources created updated deleted drifted } }`
+
+	type configStatus struct {
+		Path     string   `json:"path"`
+		Trigger  string   `json:"trigger"`
+		LoadedAt *string  `json:"loadedAt"`
+		OK       bool     `json:"ok"`
+		Error    *string  `json:"error"`
+		Sources  int      `json:"sources"`
+		Created  []string `json:"created"`
+		Updated  []string `json:"updated"`
+		Deleted  []string `json:"deleted"`
+		Drifted  []string `json:"drifted"`
+	}
+	var data struct {
+		ConfigStatus *configStatus `json:"configStatus"`
+	}
+
+	t.Run("without config file", func(t *testing.T) {
+		server := httpcontroller.New(gqlcontroller.NewResolver(repo, uc, fetchUC))
+		resp := executeGraphQL(t, server, query, nil)
+		gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+		gt.NoError(t, json.Unmarshal(resp.Data, &data))
+		gt.V(t, data.ConfigStatus).Nil()
+	})
+
+	t.Run("failed reload", func(t *testing.T) {
+		status := &model.ConfigStatus{
+			Path:      "config.toml",
+			Trigger:   model.ConfigReloadFile,
+			CheckedAt: time.Now(),
+			LoadedAt:  time.Now().Add(-time.Hour),
+			Error:     "invalid source",
+			Sources:   2,
+		}
+		resolver := gqlcontroller.NewResolver(repo, uc, fetchUC,
+			gqlcontroller.WithConfigStatus(func() *model.ConfigStatus { return status }))
+		resp := executeGraphQL(t, httpcontroller.New(resolver), query, nil)
+		gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+		gt.NoError(t, json.Unmarshal(resp.Data, &data))
+		gt.V(t, data.ConfigStatus).NotNil()
+		gt.S(t, data.ConfigStatus.Trigger).Equal("file")
+		gt.False(t, data.ConfigStatus.OK)
+		gt.S(t, *data.ConfigStatus.Error).Equal("invalid source")
+		gt.V(t, data.ConfigStatus.LoadedAt).NotNil()
+		gt.N(t, data.ConfigStatus.Sources).Equal(2)
+		gt.A(t, data.ConfigStatus.Created).Length(0)
+	})
+
+	t.Run("drifted sources", func(t *testing.T) {
+		status := &model.ConfigStatus{
+			Path:      "config.toml",
+			Trigger:   model.ConfigReloadStartup,
+			CheckedAt: time.Now(),
+			LoadedAt:  time.Now(),
+			Sources:   2,
+			Drifted:   []string{"blog"},
+		}
+		resolver := gqlcontroller.NewResolver(repo, uc, fetchUC,
+			gqlcontroller.WithConfigStatus(func() *model.ConfigStatus { return status }))
+		resp := executeGraphQL(t, httpcontroller.New(resolver), query, nil)
+		gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+		gt.NoError(t, json.Unmarshal(resp.Data, &data))
+		gt.False(t, data.ConfigStatus.OK)
+		gt.V(t, data.ConfigStatus.Error).Nil()
+		gt.A(t, data.ConfigStatus.Drifted).Equal([]string{"blog"})
+	})
+}
+
+func TestGraphQL_FetchJobs(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+	blog := httptest.NewServer(mux)
+	defer blog.Close()
+	mux.HandleFunc("/feed", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Blog</title>
+<item><title>Campaign</title><link>%s/post/1</link><guid>post-1</guid></item>
+</channel></rss>`, blog.URL)
+	})
+	mux.HandleFunc("/post/1", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `<html><body><article>C2 at 198.51.100[.]42</article></body></html>`)
+	})
+
+	repo := memory.New()
+	uc := usecase.New(repo)
+	_, err := uc.CreateSource(ctx, &model.Source{
+		ID:        "blog",
+		Type:      model.SourceTypeRSS,
+		URL:       blog.URL + "/feed",
+		Enabled:   true,
+		RSSConfig: &model.RSSConfig{Extraction: model.ExtractionModeRegex},
+	}, "alice")
+	gt.NoError(t, err)
+
+	jobs := usecase.NewFetchJobUseCase(usecase.NewFetchUseCase(repo, nil))
+	defer jobs.Shutdown()
+	resolver := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil), gqlcontroller.WithFetchJobs(jobs))
+	server := httpcontroller.New(resolver)
+
+	resp := executeGraphQL(t, server, `mutation { fetchSource(sourceID: "blog") { id sourceID status } }`, nil)
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+	var started struct {
+		FetchSource struct {
+			ID       string `json:"id"`
+			SourceID string `json:"sourceID"`
+		} `json:"fetchSource"`
+	}
+	gt.NoError(t, json.Unmarshal(resp.Data, &started))
+	gt.S(t, started.FetchSource.SourceID).Equal("blog")
+
+	type job struct {
+		ID             string  `json:"id"`
+		Status         string  `json:"status"`
+		ItemsTotal     int     `json:"itemsTotal"`
+		ItemsProcessed int     `json:"itemsProcessed"`
+		IocsExtracted  int     `json:"iocsExtracted"`
+		HistoryID      *string `json:"historyID"`
+	}
+
+	t.Run("subscription streams progress until the job finishes", func(t *testing.T) {
+		c := client.New(server, client.Path("/graphql"))
+		sub := c.Websocket(`subscription($id: ID!) {
+			jobProgress(id: $id) { id status itemsTotal itemsProcessed iocsExtracted historyID }
+		}`, client.Var("id", started.FetchSource.ID))
+		defer func() { _ = sub.Close() }()
+
+		var progress struct {
+			JobProgress job `json:"jobProgress"`
+		}
+		for progress.JobProgress.Status != "succeeded" {
+			gt.NoError(t, sub.Next(&progress))
+			gt.S(t, progress.JobProgress.ID).Equal(started.FetchSource.ID)
+			gt.S(t, progress.JobProgress.Status).NotEqual("failed")
+		}
+		gt.N(t, progress.JobProgress.ItemsProcessed).Equal(1)
+		gt.N(t, progress.JobProgress.IocsExtracted).Equal(1)
+		gt.V(t, progress.JobProgress.HistoryID).NotNil()
+	})
+
+	resp = executeGraphQL(t, server, `query($id: ID!) { job(id: $id) { id status itemsTotal historyID } }`,
+		map[string]interface{}{"id": started.FetchSource.ID})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+	var got struct {
+		Job *job `json:"job"`
+	}
+	gt.NoError(t, json.Unmarshal(resp.Data, &got))
+	gt.S(t, got.Job.Status).Equal("succeeded")
+	gt.N(t, got.Job.ItemsTotal).Equal(1)
+
+	resp = executeGraphQL(t, server, `mutation($id: ID!) { cancelJob(id: $id) { status } }`,
+		map[string]interface{}{"id": started.FetchSource.ID})
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("canceling a finished job has no effect")
+
+	resp = executeGraphQL(t, server, `{ job(id: "missing") { id } }`, nil)
+	gt.N(t, len(resp.Errors)).Equal(0).Describe("should have no errors")
+	gt.NoError(t, json.Unmarshal(resp.Data, &got))
+	gt.V(t, got.Job).Nil()
+}
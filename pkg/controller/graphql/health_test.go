@@ -0,0 +1,145 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	gqlcontroller "github.com/secmon-lab/beehive/pkg/controller/graphql"
+	httpcontroller "github.com/secmon-lab/beehive/pkg/controller/http"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/service/auth"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	ctx := context.Background()
+	jwksURL, sign := newTokenSigner(t)
+	repo := memory.New()
+	uc := usecase.New(repo)
+	resolver := gqlcontroller.NewResolver(repo, uc, usecase.NewFetchUseCase(repo, nil))
+	// Probes are served without credentials
+	server := httpcontroller.New(resolver,
+		httpcontroller.WithAuthenticator(auth.NewJWT(jwksURL)),
+		httpcontroller.WithFetchInterval(time.Hour))
+
+	getWithToken := func(path, token string, out any) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		gt.Equal(t, w.Header().Get("Content-Type"), "application/json")
+		gt.NoError(t, json.Unmarshal(w.Body.Bytes(), out))
+		return w.Code
+	}
+	get := func(path string, out any) int {
+		t.Helper()
+		return getWithToken(path, "", out)
+	}
+
+	t.Run("liveness", func(t *testing.T) {
+		var resp map[string]string
+		gt.Equal(t, get("/healthz", &resp), http.StatusOK)
+		gt.Equal(t, resp["status"], "ok")
+	})
+
+	type check struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	}
+	var readiness struct {
+		Status string           `json:"status"`
+		Checks map[string]check `json:"checks"`
+	}
+
+	t.Run("readiness", func(t *testing.T) {
+		code := get("/readyz", &readiness)
+		gt.Equal(t, readiness.Checks["repository"].Status, "ok")
+		gt.Equal(t, readiness.Checks["llm"].Status, "ok")
+		// The frontend is only embedded after it is built
+		if readiness.Checks["frontend"].Status == "ok" {
+			gt.Equal(t, code, http.StatusOK)
+		} else {
+			gt.Equal(t, code, http.StatusServiceUnavailable)
+			gt.Equal(t, readiness.Status, "unavailable")
+		}
+	})
+
+	t.Run("readiness fails for sources skipped without LLM", func(t *testing.T) {
+		_, err := uc.CreateSource(ctx, &model.Source{
+			ID: "llm-blog", Type: model.SourceTypeRSS, URL: "https://blog.example/feed", Enabled: true,
+		}, "alice")
+		gt.NoError(t, err)
+		t.Cleanup(func() { gt.NoError(t, uc.DeleteSource(ctx, "llm-blog", "alice")) })
+
+		gt.Equal(t, get("/readyz", &readiness), http.StatusServiceUnavailable)
+		gt.Equal(t, readiness.Checks["llm"].Status, "error")
+		// Error details are logged, not returned
+		gt.Equal(t, readiness.Checks["llm"].Error, "")
+	})
+
+	type status struct {
+		Status        string   `json:"status"`
+		FetchInterval string   `json:"fetch_interval"`
+		SourceCount   int      `json:"source_count"`
+		StaleCount    int      `json:"stale_count"`
+		StaleSources  []string `json:"stale_sources"`
+		Sources       []struct {
+			SourceID      string     `json:"source_id"`
+			LastFetchedAt *time.Time `json:"last_fetched_at"`
+			LastStatus    string     `json:"last_status"`
+			Stale         bool       `json:"stale"`
+			Reason        string     `json:"reason"`
+		} `json:"sources"`
+	}
+
+	t.Run("status of sources", func(t *testing.T) {
+		token := sign("alice", model.RoleViewer)
+		var resp status
+		gt.Equal(t, getWithToken("/status", token, &resp), http.StatusOK)
+		gt.Equal(t, resp.Status, "ok")
+		gt.Equal(t, resp.FetchInterval, "1h0m0s")
+		gt.Equal(t, resp.SourceCount, 0)
+		gt.A(t, resp.Sources).Length(0)
+
+		_, err := uc.CreateSource(ctx, &model.Source{
+			ID: "feed", Type: model.SourceTypeFeed, URL: "https://feed.example/csv", Enabled: true,
+			FeedConfig: &model.FeedConfig{Schema: "abuse_ch_urlhaus"},
+		}, "alice")
+		gt.NoError(t, err)
+		gt.NoError(t, repo.SaveState(ctx, &model.SourceState{
+			SourceID: "feed", LastFetchedAt: time.Now().Add(-3 * time.Hour), LastStatus: string(model.FetchStatusSuccess),
+		}))
+
+		resp = status{}
+		gt.Equal(t, getWithToken("/status", token, &resp), http.StatusServiceUnavailable)
+		gt.Equal(t, resp.Status, "stale")
+		gt.Equal(t, resp.SourceCount, 1)
+		gt.Equal(t, resp.StaleCount, 1)
+		gt.Equal(t, resp.StaleSources, []string{"feed"})
+		gt.A(t, resp.Sources).Length(1)
+		gt.True(t, resp.Sources[0].Stale)
+		gt.Equal(t, resp.Sources[0].LastStatus, "success")
+		gt.V(t, resp.Sources[0].LastFetchedAt).NotNil()
+		gt.Equal(t, resp.Sources[0].Reason, "not fetched within the expected interval")
+
+		// Requests without valid credentials only get the counts
+		for _, token := range []string{"", "invalid"} {
+			resp = status{}
+			gt.Equal(t, getWithToken("/status", token, &resp), http.StatusServiceUnavailable)
+			gt.Equal(t, resp.Status, "stale")
+			gt.Equal(t, resp.SourceCount, 1)
+			gt.Equal(t, resp.StaleCount, 1)
+			gt.V(t, resp.StaleSources).Nil()
+			gt.V(t, resp.Sources).Nil()
+		}
+	})
+}
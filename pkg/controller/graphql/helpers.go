@@ -4,6 +4,8 @@ import (
 	"sort"
 	"time"
 
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/cli/config"
 	"github.com/secmon-lab/beehive/pkg/domain/model"
 	graphql1 "github.com/secmon-lab/beehive/pkg/domain/model/graphql"
 	"github.com/secmon-lab/beehive/pkg/service/feed"
@@ -16,6 +18,20 @@ func ptrIntValue(ptr *int) int {
 	return *ptr
 }
 
+func ptrStringValue(ptr *string) string {
+	if ptr == nil {
+		return ""
+	}
+	return *ptr
+}
+
+func nonEmptyStringPtr(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
 func toModelSortField(field *graphql1.IoCSortField) model.IoCSortField {
 	if field == nil {
 		return ""
@@ -33,6 +49,8 @@ func toModelSortField(field *graphql1.IoCSortField) model.IoCSortField {
 		return model.IoCSortByFirstSeenAt
 	case graphql1.IoCSortFieldUpdatedAt:
 		return model.IoCSortByUpdatedAt
+	case graphql1.IoCSortFieldConfidence:
+		return model.IoCSortByConfidence
 	default:
 		return ""
 	}
@@ -52,24 +70,121 @@ func toModelSortOrder(order *graphql1.SortOrder) model.SortOrder {
 	}
 }
 
+func toModelIoCTypes(types []graphql1.IoCType) []model.IoCType {
+	if len(types) == 0 {
+		return nil
+	}
+	result := make([]model.IoCType, 0, len(types))
+	for _, t := range types {
+		switch t {
+		case graphql1.IoCTypeIPV4:
+			result = append(result, model.IoCTypeIPv4)
+		case graphql1.IoCTypeIPV6:
+			result = append(result, model.IoCTypeIPv6)
+		case graphql1.IoCTypeDomain:
+			result = append(result, model.IoCTypeDomain)
+		case graphql1.IoCTypeURL:
+			result = append(result, model.IoCTypeURL)
+		case graphql1.IoCTypeEmail:
+			result = append(result, model.IoCTypeEmail)
+		case graphql1.IoCTypeMacAddr:
+			result = append(result, model.IoCTypeMacAddr)
+		case graphql1.IoCTypeAsn:
+			result = append(result, model.IoCTypeASN)
+		case graphql1.IoCTypeMd5:
+			result = append(result, model.IoCTypeMD5)
+		case graphql1.IoCTypeSha1:
+			result = append(result, model.IoCTypeSHA1)
+		case graphql1.IoCTypeSha256:
+			result = append(result, model.IoCTypeSHA256)
+		case graphql1.IoCTypeFilename:
+			result = append(result, model.IoCTypeFilename)
+		case graphql1.IoCTypeProcess:
+			result = append(result, model.IoCTypeProcess)
+		case graphql1.IoCTypeMutex:
+			result = append(result, model.IoCTypeMutex)
+		case graphql1.IoCTypeRegistryKey:
+			result = append(result, model.IoCTypeRegKey)
+		case graphql1.IoCTypeUserAgent:
+			result = append(result, model.IoCTypeUserAgent)
+		case graphql1.IoCTypeCertHash:
+			result = append(result, model.IoCTypeCertHash)
+		}
+	}
+	return result
+}
+
 func toGraphQLIoC(ioc *model.IoC) *graphql1.IoC {
 	var sourceURL *string
 	if ioc.SourceURL != "" {
 		sourceURL = &ioc.SourceURL
 	}
+	var expiresAt *time.Time
+	if !ioc.ExpiresAt.IsZero() {
+		expiresAt = &ioc.ExpiresAt
+	}
+	var sourceConfidence *int
+	if ioc.SourceConfidence > 0 {
+		sourceConfidence = &ioc.SourceConfidence
+	}
+	var reportID *string
+	if ioc.ReportID != "" {
+		reportID = &ioc.ReportID
+	}
 
 	return &graphql1.IoC{
-		ID:          ioc.ID,
-		SourceID:    ioc.SourceID,
-		SourceType:  ioc.SourceType,
-		Type:        string(ioc.Type),
-		Value:       ioc.Value,
-		Description: ioc.Description,
-		SourceURL:   sourceURL,
-		Context:     ioc.Context,
-		Status:      string(ioc.Status),
-		FirstSeenAt: ioc.FirstSeenAt,
-		UpdatedAt:   ioc.UpdatedAt,
+		ID:               ioc.ID,
+		SourceID:         ioc.SourceID,
+		SourceType:       ioc.SourceType,
+		Type:             string(ioc.Type),
+		Value:            ioc.Value,
+		Description:      ioc.Description,
+		SourceURL:        sourceURL,
+		Context:          ioc.Context,
+		Status:           string(ioc.Status),
+		FirstSeenAt:      ioc.FirstSeenAt,
+		UpdatedAt:        ioc.UpdatedAt,
+		ExpiresAt:        expiresAt,
+		Confidence:       ioc.Confidence,
+		SourceConfidence: sourceConfidence,
+		ExtractedByLlm:   ioc.ExtractedByLLM,
+		Tags:             ensureStringSlice(ioc.Tags),
+		Notes:            ioc.Notes,
+		Override:         toGraphQLIoCOverride(ioc.Override),
+		ReportID:         reportID,
+	}
+}
+
+func toGraphQLIoCStatusTransition(tr *model.IoCStatusTransition) *graphql1.IoCStatusTransition {
+	return &graphql1.IoCStatusTransition{
+		ID:        tr.ID,
+		From:      string(tr.From),
+		To:        string(tr.To),
+		Actor:     tr.Actor,
+		Reason:    tr.Reason,
+		CreatedAt: tr.CreatedAt,
+	}
+}
+
+func toGraphQLIoCOverride(o *model.IoCOverride) *graphql1.IoCOverride {
+	if o == nil {
+		return nil
+	}
+
+	var description *string
+	var status *string
+	if o.Description != "" {
+		description = &o.Description
+	}
+	if o.Status != "" {
+		s := string(o.Status)
+		status = &s
+	}
+
+	return &graphql1.IoCOverride{
+		Description: description,
+		Status:      status,
+		UpdatedAt:   o.UpdatedAt,
 	}
 }
 
@@ -146,12 +261,16 @@ func toGraphQLHistory(h *model.History) *graphql1.History {
 		CompletedAt:    h.CompletedAt,
 		ProcessingTime: int(h.ProcessingTime / time.Millisecond),
 		Urls:           h.URLs,
+		TraceID:        nonEmptyStringPtr(h.TraceID),
 		ItemsFetched:   h.ItemsFetched,
 		IoCsExtracted:  h.IoCsExtracted,
+		IoCsUnverified: h.IoCsUnverified,
 		IoCsCreated:    h.IoCsCreated,
 		IoCsUpdated:    h.IoCsUpdated,
 		IoCsUnchanged:  h.IoCsUnchanged,
 		ErrorCount:     h.ErrorCount,
+		WatchlistHits:  h.WatchlistHits,
+		BrandMatches:   h.BrandMatches,
 		Errors:         errors,
 		CreatedAt:      h.CreatedAt,
 	}
@@ -170,3 +289,403 @@ func getSchemaDescription(schema string) string {
 	}
 	return ""
 }
+
+// toModelWatchPatterns converts pattern inputs. A nil input stays nil (unchanged on update).
+func toModelWatchPatterns(inputs []*graphql1.WatchPatternInput) []model.WatchPattern {
+	if inputs == nil {
+		return nil
+	}
+
+	patterns := make([]model.WatchPattern, len(inputs))
+	for i, p := range inputs {
+		patterns[i] = model.WatchPattern{
+			Kind:  model.WatchPatternKind(p.Kind),
+			Value: p.Value,
+		}
+	}
+	return patterns
+}
+
+func toGraphQLWatchlist(w *model.Watchlist) *graphql1.Watchlist {
+	patterns := make([]*graphql1.WatchPattern, len(w.Patterns))
+	for i, p := range w.Patterns {
+		patterns[i] = &graphql1.WatchPattern{
+			Kind:  string(p.Kind),
+			Value: p.Value,
+		}
+	}
+
+	return &graphql1.Watchlist{
+		ID:          w.ID,
+		Name:        w.Name,
+		Description: w.Description,
+		Patterns:    patterns,
+		Enabled:     w.Enabled,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+}
+
+func toGraphQLWatchlistHit(hit *model.WatchlistHit) *graphql1.WatchlistHit {
+	var acknowledgedBy *string
+	if hit.AcknowledgedBy != "" {
+		acknowledgedBy = &hit.AcknowledgedBy
+	}
+	var acknowledgedAt *time.Time
+	if !hit.AcknowledgedAt.IsZero() {
+		acknowledgedAt = &hit.AcknowledgedAt
+	}
+
+	return &graphql1.WatchlistHit{
+		ID:             hit.ID,
+		WatchlistID:    hit.WatchlistID,
+		WatchlistName:  hit.WatchlistName,
+		IocID:          hit.IoCID,
+		IocType:        string(hit.IoCType),
+		IocValue:       hit.IoCValue,
+		SourceID:       hit.SourceID,
+		PatternKind:    string(hit.PatternKind),
+		Pattern:        hit.Pattern,
+		MatchedField:   hit.MatchedField,
+		Acknowledged:   hit.Acknowledged,
+		AcknowledgedBy: acknowledgedBy,
+		AcknowledgedAt: acknowledgedAt,
+		CreatedAt:      hit.CreatedAt,
+	}
+}
+
+func toGraphQLBrandMatch(m *model.BrandMatch) *graphql1.BrandMatch {
+	return &graphql1.BrandMatch{
+		ID:              m.ID,
+		ProtectedDomain: m.ProtectedDomain,
+		Domain:          m.Domain,
+		IocID:           m.IoCID,
+		IocType:         string(m.IoCType),
+		IocValue:        m.IoCValue,
+		SourceID:        m.SourceID,
+		Technique:       string(m.Technique),
+		EditDistance:    m.EditDistance,
+		Score:           m.Score,
+		CreatedAt:       m.CreatedAt,
+	}
+}
+
+func toGraphQLReport(report *model.Report) *graphql1.Report {
+	var publishedAt *time.Time
+	if !report.PublishedAt.IsZero() {
+		publishedAt = &report.PublishedAt
+	}
+
+	return &graphql1.Report{
+		ID:               report.ID,
+		SourceID:         report.SourceID,
+		Title:            report.Title,
+		URL:              report.URL,
+		PublishedAt:      publishedAt,
+		Content:          report.Content,
+		Summary:          report.Summary,
+		ThreatActors:     ensureStringSlice(report.ThreatActors),
+		MalwareFamilies:  ensureStringSlice(report.MalwareFamilies),
+		Sectors:          ensureStringSlice(report.Sectors),
+		Cves:             ensureStringSlice(report.CVEs),
+		AttackTechniques: ensureStringSlice(report.AttackTechniques),
+		CreatedAt:        report.CreatedAt,
+		UpdatedAt:        report.UpdatedAt,
+	}
+}
+
+func toGraphQLSource(src *model.Source) *graphql1.Source {
+	gqlSrc := &graphql1.Source{
+		ID:          src.ID,
+		Type:        string(src.Type),
+		URL:         src.URL,
+		Tags:        ensureStringSlice(src.Tags),
+		Enabled:     src.Enabled,
+		Reliability: src.Reliability,
+	}
+	if src.Description != "" {
+		gqlSrc.Description = &src.Description
+	}
+	if src.TTL != nil {
+		ttl := src.TTL.String()
+		gqlSrc.TTL = &ttl
+	}
+	if src.RSSConfig != nil {
+		maxArticles := src.RSSConfig.MaxArticles
+		gqlSrc.MaxArticles = &maxArticles
+		if src.RSSConfig.Extraction != "" {
+			extraction := string(src.RSSConfig.Extraction)
+			gqlSrc.Extraction = &extraction
+		}
+	}
+	if src.FeedConfig != nil {
+		schema := src.FeedConfig.Schema
+		gqlSrc.Schema = &schema
+		if desc := getSchemaDescription(schema); desc != "" {
+			gqlSrc.SchemaDescription = &desc
+		}
+		maxItems := src.FeedConfig.MaxItems
+		gqlSrc.MaxItems = &maxItems
+	}
+	if !src.CreatedAt.IsZero() {
+		gqlSrc.CreatedAt = &src.CreatedAt
+	}
+	if !src.UpdatedAt.IsZero() {
+		gqlSrc.UpdatedAt = &src.UpdatedAt
+	}
+	return gqlSrc
+}
+
+func toGraphQLSourceChange(c *model.SourceChange) *graphql1.SourceChange {
+	change := &graphql1.SourceChange{
+		ID:        c.ID,
+		SourceID:  c.SourceID,
+		Action:    string(c.Action),
+		Actor:     c.Actor,
+		Fields:    ensureStringSlice(c.Fields),
+		CreatedAt: c.CreatedAt,
+	}
+	if c.Before != nil {
+		change.Before = toGraphQLSource(c.Before)
+	}
+	if c.After != nil {
+		change.After = toGraphQLSource(c.After)
+	}
+	return change
+}
+
+// sourceInput holds the settings of CreateSourceInput and UpdateSourceInput.
+// Nil fields are left unchanged.
+type sourceInput struct {
+	URL         *string
+	Description *string
+	Tags        []string
+	Enabled     *bool
+	TTL         *string
+	Reliability *float64
+	Extraction  *string
+	MaxArticles *int
+	Schema      *string
+	MaxItems    *int
+}
+
+// applySourceInput applies the input to a source definition and validates the
+// result with the config rules of the source type
+func applySourceInput(src *model.Source, input sourceInput) (*model.Source, error) {
+	next := *src
+	if input.URL != nil {
+		next.URL = *input.URL
+	}
+	if input.Description != nil {
+		next.Description = *input.Description
+	}
+	if input.Tags != nil {
+		next.Tags = input.Tags
+	}
+	if input.Enabled != nil {
+		next.Enabled = *input.Enabled
+	}
+	if input.Reliability != nil {
+		next.Reliability = input.Reliability
+	}
+
+	var result model.Source
+	switch src.Type {
+	case model.SourceTypeRSS:
+		if input.Schema != nil || input.MaxItems != nil {
+			return nil, goerr.New("schema and maxItems are not used by RSS sources", goerr.V("id", src.ID))
+		}
+		cfg := config.NewRSSSource(&next)
+		if input.TTL != nil {
+			cfg.RawTTL = *input.TTL
+		}
+		if input.Extraction != nil {
+			cfg.Extraction = model.ExtractionMode(*input.Extraction)
+		}
+		if input.MaxArticles != nil {
+			cfg.MaxArticles = *input.MaxArticles
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, goerr.Wrap(err, "invalid RSS source", goerr.V("id", src.ID))
+		}
+		result = cfg.Source(src.ID)
+
+	case model.SourceTypeFeed:
+		if input.Extraction != nil || input.MaxArticles != nil {
+			return nil, goerr.New("extraction and maxArticles are not used by feed sources", goerr.V("id", src.ID))
+		}
+		cfg := config.NewFeedSource(&next)
+		if input.TTL != nil {
+			cfg.RawTTL = *input.TTL
+		}
+		if input.Schema != nil {
+			cfg.RawSchema = *input.Schema
+		}
+		if input.MaxItems != nil {
+			cfg.MaxItems = *input.MaxItems
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, goerr.Wrap(err, "invalid feed source", goerr.V("id", src.ID))
+		}
+		result = cfg.Source(src.ID)
+
+	default:
+		return nil, goerr.New("invalid source type", goerr.V("id", src.ID), goerr.V("type", src.Type))
+	}
+
+	result.CreatedAt = src.CreatedAt
+	result.UpdatedAt = src.UpdatedAt
+	return &result, nil
+}
+
+func toGraphQLConfigStatus(status *model.ConfigStatus) *graphql1.ConfigStatus {
+	gqlStatus := &graphql1.ConfigStatus{
+		Path:      status.Path,
+		Trigger:   string(status.Trigger),
+		CheckedAt: status.CheckedAt,
+		Ok:        status.InSync(),
+		Sources:   status.Sources,
+		Created:   ensureStringSlice(status.Created),
+		Updated:   ensureStringSlice(status.Updated),
+		Deleted:   ensureStringSlice(status.Deleted),
+		Drifted:   ensureStringSlice(status.Drifted),
+	}
+	if !status.LoadedAt.IsZero() {
+		gqlStatus.LoadedAt = &status.LoadedAt
+	}
+	if status.Error != "" {
+		gqlStatus.Error = &status.Error
+	}
+	return gqlStatus
+}
+
+// toGraphQLJob converts a domain Job to a GraphQL Job
+func toGraphQLJob(job *model.Job) *graphql1.Job {
+	gqlJob := &graphql1.Job{
+		ID:             job.ID,
+		SourceID:       job.SourceID,
+		Status:         string(job.Status),
+		ItemsTotal:     job.ItemsTotal,
+		ItemsProcessed: job.ItemsProcessed,
+		IocsExtracted:  job.IoCsExtracted,
+		CreatedAt:      job.CreatedAt,
+	}
+	if job.Phase != "" {
+		phase := string(job.Phase)
+		gqlJob.Phase = &phase
+	}
+	if job.HistoryID != "" {
+		gqlJob.HistoryID = &job.HistoryID
+	}
+	if job.Error != "" {
+		gqlJob.Error = &job.Error
+	}
+	if !job.StartedAt.IsZero() {
+		gqlJob.StartedAt = &job.StartedAt
+	}
+	if !job.FinishedAt.IsZero() {
+		gqlJob.FinishedAt = &job.FinishedAt
+	}
+	return gqlJob
+}
+
+// toGraphQLPrincipal converts a domain Principal to a GraphQL Principal
+func toGraphQLPrincipal(p *model.Principal) *graphql1.Principal {
+	principal := &graphql1.Principal{
+		Subject: p.Subject,
+		Method:  string(p.Method),
+		Scopes:  toScopeStrings(p.Scopes),
+	}
+	for gqlRole, r := range graphQLRoles {
+		if r == p.Role {
+			principal.Role = &gqlRole
+		}
+	}
+	return principal
+}
+
+func toScopeStrings(scopes []model.TokenScope) []string {
+	result := make([]string, len(scopes))
+	for i, scope := range scopes {
+		result[i] = string(scope)
+	}
+	return result
+}
+
+func toGraphQLAPIToken(token *model.APIToken) *graphql1.APIToken {
+	gqlToken := &graphql1.APIToken{
+		ID:        token.ID,
+		Name:      token.Name,
+		Scopes:    toScopeStrings(token.Scopes),
+		CreatedBy: token.CreatedBy,
+		CreatedAt: token.CreatedAt,
+		Active:    token.Active(time.Now()),
+	}
+	if !token.ExpiresAt.IsZero() {
+		gqlToken.ExpiresAt = &token.ExpiresAt
+	}
+	if !token.LastUsedAt.IsZero() {
+		gqlToken.LastUsedAt = &token.LastUsedAt
+	}
+	if !token.RevokedAt.IsZero() {
+		gqlToken.RevokedAt = &token.RevokedAt
+	}
+	return gqlToken
+}
+
+// defaultAuditRecordLimit is the number of audit records returned when the
+// filter sets no limit
+const defaultAuditRecordLimit = 100
+
+func toModelAuditFilter(filter *graphql1.AuditLogFilter) *model.AuditFilter {
+	f := &model.AuditFilter{Limit: defaultAuditRecordLimit}
+	if filter == nil {
+		return f
+	}
+	f.Actor = ptrStringValue(filter.Actor)
+	f.Action = model.AuditAction(ptrStringValue(filter.Action))
+	f.TargetType = ptrStringValue(filter.TargetType)
+	f.TargetID = ptrStringValue(filter.TargetID)
+	f.RequestID = ptrStringValue(filter.RequestID)
+	if filter.Since != nil {
+		f.Since = *filter.Since
+	}
+	if filter.Until != nil {
+		f.Until = *filter.Until
+	}
+	if limit := ptrIntValue(filter.Limit); limit > 0 {
+		f.Limit = limit
+	}
+	return f
+}
+
+// auditValue returns a JSON encoded value of an audit change, nil if absent
+func auditValue(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+func toGraphQLAuditRecord(r *model.AuditRecord) *graphql1.AuditRecord {
+	record := &graphql1.AuditRecord{
+		ID:         r.ID,
+		Actor:      r.Actor,
+		Action:     string(r.Action),
+		TargetType: r.TargetType,
+		TargetID:   r.TargetID,
+		Changes:    make([]*graphql1.AuditChange, len(r.Changes)),
+		CreatedAt:  r.CreatedAt,
+	}
+	for i, c := range r.Changes {
+		record.Changes[i] = &graphql1.AuditChange{
+			Field:  c.Field,
+			Before: auditValue(c.Before),
+			After:  auditValue(c.After),
+		}
+	}
+	if r.RequestID != "" {
+		record.RequestID = &r.RequestID
+	}
+	return record
+}
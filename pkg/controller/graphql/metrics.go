@@ -0,0 +1,31 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+)
+
+// ObserveRootFields is a field middleware recording the latency and errors of
+// root fields in metrics. Fields are labeled by their schema name, e.g.
+// "Query.listIoCs", not by the client-chosen operation name, to keep labels bounded.
+func ObserveRootFields(metrics interfaces.Metrics) graphql.FieldMiddleware {
+	return func(ctx context.Context, next graphql.Resolver) (any, error) {
+		fc := graphql.GetFieldContext(ctx)
+		if fc == nil {
+			return next(ctx)
+		}
+		switch fc.Object {
+		case "Query", "Mutation", "Subscription":
+		default:
+			return next(ctx)
+		}
+
+		start := time.Now()
+		res, err := next(ctx)
+		metrics.ObserveGraphQLField(fc.Object+"."+fc.Field.Name, time.Since(start), err)
+		return res, err
+	}
+}
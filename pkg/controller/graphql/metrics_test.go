@@ -0,0 +1,44 @@
+package graphql_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	gqlcontroller "github.com/secmon-lab/beehive/pkg/controller/graphql"
+	httpcontroller "github.com/secmon-lab/beehive/pkg/controller/http"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/service/auth"
+	"github.com/secmon-lab/beehive/pkg/service/metrics"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+func TestGraphQL_Metrics(t *testing.T) {
+	jwksURL, sign := newTokenSigner(t)
+	repo := memory.New()
+	prom := metrics.New()
+	resolver := gqlcontroller.NewResolver(repo, usecase.New(repo), usecase.NewFetchUseCase(repo, nil))
+	server := httpcontroller.New(resolver,
+		httpcontroller.WithAuthenticator(auth.NewJWT(jwksURL)),
+		httpcontroller.WithMetrics(prom))
+
+	viewer := sign("victor", model.RoleViewer)
+	resp, _ := executeGraphQLWithToken(t, server, viewer, `query Named { health listIoCs { total } }`, nil)
+	gt.N(t, len(resp.Errors)).Equal(0)
+	resp, _ = executeGraphQLWithToken(t, server, viewer, `mutation { deleteIoC(id: "x") }`, nil)
+	gt.True(t, isForbidden(resp))
+
+	// Metrics are served without credentials
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	gt.N(t, w.Code).Equal(http.StatusOK)
+	out := w.Body.String()
+	gt.S(t, out).Contains(`beehive_graphql_field_duration_seconds_count{field="Query.health"} 1`)
+	gt.S(t, out).Contains(`beehive_graphql_field_duration_seconds_count{field="Query.listIoCs"} 1`)
+	gt.S(t, out).Contains(`beehive_graphql_field_errors_total{field="Mutation.deleteIoC"} 1`)
+	// Nested fields and operation names are not labels
+	gt.S(t, out).NotContains(`IoCConnection`)
+	gt.S(t, out).NotContains(`Named`)
+}
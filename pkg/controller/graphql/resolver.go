@@ -1,8 +1,6 @@
 package graphql
 
 import (
-	"github.com/m-mizutani/goerr/v2"
-	"github.com/secmon-lab/beehive/pkg/cli/config"
 	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
 	"github.com/secmon-lab/beehive/pkg/domain/model"
 	"github.com/secmon-lab/beehive/pkg/usecase"
@@ -16,57 +14,56 @@ type Resolver struct {
 	repo         interfaces.Repository
 	uc           *usecase.UseCases
 	fetchUseCase *usecase.FetchUseCase
-	sourcesMap   map[string]model.Source
+	fetchJobs    *usecase.FetchJobUseCase
+	configStatus func() *model.ConfigStatus
 }
 
-func NewResolver(repo interfaces.Repository, uc *usecase.UseCases, fetchUseCase *usecase.FetchUseCase, sourcesConfigPath string) (*Resolver, error) {
-	// Initialize empty sources map
-	sourcesMap := make(map[string]model.Source)
+// ResolverOption configures Resolver
+type ResolverOption func(*Resolver)
 
-	// Load and cache sources configuration if path is provided
-	if sourcesConfigPath != "" {
-		cfg, err := config.LoadConfig(sourcesConfigPath)
-		if err != nil {
-			return nil, goerr.Wrap(err, "failed to load sources config")
-		}
-
-		// Add RSS sources
-		for id, src := range cfg.RSS {
-			sourcesMap[id] = model.Source{
-				Type:    model.SourceTypeRSS,
-				URL:     src.URL,
-				Tags:    ensureStringSlice(src.Tags.Strings()),
-				Enabled: !src.Disabled,
-				RSSConfig: &model.RSSConfig{
-					MaxArticles: src.MaxArticles,
-				},
-			}
-		}
+// WithConfigStatus sets the provider of the configStatus query
+func WithConfigStatus(status func() *model.ConfigStatus) ResolverOption {
+	return func(r *Resolver) {
+		r.configStatus = status
+	}
+}
 
-		// Add Feed sources
-		for id, src := range cfg.Feed {
-			sourcesMap[id] = model.Source{
-				Type:    model.SourceTypeFeed,
-				URL:     src.GetURL(),
-				Tags:    ensureStringSlice(src.Tags.Strings()),
-				Enabled: !src.Disabled,
-				FeedConfig: &model.FeedConfig{
-					Schema:   string(src.Schema),
-					MaxItems: src.MaxItems,
-				},
-			}
-		}
+// WithFetchJobs sets the runner of fetchSource jobs. By default a runner with
+// the default concurrency is created from the fetch use case.
+func WithFetchJobs(jobs *usecase.FetchJobUseCase) ResolverOption {
+	return func(r *Resolver) {
+		r.fetchJobs = jobs
 	}
+}
 
-	return &Resolver{
+// NewResolver creates a resolver. Sources are read from the repository, which
+// is seeded from the TOML config by the CLI.
+func NewResolver(repo interfaces.Repository, uc *usecase.UseCases, fetchUseCase *usecase.FetchUseCase, opts ...ResolverOption) *Resolver {
+	r := &Resolver{
 		repo:         repo,
 		uc:           uc,
 		fetchUseCase: fetchUseCase,
-		sourcesMap:   sourcesMap,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.fetchJobs == nil {
+		r.fetchJobs = usecase.NewFetchJobUseCase(fetchUseCase)
+	}
+	return r
 }
 
 // Repository returns the repository instance
 func (r *Resolver) Repository() interfaces.Repository {
 	return r.repo
 }
+
+// UseCases returns the use cases of the resolver, shared with routes outside GraphQL
+func (r *Resolver) UseCases() *usecase.UseCases {
+	return r.uc
+}
+
+// FetchUseCase returns the fetch use case of the resolver, checked for readiness
+func (r *Resolver) FetchUseCase() *usecase.FetchUseCase {
+	return r.fetchUseCase
+}
@@ -8,13 +8,60 @@ package graphql
 import (
 	"context"
 	"errors"
+	"time"
 
 	goerr "github.com/m-mizutani/goerr/v2"
 	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
 	"github.com/secmon-lab/beehive/pkg/domain/model"
 	graphql1 "github.com/secmon-lab/beehive/pkg/domain/model/graphql"
+	"github.com/secmon-lab/beehive/pkg/usecase"
 )
 
+// Ioc is the resolver for the ioc field.
+func (r *brandMatchResolver) Ioc(ctx context.Context, obj *graphql1.BrandMatch) (*graphql1.IoC, error) {
+	ioc, err := r.repo.GetIoC(ctx, obj.IocID)
+	if errors.Is(err, interfaces.ErrIoCNotFound) {
+		// The IoC may have been deleted after the match was recorded
+		return nil, nil
+	}
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get IoC", goerr.V("ioc_id", obj.IocID))
+	}
+
+	return toGraphQLIoC(ioc), nil
+}
+
+// StatusHistory is the resolver for the statusHistory field.
+func (r *ioCResolver) StatusHistory(ctx context.Context, obj *graphql1.IoC) ([]*graphql1.IoCStatusTransition, error) {
+	transitions, err := r.repo.ListStatusTransitions(ctx, obj.ID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list status transitions", goerr.V("ioc_id", obj.ID))
+	}
+
+	result := make([]*graphql1.IoCStatusTransition, len(transitions))
+	for i, tr := range transitions {
+		result[i] = toGraphQLIoCStatusTransition(tr)
+	}
+	return result, nil
+}
+
+// Report is the resolver for the report field.
+func (r *ioCResolver) Report(ctx context.Context, obj *graphql1.IoC) (*graphql1.Report, error) {
+	if obj.ReportID == nil {
+		return nil, nil
+	}
+
+	report, err := r.repo.GetReport(ctx, *obj.ReportID)
+	if errors.Is(err, interfaces.ErrReportNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get report", goerr.V("report_id", *obj.ReportID))
+	}
+
+	return toGraphQLReport(report), nil
+}
+
 // Noop is the resolver for the noop field.
 func (r *mutationResolver) Noop(ctx context.Context) (*bool, error) {
 	result := true
@@ -22,14 +69,238 @@ func (r *mutationResolver) Noop(ctx context.Context) (*bool, error) {
 }
 
 // FetchSource is the resolver for the fetchSource field.
-func (r *mutationResolver) FetchSource(ctx context.Context, sourceID string) (*graphql1.History, error) {
-	// Execute fetch for the specified source using cached sourcesMap
-	history, err := r.fetchUseCase.FetchSourceByID(ctx, r.sourcesMap, sourceID)
+func (r *mutationResolver) FetchSource(ctx context.Context, sourceID string) (*graphql1.Job, error) {
+	sources, err := r.uc.SourcesMap(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list sources")
+	}
+
+	job, err := r.fetchJobs.Start(ctx, sources, sourceID)
 	if err != nil {
 		return nil, goerr.Wrap(err, "failed to fetch source", goerr.V("source_id", sourceID))
 	}
 
-	return toGraphQLHistory(history), nil
+	return toGraphQLJob(job), nil
+}
+
+// CancelJob is the resolver for the cancelJob field.
+func (r *mutationResolver) CancelJob(ctx context.Context, id string) (*graphql1.Job, error) {
+	job, err := r.fetchJobs.CancelJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toGraphQLJob(job), nil
+}
+
+// CreateSource is the resolver for the createSource field.
+func (r *mutationResolver) CreateSource(ctx context.Context, input graphql1.CreateSourceInput) (*graphql1.Source, error) {
+	src, err := applySourceInput(&model.Source{
+		ID:      input.ID,
+		Type:    model.SourceType(input.Type),
+		Enabled: true,
+	}, sourceInput{
+		URL:         input.URL,
+		Description: input.Description,
+		Tags:        input.Tags,
+		Enabled:     input.Enabled,
+		TTL:         input.TTL,
+		Reliability: input.Reliability,
+		Extraction:  input.Extraction,
+		MaxArticles: input.MaxArticles,
+		Schema:      input.Schema,
+		MaxItems:    input.MaxItems,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := r.uc.CreateSource(ctx, src, model.ActorFrom(ctx))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create source", goerr.V("id", input.ID))
+	}
+	return toGraphQLSource(created), nil
+}
+
+// UpdateSource is the resolver for the updateSource field.
+func (r *mutationResolver) UpdateSource(ctx context.Context, id string, input graphql1.UpdateSourceInput) (*graphql1.Source, error) {
+	current, err := r.uc.GetSource(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := applySourceInput(current, sourceInput{
+		URL:         input.URL,
+		Description: input.Description,
+		Tags:        input.Tags,
+		TTL:         input.TTL,
+		Reliability: input.Reliability,
+		Extraction:  input.Extraction,
+		MaxArticles: input.MaxArticles,
+		Schema:      input.Schema,
+		MaxItems:    input.MaxItems,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := r.uc.UpdateSource(ctx, src, model.ActorFrom(ctx))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to update source", goerr.V("id", id))
+	}
+	return toGraphQLSource(updated), nil
+}
+
+// SetSourceEnabled is the resolver for the setSourceEnabled field.
+func (r *mutationResolver) SetSourceEnabled(ctx context.Context, id string, enabled bool) (*graphql1.Source, error) {
+	updated, err := r.uc.SetSourceEnabled(ctx, id, enabled, model.ActorFrom(ctx))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to set source enabled", goerr.V("id", id), goerr.V("enabled", enabled))
+	}
+	return toGraphQLSource(updated), nil
+}
+
+// DeleteSource is the resolver for the deleteSource field.
+func (r *mutationResolver) DeleteSource(ctx context.Context, id string) (bool, error) {
+	if err := r.uc.DeleteSource(ctx, id, model.ActorFrom(ctx)); err != nil {
+		return false, goerr.Wrap(err, "failed to delete source", goerr.V("id", id))
+	}
+	return true, nil
+}
+
+// CreateIoC is the resolver for the createIoC field.
+func (r *mutationResolver) CreateIoC(ctx context.Context, input graphql1.CreateIoCInput) (*graphql1.IoC, error) {
+	created, err := r.uc.CreateIoC(ctx, &usecase.CreateIoCInput{
+		Type:        model.IoCType(input.Type),
+		Value:       input.Value,
+		Description: ptrStringValue(input.Description),
+		Status:      model.IoCStatus(ptrStringValue(input.Status)),
+		SourceURL:   ptrStringValue(input.SourceURL),
+		Context:     ptrStringValue(input.Context),
+		Notes:       ptrStringValue(input.Notes),
+		Tags:        input.Tags,
+	})
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create IoC",
+			goerr.V("type", input.Type),
+			goerr.V("value", input.Value))
+	}
+
+	return toGraphQLIoC(created), nil
+}
+
+// UpdateIoC is the resolver for the updateIoC field.
+func (r *mutationResolver) UpdateIoC(ctx context.Context, id string, input graphql1.UpdateIoCInput) (*graphql1.IoC, error) {
+	var status *model.IoCStatus
+	if input.Status != nil {
+		s := model.IoCStatus(*input.Status)
+		status = &s
+	}
+
+	updated, err := r.uc.UpdateIoC(ctx, id, &usecase.UpdateIoCInput{
+		Description:   input.Description,
+		Status:        status,
+		Notes:         input.Notes,
+		Tags:          input.Tags,
+		Reason:        ptrStringValue(input.Reason),
+		ClearOverride: input.ClearOverride != nil && *input.ClearOverride,
+	})
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to update IoC", goerr.V("id", id))
+	}
+
+	return toGraphQLIoC(updated), nil
+}
+
+// DeleteIoC is the resolver for the deleteIoC field.
+func (r *mutationResolver) DeleteIoC(ctx context.Context, id string) (bool, error) {
+	if err := r.uc.DeleteIoC(ctx, id); err != nil {
+		return false, goerr.Wrap(err, "failed to delete IoC", goerr.V("id", id))
+	}
+
+	return true, nil
+}
+
+// CreateWatchlist is the resolver for the createWatchlist field.
+func (r *mutationResolver) CreateWatchlist(ctx context.Context, input graphql1.CreateWatchlistInput) (*graphql1.Watchlist, error) {
+	created, err := r.uc.CreateWatchlist(ctx, &usecase.CreateWatchlistInput{
+		Name:        input.Name,
+		Description: ptrStringValue(input.Description),
+		Patterns:    toModelWatchPatterns(input.Patterns),
+		Enabled:     input.Enabled,
+	})
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create watchlist", goerr.V("name", input.Name))
+	}
+
+	return toGraphQLWatchlist(created), nil
+}
+
+// UpdateWatchlist is the resolver for the updateWatchlist field.
+func (r *mutationResolver) UpdateWatchlist(ctx context.Context, id string, input graphql1.UpdateWatchlistInput) (*graphql1.Watchlist, error) {
+	updated, err := r.uc.UpdateWatchlist(ctx, id, &usecase.UpdateWatchlistInput{
+		Name:        input.Name,
+		Description: input.Description,
+		Patterns:    toModelWatchPatterns(input.Patterns),
+		Enabled:     input.Enabled,
+	})
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to update watchlist", goerr.V("id", id))
+	}
+
+	return toGraphQLWatchlist(updated), nil
+}
+
+// DeleteWatchlist is the resolver for the deleteWatchlist field.
+func (r *mutationResolver) DeleteWatchlist(ctx context.Context, id string) (bool, error) {
+	if err := r.uc.DeleteWatchlist(ctx, id); err != nil {
+		return false, goerr.Wrap(err, "failed to delete watchlist", goerr.V("id", id))
+	}
+
+	return true, nil
+}
+
+// AcknowledgeWatchlistHits is the resolver for the acknowledgeWatchlistHits field.
+func (r *mutationResolver) AcknowledgeWatchlistHits(ctx context.Context, ids []string) ([]*graphql1.WatchlistHit, error) {
+	hits, err := r.uc.AcknowledgeWatchlistHits(ctx, ids, model.ActorFrom(ctx))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to acknowledge watchlist hits", goerr.V("ids", ids))
+	}
+
+	result := make([]*graphql1.WatchlistHit, len(hits))
+	for i, hit := range hits {
+		result[i] = toGraphQLWatchlistHit(hit)
+	}
+	return result, nil
+}
+
+// CreateAPIToken is the resolver for the createAPIToken field.
+func (r *mutationResolver) CreateAPIToken(ctx context.Context, input graphql1.CreateAPITokenInput) (*graphql1.CreatedAPIToken, error) {
+	var expiresAt time.Time
+	if input.ExpiresAt != nil {
+		expiresAt = *input.ExpiresAt
+	}
+	token, secret, err := r.uc.CreateAPIToken(ctx, &usecase.CreateAPITokenInput{
+		Name:      input.Name,
+		Scopes:    input.Scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create API token", goerr.V("name", input.Name))
+	}
+
+	return &graphql1.CreatedAPIToken{
+		Token:    secret,
+		APIToken: toGraphQLAPIToken(token),
+	}, nil
+}
+
+// RevokeAPIToken is the resolver for the revokeAPIToken field.
+func (r *mutationResolver) RevokeAPIToken(ctx context.Context, id string) (*graphql1.APIToken, error) {
+	token, err := r.uc.RevokeAPIToken(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to revoke API token", goerr.V("id", id))
+	}
+	return toGraphQLAPIToken(token), nil
 }
 
 // Health is the resolver for the health field.
@@ -37,16 +308,27 @@ func (r *queryResolver) Health(ctx context.Context) (string, error) {
 	return "OK", nil
 }
 
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*graphql1.Principal, error) {
+	p := model.PrincipalFrom(ctx)
+	if p == nil {
+		return nil, errForbidden()
+	}
+	return toGraphQLPrincipal(p), nil
+}
+
 // ListIoCs is the resolver for the listIoCs field.
 func (r *queryResolver) ListIoCs(ctx context.Context, options *graphql1.IoCListOptions) (*graphql1.IoCConnection, error) {
 	// Convert GraphQL input to domain model
 	var opts *model.IoCListOptions
 	if options != nil {
 		opts = &model.IoCListOptions{
-			Offset:    ptrIntValue(options.Offset),
-			Limit:     ptrIntValue(options.Limit),
-			SortField: toModelSortField(options.SortField),
-			SortOrder: toModelSortOrder(options.SortOrder),
+			Offset:        ptrIntValue(options.Offset),
+			Limit:         ptrIntValue(options.Limit),
+			SortField:     toModelSortField(options.SortField),
+			SortOrder:     toModelSortOrder(options.SortOrder),
+			MinConfidence: ptrIntValue(options.MinConfidence),
+			Types:         toModelIoCTypes(options.Types),
 		}
 	}
 
@@ -78,68 +360,29 @@ func (r *queryResolver) GetIoC(ctx context.Context, id string) (*graphql1.IoC, e
 
 // ListSources is the resolver for the listSources field.
 func (r *queryResolver) ListSources(ctx context.Context) ([]*graphql1.Source, error) {
-	// Get loaders from context
-	loaders := LoadersFromContext(ctx)
-
-	// Collect all source IDs and create GraphQL sources
-	var result []*graphql1.Source
-	type sourceWithThunk struct {
-		gqlSrc *graphql1.Source
-		thunk  func() (*model.SourceState, error)
-	}
-	sourceThunks := make([]sourceWithThunk, 0, len(r.sourcesMap))
-
-	// Iterate over cached sourcesMap
-	for id, src := range r.sourcesMap {
-		var srcType string
-		var schema *string
-		var schemaDescription *string
-		var description *string
-
-		if src.Type == model.SourceTypeRSS {
-			srcType = "rss"
-		} else {
-			srcType = "feed"
-			if src.FeedConfig != nil {
-				schema = &src.FeedConfig.Schema
-				if desc := getSchemaDescription(src.FeedConfig.Schema); desc != "" {
-					schemaDescription = &desc
-				}
-			}
-		}
-
-		if src.Description != "" {
-			description = &src.Description
-		}
-
-		gqlSrc := &graphql1.Source{
-			ID:                id,
-			Type:              srcType,
-			URL:               src.URL,
-			Schema:            schema,
-			SchemaDescription: schemaDescription,
-			Description:       description,
-			Tags:              src.Tags,
-			Enabled:           src.Enabled,
-		}
+	sources, err := r.uc.ListSources(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-		// Queue data loader request (doesn't execute yet)
-		thunk := loaders.SourceStateLoader.Load(ctx, id)
-		sourceThunks = append(sourceThunks, sourceWithThunk{gqlSrc: gqlSrc, thunk: thunk})
+	// Queue data loader requests first so that states are batch loaded
+	loaders := LoadersFromContext(ctx)
+	thunks := make([]func() (*model.SourceState, error), len(sources))
+	for i, src := range sources {
+		thunks[i] = loaders.SourceStateLoader.Load(ctx, src.ID)
 	}
 
-	// Execute all thunks (triggers batch loading)
-	for _, st := range sourceThunks {
-		state, err := st.thunk()
+	result := make([]*graphql1.Source, len(sources))
+	for i, src := range sources {
+		gqlSrc := toGraphQLSource(src)
+		state, err := thunks[i]()
 		if err != nil && !errors.Is(err, interfaces.ErrSourceStateNotFound) {
-			return nil, goerr.Wrap(err, "failed to get source state", goerr.V("source_id", st.gqlSrc.ID))
+			return nil, goerr.Wrap(err, "failed to get source state", goerr.V("source_id", src.ID))
 		}
-
 		if state != nil {
-			st.gqlSrc.State = toGraphQLSourceState(state)
+			gqlSrc.State = toGraphQLSourceState(state)
 		}
-
-		result = append(result, st.gqlSrc)
+		result[i] = gqlSrc
 	}
 
 	return result, nil
@@ -147,43 +390,11 @@ func (r *queryResolver) ListSources(ctx context.Context) ([]*graphql1.Source, er
 
 // GetSource is the resolver for the getSource field.
 func (r *queryResolver) GetSource(ctx context.Context, id string) (*graphql1.Source, error) {
-	// Look up source in cached sourcesMap
-	src, found := r.sourcesMap[id]
-	if !found {
-		return nil, goerr.New("source not found", goerr.V("id", id))
-	}
-
-	var srcType string
-	var schema *string
-	var schemaDescription *string
-	var description *string
-
-	if src.Type == model.SourceTypeRSS {
-		srcType = "rss"
-	} else {
-		srcType = "feed"
-		if src.FeedConfig != nil {
-			schema = &src.FeedConfig.Schema
-			if desc := getSchemaDescription(src.FeedConfig.Schema); desc != "" {
-				schemaDescription = &desc
-			}
-		}
-	}
-
-	if src.Description != "" {
-		description = &src.Description
-	}
-
-	gqlSrc := &graphql1.Source{
-		ID:                id,
-		Type:              srcType,
-		URL:               src.URL,
-		Schema:            schema,
-		SchemaDescription: schemaDescription,
-		Description:       description,
-		Tags:              src.Tags,
-		Enabled:           src.Enabled,
+	src, err := r.uc.GetSource(ctx, id)
+	if err != nil {
+		return nil, err
 	}
+	gqlSrc := toGraphQLSource(src)
 
 	// Get source state from repository
 	state, err := r.repo.GetState(ctx, id)
@@ -198,6 +409,72 @@ func (r *queryResolver) GetSource(ctx context.Context, id string) (*graphql1.Sou
 	return gqlSrc, nil
 }
 
+// ListSourceChanges is the resolver for the listSourceChanges field.
+func (r *queryResolver) ListSourceChanges(ctx context.Context, sourceID *string, limit *int) ([]*graphql1.SourceChange, error) {
+	changes, err := r.uc.ListSourceChanges(ctx, ptrStringValue(sourceID), ptrIntValue(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*graphql1.SourceChange, len(changes))
+	for i, c := range changes {
+		result[i] = toGraphQLSourceChange(c)
+	}
+	return result, nil
+}
+
+// ConfigStatus is the resolver for the configStatus field.
+func (r *queryResolver) ConfigStatus(ctx context.Context) (*graphql1.ConfigStatus, error) {
+	if r.configStatus == nil {
+		return nil, nil
+	}
+	status := r.configStatus()
+	if status == nil {
+		return nil, nil
+	}
+	return toGraphQLConfigStatus(status), nil
+}
+
+// ListAPITokens is the resolver for the listAPITokens field.
+func (r *queryResolver) ListAPITokens(ctx context.Context) ([]*graphql1.APIToken, error) {
+	tokens, err := r.uc.ListAPITokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*graphql1.APIToken, len(tokens))
+	for i, token := range tokens {
+		result[i] = toGraphQLAPIToken(token)
+	}
+	return result, nil
+}
+
+// ListAuditRecords is the resolver for the listAuditRecords field.
+func (r *queryResolver) ListAuditRecords(ctx context.Context, filter *graphql1.AuditLogFilter) ([]*graphql1.AuditRecord, error) {
+	records, err := r.uc.ListAuditRecords(ctx, toModelAuditFilter(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*graphql1.AuditRecord, len(records))
+	for i, record := range records {
+		result[i] = toGraphQLAuditRecord(record)
+	}
+	return result, nil
+}
+
+// Job is the resolver for the job field.
+func (r *queryResolver) Job(ctx context.Context, id string) (*graphql1.Job, error) {
+	job, err := r.fetchJobs.GetJob(ctx, id)
+	if errors.Is(err, usecase.ErrJobNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toGraphQLJob(job), nil
+}
+
 // ListHistories is the resolver for the listHistories field.
 func (r *queryResolver) ListHistories(ctx context.Context, sourceID string, limit *int, offset *int) (*graphql1.HistoryConnection, error) {
 	actualLimit := 0
@@ -241,11 +518,204 @@ func (r *queryResolver) GetHistory(ctx context.Context, sourceID string, id stri
 	return toGraphQLHistory(history), nil
 }
 
+// ListWatchlists is the resolver for the listWatchlists field.
+func (r *queryResolver) ListWatchlists(ctx context.Context) ([]*graphql1.Watchlist, error) {
+	watchlists, err := r.repo.ListWatchlists(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list watchlists")
+	}
+
+	result := make([]*graphql1.Watchlist, len(watchlists))
+	for i, w := range watchlists {
+		result[i] = toGraphQLWatchlist(w)
+	}
+	return result, nil
+}
+
+// GetWatchlist is the resolver for the getWatchlist field.
+func (r *queryResolver) GetWatchlist(ctx context.Context, id string) (*graphql1.Watchlist, error) {
+	w, err := r.repo.GetWatchlist(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get watchlist", goerr.V("id", id))
+	}
+
+	return toGraphQLWatchlist(w), nil
+}
+
+// ListWatchlistHits is the resolver for the listWatchlistHits field.
+func (r *queryResolver) ListWatchlistHits(ctx context.Context, options *graphql1.WatchlistHitListOptions) (*graphql1.WatchlistHitConnection, error) {
+	opts := &model.WatchlistHitListOptions{}
+	if options != nil {
+		opts.WatchlistID = ptrStringValue(options.WatchlistID)
+		opts.Acknowledged = options.Acknowledged
+		opts.Offset = ptrIntValue(options.Offset)
+		opts.Limit = ptrIntValue(options.Limit)
+	}
+
+	conn, err := r.repo.ListWatchlistHits(ctx, opts)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list watchlist hits",
+			goerr.V("watchlist_id", opts.WatchlistID))
+	}
+
+	items := make([]*graphql1.WatchlistHit, len(conn.Items))
+	for i, hit := range conn.Items {
+		items[i] = toGraphQLWatchlistHit(hit)
+	}
+
+	return &graphql1.WatchlistHitConnection{
+		Items: items,
+		Total: conn.Total,
+	}, nil
+}
+
+// ProtectedDomains is the resolver for the protectedDomains field.
+func (r *queryResolver) ProtectedDomains(ctx context.Context) ([]string, error) {
+	return ensureStringSlice(r.fetchUseCase.ProtectedDomains()), nil
+}
+
+// ListBrandMatches is the resolver for the listBrandMatches field.
+func (r *queryResolver) ListBrandMatches(ctx context.Context, options *graphql1.BrandMatchListOptions) (*graphql1.BrandMatchConnection, error) {
+	opts := &model.BrandMatchListOptions{}
+	if options != nil {
+		opts.ProtectedDomain = ptrStringValue(options.ProtectedDomain)
+		opts.Technique = model.LookalikeTechnique(ptrStringValue(options.Technique))
+		opts.Offset = ptrIntValue(options.Offset)
+		opts.Limit = ptrIntValue(options.Limit)
+	}
+	if opts.Technique != "" && !opts.Technique.IsValid() {
+		return nil, goerr.New("unknown lookalike technique", goerr.V("technique", opts.Technique))
+	}
+
+	conn, err := r.repo.ListBrandMatches(ctx, opts)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list brand matches",
+			goerr.V("protected_domain", opts.ProtectedDomain))
+	}
+
+	items := make([]*graphql1.BrandMatch, len(conn.Items))
+	for i, m := range conn.Items {
+		items[i] = toGraphQLBrandMatch(m)
+	}
+
+	return &graphql1.BrandMatchConnection{
+		Items: items,
+		Total: conn.Total,
+	}, nil
+}
+
+// ListReports is the resolver for the listReports field.
+func (r *queryResolver) ListReports(ctx context.Context, options *graphql1.ReportListOptions) (*graphql1.ReportConnection, error) {
+	opts := &model.ReportListOptions{}
+	if options != nil {
+		opts.SourceID = ptrStringValue(options.SourceID)
+		opts.Offset = ptrIntValue(options.Offset)
+		opts.Limit = ptrIntValue(options.Limit)
+	}
+
+	conn, err := r.repo.ListReports(ctx, opts)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list reports", goerr.V("source_id", opts.SourceID))
+	}
+
+	items := make([]*graphql1.Report, len(conn.Items))
+	for i, report := range conn.Items {
+		items[i] = toGraphQLReport(report)
+	}
+
+	return &graphql1.ReportConnection{
+		Items: items,
+		Total: conn.Total,
+	}, nil
+}
+
+// GetReport is the resolver for the getReport field.
+func (r *queryResolver) GetReport(ctx context.Context, id string) (*graphql1.Report, error) {
+	report, err := r.repo.GetReport(ctx, id)
+	if errors.Is(err, interfaces.ErrReportNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get report", goerr.V("id", id))
+	}
+
+	return toGraphQLReport(report), nil
+}
+
+// Iocs is the resolver for the iocs field.
+func (r *reportResolver) Iocs(ctx context.Context, obj *graphql1.Report) ([]*graphql1.IoC, error) {
+	iocs, err := r.repo.ListIoCsByReport(ctx, obj.ID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list IoCs of report", goerr.V("report_id", obj.ID))
+	}
+
+	result := make([]*graphql1.IoC, len(iocs))
+	for i, ioc := range iocs {
+		result[i] = toGraphQLIoC(ioc)
+	}
+	return result, nil
+}
+
+// JobProgress is the resolver for the jobProgress field.
+func (r *subscriptionResolver) JobProgress(ctx context.Context, id string) (<-chan *graphql1.Job, error) {
+	jobs, err := r.fetchJobs.SubscribeJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *graphql1.Job)
+	go func() {
+		defer close(ch)
+		for job := range jobs {
+			select {
+			case ch <- toGraphQLJob(job):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// Ioc is the resolver for the ioc field.
+func (r *watchlistHitResolver) Ioc(ctx context.Context, obj *graphql1.WatchlistHit) (*graphql1.IoC, error) {
+	ioc, err := r.repo.GetIoC(ctx, obj.IocID)
+	if errors.Is(err, interfaces.ErrIoCNotFound) {
+		// The IoC may have been deleted after the hit was recorded
+		return nil, nil
+	}
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get IoC", goerr.V("ioc_id", obj.IocID))
+	}
+
+	return toGraphQLIoC(ioc), nil
+}
+
+// BrandMatch returns BrandMatchResolver implementation.
+func (r *Resolver) BrandMatch() BrandMatchResolver { return &brandMatchResolver{r} }
+
+// IoC returns IoCResolver implementation.
+func (r *Resolver) IoC() IoCResolver { return &ioCResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Report returns ReportResolver implementation.
+func (r *Resolver) Report() ReportResolver { return &reportResolver{r} }
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+// WatchlistHit returns WatchlistHitResolver implementation.
+func (r *Resolver) WatchlistHit() WatchlistHitResolver { return &watchlistHitResolver{r} }
+
+type brandMatchResolver struct{ *Resolver }
+type ioCResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type reportResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+type watchlistHitResolver struct{ *Resolver }
@@ -0,0 +1,23 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/secmon-lab/beehive/pkg/utils/tracing"
+)
+
+// TraceResolvers is a field middleware starting a span for each field backed
+// by a resolver, e.g. "graphql.Query.listIoCs" or "graphql.Source.state".
+// Plain struct fields are not traced to keep traces small.
+func TraceResolvers(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := tracing.Start(ctx, "graphql."+fc.Object+"."+fc.Field.Name)
+	res, err := next(ctx)
+	tracing.End(span, err)
+	return res, err
+}
@@ -0,0 +1,52 @@
+package graphql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	gqlcontroller "github.com/secmon-lab/beehive/pkg/controller/graphql"
+	httpcontroller "github.com/secmon-lab/beehive/pkg/controller/http"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/service/auth"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestGraphQL_Tracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	jwksURL, sign := newTokenSigner(t)
+	repo := memory.New()
+	resolver := gqlcontroller.NewResolver(repo, usecase.New(repo), usecase.NewFetchUseCase(repo, nil))
+	server := httpcontroller.New(resolver, httpcontroller.WithAuthenticator(auth.NewJWT(jwksURL)))
+
+	resp, _ := executeGraphQLWithToken(t, server, sign("victor", model.RoleViewer), `{ health listIoCs { total } }`, nil)
+	gt.N(t, len(resp.Errors)).Equal(0)
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	request, ok := spans["POST /graphql"]
+	gt.True(t, ok)
+	field, ok := spans["graphql.Query.listIoCs"]
+	gt.True(t, ok)
+	gt.Equal(t, field.Parent.SpanID(), request.SpanContext.SpanID())
+	_, ok = spans["graphql.Query.health"]
+	gt.True(t, ok)
+
+	// Struct fields without a resolver are not traced
+	_, ok = spans["graphql.IoCConnection.total"]
+	gt.False(t, ok)
+}
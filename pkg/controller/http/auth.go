@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/errutil"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+)
+
+// openAccess is the authenticator of servers without authentication: every
+// request is an anonymous admin
+type openAccess struct{}
+
+func (openAccess) Authenticate(r *http.Request) (*model.Principal, error) {
+	return &model.Principal{
+		Subject: "anonymous",
+		Role:    model.RoleAdmin,
+		Method:  model.AuthMethodAnonymous,
+	}, nil
+}
+
+// authenticate sets the principal of requests and rejects requests without
+// valid credentials
+func authenticate(authn interfaces.Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, err := authn.Authenticate(r)
+			switch {
+			case err == nil:
+				next.ServeHTTP(w, r.WithContext(model.WithPrincipal(r.Context(), p)))
+			case errors.Is(err, interfaces.ErrNoCredentials) && isWebSocketUpgrade(r):
+				// Browsers cannot set headers on websockets; the credentials
+				// of the connection_init message are checked instead
+				next.ServeHTTP(w, r)
+			case errors.Is(err, interfaces.ErrNoCredentials), errors.Is(err, interfaces.ErrInvalidCredentials):
+				logging.From(r.Context()).Info("authentication failed",
+					"path", r.URL.Path,
+					"remote", r.RemoteAddr,
+					"error", err)
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			default:
+				errutil.Handle(r.Context(), err, "failed to authenticate request")
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		})
+	}
+}
+
+// websocketInit authenticates websocket connections opened without
+// credentials by the Authorization value of the connection_init payload
+func websocketInit(authn interfaces.Authenticator) transport.WebsocketInitFunc {
+	return func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		if model.PrincipalFrom(ctx) != nil {
+			return ctx, nil, nil
+		}
+
+		r, err := http.NewRequestWithContext(ctx, http.MethodGet, "/graphql", nil)
+		if err != nil {
+			return ctx, nil, goerr.Wrap(err, "failed to create request")
+		}
+		if authorization := payload.Authorization(); authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+
+		p, err := authn.Authenticate(r)
+		if err != nil {
+			logging.From(ctx).Info("websocket authentication failed", "error", err)
+			return ctx, nil, goerr.New("unauthorized")
+		}
+		return model.WithPrincipal(ctx, p), nil, nil
+	}
+}
+
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
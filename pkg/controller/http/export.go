@@ -0,0 +1,117 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/domain/types"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+	"github.com/secmon-lab/beehive/pkg/utils/errutil"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+)
+
+// exportedIoC is an IoC of a JSON export
+type exportedIoC struct {
+	Type        string     `json:"type"`
+	Value       string     `json:"value"`
+	SourceID    string     `json:"source_id"`
+	Confidence  int        `json:"confidence"`
+	FirstSeenAt time.Time  `json:"first_seen_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// exportHandler serves the active IoCs of a tag for firewalls and other
+// consumers: one value per line, or JSON with format=json. The type query
+// parameter, which may be repeated, restricts the IoC types and
+// min_confidence omits IoCs with a lower confidence score.
+func exportHandler(uc *usecase.UseCases) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		tag := chi.URLParam(r, "tag")
+		if _, err := types.NewTag(tag); err != nil {
+			http.Error(w, "invalid tag", http.StatusBadRequest)
+			return
+		}
+
+		p := model.PrincipalFrom(ctx)
+		if p == nil || !p.CanExport(tag) {
+			var subject string
+			if p != nil {
+				subject = p.Subject
+			}
+			logging.From(ctx).Info("access denied", "path", r.URL.Path, "subject", subject)
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		query := r.URL.Query()
+		var iocTypes []model.IoCType
+		for _, t := range query["type"] {
+			iocType := model.IoCType(t)
+			if !iocType.IsValid() {
+				http.Error(w, "invalid type: "+t, http.StatusBadRequest)
+				return
+			}
+			iocTypes = append(iocTypes, iocType)
+		}
+		var minConfidence int
+		if raw := query.Get("min_confidence"); raw != "" {
+			v, err := strconv.Atoi(raw)
+			if err != nil || v < 0 || v > 100 {
+				http.Error(w, "min_confidence must be an integer between 0 and 100", http.StatusBadRequest)
+				return
+			}
+			minConfidence = v
+		}
+		format := query.Get("format")
+		if format != "" && format != "text" && format != "json" {
+			http.Error(w, "format must be text or json", http.StatusBadRequest)
+			return
+		}
+
+		iocs, err := uc.ExportIoCs(ctx, tag, iocTypes, minConfidence)
+		if err != nil {
+			errutil.Handle(ctx, err, "failed to export IoCs")
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		if format == "json" {
+			result := make([]exportedIoC, len(iocs))
+			for i, ioc := range iocs {
+				result[i] = exportedIoC{
+					Type:        string(ioc.Type),
+					Value:       ioc.Value,
+					SourceID:    ioc.SourceID,
+					Confidence:  ioc.Confidence,
+					FirstSeenAt: ioc.FirstSeenAt,
+					UpdatedAt:   ioc.UpdatedAt,
+				}
+				if !ioc.ExpiresAt.IsZero() {
+					result[i].ExpiresAt = &ioc.ExpiresAt
+				}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(result); err != nil {
+				errutil.Handle(ctx, err, "failed to write export")
+			}
+			return
+		}
+
+		var b strings.Builder
+		for _, ioc := range iocs {
+			b.WriteString(ioc.Value)
+			b.WriteByte('\n')
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if _, err := w.Write([]byte(b.String())); err != nil {
+			errutil.Handle(ctx, err, "failed to write export")
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	gqlcontroller "github.com/secmon-lab/beehive/pkg/controller/graphql"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/errutil"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+)
+
+const (
+	// DefaultFetchInterval is the expected interval between fetches of a
+	// source reported by /status
+	DefaultFetchInterval = 24 * time.Hour
+
+	// readinessTimeout bounds the dependency checks of a readiness probe
+	readinessTimeout = 5 * time.Second
+
+	statusOK          = "ok"
+	statusError       = "error"
+	statusUnavailable = "unavailable"
+	statusStale       = "stale"
+)
+
+var errFrontendNotFound = goerr.New("frontend is not embedded, index.html not found in frontend dist")
+
+// readinessCheck checks a dependency required to serve requests
+type readinessCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// checkResult is the outcome of a readiness check. Error details are logged
+// rather than returned, since the probe is served without authentication.
+type checkResult struct {
+	Status string `json:"status"`
+}
+
+type readinessResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks"`
+}
+
+type sourceStatus struct {
+	SourceID      string     `json:"source_id"`
+	Type          string     `json:"type"`
+	LastFetchedAt *time.Time `json:"last_fetched_at,omitempty"`
+	LastStatus    string     `json:"last_status,omitempty"`
+	Stale         bool       `json:"stale"`
+	Reason        string     `json:"reason,omitempty"`
+}
+
+// statusResponse is the ingestion status. Source IDs and failure reasons are
+// only returned to authenticated requests.
+type statusResponse struct {
+	Status        string `json:"status"`
+	FetchInterval string `json:"fetch_interval"`
+	SourceCount   int    `json:"source_count"`
+	StaleCount    int    `json:"stale_count"`
+	*statusDetails
+}
+
+type statusDetails struct {
+	StaleSources []string       `json:"stale_sources"`
+	Sources      []sourceStatus `json:"sources"`
+}
+
+// healthzHandler reports that the process is alive without checking dependencies
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(r.Context(), w, http.StatusOK, map[string]string{"status": statusOK})
+}
+
+// readinessChecks returns the checks of the repository, the LLM configuration
+// of the enabled sources and the embedded frontend
+func readinessChecks(resolver *gqlcontroller.Resolver, frontend bool) []readinessCheck {
+	uc := resolver.UseCases()
+	checks := []readinessCheck{
+		{name: "repository", check: uc.CheckRepository},
+	}
+	if fetchUC := resolver.FetchUseCase(); fetchUC != nil {
+		checks = append(checks, readinessCheck{name: "llm", check: func(ctx context.Context) error {
+			sources, err := uc.SourcesMap(ctx)
+			if err != nil {
+				return err
+			}
+			return fetchUC.CheckLLM(sources)
+		}})
+	}
+	checks = append(checks, readinessCheck{name: "frontend", check: func(context.Context) error {
+		if !frontend {
+			return errFrontendNotFound
+		}
+		return nil
+	}})
+	return checks
+}
+
+// readyzHandler runs the readiness checks and responds with 503 if any fails
+func readyzHandler(checks []readinessCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+		defer cancel()
+
+		resp := readinessResponse{Status: statusOK, Checks: make(map[string]checkResult, len(checks))}
+		for _, c := range checks {
+			if err := c.check(ctx); err != nil {
+				logging.From(ctx).Warn("readiness check failed", "check", c.name, "error", err)
+				resp.Status = statusUnavailable
+				resp.Checks[c.name] = checkResult{Status: statusError}
+				continue
+			}
+			resp.Checks[c.name] = checkResult{Status: statusOK}
+		}
+
+		code := http.StatusOK
+		if resp.Status != statusOK {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(ctx, w, code, resp)
+	}
+}
+
+// statusHandler reports the ingestion status of the enabled sources and
+// responds with 503 if any source is stale, so that uptime monitors can alert
+// on sources that silently stopped being fetched. Requests without valid
+// credentials only get the counts of sources and stale sources.
+func statusHandler(resolver *gqlcontroller.Resolver, authn interfaces.Authenticator, interval time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		statuses, err := resolver.UseCases().SourceStatuses(ctx, interval)
+		if err != nil {
+			errutil.Handle(ctx, err, "failed to get source statuses")
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		details := &statusDetails{
+			StaleSources: []string{},
+			Sources:      make([]sourceStatus, len(statuses)),
+		}
+		for i, s := range statuses {
+			details.Sources[i] = sourceStatus{
+				SourceID:   s.SourceID,
+				Type:       string(s.SourceType),
+				LastStatus: s.LastStatus,
+				Stale:      s.Stale,
+				Reason:     s.Reason,
+			}
+			if !s.LastFetchedAt.IsZero() {
+				details.Sources[i].LastFetchedAt = &s.LastFetchedAt
+			}
+			if s.Stale {
+				details.StaleSources = append(details.StaleSources, s.SourceID)
+			}
+		}
+		sort.Strings(details.StaleSources)
+
+		resp := statusResponse{
+			Status:        statusOK,
+			FetchInterval: interval.String(),
+			SourceCount:   len(details.Sources),
+			StaleCount:    len(details.StaleSources),
+		}
+		if p, err := authn.Authenticate(r); err == nil && p.Role.Includes(model.RoleViewer) {
+			resp.statusDetails = details
+		}
+
+		code := http.StatusOK
+		if resp.StaleCount > 0 {
+			resp.Status = statusStale
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(ctx, w, code, resp)
+	}
+}
+
+func writeJSON(ctx context.Context, w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		errutil.Handle(ctx, err, "failed to write response")
+	}
+}
@@ -9,22 +9,34 @@ import (
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/m-mizutani/goerr/v2"
 	"github.com/secmon-lab/beehive/frontend"
 	gqlcontroller "github.com/secmon-lab/beehive/pkg/controller/graphql"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/service/metrics"
 	"github.com/secmon-lab/beehive/pkg/utils/errutil"
 	"github.com/secmon-lab/beehive/pkg/utils/logging"
 	"github.com/secmon-lab/beehive/pkg/utils/safe"
+	"github.com/secmon-lab/beehive/pkg/utils/tracing"
+	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type Server struct {
 	router         *chi.Mux
 	gqlResolver    *gqlcontroller.Resolver
 	enableGraphiQL bool
+	authenticator  interfaces.Authenticator
+	metrics        *metrics.Prometheus
+	fetchInterval  time.Duration
 }
 
 type Options func(*Server)
@@ -35,6 +47,30 @@ func WithGraphiQL(enabled bool) Options {
 	}
 }
 
+// WithAuthenticator sets the authenticator of GraphQL and export requests. Without it,
+// every request is served as an anonymous admin.
+func WithAuthenticator(authn interfaces.Authenticator) Options {
+	return func(s *Server) {
+		s.authenticator = authn
+	}
+}
+
+// WithMetrics serves metrics at /metrics and records the latency of GraphQL
+// root fields in them
+func WithMetrics(m *metrics.Prometheus) Options {
+	return func(s *Server) {
+		s.metrics = m
+	}
+}
+
+// WithFetchInterval sets the expected interval between fetches of a source.
+// /status reports sources not fetched within twice the interval as stale.
+func WithFetchInterval(interval time.Duration) Options {
+	return func(s *Server) {
+		s.fetchInterval = interval
+	}
+}
+
 func New(gqlResolver *gqlcontroller.Resolver, opts ...Options) *Server {
 	r := chi.NewRouter()
 
@@ -42,45 +78,74 @@ func New(gqlResolver *gqlcontroller.Resolver, opts ...Options) *Server {
 		router:         r,
 		gqlResolver:    gqlResolver,
 		enableGraphiQL: false,
+		authenticator:  openAccess{},
+		fetchInterval:  DefaultFetchInterval,
 	}
 	for _, opt := range opts {
 		opt(s)
 	}
 
 	// Middleware
+	r.Use(traceRequests)
 	r.Use(middleware.RequestID)
+	r.Use(requestIDContext)
 	r.Use(accessLogger)
 	r.Use(middleware.Recoverer)
 
-	// GraphQL endpoint (must be registered before catch-all route)
-	gqlHandler := graphqlHandler(gqlResolver)
-	r.Route("/graphql", func(r chi.Router) {
-		// Add data loader middleware for GraphQL requests
-		r.Use(dataLoaderMiddleware(gqlResolver))
-		r.Post("/", gqlHandler.ServeHTTP)
-		r.Get("/", gqlHandler.ServeHTTP) // Support GET for introspection
+	// API routes, authenticated ahead of the handlers (must be registered
+	// before catch-all route)
+	gqlHandler := graphqlHandler(gqlResolver, s.authenticator, s.metrics)
+	r.Group(func(r chi.Router) {
+		r.Use(authenticate(s.authenticator))
+
+		r.Route("/graphql", func(r chi.Router) {
+			// Add data loader middleware for GraphQL requests
+			r.Use(dataLoaderMiddleware(gqlResolver))
+			r.Post("/", gqlHandler.ServeHTTP)
+			r.Get("/", gqlHandler.ServeHTTP) // Support GET for introspection
+		})
+
+		// Blocklists of tagged IoCs for firewalls and other consumers
+		r.Get("/api/export/{tag}", exportHandler(gqlResolver.UseCases()))
 	})
 
+	// Probes of orchestrators and uptime monitors, served without credentials.
+	// /status returns the details of sources to authenticated requests only.
+	staticFS, frontendFound := frontendFS()
+	r.Get("/healthz", healthzHandler)
+	r.Get("/readyz", readyzHandler(readinessChecks(gqlResolver, frontendFound)))
+	r.Get("/status", statusHandler(gqlResolver, s.authenticator, s.fetchInterval))
+
+	// Prometheus metrics, scraped without credentials like the playground
+	if s.metrics != nil {
+		r.Get("/metrics", s.metrics.Handler().ServeHTTP)
+	}
+
 	// GraphiQL playground
 	if s.enableGraphiQL {
 		r.Get("/graphiql", playground.Handler("GraphQL playground", "/graphql").ServeHTTP)
 	}
 
 	// Static file serving for SPA (catch-all, must be last)
+	if frontendFound {
+		r.Get("/*", spaHandler(staticFS))
+	}
+
+	return s
+}
+
+// frontendFS returns the embedded frontend and whether its index.html exists
+func frontendFS() (fs.FS, bool) {
 	staticFS, err := fs.Sub(frontend.StaticFiles, "dist")
 	if err != nil {
 		logging.Default().Error("failed to create sub FS for frontend static files", "error", err)
-	} else {
-		// Check if index.html exists
-		if _, err := staticFS.Open("index.html"); err == nil {
-			// Serve static files and handle SPA routing
-			r.Get("/*", spaHandler(staticFS))
-		} else {
-			logging.Default().Warn("index.html not found in frontend dist", "error", err)
-		}
+		return nil, false
 	}
-
-	return s
+	if _, err := staticFS.Open("index.html"); err != nil {
+		logging.Default().Warn("index.html not found in frontend dist", "error", err)
+		return nil, false
+	}
+	return staticFS, true
 }
 
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -102,6 +167,8 @@ func accessLogger(next http.Handler) http.Handler {
 				"duration", time.Since(start),
 				"remote", r.RemoteAddr,
 				"user_agent", r.UserAgent(),
+				"request_id", middleware.GetReqID(r.Context()),
+				"trace_id", tracing.TraceID(r.Context()),
 			)
 		}()
 
@@ -109,6 +176,25 @@ func accessLogger(next http.Handler) http.Handler {
 	})
 }
 
+// traceRequests starts the root span of each request, continuing a trace
+// propagated by the client
+func traceRequests(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http.server",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+	)
+}
+
+// requestIDContext passes the request ID assigned by middleware.RequestID to
+// the usecases, which record it in the audit log
+func requestIDContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := model.WithRequestID(r.Context(), middleware.GetReqID(r.Context()))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // dataLoaderMiddleware adds data loaders to the request context
 func dataLoaderMiddleware(resolver *gqlcontroller.Resolver) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -122,11 +208,35 @@ func dataLoaderMiddleware(resolver *gqlcontroller.Resolver) func(http.Handler) h
 }
 
 // GraphQL handler
-func graphqlHandler(resolver *gqlcontroller.Resolver) http.Handler {
-	srv := handler.NewDefaultServer(
-		gqlcontroller.NewExecutableSchema(gqlcontroller.Config{Resolvers: resolver}),
+func graphqlHandler(resolver *gqlcontroller.Resolver, authn interfaces.Authenticator, m *metrics.Prometheus) http.Handler {
+	srv := handler.New(
+		gqlcontroller.NewExecutableSchema(gqlcontroller.NewConfig(resolver)),
 	)
 
+	// Subscriptions (job progress) are served over websocket
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc:              websocketInit(authn),
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	// Metrics include fields rejected by authorization
+	if m != nil {
+		srv.AroundFields(gqlcontroller.ObserveRootFields(m))
+	}
+	// API tokens are restricted by their scopes instead of roles
+	srv.AroundFields(gqlcontroller.AuthorizeScopes)
+	srv.AroundFields(gqlcontroller.TraceResolvers)
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New[string](100),
+	})
+
 	// Add error handling middleware to ensure 500 errors are logged
 	srv.SetErrorPresenter(func(ctx context.Context, err error) *gqlerror.Error {
 		// Log all GraphQL errors with full context
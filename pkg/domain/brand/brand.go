@@ -0,0 +1,393 @@
+package brand
+
+import (
+	"math"
+	"math/bits"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/domain/vectorizer"
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// ErrInvalidProtectedDomain is returned when a protected domain is not a registrable domain
+	ErrInvalidProtectedDomain = goerr.New("invalid protected domain")
+)
+
+// minFuzzyLength is the minimum protected label length for typosquat and combosquat
+// detection. Shorter names are one edit away from, or part of, too many unrelated words.
+const minFuzzyLength = 4
+
+// Detector flags domains imitating protected domains
+type Detector struct {
+	protected  []*protectedDomain
+	techniques map[model.LookalikeTechnique]bool
+	minScore   float64
+	vec        *vectorizer.NGramVectorizer
+}
+
+type protectedDomain struct {
+	domain   string // Registrable domain, e.g. example.co.uk
+	label    string // Registrable label, e.g. example
+	suffix   string // Public suffix, e.g. co.uk
+	skeleton string
+	vec      []float32
+}
+
+// Option configures Detector
+type Option func(*Detector)
+
+// WithMinScore drops matches whose similarity score is below score (default: 0)
+func WithMinScore(score float64) Option {
+	return func(d *Detector) {
+		d.minScore = score
+	}
+}
+
+// WithTechniques limits detection to the given techniques (default: all)
+func WithTechniques(techniques ...model.LookalikeTechnique) Option {
+	return func(d *Detector) {
+		d.techniques = make(map[model.LookalikeTechnique]bool, len(techniques))
+		for _, t := range techniques {
+			d.techniques[t] = true
+		}
+	}
+}
+
+// New creates a detector for the protected registrable domains (e.g. example.com)
+func New(domains []string, opts ...Option) (*Detector, error) {
+	d := &Detector{
+		techniques: make(map[model.LookalikeTechnique]bool),
+		// Bigrams keep short domain names comparable
+		vec: vectorizer.NewNGramVectorizer(vectorizer.WithNGramSize(2)),
+	}
+	for _, t := range model.AllLookalikeTechniques {
+		d.techniques[t] = true
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	for t := range d.techniques {
+		if !t.IsValid() {
+			return nil, goerr.New("unknown lookalike technique", goerr.V("technique", t))
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, raw := range domains {
+		host, err := idna.Lookup.ToASCII(strings.TrimSuffix(strings.ToLower(strings.TrimSpace(raw)), "."))
+		if err != nil || host == "" {
+			return nil, goerr.Wrap(ErrInvalidProtectedDomain, "protected domain is not a valid host name",
+				goerr.V("domain", raw), goerr.V("error", err))
+		}
+		registrable, label, suffix, err := splitRegistrable(host)
+		if err != nil || registrable != host {
+			return nil, goerr.Wrap(ErrInvalidProtectedDomain, "protected domain must be a registrable domain such as example.com",
+				goerr.V("domain", raw))
+		}
+		if seen[host] {
+			continue
+		}
+		seen[host] = true
+
+		p := &protectedDomain{
+			domain:   host,
+			label:    label,
+			suffix:   suffix,
+			skeleton: asciiSkeleton(label),
+		}
+		if p.vec, err = d.vec.Vectorize(host); err != nil {
+			return nil, goerr.Wrap(err, "failed to vectorize protected domain", goerr.V("domain", raw))
+		}
+		d.protected = append(d.protected, p)
+	}
+
+	return d, nil
+}
+
+// Domains returns the protected domains
+func (d *Detector) Domains() []string {
+	domains := make([]string, len(d.protected))
+	for i, p := range d.protected {
+		domains[i] = p.domain
+	}
+	return domains
+}
+
+// Empty returns true if no domain is protected
+func (d *Detector) Empty() bool {
+	return len(d.protected) == 0
+}
+
+// Detect returns one match per protected domain imitated by the host of a domain
+// or URL IoC. Hosts under a protected domain are legitimate and never match.
+func (d *Detector) Detect(ioc *model.IoC, now time.Time) []*model.BrandMatch {
+	host := iocHost(ioc)
+	if host == "" || net.ParseIP(host) != nil {
+		return nil
+	}
+	host, err := normalizeHost(host)
+	if err != nil {
+		return nil
+	}
+	registrable, label, suffix, err := splitRegistrable(host)
+	if err != nil {
+		return nil
+	}
+
+	// Compare Unicode labels so that IDN homographs are visible
+	unicodeLabel, err := idna.Punycode.ToUnicode(label)
+	if err != nil {
+		unicodeLabel = label
+	}
+	domain, err := idna.Punycode.ToUnicode(registrable)
+	if err != nil {
+		domain = registrable
+	}
+
+	var matches []*model.BrandMatch
+	for _, p := range d.protected {
+		if host == p.domain || strings.HasSuffix(host, "."+p.domain) {
+			continue
+		}
+
+		technique, distance, compared, ok := d.classify(p, unicodeLabel, suffix)
+		if !ok {
+			continue
+		}
+
+		score := d.score(p, compared+"."+suffix, distance, unicodeLabel)
+		if score < d.minScore {
+			continue
+		}
+
+		matches = append(matches, &model.BrandMatch{
+			ID:              model.BrandMatchID(p.domain, ioc.ID),
+			ProtectedDomain: p.domain,
+			Domain:          domain,
+			IoCID:           ioc.ID,
+			IoCType:         ioc.Type,
+			IoCValue:        ioc.Value,
+			SourceID:        ioc.SourceID,
+			Technique:       technique,
+			EditDistance:    distance,
+			Score:           score,
+			CreatedAt:       now,
+		})
+	}
+
+	return matches
+}
+
+// classify returns the technique, the edit distance between the labels and the
+// label used for similarity scoring (the ASCII form of homographs)
+func (d *Detector) classify(p *protectedDomain, label, suffix string) (model.LookalikeTechnique, int, string, bool) {
+	distance := osaDistance(label, p.label)
+
+	if label == p.label {
+		if suffix != p.suffix && d.techniques[model.LookalikeTLDSwap] {
+			return model.LookalikeTLDSwap, 0, label, true
+		}
+		return "", 0, "", false
+	}
+
+	if !isASCII(label) {
+		skeleton := unicodeSkeleton(label)
+		if d.techniques[model.LookalikeIDN] && asciiSkeleton(skeleton) == p.skeleton {
+			return model.LookalikeIDN, distance, p.label, true
+		}
+		return "", 0, "", false
+	}
+
+	if d.techniques[model.LookalikeHomoglyph] && asciiSkeleton(label) == p.skeleton {
+		return model.LookalikeHomoglyph, distance, p.label, true
+	}
+	if d.techniques[model.LookalikeBitsquat] && isBitsquat(label, p.label) {
+		return model.LookalikeBitsquat, distance, label, true
+	}
+	if len(p.label) < minFuzzyLength {
+		return "", 0, "", false
+	}
+	if d.techniques[model.LookalikeTyposquat] && distance <= maxTypoDistance(p.label) {
+		return model.LookalikeTyposquat, distance, label, true
+	}
+	if d.techniques[model.LookalikeCombosquat] && strings.Contains(label, p.label) {
+		return model.LookalikeCombosquat, distance, label, true
+	}
+
+	return "", 0, "", false
+}
+
+// score combines the normalized edit similarity of the labels and the n-gram
+// cosine similarity of the registrable domains into 0.0-1.0
+func (d *Detector) score(p *protectedDomain, compared string, distance int, label string) float64 {
+	longest := max(len([]rune(label)), len(p.label))
+	editSimilarity := 1 - float64(distance)/float64(longest)
+
+	var cosine float64
+	if vec, err := d.vec.Vectorize(compared); err == nil {
+		for i := range vec {
+			cosine += float64(vec[i] * p.vec[i])
+		}
+	}
+	cosine = math.Min(math.Max(cosine, 0), 1)
+
+	return math.Round((editSimilarity+cosine)/2*1000) / 1000
+}
+
+// maxTypoDistance allows two edits for longer names and one for shorter ones
+func maxTypoDistance(label string) int {
+	if len(label) >= 8 {
+		return 2
+	}
+	return 1
+}
+
+// iocHost returns the host of domain and URL IoCs
+func iocHost(ioc *model.IoC) string {
+	switch ioc.Type {
+	case model.IoCTypeDomain:
+		return ioc.Value
+	case model.IoCTypeURL:
+		if u, err := url.Parse(ioc.Value); err == nil {
+			return u.Hostname()
+		}
+	}
+	return ""
+}
+
+// normalizeHost returns the lower-cased ASCII (punycode) form of a host
+func normalizeHost(host string) (string, error) {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	if host == "" {
+		return "", goerr.New("empty domain")
+	}
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		// Lookalike domains often fail strict IDNA validation; keep the raw form
+		ascii, err = idna.Punycode.ToASCII(host)
+		if err != nil {
+			return "", goerr.Wrap(err, "invalid domain")
+		}
+	}
+	return ascii, nil
+}
+
+// splitRegistrable splits an ASCII host into its registrable domain, label and public suffix
+func splitRegistrable(host string) (string, string, string, error) {
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return "", "", "", goerr.Wrap(err, "no registrable domain", goerr.V("host", host))
+	}
+	label, suffix, _ := strings.Cut(registrable, ".")
+	return registrable, label, suffix, nil
+}
+
+// asciiSkeleton maps ASCII characters and sequences that render alike to a single form
+func asciiSkeleton(s string) string {
+	s = strings.NewReplacer("rn", "m", "vv", "w", "cl", "d").Replace(s)
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '0':
+			return 'o'
+		case '1', 'i':
+			return 'l'
+		case '5':
+			return 's'
+		}
+		return r
+	}, s)
+}
+
+// confusables maps non-Latin letters to the Latin letters they are commonly mistaken for
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'һ': 'h', 'і': 'i', 'ј': 'j', 'к': 'k', 'м': 'm', 'н': 'h',
+	'о': 'o', 'р': 'p', 'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'ѕ': 's', 'ԁ': 'd', 'ԛ': 'q',
+	'ԝ': 'w', 'ү': 'y', 'ӏ': 'l',
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'τ': 't',
+	'υ': 'u', 'χ': 'x',
+	// Latin letters outside ASCII without a decomposition
+	'ı': 'i', 'ȷ': 'j', 'ɑ': 'a', 'ɡ': 'g', 'ł': 'l', 'ø': 'o', 'ß': 's', 'đ': 'd', 'ħ': 'h',
+}
+
+// unicodeSkeleton strips diacritics and maps confusable letters to Latin
+func unicodeSkeleton(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if c, ok := confusables[r]; ok {
+			r = c
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// isBitsquat returns true if a and b differ by one bit in a single hostname character
+func isBitsquat(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	diff := -1
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			if diff >= 0 {
+				return false
+			}
+			diff = i
+		}
+	}
+	return diff >= 0 && bits.OnesCount8(a[diff]^b[diff]) == 1
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// osaDistance returns the optimal string alignment distance (Levenshtein distance
+// with transpositions of adjacent characters) between a and b
+func osaDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
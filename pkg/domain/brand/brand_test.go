@@ -0,0 +1,200 @@
+package brand_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/brand"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func TestDetector_Detect(t *testing.T) {
+	detector, err := brand.New([]string{"example.com", "Acme-Bank.co.uk."})
+	gt.NoError(t, err)
+	gt.A(t, detector.Domains()).Length(2).
+		At(0, func(t testing.TB, v string) { gt.Equal(t, v, "example.com") }).
+		At(1, func(t testing.TB, v string) { gt.Equal(t, v, "acme-bank.co.uk") })
+
+	testCases := []struct {
+		name      string
+		ioc       *model.IoC
+		protected string
+		technique model.LookalikeTechnique
+		distance  int
+		domain    string
+	}{
+		{
+			name:      "transposition",
+			ioc:       &model.IoC{Type: model.IoCTypeDomain, Value: "examlpe.com"},
+			protected: "example.com",
+			technique: model.LookalikeTyposquat,
+			distance:  1,
+			domain:    "examlpe.com",
+		},
+		{
+			name:      "omission in URL host",
+			ioc:       &model.IoC{Type: model.IoCTypeURL, Value: "https://login.exmple.com/signin"},
+			protected: "example.com",
+			technique: model.LookalikeTyposquat,
+			distance:  1,
+			domain:    "exmple.com",
+		},
+		{
+			name:      "two edits on a long name",
+			ioc:       &model.IoC{Type: model.IoCTypeDomain, Value: "acmebnak.co.uk"},
+			protected: "acme-bank.co.uk",
+			technique: model.LookalikeTyposquat,
+			distance:  2,
+			domain:    "acmebnak.co.uk",
+		},
+		{
+			name:      "ascii homoglyph",
+			ioc:       &model.IoC{Type: model.IoCTypeDomain, Value: "exarnp1e.com"},
+			protected: "example.com",
+			technique: model.LookalikeHomoglyph,
+			distance:  3,
+			domain:    "exarnp1e.com",
+		},
+		{
+			name: "cyrillic homograph in punycode",
+			// "еxаmple.com" with Cyrillic е and а
+			ioc:       &model.IoC{Type: model.IoCTypeDomain, Value: "xn--xmple-4ve7a.com"},
+			protected: "example.com",
+			technique: model.LookalikeIDN,
+			distance:  2,
+			domain:    "еxаmple.com",
+		},
+		{
+			name:      "unicode homograph",
+			ioc:       &model.IoC{Type: model.IoCTypeDomain, Value: "exаmplé.com"},
+			protected: "example.com",
+			technique: model.LookalikeIDN,
+			distance:  2,
+			domain:    "exаmplé.com",
+		},
+		{
+			name:      "bit flip",
+			ioc:       &model.IoC{Type: model.IoCTypeDomain, Value: "exampme.com"},
+			protected: "example.com",
+			technique: model.LookalikeBitsquat,
+			distance:  1,
+			domain:    "exampme.com",
+		},
+		{
+			name:      "combosquat",
+			ioc:       &model.IoC{Type: model.IoCTypeDomain, Value: "example-secure-login.net"},
+			protected: "example.com",
+			technique: model.LookalikeCombosquat,
+			distance:  13,
+			domain:    "example-secure-login.net",
+		},
+		{
+			name:      "tld swap",
+			ioc:       &model.IoC{Type: model.IoCTypeDomain, Value: "www.example.co"},
+			protected: "example.com",
+			technique: model.LookalikeTLDSwap,
+			distance:  0,
+			domain:    "example.co",
+		},
+	}
+
+	now := time.Now()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.ioc.ID = "ioc-1"
+			tc.ioc.SourceID = "feed"
+			matches := detector.Detect(tc.ioc, now)
+			gt.A(t, matches).Length(1).At(0, func(t testing.TB, m *model.BrandMatch) {
+				gt.Equal(t, m.ProtectedDomain, tc.protected)
+				gt.Equal(t, m.Technique, tc.technique)
+				gt.Equal(t, m.EditDistance, tc.distance)
+				gt.Equal(t, m.Domain, tc.domain)
+				gt.Equal(t, m.ID, model.BrandMatchID(tc.protected, "ioc-1"))
+				gt.Equal(t, m.IoCValue, tc.ioc.Value)
+				gt.Equal(t, m.SourceID, "feed")
+				gt.True(t, m.Score > 0 && m.Score <= 1)
+			})
+		})
+	}
+
+	for _, value := range []string{
+		"example.com",        // The protected domain itself
+		"mail.example.com",   // Subdomains are legitimate
+		"unrelated.org",      // No resemblance
+		"198.51.100.7",       // IP addresses are ignored
+		"http://192.0.2.1/x", // IP hosts are ignored
+		"localhost",          // No registrable domain
+	} {
+		t.Run("no match "+value, func(t *testing.T) {
+			iocType := model.IoCTypeDomain
+			if value[:4] == "http" {
+				iocType = model.IoCTypeURL
+			}
+			gt.A(t, detector.Detect(&model.IoC{ID: "x", Type: iocType, Value: value}, now)).Length(0)
+		})
+	}
+
+	t.Run("other IoC types are ignored", func(t *testing.T) {
+		ioc := &model.IoC{ID: "x", Type: model.IoCTypeEmail, Value: "ceo@examlpe.com"}
+		gt.A(t, detector.Detect(ioc, now)).Length(0)
+	})
+}
+
+func TestDetector_Score(t *testing.T) {
+	detector, err := brand.New([]string{"example.com"})
+	gt.NoError(t, err)
+
+	score := func(value string) float64 {
+		matches := detector.Detect(&model.IoC{ID: "x", Type: model.IoCTypeDomain, Value: value}, time.Now())
+		gt.A(t, matches).Length(1)
+		return matches[0].Score
+	}
+
+	// Closer lookalikes score higher
+	gt.True(t, score("examlpe.com") > score("example-login.com"))
+	gt.True(t, score("example.net") > score("examplesupport.com"))
+
+	strict, err := brand.New([]string{"example.com"}, brand.WithMinScore(0.7))
+	gt.NoError(t, err)
+	gt.A(t, strict.Detect(&model.IoC{ID: "x", Type: model.IoCTypeDomain, Value: "examlpe.com"}, time.Now())).Length(1)
+	gt.A(t, strict.Detect(&model.IoC{ID: "x", Type: model.IoCTypeDomain, Value: "example-secure-login.com"}, time.Now())).Length(0)
+}
+
+func TestDetector_Techniques(t *testing.T) {
+	detector, err := brand.New([]string{"example.com"}, brand.WithTechniques(model.LookalikeIDN))
+	gt.NoError(t, err)
+	gt.A(t, detector.Detect(&model.IoC{ID: "x", Type: model.IoCTypeDomain, Value: "examlpe.com"}, time.Now())).Length(0)
+	gt.A(t, detector.Detect(&model.IoC{ID: "x", Type: model.IoCTypeDomain, Value: "xn--xmple-4ve7a.com"}, time.Now())).Length(1)
+
+	_, err = brand.New([]string{"example.com"}, brand.WithTechniques("soundalike"))
+	gt.Error(t, err)
+}
+
+func TestNew_InvalidDomain(t *testing.T) {
+	for _, domain := range []string{"", "www.example.com", "com", "exa mple.com"} {
+		t.Run(domain, func(t *testing.T) {
+			_, err := brand.New([]string{domain})
+			gt.Error(t, err)
+			gt.True(t, errors.Is(err, brand.ErrInvalidProtectedDomain))
+		})
+	}
+}
+
+func TestOSADistance(t *testing.T) {
+	gt.Equal(t, brand.OSADistance("example", "example"), 0)
+	gt.Equal(t, brand.OSADistance("example", "examlpe"), 1)
+	gt.Equal(t, brand.OSADistance("example", "exmple"), 1)
+	gt.Equal(t, brand.OSADistance("example", "examples"), 1)
+	gt.Equal(t, brand.OSADistance("example", "exampel"), 1)
+	gt.Equal(t, brand.OSADistance("", "abc"), 3)
+	gt.Equal(t, brand.OSADistance("еxample", "example"), 1)
+}
+
+func TestIsBitsquat(t *testing.T) {
+	gt.True(t, brand.IsBitsquat("exampme", "example"))  // l (0x6c) -> m (0x6d)
+	gt.False(t, brand.IsBitsquat("exampze", "example")) // more than one bit
+	gt.False(t, brand.IsBitsquat("example", "example"))
+	gt.False(t, brand.IsBitsquat("exampmf", "example"))
+}
@@ -0,0 +1,13 @@
+package brand
+
+// Export internal functions for testing
+
+// OSADistance is exported for testing
+func OSADistance(a, b string) int {
+	return osaDistance(a, b)
+}
+
+// IsBitsquat is exported for testing
+func IsBitsquat(a, b string) bool {
+	return isBitsquat(a, b)
+}
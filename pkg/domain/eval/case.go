@@ -0,0 +1,122 @@
+// Package eval measures the quality of IoC extraction against golden articles
+// whose IoCs are labeled malicious or benign.
+package eval
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// Label tells whether an IoC of a golden article must be extracted
+type Label string
+
+const (
+	// LabelMalicious marks an IoC that must be extracted
+	LabelMalicious Label = "malicious"
+	// LabelBenign marks a value in the article that must not be extracted,
+	// e.g. a reference URL or the author's address
+	LabelBenign Label = "benign"
+)
+
+// ExpectedIoC is a labeled IoC of a golden article
+type ExpectedIoC struct {
+	Type  model.IoCType `toml:"type"`
+	Value string        `toml:"value"`
+	Label Label         `toml:"label"`
+}
+
+// Case is a golden article. It is loaded from <name>.toml, and the article
+// text from the file named by Article, or <name>.md by default.
+//
+//	title = "Campaign analysis"
+//	article = "campaign.md"  # optional
+//	mode = "llm"             # optional extraction mode
+//
+//	[[iocs]]
+//	type = "ipv4"
+//	value = "198.51.100.42"
+//	label = "malicious"
+type Case struct {
+	Name    string               `toml:"-"`
+	Title   string               `toml:"title"`
+	Article string               `toml:"article"`
+	Mode    model.ExtractionMode `toml:"mode"`
+	IoCs    []ExpectedIoC        `toml:"iocs"`
+	Content string               `toml:"-"`
+}
+
+// LoadCases loads the golden articles in dir, sorted by name. Expected values
+// are validated and normalized like extracted values.
+func LoadCases(dir string) ([]*Case, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list golden articles", goerr.V("dir", dir))
+	}
+	if len(paths) == 0 {
+		return nil, goerr.New("no golden articles found", goerr.V("dir", dir))
+	}
+	sort.Strings(paths)
+
+	cases := make([]*Case, 0, len(paths))
+	for _, path := range paths {
+		c, err := loadCase(path)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+func loadCase(path string) (*Case, error) {
+	name := strings.TrimSuffix(filepath.Base(path), ".toml")
+
+	var c Case
+	if _, err := toml.DecodeFile(path, &c); err != nil {
+		return nil, goerr.Wrap(err, "failed to decode golden article", goerr.V("path", path))
+	}
+	c.Name = name
+
+	if c.Article == "" {
+		c.Article = name + ".md"
+	}
+	articlePath := filepath.Join(filepath.Dir(path), c.Article)
+	content, err := os.ReadFile(articlePath) // #nosec G304 -- golden articles are chosen by the operator
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read golden article", goerr.V("case", name), goerr.V("path", articlePath))
+	}
+	c.Content = string(content)
+	if c.Title == "" {
+		c.Title = name
+	}
+
+	if c.Mode != "" && !c.Mode.IsValid() {
+		return nil, goerr.New("invalid extraction mode", goerr.V("case", name), goerr.V("mode", c.Mode))
+	}
+
+	for i := range c.IoCs {
+		exp := &c.IoCs[i]
+		if exp.Label != LabelMalicious && exp.Label != LabelBenign {
+			return nil, goerr.New("invalid label of expected IoC, must be malicious or benign",
+				goerr.V("case", name), goerr.V("value", exp.Value), goerr.V("label", exp.Label))
+		}
+		ioc, err := extractor.ConvertToIoC(name, "eval", "", &extractor.ExtractedIoC{
+			Type:  string(exp.Type),
+			Value: exp.Value,
+		}, nil)
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid expected IoC", goerr.V("case", name), goerr.V("value", exp.Value))
+		}
+		exp.Type = ioc.Type
+		exp.Value = ioc.Value
+	}
+
+	return &c, nil
+}
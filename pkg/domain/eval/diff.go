@@ -0,0 +1,128 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+)
+
+// CaseDiff lists the changes of a golden article's result from the baseline
+type CaseDiff struct {
+	Name                string
+	NewlyFound          []Finding
+	NewlyMissed         []Finding
+	NewFalsePositives   []Finding
+	FixedFalsePositives []Finding
+}
+
+func (d *CaseDiff) empty() bool {
+	return len(d.NewlyFound) == 0 && len(d.NewlyMissed) == 0 &&
+		len(d.NewFalsePositives) == 0 && len(d.FixedFalsePositives) == 0
+}
+
+// Diff is the comparison of a report with a previous run
+type Diff struct {
+	Baseline *Report
+	Current  *Report
+
+	Cases        []*CaseDiff // Changed cases in both reports
+	AddedCases   []string
+	RemovedCases []string
+}
+
+// Compare compares the current report with a baseline report
+func Compare(baseline, current *Report) *Diff {
+	d := &Diff{Baseline: baseline, Current: current}
+
+	previous := make(map[string]*CaseResult, len(baseline.Cases))
+	for _, c := range baseline.Cases {
+		previous[c.Name] = c
+	}
+	inCurrent := make(map[string]bool, len(current.Cases))
+
+	for _, cur := range current.Cases {
+		inCurrent[cur.Name] = true
+		prev, ok := previous[cur.Name]
+		if !ok {
+			d.AddedCases = append(d.AddedCases, cur.Name)
+			continue
+		}
+
+		cd := &CaseDiff{
+			Name:                cur.Name,
+			NewlyFound:          subtract(cur.Found, prev.Found),
+			NewlyMissed:         subtract(cur.Missed, prev.Missed),
+			NewFalsePositives:   subtract(cur.FalsePositives, prev.FalsePositives),
+			FixedFalsePositives: subtract(prev.FalsePositives, cur.FalsePositives),
+		}
+		if !cd.empty() {
+			d.Cases = append(d.Cases, cd)
+		}
+	}
+
+	for _, c := range baseline.Cases {
+		if !inCurrent[c.Name] {
+			d.RemovedCases = append(d.RemovedCases, c.Name)
+		}
+	}
+
+	return d
+}
+
+// subtract returns the findings of a that are not in b
+func subtract(a, b []Finding) []Finding {
+	exists := make(map[string]bool, len(b))
+	for _, f := range b {
+		exists[f.key()] = true
+	}
+	var result []Finding
+	for _, f := range a {
+		if !exists[f.key()] {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// Regressed returns true if an IoC found in the baseline is missed, or a new
+// false positive appeared, in an article of both reports
+func (d *Diff) Regressed() bool {
+	for _, c := range d.Cases {
+		if len(c.NewlyMissed) > 0 || len(c.NewFalsePositives) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText writes a human readable summary of the differences
+func (d *Diff) WriteText(w io.Writer) error {
+	p := func(format string, args ...any) { _, _ = fmt.Fprintf(w, format, args...) }
+
+	p("Compared with baseline of %s (model %s, prompt %s)\n",
+		d.Baseline.CreatedAt.Format("2006-01-02 15:04:05"), d.Baseline.Model, d.Baseline.PromptVersion)
+	p("  precision: %.3f -> %.3f (%+.3f)\n",
+		d.Baseline.Total.Precision(), d.Current.Total.Precision(),
+		d.Current.Total.Precision()-d.Baseline.Total.Precision())
+	p("  recall:    %.3f -> %.3f (%+.3f)\n",
+		d.Baseline.Total.Recall(), d.Current.Total.Recall(),
+		d.Current.Total.Recall()-d.Baseline.Total.Recall())
+
+	for _, name := range d.AddedCases {
+		p("  added article: %s\n", name)
+	}
+	for _, name := range d.RemovedCases {
+		p("  removed article: %s\n", name)
+	}
+
+	for _, c := range d.Cases {
+		p("\n%s:\n", c.Name)
+		writeFindings(w, "+ found", c.NewlyFound)
+		writeFindings(w, "- missed", c.NewlyMissed)
+		writeFindings(w, "- false positive", c.NewFalsePositives)
+		writeFindings(w, "+ fixed false positive", c.FixedFalsePositives)
+	}
+	if len(d.Cases) == 0 {
+		p("  no changes in extracted IoCs\n")
+	}
+	return nil
+}
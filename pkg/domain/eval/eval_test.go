@@ -0,0 +1,248 @@
+package eval_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/eval"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+const goldenTOML = `title = "Campaign"
+
+[[iocs]]
+type = "ipv4"
+value = "198.51.100[.]42"
+label = "malicious"
+
+[[iocs]]
+type = "domain"
+value = "Evil.Example"
+label = "malicious"
+
+[[iocs]]
+type = "url"
+value = "https://evil.example/payload"
+label = "malicious"
+
+[[iocs]]
+type = "md5"
+value = "5d41402abc4b2a76b9719d911017c592"
+label = "malicious"
+
+[[iocs]]
+type = "url"
+value = "https://github.com/org/tool"
+label = "benign"
+`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	gt.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func loadGolden(t *testing.T) *eval.Case {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "campaign.toml"), goldenTOML)
+	writeFile(t, filepath.Join(dir, "campaign.md"), "The implant beacons to 198.51.100[.]42.")
+
+	cases, err := eval.LoadCases(dir)
+	gt.NoError(t, err)
+	gt.A(t, cases).Length(1)
+	return cases[0]
+}
+
+func TestLoadCases(t *testing.T) {
+	c := loadGolden(t)
+	gt.Equal(t, c.Name, "campaign")
+	gt.Equal(t, c.Title, "Campaign")
+	gt.S(t, c.Content).Contains("beacons")
+
+	// Expected values are normalized like extracted values
+	gt.A(t, c.IoCs).Length(5).
+		At(0, func(t testing.TB, exp eval.ExpectedIoC) { gt.Equal(t, exp.Value, "198.51.100.42") }).
+		At(1, func(t testing.TB, exp eval.ExpectedIoC) { gt.Equal(t, exp.Value, "evil.example") })
+
+	t.Run("article file and mode", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "a.toml"), "article = \"post.html\"\nmode = \"regex\"\n")
+		writeFile(t, filepath.Join(dir, "post.html"), "<p>text</p>")
+
+		cases, err := eval.LoadCases(dir)
+		gt.NoError(t, err)
+		gt.Equal(t, cases[0].Title, "a")
+		gt.Equal(t, cases[0].Mode, model.ExtractionModeRegex)
+		gt.Equal(t, cases[0].Content, "<p>text</p>")
+	})
+
+	for name, toml := range map[string]string{
+		"invalid label": "[[iocs]]\ntype = \"ipv4\"\nvalue = \"198.51.100.1\"\nlabel = \"suspicious\"\n",
+		"invalid value": "[[iocs]]\ntype = \"ipv4\"\nvalue = \"not an address\"\nlabel = \"malicious\"\n",
+		"invalid mode":  "mode = \"magic\"\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, "a.toml"), toml)
+			writeFile(t, filepath.Join(dir, "a.md"), "text")
+			_, err := eval.LoadCases(dir)
+			gt.Error(t, err)
+		})
+	}
+
+	t.Run("missing article", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "a.toml"), "")
+		_, err := eval.LoadCases(dir)
+		gt.Error(t, err)
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		_, err := eval.LoadCases(t.TempDir())
+		gt.Error(t, err)
+	})
+}
+
+func TestScore(t *testing.T) {
+	c := loadGolden(t)
+
+	result := eval.Score(c, model.ExtractionModeLLM, []*extractor.ExtractedIoC{
+		{Type: "ipv4", Value: "198.51.100[.]42"},                  // found
+		{Type: "ip", Value: "198.51.100.42"},                      // duplicate after normalization
+		{Type: "url", Value: "https://evil.example"},              // unlabeled
+		{Type: "domain", Value: "https://evil.example/payload"},   // rejected by validation
+		{Type: "url", Value: "https://github.com/org/tool"},       // benign
+		{Type: "sha1", Value: "5d41402abc4b2a76b9719d911017c592"}, // rejected: not a SHA-1
+		{Type: "url", Value: "hxxps://evil[.]example/payload"},    // found
+	}, nil)
+
+	gt.Equal(t, result.Counts, eval.Counts{TruePositives: 2, FalsePositives: 2, FalseNegatives: 2})
+	gt.Equal(t, result.Found, []eval.Finding{
+		{Type: model.IoCTypeIPv4, Value: "198.51.100.42"},
+		{Type: model.IoCTypeURL, Value: "https://evil.example/payload"},
+	})
+	gt.Equal(t, result.Missed, []eval.Finding{
+		{Type: model.IoCTypeDomain, Value: "evil.example"},
+		{Type: model.IoCTypeMD5, Value: "5d41402abc4b2a76b9719d911017c592"},
+	})
+	gt.Equal(t, result.FalsePositives, []eval.Finding{
+		{Type: model.IoCTypeURL, Value: "https://evil.example", Reason: eval.ReasonUnlabeled},
+		{Type: model.IoCTypeURL, Value: "https://github.com/org/tool", Reason: eval.ReasonBenign},
+	})
+	gt.A(t, result.Rejected).Length(2)
+	gt.Equal(t, result.Precision(), 0.5)
+	gt.Equal(t, result.Recall(), 0.5)
+
+	t.Run("wrong type", func(t *testing.T) {
+		result := eval.Score(c, model.ExtractionModeLLM, []*extractor.ExtractedIoC{
+			{Type: "cert-hash", Value: "5d41402abc4b2a76b9719d911017c592"},
+		}, nil)
+		gt.Equal(t, result.FalsePositives, []eval.Finding{{
+			Type:         model.IoCTypeCertHash,
+			Value:        "5d41402abc4b2a76b9719d911017c592",
+			ExpectedType: model.IoCTypeMD5,
+			Reason:       eval.ReasonWrongType,
+		}})
+		gt.A(t, result.Missed).Length(4).At(3, func(t testing.TB, f eval.Finding) {
+			gt.Equal(t, f.Type, model.IoCTypeMD5)
+			gt.Equal(t, f.Reason, eval.ReasonWrongType)
+		})
+	})
+
+	t.Run("extraction error misses all malicious IoCs", func(t *testing.T) {
+		result := eval.Score(c, model.ExtractionModeLLM, nil, errors.New("quota exceeded"))
+		gt.Equal(t, result.Error, "quota exceeded")
+		gt.Equal(t, result.Counts, eval.Counts{FalseNegatives: 4})
+		gt.Equal(t, result.Missed[0].Reason, eval.ReasonError)
+	})
+}
+
+func TestReport(t *testing.T) {
+	c := loadGolden(t)
+	results := []*eval.CaseResult{
+		eval.Score(c, model.ExtractionModeLLM, []*extractor.ExtractedIoC{
+			{Type: "ipv4", Value: "198.51.100.42"},
+			{Type: "cert-hash", Value: "5d41402abc4b2a76b9719d911017c592"},
+			{Type: "url", Value: "https://github.com/org/tool"},
+		}, nil),
+	}
+	report := eval.NewReport("gemini/default", "abc", results)
+
+	gt.Equal(t, report.Total, eval.Counts{TruePositives: 1, FalsePositives: 2, FalseNegatives: 3})
+	gt.Equal(t, *report.Types[model.IoCTypeIPv4], eval.Counts{TruePositives: 1})
+	gt.Equal(t, *report.Types[model.IoCTypeCertHash], eval.Counts{FalsePositives: 1})
+	gt.Equal(t, *report.Types[model.IoCTypeMD5], eval.Counts{FalseNegatives: 1})
+	gt.Equal(t, report.Confusion, map[string]map[string]int{
+		"ipv4":               {"ipv4": 1},
+		"md5":                {"cert-hash": 1},
+		"domain":             {eval.ConfusionMissed: 1},
+		"url":                {eval.ConfusionMissed: 1},
+		eval.ConfusionBenign: {"url": 1},
+	})
+
+	t.Run("save and load", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.json")
+		gt.NoError(t, report.Save(path))
+
+		loaded, err := eval.LoadReport(path)
+		gt.NoError(t, err)
+		gt.Equal(t, loaded.Total, report.Total)
+		gt.Equal(t, loaded.Confusion, report.Confusion)
+		gt.Equal(t, loaded.Cases[0].Found, report.Cases[0].Found)
+	})
+}
+
+func TestCompare(t *testing.T) {
+	c := loadGolden(t)
+	baseline := eval.NewReport("m", "v1", []*eval.CaseResult{
+		eval.Score(c, model.ExtractionModeLLM, []*extractor.ExtractedIoC{
+			{Type: "ipv4", Value: "198.51.100.42"},
+			{Type: "url", Value: "https://github.com/org/tool"},
+		}, nil),
+		{Name: "removed"},
+	})
+
+	t.Run("improvement", func(t *testing.T) {
+		current := eval.NewReport("m", "v2", []*eval.CaseResult{
+			eval.Score(c, model.ExtractionModeLLM, []*extractor.ExtractedIoC{
+				{Type: "ipv4", Value: "198.51.100.42"},
+				{Type: "domain", Value: "evil.example"},
+			}, nil),
+			{Name: "added"},
+		})
+
+		diff := eval.Compare(baseline, current)
+		gt.False(t, diff.Regressed())
+		gt.Equal(t, diff.AddedCases, []string{"added"})
+		gt.Equal(t, diff.RemovedCases, []string{"removed"})
+		gt.A(t, diff.Cases).Length(1).At(0, func(t testing.TB, cd *eval.CaseDiff) {
+			gt.Equal(t, cd.NewlyFound, []eval.Finding{{Type: model.IoCTypeDomain, Value: "evil.example"}})
+			gt.A(t, cd.FixedFalsePositives).Length(1)
+			gt.A(t, cd.NewlyMissed).Length(0)
+		})
+	})
+
+	t.Run("regression", func(t *testing.T) {
+		current := eval.NewReport("m", "v2", []*eval.CaseResult{
+			eval.Score(c, model.ExtractionModeLLM, []*extractor.ExtractedIoC{
+				{Type: "url", Value: "https://github.com/org/tool"},
+			}, nil),
+		})
+
+		diff := eval.Compare(baseline, current)
+		gt.True(t, diff.Regressed())
+		gt.A(t, diff.Cases).Length(1).At(0, func(t testing.TB, cd *eval.CaseDiff) {
+			gt.Equal(t, cd.NewlyMissed, []eval.Finding{{Type: model.IoCTypeIPv4, Value: "198.51.100.42"}})
+		})
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		diff := eval.Compare(baseline, baseline)
+		gt.False(t, diff.Regressed())
+		gt.A(t, diff.Cases).Length(0)
+	})
+}
@@ -0,0 +1,334 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// Reasons of missed IoCs and false positives
+const (
+	ReasonBenign    = "benign"     // Extracted value labeled benign
+	ReasonUnlabeled = "unlabeled"  // Extracted value not in the golden article
+	ReasonWrongType = "wrong_type" // Malicious value extracted with another type
+	ReasonInvalid   = "invalid"    // Extracted value rejected by validation, as in fetch
+	ReasonError     = "error"      // Extraction of the article failed
+)
+
+// Rows and columns of the confusion breakdown besides IoC types
+const (
+	ConfusionMissed    = "(missed)"
+	ConfusionBenign    = "(benign)"
+	ConfusionUnlabeled = "(unlabeled)"
+)
+
+// Finding is an IoC of an evaluation result. Type is the expected type of found
+// and missed IoCs, and the extracted type of false positives.
+type Finding struct {
+	Type         model.IoCType `json:"type"`
+	Value        string        `json:"value"`
+	ExpectedType model.IoCType `json:"expected_type,omitempty"` // Of wrong_type false positives
+	Reason       string        `json:"reason,omitempty"`
+}
+
+func (f Finding) key() string {
+	return string(f.Type) + "\x00" + f.Value
+}
+
+// Counts are true positive, false positive and false negative counts
+type Counts struct {
+	TruePositives  int `json:"true_positives"`
+	FalsePositives int `json:"false_positives"`
+	FalseNegatives int `json:"false_negatives"`
+}
+
+// Precision returns TP / (TP + FP), or 1 if nothing was extracted
+func (c Counts) Precision() float64 {
+	if c.TruePositives+c.FalsePositives == 0 {
+		return 1
+	}
+	return float64(c.TruePositives) / float64(c.TruePositives+c.FalsePositives)
+}
+
+// Recall returns TP / (TP + FN), or 1 if nothing was expected
+func (c Counts) Recall() float64 {
+	if c.TruePositives+c.FalseNegatives == 0 {
+		return 1
+	}
+	return float64(c.TruePositives) / float64(c.TruePositives+c.FalseNegatives)
+}
+
+func (c *Counts) add(o Counts) {
+	c.TruePositives += o.TruePositives
+	c.FalsePositives += o.FalsePositives
+	c.FalseNegatives += o.FalseNegatives
+}
+
+// CaseResult is the evaluation result of a golden article
+type CaseResult struct {
+	Name  string               `json:"name"`
+	Mode  model.ExtractionMode `json:"mode"`
+	Error string               `json:"error,omitempty"`
+	Counts
+
+	Found          []Finding `json:"found"`
+	Missed         []Finding `json:"missed"`
+	FalsePositives []Finding `json:"false_positives"`
+	Rejected       []Finding `json:"rejected,omitempty"` // Not counted as false positives
+}
+
+// Score compares the IoCs extracted from a golden article with its labels.
+// Extracted values are validated and normalized as in fetch; rejected values
+// are listed but not counted. If extraction failed, all malicious IoCs are missed.
+func Score(c *Case, mode model.ExtractionMode, extracted []*extractor.ExtractedIoC, extractErr error) *CaseResult {
+	result := &CaseResult{Name: c.Name, Mode: mode}
+
+	expected := make(map[string]*ExpectedIoC, len(c.IoCs))
+	for i := range c.IoCs {
+		expected[c.IoCs[i].Value] = &c.IoCs[i]
+	}
+	found := make(map[*ExpectedIoC]string) // Expected IoC -> reason
+
+	if extractErr != nil {
+		result.Error = extractErr.Error()
+	}
+
+	seen := make(map[string]bool)
+	for _, ext := range extracted {
+		ioc, err := extractor.ConvertToIoC(c.Name, "eval", "", ext, nil)
+		if err != nil {
+			result.Rejected = append(result.Rejected, Finding{
+				Type:   model.IoCType(ext.Type),
+				Value:  ext.Value,
+				Reason: ReasonInvalid,
+			})
+			continue
+		}
+		f := Finding{Type: ioc.Type, Value: ioc.Value}
+		if seen[f.key()] {
+			continue
+		}
+		seen[f.key()] = true
+
+		exp, ok := expected[ioc.Value]
+		switch {
+		case !ok:
+			f.Reason = ReasonUnlabeled
+		case exp.Label == LabelBenign:
+			f.Reason = ReasonBenign
+		case exp.Type == ioc.Type:
+			found[exp] = ""
+			result.Found = append(result.Found, f)
+			continue
+		default:
+			if _, ok := found[exp]; !ok {
+				found[exp] = ReasonWrongType
+			}
+			f.ExpectedType = exp.Type
+			f.Reason = ReasonWrongType
+		}
+		result.FalsePositives = append(result.FalsePositives, f)
+	}
+
+	for i := range c.IoCs {
+		exp := &c.IoCs[i]
+		if exp.Label != LabelMalicious {
+			continue
+		}
+		reason, ok := found[exp]
+		if ok && reason == "" {
+			continue
+		}
+		if extractErr != nil {
+			reason = ReasonError
+		}
+		result.Missed = append(result.Missed, Finding{Type: exp.Type, Value: exp.Value, Reason: reason})
+	}
+
+	result.Counts = Counts{
+		TruePositives:  len(result.Found),
+		FalsePositives: len(result.FalsePositives),
+		FalseNegatives: len(result.Missed),
+	}
+	return result
+}
+
+// Report is the result of an evaluation run over golden articles
+type Report struct {
+	Model         string    `json:"model"`
+	PromptVersion string    `json:"prompt_version"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	Total Counts                    `json:"total"`
+	Types map[model.IoCType]*Counts `json:"types"`
+	// Confusion counts expected types (rows) against extracted types (columns).
+	// Missed IoCs are in the ConfusionMissed column, and false positives that
+	// are not of a malicious value in the ConfusionBenign and ConfusionUnlabeled rows.
+	Confusion map[string]map[string]int `json:"confusion"`
+
+	Cases []*CaseResult `json:"cases"`
+}
+
+// NewReport aggregates case results
+func NewReport(llmModel, promptVersion string, cases []*CaseResult) *Report {
+	r := &Report{
+		Model:         llmModel,
+		PromptVersion: promptVersion,
+		CreatedAt:     time.Now(),
+		Types:         make(map[model.IoCType]*Counts),
+		Confusion:     make(map[string]map[string]int),
+		Cases:         cases,
+	}
+
+	typeCounts := func(t model.IoCType) *Counts {
+		if r.Types[t] == nil {
+			r.Types[t] = &Counts{}
+		}
+		return r.Types[t]
+	}
+	confuse := func(row, col string) {
+		if r.Confusion[row] == nil {
+			r.Confusion[row] = make(map[string]int)
+		}
+		r.Confusion[row][col]++
+	}
+
+	for _, c := range cases {
+		r.Total.add(c.Counts)
+		for _, f := range c.Found {
+			typeCounts(f.Type).TruePositives++
+			confuse(string(f.Type), string(f.Type))
+		}
+		for _, f := range c.Missed {
+			typeCounts(f.Type).FalseNegatives++
+			if f.Reason != ReasonWrongType {
+				confuse(string(f.Type), ConfusionMissed)
+			}
+		}
+		for _, f := range c.FalsePositives {
+			typeCounts(f.Type).FalsePositives++
+			switch f.Reason {
+			case ReasonWrongType:
+				confuse(string(f.ExpectedType), string(f.Type))
+			case ReasonBenign:
+				confuse(ConfusionBenign, string(f.Type))
+			default:
+				confuse(ConfusionUnlabeled, string(f.Type))
+			}
+		}
+	}
+
+	return r
+}
+
+// LoadReport reads a report saved by Save
+func LoadReport(path string) (*Report, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- report path is chosen by the operator
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read evaluation report", goerr.V("path", path))
+	}
+	var r Report
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return nil, goerr.Wrap(err, "failed to decode evaluation report", goerr.V("path", path))
+	}
+	return &r, nil
+}
+
+// Save writes the report as JSON to path
+func (r *Report) Save(path string) error {
+	raw, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return goerr.Wrap(err, "failed to encode evaluation report")
+	}
+	if err := os.WriteFile(path, append(raw, '\n'), 0o600); err != nil {
+		return goerr.Wrap(err, "failed to write evaluation report", goerr.V("path", path))
+	}
+	return nil
+}
+
+// WriteText writes a human readable summary of the report
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	p := func(format string, args ...any) { _, _ = fmt.Fprintf(tw, format, args...) }
+
+	p("Evaluated %d articles (model %s, prompt %s)\n\n", len(r.Cases), r.Model, r.PromptVersion)
+
+	p("TYPE\tTP\tFP\tFN\tPRECISION\tRECALL\n")
+	row := func(name string, c Counts) {
+		p("%s\t%d\t%d\t%d\t%.3f\t%.3f\n", name, c.TruePositives, c.FalsePositives, c.FalseNegatives, c.Precision(), c.Recall())
+	}
+	types := make([]string, 0, len(r.Types))
+	for t := range r.Types {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		row(t, *r.Types[model.IoCType(t)])
+	}
+	row("total", r.Total)
+
+	if len(r.Confusion) > 0 {
+		p("\nEXPECTED\tEXTRACTED\tCOUNT\n")
+		rows := make([]string, 0, len(r.Confusion))
+		for row := range r.Confusion {
+			rows = append(rows, row)
+		}
+		sort.Strings(rows)
+		for _, row := range rows {
+			cols := make([]string, 0, len(r.Confusion[row]))
+			for col := range r.Confusion[row] {
+				cols = append(cols, col)
+			}
+			sort.Strings(cols)
+			for _, col := range cols {
+				if row == col {
+					continue
+				}
+				p("%s\t%s\t%d\n", row, col, r.Confusion[row][col])
+			}
+		}
+	}
+
+	p("\nCASE\tTP\tFP\tFN\tPRECISION\tRECALL\n")
+	for _, c := range r.Cases {
+		row(c.Name, c.Counts)
+	}
+	if err := tw.Flush(); err != nil {
+		return goerr.Wrap(err, "failed to write evaluation report")
+	}
+
+	for _, c := range r.Cases {
+		if c.Error == "" && len(c.Missed) == 0 && len(c.FalsePositives) == 0 && len(c.Rejected) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "\n%s:\n", c.Name)
+		if c.Error != "" {
+			_, _ = fmt.Fprintf(w, "  error: %s\n", c.Error)
+		}
+		writeFindings(w, "missed", c.Missed)
+		writeFindings(w, "false positive", c.FalsePositives)
+		writeFindings(w, "rejected", c.Rejected)
+	}
+	return nil
+}
+
+func writeFindings(w io.Writer, kind string, findings []Finding) {
+	for _, f := range findings {
+		detail := ""
+		switch {
+		case f.Reason == ReasonWrongType:
+			detail = fmt.Sprintf(" (%s, expected %s)", f.Reason, f.ExpectedType)
+		case f.Reason != "":
+			detail = fmt.Sprintf(" (%s)", f.Reason)
+		}
+		_, _ = fmt.Fprintf(w, "  %s: %s %s%s\n", kind, f.Type, f.Value, detail)
+	}
+}
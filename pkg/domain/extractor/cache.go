@@ -0,0 +1,53 @@
+package extractor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// PromptVersion returns a short hash of the prompt templates used to extract
+// IoCs in mode. It changes whenever a template under prompts/ is edited, so
+// cached results of an older prompt are not used. Empty for modes without LLM.
+func PromptVersion(mode model.ExtractionMode) string {
+	var prompt string
+	switch mode {
+	case "", model.ExtractionModeLLM, model.ExtractionModeCrossCheck:
+		prompt = extractionPromptTemplate
+	case model.ExtractionModePrefilter:
+		prompt = classificationPromptTemplate
+	default:
+		return ""
+	}
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:6])
+}
+
+// PromptVersion returns the version of the prompts the extractor uses in mode.
+// It is PromptVersion(mode), changed by the per-source prompt customization.
+func (e *Extractor) PromptVersion(mode model.ExtractionMode) string {
+	version := PromptVersion(mode)
+	if version == "" || e.prompt == nil {
+		return version
+	}
+	sum := sha256.Sum256([]byte(version + "\x00" + e.prompt.Version()))
+	return hex.EncodeToString(sum[:6])
+}
+
+// CacheKey returns the key of the extraction result of an article in mode with
+// the LLM model llmModel, and the prompts and chunk sizes of the extractor, since
+// articles are split into different prompts when the chunk sizes change
+func (e *Extractor) CacheKey(mode model.ExtractionMode, llmModel, title, content string) string {
+	if mode == "" {
+		mode = model.ExtractionModeLLM
+	}
+	chunking := strconv.Itoa(e.chunkTokens) + "/" + strconv.Itoa(e.chunkOverlap)
+	h := sha256.New()
+	for _, part := range []string{e.PromptVersion(mode), string(mode), llmModel, chunking, title, content} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,50 @@
+package extractor_test
+
+import (
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func TestPromptVersion(t *testing.T) {
+	llm := extractor.PromptVersion(model.ExtractionModeLLM)
+	gt.S(t, llm).NotEqual("")
+	gt.Equal(t, extractor.PromptVersion(""), llm)
+	gt.Equal(t, extractor.PromptVersion(model.ExtractionModeCrossCheck), llm)
+	gt.S(t, extractor.PromptVersion(model.ExtractionModePrefilter)).NotEqual(llm)
+	gt.Equal(t, extractor.PromptVersion(model.ExtractionModeRegex), "")
+}
+
+func TestCacheKey(t *testing.T) {
+	ex := extractor.New(nil)
+	key := ex.CacheKey(model.ExtractionModeLLM, "gemini/default", "title", "content")
+	gt.Equal(t, ex.CacheKey("", "gemini/default", "title", "content"), key)
+
+	for _, other := range []string{
+		ex.CacheKey(model.ExtractionModeCrossCheck, "gemini/default", "title", "content"),
+		ex.CacheKey(model.ExtractionModeLLM, "claude/default", "title", "content"),
+		ex.CacheKey(model.ExtractionModeLLM, "gemini/default", "other", "content"),
+		ex.CacheKey(model.ExtractionModeLLM, "gemini/default", "title", "other"),
+		ex.CacheKey(model.ExtractionModeLLM, "gemini/default", "titlecontent", ""),
+		extractor.New(nil, extractor.WithChunking(1000, extractor.DefaultChunkOverlap)).CacheKey(model.ExtractionModeLLM, "gemini/default", "title", "content"),
+		extractor.New(nil, extractor.WithChunking(extractor.DefaultChunkTokens, 0)).CacheKey(model.ExtractionModeLLM, "gemini/default", "title", "content"),
+	} {
+		gt.S(t, other).NotEqual(key)
+	}
+
+	t.Run("parallelism does not change the key", func(t *testing.T) {
+		gt.Equal(t, extractor.New(nil, extractor.WithParallelism(8)).CacheKey(model.ExtractionModeLLM, "gemini/default", "title", "content"), key)
+	})
+
+	t.Run("prompt customization", func(t *testing.T) {
+		prompt, err := extractor.NewPrompt(&model.ExtractionPrompt{Language: "Japanese"})
+		gt.NoError(t, err)
+		custom := ex.WithPrompt(prompt)
+		gt.S(t, custom.PromptVersion(model.ExtractionModeLLM)).NotEqual(extractor.PromptVersion(model.ExtractionModeLLM))
+		gt.Equal(t, custom.PromptVersion(model.ExtractionModeRegex), "")
+		gt.S(t, custom.CacheKey(model.ExtractionModeLLM, "gemini/default", "title", "content")).NotEqual(key)
+		gt.Equal(t, ex.CacheKey(model.ExtractionModeLLM, "gemini/default", "title", "content"), key)
+	})
+}
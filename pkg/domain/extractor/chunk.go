@@ -0,0 +1,262 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/m-mizutani/goerr/v2"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// DefaultChunkTokens is the default maximum size of an article chunk in estimated tokens
+	DefaultChunkTokens = 8000
+	// DefaultChunkOverlap is the default number of estimated tokens repeated between chunks
+	DefaultChunkOverlap = 200
+	// DefaultParallelism is the default number of chunks extracted concurrently
+	DefaultParallelism = 4
+
+	// minChunkTokens is the smallest chunk size used when retrying truncated responses
+	minChunkTokens = 500
+)
+
+var errResponseTruncated = goerr.New("LLM response was truncated")
+
+// WithChunking sets the maximum chunk size and the overlap between chunks in
+// estimated tokens. Articles longer than maxTokens are split into chunks that
+// are extracted separately. Values <= 0 keep the defaults.
+func WithChunking(maxTokens, overlapTokens int) Option {
+	return func(e *Extractor) {
+		if maxTokens > 0 {
+			e.chunkTokens = maxTokens
+		}
+		if overlapTokens >= 0 {
+			e.chunkOverlap = overlapTokens
+		}
+	}
+}
+
+// WithParallelism sets how many chunks of an article are extracted concurrently.
+// 1 extracts chunks sequentially. Values <= 0 keep the default.
+func WithParallelism(n int) Option {
+	return func(e *Extractor) {
+		if n > 0 {
+			e.parallelism = n
+		}
+	}
+}
+
+// estimateTokens approximates the number of tokens of text without calling the
+// LLM: about 4 ASCII characters per token, and one token per non-ASCII character
+// (e.g. CJK text).
+func estimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// splitChunks splits text into chunks of at most maxTokens estimated tokens.
+// Chunks end at line boundaries where possible so that table rows stay intact,
+// and each chunk starts with up to overlapTokens of the end of the previous
+// chunk so that values on a boundary appear whole in one of them.
+func splitChunks(text string, maxTokens, overlapTokens int) []string {
+	if estimateTokens(text) <= maxTokens {
+		return []string{text}
+	}
+	overlapTokens = min(overlapTokens, maxTokens/2)
+
+	// Split into lines, and lines longer than a chunk into smaller pieces
+	var pieces []string
+	for _, line := range strings.SplitAfter(text, "\n") {
+		pieces = append(pieces, splitLongLine(line, maxTokens)...)
+	}
+
+	var chunks []string
+	var current []string
+	currentTokens := 0
+	for _, piece := range pieces {
+		tokens := estimateTokens(piece)
+		if currentTokens+tokens > maxTokens && len(current) > 0 {
+			chunks = append(chunks, strings.Join(current, ""))
+
+			// Carry over trailing pieces of the previous chunk as overlap
+			var overlap []string
+			overlapSize := 0
+			for i := len(current) - 1; i > 0; i-- {
+				t := estimateTokens(current[i])
+				if overlapSize+t > overlapTokens || overlapSize+t+tokens > maxTokens {
+					break
+				}
+				overlap = append([]string{current[i]}, overlap...)
+				overlapSize += t
+			}
+			current, currentTokens = overlap, overlapSize
+		}
+		current = append(current, piece)
+		currentTokens += tokens
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, strings.Join(current, ""))
+	}
+
+	return chunks
+}
+
+// splitLongLine splits a line exceeding maxTokens at whitespace, or at any
+// character if a word is longer than a chunk
+func splitLongLine(line string, maxTokens int) []string {
+	if estimateTokens(line) <= maxTokens {
+		return []string{line}
+	}
+
+	var parts []string
+	start, lastSpace, ascii, other := 0, -1, 0, 0
+	for i, r := range line {
+		end := i + utf8.RuneLen(r)
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+		if i > start && (ascii+3)/4+other > maxTokens {
+			cut := i
+			if lastSpace > start {
+				cut = lastSpace
+			}
+			parts = append(parts, line[start:cut])
+			start, lastSpace = cut, -1
+			ascii, other = 0, 0
+			for _, c := range line[start:end] {
+				if c < utf8.RuneSelf {
+					ascii++
+				} else {
+					other++
+				}
+			}
+		}
+		if r == ' ' || r == '\t' {
+			lastSpace = end
+		}
+	}
+	return append(parts, line[start:])
+}
+
+// extractChunked extracts IoCs from each chunk of content, in parallel up to the
+// configured parallelism, and merges the results in chunk order
+func (e *Extractor) extractChunked(ctx context.Context, title, content string) ([]*ExtractedIoC, error) {
+	chunks := splitChunks(content, e.chunkTokens, e.chunkOverlap)
+	results := make([][]*ExtractedIoC, len(chunks))
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(e.parallelism)
+	for i, chunk := range chunks {
+		eg.Go(func() error {
+			iocs, err := e.extractChunk(ctx, title, chunk, i+1, len(chunks), e.chunkTokens)
+			if err != nil {
+				return goerr.Wrap(err, "failed to extract IoCs from chunk",
+					goerr.V("chunk", i+1), goerr.V("chunks", len(chunks)))
+			}
+			results[i] = iocs
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	return mergeExtracted(results...), nil
+}
+
+// classifyChunked classifies the deterministic candidates of each chunk of
+// content as extractChunked does, and merges the malicious ones in chunk order.
+// A value in the overlap of two chunks is kept if either classifies it as
+// malicious.
+func (e *Extractor) classifyChunked(ctx context.Context, title, content string) ([]*ExtractedIoC, error) {
+	chunks := splitChunks(content, e.chunkTokens, e.chunkOverlap)
+	results := make([][]*ExtractedIoC, len(chunks))
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(e.parallelism)
+	for i, chunk := range chunks {
+		eg.Go(func() error {
+			iocs, err := e.classifyCandidates(ctx, title, chunk, i+1, len(chunks))
+			if err != nil {
+				return goerr.Wrap(err, "failed to classify candidates of chunk",
+					goerr.V("chunk", i+1), goerr.V("chunks", len(chunks)))
+			}
+			results[i] = iocs
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	return mergeExtracted(results...), nil
+}
+
+// extractChunk extracts IoCs from a chunk. If the LLM response is truncated
+// (typically because the chunk lists more values than fit into the output),
+// the chunk is split in half and each half is extracted again.
+func (e *Extractor) extractChunk(ctx context.Context, title, chunk string, part, parts, chunkTokens int) ([]*ExtractedIoC, error) {
+	var response extractionResponse
+	err := e.generate(ctx, llmOperationExtract, e.prompt.template(), e.prompt.data(title, chunk, part, parts),
+		getIoCSchema(e.prompt.types()), &response)
+	if err == nil {
+		return response.IoCs, nil
+	}
+
+	smaller := min(chunkTokens, estimateTokens(chunk)) / 2
+	if !errors.Is(err, errResponseTruncated) || smaller < minChunkTokens {
+		return nil, err
+	}
+
+	subChunks := splitChunks(chunk, smaller, min(e.chunkOverlap, smaller/4))
+	if len(subChunks) < 2 {
+		return nil, err
+	}
+
+	results := make([][]*ExtractedIoC, len(subChunks))
+	for i, sub := range subChunks {
+		iocs, err := e.extractChunk(ctx, title, sub, part, parts, smaller)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = iocs
+	}
+	return mergeExtracted(results...), nil
+}
+
+// mergeExtracted concatenates extraction results and removes duplicates by type
+// and normalized value, keeping the first occurrence
+func mergeExtracted(results ...[]*ExtractedIoC) []*ExtractedIoC {
+	var merged []*ExtractedIoC
+	seen := make(map[string]bool)
+	for _, iocs := range results {
+		for _, ioc := range iocs {
+			key := dedupKey(ioc)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, ioc)
+		}
+	}
+	return merged
+}
+
+func dedupKey(ioc *ExtractedIoC) string {
+	iocType := mapStringToIoCType(ioc.Type)
+	if iocType == "" {
+		return strings.ToLower(ioc.Type) + "\x00" + strings.TrimSpace(ioc.Value)
+	}
+	return string(iocType) + "\x00" + normalizeExtracted(iocType, ioc.Value)
+}
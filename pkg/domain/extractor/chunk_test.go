@@ -0,0 +1,274 @@
+package extractor_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gollem"
+	"github.com/m-mizutani/gollem/mock"
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	gt.Equal(t, extractor.EstimateTokens(""), 0)
+	gt.Equal(t, extractor.EstimateTokens("abcd"), 1)
+	gt.Equal(t, extractor.EstimateTokens("abcde"), 2)
+	gt.Equal(t, extractor.EstimateTokens("攻撃者"), 3)
+}
+
+func TestSplitChunks(t *testing.T) {
+	t.Run("short text is a single chunk", func(t *testing.T) {
+		gt.Equal(t, extractor.SplitChunks("short article", 100, 10), []string{"short article"})
+	})
+
+	t.Run("chunks end at lines and overlap", func(t *testing.T) {
+		var lines []string
+		for i := range 200 {
+			lines = append(lines, fmt.Sprintf("line %03d with some padding text\n", i))
+		}
+		text := strings.Join(lines, "")
+
+		chunks := extractor.SplitChunks(text, 100, 20)
+		gt.N(t, len(chunks)).Greater(1)
+		for _, chunk := range chunks {
+			gt.N(t, extractor.EstimateTokens(chunk)).LessOrEqual(100)
+			gt.True(t, strings.HasSuffix(chunk, "\n"))
+		}
+
+		// Every line is kept whole in some chunk
+		for _, line := range lines {
+			found := false
+			for _, chunk := range chunks {
+				if strings.Contains(chunk, line) {
+					found = true
+					break
+				}
+			}
+			gt.True(t, found).Describef("line %q is missing", line)
+		}
+
+		// Each chunk repeats the last line of the previous chunk
+		for i := 1; i < len(chunks); i++ {
+			prev := strings.SplitAfter(strings.TrimSuffix(chunks[i-1], "\n"), "\n")
+			last := prev[len(prev)-1] + "\n"
+			gt.True(t, strings.HasPrefix(chunks[i], prev[len(prev)-2]) || strings.HasPrefix(chunks[i], last))
+			gt.S(t, chunks[i]).Contains(last)
+		}
+	})
+
+	t.Run("long lines are split at spaces", func(t *testing.T) {
+		text := strings.Repeat("word ", 1000)
+		chunks := extractor.SplitChunks(text, 50, 0)
+		gt.N(t, len(chunks)).Greater(1)
+		gt.Equal(t, strings.Join(chunks, ""), text)
+		for _, chunk := range chunks {
+			gt.N(t, extractor.EstimateTokens(chunk)).LessOrEqual(50)
+			gt.True(t, strings.HasSuffix(chunk, " "))
+		}
+	})
+}
+
+var sha256Pattern = regexp.MustCompile(`\b[0-9a-f]{64}\b`)
+
+// hashAppendix returns an article with an appendix table of n SHA-256 hashes
+func hashAppendix(n int) (string, []string) {
+	var b strings.Builder
+	b.WriteString("# Incident report\n\nThe actor deployed the following files.\n\n| # | SHA-256 |\n|---|---|\n")
+	hashes := make([]string, n)
+	for i := range n {
+		sum := sha256.Sum256(fmt.Appendf(nil, "sample-%d", i))
+		hashes[i] = hex.EncodeToString(sum[:])
+		fmt.Fprintf(&b, "| %d | %s |\n", i+1, hashes[i])
+	}
+	return b.String(), hashes
+}
+
+// contentOf returns the article content section of an extraction prompt
+func contentOf(prompt string) string {
+	_, content, _ := strings.Cut(prompt, "**Content:**")
+	content, _, _ = strings.Cut(content, "## Task")
+	return content
+}
+
+// hashResponse returns an extraction response listing all SHA-256 hashes in the prompt
+func hashResponse(prompt string) string {
+	var iocs []map[string]string
+	for _, h := range sha256Pattern.FindAllString(contentOf(prompt), -1) {
+		iocs = append(iocs, map[string]string{"type": "sha256", "value": h, "description": "Dropped file"})
+	}
+	raw, _ := json.Marshal(map[string]any{"iocs": iocs})
+	return string(raw)
+}
+
+func newPromptLLM(respond func(prompt string) (string, error)) *mock.LLMClientMock {
+	return &mock.LLMClientMock{
+		NewSessionFunc: func(ctx context.Context, options ...gollem.SessionOption) (gollem.Session, error) {
+			return &mock.SessionMock{
+				GenerateContentFunc: func(ctx context.Context, input ...gollem.Input) (*gollem.Response, error) {
+					prompt := string(input[0].(gollem.Text))
+					text, err := respond(prompt)
+					if err != nil {
+						return nil, err
+					}
+					return &gollem.Response{Texts: []string{text}}, nil
+				},
+			}, nil
+		},
+	}
+}
+
+func TestExtractFromArticle_Chunked(t *testing.T) {
+	ctx := context.Background()
+	article, hashes := hashAppendix(300)
+
+	var (
+		mu       sync.Mutex
+		prompts  []string
+		running  atomic.Int32
+		maxInUse atomic.Int32
+	)
+	llm := newPromptLLM(func(prompt string) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxInUse.Load()
+			if n <= m || maxInUse.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		prompts = append(prompts, prompt)
+		mu.Unlock()
+		return hashResponse(prompt), nil
+	})
+
+	ext := extractor.New(llm, extractor.WithChunking(1000, 100), extractor.WithParallelism(3))
+	iocs, err := ext.ExtractFromArticle(ctx, "Incident report", article)
+	gt.NoError(t, err)
+
+	// Every hash is extracted once, in article order, although chunks overlap
+	gt.A(t, iocs).Length(len(hashes))
+	for i, ioc := range iocs {
+		gt.Equal(t, ioc.Value, hashes[i])
+	}
+
+	chunks := extractor.SplitChunks(article, 1000, 100)
+	gt.N(t, len(chunks)).Greater(3)
+	gt.A(t, prompts).Length(len(chunks))
+	gt.N(t, maxInUse.Load()).LessOrEqual(3).Greater(1)
+	for _, prompt := range prompts {
+		gt.S(t, prompt).Contains(fmt.Sprintf("of %d.", len(chunks)))
+	}
+
+	t.Run("short articles are extracted in one prompt", func(t *testing.T) {
+		var prompts []string
+		llm := newMockLLM(`{"iocs": []}`, &prompts)
+		_, err := extractor.New(llm).ExtractFromArticle(ctx, "title", "short article")
+		gt.NoError(t, err)
+		gt.A(t, prompts).Length(1)
+		gt.S(t, prompts[0]).NotContains("**Part:**")
+	})
+}
+
+func TestExtractFromArticle_RetriesTruncatedResponses(t *testing.T) {
+	ctx := context.Background()
+	article, hashes := hashAppendix(120)
+
+	// The model can only output 40 hashes; longer responses are cut off
+	var calls atomic.Int32
+	llm := newPromptLLM(func(prompt string) (string, error) {
+		calls.Add(1)
+		resp := hashResponse(prompt)
+		if n := len(sha256Pattern.FindAllString(contentOf(prompt), -1)); n > 40 {
+			return resp[:len(resp)/2], nil
+		}
+		return resp, nil
+	})
+
+	ext := extractor.New(llm, extractor.WithChunking(4000, 100), extractor.WithParallelism(1))
+	iocs, err := ext.ExtractFromArticle(ctx, "Incident report", article)
+	gt.NoError(t, err)
+	gt.A(t, iocs).Length(len(hashes))
+	gt.N(t, int(calls.Load())).Greater(1)
+
+	t.Run("invalid responses are not retried", func(t *testing.T) {
+		var calls atomic.Int32
+		llm := newPromptLLM(func(prompt string) (string, error) {
+			calls.Add(1)
+			return `{"iocs": "not a list"}`, nil
+		})
+		_, err := extractor.New(llm, extractor.WithChunking(4000, 100)).ExtractFromArticle(ctx, "title", article)
+		gt.Error(t, err)
+		gt.Equal(t, calls.Load(), int32(1))
+	})
+
+	t.Run("a failing chunk fails the extraction", func(t *testing.T) {
+		llm := newPromptLLM(func(prompt string) (string, error) {
+			if strings.Contains(contentOf(prompt), hashes[len(hashes)-1]) {
+				return "", fmt.Errorf("quota exceeded")
+			}
+			return hashResponse(prompt), nil
+		})
+		_, err := extractor.New(llm, extractor.WithChunking(1000, 100)).ExtractFromArticle(ctx, "title", article)
+		gt.Error(t, err)
+	})
+}
+
+func TestExtract_PrefilterChunked(t *testing.T) {
+	ctx := context.Background()
+	article, hashes := hashAppendix(300)
+
+	var (
+		mu      sync.Mutex
+		prompts []string
+	)
+	llm := newPromptLLM(func(prompt string) (string, error) {
+		mu.Lock()
+		prompts = append(prompts, prompt)
+		mu.Unlock()
+
+		var candidates []map[string]any
+		for _, h := range sha256Pattern.FindAllString(contentOf(prompt), -1) {
+			candidates = append(candidates, map[string]any{"value": h, "malicious": true, "description": "Dropped file"})
+		}
+		raw, _ := json.Marshal(map[string]any{"candidates": candidates})
+		return string(raw), nil
+	})
+
+	ext := extractor.New(llm, extractor.WithChunking(1000, 100), extractor.WithParallelism(3))
+	result, err := ext.Extract(ctx, model.ExtractionModePrefilter, "Incident report", article)
+	gt.NoError(t, err)
+
+	// Each chunk is classified with its own candidates
+	chunks := extractor.SplitChunks(article, 1000, 100)
+	gt.N(t, len(chunks)).Greater(3)
+	gt.A(t, prompts).Length(len(chunks))
+	for _, prompt := range prompts {
+		gt.S(t, prompt).Contains(fmt.Sprintf("of %d.", len(chunks)))
+		gt.N(t, countCandidates(prompt)).LessOrEqual(len(hashes) / 2)
+	}
+
+	gt.A(t, result.IoCs).Length(len(hashes))
+	for i, ioc := range result.IoCs {
+		gt.Equal(t, ioc.Value, hashes[i])
+	}
+}
+
+// countCandidates counts the candidate lines of a classification prompt
+func countCandidates(prompt string) int {
+	_, list, _ := strings.Cut(prompt, "## Candidates")
+	return strings.Count(list, "\n- sha256: ")
+}
@@ -0,0 +1,288 @@
+package extractor
+
+import (
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"golang.org/x/net/publicsuffix"
+)
+
+var (
+	urlPattern    = regexp.MustCompile(`(?i)\b(?:https?|ftp)://[^\s<>"'\x60\\]+`)
+	emailPattern  = regexp.MustCompile(`(?i)\b[a-z0-9][a-z0-9._%+-]*@(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z][a-z0-9-]*[a-z0-9]\b`)
+	ipv4Pattern   = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}(?:/\d{1,3})?\b`)
+	ipv6Pattern   = regexp.MustCompile(`(?i)(?:[0-9a-f]{1,4})?(?::(?:[0-9a-f]{1,4})?){2,7}(?:/\d{1,3})?`)
+	hashPattern   = regexp.MustCompile(`\b[0-9a-fA-F]{32,64}\b`)
+	regKeyPattern = regexp.MustCompile(`(?i)\b(?:HKEY_(?:LOCAL_MACHINE|CURRENT_USER|CLASSES_ROOT|USERS|CURRENT_CONFIG)|HKLM|HKCU|HKCR|HKU|HKCC):?\\[^\s"'<>|\x60]+`)
+	certPattern   = regexp.MustCompile(`(?i)\b[0-9a-f]{2}(?::[0-9a-f]{2}){15,31}\b`)
+	macPattern    = regexp.MustCompile(`(?i)\b[0-9a-f]{2}(?::[0-9a-f]{2}){5}\b|\b[0-9a-f]{2}(?:-[0-9a-f]{2}){5}\b`)
+	asnPattern    = regexp.MustCompile(`\bAS(?:N ?)?[0-9]{1,10}\b`)
+	domainPattern = regexp.MustCompile(`(?i)\b(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z][a-z0-9-]{0,61}[a-z0-9]\b`)
+)
+
+// fileExtensionTLDs are top-level domains that are far more often file extensions
+// in article text ("setup.zip", "README.md"). Two-label names under them are not
+// extracted as domains.
+var fileExtensionTLDs = map[string]bool{
+	"md": true, "py": true, "sh": true, "zip": true, "mov": true,
+	"pl": true, "rs": true, "so": true, "ps": true,
+}
+
+// maxContextLength is the maximum length of the context kept as the description
+// of deterministically extracted IoCs
+const maxContextLength = 200
+
+// ExtractDeterministic extracts candidate IoCs from text with validated patterns.
+// Network indicators, hashes, certificate fingerprints, MAC addresses, AS numbers
+// ("AS13335") and registry keys are extracted; types without a reliable pattern
+// (file names, processes, mutexes and User-Agents) are left to the LLM.
+// The text is refanged first. Domains and IP addresses that only occur inside an
+// extracted URL or email address are not reported separately, IP ranges (CIDR
+// notation other than single hosts) and non-global addresses are skipped, and
+// hashes are classified by length. The description of each candidate is the text
+// surrounding its first occurrence. Results are deduplicated by normalized value
+// in order of appearance.
+func ExtractDeterministic(text string) []*ExtractedIoC {
+	text = Refang(text)
+	// masked has matched URLs and emails blanked out so that their hosts are not
+	// extracted again; offsets are identical to text
+	masked := []byte(text)
+
+	var results []*ExtractedIoC
+	seen := make(map[string]bool)
+	add := func(iocType model.IoCType, value string, start, end int) {
+		value = model.NormalizeValue(iocType, value)
+		key := string(iocType) + "\x00" + value
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		results = append(results, &ExtractedIoC{
+			Type:        string(iocType),
+			Value:       value,
+			Description: contextSnippet(text, start, end),
+		})
+	}
+	mask := func(start, end int) {
+		for i := start; i < end; i++ {
+			masked[i] = ' '
+		}
+	}
+
+	for _, loc := range urlPattern.FindAllStringIndex(text, -1) {
+		raw := trimURL(text[loc[0]:loc[1]])
+		end := loc[0] + len(raw)
+		if isValidURL(raw) {
+			add(model.IoCTypeURL, raw, loc[0], end)
+		}
+		mask(loc[0], end)
+	}
+
+	for _, loc := range emailPattern.FindAllStringIndex(string(masked), -1) {
+		raw := text[loc[0]:loc[1]]
+		if isValidDomain(raw[strings.LastIndex(raw, "@")+1:]) {
+			add(model.IoCTypeEmail, raw, loc[0], loc[1])
+		}
+		mask(loc[0], loc[1])
+	}
+
+	for _, loc := range regKeyPattern.FindAllStringIndex(string(masked), -1) {
+		raw := trimRegKey(text[loc[0]:loc[1]])
+		end := loc[0] + len(raw)
+		add(model.IoCTypeRegKey, raw, loc[0], end)
+		mask(loc[0], end)
+	}
+
+	for _, loc := range certPattern.FindAllStringIndex(string(masked), -1) {
+		// MD5, SHA-1 and SHA-256 fingerprints have 16, 20 and 32 bytes
+		switch (loc[1] - loc[0] + 1) / 3 {
+		case 16, 20, 32:
+			add(model.IoCTypeCertHash, text[loc[0]:loc[1]], loc[0], loc[1])
+			mask(loc[0], loc[1])
+		}
+	}
+
+	for _, loc := range macPattern.FindAllStringIndex(string(masked), -1) {
+		// Skip MAC-like runs that are part of a longer colon separated value
+		if (loc[0] > 0 && isWordOrColon(masked[loc[0]-1])) || (loc[1] < len(masked) && isWordOrColon(masked[loc[1]])) {
+			continue
+		}
+		add(model.IoCTypeMacAddr, text[loc[0]:loc[1]], loc[0], loc[1])
+		mask(loc[0], loc[1])
+	}
+
+	for _, loc := range asnPattern.FindAllStringIndex(string(masked), -1) {
+		if model.ValidateValue(model.IoCTypeASN, model.NormalizeValue(model.IoCTypeASN, text[loc[0]:loc[1]])) == nil {
+			add(model.IoCTypeASN, text[loc[0]:loc[1]], loc[0], loc[1])
+		}
+	}
+
+	for _, loc := range ipv4Pattern.FindAllStringIndex(string(masked), -1) {
+		if addr, ok := parseHostAddr(text[loc[0]:loc[1]]); ok && addr.Is4() {
+			add(model.IoCTypeIPv4, addr.String(), loc[0], loc[1])
+		}
+		mask(loc[0], loc[1])
+	}
+
+	for _, loc := range ipv6Pattern.FindAllStringIndex(string(masked), -1) {
+		// The pattern has no boundaries as it may start or end with a colon
+		if (loc[0] > 0 && isWordOrColon(masked[loc[0]-1])) || (loc[1] < len(masked) && isWordOrColon(masked[loc[1]])) {
+			continue
+		}
+		if addr, ok := parseHostAddr(text[loc[0]:loc[1]]); ok && addr.Is6() && !addr.Is4In6() {
+			add(model.IoCTypeIPv6, addr.String(), loc[0], loc[1])
+			mask(loc[0], loc[1])
+		}
+	}
+
+	for _, loc := range hashPattern.FindAllStringIndex(string(masked), -1) {
+		var iocType model.IoCType
+		switch loc[1] - loc[0] {
+		case 32:
+			iocType = model.IoCTypeMD5
+		case 40:
+			iocType = model.IoCTypeSHA1
+		case 64:
+			iocType = model.IoCTypeSHA256
+		default:
+			continue
+		}
+		add(iocType, text[loc[0]:loc[1]], loc[0], loc[1])
+		mask(loc[0], loc[1])
+	}
+
+	for _, loc := range domainPattern.FindAllStringIndex(string(masked), -1) {
+		raw := text[loc[0]:loc[1]]
+		labels := strings.Split(strings.ToLower(raw), ".")
+		if len(labels) == 2 && fileExtensionTLDs[labels[1]] {
+			continue
+		}
+		if isValidDomain(raw) {
+			add(model.IoCTypeDomain, raw, loc[0], loc[1])
+		}
+	}
+
+	return results
+}
+
+// isValidDomain returns true if the name ends in an ICANN top-level domain
+func isValidDomain(name string) bool {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	dot := strings.LastIndex(name, ".")
+	if dot <= 0 {
+		return false
+	}
+	_, icann := publicsuffix.PublicSuffix(name[dot+1:])
+	return icann
+}
+
+// isValidURL returns true if the URL parses and its host is a global IP address
+// or a domain with a valid top-level domain
+func isValidURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return addr.IsGlobalUnicast() && !addr.IsPrivate()
+	}
+	return isValidDomain(host)
+}
+
+// parseHostAddr parses an address optionally in CIDR notation. Prefixes are only
+// accepted when they denote a single host, and only global unicast addresses are
+// returned.
+func parseHostAddr(raw string) (netip.Addr, bool) {
+	addrPart, bits, hasPrefix := strings.Cut(raw, "/")
+	addr, err := netip.ParseAddr(addrPart)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	if hasPrefix {
+		n, err := strconv.Atoi(bits)
+		if err != nil || n != addr.BitLen() {
+			return netip.Addr{}, false
+		}
+	}
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return netip.Addr{}, false
+	}
+	return addr, true
+}
+
+// trimURL removes trailing punctuation that belongs to the surrounding sentence,
+// including closing brackets without a matching opening bracket in the URL
+func trimURL(raw string) string {
+	pairs := map[byte]byte{')': '(', ']': '[', '}': '{'}
+	for len(raw) > 0 {
+		last := raw[len(raw)-1]
+		if strings.IndexByte(".,;:!?'\"", last) >= 0 {
+			raw = raw[:len(raw)-1]
+			continue
+		}
+		if open, ok := pairs[last]; ok && strings.Count(raw, string(open)) < strings.Count(raw, string(last)) {
+			raw = raw[:len(raw)-1]
+			continue
+		}
+		break
+	}
+	return raw
+}
+
+// contextSnippet returns the line containing text[start:end] with whitespace
+// collapsed, shortened around the match to at most maxContextLength bytes
+func contextSnippet(text string, start, end int) string {
+	lineStart := strings.LastIndexByte(text[:start], '\n') + 1
+	lineEnd := len(text)
+	if i := strings.IndexByte(text[end:], '\n'); i >= 0 {
+		lineEnd = end + i
+	}
+
+	if lineEnd-lineStart > maxContextLength {
+		margin := max((maxContextLength-(end-start))/2, 0)
+		lineStart = max(lineStart, start-margin)
+		lineEnd = min(lineEnd, end+margin)
+		// Do not cut UTF-8 sequences
+		for lineStart < start && !isRuneStart(text[lineStart]) {
+			lineStart++
+		}
+		for lineEnd > end && lineEnd < len(text) && !isRuneStart(text[lineEnd]) {
+			lineEnd--
+		}
+	}
+
+	return strings.Join(strings.Fields(text[lineStart:lineEnd]), " ")
+}
+
+func isWordOrColon(b byte) bool {
+	return b == ':' || ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// trimRegKey removes trailing punctuation from a registry key match. Closing
+// brackets are kept when the key opens them, e.g. CLSID keys like "{...}".
+func trimRegKey(raw string) string {
+	for raw != "" {
+		last := raw[len(raw)-1]
+		switch last {
+		case '.', ',', ';', ':', '!', '?':
+		case ')', ']', '}':
+			open := map[byte]string{')': "(", ']': "[", '}': "{"}[last]
+			if strings.Count(raw, open) >= strings.Count(raw, string(last)) {
+				return raw
+			}
+		default:
+			return raw
+		}
+		raw = raw[:len(raw)-1]
+	}
+	return raw
+}
@@ -0,0 +1,203 @@
+package extractor_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/gollem"
+	"github.com/m-mizutani/gollem/mock"
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func TestRefang(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"hxxp://evil[.]com/a", "http://evil.com/a"},
+		{"hXXps[://]evil(.)com", "https://evil.com"},
+		{"1.2.3[.]4", "1.2.3.4"},
+		{"198[.]51[.]100[.]7", "198.51.100.7"},
+		{"evil [.] com", "evil.com"},
+		{"evil[dot]com", "evil.com"},
+		{"admin[@]evil{.}com", "admin@evil.com"},
+		{"admin[at]evil[.]com", "admin@evil.com"},
+		{"fxp://files[.]evil[.]com", "ftp://files.evil.com"},
+		{"h[tt]ps://evil[.]com[/]path", "https://evil.com/path"},
+		{"plain text (with parentheses) stays", "plain text (with parentheses) stays"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			gt.Equal(t, extractor.Refang(tc.input), tc.expected)
+		})
+	}
+}
+
+const defangedArticle = `# Campaign analysis
+
+The loader downloads its payload from hxxp://cdn.evil-domain[.]ru/malware.exe and
+beacons to 198.51.100[.]42 on port 8443 (see 198.51.100.42:8443).
+A second stage talks to update-check[.]xyz. Phishing mails came from support[@]secure-banking-verify[.]net.
+IPv6 C2: 2001:db8:85a3::8a2e:370:7334, internal host 10.0.0.5 and fe80::1 are not reported.
+The operators own 203.0.113.0/24; only 203.0.113.9/32 was observed.
+
+Hashes:
+- 5D41402ABC4B2A76B9719D911017C592
+- aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d
+- e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855
+- 0123456789abcdef0123 (too short)
+
+The dropper is setup.zip, it reads config.json and writes README.md at 07:24:23.
+More details in the vendor report (https://vendor.example.com/blog/analysis).
+`
+
+func TestExtractDeterministic(t *testing.T) {
+	extracted := extractor.ExtractDeterministic(defangedArticle)
+
+	got := make(map[string]string)
+	for _, ioc := range extracted {
+		gt.False(t, strings.Contains(got[ioc.Value], ioc.Type)).Describef("duplicate %s", ioc.Value)
+		got[ioc.Value] = ioc.Type
+		gt.S(t, ioc.Description).NotEqual("")
+	}
+
+	expected := map[string]string{
+		"http://cdn.evil-domain.ru/malware.exe":    "url",
+		"https://vendor.example.com/blog/analysis": "url",
+		"198.51.100.42":                            "ipv4",
+		"203.0.113.9":                              "ipv4",
+		"2001:db8:85a3::8a2e:370:7334":             "ipv6",
+		"update-check.xyz":                         "domain",
+		"support@secure-banking-verify.net":        "email",
+		"5d41402abc4b2a76b9719d911017c592":         "md5",
+		"aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d": "sha1",
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855": "sha256",
+	}
+	gt.Equal(t, got, expected)
+
+	t.Run("description is the surrounding line", func(t *testing.T) {
+		for _, ioc := range extracted {
+			if ioc.Value == "update-check.xyz" {
+				gt.S(t, ioc.Description).Contains("A second stage talks to update-check.xyz.")
+			}
+		}
+	})
+}
+
+func TestExtractDeterministic_LongLineContext(t *testing.T) {
+	line := strings.Repeat("lorem ipsum ", 100) + "evil-domain.ru" + strings.Repeat(" dolor sit", 100)
+	extracted := extractor.ExtractDeterministic(line)
+	gt.A(t, extracted).Length(1).At(0, func(t testing.TB, ioc *extractor.ExtractedIoC) {
+		gt.Equal(t, ioc.Value, "evil-domain.ru")
+		gt.True(t, len(ioc.Description) <= 200)
+		gt.S(t, ioc.Description).Contains("evil-domain.ru")
+	})
+}
+
+func TestCrossCheck(t *testing.T) {
+	iocs := []*extractor.ExtractedIoC{
+		{Type: "ipv4", Value: "198.51.100.42"},
+		{Type: "url", Value: "HXXP://CDN.EVIL-DOMAIN[.]RU/malware.exe"},
+		{Type: "ipv6", Value: "2001:0db8:85a3:0000:0000:8a2e:0370:7334"},
+		{Type: "domain", Value: "hallucinated-c2.com"},
+		{Type: "sha256", Value: "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"},
+	}
+
+	verified, unverified := extractor.CrossCheck(iocs, defangedArticle)
+	gt.A(t, verified).Length(3)
+	gt.A(t, unverified).Length(2).
+		At(0, func(t testing.TB, ioc *extractor.ExtractedIoC) { gt.Equal(t, ioc.Value, "hallucinated-c2.com") }).
+		At(1, func(t testing.TB, ioc *extractor.ExtractedIoC) { gt.Equal(t, ioc.Type, "sha256") })
+}
+
+func newMockLLM(response string, prompts *[]string) *mock.LLMClientMock {
+	return &mock.LLMClientMock{
+		NewSessionFunc: func(ctx context.Context, options ...gollem.SessionOption) (gollem.Session, error) {
+			return &mock.SessionMock{
+				GenerateContentFunc: func(ctx context.Context, input ...gollem.Input) (*gollem.Response, error) {
+					for _, in := range input {
+						if text, ok := in.(gollem.Text); ok {
+							*prompts = append(*prompts, string(text))
+						}
+					}
+					return &gollem.Response{Texts: []string{response}}, nil
+				},
+			}, nil
+		},
+	}
+}
+
+func TestExtract_Modes(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("regex mode does not need an LLM", func(t *testing.T) {
+		result, err := extractor.New(nil).Extract(ctx, model.ExtractionModeRegex, "title", defangedArticle)
+		gt.NoError(t, err)
+		gt.A(t, result.IoCs).Length(10)
+	})
+
+	t.Run("prefilter keeps candidates classified as malicious", func(t *testing.T) {
+		var prompts []string
+		llm := newMockLLM(`{"candidates": [
+			{"value": "198.51.100.42", "malicious": true, "description": "C2 server"},
+			{"value": "https://vendor.example.com/blog/analysis", "malicious": false, "description": "Vendor report"},
+			{"value": "hxxp://cdn.evil-domain[.]ru/malware.exe", "malicious": true, "description": "Payload download"},
+			{"value": "not-a-candidate.com", "malicious": true, "description": "Invented"}
+		]}`, &prompts)
+
+		result, err := extractor.New(llm).Extract(ctx, model.ExtractionModePrefilter, "title", defangedArticle)
+		gt.NoError(t, err)
+		gt.A(t, result.IoCs).Length(2).
+			At(0, func(t testing.TB, ioc *extractor.ExtractedIoC) {
+				gt.Equal(t, ioc.Type, "url")
+				gt.Equal(t, ioc.Value, "http://cdn.evil-domain.ru/malware.exe")
+				gt.Equal(t, ioc.Description, "Payload download")
+			}).
+			At(1, func(t testing.TB, ioc *extractor.ExtractedIoC) {
+				gt.Equal(t, ioc.Type, "ipv4")
+				gt.Equal(t, ioc.Description, "C2 server")
+			})
+		gt.A(t, prompts).Length(1)
+		gt.S(t, prompts[0]).Contains("- ipv4: 198.51.100.42")
+	})
+
+	t.Run("prefilter skips the LLM without candidates", func(t *testing.T) {
+		var prompts []string
+		llm := newMockLLM(`{"candidates": []}`, &prompts)
+		result, err := extractor.New(llm).Extract(ctx, model.ExtractionModePrefilter, "title", "nothing to see here")
+		gt.NoError(t, err)
+		gt.A(t, result.IoCs).Length(0)
+		gt.A(t, prompts).Length(0)
+	})
+
+	t.Run("crosscheck reports values missing from the article", func(t *testing.T) {
+		var prompts []string
+		llm := newMockLLM(`{"iocs": [
+			{"type": "ipv4", "value": "198.51.100.42", "description": "C2 server"},
+			{"type": "domain", "value": "hallucinated-c2.com", "description": "C2 domain"}
+		]}`, &prompts)
+
+		result, err := extractor.New(llm).Extract(ctx, model.ExtractionModeCrossCheck, "title", defangedArticle)
+		gt.NoError(t, err)
+		gt.A(t, result.IoCs).Length(1)
+		gt.A(t, result.Unverified).Length(1).At(0, func(t testing.TB, ioc *extractor.ExtractedIoC) {
+			gt.Equal(t, ioc.Value, "hallucinated-c2.com")
+		})
+	})
+
+	t.Run("unknown mode", func(t *testing.T) {
+		_, err := extractor.New(nil).Extract(ctx, model.ExtractionMode("magic"), "title", defangedArticle)
+		gt.Error(t, err)
+	})
+}
+
+func TestConvertToIoC_Refangs(t *testing.T) {
+	ioc, err := extractor.ConvertToIoC("blog", "rss", "https://blog.example.com/post",
+		&extractor.ExtractedIoC{Type: "domain", Value: "Evil-Domain[.]ru"}, nil)
+	gt.NoError(t, err)
+	gt.Equal(t, ioc.Value, "evil-domain.ru")
+}
@@ -0,0 +1,13 @@
+package extractor
+
+// Export internal functions for testing
+
+// SplitChunks is exported for testing
+func SplitChunks(text string, maxTokens, overlapTokens int) []string {
+	return splitChunks(text, maxTokens, overlapTokens)
+}
+
+// EstimateTokens is exported for testing
+func EstimateTokens(text string) int {
+	return estimateTokens(text)
+}
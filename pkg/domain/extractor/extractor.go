@@ -5,12 +5,17 @@ import (
 	"context"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/m-mizutani/goerr/v2"
 	"github.com/m-mizutani/gollem"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
 	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -22,6 +27,11 @@ var extractionPromptTemplate string
 
 var extractionTmpl = template.Must(template.New("extraction").Parse(extractionPromptTemplate))
 
+//go:embed prompts/classify_candidates.md
+var classificationPromptTemplate string
+
+var classificationTmpl = template.Must(template.New("classification").Parse(classificationPromptTemplate))
+
 // ExtractedIoC represents an IoC extracted from text
 type ExtractedIoC struct {
 	Type        string `json:"type"`
@@ -29,8 +39,8 @@ type ExtractedIoC struct {
 	Description string `json:"description"`
 }
 
-// getIoCSchema returns the JSON schema for IoC extraction
-func getIoCSchema() *gollem.Parameter {
+// getIoCSchema returns the JSON schema for IoC extraction of the given type names
+func getIoCSchema(types []string) *gollem.Parameter {
 	return &gollem.Parameter{
 		Type:        gollem.TypeObject,
 		Description: "Extracted Indicators of Compromise from the article",
@@ -45,7 +55,7 @@ func getIoCSchema() *gollem.Parameter {
 						"type": {
 							Type:        gollem.TypeString,
 							Description: "The type of Indicator of Compromise",
-							Enum:        []string{"ipv4", "ipv6", "domain", "url", "email", "md5", "sha1", "sha256"},
+							Enum:        types,
 						},
 						"value": {
 							Type:        gollem.TypeString,
@@ -64,11 +74,71 @@ func getIoCSchema() *gollem.Parameter {
 	}
 }
 
+// iocTypeNames returns the names of all IoC types for the extraction schema
+func iocTypeNames() []string {
+	names := make([]string, len(model.AllIoCTypes))
+	for i, t := range model.AllIoCTypes {
+		names[i] = string(t)
+	}
+	return names
+}
+
 // extractionResponse represents the structured response from LLM
 type extractionResponse struct {
 	IoCs []*ExtractedIoC `json:"iocs"`
 }
 
+// getClassificationSchema returns the JSON schema for candidate classification
+func getClassificationSchema() *gollem.Parameter {
+	return &gollem.Parameter{
+		Type:        gollem.TypeObject,
+		Description: "Classification of IoC candidates found in the article",
+		Properties: map[string]*gollem.Parameter{
+			"candidates": {
+				Type:        gollem.TypeArray,
+				Description: "Classification of each candidate",
+				Items: &gollem.Parameter{
+					Type:        gollem.TypeObject,
+					Description: "Individual candidate",
+					Properties: map[string]*gollem.Parameter{
+						"value": {
+							Type:        gollem.TypeString,
+							Description: "The candidate value exactly as listed",
+						},
+						"malicious": {
+							Type:        gollem.TypeBoolean,
+							Description: "True if the value is part of the attack described in the article",
+						},
+						"description": {
+							Type:        gollem.TypeString,
+							Description: "Brief context or description of the value from the article",
+						},
+					},
+					Required: []string{"value", "malicious", "description"},
+				},
+			},
+		},
+		Required: []string{"candidates"},
+	}
+}
+
+// classificationResponse represents the structured classification response from LLM
+type classificationResponse struct {
+	Candidates []struct {
+		Value       string `json:"value"`
+		Malicious   bool   `json:"malicious"`
+		Description string `json:"description"`
+	} `json:"candidates"`
+}
+
+// Result is the outcome of Extract
+type Result struct {
+	IoCs []*ExtractedIoC
+	// Unverified holds values extracted by the LLM that do not occur in the article.
+	// They are only reported in model.ExtractionModeCrossCheck and are not in IoCs.
+	Unverified []*ExtractedIoC
+}
+
 // Vectorizer interface for generating embeddings
 type Vectorizer interface {
 	Vectorize(value string) ([]float32, error)
@@ -80,6 +150,14 @@ type Extractor struct {
 	llmClient  gollem.LLMClient
 	vectorizer Vectorizer
 	useNGram   bool
+	embedder   interfaces.Embedder
+	metrics    interfaces.Metrics
+
+	chunkTokens  int
+	chunkOverlap int
+	parallelism  int
+
+	prompt *Prompt
 }
 
 // Option configures Extractor
@@ -93,10 +171,27 @@ func WithNGramVectorizer(v Vectorizer) Option {
 	}
 }
 
+// WithEmbedder sets the embedder of GenerateEmbedding. It takes precedence over WithNGramVectorizer.
+func WithEmbedder(embedder interfaces.Embedder) Option {
+	return func(e *Extractor) {
+		e.embedder = embedder
+	}
+}
+
+// WithMetrics records the latency and token usage of LLM calls in metrics
+func WithMetrics(metrics interfaces.Metrics) Option {
+	return func(e *Extractor) {
+		e.metrics = metrics
+	}
+}
+
 // New creates a new IoC extractor
 func New(llmClient gollem.LLMClient, opts ...Option) *Extractor {
 	e := &Extractor{
-		llmClient: llmClient,
+		llmClient:    llmClient,
+		chunkTokens:  DefaultChunkTokens,
+		chunkOverlap: DefaultChunkOverlap,
+		parallelism:  DefaultParallelism,
 	}
 
 	for _, opt := range opts {
@@ -106,35 +201,149 @@ func New(llmClient gollem.LLMClient, opts ...Option) *Extractor {
 	return e
 }
 
-// ExtractFromArticle extracts IoCs from a blog article using LLM
+// WithPrompt returns a copy of the extractor that extracts IoCs with the
+// per-source prompt customization p. A nil p is the built-in prompt.
+func (e *Extractor) WithPrompt(p *Prompt) *Extractor {
+	c := *e
+	c.prompt = p
+	return &c
+}
+
+// Extract extracts IoCs from a blog article with the given mode. An empty mode
+// is treated as model.ExtractionModeLLM. model.ExtractionModeRegex does not use
+// the LLM client. IoCs of types not allowed by the prompt customization are
+// dropped in all modes.
+func (e *Extractor) Extract(ctx context.Context, mode model.ExtractionMode, title, content string) (*Result, error) {
+	switch mode {
+	case "", model.ExtractionModeLLM:
+		iocs, err := e.ExtractFromArticle(ctx, title, content)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{IoCs: e.prompt.filter(iocs)}, nil
+
+	case model.ExtractionModeRegex:
+		return &Result{IoCs: e.prompt.filter(ExtractDeterministic(content))}, nil
+
+	case model.ExtractionModePrefilter:
+		iocs, err := e.classifyChunked(ctx, title, content)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{IoCs: iocs}, nil
+
+	case model.ExtractionModeCrossCheck:
+		iocs, err := e.ExtractFromArticle(ctx, title, content)
+		if err != nil {
+			return nil, err
+		}
+		verified, unverified := CrossCheck(e.prompt.filter(iocs), content)
+		return &Result{IoCs: verified, Unverified: unverified}, nil
+
+	default:
+		return nil, goerr.New("unknown extraction mode", goerr.V("mode", mode))
+	}
+}
+
+// ExtractFromArticle extracts IoCs from a blog article using LLM. Long articles
+// are split into overlapping chunks (see WithChunking) that are extracted
+// separately, and the results are merged and deduplicated by normalized value.
 func (e *Extractor) ExtractFromArticle(ctx context.Context, title, content string) ([]*ExtractedIoC, error) {
+	return e.extractChunked(ctx, title, content)
+}
+
+// classifyCandidates asks the LLM to classify the deterministic candidates of a
+// chunk and returns the malicious ones. Types and values are taken from the
+// candidates, so values the LLM adds or alters are ignored. The LLM is not
+// called without candidates.
+func (e *Extractor) classifyCandidates(ctx context.Context, title, chunk string, part, parts int) ([]*ExtractedIoC, error) {
+	candidates := e.prompt.filter(ExtractDeterministic(chunk))
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	var response classificationResponse
+	if err := e.generate(ctx, llmOperationClassify, classificationTmpl, map[string]any{
+		"Title":      title,
+		"Content":    chunk,
+		"Candidates": candidates,
+		"Part":       part,
+		"Parts":      parts,
+	}, getClassificationSchema(), &response); err != nil {
+		return nil, err
+	}
+
+	verdicts := make(map[string]int, len(response.Candidates))
+	for i, c := range response.Candidates {
+		verdicts[strings.ToLower(strings.TrimSpace(Refang(c.Value)))] = i
+	}
+
+	var malicious []*ExtractedIoC
+	for _, candidate := range candidates {
+		i, ok := verdicts[strings.ToLower(candidate.Value)]
+		if !ok || !response.Candidates[i].Malicious {
+			continue
+		}
+		malicious = append(malicious, &ExtractedIoC{
+			Type:        candidate.Type,
+			Value:       candidate.Value,
+			Description: response.Candidates[i].Description,
+		})
+	}
+
+	return malicious, nil
+}
+
+// Operations of LLM calls recorded in metrics
+const (
+	llmOperationExtract  = "extract"
+	llmOperationClassify = "classify"
+	llmOperationReport   = "report"
+)
+
+// generate renders the prompt template and decodes the structured LLM response into out
+func (e *Extractor) generate(ctx context.Context, operation string, tmpl *template.Template, data map[string]any, schema *gollem.Parameter, out any) error {
 	if e.llmClient == nil {
-		return nil, goerr.New("LLM client not configured")
+		return goerr.New("LLM client not configured")
 	}
 
 	// Render prompt template
 	var promptBuf bytes.Buffer
-	if err := extractionTmpl.Execute(&promptBuf, map[string]string{
-		"Title":   title,
-		"Content": content,
-	}); err != nil {
-		return nil, goerr.Wrap(err, "failed to render prompt template")
+	if err := tmpl.Execute(&promptBuf, data); err != nil {
+		return goerr.Wrap(err, "failed to render prompt template")
 	}
 	prompt := promptBuf.String()
 
 	// Create a session with response schema and JSON content type
 	session, err := e.llmClient.NewSession(ctx,
 		gollem.WithSessionContentType(gollem.ContentTypeJSON),
-		gollem.WithSessionResponseSchema(getIoCSchema()),
+		gollem.WithSessionResponseSchema(schema),
 	)
 	if err != nil {
-		return nil, goerr.Wrap(err, "failed to create LLM session")
+		return goerr.Wrap(err, "failed to create LLM session")
 	}
 
 	// Generate content using LLM
-	resp, err := session.GenerateContent(ctx, gollem.Text(prompt))
+	start := time.Now()
+	spanCtx, span := tracing.Start(ctx, "llm.generate", attribute.String("llm.operation", operation))
+	resp, err := session.GenerateContent(spanCtx, gollem.Text(prompt))
+	var inputTokens, outputTokens int
+	if resp != nil {
+		inputTokens, outputTokens = resp.InputToken, resp.OutputToken
+	}
+	span.SetAttributes(
+		attribute.Int("llm.input_tokens", inputTokens),
+		attribute.Int("llm.output_tokens", outputTokens))
+	tracing.End(span, err)
+	if e.metrics != nil {
+		e.metrics.ObserveLLMCall(operation, time.Since(start), inputTokens, outputTokens, err)
+	}
+	if errors.Is(err, interfaces.ErrLLMResponseTruncated) {
+		return goerr.Wrap(errResponseTruncated, "LLM response hit the output token limit",
+			goerr.V("output_tokens", outputTokens))
+	}
 	if err != nil {
-		return nil, goerr.Wrap(errExtractionFailed, "LLM generation failed",
+		return goerr.Wrap(errExtractionFailed, "LLM generation failed",
 			goerr.V("error", err.Error()))
 	}
 
@@ -145,48 +354,82 @@ func (e *Extractor) ExtractFromArticle(ctx context.Context, title, content strin
 	}
 
 	if responseText == "" {
-		return nil, goerr.Wrap(errExtractionFailed, "empty LLM response")
+		return goerr.Wrap(errExtractionFailed, "empty LLM response")
 	}
 
 	// Parse JSON response with schema
-	var response extractionResponse
-	if err := json.Unmarshal([]byte(responseText), &response); err != nil {
-		return nil, goerr.Wrap(errExtractionFailed, "failed to parse LLM response",
+	if err := json.Unmarshal([]byte(responseText), out); err != nil {
+		// A response ending in the middle of the JSON document hit the output limit
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) && syntaxErr.Offset >= int64(len(responseText)) {
+			return goerr.Wrap(errResponseTruncated, "failed to parse LLM response",
+				goerr.V("response_length", len(responseText)))
+		}
+		return goerr.Wrap(errExtractionFailed, "failed to parse LLM response",
 			goerr.V("response", responseText),
 			goerr.V("error", err.Error()))
 	}
 
-	return response.IoCs, nil
+	return nil
+}
+
+// CrossCheck splits LLM-extracted IoCs into those whose value occurs in the
+// (refanged) content and those that do not. A value is found if it occurs
+// literally, ignoring case, or if it equals a deterministic candidate after
+// normalization, which covers values the LLM reformatted such as compressed
+// IPv6 addresses.
+func CrossCheck(iocs []*ExtractedIoC, content string) (verified, unverified []*ExtractedIoC) {
+	text := strings.ToLower(Refang(content))
+
+	candidates := make(map[string]bool)
+	for _, c := range ExtractDeterministic(content) {
+		candidates[c.Value] = true
+	}
+
+	for _, ioc := range iocs {
+		value := strings.TrimSpace(Refang(ioc.Value))
+		normalized := value
+		if iocType := mapStringToIoCType(ioc.Type); iocType != "" {
+			normalized = model.NormalizeValue(iocType, value)
+		}
+
+		if value != "" && (strings.Contains(text, strings.ToLower(value)) || candidates[normalized]) {
+			verified = append(verified, ioc)
+		} else {
+			unverified = append(unverified, ioc)
+		}
+	}
+
+	return verified, unverified
 }
 
 // GenerateEmbedding generates a vector embedding for the given text
 // text can be an IoC value OR a search query
 func (e *Extractor) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	if e.embedder != nil {
+		vectors, err := e.embedder.Embed(ctx, []string{text})
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to generate embedding")
+		}
+		return vectors[0], nil
+	}
+
 	// Use n-gram vectorizer if enabled
 	if e.useNGram && e.vectorizer != nil {
 		return e.vectorizer.Vectorize(text)
 	}
 
-	// Fallback to LLM embedding
-	if e.llmClient == nil {
-		return nil, goerr.New("LLM client not configured")
-	}
-
-	// Use gollem's embedding generation if available
-	// For now, return a placeholder implementation
-	// This should be implemented when gollem's embedding API is available
-
-	// Create a dummy embedding with correct dimensions
-	embedding := make([]float32, model.EmbeddingDimension)
-
-	// TODO: Replace with actual LLM embedding generation
-	// resp, err := e.llmClient.GenerateEmbedding(ctx, text)
-	// if err != nil {
-	//     return nil, goerr.Wrap(errEmbeddingFailed, "embedding generation failed", err)
-	// }
-	// return resp.Embedding, nil
+	return nil, goerr.New("embedder not configured")
+}
 
-	return embedding, nil
+// normalizeExtracted normalizes an extracted value. The LLM may return network
+// indicators as defanged in the article.
+func normalizeExtracted(iocType model.IoCType, value string) string {
+	switch iocType {
+	case model.IoCTypeIPv4, model.IoCTypeIPv6, model.IoCTypeDomain, model.IoCTypeURL, model.IoCTypeEmail:
+		value = Refang(value)
+	}
+	return model.NormalizeValue(iocType, value)
 }
 
 // ConvertToIoC converts an ExtractedIoC to a domain IoC model.
@@ -202,8 +445,10 @@ func ConvertToIoC(sourceID, sourceType, sourceURL string, extracted *ExtractedIo
 			goerr.V("value", extracted.Value))
 	}
 
-	// Normalize the value
-	normalizedValue := model.NormalizeValue(iocType, extracted.Value)
+	normalizedValue := normalizeExtracted(iocType, extracted.Value)
+	if err := model.ValidateValue(iocType, normalizedValue); err != nil {
+		return nil, goerr.Wrap(err, "invalid IoC value from extraction")
+	}
 
 	// Generate context key based on source type and parameters
 	contextKey := model.GenerateContextKey(sourceType, contextParams)
@@ -220,39 +465,41 @@ func ConvertToIoC(sourceID, sourceType, sourceURL string, extracted *ExtractedIo
 		Description: extracted.Description,
 		SourceURL:   sourceURL,
 		Status:      model.IoCStatusActive,
-		Embedding:   make([]float32, model.EmbeddingDimension), // Will be filled later
 	}
 
 	return ioc, nil
 }
 
+// iocTypeAliases maps spellings LLMs commonly use instead of the schema's type names
+var iocTypeAliases = map[string]model.IoCType{
+	"ip":           model.IoCTypeIPv4,
+	"hostname":     model.IoCTypeDomain,
+	"mac":          model.IoCTypeMacAddr,
+	"mac_addr":     model.IoCTypeMacAddr,
+	"mac_address":  model.IoCTypeMacAddr,
+	"as":           model.IoCTypeASN,
+	"sha-1":        model.IoCTypeSHA1,
+	"sha-256":      model.IoCTypeSHA256,
+	"file":         model.IoCTypeFilename,
+	"file_name":    model.IoCTypeFilename,
+	"command_line": model.IoCTypeProcess,
+	"registry_key": model.IoCTypeRegKey,
+	"regkey":       model.IoCTypeRegKey,
+	"user_agent":   model.IoCTypeUserAgent,
+	"useragent":    model.IoCTypeUserAgent,
+	"cert_hash":    model.IoCTypeCertHash,
+}
+
 // mapStringToIoCType maps a string type to IoCType
 // Returns an empty IoCType if the type string is unknown
 func mapStringToIoCType(typeStr string) model.IoCType {
 	typeStr = strings.ToLower(strings.TrimSpace(typeStr))
 
-	switch typeStr {
-	case "ipv4":
-		return model.IoCTypeIPv4
-	case "ipv6":
-		return model.IoCTypeIPv6
-	case "domain":
-		return model.IoCTypeDomain
-	case "url":
-		return model.IoCTypeURL
-	case "email":
-		return model.IoCTypeEmail
-	case "md5":
-		return model.IoCTypeMD5
-	case "sha1":
-		return model.IoCTypeSHA1
-	case "sha256":
-		return model.IoCTypeSHA256
-	default:
-		// Unknown type - return empty string
-		// This should not happen if LLM follows the schema, but handle gracefully
-		return ""
+	if t := model.IoCType(typeStr); t.IsValid() {
+		return t
 	}
+	// Unknown type - this should not happen if LLM follows the schema, but handle gracefully
+	return iocTypeAliases[typeStr]
 }
 
 // ExtractIoCsFromFeedEntry converts a feed entry to IoC models
@@ -0,0 +1,166 @@
+package extractor_test
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+//go:embed testdata/host_artifacts.md
+var hostArtifactsArticle string
+
+func TestConvertToIoC_AllTypes(t *testing.T) {
+	testCases := []struct {
+		iocType  string
+		value    string
+		expected string
+	}{
+		{"ipv4", "198.51.100[.]42", "198.51.100.42"},
+		{"ipv6", "2001:0DB8::0001", "2001:db8::1"},
+		{"domain", "Telemetry-Sync[.]Top.", "telemetry-sync.top"},
+		{"url", "hxxps://Telemetry-Sync[.]top/api/v2", "https://telemetry-sync.top/api/v2"},
+		{"email", "Support[@]Secure-Banking-Verify[.]net", "support@secure-banking-verify.net"},
+		{"mac-addr", "00-50-56-C0-00-08", "005056c00008"},
+		{"asn", "AS209588", "209588"},
+		{"asn", "ASN 1.10", "65546"},
+		{"md5", "5D41402ABC4B2A76B9719D911017C592", "5d41402abc4b2a76b9719d911017c592"},
+		{"sha1", "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d", "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d"},
+		{"sha256", "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"filename", " invoice_2024.pdf.exe ", "invoice_2024.pdf.exe"},
+		{"filename", `C:\ProgramData\Microsoft\svchelper.dll`, `C:\ProgramData\Microsoft\svchelper.dll`},
+		{"cert-hash", "3A:7F:0C:9B:2E:41:D8:66:05:C1:9E:AB:73:20:F4:58:BD:0E:11:C7", "3a7f0c9b2e41d86605c19eab7320f458bd0e11c7"},
+		{"process", `rundll32.exe C:\ProgramData\Microsoft\svchelper.dll,Start  -k netsvc`, `rundll32.exe C:\ProgramData\Microsoft\svchelper.dll,Start  -k netsvc`},
+		{"mutex", `Global\SvcHelperMtx_8841`, `Global\SvcHelperMtx_8841`},
+		{"registry-key", `hkcu\Software\Microsoft\Windows\CurrentVersion\Run\SvcHelper\`, `HKEY_CURRENT_USER\Software\Microsoft\Windows\CurrentVersion\Run\SvcHelper`},
+		{"registry-key", `HKLM:/SOFTWARE/Classes`, `HKEY_LOCAL_MACHINE\SOFTWARE\Classes`},
+		{"user-agent", "Mozilla/5.0  (Windows NT 10.0; Win64; x64)   SvcHelper/2.1", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) SvcHelper/2.1"},
+		{"user-agent", "Microsoft Internet Explorer", "Microsoft Internet Explorer"},
+		// Spellings outside the schema are mapped to the schema's type names
+		{"Registry_Key", `HKCU\Software\Evil`, `HKEY_CURRENT_USER\Software\Evil`},
+		{"useragent", "curl/8.4.0", "curl/8.4.0"},
+	}
+
+	covered := make(map[model.IoCType]bool)
+	for _, tc := range testCases {
+		t.Run(tc.iocType+" "+tc.value, func(t *testing.T) {
+			ioc, err := extractor.ConvertToIoC("blog", "rss", "https://blog.example.com/post",
+				&extractor.ExtractedIoC{Type: tc.iocType, Value: tc.value}, nil)
+			gt.NoError(t, err)
+			gt.Equal(t, ioc.Value, tc.expected)
+			covered[ioc.Type] = true
+		})
+	}
+
+	for _, iocType := range model.AllIoCTypes {
+		gt.True(t, covered[iocType]).Describef("type %s has no test case", iocType)
+	}
+}
+
+func TestConvertToIoC_InvalidValues(t *testing.T) {
+	testCases := []struct {
+		iocType string
+		value   string
+	}{
+		{"ipv4", "198.51.100.420"},
+		{"ipv4", "2001:db8::1"},
+		{"ipv6", "198.51.100.42"},
+		{"domain", "not a domain"},
+		{"url", "/relative/path"},
+		{"email", "user@"},
+		{"mac-addr", "00:50:56:C0:00"},
+		{"asn", "AS0"},
+		{"asn", "AS99999999999"},
+		{"md5", "5d41402abc4b2a76b9719d911017c59"},
+		{"sha1", "xyz4c61ddcc5e8a2dabede0f3b482cd9aea9434d"},
+		{"sha256", "e3b0c44298fc1c149afbf4c8996fb924"},
+		{"cert-hash", "3A:7F:0C"},
+		{"filename", "C:\\ProgramData\\"},
+		{"process", "evil.exe\x00"},
+		{"mutex", "mutex\nname"},
+		{"registry-key", `Software\Microsoft\Windows\CurrentVersion\Run`},
+		{"registry-key", `HKEY_FAKE\Software`},
+		{"user-agent", "Mozilla"},
+		{"user-agent", "https://evil.example/ua"},
+		{"user-agent", "12345 67890"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.iocType+" "+tc.value, func(t *testing.T) {
+			_, err := extractor.ConvertToIoC("blog", "rss", "https://blog.example.com/post",
+				&extractor.ExtractedIoC{Type: tc.iocType, Value: tc.value}, nil)
+			gt.Error(t, err)
+			gt.True(t, errors.Is(err, model.ErrInvalidIoCValue))
+		})
+	}
+
+	_, err := extractor.ConvertToIoC("blog", "rss", "", &extractor.ExtractedIoC{Type: "cve", Value: "CVE-2025-1234"}, nil)
+	gt.True(t, errors.Is(err, model.ErrInvalidIoCType))
+}
+
+func TestExtractDeterministic_HostArtifacts(t *testing.T) {
+	got := make(map[string]string)
+	for _, ioc := range extractor.ExtractDeterministic(hostArtifactsArticle) {
+		got[ioc.Value] = ioc.Type
+	}
+
+	gt.Equal(t, got, map[string]string{
+		"https://telemetry-sync.top/api/v2":                                                "url",
+		`HKEY_CURRENT_USER\Software\Microsoft\Windows\CurrentVersion\Run\SvcHelper`:        "registry-key",
+		`HKEY_LOCAL_MACHINE\SOFTWARE\Classes\CLSID\{6B4F2C10-7A55-4C3E-9D1A-0C6F3B7E2D91}`: "registry-key",
+		`HKEY_LOCAL_MACHINE\SOFTWARE\Policies\Microsoft\Windows`:                           "registry-key",
+		"3a7f0c9b2e41d86605c19eab7320f458bd0e11c7":                                         "cert-hash",
+		"005056c00008": "mac-addr",
+		"209588":       "asn",
+	})
+}
+
+func TestExtract_HostArtifactsWithLLM(t *testing.T) {
+	ctx := context.Background()
+	var prompts []string
+	llm := newMockLLM(`{"iocs": [
+		{"type": "filename", "value": "invoice_2024.pdf.exe", "description": "Trojanized installer"},
+		{"type": "filename", "value": "C:\\ProgramData\\Microsoft\\svchelper.dll", "description": "Dropped loader"},
+		{"type": "process", "value": "rundll32.exe C:\\ProgramData\\Microsoft\\svchelper.dll,Start -k netsvc", "description": "Loader execution"},
+		{"type": "mutex", "value": "Global\\SvcHelperMtx_8841", "description": "Infection marker"},
+		{"type": "registry-key", "value": "HKCU\\Software\\Microsoft\\Windows\\CurrentVersion\\Run\\SvcHelper", "description": "Run key persistence"},
+		{"type": "user-agent", "value": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) SvcHelper/2.1", "description": "C2 User-Agent"},
+		{"type": "url", "value": "https://telemetry-sync[.]top/api/v2", "description": "C2 endpoint"},
+		{"type": "asn", "value": "AS209588", "description": "C2 hosting network"},
+		{"type": "cert-hash", "value": "3A:7F:0C:9B:2E:41:D8:66:05:C1:9E:AB:73:20:F4:58:BD:0E:11:C7", "description": "C2 certificate"},
+		{"type": "mac-addr", "value": "00:50:56:C0:00:08", "description": "Infected host"}
+	]}`, &prompts)
+
+	result, err := extractor.New(llm).Extract(ctx, model.ExtractionModeCrossCheck, "Loader", hostArtifactsArticle)
+	gt.NoError(t, err)
+	gt.A(t, result.Unverified).Length(0)
+	gt.A(t, result.IoCs).Length(10)
+
+	types := make(map[model.IoCType]int)
+	for _, ext := range result.IoCs {
+		ioc, err := extractor.ConvertToIoC("blog", "rss", "https://blog.example.com/post", ext, nil)
+		gt.NoError(t, err)
+		types[ioc.Type]++
+	}
+	gt.Equal(t, types, map[model.IoCType]int{
+		model.IoCTypeFilename:  2,
+		model.IoCTypeProcess:   1,
+		model.IoCTypeMutex:     1,
+		model.IoCTypeRegKey:    1,
+		model.IoCTypeUserAgent: 1,
+		model.IoCTypeURL:       1,
+		model.IoCTypeASN:       1,
+		model.IoCTypeCertHash:  1,
+		model.IoCTypeMacAddr:   1,
+	})
+
+	// The prompt asks for every type
+	gt.A(t, prompts).Length(1)
+	for _, iocType := range model.AllIoCTypes {
+		gt.S(t, prompts[0]).Contains("`" + string(iocType) + "`")
+	}
+}
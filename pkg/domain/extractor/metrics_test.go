@@ -0,0 +1,73 @@
+package extractor_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gollem"
+	"github.com/m-mizutani/gollem/mock"
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+type llmCall struct {
+	operation     string
+	input, output int
+	failed        bool
+}
+
+// llmMetrics records LLM calls and ignores other metrics
+type llmMetrics struct {
+	mu    sync.Mutex
+	calls []llmCall
+}
+
+func (m *llmMetrics) ObserveFetch(*model.History) {}
+
+func (m *llmMetrics) ObserveLLMCall(operation string, duration time.Duration, inputTokens, outputTokens int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, llmCall{operation: operation, input: inputTokens, output: outputTokens, failed: err != nil})
+}
+
+func (m *llmMetrics) ObserveGraphQLField(string, time.Duration, error) {}
+
+func (m *llmMetrics) ObserveRepository(string, time.Duration, error) {}
+
+func TestExtractor_Metrics(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("token usage of each call", func(t *testing.T) {
+		llm := &mock.LLMClientMock{
+			NewSessionFunc: func(ctx context.Context, options ...gollem.SessionOption) (gollem.Session, error) {
+				return &mock.SessionMock{
+					GenerateContentFunc: func(ctx context.Context, input ...gollem.Input) (*gollem.Response, error) {
+						return &gollem.Response{Texts: []string{`{"iocs": []}`}, InputToken: 120, OutputToken: 8}, nil
+					},
+				}, nil
+			},
+		}
+		metrics := &llmMetrics{}
+		_, err := extractor.New(llm, extractor.WithMetrics(metrics)).ExtractFromArticle(ctx, "title", "short article")
+		gt.NoError(t, err)
+		gt.Equal(t, metrics.calls, []llmCall{{operation: "extract", input: 120, output: 8}})
+	})
+
+	t.Run("failed calls", func(t *testing.T) {
+		llm := newPromptLLM(func(prompt string) (string, error) {
+			return "", errors.New("rate limited")
+		})
+		metrics := &llmMetrics{}
+		_, err := extractor.New(llm, extractor.WithMetrics(metrics)).
+			Extract(ctx, model.ExtractionModeLLM, "title", "Report about 198.51.100.1")
+		gt.Error(t, err)
+		gt.N(t, len(metrics.calls)).Greater(0)
+		for _, call := range metrics.calls {
+			gt.True(t, call.failed)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package extractor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"text/template"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// Prompt is a validated per-source customization of the extraction prompt.
+// It is applied with Extractor.WithPrompt.
+type Prompt struct {
+	cfg     model.ExtractionPrompt
+	tmpl    *template.Template
+	version string
+}
+
+// promptExample is a few-shot example as rendered into the prompt template
+type promptExample struct {
+	Input  string
+	Output string
+}
+
+// NewPrompt validates cfg and parses its template override. The template is
+// rendered once with sample data, so that errors such as unknown fields are
+// reported at config load rather than when the first article is extracted.
+// A nil cfg returns a nil Prompt, which is the built-in prompt.
+func NewPrompt(cfg *model.ExtractionPrompt) (*Prompt, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	for _, t := range cfg.Types {
+		if !t.IsValid() {
+			return nil, goerr.Wrap(model.ErrInvalidIoCType, "unknown IoC type in types", goerr.V("type", t))
+		}
+	}
+
+	p := &Prompt{cfg: *cfg, tmpl: extractionTmpl}
+	for i, example := range cfg.Examples {
+		if example.Input == "" {
+			return nil, goerr.New("input of example is empty", goerr.V("example", i+1))
+		}
+		for _, ioc := range example.IoCs {
+			if !ioc.Type.IsValid() {
+				return nil, goerr.Wrap(model.ErrInvalidIoCType, "unknown IoC type in example",
+					goerr.V("example", i+1), goerr.V("type", ioc.Type))
+			}
+			if !cfg.AllowsType(ioc.Type) {
+				return nil, goerr.New("IoC type of example is not in types",
+					goerr.V("example", i+1), goerr.V("type", ioc.Type))
+			}
+		}
+	}
+
+	if cfg.Template != "" {
+		tmpl, err := template.New("extraction").Option("missingkey=error").Parse(cfg.Template)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to parse prompt template")
+		}
+		p.tmpl = tmpl
+	}
+
+	if err := p.tmpl.Execute(io.Discard, p.data("Sample title", "Sample content", 1, 1)); err != nil {
+		return nil, goerr.Wrap(err, "failed to render prompt template")
+	}
+
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to marshal prompt config")
+	}
+	sum := sha256.Sum256(raw)
+	p.version = hex.EncodeToString(sum[:6])
+
+	return p, nil
+}
+
+// Version returns a short hash of the customization
+func (p *Prompt) Version() string {
+	if p == nil {
+		return ""
+	}
+	return p.version
+}
+
+// template returns the extraction prompt template
+func (p *Prompt) template() *template.Template {
+	if p == nil {
+		return extractionTmpl
+	}
+	return p.tmpl
+}
+
+// types returns the allowed IoC type names, or all type names if not restricted
+func (p *Prompt) types() []string {
+	if p == nil || len(p.cfg.Types) == 0 {
+		return iocTypeNames()
+	}
+	names := make([]string, len(p.cfg.Types))
+	for i, t := range p.cfg.Types {
+		names[i] = string(t)
+	}
+	return names
+}
+
+// allows returns true if an extracted IoC is of an allowed type. IoCs of
+// unknown types are left for ConvertToIoC to reject.
+func (p *Prompt) allows(ioc *ExtractedIoC) bool {
+	if p == nil {
+		return true
+	}
+	iocType := mapStringToIoCType(ioc.Type)
+	return iocType == "" || p.cfg.AllowsType(iocType)
+}
+
+// filter returns the IoCs of allowed types
+func (p *Prompt) filter(iocs []*ExtractedIoC) []*ExtractedIoC {
+	if p == nil || len(p.cfg.Types) == 0 {
+		return iocs
+	}
+	var allowed []*ExtractedIoC
+	for _, ioc := range iocs {
+		if p.allows(ioc) {
+			allowed = append(allowed, ioc)
+		}
+	}
+	return allowed
+}
+
+// data returns the data to render the extraction prompt template of a chunk
+func (p *Prompt) data(title, content string, part, parts int) map[string]any {
+	data := map[string]any{
+		"Title":        title,
+		"Content":      content,
+		"Part":         part,
+		"Parts":        parts,
+		"Instructions": "",
+		"Examples":     []promptExample(nil),
+		"Types":        []string(nil),
+		"Language":     "",
+	}
+	if p == nil {
+		return data
+	}
+
+	examples := make([]promptExample, 0, len(p.cfg.Examples))
+	for _, example := range p.cfg.Examples {
+		iocs := example.IoCs
+		if iocs == nil {
+			iocs = []model.ExampleIoC{}
+		}
+		// Marshaling a slice of plain structs does not fail
+		output, _ := json.Marshal(map[string]any{"iocs": iocs})
+		examples = append(examples, promptExample{Input: example.Input, Output: string(output)})
+	}
+
+	data["Instructions"] = p.cfg.Instructions
+	data["Examples"] = examples
+	if len(p.cfg.Types) > 0 {
+		data["Types"] = p.types()
+	}
+	data["Language"] = p.cfg.Language
+	return data
+}
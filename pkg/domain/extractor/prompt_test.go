@@ -0,0 +1,129 @@
+package extractor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func TestNewPrompt(t *testing.T) {
+	p, err := extractor.NewPrompt(nil)
+	gt.NoError(t, err)
+	gt.Equal(t, p.Version(), "")
+
+	valid := &model.ExtractionPrompt{
+		Instructions: "IoCs are listed in the table at the end of the article.",
+		Examples: []model.PromptExample{{
+			Input: "| sha256 | e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855 |",
+			IoCs: []model.ExampleIoC{{
+				Type:        model.IoCTypeSHA256,
+				Value:       "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+				Description: "Loader",
+			}},
+		}},
+		Types:    []model.IoCType{model.IoCTypeSHA256, model.IoCTypeDomain},
+		Language: "Japanese",
+	}
+	p, err = extractor.NewPrompt(valid)
+	gt.NoError(t, err)
+	gt.S(t, p.Version()).NotEqual("")
+
+	other, err := extractor.NewPrompt(&model.ExtractionPrompt{Language: "German"})
+	gt.NoError(t, err)
+	gt.S(t, other.Version()).NotEqual(p.Version())
+
+	t.Run("custom template", func(t *testing.T) {
+		_, err := extractor.NewPrompt(&model.ExtractionPrompt{
+			Template: "Extract IoCs of {{.Title}} in {{.Language}}:\n{{.Content}}",
+		})
+		gt.NoError(t, err)
+	})
+
+	for name, cfg := range map[string]*model.ExtractionPrompt{
+		"syntax error":            {Template: "{{.Content"},
+		"unknown field":           {Template: "{{.Body}}"},
+		"unknown function":        {Template: "{{lower .Content}}"},
+		"unknown type":            {Types: []model.IoCType{"hostname"}},
+		"empty example":           {Examples: []model.PromptExample{{}}},
+		"unknown example type":    {Examples: []model.PromptExample{{Input: "x", IoCs: []model.ExampleIoC{{Type: "ip", Value: "198.51.100.1"}}}}},
+		"example type not in set": {Types: []model.IoCType{model.IoCTypeMD5}, Examples: []model.PromptExample{{Input: "x", IoCs: []model.ExampleIoC{{Type: model.IoCTypeIPv4, Value: "198.51.100.1"}}}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := extractor.NewPrompt(cfg)
+			gt.Error(t, err)
+		})
+	}
+}
+
+func TestExtract_WithPrompt(t *testing.T) {
+	ctx := context.Background()
+
+	prompt, err := extractor.NewPrompt(&model.ExtractionPrompt{
+		Instructions: "Hashes are listed in the appendix.",
+		Examples: []model.PromptExample{{
+			Input: "C2: evil-example[.]net",
+			IoCs:  []model.ExampleIoC{{Type: model.IoCTypeDomain, Value: "evil-example.net", Description: "C2"}},
+		}},
+		Types:    []model.IoCType{model.IoCTypeDomain, model.IoCTypeSHA256},
+		Language: "Japanese",
+	})
+	gt.NoError(t, err)
+
+	t.Run("prompt is rendered and types are filtered", func(t *testing.T) {
+		var prompts []string
+		llm := newMockLLM(`{"iocs": [
+			{"type": "ipv4", "value": "198.51.100.42", "description": "C2 server"},
+			{"type": "domain", "value": "update-check[.]xyz", "description": "Second stage"}
+		]}`, &prompts)
+
+		result, err := extractor.New(llm).WithPrompt(prompt).Extract(ctx, model.ExtractionModeLLM, "title", defangedArticle)
+		gt.NoError(t, err)
+		gt.A(t, result.IoCs).Length(1).At(0, func(t testing.TB, ioc *extractor.ExtractedIoC) {
+			gt.Equal(t, ioc.Type, "domain")
+		})
+
+		gt.A(t, prompts).Length(1)
+		gt.S(t, prompts[0]).
+			Contains("The article is written in Japanese").
+			Contains("extract ONLY these types:** `domain`, `sha256`").
+			Contains("C2: evil-example[.]net").
+			Contains(`{"iocs":[{"type":"domain","value":"evil-example.net","description":"C2"}]}`).
+			Contains("Hashes are listed in the appendix.")
+	})
+
+	t.Run("built-in prompt has no source-specific sections", func(t *testing.T) {
+		var prompts []string
+		llm := newMockLLM(`{"iocs": []}`, &prompts)
+		_, err := extractor.New(llm).Extract(ctx, model.ExtractionModeLLM, "title", defangedArticle)
+		gt.NoError(t, err)
+		gt.S(t, prompts[0]).
+			NotContains("Source-specific").
+			NotContains("extract ONLY these types").
+			NotContains("<no value>")
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		custom, err := extractor.NewPrompt(&model.ExtractionPrompt{
+			Template: "Article {{.Title}} ({{.Part}}/{{.Parts}}):\n{{.Content}}",
+		})
+		gt.NoError(t, err)
+
+		var prompts []string
+		llm := newMockLLM(`{"iocs": []}`, &prompts)
+		_, err = extractor.New(llm).WithPrompt(custom).Extract(ctx, model.ExtractionModeLLM, "Report", "text")
+		gt.NoError(t, err)
+		gt.Equal(t, prompts, []string{"Article Report (1/1):\ntext"})
+	})
+
+	t.Run("regex mode filters types", func(t *testing.T) {
+		result, err := extractor.New(nil).WithPrompt(prompt).Extract(ctx, model.ExtractionModeRegex, "title", defangedArticle)
+		gt.NoError(t, err)
+		for _, ioc := range result.IoCs {
+			gt.True(t, ioc.Type == "domain" || ioc.Type == "sha256")
+		}
+		gt.A(t, result.IoCs).Longer(0)
+	})
+}
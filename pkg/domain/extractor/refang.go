@@ -0,0 +1,32 @@
+package extractor
+
+import "regexp"
+
+// refangRule rewrites one defanging convention back to its original form
+type refangRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// refangRules are applied in order. Bracketed forms may be surrounded by spaces
+// ("evil [.] com") which are removed together with the brackets.
+var refangRules = []refangRule{
+	{regexp.MustCompile(`(?i)\bhxxp(s?)\b`), "http$1"},
+	{regexp.MustCompile(`(?i)\bh\[tt\]p(s?)\b`), "http$1"},
+	{regexp.MustCompile(`(?i)\bfxp\b`), "ftp"},
+	{regexp.MustCompile(`\s?[\[\(\{]://[\]\)\}]`), "://"},
+	{regexp.MustCompile(`\s?[\[\(\{]:[\]\)\}]\s?`), ":"},
+	{regexp.MustCompile(`(?i)\s?[\[\(\{](?:\.|dot)[\]\)\}]\s?`), "."},
+	{regexp.MustCompile(`(?i)\s?[\[\(\{](?:@|at)[\]\)\}]\s?`), "@"},
+	{regexp.MustCompile(`\s?[\[\(\{]/[\]\)\}]\s?`), "/"},
+}
+
+// Refang restores defanged indicators in text, e.g. "hxxp://evil[.]com" becomes
+// "http://evil.com" and "1.2.3[.]4" becomes "1.2.3.4". Text without defanged
+// indicators is returned unchanged.
+func Refang(text string) string {
+	for _, rule := range refangRules {
+		text = rule.pattern.ReplaceAllString(text, rule.replacement)
+	}
+	return text
+}
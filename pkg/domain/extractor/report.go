@@ -0,0 +1,97 @@
+package extractor
+
+import (
+	"context"
+	_ "embed"
+	"regexp"
+	"text/template"
+
+	"github.com/m-mizutani/gollem"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+//go:embed prompts/analyze_report.md
+var reportPromptTemplate string
+
+var reportTmpl = template.Must(template.New("report").Parse(reportPromptTemplate))
+
+var (
+	cvePattern             = regexp.MustCompile(`(?i)\bCVE-[0-9]{4}-[0-9]{4,}\b`)
+	attackTechniquePattern = regexp.MustCompile(`\bT[0-9]{4}(?:\.[0-9]{3})?\b`)
+)
+
+// ReportAnalysis is the analysis of a threat report
+type ReportAnalysis struct {
+	Summary          string   `json:"summary"`
+	ThreatActors     []string `json:"threat_actors"`
+	MalwareFamilies  []string `json:"malware_families"`
+	Sectors          []string `json:"sectors"`
+	CVEs             []string `json:"cves"`
+	AttackTechniques []string `json:"attack_techniques"`
+}
+
+// getReportSchema returns the JSON schema for threat report analysis
+func getReportSchema() *gollem.Parameter {
+	stringList := func(description string) *gollem.Parameter {
+		return &gollem.Parameter{
+			Type:        gollem.TypeArray,
+			Description: description,
+			Items:       &gollem.Parameter{Type: gollem.TypeString},
+		}
+	}
+
+	return &gollem.Parameter{
+		Type:        gollem.TypeObject,
+		Description: "Analysis of the threat report",
+		Properties: map[string]*gollem.Parameter{
+			"summary": {
+				Type:        gollem.TypeString,
+				Description: "Concise summary of the article in 2-4 sentences",
+			},
+			"threat_actors":     stringList("Names of threat actors or groups"),
+			"malware_families":  stringList("Names of malware families and attack tools"),
+			"sectors":           stringList("Targeted industry sectors"),
+			"cves":              stringList("CVE IDs mentioned in the article, e.g. CVE-2024-3400"),
+			"attack_techniques": stringList("MITRE ATT&CK technique IDs, e.g. T1566.001"),
+		},
+		Required: []string{"summary", "threat_actors", "malware_families", "sectors", "cves", "attack_techniques"},
+	}
+}
+
+// AnalyzeReport summarizes an article and extracts threat actors, malware
+// families, targeted sectors, CVE IDs and ATT&CK technique IDs. CVE and technique
+// IDs written in the article are always included. In model.ExtractionModeRegex
+// the LLM client is not used and only these IDs are returned. Only the first
+// chunk of long articles (see WithChunking) is given to the LLM.
+func (e *Extractor) AnalyzeReport(ctx context.Context, mode model.ExtractionMode, title, content string) (*ReportAnalysis, error) {
+	text := Refang(content)
+	analysis := &ReportAnalysis{
+		CVEs:             cvePattern.FindAllString(text, -1),
+		AttackTechniques: attackTechniquePattern.FindAllString(text, -1),
+	}
+
+	if mode.UsesLLM() {
+		var response ReportAnalysis
+		if err := e.generate(ctx, llmOperationReport, reportTmpl, map[string]any{
+			"Title":   title,
+			"Content": splitChunks(content, e.chunkTokens, 0)[0],
+		}, getReportSchema(), &response); err != nil {
+			return nil, err
+		}
+
+		analysis.Summary = response.Summary
+		analysis.ThreatActors = response.ThreatActors
+		analysis.MalwareFamilies = response.MalwareFamilies
+		analysis.Sectors = response.Sectors
+		analysis.CVEs = append(response.CVEs, analysis.CVEs...)
+		analysis.AttackTechniques = append(response.AttackTechniques, analysis.AttackTechniques...)
+	}
+
+	analysis.ThreatActors = model.NormalizeNames(analysis.ThreatActors)
+	analysis.MalwareFamilies = model.NormalizeNames(analysis.MalwareFamilies)
+	analysis.Sectors = model.NormalizeNames(analysis.Sectors)
+	analysis.CVEs = model.NormalizeCVEs(analysis.CVEs)
+	analysis.AttackTechniques = model.NormalizeAttackTechniques(analysis.AttackTechniques)
+
+	return analysis, nil
+}
@@ -0,0 +1,58 @@
+package extractor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+const campaignReport = `# APT29 targets diplomats
+
+APT29 sent phishing mails (T1566.001) with a link to hxxps://docs-share[.]top/invite
+that exploited cve-2024-3400 and CVE-2023-23397. The payload loads Cobalt Strike.
+`
+
+func TestAnalyzeReport(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("LLM analysis is normalized and merged with IDs in the text", func(t *testing.T) {
+		var prompts []string
+		llm := newMockLLM(`{
+			"summary": "APT29 phished diplomats to deploy Cobalt Strike.",
+			"threat_actors": ["APT29", " apt29 ", "Midnight  Blizzard"],
+			"malware_families": ["Cobalt Strike", ""],
+			"sectors": ["Government", "Diplomacy"],
+			"cves": ["CVE-2024-3400", "CVE-2024-XXXX"],
+			"attack_techniques": ["t1566.001", "T1059", "Phishing"]
+		}`, &prompts)
+
+		analysis, err := extractor.New(llm).AnalyzeReport(ctx, model.ExtractionModeLLM, "APT29 targets diplomats", campaignReport)
+		gt.NoError(t, err)
+		gt.Equal(t, analysis.Summary, "APT29 phished diplomats to deploy Cobalt Strike.")
+		gt.Equal(t, analysis.ThreatActors, []string{"APT29", "Midnight Blizzard"})
+		gt.Equal(t, analysis.MalwareFamilies, []string{"Cobalt Strike"})
+		gt.Equal(t, analysis.Sectors, []string{"Government", "Diplomacy"})
+		gt.Equal(t, analysis.CVEs, []string{"CVE-2024-3400", "CVE-2023-23397"})
+		gt.Equal(t, analysis.AttackTechniques, []string{"T1566.001", "T1059"})
+
+		gt.A(t, prompts).Length(1)
+		gt.S(t, prompts[0]).Contains("APT29 targets diplomats")
+	})
+
+	t.Run("regex mode does not need an LLM", func(t *testing.T) {
+		analysis, err := extractor.New(nil).AnalyzeReport(ctx, model.ExtractionModeRegex, "title", campaignReport)
+		gt.NoError(t, err)
+		gt.Equal(t, analysis.Summary, "")
+		gt.A(t, analysis.ThreatActors).Length(0)
+		gt.Equal(t, analysis.CVEs, []string{"CVE-2024-3400", "CVE-2023-23397"})
+		gt.Equal(t, analysis.AttackTechniques, []string{"T1566.001"})
+	})
+
+	t.Run("LLM modes fail without a client", func(t *testing.T) {
+		_, err := extractor.New(nil).AnalyzeReport(ctx, model.ExtractionModeCrossCheck, "title", campaignReport)
+		gt.Error(t, err)
+	})
+}
@@ -0,0 +1,32 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+var (
+	// ErrAPITokenNotFound is returned when an API token is not found
+	ErrAPITokenNotFound = goerr.New("API token not found")
+)
+
+// APITokenRepository defines the interface for persistence of API tokens.
+// Only the hashes of the tokens are stored.
+type APITokenRepository interface {
+	// PutAPIToken creates or replaces an API token
+	PutAPIToken(ctx context.Context, token *model.APIToken) error
+	// GetAPIToken returns ErrAPITokenNotFound if the token does not exist
+	GetAPIToken(ctx context.Context, id string) (*model.APIToken, error)
+	// GetAPITokenByHash returns ErrAPITokenNotFound if no token has the hash
+	GetAPITokenByHash(ctx context.Context, hash string) (*model.APIToken, error)
+	// ListAPITokens returns all tokens, including revoked and expired ones,
+	// ordered by CreatedAt descending (newest first)
+	ListAPITokens(ctx context.Context) ([]*model.APIToken, error)
+	// TouchAPIToken sets only the last-used time of a token, so that it never
+	// overwrites a concurrent revocation. Returns ErrAPITokenNotFound if the
+	// token does not exist.
+	TouchAPIToken(ctx context.Context, id string, at time.Time) error
+}
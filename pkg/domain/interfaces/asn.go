@@ -0,0 +1,11 @@
+package interfaces
+
+import "context"
+
+// ASNResolver maps the host of an IoC to the autonomous systems announcing it
+type ASNResolver interface {
+	// LookupASNs returns the ASNs announcing host, an IP address or a domain
+	// name. Domain names are resolved only if the resolver is configured to;
+	// otherwise, and for unrouted addresses, no ASN is returned.
+	LookupASNs(ctx context.Context, host string) ([]uint32, error)
+}
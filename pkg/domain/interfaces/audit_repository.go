@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// AuditRepository defines the interface for persistence of the audit log.
+// Records are only appended; they are never updated or deleted.
+type AuditRepository interface {
+	// SaveAuditRecord appends a record. Saving a record with an existing ID fails.
+	SaveAuditRecord(ctx context.Context, record *model.AuditRecord) error
+	// ListAuditRecords returns the records matching filter ordered by
+	// CreatedAt descending (newest first)
+	ListAuditRecords(ctx context.Context, filter *model.AuditFilter) ([]*model.AuditRecord, error)
+}
+
+// AuditMirror receives a copy of every audit record, e.g. to keep the log
+// outside the repository
+type AuditMirror interface {
+	WriteAuditRecord(ctx context.Context, record *model.AuditRecord) error
+}
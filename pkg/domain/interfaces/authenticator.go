@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+var (
+	// ErrNoCredentials is returned when a request carries no credentials the
+	// authenticator handles, so that the next authenticator can be tried
+	ErrNoCredentials = goerr.New("no credentials")
+	// ErrInvalidCredentials is returned when the credentials of a request are
+	// rejected
+	ErrInvalidCredentials = goerr.New("invalid credentials")
+)
+
+// Authenticator identifies the caller of an HTTP request
+type Authenticator interface {
+	// Authenticate returns the principal of the request. It returns
+	// ErrNoCredentials if the request has no credentials of its kind and
+	// ErrInvalidCredentials if they are rejected.
+	Authenticate(r *http.Request) (*model.Principal, error)
+}
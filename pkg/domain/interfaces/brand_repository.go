@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// BrandMatchRepository defines the interface for lookalike domain match persistence
+type BrandMatchRepository interface {
+	// SaveBrandMatches stores matches whose ID does not exist yet. Existing matches
+	// are left unchanged. Returns the stored matches.
+	SaveBrandMatches(ctx context.Context, matches []*model.BrandMatch) ([]*model.BrandMatch, error)
+	// ListBrandMatches returns matches ordered by CreatedAt descending (newest first)
+	ListBrandMatches(ctx context.Context, opts *model.BrandMatchListOptions) (*model.BrandMatchConnection, error)
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import "context"
+
+// Embedder generates vector embeddings of IoC texts and search queries
+type Embedder interface {
+	// Embed returns the embedding of each text, in order. All embeddings have Dimension elements.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Model identifies the embedding model, e.g. "openai/text-embedding-3-small".
+	// It is recorded on IoCs to find embeddings of another model.
+	Model() string
+	// Dimension returns the dimension of the embeddings
+	Dimension() int
+}
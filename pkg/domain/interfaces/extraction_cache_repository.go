@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+var (
+	// ErrExtractionCacheNotFound is returned when no cache entry exists for a key
+	ErrExtractionCacheNotFound = goerr.New("extraction cache entry not found")
+)
+
+// ExtractionCacheRepository defines the interface for LLM extraction result caching
+type ExtractionCacheRepository interface {
+	// GetExtractionCache retrieves an entry by key, including expired entries.
+	// Returns ErrExtractionCacheNotFound if it does not exist.
+	GetExtractionCache(ctx context.Context, key string) (*model.ExtractionCacheEntry, error)
+	// PutExtractionCache creates or replaces an entry
+	PutExtractionCache(ctx context.Context, entry *model.ExtractionCacheEntry) error
+	// PurgeExtractionCache deletes the entries selected by opts and returns the number deleted
+	PurgeExtractionCache(ctx context.Context, opts *model.ExtractionCachePurgeOptions) (int, error)
+}
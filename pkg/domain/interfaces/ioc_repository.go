@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/m-mizutani/goerr/v2"
 	"github.com/secmon-lab/beehive/pkg/domain/model"
@@ -10,6 +11,8 @@ import (
 var (
 	// ErrIoCNotFound is returned when an IoC is not found
 	ErrIoCNotFound = goerr.New("IoC not found")
+	// ErrIoCExists is returned when creating an IoC whose ID is already stored
+	ErrIoCExists = goerr.New("IoC already exists")
 )
 
 // BatchUpsertResult represents the result of a batch upsert operation
@@ -17,6 +20,13 @@ type BatchUpsertResult struct {
 	Created   int // Number of new IoCs created
 	Updated   int // Number of existing IoCs updated
 	Unchanged int // Number of existing IoCs unchanged (skipped)
+
+	// CreatedIDs lists the IDs of the newly created IoCs
+	CreatedIDs []string
+
+	// StatusChanges lists status changes of existing IoCs applied by the upsert.
+	// Actor and Reason are left empty for the caller to fill in.
+	StatusChanges []*model.IoCStatusTransition
 }
 
 // IoCRepository defines the interface for IoC persistence
@@ -25,11 +35,37 @@ type IoCRepository interface {
 	ListIoCsBySource(ctx context.Context, sourceID string) ([]*model.IoC, error)
 	ListAllIoCs(ctx context.Context) ([]*model.IoC, error)
 	ListIoCs(ctx context.Context, opts *model.IoCListOptions) (*model.IoCConnection, error)
+	// ListIoCsByTag lists the IoCs matching a tag filter, each IoC once
+	ListIoCsByTag(ctx context.Context, filter *model.IoCTagFilter) ([]*model.IoC, error)
+	// UpsertIoC inserts or updates an IoC coming from a source.
+	// Analyst curation (tags, notes, override) of the stored IoC is preserved.
 	UpsertIoC(ctx context.Context, ioc *model.IoC) error
+	// CreateIoC stores a new IoC. Returns ErrIoCExists if an IoC with the same
+	// ID is stored, checked atomically with the write.
+	CreateIoC(ctx context.Context, ioc *model.IoC) error
+	// PutIoC stores the IoC as given, replacing any stored record including
+	// its curation. FirstSeenAt of an existing record is preserved.
+	// Used for analyst edits.
+	PutIoC(ctx context.Context, ioc *model.IoC) error
+	// DeleteIoC deletes an IoC. Returns ErrIoCNotFound if it does not exist.
+	DeleteIoC(ctx context.Context, id string) error
 	// BatchUpsertIoCs upserts multiple IoCs in a single batch operation
+	// Analyst curation of stored IoCs is preserved as in UpsertIoC.
 	// Returns the result with created/updated/unchanged counts and any error
 	BatchUpsertIoCs(ctx context.Context, iocs []*model.IoC) (*BatchUpsertResult, error)
+	// ListExpiredIoCs lists active IoCs whose ExpiresAt is set and not after now
+	ListExpiredIoCs(ctx context.Context, now time.Time) ([]*model.IoC, error)
+	// ListSourceIDsByValues returns the distinct source IDs reporting each of the
+	// given normalized values of a type. Values without IoCs are omitted.
+	ListSourceIDsByValues(ctx context.Context, iocType model.IoCType, values []string) (map[string][]string, error)
+	// UpdateIoCConfidences sets the confidence score of IoCs by ID without
+	// changing UpdatedAt. Unknown IDs are ignored.
+	UpdateIoCConfidences(ctx context.Context, scores map[string]int) error
+	// UpdateIoCEmbeddings sets the embedding, embedding model and dimension of
+	// IoCs by ID without changing UpdatedAt. Unknown IDs are ignored.
+	UpdateIoCEmbeddings(ctx context.Context, embeddings map[string]*model.IoCEmbedding) error
 	// FindNearestIoCs performs vector similarity search
-	// Returns IoCs ordered by similarity to the query vector (most similar first)
+	// Returns IoCs ordered by similarity to the query vector (most similar first).
+	// The query vector must have the embedding dimension of the repository.
 	FindNearestIoCs(ctx context.Context, queryVector []float32, limit int) ([]*model.IoC, error)
 }
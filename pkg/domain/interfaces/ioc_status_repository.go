@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// IoCStatusRepository defines the interface for IoC status transition persistence
+type IoCStatusRepository interface {
+	// SaveStatusTransitions appends status transition records
+	SaveStatusTransitions(ctx context.Context, transitions []*model.IoCStatusTransition) error
+
+	// ListStatusTransitions retrieves transitions of an IoC ordered by CreatedAt ascending
+	ListStatusTransitions(ctx context.Context, iocID string) ([]*model.IoCStatusTransition, error)
+}
@@ -0,0 +1,8 @@
+package interfaces
+
+import "github.com/m-mizutani/goerr/v2"
+
+// ErrLLMResponseTruncated is returned by LLM clients when the response was cut
+// off at the output token limit. The truncated response is returned along with
+// the error.
+var ErrLLMResponseTruncated = goerr.New("LLM response truncated at the output token limit")
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"time"
+
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// Metrics records operational metrics of fetches, LLM calls, the GraphQL API
+// and the repository. Callers only pass label values from fixed sets (source
+// IDs, schema field names and operation names), never user input.
+type Metrics interface {
+	// ObserveFetch records a completed fetch of a source
+	ObserveFetch(history *model.History)
+	// ObserveLLMCall records an LLM generation of an extraction step such as
+	// "extract", "classify" or "report"
+	ObserveLLMCall(operation string, duration time.Duration, inputTokens, outputTokens int, err error)
+	// ObserveGraphQLField records the resolution of a root field such as "Query.listIoCs"
+	ObserveGraphQLField(field string, duration time.Duration, err error)
+	// ObserveRepository records a repository method call such as "BatchUpsertIoCs"
+	ObserveRepository(operation string, duration time.Duration, err error)
+}
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// NotificationChannel delivers notifications to an external destination
+type NotificationChannel interface {
+	Send(ctx context.Context, n *model.Notification) error
+}
+
+// NotificationRepository records sent notifications for deduplication
+type NotificationRepository interface {
+	// ClaimNotification records the key as sent. Returns false if the key was
+	// already recorded, in which case the notification must not be sent again.
+	ClaimNotification(ctx context.Context, key string, at time.Time) (bool, error)
+	// ReleaseNotification removes a claim so that the notification can be retried
+	ReleaseNotification(ctx context.Context, key string) error
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+var (
+	// ErrReportNotFound is returned when a report is not found
+	ErrReportNotFound = goerr.New("report not found")
+)
+
+// ReportRepository defines the interface for threat report persistence
+type ReportRepository interface {
+	// PutReport creates or replaces a report. CreatedAt of an existing report is preserved.
+	PutReport(ctx context.Context, report *model.Report) error
+	// GetReport retrieves a report by ID. Returns ErrReportNotFound if it does not exist.
+	GetReport(ctx context.Context, id string) (*model.Report, error)
+	// ListReports returns reports ordered by PublishedAt descending (newest first)
+	ListReports(ctx context.Context, opts *model.ReportListOptions) (*model.ReportConnection, error)
+	// ListIoCsByReport returns the IoCs extracted from a report
+	ListIoCsByReport(ctx context.Context, reportID string) ([]*model.IoC, error)
+}
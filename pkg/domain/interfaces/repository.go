@@ -1,8 +1,22 @@
 package interfaces
 
+import "context"
+
 // Repository defines the interface for data persistence
 type Repository interface {
 	IoCRepository
+	IoCStatusRepository
 	SourceStateRepository
 	HistoryRepository
+	NotificationRepository
+	WatchlistRepository
+	BrandMatchRepository
+	ReportRepository
+	ExtractionCacheRepository
+	SourceRepository
+	APITokenRepository
+	AuditRepository
+
+	// Ping checks that the storage is reachable with a cheap read
+	Ping(ctx context.Context) error
 }
@@ -0,0 +1,33 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+var (
+	// ErrSourceNotFound is returned when a stored source is not found
+	ErrSourceNotFound = goerr.New("source not found")
+)
+
+// SourceRepository defines the interface for persistence of source definitions
+// and their change records
+type SourceRepository interface {
+	// PutSource creates or replaces a source
+	PutSource(ctx context.Context, source *model.Source) error
+	// GetSource returns ErrSourceNotFound if the source does not exist
+	GetSource(ctx context.Context, id string) (*model.Source, error)
+	// ListSources returns all sources ordered by ID
+	ListSources(ctx context.Context) ([]*model.Source, error)
+	// DeleteSource returns ErrSourceNotFound if the source does not exist.
+	// The state, histories and change records of the source are kept.
+	DeleteSource(ctx context.Context, id string) error
+
+	// SaveSourceChange appends a change record
+	SaveSourceChange(ctx context.Context, change *model.SourceChange) error
+	// ListSourceChanges returns change records ordered by CreatedAt descending
+	// (newest first), of all sources if sourceID is empty. limit <= 0 means no limit.
+	ListSourceChanges(ctx context.Context, sourceID string, limit int) ([]*model.SourceChange, error)
+}
@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+var (
+	// ErrWatchlistNotFound is returned when a watchlist is not found
+	ErrWatchlistNotFound = goerr.New("watchlist not found")
+	// ErrWatchlistHitNotFound is returned when a watchlist hit is not found
+	ErrWatchlistHitNotFound = goerr.New("watchlist hit not found")
+)
+
+// WatchlistRepository defines the interface for watchlist and hit persistence
+type WatchlistRepository interface {
+	// PutWatchlist creates or replaces a watchlist
+	PutWatchlist(ctx context.Context, watchlist *model.Watchlist) error
+	// GetWatchlist returns ErrWatchlistNotFound if the watchlist does not exist
+	GetWatchlist(ctx context.Context, id string) (*model.Watchlist, error)
+	// ListWatchlists returns all watchlists ordered by name
+	ListWatchlists(ctx context.Context) ([]*model.Watchlist, error)
+	// DeleteWatchlist returns ErrWatchlistNotFound if the watchlist does not exist.
+	// Hits of the watchlist are kept.
+	DeleteWatchlist(ctx context.Context, id string) error
+
+	// SaveWatchlistHits stores hits whose ID does not exist yet. Existing hits,
+	// including their acknowledgement, are left unchanged. Returns the stored hits.
+	SaveWatchlistHits(ctx context.Context, hits []*model.WatchlistHit) ([]*model.WatchlistHit, error)
+	// GetWatchlistHit returns ErrWatchlistHitNotFound if the hit does not exist
+	GetWatchlistHit(ctx context.Context, id string) (*model.WatchlistHit, error)
+	// ListWatchlistHits returns hits ordered by CreatedAt descending (newest first)
+	ListWatchlistHits(ctx context.Context, opts *model.WatchlistHitListOptions) (*model.WatchlistHitConnection, error)
+	// AcknowledgeWatchlistHits marks hits as acknowledged by actor and returns them.
+	// Returns ErrWatchlistHitNotFound if any hit does not exist; no hit is changed in that case.
+	AcknowledgeWatchlistHits(ctx context.Context, ids []string, actor string, at time.Time) ([]*model.WatchlistHit, error)
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/types"
+)
+
+// APITokenPrefix starts every API token so that they can be told apart from
+// JWTs and static tokens, and found by secret scanners
+const APITokenPrefix = "bhv_"
+
+// TokenScope is a permission of an API token. API tokens have no role; they
+// can only do what their scopes allow.
+type TokenScope string
+
+const (
+	// ScopeReadIoCs allows reading IoCs through GraphQL and exporting IoCs of any tag
+	ScopeReadIoCs TokenScope = "read:iocs"
+	// ScopeFetchTrigger allows starting, following and canceling fetch jobs
+	ScopeFetchTrigger TokenScope = "fetch:trigger"
+
+	scopeExportPrefix = "export:"
+)
+
+// ExportScope returns the scope allowing the export of IoCs with tag
+func ExportScope(tag string) TokenScope {
+	return TokenScope(scopeExportPrefix + tag)
+}
+
+// ParseTokenScope parses a scope name: read:iocs, fetch:trigger or export:<tag>
+func ParseTokenScope(s string) (TokenScope, error) {
+	switch scope := TokenScope(s); scope {
+	case ScopeReadIoCs, ScopeFetchTrigger:
+		return scope, nil
+	}
+
+	tag, ok := strings.CutPrefix(s, scopeExportPrefix)
+	if !ok {
+		return "", goerr.New("unknown token scope", goerr.V("scope", s))
+	}
+	if _, err := types.NewTag(tag); err != nil {
+		return "", goerr.Wrap(err, "invalid tag of export scope", goerr.V("scope", s))
+	}
+	return ExportScope(tag), nil
+}
+
+// ExportTag returns the tag of an export:<tag> scope
+func (s TokenScope) ExportTag() (string, bool) {
+	return strings.CutPrefix(string(s), scopeExportPrefix)
+}
+
+// APIToken is a long-lived credential of a machine consumer. Only the hash of
+// the token is stored; the token itself is shown once when it is created.
+type APIToken struct {
+	ID         string       // Unique identifier (UUIDv7)
+	Name       string       // Name of the consumer, unique among active tokens
+	Hash       string       // Hex SHA-256 of the token, see HashAPIToken
+	Scopes     []TokenScope // Permissions of the token
+	CreatedBy  string       // Actor that created the token
+	CreatedAt  time.Time    // Creation time
+	ExpiresAt  time.Time    // Time the token expires (zero = never)
+	LastUsedAt time.Time    // Last time the token authenticated a request (zero = never)
+	RevokedAt  time.Time    // Time the token was revoked (zero = not revoked)
+}
+
+// NewAPIToken generates a token and returns its record and the token
+func NewAPIToken(name string, scopes []TokenScope, createdBy string, expiresAt, now time.Time) (*APIToken, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", goerr.Wrap(err, "failed to generate API token")
+	}
+	token := APITokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return &APIToken{
+		ID:        uuid.Must(uuid.NewV7()).String(),
+		Name:      name,
+		Hash:      HashAPIToken(token),
+		Scopes:    scopes,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}, token, nil
+}
+
+// HashAPIToken returns the stored hash of an API token
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Revoked reports whether the token has been revoked
+func (t *APIToken) Revoked() bool {
+	return !t.RevokedAt.IsZero()
+}
+
+// Expired reports whether the token has expired at now
+func (t *APIToken) Expired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt)
+}
+
+// Active reports whether the token can authenticate requests at now
+func (t *APIToken) Active(now time.Time) bool {
+	return !t.Revoked() && !t.Expired(now)
+}
+
+// Principal returns the principal of requests authenticated by the token
+func (t *APIToken) Principal() *Principal {
+	return &Principal{
+		Subject: t.Name,
+		Method:  AuthMethodAPIToken,
+		Scopes:  append([]TokenScope{}, t.Scopes...),
+	}
+}
+
+// HasScope reports whether the principal is an API token with scope
+func (p *Principal) HasScope(scope TokenScope) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// Scoped reports whether the principal is authorized by scopes instead of a role
+func (p *Principal) Scoped() bool {
+	return p.Scopes != nil
+}
+
+// CanExport reports whether the principal may export IoCs with tag
+func (p *Principal) CanExport(tag string) bool {
+	if p.Scoped() {
+		return p.HasScope(ScopeReadIoCs) || p.HasScope(ExportScope(tag))
+	}
+	return p.Role.Includes(RoleViewer)
+}
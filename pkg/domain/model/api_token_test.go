@@ -0,0 +1,68 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func TestParseTokenScope(t *testing.T) {
+	for _, s := range []string{"read:iocs", "fetch:trigger", "export:blocklist", "export:c2-servers"} {
+		scope, err := model.ParseTokenScope(s)
+		gt.NoError(t, err)
+		gt.Equal(t, string(scope), s)
+	}
+	for _, s := range []string{"", "admin", "read:sources", "export:", "export:bad tag"} {
+		_, err := model.ParseTokenScope(s)
+		gt.Error(t, err).Describef("scope %q should be rejected", s)
+	}
+
+	tag, ok := model.ExportScope("blocklist").ExportTag()
+	gt.True(t, ok)
+	gt.Equal(t, tag, "blocklist")
+	_, ok = model.ScopeReadIoCs.ExportTag()
+	gt.False(t, ok)
+}
+
+func TestNewAPIToken(t *testing.T) {
+	now := time.Now()
+	token, secret, err := model.NewAPIToken("firewall", []model.TokenScope{model.ExportScope("blocklist")},
+		"user:alice", now.Add(time.Hour), now)
+	gt.NoError(t, err)
+	gt.True(t, strings.HasPrefix(secret, model.APITokenPrefix))
+	gt.Equal(t, token.Hash, model.HashAPIToken(secret))
+	gt.False(t, strings.Contains(token.Hash, secret))
+
+	gt.True(t, token.Active(now))
+	gt.False(t, token.Active(now.Add(time.Hour)))
+	token.RevokedAt = now
+	gt.False(t, token.Active(now))
+
+	_, other, err := model.NewAPIToken("firewall", nil, "user:alice", time.Time{}, now)
+	gt.NoError(t, err)
+	gt.NotEqual(t, other, secret)
+}
+
+func TestPrincipal_CanExport(t *testing.T) {
+	exporter := &model.Principal{Subject: "fw", Method: model.AuthMethodAPIToken,
+		Scopes: []model.TokenScope{model.ExportScope("blocklist")}}
+	gt.True(t, exporter.Scoped())
+	gt.True(t, exporter.CanExport("blocklist"))
+	gt.False(t, exporter.CanExport("internal"))
+	gt.Equal(t, exporter.Actor(), "api-token:fw")
+
+	reader := &model.Principal{Subject: "soar", Method: model.AuthMethodAPIToken,
+		Scopes: []model.TokenScope{model.ScopeReadIoCs}}
+	gt.True(t, reader.CanExport("internal"))
+
+	trigger := &model.Principal{Subject: "ci", Method: model.AuthMethodAPIToken,
+		Scopes: []model.TokenScope{model.ScopeFetchTrigger}}
+	gt.False(t, trigger.CanExport("blocklist"))
+
+	viewer := &model.Principal{Subject: "victor", Role: model.RoleViewer, Method: model.AuthMethodJWT}
+	gt.False(t, viewer.Scoped())
+	gt.True(t, viewer.CanExport("blocklist"))
+}
@@ -0,0 +1,181 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// AuditAction is a kind of state-changing operation recorded in the audit log
+type AuditAction string
+
+const (
+	AuditIoCCreate               AuditAction = "ioc.create"
+	AuditIoCUpdate               AuditAction = "ioc.update" // Includes status overrides
+	AuditIoCDelete               AuditAction = "ioc.delete"
+	AuditWatchlistCreate         AuditAction = "watchlist.create"
+	AuditWatchlistUpdate         AuditAction = "watchlist.update"
+	AuditWatchlistDelete         AuditAction = "watchlist.delete"
+	AuditWatchlistHitAcknowledge AuditAction = "watchlist_hit.acknowledge"
+	AuditAPITokenCreate          AuditAction = "api_token.create"
+	AuditAPITokenRevoke          AuditAction = "api_token.revoke"
+	AuditFetchStart              AuditAction = "fetch.start"  // Fetch job requested through the API
+	AuditFetchCancel             AuditAction = "fetch.cancel" // Fetch job canceled through the API
+	AuditFetchRun                AuditAction = "fetch.run"    // Completed fetch of a source, scheduled or requested
+)
+
+// AuditSourceAction returns the audit action of a source change, e.g. "source.update"
+func AuditSourceAction(action SourceChangeAction) AuditAction {
+	return AuditAction("source." + string(action))
+}
+
+// Target types of audit records
+const (
+	AuditTargetIoC          = "ioc"
+	AuditTargetSource       = "source"
+	AuditTargetWatchlist    = "watchlist"
+	AuditTargetWatchlistHit = "watchlist_hit"
+	AuditTargetAPIToken     = "api_token"
+	AuditTargetJob          = "job"
+)
+
+// AuditChange is a changed field of an audit record. Values are JSON encoded;
+// a value is empty if the field did not exist before or after the change.
+type AuditChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// AuditRecord is an append-only record of a state-changing operation
+type AuditRecord struct {
+	ID         string        // Unique identifier (UUIDv7)
+	Actor      string        // Principal that made the change, see Principal.Actor
+	Action     AuditAction   // Kind of operation
+	TargetType string        // Kind of the changed object, e.g. AuditTargetIoC
+	TargetID   string        // ID of the changed object
+	Changes    []AuditChange // Fields changed by the operation
+	RequestID  string        // ID of the HTTP request, empty outside the server
+	CreatedAt  time.Time     // Time of the operation
+}
+
+// NewAuditRecord creates a record of an operation with the diff of the object
+// before and after it. before or after is nil for creations and deletions.
+// Objects are compared by their top-level JSON fields.
+func NewAuditRecord(ctx context.Context, actor string, action AuditAction, targetType, targetID string, before, after any) (*AuditRecord, error) {
+	changes, err := DiffAudit(before, after)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to diff audit target",
+			goerr.V("action", action),
+			goerr.V("target_id", targetID))
+	}
+	return &AuditRecord{
+		ID:         uuid.Must(uuid.NewV7()).String(),
+		Actor:      actor,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Changes:    changes,
+		RequestID:  RequestIDFrom(ctx),
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// DiffAudit returns the top-level JSON fields that differ between before and
+// after, sorted by name
+func DiffAudit(before, after any) ([]AuditChange, error) {
+	b, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := slices.Collect(maps.Keys(b))
+	for field := range a {
+		if _, ok := b[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	var changes []AuditChange
+	for _, field := range fields {
+		if bytes.Equal(b[field], a[field]) {
+			continue
+		}
+		changes = append(changes, AuditChange{
+			Field:  field,
+			Before: string(b[field]),
+			After:  string(a[field]),
+		})
+	}
+	return changes, nil
+}
+
+func auditFields(v any) (map[string]json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if v == nil {
+		return fields, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to encode audit target")
+	}
+	if bytes.Equal(raw, []byte("null")) {
+		return fields, nil
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		// Not an object; the whole value is one field
+		return map[string]json.RawMessage{"value": raw}, nil
+	}
+	return fields, nil
+}
+
+// AuditFilter selects audit records. Empty fields match every record.
+type AuditFilter struct {
+	Actor      string
+	Action     AuditAction
+	TargetType string
+	TargetID   string
+	RequestID  string
+	Since      time.Time // Records created at or after Since
+	Until      time.Time // Records created before Until
+	Limit      int       // Maximum number of records, <= 0 means no limit
+}
+
+// Match reports whether a record matches the filter, ignoring Limit
+func (f *AuditFilter) Match(r *AuditRecord) bool {
+	switch {
+	case f.Actor != "" && r.Actor != f.Actor,
+		f.Action != "" && r.Action != f.Action,
+		f.TargetType != "" && r.TargetType != f.TargetType,
+		f.TargetID != "" && r.TargetID != f.TargetID,
+		f.RequestID != "" && r.RequestID != f.RequestID,
+		!f.Since.IsZero() && r.CreatedAt.Before(f.Since),
+		!f.Until.IsZero() && !r.CreatedAt.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the ID of an HTTP request
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom returns the ID of the HTTP request of ctx, empty if none
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,102 @@
+package model_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func TestDiffAudit(t *testing.T) {
+	type target struct {
+		Name   string
+		Status string
+		Tags   []string `json:",omitempty"`
+	}
+
+	t.Run("update reports changed fields only", func(t *testing.T) {
+		changes, err := model.DiffAudit(
+			&target{Name: "a", Status: "active"},
+			&target{Name: "a", Status: "false_positive", Tags: []string{"fp"}},
+		)
+		gt.NoError(t, err)
+		gt.Equal(t, changes, []model.AuditChange{
+			{Field: "Status", Before: `"active"`, After: `"false_positive"`},
+			{Field: "Tags", Before: "", After: `["fp"]`},
+		})
+	})
+
+	t.Run("creation and deletion", func(t *testing.T) {
+		changes, err := model.DiffAudit(nil, &target{Name: "a"})
+		gt.NoError(t, err)
+		gt.A(t, changes).Length(2)
+		gt.Equal(t, changes[0], model.AuditChange{Field: "Name", After: `"a"`})
+
+		var none *target
+		changes, err = model.DiffAudit(&target{Name: "a"}, none)
+		gt.NoError(t, err)
+		gt.A(t, changes).Length(2)
+		gt.Equal(t, changes[0], model.AuditChange{Field: "Name", Before: `"a"`})
+	})
+
+	t.Run("non-object values", func(t *testing.T) {
+		changes, err := model.DiffAudit(nil, []string{"id-1"})
+		gt.NoError(t, err)
+		gt.Equal(t, changes, []model.AuditChange{{Field: "value", After: `["id-1"]`}})
+	})
+
+	t.Run("no change", func(t *testing.T) {
+		changes, err := model.DiffAudit(&target{Name: "a"}, &target{Name: "a"})
+		gt.NoError(t, err)
+		gt.A(t, changes).Length(0)
+	})
+}
+
+func TestNewAuditRecord(t *testing.T) {
+	ctx := model.WithRequestID(context.Background(), "host/abc-000001")
+	record, err := model.NewAuditRecord(ctx, "user:alice", model.AuditIoCDelete, model.AuditTargetIoC, "ioc-1",
+		map[string]string{"Value": "198.51.100.1"}, nil)
+	gt.NoError(t, err)
+	gt.NotEqual(t, record.ID, "")
+	gt.Equal(t, record.RequestID, "host/abc-000001")
+	gt.Equal(t, record.Changes, []model.AuditChange{{Field: "Value", Before: `"198.51.100.1"`}})
+
+	record, err = model.NewAuditRecord(context.Background(), model.ActorFetcher, model.AuditFetchRun,
+		model.AuditTargetSource, "src", nil, nil)
+	gt.NoError(t, err)
+	gt.Equal(t, record.RequestID, "")
+	gt.A(t, record.Changes).Length(0)
+}
+
+func TestAuditFilter_Match(t *testing.T) {
+	now := time.Now()
+	record := &model.AuditRecord{
+		Actor:      "user:alice",
+		Action:     model.AuditSourceAction(model.SourceChangeUpdate),
+		TargetType: model.AuditTargetSource,
+		TargetID:   "feed",
+		RequestID:  "req-1",
+		CreatedAt:  now,
+	}
+
+	for _, f := range []model.AuditFilter{
+		{},
+		{Actor: "user:alice", Action: "source.update", TargetType: "source", TargetID: "feed", RequestID: "req-1"},
+		{Since: now, Until: now.Add(time.Second)},
+	} {
+		gt.True(t, f.Match(record))
+	}
+	for _, f := range []model.AuditFilter{
+		{Actor: "user:bob"},
+		{Action: model.AuditIoCUpdate},
+		{TargetType: model.AuditTargetIoC},
+		{TargetID: "other"},
+		{RequestID: "req-2"},
+		{Since: now.Add(time.Nanosecond)},
+		{Until: now},
+	} {
+		gt.False(t, f.Match(record))
+	}
+}
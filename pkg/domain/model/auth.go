@@ -0,0 +1,98 @@
+package model
+
+import (
+	"context"
+
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// Role is the permission level of an authenticated principal. Each role
+// includes the permissions of the roles below it.
+type Role string
+
+const (
+	RoleViewer  Role = "viewer"  // Read IoCs, sources and fetch results
+	RoleAnalyst Role = "analyst" // Curate IoCs and watchlists, trigger fetches
+	RoleAdmin   Role = "admin"   // Manage sources and server configuration
+)
+
+var roleRanks = map[Role]int{
+	RoleViewer:  1,
+	RoleAnalyst: 2,
+	RoleAdmin:   3,
+}
+
+// ParseRole parses a role name
+func ParseRole(s string) (Role, error) {
+	role := Role(s)
+	if _, ok := roleRanks[role]; !ok {
+		return "", goerr.New("unknown role", goerr.V("role", s))
+	}
+	return role, nil
+}
+
+// IsValid reports whether r is a known role
+func (r Role) IsValid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// Includes reports whether r has the permissions of required
+func (r Role) Includes(required Role) bool {
+	return r.IsValid() && roleRanks[r] >= roleRanks[required]
+}
+
+// AuthMethod is how a principal was authenticated
+type AuthMethod string
+
+const (
+	AuthMethodJWT       AuthMethod = "jwt"       // OIDC/JWT bearer token
+	AuthMethodToken     AuthMethod = "token"     // Static API token
+	AuthMethodAPIToken  AuthMethod = "api_token" // API token minted by an admin, see APIToken
+	AuthMethodHeader    AuthMethod = "header"    // Header set by a trusted proxy
+	AuthMethodAnonymous AuthMethod = "anonymous" // No credentials, allowed by configuration
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Subject string // User or token name
+	Role    Role
+	Method  AuthMethod
+	Scopes  []TokenScope // Permissions of API tokens, which have no role; nil otherwise
+}
+
+// Actor returns the actor recorded for changes made by the principal
+func (p *Principal) Actor() string {
+	switch p.Method {
+	case AuthMethodAnonymous:
+		return ActorAnalyst
+	case AuthMethodToken:
+		return "token:" + p.Subject
+	case AuthMethodAPIToken:
+		return "api-token:" + p.Subject
+	default:
+		return "user:" + p.Subject
+	}
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the principal of a request
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal of a request, nil if unauthenticated
+func PrincipalFrom(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// ActorFrom returns the actor recorded for changes made in ctx, ActorAnalyst
+// if no principal is known
+func ActorFrom(ctx context.Context) string {
+	if p := PrincipalFrom(ctx); p != nil {
+		return p.Actor()
+	}
+	return ActorAnalyst
+}
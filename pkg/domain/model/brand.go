@@ -0,0 +1,84 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// LookalikeTechnique represents how a domain imitates a protected domain
+type LookalikeTechnique string
+
+const (
+	// LookalikeTyposquat is a small edit of the protected name (omission, insertion,
+	// substitution or transposition of characters), e.g. examlpe.com
+	LookalikeTyposquat LookalikeTechnique = "typosquat"
+	// LookalikeHomoglyph replaces ASCII characters with look-alike ASCII sequences,
+	// e.g. rn for m or 0 for o
+	LookalikeHomoglyph LookalikeTechnique = "homoglyph"
+	// LookalikeIDN uses non-ASCII (punycode) characters that render like the protected
+	// name, e.g. Cyrillic а for Latin a
+	LookalikeIDN LookalikeTechnique = "idn"
+	// LookalikeCombosquat adds words to the protected name, e.g. example-login.com
+	LookalikeCombosquat LookalikeTechnique = "combosquat"
+	// LookalikeBitsquat differs from the protected name by a single bit flip in one character
+	LookalikeBitsquat LookalikeTechnique = "bitsquat"
+	// LookalikeTLDSwap uses the protected name under another public suffix, e.g. example.net
+	LookalikeTLDSwap LookalikeTechnique = "tld_swap"
+)
+
+// AllLookalikeTechniques lists the techniques in the order they are evaluated
+var AllLookalikeTechniques = []LookalikeTechnique{
+	LookalikeIDN,
+	LookalikeHomoglyph,
+	LookalikeBitsquat,
+	LookalikeTyposquat,
+	LookalikeCombosquat,
+	LookalikeTLDSwap,
+}
+
+// IsValid returns true if the technique is one of the defined techniques
+func (t LookalikeTechnique) IsValid() bool {
+	for _, v := range AllLookalikeTechniques {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+// BrandMatch records a domain or URL IoC whose host imitates a protected domain
+type BrandMatch struct {
+	ID              string // Derived from protected domain and IoC ID
+	ProtectedDomain string
+	Domain          string // Registrable domain of the IoC host (Unicode for IDNs)
+	IoCID           string
+	IoCType         IoCType
+	IoCValue        string
+	SourceID        string
+	Technique       LookalikeTechnique
+	EditDistance    int     // Edit distance between the registrable labels
+	Score           float64 // Similarity to the protected domain, 0.0-1.0
+	CreatedAt       time.Time
+}
+
+// BrandMatchID returns the ID of the match of an IoC against a protected domain.
+// An IoC produces at most one match per protected domain.
+func BrandMatchID(protectedDomain, iocID string) string {
+	sum := sha256.Sum256([]byte(protectedDomain + "\x00" + iocID))
+	return hex.EncodeToString(sum[:16])
+}
+
+// BrandMatchListOptions represents filter and pagination options for listing brand matches
+type BrandMatchListOptions struct {
+	ProtectedDomain string             // Empty = all protected domains
+	Technique       LookalikeTechnique // Empty = all techniques
+	Offset          int
+	Limit           int // 0 = no limit
+}
+
+// BrandMatchConnection represents a paginated list of brand matches
+type BrandMatchConnection struct {
+	Items []*BrandMatch
+	Total int
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// DefaultSourceReliability is used for sources without a configured reliability
+	DefaultSourceReliability = 0.5
+	// ManualSourceReliability is used for analyst-managed IoCs unless configured
+	ManualSourceReliability = 0.9
+	// DefaultConfidenceHalfLife is the age at which the recency factor halves
+	DefaultConfidenceHalfLife = 30 * Day
+	// DefaultLLMExtractionFactor scales the score of IoCs extracted by an LLM
+	DefaultLLMExtractionFactor = 0.8
+)
+
+// ConfidencePolicy computes the 0-100 confidence score of an IoC.
+//
+// The score combines:
+//   - reliability of the reporting source (0-1, configured per source)
+//   - feed-native confidence (ThreatFox confidence_level, IPsum level), falling back to reliability
+//   - corroboration by independent sources reporting the same normalized value
+//   - recency, decaying with the age of the last sighting (or FirstSeenAt)
+//   - a penalty for values extracted from free text by an LLM
+type ConfidencePolicy struct {
+	SourceReliability  map[string]float64 // Source ID -> reliability (0-1)
+	DefaultReliability float64            // Reliability of sources not in SourceReliability
+	HalfLife           time.Duration      // Recency half-life (0 = no decay)
+	LLMFactor          float64            // Multiplier for LLM-extracted IoCs
+}
+
+// NewConfidencePolicy creates a policy with default parameters and the given source reliabilities
+func NewConfidencePolicy(reliability map[string]float64) *ConfidencePolicy {
+	if reliability == nil {
+		reliability = map[string]float64{}
+	}
+	return &ConfidencePolicy{
+		SourceReliability:  reliability,
+		DefaultReliability: DefaultSourceReliability,
+		HalfLife:           DefaultConfidenceHalfLife,
+		LLMFactor:          DefaultLLMExtractionFactor,
+	}
+}
+
+// WithSourceReliability returns a copy of the policy with the reliability of a
+// source set. The policy itself is returned if reliability is nil.
+func (p *ConfidencePolicy) WithSourceReliability(sourceID string, reliability *float64) *ConfidencePolicy {
+	if reliability == nil {
+		return p
+	}
+	c := *p
+	c.SourceReliability = make(map[string]float64, len(p.SourceReliability)+1)
+	for id, r := range p.SourceReliability {
+		c.SourceReliability[id] = r
+	}
+	c.SourceReliability[sourceID] = *reliability
+	return &c
+}
+
+// Reliability returns the reliability of a source
+func (p *ConfidencePolicy) Reliability(sourceID string) float64 {
+	if r, ok := p.SourceReliability[sourceID]; ok {
+		return r
+	}
+	if sourceID == ManualSourceID {
+		return ManualSourceReliability
+	}
+	return p.DefaultReliability
+}
+
+// Score computes the confidence score of an IoC. sourceCount is the number of
+// independent sources reporting the same type and normalized value, including
+// the IoC's own source.
+func (p *ConfidencePolicy) Score(ioc *IoC, sourceCount int, now time.Time) int {
+	reliability := p.Reliability(ioc.SourceID)
+
+	native := reliability
+	if ioc.SourceConfidence > 0 {
+		native = float64(ioc.SourceConfidence) / 100
+	}
+
+	// 1 source = 0.5, 2 sources = 0.75, 3 sources = 0.875, ...
+	corroboration := 1 - math.Pow(0.5, float64(max(sourceCount, 1)))
+
+	recency := 1.0
+	seenAt := ioc.LastSeenAt
+	if seenAt.IsZero() {
+		seenAt = ioc.FirstSeenAt
+	}
+	if p.HalfLife > 0 && !seenAt.IsZero() && now.After(seenAt) {
+		recency = math.Pow(0.5, float64(now.Sub(seenAt))/float64(p.HalfLife))
+	}
+
+	score := (0.4*reliability + 0.3*native + 0.3*corroboration) * (0.5 + 0.5*recency)
+	if ioc.ExtractedByLLM {
+		score *= p.LLMFactor
+	}
+
+	return min(max(int(math.Round(score*100)), 0), 100)
+}
@@ -0,0 +1,83 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func TestConfidencePolicy_Score(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	policy := model.NewConfidencePolicy(map[string]float64{
+		"trusted":   0.9,
+		"untrusted": 0.1,
+	})
+
+	newIoC := func(sourceID string) *model.IoC {
+		return &model.IoC{SourceID: sourceID, Type: model.IoCTypeIPv4, Value: "198.51.100.1"}
+	}
+
+	t.Run("score range", func(t *testing.T) {
+		best := newIoC("trusted")
+		best.SourceConfidence = 100
+		gt.N(t, policy.Score(best, 10, now)).LessOrEqual(100)
+
+		worst := newIoC("untrusted")
+		worst.ExtractedByLLM = true
+		worst.LastSeenAt = now.Add(-365 * model.Day)
+		gt.N(t, policy.Score(worst, 1, now)).GreaterOrEqual(0)
+	})
+
+	t.Run("source reliability", func(t *testing.T) {
+		gt.N(t, policy.Score(newIoC("trusted"), 1, now)).
+			Greater(policy.Score(newIoC("unknown"), 1, now))
+		gt.N(t, policy.Score(newIoC("unknown"), 1, now)).
+			Greater(policy.Score(newIoC("untrusted"), 1, now))
+	})
+
+	t.Run("corroboration", func(t *testing.T) {
+		ioc := newIoC("unknown")
+		gt.N(t, policy.Score(ioc, 3, now)).Greater(policy.Score(ioc, 1, now))
+		gt.Equal(t, policy.Score(ioc, 0, now), policy.Score(ioc, 1, now))
+	})
+
+	t.Run("recency decay", func(t *testing.T) {
+		fresh := newIoC("unknown")
+		fresh.LastSeenAt = now
+		old := newIoC("unknown")
+		old.LastSeenAt = now.Add(-90 * model.Day)
+		gt.N(t, policy.Score(fresh, 1, now)).Greater(policy.Score(old, 1, now))
+
+		// Edits without a new sighting do not make an IoC recent
+		edited := newIoC("unknown")
+		edited.LastSeenAt = old.LastSeenAt
+		edited.UpdatedAt = now
+		gt.Equal(t, policy.Score(edited, 1, now), policy.Score(old, 1, now))
+
+		// FirstSeenAt is used when LastSeenAt is not set
+		firstSeen := newIoC("unknown")
+		firstSeen.FirstSeenAt = old.LastSeenAt
+		gt.Equal(t, policy.Score(firstSeen, 1, now), policy.Score(old, 1, now))
+	})
+
+	t.Run("feed-native confidence", func(t *testing.T) {
+		high := newIoC("unknown")
+		high.SourceConfidence = 100
+		low := newIoC("unknown")
+		low.SourceConfidence = 10
+		gt.N(t, policy.Score(high, 1, now)).Greater(policy.Score(low, 1, now))
+	})
+
+	t.Run("LLM extraction", func(t *testing.T) {
+		llm := newIoC("unknown")
+		llm.ExtractedByLLM = true
+		gt.N(t, policy.Score(llm, 1, now)).Less(policy.Score(newIoC("unknown"), 1, now))
+	})
+
+	t.Run("manual source", func(t *testing.T) {
+		gt.N(t, policy.Score(newIoC(model.ManualSourceID), 1, now)).
+			Greater(policy.Score(newIoC("unknown"), 1, now))
+	})
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// ConfigReloadTrigger is what caused a load of the sources configuration
+type ConfigReloadTrigger string
+
+const (
+	ConfigReloadStartup ConfigReloadTrigger = "startup"
+	ConfigReloadFile    ConfigReloadTrigger = "file"   // The config file changed
+	ConfigReloadSignal  ConfigReloadTrigger = "signal" // SIGHUP
+)
+
+// ConfigStatus is the result of the last load of the sources configuration
+type ConfigStatus struct {
+	Path      string
+	Trigger   ConfigReloadTrigger // Trigger of the last attempt
+	CheckedAt time.Time           // Time of the last attempt
+	LoadedAt  time.Time           // Time of the last successful load, zero if none
+	Error     string              // Error of the last attempt, empty if it succeeded
+	Sources   int                 // Number of sources in the loaded config
+
+	// Sources changed by the last successful load
+	Created []string
+	Updated []string
+	Deleted []string
+
+	// Sources of the config that differ from the stored sources changed
+	// through the API, which are kept as they are
+	Drifted []string
+}
+
+// InSync returns true if the last attempt succeeded and every source of the
+// config matches the stored source
+func (s *ConfigStatus) InSync() bool {
+	return s.Error == "" && len(s.Drifted) == 0
+}
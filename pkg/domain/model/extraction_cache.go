@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+)
+
+// CachedIoC is an IoC extracted by the LLM as stored in the extraction cache
+type CachedIoC struct {
+	Type        string
+	Value       string
+	Description string
+}
+
+// ExtractionCacheEntry is a cached LLM extraction result of an article. The key
+// is a hash of the prompt version, the model and the article, so entries are not
+// used any more once the prompt or the model changes.
+type ExtractionCacheEntry struct {
+	Key           string
+	SourceID      string // Source that stored the entry
+	Mode          ExtractionMode
+	PromptVersion string
+	Model         string
+
+	IoCs       []*CachedIoC
+	Unverified []*CachedIoC // Values dropped by cross-checking
+
+	CreatedAt time.Time
+	ExpiresAt time.Time // Zero means the entry does not expire
+}
+
+// Expired returns true if the entry is past its expiry at now
+func (e *ExtractionCacheEntry) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !e.ExpiresAt.After(now)
+}
+
+// ExtractionCachePurgeOptions selects the cache entries to delete. Conditions
+// are combined with AND. Empty options delete all entries.
+type ExtractionCachePurgeOptions struct {
+	SourceID string    // Entries stored by this source
+	Before   time.Time // Entries that expire at or before this time
+}
+
+// Matches returns true if the entry is selected by the options
+func (o *ExtractionCachePurgeOptions) Matches(e *ExtractionCacheEntry) bool {
+	if o == nil {
+		return true
+	}
+	if o.SourceID != "" && e.SourceID != o.SourceID {
+		return false
+	}
+	if !o.Before.IsZero() && (e.ExpiresAt.IsZero() || e.ExpiresAt.After(o.Before)) {
+		return false
+	}
+	return true
+}
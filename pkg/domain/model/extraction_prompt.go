@@ -0,0 +1,43 @@
+package model
+
+// ExtractionPrompt customizes the LLM extraction prompt of a source, e.g. for
+// blogs listing IoCs in tables or articles written in another language
+type ExtractionPrompt struct {
+	// Template replaces the built-in extraction prompt template. It is a Go
+	// text/template rendered with the same data as the built-in template.
+	Template string
+	// Instructions are added to the extraction prompt
+	Instructions string
+	// Examples are few-shot examples of article excerpts and the IoCs to extract
+	Examples []PromptExample
+	// Types restricts extraction to these IoC types. Empty means all types.
+	Types []IoCType
+	// Language of the articles, e.g. "Japanese"
+	Language string
+}
+
+// PromptExample is a few-shot example of the extraction prompt
+type PromptExample struct {
+	Input string       `toml:"input"`
+	IoCs  []ExampleIoC `toml:"iocs"`
+}
+
+// ExampleIoC is an IoC to extract from the input of a PromptExample
+type ExampleIoC struct {
+	Type        IoCType `toml:"type" json:"type"`
+	Value       string  `toml:"value" json:"value"`
+	Description string  `toml:"description" json:"description"`
+}
+
+// AllowsType returns true if IoCs of type t are extracted. A nil prompt allows all types.
+func (p *ExtractionPrompt) AllowsType(t IoCType) bool {
+	if p == nil || len(p.Types) == 0 {
+		return true
+	}
+	for _, allowed := range p.Types {
+		if allowed == t {
+			return true
+		}
+	}
+	return false
+}
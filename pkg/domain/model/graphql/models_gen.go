@@ -10,26 +10,169 @@ import (
 	"time"
 )
 
+type APIToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  string     `json:"createdBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	Active     bool       `json:"active"`
+}
+
+// A changed field of an audited object. Values are JSON encoded; a value is null
+// if the field did not exist before or after the change.
+type AuditChange struct {
+	Field  string  `json:"field"`
+	Before *string `json:"before,omitempty"`
+	After  *string `json:"after,omitempty"`
+}
+
+type AuditLogFilter struct {
+	Actor      *string `json:"actor,omitempty"`
+	Action     *string `json:"action,omitempty"`
+	TargetType *string `json:"targetType,omitempty"`
+	TargetID   *string `json:"targetID,omitempty"`
+	RequestID  *string `json:"requestID,omitempty"`
+	// Records created at or after since
+	Since *time.Time `json:"since,omitempty"`
+	// Records created before until
+	Until *time.Time `json:"until,omitempty"`
+	// Maximum number of records, newest first (default 100)
+	Limit *int `json:"limit,omitempty"`
+}
+
+// An append-only record of a state-changing operation
+type AuditRecord struct {
+	ID    string `json:"id"`
+	Actor string `json:"actor"`
+	// e.g. ioc.update, source.create, fetch.run
+	Action string `json:"action"`
+	// ioc, source, watchlist, watchlist_hit, api_token or job
+	TargetType string         `json:"targetType"`
+	TargetID   string         `json:"targetID"`
+	Changes    []*AuditChange `json:"changes"`
+	// ID of the HTTP request, null for operations outside the server
+	RequestID *string   `json:"requestID,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type BrandMatch struct {
+	ID              string    `json:"id"`
+	ProtectedDomain string    `json:"protectedDomain"`
+	Domain          string    `json:"domain"`
+	IocID           string    `json:"iocID"`
+	IocType         string    `json:"iocType"`
+	IocValue        string    `json:"iocValue"`
+	SourceID        string    `json:"sourceID"`
+	Technique       string    `json:"technique"`
+	EditDistance    int       `json:"editDistance"`
+	Score           float64   `json:"score"`
+	CreatedAt       time.Time `json:"createdAt"`
+	Ioc             *IoC      `json:"ioc,omitempty"`
+}
+
+type BrandMatchConnection struct {
+	Items []*BrandMatch `json:"items"`
+	Total int           `json:"total"`
+}
+
+type BrandMatchListOptions struct {
+	ProtectedDomain *string `json:"protectedDomain,omitempty"`
+	Technique       *string `json:"technique,omitempty"`
+	Offset          *int    `json:"offset,omitempty"`
+	Limit           *int    `json:"limit,omitempty"`
+}
+
+type ConfigStatus struct {
+	Path      string     `json:"path"`
+	Trigger   string     `json:"trigger"`
+	CheckedAt time.Time  `json:"checkedAt"`
+	LoadedAt  *time.Time `json:"loadedAt,omitempty"`
+	Ok        bool       `json:"ok"`
+	Error     *string    `json:"error,omitempty"`
+	Sources   int        `json:"sources"`
+	Created   []string   `json:"created"`
+	Updated   []string   `json:"updated"`
+	Deleted   []string   `json:"deleted"`
+	// Sources whose stored definition was changed through the API and differs from the file
+	Drifted []string `json:"drifted"`
+}
+
+type CreateAPITokenInput struct {
+	Name string `json:"name"`
+	// read:iocs, fetch:trigger or export:<tag>
+	Scopes []string `json:"scopes"`
+	// Omit for a token that never expires
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type CreateIoCInput struct {
+	Type        string   `json:"type"`
+	Value       string   `json:"value"`
+	Description *string  `json:"description,omitempty"`
+	Status      *string  `json:"status,omitempty"`
+	SourceURL   *string  `json:"sourceURL,omitempty"`
+	Context     *string  `json:"context,omitempty"`
+	Notes       *string  `json:"notes,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+type CreateSourceInput struct {
+	ID          string   `json:"id"`
+	Type        string   `json:"type"`
+	URL         *string  `json:"url,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Enabled     *bool    `json:"enabled,omitempty"`
+	TTL         *string  `json:"ttl,omitempty"`
+	Reliability *float64 `json:"reliability,omitempty"`
+	Extraction  *string  `json:"extraction,omitempty"`
+	MaxArticles *int     `json:"maxArticles,omitempty"`
+	Schema      *string  `json:"schema,omitempty"`
+	MaxItems    *int     `json:"maxItems,omitempty"`
+}
+
+type CreateWatchlistInput struct {
+	Name        string               `json:"name"`
+	Description *string              `json:"description,omitempty"`
+	Patterns    []*WatchPatternInput `json:"patterns"`
+	Enabled     *bool                `json:"enabled,omitempty"`
+}
+
+// A new API token. The token is only returned here; only its hash is stored.
+type CreatedAPIToken struct {
+	Token    string    `json:"token"`
+	APIToken *APIToken `json:"apiToken"`
+}
+
 type FetchError struct {
 	Message string      `json:"message"`
 	Values  []*KeyValue `json:"values"`
 }
 
 type History struct {
-	ID             string        `json:"id"`
-	SourceID       string        `json:"sourceID"`
-	SourceType     string        `json:"sourceType"`
-	Status         string        `json:"status"`
-	StartedAt      time.Time     `json:"startedAt"`
-	CompletedAt    time.Time     `json:"completedAt"`
-	ProcessingTime int           `json:"processingTime"`
-	Urls           []string      `json:"urls"`
+	ID             string    `json:"id"`
+	SourceID       string    `json:"sourceID"`
+	SourceType     string    `json:"sourceType"`
+	Status         string    `json:"status"`
+	StartedAt      time.Time `json:"startedAt"`
+	CompletedAt    time.Time `json:"completedAt"`
+	ProcessingTime int       `json:"processingTime"`
+	Urls           []string  `json:"urls"`
+	// OpenTelemetry trace ID of the fetch, null if tracing was disabled
+	TraceID        *string       `json:"traceID,omitempty"`
 	ItemsFetched   int           `json:"itemsFetched"`
 	IoCsExtracted  int           `json:"ioCsExtracted"`
+	IoCsUnverified int           `json:"ioCsUnverified"`
 	IoCsCreated    int           `json:"ioCsCreated"`
 	IoCsUpdated    int           `json:"ioCsUpdated"`
 	IoCsUnchanged  int           `json:"ioCsUnchanged"`
 	ErrorCount     int           `json:"errorCount"`
+	WatchlistHits  int           `json:"watchlistHits"`
+	BrandMatches   int           `json:"brandMatches"`
 	Errors         []*FetchError `json:"errors"`
 	CreatedAt      time.Time     `json:"createdAt"`
 }
@@ -40,17 +183,27 @@ type HistoryConnection struct {
 }
 
 type IoC struct {
-	ID          string    `json:"id"`
-	SourceID    string    `json:"sourceID"`
-	SourceType  string    `json:"sourceType"`
-	Type        string    `json:"type"`
-	Value       string    `json:"value"`
-	Description string    `json:"description"`
-	SourceURL   *string   `json:"sourceURL,omitempty"`
-	Context     string    `json:"context"`
-	Status      string    `json:"status"`
-	FirstSeenAt time.Time `json:"firstSeenAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID               string                 `json:"id"`
+	SourceID         string                 `json:"sourceID"`
+	SourceType       string                 `json:"sourceType"`
+	Type             string                 `json:"type"`
+	Value            string                 `json:"value"`
+	Description      string                 `json:"description"`
+	SourceURL        *string                `json:"sourceURL,omitempty"`
+	Context          string                 `json:"context"`
+	Status           string                 `json:"status"`
+	FirstSeenAt      time.Time              `json:"firstSeenAt"`
+	UpdatedAt        time.Time              `json:"updatedAt"`
+	ExpiresAt        *time.Time             `json:"expiresAt,omitempty"`
+	Confidence       int                    `json:"confidence"`
+	SourceConfidence *int                   `json:"sourceConfidence,omitempty"`
+	ExtractedByLlm   bool                   `json:"extractedByLLM"`
+	Tags             []string               `json:"tags"`
+	Notes            string                 `json:"notes"`
+	Override         *IoCOverride           `json:"override,omitempty"`
+	StatusHistory    []*IoCStatusTransition `json:"statusHistory"`
+	ReportID         *string                `json:"reportID,omitempty"`
+	Report           *Report                `json:"report,omitempty"`
 }
 
 type IoCConnection struct {
@@ -59,10 +212,42 @@ type IoCConnection struct {
 }
 
 type IoCListOptions struct {
-	Offset    *int          `json:"offset,omitempty"`
-	Limit     *int          `json:"limit,omitempty"`
-	SortField *IoCSortField `json:"sortField,omitempty"`
-	SortOrder *SortOrder    `json:"sortOrder,omitempty"`
+	Offset        *int          `json:"offset,omitempty"`
+	Limit         *int          `json:"limit,omitempty"`
+	SortField     *IoCSortField `json:"sortField,omitempty"`
+	SortOrder     *SortOrder    `json:"sortOrder,omitempty"`
+	MinConfidence *int          `json:"minConfidence,omitempty"`
+	Types         []IoCType     `json:"types,omitempty"`
+}
+
+type IoCOverride struct {
+	Description *string   `json:"description,omitempty"`
+	Status      *string   `json:"status,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type IoCStatusTransition struct {
+	ID        string    `json:"id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Job struct {
+	ID             string     `json:"id"`
+	SourceID       string     `json:"sourceID"`
+	Status         string     `json:"status"`
+	Phase          *string    `json:"phase,omitempty"`
+	ItemsTotal     int        `json:"itemsTotal"`
+	ItemsProcessed int        `json:"itemsProcessed"`
+	IocsExtracted  int        `json:"iocsExtracted"`
+	HistoryID      *string    `json:"historyID,omitempty"`
+	Error          *string    `json:"error,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	StartedAt      *time.Time `json:"startedAt,omitempty"`
+	FinishedAt     *time.Time `json:"finishedAt,omitempty"`
 }
 
 type KeyValue struct {
@@ -73,9 +258,47 @@ type KeyValue struct {
 type Mutation struct {
 }
 
+type Principal struct {
+	Subject string `json:"subject"`
+	// Null for API tokens
+	Role   *Role  `json:"role,omitempty"`
+	Method string `json:"method"`
+	// Scopes of API tokens
+	Scopes []string `json:"scopes"`
+}
+
 type Query struct {
 }
 
+type Report struct {
+	ID               string     `json:"id"`
+	SourceID         string     `json:"sourceID"`
+	Title            string     `json:"title"`
+	URL              string     `json:"url"`
+	PublishedAt      *time.Time `json:"publishedAt,omitempty"`
+	Content          string     `json:"content"`
+	Summary          string     `json:"summary"`
+	ThreatActors     []string   `json:"threatActors"`
+	MalwareFamilies  []string   `json:"malwareFamilies"`
+	Sectors          []string   `json:"sectors"`
+	Cves             []string   `json:"cves"`
+	AttackTechniques []string   `json:"attackTechniques"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+	Iocs             []*IoC     `json:"iocs"`
+}
+
+type ReportConnection struct {
+	Items []*Report `json:"items"`
+	Total int       `json:"total"`
+}
+
+type ReportListOptions struct {
+	SourceID *string `json:"sourceID,omitempty"`
+	Offset   *int    `json:"offset,omitempty"`
+	Limit    *int    `json:"limit,omitempty"`
+}
+
 type Source struct {
 	ID                string       `json:"id"`
 	Type              string       `json:"type"`
@@ -86,6 +309,24 @@ type Source struct {
 	Tags              []string     `json:"tags"`
 	Enabled           bool         `json:"enabled"`
 	State             *SourceState `json:"state,omitempty"`
+	TTL               *string      `json:"ttl,omitempty"`
+	Reliability       *float64     `json:"reliability,omitempty"`
+	Extraction        *string      `json:"extraction,omitempty"`
+	MaxArticles       *int         `json:"maxArticles,omitempty"`
+	MaxItems          *int         `json:"maxItems,omitempty"`
+	CreatedAt         *time.Time   `json:"createdAt,omitempty"`
+	UpdatedAt         *time.Time   `json:"updatedAt,omitempty"`
+}
+
+type SourceChange struct {
+	ID        string    `json:"id"`
+	SourceID  string    `json:"sourceID"`
+	Action    string    `json:"action"`
+	Actor     string    `json:"actor"`
+	Fields    []string  `json:"fields"`
+	Before    *Source   `json:"before,omitempty"`
+	After     *Source   `json:"after,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type SourceState struct {
@@ -100,6 +341,88 @@ type SourceState struct {
 	UpdatedAt     *time.Time `json:"updatedAt,omitempty"`
 }
 
+type Subscription struct {
+}
+
+type UpdateIoCInput struct {
+	Description *string  `json:"description,omitempty"`
+	Status      *string  `json:"status,omitempty"`
+	Notes       *string  `json:"notes,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Reason      *string  `json:"reason,omitempty"`
+	// Remove the analyst override of an IoC from a source before description and status are applied, restoring the source values
+	ClearOverride *bool `json:"clearOverride,omitempty"`
+}
+
+type UpdateSourceInput struct {
+	URL         *string  `json:"url,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	TTL         *string  `json:"ttl,omitempty"`
+	Reliability *float64 `json:"reliability,omitempty"`
+	Extraction  *string  `json:"extraction,omitempty"`
+	MaxArticles *int     `json:"maxArticles,omitempty"`
+	Schema      *string  `json:"schema,omitempty"`
+	MaxItems    *int     `json:"maxItems,omitempty"`
+}
+
+type UpdateWatchlistInput struct {
+	Name        *string              `json:"name,omitempty"`
+	Description *string              `json:"description,omitempty"`
+	Patterns    []*WatchPatternInput `json:"patterns,omitempty"`
+	Enabled     *bool                `json:"enabled,omitempty"`
+}
+
+type WatchPattern struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type WatchPatternInput struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type Watchlist struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Patterns    []*WatchPattern `json:"patterns"`
+	Enabled     bool            `json:"enabled"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+}
+
+type WatchlistHit struct {
+	ID             string     `json:"id"`
+	WatchlistID    string     `json:"watchlistID"`
+	WatchlistName  string     `json:"watchlistName"`
+	IocID          string     `json:"iocID"`
+	IocType        string     `json:"iocType"`
+	IocValue       string     `json:"iocValue"`
+	SourceID       string     `json:"sourceID"`
+	PatternKind    string     `json:"patternKind"`
+	Pattern        string     `json:"pattern"`
+	MatchedField   string     `json:"matchedField"`
+	Acknowledged   bool       `json:"acknowledged"`
+	AcknowledgedBy *string    `json:"acknowledgedBy,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	Ioc            *IoC       `json:"ioc,omitempty"`
+}
+
+type WatchlistHitConnection struct {
+	Items []*WatchlistHit `json:"items"`
+	Total int             `json:"total"`
+}
+
+type WatchlistHitListOptions struct {
+	WatchlistID  *string `json:"watchlistID,omitempty"`
+	Acknowledged *bool   `json:"acknowledged,omitempty"`
+	Offset       *int    `json:"offset,omitempty"`
+	Limit        *int    `json:"limit,omitempty"`
+}
+
 type IoCSortField string
 
 const (
@@ -109,6 +432,7 @@ const (
 	IoCSortFieldStatus      IoCSortField = "STATUS"
 	IoCSortFieldFirstSeenAt IoCSortField = "FIRST_SEEN_AT"
 	IoCSortFieldUpdatedAt   IoCSortField = "UPDATED_AT"
+	IoCSortFieldConfidence  IoCSortField = "CONFIDENCE"
 )
 
 var AllIoCSortField = []IoCSortField{
@@ -118,11 +442,12 @@ var AllIoCSortField = []IoCSortField{
 	IoCSortFieldStatus,
 	IoCSortFieldFirstSeenAt,
 	IoCSortFieldUpdatedAt,
+	IoCSortFieldConfidence,
 }
 
 func (e IoCSortField) IsValid() bool {
 	switch e {
-	case IoCSortFieldType, IoCSortFieldValue, IoCSortFieldSourceID, IoCSortFieldStatus, IoCSortFieldFirstSeenAt, IoCSortFieldUpdatedAt:
+	case IoCSortFieldType, IoCSortFieldValue, IoCSortFieldSourceID, IoCSortFieldStatus, IoCSortFieldFirstSeenAt, IoCSortFieldUpdatedAt, IoCSortFieldConfidence:
 		return true
 	}
 	return false
@@ -163,6 +488,149 @@ func (e IoCSortField) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+type IoCType string
+
+const (
+	IoCTypeIPV4        IoCType = "IPV4"
+	IoCTypeIPV6        IoCType = "IPV6"
+	IoCTypeDomain      IoCType = "DOMAIN"
+	IoCTypeURL         IoCType = "URL"
+	IoCTypeEmail       IoCType = "EMAIL"
+	IoCTypeMacAddr     IoCType = "MAC_ADDR"
+	IoCTypeAsn         IoCType = "ASN"
+	IoCTypeMd5         IoCType = "MD5"
+	IoCTypeSha1        IoCType = "SHA1"
+	IoCTypeSha256      IoCType = "SHA256"
+	IoCTypeFilename    IoCType = "FILENAME"
+	IoCTypeProcess     IoCType = "PROCESS"
+	IoCTypeMutex       IoCType = "MUTEX"
+	IoCTypeRegistryKey IoCType = "REGISTRY_KEY"
+	IoCTypeUserAgent   IoCType = "USER_AGENT"
+	IoCTypeCertHash    IoCType = "CERT_HASH"
+)
+
+var AllIoCType = []IoCType{
+	IoCTypeIPV4,
+	IoCTypeIPV6,
+	IoCTypeDomain,
+	IoCTypeURL,
+	IoCTypeEmail,
+	IoCTypeMacAddr,
+	IoCTypeAsn,
+	IoCTypeMd5,
+	IoCTypeSha1,
+	IoCTypeSha256,
+	IoCTypeFilename,
+	IoCTypeProcess,
+	IoCTypeMutex,
+	IoCTypeRegistryKey,
+	IoCTypeUserAgent,
+	IoCTypeCertHash,
+}
+
+func (e IoCType) IsValid() bool {
+	switch e {
+	case IoCTypeIPV4, IoCTypeIPV6, IoCTypeDomain, IoCTypeURL, IoCTypeEmail, IoCTypeMacAddr, IoCTypeAsn, IoCTypeMd5, IoCTypeSha1, IoCTypeSha256, IoCTypeFilename, IoCTypeProcess, IoCTypeMutex, IoCTypeRegistryKey, IoCTypeUserAgent, IoCTypeCertHash:
+		return true
+	}
+	return false
+}
+
+func (e IoCType) String() string {
+	return string(e)
+}
+
+func (e *IoCType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = IoCType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid IoCType", str)
+	}
+	return nil
+}
+
+func (e IoCType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *IoCType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e IoCType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Permission levels; each role includes the roles before it. Fields without
+// @hasRole are available to every authenticated role. API tokens have no role;
+// their scopes allow a fixed set of fields instead.
+type Role string
+
+const (
+	RoleViewer  Role = "VIEWER"
+	RoleAnalyst Role = "ANALYST"
+	RoleAdmin   Role = "ADMIN"
+)
+
+var AllRole = []Role{
+	RoleViewer,
+	RoleAnalyst,
+	RoleAdmin,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleViewer, RoleAnalyst, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *Role) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e Role) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type SortOrder string
 
 const (
@@ -33,14 +33,18 @@ type History struct {
 	CompletedAt    time.Time     // Completion time
 	ProcessingTime time.Duration // Processing duration
 	URLs           []string      // URLs accessed during fetch
+	TraceID        string        // OpenTelemetry trace of the fetch, empty if not traced
 
 	// Statistics (from FetchStats)
-	ItemsFetched  int // Number of items fetched
-	IoCsExtracted int // Number of IoCs extracted
-	IoCsCreated   int // Number of new IoCs created
-	IoCsUpdated   int // Number of IoCs updated
-	IoCsUnchanged int // Number of unchanged IoCs
-	ErrorCount    int // Number of errors
+	ItemsFetched   int // Number of items fetched
+	IoCsExtracted  int // Number of IoCs extracted
+	IoCsUnverified int // Number of LLM-extracted values dropped because they do not occur in the article
+	IoCsCreated    int // Number of new IoCs created
+	IoCsUpdated    int // Number of IoCs updated
+	IoCsUnchanged  int // Number of unchanged IoCs
+	WatchlistHits  int // Number of new watchlist hits
+	BrandMatches   int // Number of new lookalike domains of protected domains
+	ErrorCount     int // Number of errors
 
 	// Error details
 	Errors []*FetchError // List of errors with context
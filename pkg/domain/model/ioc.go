@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,10 +16,10 @@ import (
 )
 
 const (
-	// EmbeddingDimension is the dimension size for text embeddings
-	// Using 128 dimensions for efficient storage and retrieval
-	// This value is fixed and cannot be changed as Firestore vector index
-	// is created with a specific dimension size
+	// EmbeddingDimension is the default dimension size for text embeddings.
+	// Using 128 dimensions for efficient storage and retrieval. The Firestore
+	// vector index is created with a specific dimension size, so changing the
+	// dimension requires a migration and re-embedding the stored IoCs.
 	EmbeddingDimension = 128
 )
 
@@ -53,10 +54,46 @@ const (
 type IoCStatus string
 
 const (
-	IoCStatusActive   IoCStatus = "active"   // Currently active IoC
-	IoCStatusInactive IoCStatus = "inactive" // No longer active (removed from feed)
+	IoCStatusActive        IoCStatus = "active"         // Currently active IoC
+	IoCStatusInactive      IoCStatus = "inactive"       // No longer active (removed from feed)
+	IoCStatusExpired       IoCStatus = "expired"        // TTL elapsed without the source updating it
+	IoCStatusFalsePositive IoCStatus = "false_positive" // Marked as false positive by an analyst
+	IoCStatusRevoked       IoCStatus = "revoked"        // Revoked by an analyst
+	IoCStatusAllowlisted   IoCStatus = "allowlisted"    // Known benign, must not be blocked
 )
 
+// IsValid returns true if the status is one of the defined statuses
+func (s IoCStatus) IsValid() bool {
+	switch s {
+	case IoCStatusActive, IoCStatusInactive, IoCStatusExpired,
+		IoCStatusFalsePositive, IoCStatusRevoked, IoCStatusAllowlisted:
+		return true
+	default:
+		return false
+	}
+}
+
+// AllIoCTypes lists every defined IoC type
+var AllIoCTypes = []IoCType{
+	IoCTypeIPv4, IoCTypeIPv6, IoCTypeDomain, IoCTypeURL, IoCTypeEmail,
+	IoCTypeMacAddr, IoCTypeASN, IoCTypeMD5, IoCTypeSHA1, IoCTypeSHA256,
+	IoCTypeFilename, IoCTypeProcess, IoCTypeMutex, IoCTypeRegKey,
+	IoCTypeUserAgent, IoCTypeCertHash,
+}
+
+// IsValid returns true if the type is one of the defined IoC types
+func (t IoCType) IsValid() bool {
+	switch t {
+	case IoCTypeIPv4, IoCTypeIPv6, IoCTypeDomain, IoCTypeURL, IoCTypeEmail,
+		IoCTypeMacAddr, IoCTypeASN, IoCTypeMD5, IoCTypeSHA1, IoCTypeSHA256,
+		IoCTypeFilename, IoCTypeProcess, IoCTypeMutex, IoCTypeRegKey,
+		IoCTypeUserAgent, IoCTypeCertHash:
+		return true
+	default:
+		return false
+	}
+}
+
 // IoC represents an Indicator of Compromise
 type IoC struct {
 	ID          string             // Unique identifier: hash(SourceID + Type + normalized Value + ContextKey)
@@ -67,10 +104,161 @@ type IoC struct {
 	Description string             // Human-readable description
 	SourceURL   string             // Original URL where this IoC was found
 	Context     string             // Additional context (article text, surrounding text, etc)
+	ReportID    string             // ID of the report the IoC was extracted from (RSS sources only)
 	Embedding   firestore.Vector32 // Vector embedding for semantic search
 	Status      IoCStatus          // Active or inactive status
 	FirstSeenAt time.Time          // First time this IoC was observed
 	UpdatedAt   time.Time          // Last update time
+	LastSeenAt  time.Time          // Last time the source reported this IoC (zero = not reported by a fetch)
+	ExpiresAt   time.Time          // Time the IoC expires unless seen again (zero = never)
+
+	// Embedding model and dimension of Embedding. Empty for IoCs embedded
+	// before they were recorded, which have n-gram embeddings of EmbeddingDimension.
+	EmbeddingModel     string
+	EmbeddingDimension int
+
+	// Confidence scoring (see ConfidencePolicy)
+	Confidence       int  // Computed confidence score 0-100
+	SourceConfidence int  // Feed-native confidence 0-100 (0 = not provided)
+	ExtractedByLLM   bool // True if the value was extracted from free text by an LLM
+
+	// Analyst curation. These fields are never provided by sources and are
+	// carried over when a source refreshes the IoC.
+	Tags     []string     // Analyst-assigned tags
+	Notes    string       // Free-form analyst notes
+	Override *IoCOverride // Analyst override of source-provided fields (nil = none)
+}
+
+// embeddingDimension returns the recorded embedding dimension, or the default
+// dimension for IoCs embedded before the dimension was recorded
+func (ioc *IoC) embeddingDimension() int {
+	if ioc.EmbeddingDimension == 0 {
+		return EmbeddingDimension
+	}
+	return ioc.EmbeddingDimension
+}
+
+// IoCEmbedding is an embedding of an IoC generated by an embedding model
+type IoCEmbedding struct {
+	Vector []float32
+	Model  string
+}
+
+// SetEmbedding sets the embedding and records its model and dimension
+func (ioc *IoC) SetEmbedding(e *IoCEmbedding) {
+	ioc.Embedding = e.Vector
+	ioc.EmbeddingModel = e.Model
+	ioc.EmbeddingDimension = len(e.Vector)
+}
+
+// EmbeddedWith returns true if the IoC has an embedding of the model and dimension
+func (ioc *IoC) EmbeddedWith(embeddingModel string, dim int) bool {
+	return ioc.EmbeddingModel == embeddingModel &&
+		ioc.EmbeddingDimension == dim &&
+		len(ioc.Embedding) == dim
+}
+
+// PreserveEmbedding carries over the stored embedding to a reported IoC
+// without one. Fetches embed IoCs after saving them, so only new IoCs and
+// IoCs embedded by another model are embedded again.
+func (ioc *IoC) PreserveEmbedding(existing *IoC) {
+	if len(ioc.Embedding) > 0 {
+		return
+	}
+	ioc.Embedding = existing.Embedding
+	ioc.EmbeddingModel = existing.EmbeddingModel
+	ioc.EmbeddingDimension = existing.EmbeddingDimension
+}
+
+// EmbeddingText returns the text of the IoC that is embedded for semantic search
+func (ioc *IoC) EmbeddingText() string {
+	return ioc.Value + " " + ioc.Description
+}
+
+// IoCOverride holds values set by an analyst that take precedence over
+// the values provided by the source on every refresh.
+type IoCOverride struct {
+	Description string    // Overridden description (empty = keep source value)
+	Status      IoCStatus // Overridden status (empty = keep source value)
+	UpdatedAt   time.Time // Time the override was last changed
+
+	// Values last provided by the source, restored when the override is cleared
+	SourceDescription string
+	SourceStatus      IoCStatus
+}
+
+// PreserveCuration copies analyst curation from the stored IoC into an
+// incoming IoC produced by a source, and re-applies any override so that
+// source refreshes never overwrite analyst decisions.
+func (ioc *IoC) PreserveCuration(existing *IoC) {
+	ioc.Tags = existing.Tags
+	ioc.Notes = existing.Notes
+	ioc.Override = nil
+	if existing.Override != nil {
+		// Copy to avoid mutating the override shared with the stored record
+		override := *existing.Override
+		override.SourceDescription = ioc.Description
+		override.SourceStatus = ioc.Status
+		ioc.Override = &override
+	}
+	ioc.ApplyOverride()
+}
+
+// SourceChanged returns true if an IoC reported by a source differs from the
+// stored IoC in a field provided by the source. PreserveCuration must have
+// been applied to the reported IoC.
+func (ioc *IoC) SourceChanged(existing *IoC) bool {
+	return existing.Description != ioc.Description ||
+		existing.Status != ioc.Status ||
+		existing.SourceURL != ioc.SourceURL ||
+		existing.Context != ioc.Context ||
+		existing.ReportID != ioc.ReportID ||
+		existing.SourceConfidence != ioc.SourceConfidence ||
+		!existing.Override.sameSourceValues(ioc.Override)
+}
+
+// Resighted returns true if the source reported the IoC again after the stored
+// IoC was last seen. An unchanged re-sighting still refreshes LastSeenAt and
+// ExpiresAt so that IoCs kept in a source do not expire.
+func (ioc *IoC) Resighted(existing *IoC) bool {
+	return ioc.LastSeenAt.After(existing.LastSeenAt)
+}
+
+func (o *IoCOverride) sameSourceValues(other *IoCOverride) bool {
+	if o == nil || other == nil {
+		return o == other
+	}
+	return o.SourceDescription == other.SourceDescription && o.SourceStatus == other.SourceStatus
+}
+
+// ClearOverride removes the analyst override and restores the values last
+// provided by the source. Overrides recorded without source values keep the
+// current values.
+func (ioc *IoC) ClearOverride() {
+	o := ioc.Override
+	if o == nil {
+		return
+	}
+	if o.Description != "" && o.SourceDescription != "" {
+		ioc.Description = o.SourceDescription
+	}
+	if o.Status != "" && o.SourceStatus != "" {
+		ioc.Status = o.SourceStatus
+	}
+	ioc.Override = nil
+}
+
+// ApplyOverride applies the analyst override, if any, to the effective fields
+func (ioc *IoC) ApplyOverride() {
+	if ioc.Override == nil {
+		return
+	}
+	if ioc.Override.Description != "" {
+		ioc.Description = ioc.Override.Description
+	}
+	if ioc.Override.Status != "" {
+		ioc.Status = ioc.Override.Status
+	}
 }
 
 // IoCContextKey represents a context-aware unique key for deduplication.
@@ -194,23 +382,46 @@ func NormalizeValue(iocType IoCType, value string) string {
 		// Lowercase email address
 		return strings.ToLower(strings.TrimSpace(value))
 
-	case IoCTypeMD5, IoCTypeSHA1, IoCTypeSHA256, IoCTypeCertHash:
+	case IoCTypeMD5, IoCTypeSHA1, IoCTypeSHA256:
 		// Lowercase hash values
 		return strings.ToLower(strings.TrimSpace(value))
 
+	case IoCTypeCertHash:
+		// Lowercase and remove separators of fingerprints like "AB:CD:..."
+		hash := strings.ToLower(strings.TrimSpace(value))
+		hash = strings.ReplaceAll(hash, ":", "")
+		return strings.ReplaceAll(hash, " ", "")
+
 	case IoCTypeMacAddr:
 		// Normalize MAC address format (remove separators, lowercase)
 		mac := strings.ToLower(strings.TrimSpace(value))
 		mac = strings.ReplaceAll(mac, ":", "")
 		mac = strings.ReplaceAll(mac, "-", "")
+		mac = strings.ReplaceAll(mac, ".", "")
 		return mac
 
 	case IoCTypeASN:
-		// Remove "AS" prefix if present, normalize to number
-		asn := strings.TrimSpace(value)
-		asn = strings.TrimPrefix(strings.ToUpper(asn), "AS")
+		// Remove "AS"/"ASN" prefix and convert asdot notation ("1.10") to a number
+		asn := strings.ToUpper(strings.TrimSpace(value))
+		asn = strings.TrimPrefix(asn, "ASN")
+		asn = strings.TrimPrefix(asn, "AS")
+		asn = strings.TrimSpace(asn)
+		if high, low, ok := strings.Cut(asn, "."); ok {
+			h, errH := strconv.ParseUint(high, 10, 16)
+			l, errL := strconv.ParseUint(low, 10, 16)
+			if errH == nil && errL == nil {
+				return strconv.FormatUint(h<<16|l, 10)
+			}
+		}
 		return asn
 
+	case IoCTypeRegKey:
+		return normalizeRegistryKey(value)
+
+	case IoCTypeUserAgent:
+		// Collapse runs of whitespace
+		return strings.Join(strings.Fields(value), " ")
+
 	default:
 		// For other types, just trim whitespace
 		return strings.TrimSpace(value)
@@ -225,18 +436,24 @@ func ValidateIoC(ioc *IoC) error {
 	if ioc.Type == "" {
 		return goerr.Wrap(ErrInvalidIoCType, "type is required", goerr.V("type", ioc.Type))
 	}
+	if !ioc.Type.IsValid() {
+		return goerr.Wrap(ErrInvalidIoCType, "unknown type", goerr.V("type", ioc.Type))
+	}
 	if ioc.Value == "" {
 		return goerr.Wrap(ErrInvalidIoCValue, "value is required", goerr.V("field", "value"))
 	}
-	if ioc.Status != IoCStatusActive && ioc.Status != IoCStatusInactive {
+	if !ioc.Status.IsValid() {
 		return goerr.Wrap(ErrInvalidIoCValue, "invalid status", goerr.V("field", "status"), goerr.V("value", ioc.Status))
 	}
-	if len(ioc.Embedding) > 0 && len(ioc.Embedding) != EmbeddingDimension {
+	if expected := ioc.embeddingDimension(); len(ioc.Embedding) > 0 && len(ioc.Embedding) != expected {
 		return goerr.Wrap(ErrInvalidIoCValue, "invalid embedding dimension",
 			goerr.V("field", "embedding"),
-			goerr.V("expected_dimension", EmbeddingDimension),
+			goerr.V("expected_dimension", expected),
 			goerr.V("actual_dimension", len(ioc.Embedding)))
 	}
+	if ioc.Override != nil && ioc.Override.Status != "" && !ioc.Override.Status.IsValid() {
+		return goerr.Wrap(ErrInvalidIoCValue, "invalid override status", goerr.V("field", "override.status"), goerr.V("value", ioc.Override.Status))
+	}
 	return nil
 }
 
@@ -246,9 +463,17 @@ var (
 	sha256Regex = regexp.MustCompile(`^[a-fA-F0-9]{64}$`)
 	domainRegex = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9\-]{0,61}[a-zA-Z0-9])?\.)+[a-zA-Z]{2,}$`)
 	emailRegex  = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+
+	// Colon separated MD5, SHA-1 or SHA-256 fingerprint as shown by certificate tools
+	fingerprintRegex = regexp.MustCompile(`^[a-fA-F0-9]{2}(:[a-fA-F0-9]{2}){15}((:[a-fA-F0-9]{2}){4}((:[a-fA-F0-9]{2}){12})?)?$`)
+	macSepRegex      = regexp.MustCompile(`^[a-fA-F0-9]{2}(:[a-fA-F0-9]{2}){5}$|^[a-fA-F0-9]{2}(-[a-fA-F0-9]{2}){5}$|^[a-fA-F0-9]{4}\.[a-fA-F0-9]{4}\.[a-fA-F0-9]{4}$`)
+	asnPrefixRegex   = regexp.MustCompile(`^(?i)ASN?\s?[0-9]+(\.[0-9]+)?$`)
+	filenameRegex    = regexp.MustCompile(`^[^\\/:*?"<>|\s]+\.[A-Za-z0-9]{1,8}$`)
 )
 
-// DetectIoCType attempts to detect the type of an IoC value
+// DetectIoCType attempts to detect the type of an IoC value. Process names,
+// command lines and mutexes cannot be told apart from arbitrary text and are
+// never detected. An empty IoCType is returned if the type cannot be determined.
 func DetectIoCType(value string) IoCType {
 	value = strings.TrimSpace(value)
 
@@ -262,6 +487,14 @@ func DetectIoCType(value string) IoCType {
 	if md5Regex.MatchString(value) {
 		return IoCTypeMD5
 	}
+	if fingerprintRegex.MatchString(value) {
+		return IoCTypeCertHash
+	}
+
+	// Check MAC addresses before IPv6, which also uses colons
+	if macSepRegex.MatchString(value) {
+		return IoCTypeMacAddr
+	}
 
 	// Check IP addresses
 	if ip := net.ParseIP(value); ip != nil {
@@ -271,6 +504,17 @@ func DetectIoCType(value string) IoCType {
 		return IoCTypeIPv6
 	}
 
+	if asnPrefixRegex.MatchString(value) {
+		return IoCTypeASN
+	}
+
+	// Check registry keys before URLs, "HKLM:\..." would parse as a URL
+	if hive, _, ok := strings.Cut(strings.ReplaceAll(value, "/", `\`), `\`); ok {
+		if _, ok := registryHives[strings.ToUpper(strings.TrimSuffix(hive, ":"))]; ok {
+			return IoCTypeRegKey
+		}
+	}
+
 	// Check URL
 	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.Host != "" {
 		return IoCTypeURL
@@ -286,6 +530,13 @@ func DetectIoCType(value string) IoCType {
 		return IoCTypeDomain
 	}
 
-	// Default: treat as generic value (could be filename, process, etc.)
-	return IoCTypeFilename
+	if filenameRegex.MatchString(value) {
+		return IoCTypeFilename
+	}
+
+	if isPlausibleUserAgent(value) && productTokenRegex.MatchString(value) {
+		return IoCTypeUserAgent
+	}
+
+	return ""
 }
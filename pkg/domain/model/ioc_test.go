@@ -0,0 +1,86 @@
+package model_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func TestDetectIoCType(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected model.IoCType
+	}{
+		{"198.51.100.42", model.IoCTypeIPv4},
+		{"2001:db8::1", model.IoCTypeIPv6},
+		{"evil-domain.ru", model.IoCTypeDomain},
+		{"https://evil-domain.ru/payload", model.IoCTypeURL},
+		{"admin@evil-domain.ru", model.IoCTypeEmail},
+		{"00:50:56:C0:00:08", model.IoCTypeMacAddr},
+		{"0050.56c0.0008", model.IoCTypeMacAddr},
+		{"AS209588", model.IoCTypeASN},
+		{"5d41402abc4b2a76b9719d911017c592", model.IoCTypeMD5},
+		{"aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d", model.IoCTypeSHA1},
+		{"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", model.IoCTypeSHA256},
+		{"3A:7F:0C:9B:2E:41:D8:66:05:C1:9E:AB:73:20:F4:58:BD:0E:11:C7", model.IoCTypeCertHash},
+		{`HKLM\SOFTWARE\Classes`, model.IoCTypeRegKey},
+		{`HKCU:\Software\Evil`, model.IoCTypeRegKey},
+		{"invoice_2024.pdf.exe", model.IoCTypeFilename},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) SvcHelper/2.1", model.IoCTypeUserAgent},
+		// Process names and mutexes cannot be told apart from free text
+		{`Global\SvcHelperMtx_8841`, ""},
+		{"hello", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			gt.Equal(t, model.DetectIoCType(tc.value), tc.expected)
+		})
+	}
+}
+
+func TestNormalizeValue(t *testing.T) {
+	testCases := []struct {
+		iocType  model.IoCType
+		value    string
+		expected string
+	}{
+		{model.IoCTypeIPv6, "2001:0DB8::0001", "2001:db8::1"},
+		{model.IoCTypeMD5, "5D41402ABC4B2A76B9719D911017C592", "5d41402abc4b2a76b9719d911017c592"},
+		{model.IoCTypeCertHash, "3A:7F:0C:9B 2E:41", "3a7f0c9b2e41"},
+		{model.IoCTypeMacAddr, "0050.56C0.0008", "005056c00008"},
+		{model.IoCTypeASN, "asn 13335", "13335"},
+		{model.IoCTypeASN, "AS1.10", "65546"},
+		{model.IoCTypeRegKey, `hklm//SOFTWARE\\Classes\`, `HKEY_LOCAL_MACHINE\SOFTWARE\Classes`},
+		{model.IoCTypeUserAgent, " curl/8.4.0   (x86_64) ", "curl/8.4.0 (x86_64)"},
+		{model.IoCTypeMutex, ` Global\SvcHelperMtx `, `Global\SvcHelperMtx`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.iocType)+" "+tc.value, func(t *testing.T) {
+			gt.Equal(t, model.NormalizeValue(tc.iocType, tc.value), tc.expected)
+		})
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	gt.NoError(t, model.ValidateValue(model.IoCTypeRegKey, `HKEY_CURRENT_USER\Software\Evil`))
+	gt.NoError(t, model.ValidateValue(model.IoCTypeASN, "4294967295"))
+
+	for _, tc := range []struct {
+		iocType model.IoCType
+		value   string
+	}{
+		{model.IoCTypeRegKey, `HKCU\Software\Evil`}, // hive is not normalized
+		{model.IoCTypeASN, "4294967296"},
+		{model.IoCTypeUserAgent, "Mozilla"},
+		{model.IoCTypeIPv4, ""},
+	} {
+		err := model.ValidateValue(tc.iocType, tc.value)
+		gt.True(t, errors.Is(err, model.ErrInvalidIoCValue)).Describef("%s %q", tc.iocType, tc.value)
+	}
+
+	gt.True(t, errors.Is(model.ValidateValue("cve", "CVE-2025-1234"), model.ErrInvalidIoCType))
+}
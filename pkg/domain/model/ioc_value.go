@@ -0,0 +1,191 @@
+package model
+
+import (
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/m-mizutani/goerr/v2"
+)
+
+const (
+	maxDomainLength    = 253
+	maxFilenameLength  = 1024 // File names may include a path
+	maxProcessLength   = 8191 // Maximum command line length on Windows
+	maxMutexLength     = 260
+	maxRegKeyLength    = 1024
+	maxUserAgentLength = 2048
+	minUserAgentLength = 4
+)
+
+var (
+	macAddrRegex = regexp.MustCompile(`^[0-9a-f]{12}$`)
+	asnRegex     = regexp.MustCompile(`^[0-9]{1,10}$`)
+	// certHashRegex matches normalized MD5, SHA-1 and SHA-256 certificate fingerprints
+	certHashRegex = regexp.MustCompile(`^(?:[0-9a-f]{32}|[0-9a-f]{40}|[0-9a-f]{64})$`)
+	// productTokenRegex matches a product/version token of a User-Agent, e.g. "Mozilla/5.0"
+	productTokenRegex = regexp.MustCompile(`[A-Za-z][A-Za-z0-9._-]*/[A-Za-z0-9._-]+`)
+)
+
+// registryHives maps accepted Windows registry hive prefixes to their canonical name
+var registryHives = map[string]string{
+	"HKEY_LOCAL_MACHINE":    "HKEY_LOCAL_MACHINE",
+	"HKLM":                  "HKEY_LOCAL_MACHINE",
+	"HKEY_CURRENT_USER":     "HKEY_CURRENT_USER",
+	"HKCU":                  "HKEY_CURRENT_USER",
+	"HKEY_CLASSES_ROOT":     "HKEY_CLASSES_ROOT",
+	"HKCR":                  "HKEY_CLASSES_ROOT",
+	"HKEY_USERS":            "HKEY_USERS",
+	"HKU":                   "HKEY_USERS",
+	"HKEY_CURRENT_CONFIG":   "HKEY_CURRENT_CONFIG",
+	"HKCC":                  "HKEY_CURRENT_CONFIG",
+	"HKEY_PERFORMANCE_DATA": "HKEY_PERFORMANCE_DATA",
+}
+
+// ValidateValue checks that a normalized value is well-formed for its type.
+// Values from structured feeds may carry additional information (e.g. ip:port
+// entries stored as ipv4) and are not passed through this check; it is meant for
+// values extracted from free text or entered by analysts.
+func ValidateValue(iocType IoCType, value string) error {
+	invalid := func(reason string) error {
+		return goerr.Wrap(ErrInvalidIoCValue, reason, goerr.V("type", iocType), goerr.V("value", value))
+	}
+
+	if value == "" {
+		return invalid("value is required")
+	}
+
+	switch iocType {
+	case IoCTypeIPv4:
+		if addr, err := netip.ParseAddr(value); err != nil || !addr.Is4() {
+			return invalid("not an IPv4 address")
+		}
+
+	case IoCTypeIPv6:
+		if addr, err := netip.ParseAddr(value); err != nil || !addr.Is6() {
+			return invalid("not an IPv6 address")
+		}
+
+	case IoCTypeDomain:
+		if len(value) > maxDomainLength || !domainRegex.MatchString(value) {
+			return invalid("not a domain name")
+		}
+
+	case IoCTypeURL:
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return invalid("not an absolute URL")
+		}
+
+	case IoCTypeEmail:
+		if !emailRegex.MatchString(value) {
+			return invalid("not an email address")
+		}
+
+	case IoCTypeMacAddr:
+		if !macAddrRegex.MatchString(value) {
+			return invalid("not a MAC address")
+		}
+
+	case IoCTypeASN:
+		n, err := strconv.ParseUint(value, 10, 32)
+		if !asnRegex.MatchString(value) || err != nil || n == 0 {
+			return invalid("not an AS number")
+		}
+
+	case IoCTypeMD5:
+		if !md5Regex.MatchString(value) {
+			return invalid("not an MD5 hash")
+		}
+
+	case IoCTypeSHA1:
+		if !sha1Regex.MatchString(value) {
+			return invalid("not a SHA-1 hash")
+		}
+
+	case IoCTypeSHA256:
+		if !sha256Regex.MatchString(value) {
+			return invalid("not a SHA-256 hash")
+		}
+
+	case IoCTypeCertHash:
+		if !certHashRegex.MatchString(value) {
+			return invalid("not a certificate fingerprint")
+		}
+
+	case IoCTypeFilename:
+		if len(value) > maxFilenameLength || hasControlChar(value) ||
+			strings.HasSuffix(value, "/") || strings.HasSuffix(value, `\`) {
+			return invalid("not a file name")
+		}
+
+	case IoCTypeProcess:
+		if len(value) > maxProcessLength || strings.ContainsFunc(value, func(r rune) bool {
+			return unicode.IsControl(r) && r != '\t'
+		}) {
+			return invalid("not a process name or command line")
+		}
+
+	case IoCTypeMutex:
+		if len(value) > maxMutexLength || hasControlChar(value) {
+			return invalid("not a mutex name")
+		}
+
+	case IoCTypeRegKey:
+		hive, _, _ := strings.Cut(value, `\`)
+		if len(value) > maxRegKeyLength || hasControlChar(value) || registryHives[hive] != hive {
+			return invalid("registry key must start with a registry hive")
+		}
+
+	case IoCTypeUserAgent:
+		if !isPlausibleUserAgent(value) {
+			return invalid("not a plausible User-Agent")
+		}
+
+	default:
+		return goerr.Wrap(ErrInvalidIoCType, "unknown type", goerr.V("type", iocType))
+	}
+
+	return nil
+}
+
+// normalizeRegistryKey canonicalizes the hive of a registry key ("HKLM" and
+// "HKLM:" become "HKEY_LOCAL_MACHINE"), converts forward slashes to backslashes and removes
+// duplicate and trailing separators. Key names keep their case.
+func normalizeRegistryKey(value string) string {
+	key := strings.ReplaceAll(strings.TrimSpace(value), "/", `\`)
+	parts := strings.FieldsFunc(key, func(r rune) bool { return r == '\\' })
+	if len(parts) == 0 {
+		return ""
+	}
+	// PowerShell drive notation ("HKLM:") is accepted
+	if hive, ok := registryHives[strings.ToUpper(strings.TrimSuffix(parts[0], ":"))]; ok {
+		parts[0] = hive
+	}
+	return strings.Join(parts, `\`)
+}
+
+// isPlausibleUserAgent returns true for printable strings of reasonable length
+// that contain a product token ("Mozilla/5.0") or several words
+// ("Microsoft Internet Explorer"). Single words and URLs are rejected.
+func isPlausibleUserAgent(value string) bool {
+	if len(value) < minUserAgentLength || len(value) > maxUserAgentLength {
+		return false
+	}
+	for _, r := range value {
+		if r < 0x20 || r > 0x7e {
+			return false
+		}
+	}
+	if strings.Contains(value, "://") || !strings.ContainsFunc(value, unicode.IsLetter) {
+		return false
+	}
+	return productTokenRegex.MatchString(value) || strings.Contains(value, " ")
+}
+
+func hasControlChar(value string) bool {
+	return strings.ContainsFunc(value, unicode.IsControl)
+}
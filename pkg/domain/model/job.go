@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobStatus is the state of an asynchronous job
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"    // Waiting for a free slot
+	JobStatusRunning   JobStatus = "running"   // Being processed
+	JobStatusSucceeded JobStatus = "succeeded" // Finished, see HistoryID
+	JobStatusFailed    JobStatus = "failed"    // Finished with an error
+	JobStatusCanceled  JobStatus = "canceled"  // Canceled before it finished
+)
+
+// Done reports whether the job has finished
+func (s JobStatus) Done() bool {
+	return s == JobStatusSucceeded || s == JobStatusFailed || s == JobStatusCanceled
+}
+
+// JobPhase is the step a running fetch job is in
+type JobPhase string
+
+const (
+	JobPhaseFetch   JobPhase = "fetch"   // Downloading the feed
+	JobPhaseExtract JobPhase = "extract" // Parsing items and extracting IoCs
+	JobPhaseSave    JobPhase = "save"    // Saving IoCs and running detections
+)
+
+// Job is an asynchronous fetch of a source
+type Job struct {
+	ID       string
+	SourceID string
+	Status   JobStatus
+	Phase    JobPhase // Empty until the job starts
+
+	// Progress of the extract phase
+	ItemsTotal     int // Items to process, known when the phase starts
+	ItemsProcessed int
+	IoCsExtracted  int
+
+	HistoryID  string // History of the fetch, set when the job finishes
+	Error      string
+	CreatedAt  time.Time
+	StartedAt  time.Time // Zero while queued
+	FinishedAt time.Time // Zero until the job finishes
+}
+
+// NewJob creates a queued fetch job of a source
+func NewJob(sourceID string, at time.Time) *Job {
+	return &Job{
+		ID:        uuid.Must(uuid.NewV7()).String(),
+		SourceID:  sourceID,
+		Status:    JobStatusQueued,
+		CreatedAt: at,
+	}
+}
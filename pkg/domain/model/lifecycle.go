@@ -0,0 +1,90 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Day is a convenience unit for TTL definitions
+	Day = 24 * time.Hour
+
+	// ActorSweeper is the actor recorded for transitions made by the expiry sweeper
+	ActorSweeper = "system:sweeper"
+	// ActorAnalyst is the actor recorded for analyst changes when no user is known
+	ActorAnalyst = "analyst"
+	// ActorConfig is the actor recorded for sources imported from the TOML config
+	ActorConfig = "system:config"
+	// ActorFetcher is the actor recorded for fetches not requested by a user,
+	// e.g. scheduled runs of the fetch command
+	ActorFetcher = "system:fetch"
+)
+
+// DefaultIoCTTLs defines how long an IoC stays active after it was last
+// created or updated by its source. Types not listed never expire.
+// Network indicators are re-assigned quickly, while file hashes are permanent.
+var DefaultIoCTTLs = map[IoCType]time.Duration{
+	IoCTypeIPv4:   30 * Day,
+	IoCTypeIPv6:   30 * Day,
+	IoCTypeURL:    30 * Day,
+	IoCTypeDomain: 90 * Day,
+	IoCTypeEmail:  180 * Day,
+}
+
+// TTLPolicy maps IoC types to their time-to-live. A zero duration means the
+// type never expires.
+type TTLPolicy map[IoCType]time.Duration
+
+// NewTTLPolicy creates a policy from DefaultIoCTTLs with the given overrides applied
+func NewTTLPolicy(overrides map[IoCType]time.Duration) TTLPolicy {
+	policy := make(TTLPolicy, len(DefaultIoCTTLs)+len(overrides))
+	for t, d := range DefaultIoCTTLs {
+		policy[t] = d
+	}
+	for t, d := range overrides {
+		policy[t] = d
+	}
+	return policy
+}
+
+// ExpiresAt returns the expiry time of an IoC of the given type observed at
+// from. sourceTTL, if not nil, takes precedence over the per-type TTL.
+// Returns zero time if the IoC never expires.
+func (p TTLPolicy) ExpiresAt(sourceTTL *time.Duration, iocType IoCType, from time.Time) time.Time {
+	ttl := p[iocType]
+	if sourceTTL != nil {
+		ttl = *sourceTTL
+	}
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return from.Add(ttl)
+}
+
+// IoCStatusTransition records a single change of IoC status
+type IoCStatusTransition struct {
+	ID        string    // Unique identifier (UUIDv7)
+	IoCID     string    // IoC identifier
+	From      IoCStatus // Previous status
+	To        IoCStatus // New status
+	Actor     string    // "fetch:<history ID>", "system:sweeper" or user name
+	Reason    string    // Human-readable reason
+	CreatedAt time.Time // Transition time
+}
+
+// NewIoCStatusTransition creates a transition record with a generated ID
+func NewIoCStatusTransition(iocID string, from, to IoCStatus, at time.Time) *IoCStatusTransition {
+	return &IoCStatusTransition{
+		ID:        uuid.Must(uuid.NewV7()).String(),
+		IoCID:     iocID,
+		From:      from,
+		To:        to,
+		CreatedAt: at,
+	}
+}
+
+// FetchActor returns the actor name for changes made by a fetch run
+func FetchActor(historyID string) string {
+	return "fetch:" + historyID
+}
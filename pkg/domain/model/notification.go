@@ -0,0 +1,263 @@
+package model
+
+import (
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// NotificationRuleKind represents the trigger of a notification rule
+type NotificationRuleKind string
+
+const (
+	// NotificationRuleSourceFailure fires when a source fails N times in a row
+	NotificationRuleSourceFailure NotificationRuleKind = "source_failure"
+	// NotificationRuleWatchlist fires when newly created IoCs match a pattern or
+	// produce hits on registered watchlists
+	NotificationRuleWatchlist NotificationRuleKind = "watchlist"
+	// NotificationRuleDigest sends a daily digest of new IoCs per tag
+	NotificationRuleDigest NotificationRuleKind = "digest"
+	// NotificationRuleLookalike fires when newly created IoCs imitate protected domains
+	NotificationRuleLookalike NotificationRuleKind = "lookalike"
+)
+
+// MaxNotificationIoCs is the maximum number of IoCs included in a single notification
+const MaxNotificationIoCs = 100
+
+// DefaultFailureThreshold is the default number of consecutive failures for source_failure rules
+const DefaultFailureThreshold = 3
+
+// IsValid returns true if the kind is one of the defined rule kinds
+func (k NotificationRuleKind) IsValid() bool {
+	switch k {
+	case NotificationRuleSourceFailure, NotificationRuleWatchlist, NotificationRuleDigest, NotificationRuleLookalike:
+		return true
+	default:
+		return false
+	}
+}
+
+// NotificationRule decides when and where a notification is sent
+type NotificationRule struct {
+	Name     string
+	Kind     NotificationRuleKind
+	Channels []string // Channel names to deliver to
+
+	// source_failure: number of consecutive failed fetches (default DefaultFailureThreshold)
+	Threshold int
+	// source_failure: limit to these source IDs (empty = all sources)
+	Sources []string
+
+	// watchlist: case-insensitive glob patterns matched against the IoC value
+	Patterns []string
+	// watchlist: names of registered watchlists whose hits are notified ("*" = any)
+	Watchlists []string
+	// watchlist: limit to these IoC types (empty = all types)
+	Types []IoCType
+	// watchlist: minimum confidence score of matching IoCs
+	MinConfidence int
+
+	// lookalike: limit to these protected domains (empty = all)
+	Brands []string
+	// lookalike: limit to these techniques (empty = all)
+	Techniques []LookalikeTechnique
+	// lookalike: minimum similarity score of matches (0.0-1.0)
+	MinScore float64
+
+	// digest: one digest is sent per tag
+	Tags []string
+	// digest: hour of the day (UTC) at which the previous 24 hours are summarized
+	Hour int
+
+	// Optional text/template overrides, executed with a NotificationEvent
+	Subject string
+	Body    string
+}
+
+// Validate validates the rule and fills in defaults
+func (r *NotificationRule) Validate() error {
+	if r.Name == "" {
+		return goerr.New("notification rule name is required")
+	}
+	if !r.Kind.IsValid() {
+		return goerr.New("unknown notification rule kind", goerr.V("kind", r.Kind))
+	}
+	if len(r.Channels) == 0 {
+		return goerr.New("notification rule has no channels")
+	}
+
+	switch r.Kind {
+	case NotificationRuleSourceFailure:
+		if r.Threshold < 0 {
+			return goerr.New("threshold must be >= 0", goerr.V("threshold", r.Threshold))
+		}
+		if r.Threshold == 0 {
+			r.Threshold = DefaultFailureThreshold
+		}
+	case NotificationRuleWatchlist:
+		if len(r.Patterns) == 0 && len(r.Watchlists) == 0 {
+			return goerr.New("watchlist rule requires patterns or watchlists")
+		}
+		for _, p := range r.Patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return goerr.Wrap(err, "invalid watchlist pattern", goerr.V("pattern", p))
+			}
+		}
+		for _, t := range r.Types {
+			if !t.IsValid() {
+				return goerr.New("unknown IoC type in watchlist rule", goerr.V("type", t))
+			}
+		}
+		if r.MinConfidence < 0 || r.MinConfidence > 100 {
+			return goerr.New("min_confidence must be between 0 and 100",
+				goerr.V("min_confidence", r.MinConfidence))
+		}
+	case NotificationRuleLookalike:
+		for _, t := range r.Techniques {
+			if !t.IsValid() {
+				return goerr.New("unknown lookalike technique in rule", goerr.V("technique", t))
+			}
+		}
+		if r.MinScore < 0 || r.MinScore > 1 {
+			return goerr.New("min_score must be between 0 and 1", goerr.V("min_score", r.MinScore))
+		}
+	case NotificationRuleDigest:
+		if len(r.Tags) == 0 {
+			return goerr.New("digest rule requires tags")
+		}
+		if r.Hour < 0 || r.Hour > 23 {
+			return goerr.New("hour must be between 0 and 23", goerr.V("hour", r.Hour))
+		}
+	}
+
+	for name, text := range map[string]string{"subject": r.Subject, "body": r.Body} {
+		if text == "" {
+			continue
+		}
+		if _, err := template.New(name).Parse(text); err != nil {
+			return goerr.Wrap(err, "invalid notification template", goerr.V("field", name))
+		}
+	}
+
+	return nil
+}
+
+// MatchSource returns true if a source_failure rule applies to the source
+func (r *NotificationRule) MatchSource(sourceID string) bool {
+	if len(r.Sources) == 0 {
+		return true
+	}
+	for _, s := range r.Sources {
+		if s == sourceID {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchIoC returns true if the IoC value matches the patterns of a watchlist rule
+func (r *NotificationRule) MatchIoC(ioc *IoC) bool {
+	if !r.matchFilters(ioc) {
+		return false
+	}
+
+	value := strings.ToLower(ioc.Value)
+	for _, p := range r.Patterns {
+		if ok, _ := path.Match(strings.ToLower(p), value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchWatchlistHit returns true if a hit on a registered watchlist is notified by
+// a watchlist rule. ioc is the IoC of the hit.
+func (r *NotificationRule) MatchWatchlistHit(hit *WatchlistHit, ioc *IoC) bool {
+	if !r.matchFilters(ioc) {
+		return false
+	}
+	for _, name := range r.Watchlists {
+		if name == "*" || name == hit.WatchlistName {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFilters applies the type and confidence filters of a watchlist rule
+func (r *NotificationRule) matchFilters(ioc *IoC) bool {
+	if ioc.Confidence < r.MinConfidence {
+		return false
+	}
+	if len(r.Types) == 0 {
+		return true
+	}
+	for _, t := range r.Types {
+		if t == ioc.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchBrandMatch returns true if a lookalike rule applies to the match
+func (r *NotificationRule) MatchBrandMatch(m *BrandMatch) bool {
+	if m.Score < r.MinScore {
+		return false
+	}
+	if len(r.Brands) > 0 && !slices.Contains(r.Brands, m.ProtectedDomain) {
+		return false
+	}
+	if len(r.Techniques) > 0 && !slices.Contains(r.Techniques, m.Technique) {
+		return false
+	}
+	return true
+}
+
+// DigestWindow returns the most recent completed 24-hour digest window at now
+func (r *NotificationRule) DigestWindow(now time.Time) (since, until time.Time) {
+	now = now.UTC()
+	until = time.Date(now.Year(), now.Month(), now.Day(), r.Hour, 0, 0, 0, time.UTC)
+	if now.Before(until) {
+		until = until.Add(-Day)
+	}
+	return until.Add(-Day), until
+}
+
+// NotificationEvent is the data passed to notification templates
+type NotificationEvent struct {
+	Rule string
+	Kind NotificationRuleKind
+
+	// source_failure
+	SourceID            string
+	History             *History
+	ConsecutiveFailures int
+
+	// watchlist and digest; at most MaxNotificationIoCs entries, Total is the full count
+	IoCs  []*IoC
+	Total int
+
+	// lookalike; at most MaxNotificationIoCs entries, Total is the full count
+	BrandMatches []*BrandMatch
+
+	// digest
+	Tag   string
+	Since time.Time
+	Until time.Time
+}
+
+// Notification is a rendered message ready to be delivered to a channel
+type Notification struct {
+	Key       string // Deduplication key
+	Rule      string
+	Kind      NotificationRuleKind
+	Subject   string
+	Body      string
+	Event     *NotificationEvent
+	CreatedAt time.Time
+}
@@ -0,0 +1,89 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func TestNotificationRule_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		rule    model.NotificationRule
+		wantErr bool
+	}{
+		{
+			name: "valid watchlist",
+			rule: model.NotificationRule{Name: "w", Kind: model.NotificationRuleWatchlist, Channels: []string{"c"}, Patterns: []string{"*.evil.example"}},
+		},
+		{
+			name:    "unknown kind",
+			rule:    model.NotificationRule{Name: "x", Kind: "unknown", Channels: []string{"c"}},
+			wantErr: true,
+		},
+		{
+			name:    "no channels",
+			rule:    model.NotificationRule{Name: "x", Kind: model.NotificationRuleSourceFailure},
+			wantErr: true,
+		},
+		{
+			name:    "malformed pattern",
+			rule:    model.NotificationRule{Name: "w", Kind: model.NotificationRuleWatchlist, Channels: []string{"c"}, Patterns: []string{"[a-"}},
+			wantErr: true,
+		},
+		{
+			name:    "digest without tags",
+			rule:    model.NotificationRule{Name: "d", Kind: model.NotificationRuleDigest, Channels: []string{"c"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid template",
+			rule:    model.NotificationRule{Name: "f", Kind: model.NotificationRuleSourceFailure, Channels: []string{"c"}, Body: "{{.SourceID"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.Validate()
+			if tc.wantErr {
+				gt.Error(t, err)
+			} else {
+				gt.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("default threshold", func(t *testing.T) {
+		rule := model.NotificationRule{Name: "f", Kind: model.NotificationRuleSourceFailure, Channels: []string{"c"}}
+		gt.NoError(t, rule.Validate())
+		gt.Equal(t, rule.Threshold, model.DefaultFailureThreshold)
+	})
+}
+
+func TestNotificationRule_MatchIoC(t *testing.T) {
+	rule := model.NotificationRule{
+		Patterns:      []string{"*.Evil.example"},
+		Types:         []model.IoCType{model.IoCTypeDomain},
+		MinConfidence: 50,
+	}
+
+	gt.True(t, rule.MatchIoC(&model.IoC{Type: model.IoCTypeDomain, Value: "login.evil.example", Confidence: 60}))
+	gt.False(t, rule.MatchIoC(&model.IoC{Type: model.IoCTypeDomain, Value: "login.evil.example", Confidence: 40}))
+	gt.False(t, rule.MatchIoC(&model.IoC{Type: model.IoCTypeURL, Value: "login.evil.example", Confidence: 60}))
+	gt.False(t, rule.MatchIoC(&model.IoC{Type: model.IoCTypeDomain, Value: "good.example", Confidence: 60}))
+}
+
+func TestNotificationRule_DigestWindow(t *testing.T) {
+	rule := model.NotificationRule{Hour: 9}
+
+	since, until := rule.DigestWindow(time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC))
+	gt.Equal(t, until, time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	gt.Equal(t, since, time.Date(2025, 3, 9, 9, 0, 0, 0, time.UTC))
+
+	since, until = rule.DigestWindow(time.Date(2025, 3, 10, 8, 59, 0, 0, time.UTC))
+	gt.Equal(t, until, time.Date(2025, 3, 9, 9, 0, 0, 0, time.UTC))
+	gt.Equal(t, since, time.Date(2025, 3, 8, 9, 0, 0, 0, time.UTC))
+}
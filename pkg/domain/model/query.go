@@ -1,11 +1,53 @@
 package model
 
+import (
+	"slices"
+	"time"
+)
+
 // IoCListOptions represents query options for listing IoCs
 type IoCListOptions struct {
 	Offset    int
 	Limit     int
 	SortField IoCSortField
 	SortOrder SortOrder
+
+	// MinConfidence filters out IoCs with a lower confidence score (0 = no filter)
+	MinConfidence int
+
+	// Types restricts the result to the given IoC types (empty = all types)
+	Types []IoCType
+}
+
+// IoCTagFilter selects the IoCs matching a tag: IoCs tagged by analysts with
+// Tag and IoCs of the sources in SourceIDs, which carry the tag in their
+// configuration
+type IoCTagFilter struct {
+	Tag       string
+	SourceIDs []string
+
+	// UpdatedSince filters out IoCs updated before it (zero = no filter)
+	UpdatedSince time.Time
+
+	// Status restricts the result to IoCs with the status (empty = all)
+	Status IoCStatus
+
+	// MinConfidence filters out IoCs with a lower confidence score (0 = no filter)
+	MinConfidence int
+}
+
+// Match returns true if the IoC matches the filter
+func (f *IoCTagFilter) Match(ioc *IoC) bool {
+	if !slices.Contains(ioc.Tags, f.Tag) && !slices.Contains(f.SourceIDs, ioc.SourceID) {
+		return false
+	}
+	if f.Status != "" && ioc.Status != f.Status {
+		return false
+	}
+	if ioc.Confidence < f.MinConfidence {
+		return false
+	}
+	return !ioc.UpdatedAt.Before(f.UpdatedSince)
 }
 
 // IoCSortField represents the field to sort IoCs by
@@ -18,6 +60,7 @@ const (
 	IoCSortByStatus      IoCSortField = "status"
 	IoCSortByFirstSeenAt IoCSortField = "first_seen_at"
 	IoCSortByUpdatedAt   IoCSortField = "updated_at"
+	IoCSortByConfidence  IoCSortField = "confidence"
 )
 
 // SortOrder represents the sort order
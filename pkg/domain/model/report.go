@@ -0,0 +1,114 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxReportContentLength is the maximum length in bytes of the article content
+// stored in a report. Longer content is truncated to keep documents small.
+const MaxReportContentLength = 256 * 1024
+
+var (
+	cveRegex             = regexp.MustCompile(`^CVE-[0-9]{4}-[0-9]{4,}$`)
+	attackTechniqueRegex = regexp.MustCompile(`^T[0-9]{4}(?:\.[0-9]{3})?$`)
+)
+
+// Report represents a threat report (an article of an RSS source) and its
+// analysis. IoCs extracted from the article reference the report by ID.
+type Report struct {
+	ID          string // Derived from source ID and article GUID
+	SourceID    string
+	Title       string
+	URL         string
+	GUID        string
+	PublishedAt time.Time
+	Content     string // Article text, truncated to MaxReportContentLength
+
+	// Analysis of the article. Summary is empty if the report was not analyzed by an LLM.
+	Summary          string
+	ThreatActors     []string
+	MalwareFamilies  []string
+	Sectors          []string // Targeted industry sectors
+	CVEs             []string // CVE IDs, e.g. CVE-2024-3400
+	AttackTechniques []string // MITRE ATT&CK technique IDs, e.g. T1059.001
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// GenerateReportID returns the ID of the report of an article.
+// The article GUID is used as is, falling back to the link when it is empty.
+func GenerateReportID(sourceID, guid string) string {
+	sum := sha256.Sum256([]byte(sourceID + "\x00" + guid))
+	return hex.EncodeToString(sum[:16])
+}
+
+// TruncateReportContent truncates content to MaxReportContentLength bytes
+// without splitting a UTF-8 character
+func TruncateReportContent(content string) string {
+	if len(content) <= MaxReportContentLength {
+		return content
+	}
+	end := MaxReportContentLength
+	for end > 0 && !utf8.RuneStart(content[end]) {
+		end--
+	}
+	return content[:end]
+}
+
+// NormalizeCVEs uppercases CVE IDs and drops malformed and duplicate entries
+func NormalizeCVEs(ids []string) []string {
+	return normalizeIDs(ids, cveRegex)
+}
+
+// NormalizeAttackTechniques uppercases ATT&CK technique IDs and drops malformed
+// and duplicate entries
+func NormalizeAttackTechniques(ids []string) []string {
+	return normalizeIDs(ids, attackTechniqueRegex)
+}
+
+func normalizeIDs(ids []string, pattern *regexp.Regexp) []string {
+	result := []string{}
+	for _, id := range ids {
+		id = strings.ToUpper(strings.TrimSpace(id))
+		if pattern.MatchString(id) && !slices.Contains(result, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// NormalizeNames trims names and drops empty and duplicate (case-insensitive) entries
+func NormalizeNames(names []string) []string {
+	result := []string{}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.Join(strings.Fields(name), " ")
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, name)
+	}
+	return result
+}
+
+// ReportListOptions represents filter and pagination options for listing reports
+type ReportListOptions struct {
+	SourceID string // Empty = all sources
+	Offset   int
+	Limit    int // 0 = no limit
+}
+
+// ReportConnection represents a paginated list of reports
+type ReportConnection struct {
+	Items []*Report
+	Total int
+}
@@ -1,34 +1,134 @@
 package model
 
-import "time"
+import (
+	"slices"
+	"sort"
+	"time"
+)
 
 // SourceType represents the type of source
 type SourceType string
 
 const (
-	SourceTypeRSS  SourceType = "rss"
-	SourceTypeFeed SourceType = "feed"
+	SourceTypeRSS    SourceType = "rss"
+	SourceTypeFeed   SourceType = "feed"
+	SourceTypeManual SourceType = "manual" // IoCs registered by analysts
 )
 
+// ManualSourceID is the source ID assigned to analyst-managed IoCs
+const ManualSourceID = "manual"
+
 // SourcesConfig represents the entire sources configuration
 type SourcesConfig struct {
 	Sources map[string]Source // key = source ID (from TOML section name)
 }
 
+// SourceIDsWithTag returns the sorted IDs of the sources with a tag
+func SourceIDsWithTag(sources map[string]Source, tag string) []string {
+	var ids []string
+	for id, source := range sources {
+		if slices.Contains(source.Tags, tag) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
 // Source represents a single source configuration
 type Source struct {
-	Type        SourceType  `toml:"type"`
-	URL         string      `toml:"url"`
-	Description string      `toml:"description"` // User-defined description from config
-	Tags        []string    `toml:"tags"`
-	Enabled     bool        `toml:"enabled"`
-	RSSConfig   *RSSConfig  `toml:"rss_config,omitempty"`  // Only for type="rss"
-	FeedConfig  *FeedConfig `toml:"feed_config,omitempty"` // Only for type="feed"
+	ID          string         `toml:"-"` // Source ID, the TOML section name
+	Type        SourceType     `toml:"type"`
+	URL         string         `toml:"url"`
+	Description string         `toml:"description"` // User-defined description from config
+	Tags        []string       `toml:"tags"`
+	Enabled     bool           `toml:"enabled"`
+	TTL         *time.Duration `toml:"-"`                     // IoC TTL for this source (nil = per-type default, 0 = never expire)
+	RSSConfig   *RSSConfig     `toml:"rss_config,omitempty"`  // Only for type="rss"
+	FeedConfig  *FeedConfig    `toml:"feed_config,omitempty"` // Only for type="feed"
+	// Reliability of the source (0-1) for confidence scoring, nil = policy default
+	Reliability *float64 `toml:"-"`
+
+	// Set when the source is stored in the repository
+	Origin    SourceOrigin `toml:"-"`
+	CreatedAt time.Time    `toml:"-"`
+	UpdatedAt time.Time    `toml:"-"`
 }
 
+// SourceOrigin tells where the stored settings of a source were last written from
+type SourceOrigin string
+
+const (
+	// SourceOriginConfig marks sources imported from the config file. The file
+	// wins when it is imported again.
+	SourceOriginConfig SourceOrigin = "config"
+	// SourceOriginAPI marks sources created or edited through the API, which
+	// are kept when the config file is imported. Sources stored before the
+	// origin was recorded have no origin and are kept as well.
+	SourceOriginAPI SourceOrigin = "api"
+)
+
 // RSSConfig contains RSS-specific configuration
 type RSSConfig struct {
-	MaxArticles int `toml:"max_articles"` // Maximum articles to fetch per run
+	MaxArticles int            `toml:"max_articles"` // Maximum articles to fetch per run
+	Extraction  ExtractionMode `toml:"extraction"`   // How IoCs are extracted from articles (empty = LLM)
+	// Prompt customizes LLM extraction for the source (nil = built-in prompt)
+	Prompt *ExtractionPrompt `toml:"-"`
+}
+
+// ExtractionMode selects how IoCs are extracted from article text
+type ExtractionMode string
+
+const (
+	// ExtractionModeLLM extracts IoCs with the LLM only (default)
+	ExtractionModeLLM ExtractionMode = "llm"
+	// ExtractionModeRegex extracts IoCs deterministically from the refanged text without the LLM
+	ExtractionModeRegex ExtractionMode = "regex"
+	// ExtractionModePrefilter extracts candidates deterministically and lets the LLM
+	// classify them as malicious or benign
+	ExtractionModePrefilter ExtractionMode = "prefilter"
+	// ExtractionModeCrossCheck extracts IoCs with the LLM and drops values that do not
+	// occur in the article text
+	ExtractionModeCrossCheck ExtractionMode = "crosscheck"
+)
+
+// AllExtractionModes lists the defined extraction modes
+var AllExtractionModes = []ExtractionMode{
+	ExtractionModeLLM,
+	ExtractionModeRegex,
+	ExtractionModePrefilter,
+	ExtractionModeCrossCheck,
+}
+
+// IsValid returns true if the mode is one of the defined modes
+func (m ExtractionMode) IsValid() bool {
+	for _, v := range AllExtractionModes {
+		if m == v {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesLLM returns true if the mode requires an LLM client
+func (m ExtractionMode) UsesLLM() bool {
+	return m != ExtractionModeRegex
+}
+
+// LLMFallback selects how RSS sources whose extraction mode uses the LLM are
+// fetched when no LLM is configured
+type LLMFallback string
+
+const (
+	// LLMFallbackSkip fails the fetch of the source without fetching it (default)
+	LLMFallbackSkip LLMFallback = "skip"
+	// LLMFallbackRegex extracts IoCs of the source with ExtractionModeRegex
+	LLMFallbackRegex LLMFallback = "regex"
+)
+
+// IsValid returns true if the fallback is one of the defined fallbacks
+func (f LLMFallback) IsValid() bool {
+	return f == LLMFallbackSkip || f == LLMFallbackRegex
 }
 
 // FeedConfig contains feed-specific configuration
@@ -0,0 +1,142 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// SourceChangeAction is the kind of change made to a stored source
+type SourceChangeAction string
+
+const (
+	SourceChangeCreate  SourceChangeAction = "create"
+	SourceChangeUpdate  SourceChangeAction = "update"
+	SourceChangeEnable  SourceChangeAction = "enable"
+	SourceChangeDisable SourceChangeAction = "disable"
+	SourceChangeDelete  SourceChangeAction = "delete"
+	// SourceChangeImport is a source created or replaced from the TOML config
+	SourceChangeImport SourceChangeAction = "import"
+)
+
+// SourceChange records a change of a stored source for the audit trail
+type SourceChange struct {
+	ID        string             // Unique identifier (UUIDv7)
+	SourceID  string             // Source identifier
+	Action    SourceChangeAction // Kind of change
+	Actor     string             // User name, or "system:config" for imports
+	Fields    []string           // Names of the changed fields, see DiffSources
+	Before    *Source            // Source before the change, nil on create
+	After     *Source            // Source after the change, nil on delete
+	CreatedAt time.Time          // Change time
+}
+
+// NewSourceChange creates a change record with a generated ID and the changed fields
+func NewSourceChange(action SourceChangeAction, actor string, before, after *Source, at time.Time) *SourceChange {
+	c := &SourceChange{
+		ID:        uuid.Must(uuid.NewV7()).String(),
+		Action:    action,
+		Actor:     actor,
+		Fields:    DiffSources(before, after),
+		Before:    before,
+		After:     after,
+		CreatedAt: at,
+	}
+	if after != nil {
+		c.SourceID = after.ID
+	} else if before != nil {
+		c.SourceID = before.ID
+	}
+	return c
+}
+
+// DiffSources returns the names of the fields that differ between two
+// definitions of a source. Timestamps are ignored. A nil source differs in
+// every set field of the other.
+func DiffSources(before, after *Source) []string {
+	if before == nil {
+		before = &Source{}
+	}
+	if after == nil {
+		after = &Source{}
+	}
+
+	var rssBefore, rssAfter RSSConfig
+	if before.RSSConfig != nil {
+		rssBefore = *before.RSSConfig
+	}
+	if after.RSSConfig != nil {
+		rssAfter = *after.RSSConfig
+	}
+	var feedBefore, feedAfter FeedConfig
+	if before.FeedConfig != nil {
+		feedBefore = *before.FeedConfig
+	}
+	if after.FeedConfig != nil {
+		feedAfter = *after.FeedConfig
+	}
+
+	fields := []struct {
+		name          string
+		before, after any
+	}{
+		{"type", before.Type, after.Type},
+		{"url", before.URL, after.URL},
+		{"description", before.Description, after.Description},
+		{"tags", normalizeTags(before.Tags), normalizeTags(after.Tags)},
+		{"enabled", before.Enabled, after.Enabled},
+		{"ttl", before.TTL, after.TTL},
+		{"reliability", before.Reliability, after.Reliability},
+		{"max_articles", rssBefore.MaxArticles, rssAfter.MaxArticles},
+		{"extraction", rssBefore.Extraction, rssAfter.Extraction},
+		{"prompt", rssBefore.Prompt, rssAfter.Prompt},
+		{"schema", feedBefore.Schema, feedAfter.Schema},
+		{"max_items", feedBefore.MaxItems, feedAfter.MaxItems},
+	}
+
+	var changed []string
+	for _, f := range fields {
+		if !reflect.DeepEqual(f.before, f.after) {
+			changed = append(changed, f.name)
+		}
+	}
+	return changed
+}
+
+func normalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// Validate checks the fields required to store and fetch the source. Source
+// settings are validated by the config rules before they reach the model.
+func (s *Source) Validate() error {
+	if s.ID == "" {
+		return goerr.New("source ID is required")
+	}
+	if strings.ContainsAny(s.ID, "/ ") {
+		return goerr.New("source ID must not contain '/' or spaces", goerr.V("id", s.ID))
+	}
+	if s.ID == ManualSourceID {
+		return goerr.New("source ID is reserved", goerr.V("id", s.ID))
+	}
+
+	switch s.Type {
+	case SourceTypeRSS:
+		if s.URL == "" {
+			return goerr.New("url is required for RSS sources", goerr.V("id", s.ID))
+		}
+	case SourceTypeFeed:
+		if s.FeedConfig == nil || s.FeedConfig.Schema == "" {
+			return goerr.New("schema is required for feed sources", goerr.V("id", s.ID))
+		}
+	default:
+		return goerr.New("invalid source type", goerr.V("id", s.ID), goerr.V("type", s.Type))
+	}
+	return nil
+}
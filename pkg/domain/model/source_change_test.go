@@ -0,0 +1,68 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func TestDiffSources(t *testing.T) {
+	ttl := 24 * time.Hour
+	before := &model.Source{
+		ID:        "blog",
+		Type:      model.SourceTypeRSS,
+		URL:       "https://blog.example/feed",
+		Enabled:   true,
+		RSSConfig: &model.RSSConfig{MaxArticles: 10},
+		CreatedAt: time.Now(),
+	}
+
+	t.Run("equal definitions", func(t *testing.T) {
+		after := *before
+		after.Tags = []string{}
+		after.UpdatedAt = time.Now()
+		gt.A(t, model.DiffSources(before, &after)).Length(0)
+	})
+
+	t.Run("changed fields", func(t *testing.T) {
+		after := *before
+		after.Enabled = false
+		after.TTL = &ttl
+		after.RSSConfig = &model.RSSConfig{MaxArticles: 10, Extraction: model.ExtractionModeRegex}
+		gt.Equal(t, model.DiffSources(before, &after), []string{"enabled", "ttl", "extraction"})
+	})
+
+	t.Run("created source", func(t *testing.T) {
+		gt.Equal(t, model.DiffSources(nil, before), []string{"type", "url", "enabled", "max_articles"})
+	})
+}
+
+func TestSource_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     model.Source
+		wantErr bool
+	}{
+		{name: "rss", src: model.Source{ID: "blog", Type: model.SourceTypeRSS, URL: "https://blog.example/feed"}},
+		{name: "feed", src: model.Source{ID: "urlhaus", Type: model.SourceTypeFeed, FeedConfig: &model.FeedConfig{Schema: "abuse_ch_urlhaus"}}},
+		{name: "missing ID", src: model.Source{Type: model.SourceTypeRSS, URL: "https://blog.example/feed"}, wantErr: true},
+		{name: "ID with slash", src: model.Source{ID: "a/b", Type: model.SourceTypeRSS, URL: "https://blog.example/feed"}, wantErr: true},
+		{name: "reserved ID", src: model.Source{ID: model.ManualSourceID, Type: model.SourceTypeRSS, URL: "https://blog.example/feed"}, wantErr: true},
+		{name: "rss without URL", src: model.Source{ID: "blog", Type: model.SourceTypeRSS}, wantErr: true},
+		{name: "feed without schema", src: model.Source{ID: "urlhaus", Type: model.SourceTypeFeed}, wantErr: true},
+		{name: "manual type", src: model.Source{ID: "x", Type: model.SourceTypeManual}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.src.Validate()
+			if tt.wantErr {
+				gt.Error(t, err)
+			} else {
+				gt.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// StaleIntervals is the number of expected fetch intervals after which a
+// source that has not been fetched is stale. One missed run is tolerated.
+const StaleIntervals = 2
+
+// SourceStatus is the ingestion status of an enabled source
+type SourceStatus struct {
+	SourceID      string
+	SourceType    SourceType
+	LastFetchedAt time.Time // Zero if the source has never been fetched
+	LastStatus    string    // Status of the last fetch, empty if never fetched
+	Stale         bool
+	Reason        string // Why the source is stale, empty if not stale
+}
+
+// NewSourceStatus evaluates the ingestion status of source at now. state is
+// nil if the source has never been fetched. The source is stale if its last
+// fetch failed or if it has not been fetched within StaleIntervals times
+// interval, counted from its creation if it has never been fetched.
+func NewSourceStatus(source *Source, state *SourceState, interval time.Duration, now time.Time) *SourceStatus {
+	status := &SourceStatus{
+		SourceID:   source.ID,
+		SourceType: source.Type,
+	}
+	deadline := now.Add(-StaleIntervals * interval)
+
+	if state == nil || state.LastFetchedAt.IsZero() {
+		if source.CreatedAt.IsZero() || source.CreatedAt.Before(deadline) {
+			status.Stale = true
+			status.Reason = "never fetched"
+		}
+		return status
+	}
+
+	status.LastFetchedAt = state.LastFetchedAt
+	status.LastStatus = state.LastStatus
+	switch {
+	case state.LastStatus == string(FetchStatusFailure):
+		status.Stale = true
+		status.Reason = "last fetch failed"
+	case state.LastFetchedAt.Before(deadline):
+		status.Stale = true
+		status.Reason = "not fetched within the expected interval"
+	}
+	return status
+}
@@ -0,0 +1,58 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func TestNewSourceStatus(t *testing.T) {
+	now := time.Now()
+	interval := time.Hour
+	source := &model.Source{ID: "blog", Type: model.SourceTypeRSS, Enabled: true, CreatedAt: now.Add(-24 * time.Hour)}
+	state := func(fetchedAgo time.Duration, status model.FetchStatus) *model.SourceState {
+		return &model.SourceState{SourceID: "blog", LastFetchedAt: now.Add(-fetchedAgo), LastStatus: string(status)}
+	}
+
+	t.Run("fetched within the interval", func(t *testing.T) {
+		s := model.NewSourceStatus(source, state(30*time.Minute, model.FetchStatusSuccess), interval, now)
+		gt.False(t, s.Stale)
+		gt.Equal(t, s.SourceID, "blog")
+		gt.Equal(t, s.SourceType, model.SourceTypeRSS)
+		gt.Equal(t, s.LastStatus, "success")
+		gt.True(t, s.LastFetchedAt.Equal(now.Add(-30*time.Minute)))
+	})
+
+	t.Run("one missed fetch is tolerated", func(t *testing.T) {
+		s := model.NewSourceStatus(source, state(90*time.Minute, model.FetchStatusPartialSuccess), interval, now)
+		gt.False(t, s.Stale)
+	})
+
+	t.Run("not fetched within twice the interval", func(t *testing.T) {
+		s := model.NewSourceStatus(source, state(3*time.Hour, model.FetchStatusSuccess), interval, now)
+		gt.True(t, s.Stale)
+		gt.Equal(t, s.Reason, "not fetched within the expected interval")
+	})
+
+	t.Run("last fetch failed", func(t *testing.T) {
+		s := model.NewSourceStatus(source, state(time.Minute, model.FetchStatusFailure), interval, now)
+		gt.True(t, s.Stale)
+		gt.Equal(t, s.Reason, "last fetch failed")
+	})
+
+	t.Run("never fetched", func(t *testing.T) {
+		s := model.NewSourceStatus(source, nil, interval, now)
+		gt.True(t, s.Stale)
+		gt.Equal(t, s.Reason, "never fetched")
+		gt.True(t, s.LastFetchedAt.IsZero())
+	})
+
+	t.Run("recently created source is not stale yet", func(t *testing.T) {
+		created := *source
+		created.CreatedAt = now.Add(-time.Minute)
+		s := model.NewSourceStatus(&created, nil, interval, now)
+		gt.False(t, s.Stale)
+	})
+}
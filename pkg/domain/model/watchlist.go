@@ -0,0 +1,367 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// WatchPatternKind represents how a watch pattern is matched against IoCs
+type WatchPatternKind string
+
+const (
+	// WatchPatternDomain matches a domain and its subdomains in domain, URL and email IoCs
+	WatchPatternDomain WatchPatternKind = "domain"
+	// WatchPatternKeyword matches a case-insensitive substring of the IoC value, description or context
+	WatchPatternKeyword WatchPatternKind = "keyword"
+	// WatchPatternCIDR matches IPv4/IPv6 IoCs and URLs whose host is an address in the range
+	WatchPatternCIDR WatchPatternKind = "cidr"
+	// WatchPatternASN matches ASN IoCs, "AS<number>" mentions in the description or
+	// context, and IoCs whose host (IP address, or resolved domain) is announced
+	// by the ASN when an ASN resolver is configured
+	WatchPatternASN WatchPatternKind = "asn"
+	// WatchPatternRegex matches the IoC value with an RE2 regular expression
+	WatchPatternRegex WatchPatternKind = "regex"
+)
+
+// Fields of an IoC reported in WatchlistHit.MatchedField
+const (
+	WatchFieldValue       = "value"
+	WatchFieldHost        = "host"
+	WatchFieldDescription = "description"
+	WatchFieldContext     = "context"
+	WatchFieldHostASN     = "host_asn"
+)
+
+var asnMentionPattern = regexp.MustCompile(`(?i)\bAS\s?(\d+)\b`)
+
+// IsValid returns true if the kind is one of the defined pattern kinds
+func (k WatchPatternKind) IsValid() bool {
+	switch k {
+	case WatchPatternDomain, WatchPatternKeyword, WatchPatternCIDR, WatchPatternASN, WatchPatternRegex:
+		return true
+	default:
+		return false
+	}
+}
+
+// WatchPattern is a single pattern of a watchlist
+type WatchPattern struct {
+	Kind  WatchPatternKind
+	Value string
+}
+
+// Watchlist is a named set of patterns describing assets or keywords of interest.
+// Newly ingested IoCs matching any pattern of an enabled watchlist are recorded as hits.
+type Watchlist struct {
+	ID          string
+	Name        string
+	Description string
+	Patterns    []WatchPattern
+	Enabled     bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewWatchlistID generates a unique watchlist ID using UUIDv7
+func NewWatchlistID() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// Validate validates the watchlist and normalizes its patterns
+func (w *Watchlist) Validate() error {
+	if strings.TrimSpace(w.Name) == "" {
+		return goerr.New("watchlist name is required")
+	}
+	if len(w.Patterns) == 0 {
+		return goerr.New("watchlist requires at least one pattern", goerr.V("name", w.Name))
+	}
+
+	for i, p := range w.Patterns {
+		normalized, err := normalizeWatchPattern(p)
+		if err != nil {
+			return goerr.Wrap(err, "invalid watch pattern",
+				goerr.V("name", w.Name),
+				goerr.V("kind", p.Kind),
+				goerr.V("value", p.Value))
+		}
+		w.Patterns[i] = normalized
+	}
+
+	return nil
+}
+
+func normalizeWatchPattern(p WatchPattern) (WatchPattern, error) {
+	value := strings.TrimSpace(p.Value)
+	if value == "" {
+		return p, goerr.New("pattern value is required")
+	}
+
+	switch p.Kind {
+	case WatchPatternDomain:
+		value = strings.Trim(strings.TrimPrefix(strings.ToLower(value), "*."), ".")
+		if value == "" || strings.ContainsAny(value, " /:@") {
+			return p, goerr.New("invalid domain")
+		}
+	case WatchPatternKeyword:
+		value = strings.ToLower(value)
+	case WatchPatternCIDR:
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			// A single address is treated as a host prefix
+			addr, addrErr := netip.ParseAddr(value)
+			if addrErr != nil {
+				return p, goerr.Wrap(err, "invalid CIDR")
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		value = prefix.Masked().String()
+	case WatchPatternASN:
+		value = strings.TrimPrefix(strings.ToUpper(value), "AS")
+		if _, err := strconv.ParseUint(value, 10, 32); err != nil {
+			return p, goerr.Wrap(err, "invalid ASN")
+		}
+	case WatchPatternRegex:
+		if _, err := regexp.Compile(value); err != nil {
+			return p, goerr.Wrap(err, "invalid regex")
+		}
+	default:
+		return p, goerr.New("unknown pattern kind")
+	}
+
+	return WatchPattern{Kind: p.Kind, Value: value}, nil
+}
+
+// WatchlistHit records an IoC matching a watchlist
+type WatchlistHit struct {
+	ID            string // Derived from watchlist ID and IoC ID
+	WatchlistID   string
+	WatchlistName string
+	IoCID         string
+	IoCType       IoCType
+	IoCValue      string
+	SourceID      string
+	PatternKind   WatchPatternKind
+	Pattern       string
+	MatchedField  string // value, host, description or context
+
+	Acknowledged   bool
+	AcknowledgedBy string
+	AcknowledgedAt time.Time
+
+	CreatedAt time.Time
+}
+
+// WatchlistHitID returns the ID of the hit of an IoC on a watchlist.
+// An IoC produces at most one hit per watchlist.
+func WatchlistHitID(watchlistID, iocID string) string {
+	sum := sha256.Sum256([]byte(watchlistID + "\x00" + iocID))
+	return hex.EncodeToString(sum[:16])
+}
+
+// WatchlistHitListOptions represents filter and pagination options for listing hits
+type WatchlistHitListOptions struct {
+	WatchlistID  string // Empty = all watchlists
+	Acknowledged *bool  // nil = both
+	Offset       int
+	Limit        int // 0 = no limit
+}
+
+// WatchlistHitConnection represents a paginated list of watchlist hits
+type WatchlistHitConnection struct {
+	Items []*WatchlistHit
+	Total int
+}
+
+// WatchlistMatcher matches IoCs against compiled watchlists
+type WatchlistMatcher struct {
+	watchlists []*compiledWatchlist
+}
+
+type compiledWatchlist struct {
+	watchlist *Watchlist
+	patterns  []compiledWatchPattern
+}
+
+type compiledWatchPattern struct {
+	WatchPattern
+	prefix netip.Prefix
+	regex  *regexp.Regexp
+	asn    uint32
+}
+
+// NewWatchlistMatcher compiles the enabled watchlists. Watchlists must be validated.
+func NewWatchlistMatcher(watchlists []*Watchlist) (*WatchlistMatcher, error) {
+	m := &WatchlistMatcher{}
+	for _, w := range watchlists {
+		if !w.Enabled {
+			continue
+		}
+
+		cw := &compiledWatchlist{watchlist: w}
+		for _, p := range w.Patterns {
+			cp := compiledWatchPattern{WatchPattern: p}
+			switch p.Kind {
+			case WatchPatternCIDR:
+				prefix, err := netip.ParsePrefix(p.Value)
+				if err != nil {
+					return nil, goerr.Wrap(err, "invalid CIDR in watchlist",
+						goerr.V("watchlist_id", w.ID), goerr.V("value", p.Value))
+				}
+				cp.prefix = prefix
+			case WatchPatternRegex:
+				re, err := regexp.Compile(p.Value)
+				if err != nil {
+					return nil, goerr.Wrap(err, "invalid regex in watchlist",
+						goerr.V("watchlist_id", w.ID), goerr.V("value", p.Value))
+				}
+				cp.regex = re
+			case WatchPatternASN:
+				asn, err := strconv.ParseUint(p.Value, 10, 32)
+				if err != nil {
+					return nil, goerr.Wrap(err, "invalid ASN in watchlist",
+						goerr.V("watchlist_id", w.ID), goerr.V("value", p.Value))
+				}
+				cp.asn = uint32(asn)
+			}
+			cw.patterns = append(cw.patterns, cp)
+		}
+		m.watchlists = append(m.watchlists, cw)
+	}
+	return m, nil
+}
+
+// Empty returns true if there are no enabled watchlists
+func (m *WatchlistMatcher) Empty() bool {
+	return len(m.watchlists) == 0
+}
+
+// HasASNPatterns returns true if an enabled watchlist has an ASN pattern, so
+// that the ASNs of IoC hosts are needed
+func (m *WatchlistMatcher) HasASNPatterns() bool {
+	for _, cw := range m.watchlists {
+		for _, p := range cw.patterns {
+			if p.Kind == WatchPatternASN {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Match returns one hit per watchlist the IoC matches, for the first matching
+// pattern. hostASNs are the ASNs announcing the host of the IoC (see
+// IoC.Host), nil if unknown.
+func (m *WatchlistMatcher) Match(ioc *IoC, hostASNs []uint32, now time.Time) []*WatchlistHit {
+	var hits []*WatchlistHit
+	for _, cw := range m.watchlists {
+		for _, p := range cw.patterns {
+			field, ok := p.match(ioc, hostASNs)
+			if !ok {
+				continue
+			}
+			hits = append(hits, &WatchlistHit{
+				ID:            WatchlistHitID(cw.watchlist.ID, ioc.ID),
+				WatchlistID:   cw.watchlist.ID,
+				WatchlistName: cw.watchlist.Name,
+				IoCID:         ioc.ID,
+				IoCType:       ioc.Type,
+				IoCValue:      ioc.Value,
+				SourceID:      ioc.SourceID,
+				PatternKind:   p.Kind,
+				Pattern:       p.Value,
+				MatchedField:  field,
+				CreatedAt:     now,
+			})
+			break
+		}
+	}
+	return hits
+}
+
+// match returns the matched field of the IoC
+func (p *compiledWatchPattern) match(ioc *IoC, hostASNs []uint32) (string, bool) {
+	switch p.Kind {
+	case WatchPatternDomain:
+		if host, field := iocHost(ioc); host != "" && (host == p.Value || strings.HasSuffix(host, "."+p.Value)) {
+			return field, true
+		}
+	case WatchPatternKeyword:
+		for _, f := range [][2]string{
+			{WatchFieldValue, ioc.Value},
+			{WatchFieldDescription, ioc.Description},
+			{WatchFieldContext, ioc.Context},
+		} {
+			if strings.Contains(strings.ToLower(f[1]), p.Value) {
+				return f[0], true
+			}
+		}
+	case WatchPatternCIDR:
+		host, field := iocHost(ioc)
+		if addr, err := netip.ParseAddr(host); err == nil && p.prefix.Contains(addr.Unmap()) {
+			return field, true
+		}
+	case WatchPatternASN:
+		if ioc.Type == IoCTypeASN && NormalizeValue(IoCTypeASN, ioc.Value) == p.Value {
+			return WatchFieldValue, true
+		}
+		for _, f := range [][2]string{
+			{WatchFieldDescription, ioc.Description},
+			{WatchFieldContext, ioc.Context},
+		} {
+			for _, m := range asnMentionPattern.FindAllStringSubmatch(f[1], -1) {
+				if strings.TrimLeft(m[1], "0") == strings.TrimLeft(p.Value, "0") {
+					return f[0], true
+				}
+			}
+		}
+		if slices.Contains(hostASNs, p.asn) {
+			return WatchFieldHostASN, true
+		}
+	case WatchPatternRegex:
+		if p.regex.MatchString(ioc.Value) {
+			return WatchFieldValue, true
+		}
+	}
+	return "", false
+}
+
+// Host returns the lower-cased domain or IP address of domain, IP, URL and
+// email IoCs, empty for other types
+func (ioc *IoC) Host() string {
+	host, _ := iocHost(ioc)
+	return host
+}
+
+// iocHost returns the lower-cased domain or IP address of network IoCs.
+// IP values may carry a port (e.g. ThreatFox "ip:port").
+func iocHost(ioc *IoC) (string, string) {
+	switch ioc.Type {
+	case IoCTypeDomain:
+		return strings.ToLower(strings.TrimSuffix(ioc.Value, ".")), WatchFieldValue
+	case IoCTypeIPv4, IoCTypeIPv6:
+		if host, _, err := net.SplitHostPort(ioc.Value); err == nil {
+			return host, WatchFieldValue
+		}
+		return ioc.Value, WatchFieldValue
+	case IoCTypeURL:
+		if u, err := url.Parse(ioc.Value); err == nil {
+			return strings.ToLower(u.Hostname()), WatchFieldHost
+		}
+	case IoCTypeEmail:
+		if i := strings.LastIndex(ioc.Value, "@"); i >= 0 {
+			return strings.ToLower(ioc.Value[i+1:]), WatchFieldHost
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,188 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func TestWatchlist_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		pattern model.WatchPattern
+		want    string
+		wantErr bool
+	}{
+		{name: "domain is normalized", pattern: model.WatchPattern{Kind: model.WatchPatternDomain, Value: "*.Example.COM."}, want: "example.com"},
+		{name: "keyword is lower-cased", pattern: model.WatchPattern{Kind: model.WatchPatternKeyword, Value: "ACME Corp"}, want: "acme corp"},
+		{name: "cidr is masked", pattern: model.WatchPattern{Kind: model.WatchPatternCIDR, Value: "198.51.100.7/24"}, want: "198.51.100.0/24"},
+		{name: "single address becomes host prefix", pattern: model.WatchPattern{Kind: model.WatchPatternCIDR, Value: "2001:db8::1"}, want: "2001:db8::1/128"},
+		{name: "asn prefix is stripped", pattern: model.WatchPattern{Kind: model.WatchPatternASN, Value: "as64500"}, want: "64500"},
+		{name: "regex is kept", pattern: model.WatchPattern{Kind: model.WatchPatternRegex, Value: `^login\.`}, want: `^login\.`},
+		{name: "invalid cidr", pattern: model.WatchPattern{Kind: model.WatchPatternCIDR, Value: "not-an-ip"}, wantErr: true},
+		{name: "invalid asn", pattern: model.WatchPattern{Kind: model.WatchPatternASN, Value: "AS64x"}, wantErr: true},
+		{name: "asn out of range", pattern: model.WatchPattern{Kind: model.WatchPatternASN, Value: "AS4294967296"}, wantErr: true},
+		{name: "invalid regex", pattern: model.WatchPattern{Kind: model.WatchPatternRegex, Value: "(["}, wantErr: true},
+		{name: "domain with path", pattern: model.WatchPattern{Kind: model.WatchPatternDomain, Value: "example.com/login"}, wantErr: true},
+		{name: "unknown kind", pattern: model.WatchPattern{Kind: "hash", Value: "abc"}, wantErr: true},
+		{name: "empty value", pattern: model.WatchPattern{Kind: model.WatchPatternKeyword, Value: " "}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &model.Watchlist{Name: "assets", Patterns: []model.WatchPattern{tc.pattern}}
+			err := w.Validate()
+			if tc.wantErr {
+				gt.Error(t, err)
+				return
+			}
+			gt.NoError(t, err)
+			gt.Equal(t, w.Patterns[0].Value, tc.want)
+		})
+	}
+
+	t.Run("name is required", func(t *testing.T) {
+		w := &model.Watchlist{Patterns: []model.WatchPattern{{Kind: model.WatchPatternKeyword, Value: "acme"}}}
+		gt.Error(t, w.Validate())
+	})
+
+	t.Run("patterns are required", func(t *testing.T) {
+		w := &model.Watchlist{Name: "empty"}
+		gt.Error(t, w.Validate())
+	})
+}
+
+func TestWatchlistMatcher_Match(t *testing.T) {
+	newWatchlist := func(id string, patterns ...model.WatchPattern) *model.Watchlist {
+		w := &model.Watchlist{ID: id, Name: id, Patterns: patterns, Enabled: true}
+		gt.NoError(t, w.Validate())
+		return w
+	}
+
+	watchlists := []*model.Watchlist{
+		newWatchlist("domains", model.WatchPattern{Kind: model.WatchPatternDomain, Value: "acme.example"}),
+		newWatchlist("network", model.WatchPattern{Kind: model.WatchPatternCIDR, Value: "198.51.100.0/24"}),
+		newWatchlist("asn", model.WatchPattern{Kind: model.WatchPatternASN, Value: "AS64500"}),
+		newWatchlist("keywords", model.WatchPattern{Kind: model.WatchPatternKeyword, Value: "ACME"}),
+		newWatchlist("regex", model.WatchPattern{Kind: model.WatchPatternRegex, Value: `^[a-f0-9]{64}$`}),
+	}
+	disabled := newWatchlist("disabled", model.WatchPattern{Kind: model.WatchPatternKeyword, Value: "login"})
+	disabled.Enabled = false
+	watchlists = append(watchlists, disabled)
+
+	matcher, err := model.NewWatchlistMatcher(watchlists)
+	gt.NoError(t, err)
+	gt.False(t, matcher.Empty())
+
+	testCases := []struct {
+		name  string
+		ioc   *model.IoC
+		want  map[string]string // watchlist ID -> matched field
+		empty bool
+	}{
+		{
+			name: "subdomain",
+			ioc:  &model.IoC{Type: model.IoCTypeDomain, Value: "login.Acme.example"},
+			want: map[string]string{"domains": model.WatchFieldValue, "keywords": model.WatchFieldValue},
+		},
+		{
+			name: "lookalike domain is not a subdomain",
+			ioc:  &model.IoC{Type: model.IoCTypeDomain, Value: "notacme.example"},
+			want: map[string]string{"keywords": model.WatchFieldValue},
+		},
+		{
+			name: "url host",
+			ioc:  &model.IoC{Type: model.IoCTypeURL, Value: "https://198.51.100.20:8443/payload"},
+			want: map[string]string{"network": model.WatchFieldHost},
+		},
+		{
+			name: "ip with port",
+			ioc:  &model.IoC{Type: model.IoCTypeIPv4, Value: "198.51.100.50:51515"},
+			want: map[string]string{"network": model.WatchFieldValue},
+		},
+		{
+			name: "email domain",
+			ioc:  &model.IoC{Type: model.IoCTypeEmail, Value: "ceo@mail.acme.example"},
+			want: map[string]string{"domains": model.WatchFieldHost, "keywords": model.WatchFieldValue},
+		},
+		{
+			name: "asn mentioned in description",
+			ioc:  &model.IoC{Type: model.IoCTypeIPv4, Value: "203.0.113.9", Description: "C2 hosted on AS64500"},
+			want: map[string]string{"asn": model.WatchFieldDescription},
+		},
+		{
+			name: "keyword in context",
+			ioc:  &model.IoC{Type: model.IoCTypeIPv4, Value: "203.0.113.10", Context: "phishing kit targeting Acme employees"},
+			want: map[string]string{"keywords": model.WatchFieldContext},
+		},
+		{
+			name: "regex on value",
+			ioc:  &model.IoC{Type: model.IoCTypeSHA256, Value: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+			want: map[string]string{"regex": model.WatchFieldValue},
+		},
+		{
+			name:  "no match; disabled watchlist is ignored",
+			ioc:   &model.IoC{Type: model.IoCTypeDomain, Value: "login.other.example"},
+			empty: true,
+		},
+	}
+
+	t.Run("asn of the host", func(t *testing.T) {
+		gt.True(t, matcher.HasASNPatterns())
+		ioc := &model.IoC{ID: "ioc-1", Type: model.IoCTypeURL, Value: "https://cdn.other.example/a.js"}
+		gt.Equal(t, ioc.Host(), "cdn.other.example")
+
+		hits := matcher.Match(ioc, []uint32{64496, 64500}, time.Now())
+		gt.A(t, hits).Length(1)
+		gt.Equal(t, hits[0].WatchlistID, "asn")
+		gt.Equal(t, hits[0].MatchedField, model.WatchFieldHostASN)
+
+		gt.A(t, matcher.Match(ioc, []uint32{64496}, time.Now())).Length(0)
+	})
+
+	now := time.Now()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.ioc.ID = "ioc-1"
+			hits := matcher.Match(tc.ioc, nil, now)
+			if tc.empty {
+				gt.A(t, hits).Length(0)
+				return
+			}
+
+			gt.A(t, hits).Length(len(tc.want))
+			for _, hit := range hits {
+				field, ok := tc.want[hit.WatchlistID]
+				gt.True(t, ok)
+				gt.Equal(t, hit.MatchedField, field)
+				gt.Equal(t, hit.ID, model.WatchlistHitID(hit.WatchlistID, "ioc-1"))
+				gt.Equal(t, hit.IoCValue, tc.ioc.Value)
+			}
+		})
+	}
+}
+
+func TestNotificationRule_MatchWatchlistHit(t *testing.T) {
+	rule := &model.NotificationRule{
+		Name:       "assets",
+		Kind:       model.NotificationRuleWatchlist,
+		Channels:   []string{"c"},
+		Watchlists: []string{"crown-jewels"},
+		Types:      []model.IoCType{model.IoCTypeDomain},
+	}
+	gt.NoError(t, rule.Validate())
+
+	ioc := &model.IoC{Type: model.IoCTypeDomain, Value: "login.acme.example"}
+	gt.True(t, rule.MatchWatchlistHit(&model.WatchlistHit{WatchlistName: "crown-jewels"}, ioc))
+	gt.False(t, rule.MatchWatchlistHit(&model.WatchlistHit{WatchlistName: "other"}, ioc))
+	gt.False(t, rule.MatchWatchlistHit(&model.WatchlistHit{WatchlistName: "crown-jewels"},
+		&model.IoC{Type: model.IoCTypeURL, Value: "https://login.acme.example/"}))
+
+	anyRule := &model.NotificationRule{Name: "any", Kind: model.NotificationRuleWatchlist, Channels: []string{"c"}, Watchlists: []string{"*"}}
+	gt.NoError(t, anyRule.Validate())
+	gt.True(t, anyRule.MatchWatchlistHit(&model.WatchlistHit{WatchlistName: "other"}, ioc))
+	// Watchlist-only rules do not match by value pattern
+	gt.False(t, anyRule.MatchIoC(ioc))
+}
@@ -1,11 +1,14 @@
 package vectorizer
 
 import (
+	"context"
+	"fmt"
 	"hash/fnv"
 	"math"
 	"strings"
 
 	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
 )
 
 const (
@@ -36,6 +39,8 @@ type NGramVectorizer struct {
 	ngramSize int
 }
 
+var _ interfaces.Embedder = &NGramVectorizer{}
+
 // Option configures NGramVectorizer
 type Option func(*NGramVectorizer)
 
@@ -110,6 +115,16 @@ func (v *NGramVectorizer) Dimension() int {
 	return v.dimension
 }
 
+// Embed vectorizes texts as an interfaces.Embedder
+func (v *NGramVectorizer) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return v.VectorizeBatch(texts)
+}
+
+// Model identifies the n-gram size, e.g. "ngram/3"
+func (v *NGramVectorizer) Model() string {
+	return fmt.Sprintf("ngram/%d", v.ngramSize)
+}
+
 // extractNGrams extracts character n-grams from text
 // Returns map[ngram]frequency
 func extractNGrams(text string, n int) map[string]int {
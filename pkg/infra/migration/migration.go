@@ -9,28 +9,184 @@ import (
 	"github.com/secmon-lab/beehive/pkg/utils/safe"
 )
 
+type options struct {
+	embeddingDim int
+}
+
+// Option configures MigrateFirestore
+type Option func(*options)
+
+// WithEmbeddingDimension sets the dimension of the IoC embedding vector index
+// (default: model.EmbeddingDimension). It must match the dimension of the
+// configured embedder; stored IoCs of another dimension need re-embedding.
+func WithEmbeddingDimension(dim int) Option {
+	return func(o *options) {
+		o.embeddingDim = dim
+	}
+}
+
 // MigrateFirestore creates or updates Firestore indexes using fireconf
-func MigrateFirestore(ctx context.Context, projectID, databaseID string) error {
+func MigrateFirestore(ctx context.Context, projectID, databaseID string, opts ...Option) error {
+	o := options{embeddingDim: model.EmbeddingDimension}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	// Define Firestore configuration
 	config := &fireconf.Config{
 		Collections: []fireconf.Collection{
 			{
 				Name: "iocs",
-				Indexes: []fireconf.Index{
+				Indexes: append([]fireconf.Index{
 					{
 						// Vector index for semantic search
 						Fields: []fireconf.IndexField{
 							{
 								Path: "Embedding",
 								Vector: &fireconf.VectorConfig{
-									Dimension: model.EmbeddingDimension, // 128
+									Dimension: o.embeddingDim,
 								},
 							},
 						},
 						QueryScope: fireconf.QueryScopeCollection,
 					},
+					{
+						// Composite index for the expiry sweeper
+						Fields: []fireconf.IndexField{
+							{Path: "Status", Order: fireconf.OrderAscending},
+							{Path: "ExpiresAt", Order: fireconf.OrderAscending},
+						},
+						QueryScope: fireconf.QueryScopeCollection,
+					},
+					{
+						// Composite index for listing by type in the default order
+						Fields: []fireconf.IndexField{
+							{Path: "Type", Order: fireconf.OrderAscending},
+							{Path: "UpdatedAt", Order: fireconf.OrderDescending},
+						},
+						QueryScope: fireconf.QueryScopeCollection,
+					},
+					{
+						// Composite index for digests of IoCs tagged by analysts
+						Fields: []fireconf.IndexField{
+							{Path: "Tags", Array: fireconf.ArrayConfigContains},
+							{Path: "UpdatedAt", Order: fireconf.OrderAscending},
+						},
+						QueryScope: fireconf.QueryScopeCollection,
+					},
+					{
+						// Composite index for digests of IoCs of tagged sources
+						Fields: []fireconf.IndexField{
+							{Path: "SourceID", Order: fireconf.OrderAscending},
+							{Path: "UpdatedAt", Order: fireconf.OrderAscending},
+						},
+						QueryScope: fireconf.QueryScopeCollection,
+					},
+					{
+						// Composite index for exports of IoCs tagged by analysts
+						Fields: []fireconf.IndexField{
+							{Path: "Tags", Array: fireconf.ArrayConfigContains},
+							{Path: "Status", Order: fireconf.OrderAscending},
+							{Path: "Confidence", Order: fireconf.OrderAscending},
+						},
+						QueryScope: fireconf.QueryScopeCollection,
+					},
+					{
+						// Composite index for exports of IoCs of tagged sources
+						Fields: []fireconf.IndexField{
+							{Path: "SourceID", Order: fireconf.OrderAscending},
+							{Path: "Status", Order: fireconf.OrderAscending},
+							{Path: "Confidence", Order: fireconf.OrderAscending},
+						},
+						QueryScope: fireconf.QueryScopeCollection,
+					},
+					{
+						// Composite index for corroboration lookups by value
+						Fields: []fireconf.IndexField{
+							{Path: "Type", Order: fireconf.OrderAscending},
+							{Path: "Value", Order: fireconf.OrderAscending},
+						},
+						QueryScope: fireconf.QueryScopeCollection,
+					},
+				}, confidenceIndexes()...),
+			},
+			{
+				Name: "watchlist_hits",
+				Indexes: []fireconf.Index{
+					{
+						// Listing hits of a watchlist, newest first
+						Fields: []fireconf.IndexField{
+							{Path: "WatchlistID", Order: fireconf.OrderAscending},
+							{Path: "CreatedAt", Order: fireconf.OrderDescending},
+						},
+						QueryScope: fireconf.QueryScopeCollection,
+					},
+					{
+						// Listing (un)acknowledged hits, newest first
+						Fields: []fireconf.IndexField{
+							{Path: "Acknowledged", Order: fireconf.OrderAscending},
+							{Path: "CreatedAt", Order: fireconf.OrderDescending},
+						},
+						QueryScope: fireconf.QueryScopeCollection,
+					},
+					{
+						// Listing (un)acknowledged hits of a watchlist, newest first
+						Fields: []fireconf.IndexField{
+							{Path: "WatchlistID", Order: fireconf.OrderAscending},
+							{Path: "Acknowledged", Order: fireconf.OrderAscending},
+							{Path: "CreatedAt", Order: fireconf.OrderDescending},
+						},
+						QueryScope: fireconf.QueryScopeCollection,
+					},
 				},
 			},
+			{
+				Name: "brand_matches",
+				Indexes: []fireconf.Index{
+					{
+						// Listing matches of a protected domain, newest first
+						Fields: []fireconf.IndexField{
+							{Path: "ProtectedDomain", Order: fireconf.OrderAscending},
+							{Path: "CreatedAt", Order: fireconf.OrderDescending},
+						},
+						QueryScope: fireconf.QueryScopeCollection,
+					},
+					{
+						// Listing matches of a technique, newest first
+						Fields: []fireconf.IndexField{
+							{Path: "Technique", Order: fireconf.OrderAscending},
+							{Path: "CreatedAt", Order: fireconf.OrderDescending},
+						},
+						QueryScope: fireconf.QueryScopeCollection,
+					},
+					{
+						// Listing matches of a protected domain and technique, newest first
+						Fields: []fireconf.IndexField{
+							{Path: "ProtectedDomain", Order: fireconf.OrderAscending},
+							{Path: "Technique", Order: fireconf.OrderAscending},
+							{Path: "CreatedAt", Order: fireconf.OrderDescending},
+						},
+						QueryScope: fireconf.QueryScopeCollection,
+					},
+				},
+			},
+			{
+				Name: "reports",
+				Indexes: []fireconf.Index{
+					{
+						// Listing reports of a source, newest first
+						Fields: []fireconf.IndexField{
+							{Path: "SourceID", Order: fireconf.OrderAscending},
+							{Path: "PublishedAt", Order: fireconf.OrderDescending},
+						},
+						QueryScope: fireconf.QueryScopeCollection,
+					},
+				},
+			},
+			{
+				Name:    "audit_records",
+				Indexes: auditIndexes(),
+			},
 		},
 	}
 
@@ -52,3 +208,58 @@ func MigrateFirestore(ctx context.Context, projectID, databaseID string) error {
 
 	return nil
 }
+
+// iocSortPaths are the IoC fields the IoC list can be sorted by
+var iocSortPaths = []string{"Type", "Value", "SourceID", "Status", "FirstSeenAt", "UpdatedAt", "Confidence"}
+
+// confidenceIndexes returns the composite indexes for listing IoCs with a
+// confidence threshold, optionally filtered by type, in every sort order
+func confidenceIndexes() []fireconf.Index {
+	var indexes []fireconf.Index
+	for _, path := range iocSortPaths {
+		for _, order := range []fireconf.Order{fireconf.OrderAscending, fireconf.OrderDescending} {
+			if path != "Confidence" {
+				indexes = append(indexes, fireconf.Index{
+					Fields: []fireconf.IndexField{
+						{Path: "Confidence", Order: fireconf.OrderAscending},
+						{Path: path, Order: order},
+					},
+					QueryScope: fireconf.QueryScopeCollection,
+				})
+			}
+			if path == "Type" {
+				continue
+			}
+
+			// Type filter combined with the threshold
+			fields := []fireconf.IndexField{{Path: "Type", Order: fireconf.OrderAscending}}
+			if path != "Confidence" {
+				fields = append(fields, fireconf.IndexField{Path: "Confidence", Order: fireconf.OrderAscending})
+			}
+			indexes = append(indexes, fireconf.Index{
+				Fields:     append(fields, fireconf.IndexField{Path: path, Order: order}),
+				QueryScope: fireconf.QueryScopeCollection,
+			})
+		}
+	}
+	return indexes
+}
+
+// auditFilterPaths are the audit record fields the audit log can be filtered by
+var auditFilterPaths = []string{"TargetID", "RequestID", "Actor", "Action", "TargetType"}
+
+// auditIndexes returns the composite indexes for listing the audit records
+// of a filter, newest first
+func auditIndexes() []fireconf.Index {
+	indexes := make([]fireconf.Index, len(auditFilterPaths))
+	for i, path := range auditFilterPaths {
+		indexes[i] = fireconf.Index{
+			Fields: []fireconf.IndexField{
+				{Path: path, Order: fireconf.OrderAscending},
+				{Path: "CreatedAt", Order: fireconf.OrderDescending},
+			},
+			QueryScope: fireconf.QueryScopeCollection,
+		}
+	}
+	return indexes
+}
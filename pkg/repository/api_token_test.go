@@ -0,0 +1,114 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+)
+
+func runAPITokenRepositoryTest(t *testing.T, repo interfaces.APITokenRepository) {
+	ctx := context.Background()
+
+	t.Run("put, get, find by hash and list tokens", func(t *testing.T) {
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		older, _, err := model.NewAPIToken("firewall", []model.TokenScope{model.ExportScope("blocklist")},
+			"user:alice", now.Add(24*time.Hour), now.Add(-time.Minute))
+		gt.NoError(t, err)
+		newer, secret, err := model.NewAPIToken("soar", []model.TokenScope{model.ScopeReadIoCs, model.ScopeFetchTrigger},
+			"user:alice", time.Time{}, now)
+		gt.NoError(t, err)
+		gt.NoError(t, repo.PutAPIToken(ctx, older))
+		gt.NoError(t, repo.PutAPIToken(ctx, newer))
+
+		got, err := repo.GetAPIToken(ctx, newer.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, got.Name, "soar")
+		gt.Equal(t, got.Scopes, []model.TokenScope{model.ScopeReadIoCs, model.ScopeFetchTrigger})
+		gt.Equal(t, got.CreatedBy, "user:alice")
+		gt.True(t, got.CreatedAt.Equal(now))
+		gt.True(t, got.ExpiresAt.IsZero())
+
+		// Modifying the returned token does not change the stored one
+		got.Scopes[0] = "changed"
+		got, err = repo.GetAPIToken(ctx, newer.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, got.Scopes[0], model.ScopeReadIoCs)
+
+		got, err = repo.GetAPITokenByHash(ctx, model.HashAPIToken(secret))
+		gt.NoError(t, err)
+		gt.Equal(t, got.ID, newer.ID)
+
+		_, err = repo.GetAPITokenByHash(ctx, model.HashAPIToken("bhv_unknown"))
+		gt.True(t, errors.Is(err, interfaces.ErrAPITokenNotFound))
+
+		list, err := repo.ListAPITokens(ctx)
+		gt.NoError(t, err)
+		var ids []string
+		for _, token := range list {
+			if token.ID == older.ID || token.ID == newer.ID {
+				ids = append(ids, token.ID)
+			}
+		}
+		gt.Equal(t, ids, []string{newer.ID, older.ID})
+	})
+
+	t.Run("touch does not overwrite a revocation", func(t *testing.T) {
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		token, _, err := model.NewAPIToken("siem", []model.TokenScope{model.ScopeReadIoCs}, "user:alice", time.Time{}, now)
+		gt.NoError(t, err)
+		gt.NoError(t, repo.PutAPIToken(ctx, token))
+
+		revoked := *token
+		revoked.RevokedAt = now.Add(time.Second)
+		gt.NoError(t, repo.PutAPIToken(ctx, &revoked))
+		gt.NoError(t, repo.TouchAPIToken(ctx, token.ID, now.Add(2*time.Second)))
+
+		got, err := repo.GetAPIToken(ctx, token.ID)
+		gt.NoError(t, err)
+		gt.True(t, got.Revoked())
+		gt.True(t, got.LastUsedAt.Equal(now.Add(2*time.Second)))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		id := time.Now().Format("z-missing-20060102-150405.000000")
+		_, err := repo.GetAPIToken(ctx, id)
+		gt.True(t, errors.Is(err, interfaces.ErrAPITokenNotFound))
+		err = repo.TouchAPIToken(ctx, id, time.Now())
+		gt.True(t, errors.Is(err, interfaces.ErrAPITokenNotFound))
+		gt.Error(t, repo.PutAPIToken(ctx, &model.APIToken{Name: "no-id"}))
+	})
+}
+
+func TestAPITokenRepository_Memory(t *testing.T) {
+	runAPITokenRepositoryTest(t, memory.New())
+}
+
+func TestAPITokenRepository_Firestore(t *testing.T) {
+	projectID := os.Getenv("TEST_FIRESTORE_PROJECT_ID")
+	databaseID := os.Getenv("TEST_FIRESTORE_DATABASE_ID")
+
+	if projectID == "" || databaseID == "" {
+		t.Skip("TEST_FIRESTORE_PROJECT_ID and TEST_FIRESTORE_DATABASE_ID environment variables not set")
+	}
+
+	ctx := context.Background()
+	repo, err := firestoreRepo.New(ctx, projectID, firestoreRepo.WithDatabaseID(databaseID))
+	if err != nil {
+		t.Fatalf("failed to create Firestore repository: %v", err)
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	runAPITokenRepositoryTest(t, repo)
+}
@@ -0,0 +1,129 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+)
+
+func runAuditRepositoryTest(t *testing.T, repo interfaces.AuditRepository) {
+	ctx := context.Background()
+
+	newRecord := func(actor string, action model.AuditAction, targetID, requestID string, at time.Time) *model.AuditRecord {
+		return &model.AuditRecord{
+			ID:         uuid.Must(uuid.NewV7()).String(),
+			Actor:      actor,
+			Action:     action,
+			TargetType: model.AuditTargetIoC,
+			TargetID:   targetID,
+			Changes:    []model.AuditChange{{Field: "Status", Before: `"active"`, After: `"false_positive"`}},
+			RequestID:  requestID,
+			CreatedAt:  at,
+		}
+	}
+
+	t.Run("save and list with filters", func(t *testing.T) {
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		// Unique values keep records of other runs out of the results
+		targetID := "ioc-" + uuid.NewString()
+		requestID := "req-" + uuid.NewString()
+		first := newRecord("user:alice", model.AuditIoCCreate, targetID, requestID, now.Add(-2*time.Minute))
+		second := newRecord("user:bob", model.AuditIoCUpdate, targetID, requestID, now.Add(-time.Minute))
+		third := newRecord("user:alice", model.AuditIoCDelete, targetID, "", now)
+		for _, r := range []*model.AuditRecord{first, second, third} {
+			gt.NoError(t, repo.SaveAuditRecord(ctx, r))
+		}
+
+		records, err := repo.ListAuditRecords(ctx, &model.AuditFilter{TargetID: targetID})
+		gt.NoError(t, err)
+		gt.A(t, records).Length(3)
+		gt.Equal(t, records[0].ID, third.ID)
+		gt.Equal(t, records[1].ID, second.ID)
+		gt.Equal(t, records[2].ID, first.ID)
+		gt.Equal(t, records[1].Changes, second.Changes)
+		gt.Equal(t, records[1].RequestID, requestID)
+		gt.True(t, records[1].CreatedAt.Equal(second.CreatedAt))
+
+		records, err = repo.ListAuditRecords(ctx, &model.AuditFilter{TargetID: targetID, Actor: "user:alice"})
+		gt.NoError(t, err)
+		gt.A(t, records).Length(2)
+		gt.Equal(t, records[0].ID, third.ID)
+
+		records, err = repo.ListAuditRecords(ctx, &model.AuditFilter{RequestID: requestID, Action: model.AuditIoCUpdate})
+		gt.NoError(t, err)
+		gt.A(t, records).Length(1)
+		gt.Equal(t, records[0].ID, second.ID)
+
+		records, err = repo.ListAuditRecords(ctx, &model.AuditFilter{
+			TargetID: targetID,
+			Since:    now.Add(-time.Minute),
+			Until:    now,
+		})
+		gt.NoError(t, err)
+		gt.A(t, records).Length(1)
+		gt.Equal(t, records[0].ID, second.ID)
+
+		records, err = repo.ListAuditRecords(ctx, &model.AuditFilter{TargetID: targetID, Limit: 2})
+		gt.NoError(t, err)
+		gt.A(t, records).Length(2)
+		gt.Equal(t, records[0].ID, third.ID)
+
+		// The limit applies after the equality filters beyond the first
+		records, err = repo.ListAuditRecords(ctx, &model.AuditFilter{TargetID: targetID, Actor: "user:bob", Limit: 1})
+		gt.NoError(t, err)
+		gt.A(t, records).Length(1)
+		gt.Equal(t, records[0].ID, second.ID)
+	})
+
+	t.Run("list by time range", func(t *testing.T) {
+		at := time.Now().UTC().Truncate(time.Millisecond).Add(time.Hour)
+		r := newRecord("system:fetch", model.AuditFetchRun, "src-"+uuid.NewString(), "", at)
+		gt.NoError(t, repo.SaveAuditRecord(ctx, r))
+
+		records, err := repo.ListAuditRecords(ctx, &model.AuditFilter{Since: at, Until: at.Add(time.Millisecond)})
+		gt.NoError(t, err)
+		gt.A(t, records).Length(1)
+		gt.Equal(t, records[0].ID, r.ID)
+	})
+
+	t.Run("records are append-only", func(t *testing.T) {
+		r := newRecord("user:alice", model.AuditIoCCreate, "ioc-"+uuid.NewString(), "", time.Now())
+		gt.NoError(t, repo.SaveAuditRecord(ctx, r))
+		gt.Error(t, repo.SaveAuditRecord(ctx, r))
+		gt.Error(t, repo.SaveAuditRecord(ctx, &model.AuditRecord{Action: model.AuditIoCCreate}))
+	})
+}
+
+func TestAuditRepository_Memory(t *testing.T) {
+	runAuditRepositoryTest(t, memory.New())
+}
+
+func TestAuditRepository_Firestore(t *testing.T) {
+	projectID := os.Getenv("TEST_FIRESTORE_PROJECT_ID")
+	databaseID := os.Getenv("TEST_FIRESTORE_DATABASE_ID")
+
+	if projectID == "" || databaseID == "" {
+		t.Skip("TEST_FIRESTORE_PROJECT_ID and TEST_FIRESTORE_DATABASE_ID environment variables not set")
+	}
+
+	ctx := context.Background()
+	repo, err := firestoreRepo.New(ctx, projectID, firestoreRepo.WithDatabaseID(databaseID))
+	if err != nil {
+		t.Fatalf("failed to create Firestore repository: %v", err)
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	runAuditRepositoryTest(t, repo)
+}
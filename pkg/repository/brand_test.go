@@ -0,0 +1,111 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+)
+
+func runBrandMatchRepositoryTest(t *testing.T, repo interfaces.BrandMatchRepository) {
+	ctx := context.Background()
+
+	// Unique per run so that Firestore tests do not see each other's data
+	protected := time.Now().Format("p20060102150405000000") + ".example"
+
+	newMatch := func(iocID string, technique model.LookalikeTechnique, createdAt time.Time) *model.BrandMatch {
+		return &model.BrandMatch{
+			ID:              model.BrandMatchID(protected, iocID),
+			ProtectedDomain: protected,
+			Domain:          iocID + ".example",
+			IoCID:           iocID,
+			IoCType:         model.IoCTypeDomain,
+			IoCValue:        iocID + ".example",
+			SourceID:        "test-source",
+			Technique:       technique,
+			EditDistance:    1,
+			Score:           0.8,
+			CreatedAt:       createdAt.UTC().Truncate(time.Millisecond),
+		}
+	}
+
+	base := time.Now()
+	stored, err := repo.SaveBrandMatches(ctx, []*model.BrandMatch{
+		newMatch("old", model.LookalikeTyposquat, base.Add(-2*time.Hour)),
+		newMatch("mid", model.LookalikeIDN, base.Add(-time.Hour)),
+		newMatch("new", model.LookalikeTyposquat, base),
+	})
+	gt.NoError(t, err)
+	gt.A(t, stored).Length(3)
+
+	t.Run("matches are created once", func(t *testing.T) {
+		again := newMatch("new", model.LookalikeCombosquat, base.Add(time.Hour))
+		stored, err := repo.SaveBrandMatches(ctx, []*model.BrandMatch{again})
+		gt.NoError(t, err)
+		gt.A(t, stored).Length(0)
+	})
+
+	t.Run("list newest first", func(t *testing.T) {
+		conn, err := repo.ListBrandMatches(ctx, &model.BrandMatchListOptions{ProtectedDomain: protected})
+		gt.NoError(t, err)
+		gt.Equal(t, conn.Total, 3)
+		gt.A(t, conn.Items).Length(3).
+			At(0, func(t testing.TB, m *model.BrandMatch) {
+				gt.Equal(t, m.IoCID, "new")
+				gt.Equal(t, m.Technique, model.LookalikeTyposquat)
+				gt.Equal(t, m.Score, 0.8)
+			}).
+			At(2, func(t testing.TB, m *model.BrandMatch) { gt.Equal(t, m.IoCID, "old") })
+	})
+
+	t.Run("filter by technique with pagination", func(t *testing.T) {
+		conn, err := repo.ListBrandMatches(ctx, &model.BrandMatchListOptions{
+			ProtectedDomain: protected,
+			Technique:       model.LookalikeTyposquat,
+			Offset:          1,
+			Limit:           1,
+		})
+		gt.NoError(t, err)
+		gt.Equal(t, conn.Total, 2)
+		gt.A(t, conn.Items).Length(1).At(0, func(t testing.TB, m *model.BrandMatch) {
+			gt.Equal(t, m.IoCID, "old")
+		})
+	})
+
+	t.Run("empty ID is rejected", func(t *testing.T) {
+		_, err := repo.SaveBrandMatches(ctx, []*model.BrandMatch{{IoCID: "x"}})
+		gt.Error(t, err)
+	})
+}
+
+func TestBrandMatchRepository_Memory(t *testing.T) {
+	runBrandMatchRepositoryTest(t, memory.New())
+}
+
+func TestBrandMatchRepository_Firestore(t *testing.T) {
+	projectID := os.Getenv("TEST_FIRESTORE_PROJECT_ID")
+	databaseID := os.Getenv("TEST_FIRESTORE_DATABASE_ID")
+
+	if projectID == "" || databaseID == "" {
+		t.Skip("TEST_FIRESTORE_PROJECT_ID and TEST_FIRESTORE_DATABASE_ID environment variables not set")
+	}
+
+	ctx := context.Background()
+	repo, err := firestoreRepo.New(ctx, projectID, firestoreRepo.WithDatabaseID(databaseID))
+	if err != nil {
+		t.Fatalf("failed to create Firestore repository: %v", err)
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	runBrandMatchRepositoryTest(t, repo)
+}
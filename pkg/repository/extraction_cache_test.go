@@ -0,0 +1,168 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/filecache"
+	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+)
+
+func runExtractionCacheRepositoryTest(t *testing.T, repo interfaces.ExtractionCacheRepository) {
+	ctx := context.Background()
+
+	// Unique per run so that Firestore tests do not see each other's data
+	prefix := time.Now().Format("cache20060102150405000000")
+	sourceID := prefix + "-source"
+	otherSourceID := prefix + "-other"
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	newEntry := func(key, sourceID string, expiresAt time.Time) *model.ExtractionCacheEntry {
+		return &model.ExtractionCacheEntry{
+			Key:           prefix + key,
+			SourceID:      sourceID,
+			Mode:          model.ExtractionModeCrossCheck,
+			PromptVersion: "0123456789ab",
+			Model:         "gemini/default",
+			IoCs: []*model.CachedIoC{
+				{Type: "domain", Value: "c2.example.com", Description: "C2 server"},
+			},
+			Unverified: []*model.CachedIoC{
+				{Type: "ipv4", Value: "192.0.2.1", Description: "Not in article"},
+			},
+			ExpiresAt: expiresAt,
+		}
+	}
+
+	fresh := newEntry("fresh", sourceID, now.Add(time.Hour))
+	expired := newEntry("expired", sourceID, now.Add(-time.Hour))
+	permanent := newEntry("permanent", sourceID, time.Time{})
+	other := newEntry("other", otherSourceID, now.Add(-time.Hour))
+	for _, e := range []*model.ExtractionCacheEntry{fresh, expired, permanent, other} {
+		gt.NoError(t, repo.PutExtractionCache(ctx, e))
+	}
+
+	t.Run("get entry", func(t *testing.T) {
+		got, err := repo.GetExtractionCache(ctx, fresh.Key)
+		gt.NoError(t, err)
+		gt.Equal(t, got.SourceID, sourceID)
+		gt.Equal(t, got.Mode, model.ExtractionModeCrossCheck)
+		gt.Equal(t, got.Model, "gemini/default")
+		gt.A(t, got.IoCs).Length(1).At(0, func(t testing.TB, ioc *model.CachedIoC) {
+			gt.Equal(t, ioc.Value, "c2.example.com")
+			gt.Equal(t, ioc.Description, "C2 server")
+		})
+		gt.A(t, got.Unverified).Length(1)
+		gt.True(t, got.ExpiresAt.Equal(fresh.ExpiresAt))
+		gt.False(t, got.CreatedAt.IsZero())
+		gt.False(t, got.Expired(now))
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := repo.GetExtractionCache(ctx, prefix+"missing")
+		gt.True(t, errors.Is(err, interfaces.ErrExtractionCacheNotFound))
+	})
+
+	t.Run("put replaces entry", func(t *testing.T) {
+		updated := newEntry("fresh", sourceID, now.Add(time.Hour))
+		updated.IoCs = nil
+		gt.NoError(t, repo.PutExtractionCache(ctx, updated))
+
+		got, err := repo.GetExtractionCache(ctx, fresh.Key)
+		gt.NoError(t, err)
+		gt.A(t, got.IoCs).Length(0)
+	})
+
+	t.Run("purge expired entries of a source", func(t *testing.T) {
+		deleted, err := repo.PurgeExtractionCache(ctx, &model.ExtractionCachePurgeOptions{
+			SourceID: sourceID,
+			Before:   now,
+		})
+		gt.NoError(t, err)
+		gt.Equal(t, deleted, 1)
+
+		_, err = repo.GetExtractionCache(ctx, expired.Key)
+		gt.True(t, errors.Is(err, interfaces.ErrExtractionCacheNotFound))
+		for _, e := range []*model.ExtractionCacheEntry{fresh, permanent, other} {
+			_, err := repo.GetExtractionCache(ctx, e.Key)
+			gt.NoError(t, err)
+		}
+	})
+
+	t.Run("purge entries of a source", func(t *testing.T) {
+		deleted, err := repo.PurgeExtractionCache(ctx, &model.ExtractionCachePurgeOptions{SourceID: sourceID})
+		gt.NoError(t, err)
+		gt.Equal(t, deleted, 2)
+
+		_, err = repo.GetExtractionCache(ctx, other.Key)
+		gt.NoError(t, err)
+	})
+
+	t.Run("purge cleans up", func(t *testing.T) {
+		_, err := repo.PurgeExtractionCache(ctx, &model.ExtractionCachePurgeOptions{SourceID: otherSourceID})
+		gt.NoError(t, err)
+		_, err = repo.GetExtractionCache(ctx, other.Key)
+		gt.True(t, errors.Is(err, interfaces.ErrExtractionCacheNotFound))
+	})
+
+	t.Run("empty key is rejected", func(t *testing.T) {
+		gt.Error(t, repo.PutExtractionCache(ctx, &model.ExtractionCacheEntry{SourceID: sourceID}))
+	})
+}
+
+func TestExtractionCacheRepository_Memory(t *testing.T) {
+	repo := memory.New()
+	runExtractionCacheRepositoryTest(t, repo)
+
+	t.Run("empty options purge all entries", func(t *testing.T) {
+		ctx := context.Background()
+		gt.NoError(t, repo.PutExtractionCache(ctx, &model.ExtractionCacheEntry{Key: "a"}))
+		gt.NoError(t, repo.PutExtractionCache(ctx, &model.ExtractionCacheEntry{Key: "b"}))
+
+		deleted, err := repo.PurgeExtractionCache(ctx, &model.ExtractionCachePurgeOptions{})
+		gt.NoError(t, err)
+		gt.Equal(t, deleted, 2)
+	})
+}
+
+func TestExtractionCacheRepository_FileCache(t *testing.T) {
+	repo, err := filecache.New(t.TempDir())
+	gt.NoError(t, err)
+	runExtractionCacheRepositoryTest(t, repo)
+
+	t.Run("keys with path elements are rejected", func(t *testing.T) {
+		ctx := context.Background()
+		gt.Error(t, repo.PutExtractionCache(ctx, &model.ExtractionCacheEntry{Key: "../escape"}))
+		_, err := repo.GetExtractionCache(ctx, "../escape")
+		gt.Error(t, err)
+	})
+}
+
+func TestExtractionCacheRepository_Firestore(t *testing.T) {
+	projectID := os.Getenv("TEST_FIRESTORE_PROJECT_ID")
+	databaseID := os.Getenv("TEST_FIRESTORE_DATABASE_ID")
+
+	if projectID == "" || databaseID == "" {
+		t.Skip("TEST_FIRESTORE_PROJECT_ID and TEST_FIRESTORE_DATABASE_ID environment variables not set")
+	}
+
+	ctx := context.Background()
+	repo, err := firestoreRepo.New(ctx, projectID, firestoreRepo.WithDatabaseID(databaseID))
+	if err != nil {
+		t.Fatalf("failed to create Firestore repository: %v", err)
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	runExtractionCacheRepositoryTest(t, repo)
+}
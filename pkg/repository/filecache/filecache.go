@@ -0,0 +1,142 @@
+// Package filecache stores the LLM extraction cache as JSON files in a local
+// directory, for running without a shared repository backend.
+package filecache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+const fileExt = ".json"
+
+// FileCache is an extraction cache with one JSON file per entry
+type FileCache struct {
+	dir string
+}
+
+var _ interfaces.ExtractionCacheRepository = &FileCache{}
+
+// New creates a file cache in dir, creating the directory if needed
+func New(dir string) (*FileCache, error) {
+	if dir == "" {
+		return nil, goerr.New("cache directory cannot be empty")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, goerr.Wrap(err, "failed to create cache directory", goerr.V("dir", dir))
+	}
+	return &FileCache{dir: dir}, nil
+}
+
+func (c *FileCache) path(key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, `/\.`) {
+		return "", goerr.New("invalid extraction cache key", goerr.V("key", key))
+	}
+	return filepath.Join(c.dir, key+fileExt), nil
+}
+
+// GetExtractionCache reads a cache entry by key
+func (c *FileCache) GetExtractionCache(ctx context.Context, key string) (*model.ExtractionCacheEntry, error) {
+	path, err := c.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := readEntry(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, goerr.Wrap(interfaces.ErrExtractionCacheNotFound, "extraction cache entry not found", goerr.V("key", key))
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// PutExtractionCache writes a cache entry. The file is replaced atomically so
+// that concurrent readers never see a partial entry.
+func (c *FileCache) PutExtractionCache(ctx context.Context, entry *model.ExtractionCacheEntry) error {
+	path, err := c.path(entry.Key)
+	if err != nil {
+		return err
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return goerr.Wrap(err, "failed to encode extraction cache entry", goerr.V("key", entry.Key))
+	}
+
+	tmp, err := os.CreateTemp(c.dir, entry.Key+".*.tmp")
+	if err != nil {
+		return goerr.Wrap(err, "failed to create cache file", goerr.V("dir", c.dir))
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		return goerr.Wrap(err, "failed to write cache file", goerr.V("path", tmp.Name()))
+	}
+	if err := tmp.Close(); err != nil {
+		return goerr.Wrap(err, "failed to close cache file", goerr.V("path", tmp.Name()))
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return goerr.Wrap(err, "failed to rename cache file", goerr.V("path", path))
+	}
+	return nil
+}
+
+// PurgeExtractionCache deletes the cache files of the entries selected by opts
+func (c *FileCache) PurgeExtractionCache(ctx context.Context, opts *model.ExtractionCachePurgeOptions) (int, error) {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return 0, goerr.Wrap(err, "failed to read cache directory", goerr.V("dir", c.dir))
+	}
+
+	deleted := 0
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), fileExt) {
+			continue
+		}
+		path := filepath.Join(c.dir, file.Name())
+
+		entry, err := readEntry(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return deleted, err
+		}
+		if !opts.Matches(entry) {
+			continue
+		}
+
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return deleted, goerr.Wrap(err, "failed to delete cache file", goerr.V("path", path))
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+func readEntry(path string) (*model.ExtractionCacheEntry, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- path is built from a validated key
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read cache file", goerr.V("path", path))
+	}
+
+	var entry model.ExtractionCacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, goerr.Wrap(err, "failed to decode cache file", goerr.V("path", path))
+	}
+	return &entry, nil
+}
@@ -0,0 +1,102 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const collectionAPITokens = "api_tokens"
+
+var _ interfaces.APITokenRepository = &Firestore{}
+
+// PutAPIToken creates or replaces an API token
+func (f *Firestore) PutAPIToken(ctx context.Context, token *model.APIToken) error {
+	if token.ID == "" {
+		return goerr.New("API token ID cannot be empty")
+	}
+
+	if _, err := f.client.Collection(collectionAPITokens).Doc(token.ID).Set(ctx, token); err != nil {
+		return goerr.Wrap(err, "failed to put API token", goerr.V("id", token.ID))
+	}
+	return nil
+}
+
+// GetAPIToken retrieves an API token by ID
+func (f *Firestore) GetAPIToken(ctx context.Context, id string) (*model.APIToken, error) {
+	doc, err := f.client.Collection(collectionAPITokens).Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, goerr.Wrap(interfaces.ErrAPITokenNotFound, "API token not found", goerr.V("id", id))
+		}
+		return nil, goerr.Wrap(err, "failed to get API token", goerr.V("id", id))
+	}
+
+	var t model.APIToken
+	if err := doc.DataTo(&t); err != nil {
+		return nil, goerr.Wrap(err, "failed to decode API token", goerr.V("id", id))
+	}
+	return &t, nil
+}
+
+// GetAPITokenByHash retrieves the API token with a hash
+func (f *Firestore) GetAPITokenByHash(ctx context.Context, hash string) (*model.APIToken, error) {
+	docs, err := f.client.Collection(collectionAPITokens).
+		Where("Hash", "==", hash).
+		Limit(1).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to find API token")
+	}
+	if len(docs) == 0 {
+		return nil, goerr.Wrap(interfaces.ErrAPITokenNotFound, "API token not found")
+	}
+
+	var t model.APIToken
+	if err := docs[0].DataTo(&t); err != nil {
+		return nil, goerr.Wrap(err, "failed to decode API token", goerr.V("doc_id", docs[0].Ref.ID))
+	}
+	return &t, nil
+}
+
+// ListAPITokens retrieves all API tokens, newest first
+func (f *Firestore) ListAPITokens(ctx context.Context) ([]*model.APIToken, error) {
+	docs, err := f.client.Collection(collectionAPITokens).
+		OrderBy("CreatedAt", firestore.Desc).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list API tokens")
+	}
+
+	result := make([]*model.APIToken, 0, len(docs))
+	for _, doc := range docs {
+		var t model.APIToken
+		if err := doc.DataTo(&t); err != nil {
+			return nil, goerr.Wrap(err, "failed to decode API token", goerr.V("doc_id", doc.Ref.ID))
+		}
+		result = append(result, &t)
+	}
+	return result, nil
+}
+
+// TouchAPIToken updates only the last-used time of an API token
+func (f *Firestore) TouchAPIToken(ctx context.Context, id string, at time.Time) error {
+	_, err := f.client.Collection(collectionAPITokens).Doc(id).Update(ctx, []firestore.Update{
+		{Path: "LastUsedAt", Value: at},
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return goerr.Wrap(interfaces.ErrAPITokenNotFound, "API token not found", goerr.V("id", id))
+		}
+		return goerr.Wrap(err, "failed to update API token", goerr.V("id", id))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package firestore
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+const collectionAuditRecords = "audit_records"
+
+var _ interfaces.AuditRepository = &Firestore{}
+
+// SaveAuditRecord appends an audit record
+func (f *Firestore) SaveAuditRecord(ctx context.Context, record *model.AuditRecord) error {
+	if record.ID == "" {
+		return goerr.New("audit record ID cannot be empty", goerr.V("action", record.Action))
+	}
+
+	if _, err := f.client.Collection(collectionAuditRecords).Doc(record.ID).Create(ctx, record); err != nil {
+		return goerr.Wrap(err, "failed to save audit record",
+			goerr.V("id", record.ID),
+			goerr.V("action", record.Action))
+	}
+	return nil
+}
+
+// ListAuditRecords retrieves the audit records matching filter, newest first.
+// Records are queried by the first equality filter of the filter, the
+// creation time range, order and limit; further equality filters are applied
+// after fetching, without a limit in the query.
+func (f *Firestore) ListAuditRecords(ctx context.Context, filter *model.AuditFilter) ([]*model.AuditRecord, error) {
+	if filter == nil {
+		filter = &model.AuditFilter{}
+	}
+
+	equalities := []struct {
+		path  string
+		value string
+	}{
+		{"TargetID", filter.TargetID},
+		{"RequestID", filter.RequestID},
+		{"Actor", filter.Actor},
+		{"Action", string(filter.Action)},
+		{"TargetType", filter.TargetType},
+	}
+	query := f.client.Collection(collectionAuditRecords).Query
+	var filtered int
+	for _, eq := range equalities {
+		if eq.value == "" {
+			continue
+		}
+		if filtered == 0 {
+			query = query.Where(eq.path, "==", eq.value)
+		}
+		filtered++
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("CreatedAt", ">=", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("CreatedAt", "<", filter.Until)
+	}
+	query = query.OrderBy("CreatedAt", firestore.Desc)
+	if filter.Limit > 0 && filtered <= 1 {
+		query = query.Limit(filter.Limit)
+	}
+
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list audit records")
+	}
+
+	var result []*model.AuditRecord
+	for _, doc := range docs {
+		var r model.AuditRecord
+		if err := doc.DataTo(&r); err != nil {
+			return nil, goerr.Wrap(err, "failed to decode audit record", goerr.V("doc_id", doc.Ref.ID))
+		}
+		if filter.Match(&r) {
+			result = append(result, &r)
+		}
+	}
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
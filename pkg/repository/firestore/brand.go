@@ -0,0 +1,110 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	firestorepb "cloud.google.com/go/firestore/apiv1/firestorepb"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const collectionBrandMatches = "brand_matches"
+
+var _ interfaces.BrandMatchRepository = &Firestore{}
+
+// SaveBrandMatches stores matches that do not exist yet and returns them
+func (f *Firestore) SaveBrandMatches(ctx context.Context, matches []*model.BrandMatch) ([]*model.BrandMatch, error) {
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	bulkWriter := f.client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, len(matches))
+	for i, match := range matches {
+		if match.ID == "" {
+			bulkWriter.End()
+			return nil, goerr.New("brand match ID cannot be empty", goerr.V("ioc_id", match.IoCID))
+		}
+
+		docRef := f.client.Collection(collectionBrandMatches).Doc(match.ID)
+		job, err := bulkWriter.Create(docRef, match)
+		if err != nil {
+			bulkWriter.End()
+			return nil, goerr.Wrap(err, "failed to add brand match to bulk writer",
+				goerr.V("match_id", match.ID))
+		}
+		jobs[i] = job
+	}
+
+	bulkWriter.Flush()
+	bulkWriter.End()
+
+	var stored []*model.BrandMatch
+	for i, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			if status.Code(err) == codes.AlreadyExists {
+				continue
+			}
+			return stored, goerr.Wrap(err, "failed to save brand match", goerr.V("match_id", matches[i].ID))
+		}
+		stored = append(stored, matches[i])
+	}
+	return stored, nil
+}
+
+// ListBrandMatches retrieves brand matches, newest first
+func (f *Firestore) ListBrandMatches(ctx context.Context, opts *model.BrandMatchListOptions) (*model.BrandMatchConnection, error) {
+	if opts == nil {
+		opts = &model.BrandMatchListOptions{}
+	}
+
+	query := f.client.Collection(collectionBrandMatches).Query
+	if opts.ProtectedDomain != "" {
+		query = query.Where("ProtectedDomain", "==", opts.ProtectedDomain)
+	}
+	if opts.Technique != "" {
+		query = query.Where("Technique", "==", string(opts.Technique))
+	}
+
+	aggregationResults, err := query.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to count brand matches")
+	}
+	pbValue, ok := aggregationResults["total"].(*firestorepb.Value)
+	if !ok {
+		return nil, goerr.New("total count has unexpected type",
+			goerr.V("type", fmt.Sprintf("%T", aggregationResults["total"])))
+	}
+
+	query = query.OrderBy("CreatedAt", firestore.Desc)
+	if opts.Offset > 0 {
+		query = query.Offset(opts.Offset)
+	}
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list brand matches")
+	}
+
+	items := make([]*model.BrandMatch, 0, len(docs))
+	for _, doc := range docs {
+		var match model.BrandMatch
+		if err := doc.DataTo(&match); err != nil {
+			return nil, goerr.Wrap(err, "failed to decode brand match", goerr.V("doc_id", doc.Ref.ID))
+		}
+		items = append(items, &match)
+	}
+
+	return &model.BrandMatchConnection{
+		Items: items,
+		Total: int(pbValue.GetIntegerValue()),
+	}, nil
+}
@@ -0,0 +1,100 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const collectionExtractionCache = "extraction_cache"
+
+var _ interfaces.ExtractionCacheRepository = &Firestore{}
+
+// GetExtractionCache retrieves a cache entry by key
+func (f *Firestore) GetExtractionCache(ctx context.Context, key string) (*model.ExtractionCacheEntry, error) {
+	doc, err := f.client.Collection(collectionExtractionCache).Doc(key).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, goerr.Wrap(interfaces.ErrExtractionCacheNotFound, "extraction cache entry not found", goerr.V("key", key))
+		}
+		return nil, goerr.Wrap(err, "failed to get extraction cache entry", goerr.V("key", key))
+	}
+
+	var entry model.ExtractionCacheEntry
+	if err := doc.DataTo(&entry); err != nil {
+		return nil, goerr.Wrap(err, "failed to decode extraction cache entry", goerr.V("key", key))
+	}
+	return &entry, nil
+}
+
+// PutExtractionCache creates or replaces a cache entry
+func (f *Firestore) PutExtractionCache(ctx context.Context, entry *model.ExtractionCacheEntry) error {
+	if entry.Key == "" {
+		return goerr.New("extraction cache key cannot be empty", goerr.V("source_id", entry.SourceID))
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	if _, err := f.client.Collection(collectionExtractionCache).Doc(entry.Key).Set(ctx, entry); err != nil {
+		return goerr.Wrap(err, "failed to put extraction cache entry", goerr.V("key", entry.Key))
+	}
+	return nil
+}
+
+// PurgeExtractionCache deletes the cache entries selected by opts. Only one
+// condition is queried so that no composite index is needed; the other is
+// checked on the fetched entries.
+func (f *Firestore) PurgeExtractionCache(ctx context.Context, opts *model.ExtractionCachePurgeOptions) (int, error) {
+	query := f.client.Collection(collectionExtractionCache).Query
+	switch {
+	case opts != nil && opts.SourceID != "":
+		query = query.Where("SourceID", "==", opts.SourceID)
+	case opts != nil && !opts.Before.IsZero():
+		query = query.Where("ExpiresAt", "<=", opts.Before)
+	}
+
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return 0, goerr.Wrap(err, "failed to list extraction cache entries")
+	}
+
+	bulkWriter := f.client.BulkWriter(ctx)
+	var jobs []*firestore.BulkWriterJob
+	for _, doc := range docs {
+		var entry model.ExtractionCacheEntry
+		if err := doc.DataTo(&entry); err != nil {
+			bulkWriter.End()
+			return 0, goerr.Wrap(err, "failed to decode extraction cache entry", goerr.V("doc_id", doc.Ref.ID))
+		}
+		if !opts.Matches(&entry) {
+			continue
+		}
+
+		job, err := bulkWriter.Delete(doc.Ref)
+		if err != nil {
+			bulkWriter.End()
+			return 0, goerr.Wrap(err, "failed to add extraction cache deletion to bulk writer",
+				goerr.V("doc_id", doc.Ref.ID))
+		}
+		jobs = append(jobs, job)
+	}
+
+	bulkWriter.Flush()
+	bulkWriter.End()
+
+	deleted := 0
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			return deleted, goerr.Wrap(err, "failed to delete extraction cache entry")
+		}
+		deleted++
+	}
+	return deleted, nil
+}
@@ -2,7 +2,10 @@ package firestore
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"sort"
 	"time"
 
 	"cloud.google.com/go/firestore"
@@ -15,18 +18,23 @@ import (
 )
 
 const (
-	collectionIoCs         = "iocs"
-	collectionSources      = "sources"
-	subCollectionHistories = "histories"
+	collectionIoCs                 = "iocs"
+	collectionSources              = "sources"
+	collectionNotifications        = "notifications"
+	subCollectionHistories         = "histories"
+	subCollectionStatusTransitions = "status_transitions"
 )
 
 type Firestore struct {
-	client *firestore.Client
+	client       *firestore.Client
+	embeddingDim int // dimension of vector search queries
 }
 
 var _ interfaces.IoCRepository = &Firestore{}
+var _ interfaces.IoCStatusRepository = &Firestore{}
 var _ interfaces.SourceStateRepository = &Firestore{}
 var _ interfaces.HistoryRepository = &Firestore{}
+var _ interfaces.NotificationRepository = &Firestore{}
 
 func New(ctx context.Context, projectID string, opts ...Option) (*Firestore, error) {
 	var options options
@@ -51,13 +59,20 @@ func New(ctx context.Context, projectID string, opts ...Option) (*Firestore, err
 			goerr.V("database_id", options.databaseID))
 	}
 
+	embeddingDim := options.embeddingDim
+	if embeddingDim == 0 {
+		embeddingDim = model.EmbeddingDimension
+	}
+
 	return &Firestore{
-		client: client,
+		client:       client,
+		embeddingDim: embeddingDim,
 	}, nil
 }
 
 type options struct {
-	databaseID string
+	databaseID   string
+	embeddingDim int
 }
 
 type Option func(*options)
@@ -69,6 +84,15 @@ func WithDatabaseID(databaseID string) Option {
 	}
 }
 
+// WithEmbeddingDimension sets the embedding dimension of vector search
+// (default: model.EmbeddingDimension). It must match the vector index created by
+// migration.MigrateFirestore.
+func WithEmbeddingDimension(dim int) Option {
+	return func(o *options) {
+		o.embeddingDim = dim
+	}
+}
+
 func (f *Firestore) Close() error {
 	if f.client != nil {
 		return f.client.Close()
@@ -76,6 +100,15 @@ func (f *Firestore) Close() error {
 	return nil
 }
 
+// Ping reads the reference of a single IoC document to check that the
+// database is reachable and readable
+func (f *Firestore) Ping(ctx context.Context) error {
+	if _, err := f.client.Collection(collectionIoCs).Select().Limit(1).Documents(ctx).GetAll(); err != nil {
+		return goerr.Wrap(err, "failed to read from Firestore")
+	}
+	return nil
+}
+
 // GetIoC retrieves an IoC by ID
 func (f *Firestore) GetIoC(ctx context.Context, id string) (*model.IoC, error) {
 	doc, err := f.client.Collection(collectionIoCs).Doc(id).Get(ctx)
@@ -144,6 +177,14 @@ func (f *Firestore) ListAllIoCs(ctx context.Context) ([]*model.IoC, error) {
 func (f *Firestore) ListIoCs(ctx context.Context, opts *model.IoCListOptions) (*model.IoCConnection, error) {
 	// Start with base query
 	query := f.client.Collection(collectionIoCs).Query
+	if opts != nil && opts.MinConfidence > 0 {
+		query = query.Where("Confidence", ">=", opts.MinConfidence)
+	}
+	if opts != nil && len(opts.Types) > 0 {
+		query = query.Where("Type", "in", opts.Types)
+	}
+	// Keep the unsorted, filtered query for counting
+	countQuery := query
 
 	// Apply sorting
 	if opts != nil && opts.SortField != "" {
@@ -154,9 +195,8 @@ func (f *Firestore) ListIoCs(ctx context.Context, opts *model.IoCListOptions) (*
 		query = query.OrderBy("UpdatedAt", firestore.Desc)
 	}
 
-	// Get total count using aggregation query
-	// NOTE: If filters are added in the future, the same filters must be applied here
-	aggregationQuery := f.client.Collection(collectionIoCs).NewAggregationQuery().WithCount("total")
+	// Get total count using aggregation query with the same filters
+	aggregationQuery := countQuery.NewAggregationQuery().WithCount("total")
 	aggregationResults, err := aggregationQuery.Get(ctx)
 	if err != nil {
 		return nil, goerr.Wrap(err, "failed to get total count")
@@ -216,6 +256,195 @@ func (f *Firestore) ListIoCs(ctx context.Context, opts *model.IoCListOptions) (*
 	}, nil
 }
 
+// ListIoCsByTag lists the IoCs matching a tag filter. IoCs tagged by analysts
+// and IoCs of the tagged sources are queried separately and merged.
+func (f *Firestore) ListIoCsByTag(ctx context.Context, filter *model.IoCTagFilter) ([]*model.IoC, error) {
+	const inLimit = 30
+
+	base := f.client.Collection(collectionIoCs).Query
+	if filter.Status != "" {
+		base = base.Where("Status", "==", string(filter.Status))
+	}
+	if !filter.UpdatedSince.IsZero() {
+		base = base.Where("UpdatedAt", ">=", filter.UpdatedSince)
+	}
+	if filter.MinConfidence > 0 {
+		base = base.Where("Confidence", ">=", filter.MinConfidence)
+	}
+
+	queries := []firestore.Query{base.Where("Tags", "array-contains", filter.Tag)}
+	for i := 0; i < len(filter.SourceIDs); i += inLimit {
+		chunk := filter.SourceIDs[i:min(i+inLimit, len(filter.SourceIDs))]
+		queries = append(queries, base.Where("SourceID", "in", chunk))
+	}
+
+	seen := make(map[string]bool)
+	var iocs []*model.IoC
+	for _, query := range queries {
+		docs, err := query.Documents(ctx).GetAll()
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to query IoCs by tag",
+				goerr.V("tag", filter.Tag))
+		}
+
+		for _, doc := range docs {
+			if seen[doc.Ref.ID] {
+				continue
+			}
+			seen[doc.Ref.ID] = true
+
+			var ioc model.IoC
+			if err := doc.DataTo(&ioc); err != nil {
+				return nil, goerr.Wrap(err, "failed to decode IoC",
+					goerr.V("doc_id", doc.Ref.ID))
+			}
+			iocs = append(iocs, &ioc)
+		}
+	}
+
+	return iocs, nil
+}
+
+// ListExpiredIoCs lists active IoCs whose expiry time has passed.
+// IoCs without expiry keep a zero ExpiresAt and are excluded by the lower bound.
+func (f *Firestore) ListExpiredIoCs(ctx context.Context, now time.Time) ([]*model.IoC, error) {
+	docs, err := f.client.Collection(collectionIoCs).
+		Where("Status", "==", string(model.IoCStatusActive)).
+		Where("ExpiresAt", ">", time.Time{}).
+		Where("ExpiresAt", "<=", now).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list expired IoCs",
+			goerr.V("now", now))
+	}
+
+	var iocs []*model.IoC
+	for _, doc := range docs {
+		var ioc model.IoC
+		if err := doc.DataTo(&ioc); err != nil {
+			return nil, goerr.Wrap(err, "failed to decode IoC",
+				goerr.V("doc_id", doc.Ref.ID))
+		}
+		iocs = append(iocs, &ioc)
+	}
+
+	return iocs, nil
+}
+
+// ListSourceIDsByValues returns the distinct source IDs reporting each value.
+// Values are queried in chunks because Firestore limits "in" filters to 30 values.
+func (f *Firestore) ListSourceIDsByValues(ctx context.Context, iocType model.IoCType, values []string) (map[string][]string, error) {
+	const inLimit = 30
+
+	seen := make(map[string]map[string]bool)
+	for i := 0; i < len(values); i += inLimit {
+		chunk := values[i:min(i+inLimit, len(values))]
+
+		docs, err := f.client.Collection(collectionIoCs).
+			Where("Type", "==", string(iocType)).
+			Where("Value", "in", chunk).
+			Select("SourceID", "Value").
+			Documents(ctx).
+			GetAll()
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to query IoCs by value",
+				goerr.V("type", iocType),
+				goerr.V("chunk_start", i))
+		}
+
+		for _, doc := range docs {
+			var ioc model.IoC
+			if err := doc.DataTo(&ioc); err != nil {
+				return nil, goerr.Wrap(err, "failed to decode IoC",
+					goerr.V("doc_id", doc.Ref.ID))
+			}
+			if seen[ioc.Value] == nil {
+				seen[ioc.Value] = make(map[string]bool)
+			}
+			seen[ioc.Value][ioc.SourceID] = true
+		}
+	}
+
+	result := make(map[string][]string, len(seen))
+	for value, sources := range seen {
+		for sourceID := range sources {
+			result[value] = append(result[value], sourceID)
+		}
+		sort.Strings(result[value])
+	}
+
+	return result, nil
+}
+
+// UpdateIoCEmbeddings sets the embedding of IoCs without changing UpdatedAt
+func (f *Firestore) UpdateIoCEmbeddings(ctx context.Context, embeddings map[string]*model.IoCEmbedding) error {
+	if len(embeddings) == 0 {
+		return nil
+	}
+
+	bulkWriter := f.client.BulkWriter(ctx)
+	jobs := make(map[string]*firestore.BulkWriterJob, len(embeddings))
+	for id, embedding := range embeddings {
+		docRef := f.client.Collection(collectionIoCs).Doc(id)
+		job, err := bulkWriter.Update(docRef, []firestore.Update{
+			{Path: "Embedding", Value: firestore.Vector32(embedding.Vector)},
+			{Path: "EmbeddingModel", Value: embedding.Model},
+			{Path: "EmbeddingDimension", Value: len(embedding.Vector)},
+		})
+		if err != nil {
+			bulkWriter.End()
+			return goerr.Wrap(err, "failed to add embedding update to bulk writer",
+				goerr.V("ioc_id", id))
+		}
+		jobs[id] = job
+	}
+
+	bulkWriter.Flush()
+	bulkWriter.End()
+
+	for id, job := range jobs {
+		if _, err := job.Results(); err != nil && status.Code(err) != codes.NotFound {
+			return goerr.Wrap(err, "failed to update IoC embedding", goerr.V("ioc_id", id))
+		}
+	}
+
+	return nil
+}
+
+// UpdateIoCConfidences sets the confidence score of IoCs without changing UpdatedAt
+func (f *Firestore) UpdateIoCConfidences(ctx context.Context, scores map[string]int) error {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	bulkWriter := f.client.BulkWriter(ctx)
+	jobs := make(map[string]*firestore.BulkWriterJob, len(scores))
+	for id, score := range scores {
+		docRef := f.client.Collection(collectionIoCs).Doc(id)
+		job, err := bulkWriter.Update(docRef, []firestore.Update{
+			{Path: "Confidence", Value: score},
+		})
+		if err != nil {
+			bulkWriter.End()
+			return goerr.Wrap(err, "failed to add confidence update to bulk writer",
+				goerr.V("ioc_id", id))
+		}
+		jobs[id] = job
+	}
+
+	bulkWriter.Flush()
+	bulkWriter.End()
+
+	for id, job := range jobs {
+		if _, err := job.Results(); err != nil && status.Code(err) != codes.NotFound {
+			return goerr.Wrap(err, "failed to update IoC confidence", goerr.V("ioc_id", id))
+		}
+	}
+
+	return nil
+}
+
 // getSortParams converts domain sort field to Firestore field path and direction
 func getSortParams(sortField model.IoCSortField, sortOrder model.SortOrder) (string, firestore.Direction) {
 	direction := firestore.Asc
@@ -237,6 +466,8 @@ func getSortParams(sortField model.IoCSortField, sortOrder model.SortOrder) (str
 		fieldPath = "FirstSeenAt"
 	case model.IoCSortByUpdatedAt:
 		fieldPath = "UpdatedAt"
+	case model.IoCSortByConfidence:
+		fieldPath = "Confidence"
 	default:
 		fieldPath = "UpdatedAt"
 		direction = firestore.Desc
@@ -273,13 +504,20 @@ func (f *Firestore) UpsertIoC(ctx context.Context, ioc *model.IoC) error {
 			return goerr.Wrap(err, "failed to decode existing IoC",
 				goerr.V("id", ioc.ID))
 		}
+		// Keep analyst curation; sources never provide it
+		ioc.PreserveCuration(&existing)
+		ioc.PreserveEmbedding(&existing)
+
 		// Check if any field changed (for feed sources, update if anything changed)
-		needsUpdate := existing.Description != ioc.Description ||
-			existing.Status != ioc.Status ||
-			existing.SourceURL != ioc.SourceURL ||
-			existing.Context != ioc.Context
+		needsUpdate := ioc.SourceChanged(&existing)
 		if !needsUpdate {
-			// Skip - no changes needed
+			// Only refresh the sighting and score of an unchanged IoC
+			if updates := sightingUpdates(&existing, ioc, now); len(updates) > 0 {
+				if _, err := docRef.Update(ctx, updates); err != nil {
+					return goerr.Wrap(err, "failed to refresh IoC sighting",
+						goerr.V("id", ioc.ID))
+				}
+			}
 			return nil
 		}
 		// Update: preserve FirstSeenAt, update UpdatedAt
@@ -296,6 +534,76 @@ func (f *Firestore) UpsertIoC(ctx context.Context, ioc *model.IoC) error {
 	return nil
 }
 
+// CreateIoC stores a new IoC, failing if its ID is already stored
+func (f *Firestore) CreateIoC(ctx context.Context, ioc *model.IoC) error {
+	if err := model.ValidateIoC(ioc); err != nil {
+		return goerr.Wrap(err, "invalid IoC")
+	}
+
+	now := time.Now()
+	ioc.FirstSeenAt = now
+	ioc.UpdatedAt = now
+
+	if _, err := f.client.Collection(collectionIoCs).Doc(ioc.ID).Create(ctx, ioc); err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return goerr.Wrap(interfaces.ErrIoCExists, "IoC already exists", goerr.V("id", ioc.ID))
+		}
+		return goerr.Wrap(err, "failed to create IoC in firestore",
+			goerr.V("id", ioc.ID))
+	}
+
+	return nil
+}
+
+// PutIoC stores the IoC as given, preserving FirstSeenAt of an existing record
+func (f *Firestore) PutIoC(ctx context.Context, ioc *model.IoC) error {
+	if err := model.ValidateIoC(ioc); err != nil {
+		return goerr.Wrap(err, "invalid IoC")
+	}
+
+	docRef := f.client.Collection(collectionIoCs).Doc(ioc.ID)
+	now := time.Now()
+
+	doc, err := docRef.Get(ctx)
+	if err != nil {
+		if status.Code(err) != codes.NotFound {
+			return goerr.Wrap(err, "failed to check IoC existence",
+				goerr.V("id", ioc.ID))
+		}
+		ioc.FirstSeenAt = now
+	} else {
+		var existing model.IoC
+		if err := doc.DataTo(&existing); err != nil {
+			return goerr.Wrap(err, "failed to decode existing IoC",
+				goerr.V("id", ioc.ID))
+		}
+		ioc.FirstSeenAt = existing.FirstSeenAt
+	}
+	ioc.UpdatedAt = now
+
+	if _, err := docRef.Set(ctx, ioc); err != nil {
+		return goerr.Wrap(err, "failed to save IoC to firestore",
+			goerr.V("id", ioc.ID))
+	}
+
+	return nil
+}
+
+// DeleteIoC deletes an IoC by ID
+func (f *Firestore) DeleteIoC(ctx context.Context, id string) error {
+	docRef := f.client.Collection(collectionIoCs).Doc(id)
+
+	// Delete is idempotent in Firestore, so require existence explicitly
+	if _, err := docRef.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return goerr.Wrap(interfaces.ErrIoCNotFound, "IoC not found", goerr.V("id", id))
+		}
+		return goerr.Wrap(err, "failed to delete IoC from firestore", goerr.V("id", id))
+	}
+
+	return nil
+}
+
 // BatchUpsertIoCs upserts multiple IoCs in batches
 // Uses BulkWriter which handles batching automatically (20 writes per batch)
 // Processes in chunks to avoid loading too many documents at once with GetAll
@@ -320,6 +628,8 @@ func (f *Firestore) BatchUpsertIoCs(ctx context.Context, iocs []*model.IoC) (*in
 		result.Created += chunkResult.Created
 		result.Updated += chunkResult.Updated
 		result.Unchanged += chunkResult.Unchanged
+		result.CreatedIDs = append(result.CreatedIDs, chunkResult.CreatedIDs...)
+		result.StatusChanges = append(result.StatusChanges, chunkResult.StatusChanges...)
 		if err != nil {
 			return result, goerr.Wrap(err, "batch write failed",
 				goerr.V("chunk_start", i),
@@ -367,13 +677,21 @@ func (f *Firestore) writeBatch(ctx context.Context, iocs []*model.IoC) (*interfa
 		docRef := f.client.Collection(collectionIoCs).Doc(ioc.ID)
 
 		if existing, ok := existingMap[ioc.ID]; ok {
+			// Keep analyst curation; sources never provide it
+			ioc.PreserveCuration(existing)
+			ioc.PreserveEmbedding(existing)
+
 			// Existing IoC - check if any field changed (for feed sources, update if anything changed)
-			needsUpdate := existing.Description != ioc.Description ||
-				existing.Status != ioc.Status ||
-				existing.SourceURL != ioc.SourceURL ||
-				existing.Context != ioc.Context
+			needsUpdate := ioc.SourceChanged(existing)
 			if !needsUpdate {
-				// Skip - no changes needed
+				// Only refresh the sighting and score of an unchanged IoC
+				if updates := sightingUpdates(existing, ioc, now); len(updates) > 0 {
+					if _, err := bulkWriter.Update(docRef, updates); err != nil {
+						bulkWriter.End()
+						return result, goerr.Wrap(err, "failed to add sighting update to bulk writer",
+							goerr.V("ioc_id", ioc.ID))
+					}
+				}
 				result.Unchanged++
 				continue
 			}
@@ -381,11 +699,16 @@ func (f *Firestore) writeBatch(ctx context.Context, iocs []*model.IoC) (*interfa
 			ioc.FirstSeenAt = existing.FirstSeenAt
 			ioc.UpdatedAt = now
 			result.Updated++
+			if existing.Status != ioc.Status {
+				result.StatusChanges = append(result.StatusChanges,
+					model.NewIoCStatusTransition(ioc.ID, existing.Status, ioc.Status, now))
+			}
 		} else {
 			// New IoC
 			ioc.FirstSeenAt = now
 			ioc.UpdatedAt = now
 			result.Created++
+			result.CreatedIDs = append(result.CreatedIDs, ioc.ID)
 		}
 
 		if _, err := bulkWriter.Set(docRef, ioc); err != nil {
@@ -402,6 +725,24 @@ func (f *Firestore) writeBatch(ctx context.Context, iocs []*model.IoC) (*interfa
 	return result, nil
 }
 
+// sightingUpdates returns the fields to write when a source reports an IoC
+// without changes: the sighting if it was seen again and the recomputed
+// confidence score. Returns nil if nothing changed.
+func sightingUpdates(existing, ioc *model.IoC, now time.Time) []firestore.Update {
+	var updates []firestore.Update
+	if ioc.Resighted(existing) {
+		updates = append(updates,
+			firestore.Update{Path: "LastSeenAt", Value: ioc.LastSeenAt},
+			firestore.Update{Path: "ExpiresAt", Value: ioc.ExpiresAt},
+			firestore.Update{Path: "UpdatedAt", Value: now},
+		)
+	}
+	if ioc.Confidence != existing.Confidence {
+		updates = append(updates, firestore.Update{Path: "Confidence", Value: ioc.Confidence})
+	}
+	return updates
+}
+
 // GetState retrieves source state by source ID
 func (f *Firestore) GetState(ctx context.Context, sourceID string) (*model.SourceState, error) {
 	doc, err := f.client.Collection(collectionSources).Doc(sourceID).Get(ctx)
@@ -486,9 +827,9 @@ func (f *Firestore) BatchGetStates(ctx context.Context, sourceIDs []string) (map
 
 // FindNearestIoCs performs vector similarity search using Firestore Vector Search
 func (f *Firestore) FindNearestIoCs(ctx context.Context, queryVector []float32, limit int) ([]*model.IoC, error) {
-	if len(queryVector) != model.EmbeddingDimension {
+	if len(queryVector) != f.embeddingDim {
 		return nil, goerr.Wrap(interfaces.ErrIoCNotFound, "invalid query vector dimension",
-			goerr.V("expected", model.EmbeddingDimension),
+			goerr.V("expected", f.embeddingDim),
 			goerr.V("actual", len(queryVector)))
 	}
 
@@ -635,3 +976,105 @@ func (f *Firestore) GetHistory(ctx context.Context, sourceID string, historyID s
 
 	return &h, nil
 }
+
+// SaveStatusTransitions saves IoC status transitions to a subcollection of each IoC
+func (f *Firestore) SaveStatusTransitions(ctx context.Context, transitions []*model.IoCStatusTransition) error {
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	bulkWriter := f.client.BulkWriter(ctx)
+	for _, tr := range transitions {
+		if tr.IoCID == "" || tr.ID == "" {
+			bulkWriter.End()
+			return goerr.New("IoC ID and transition ID cannot be empty",
+				goerr.V("ioc_id", tr.IoCID),
+				goerr.V("transition_id", tr.ID))
+		}
+
+		// Path: iocs/{iocID}/status_transitions/{transitionID}
+		docRef := f.client.Collection(collectionIoCs).
+			Doc(tr.IoCID).
+			Collection(subCollectionStatusTransitions).
+			Doc(tr.ID)
+
+		if _, err := bulkWriter.Set(docRef, tr); err != nil {
+			bulkWriter.End()
+			return goerr.Wrap(err, "failed to add status transition to bulk writer",
+				goerr.V("ioc_id", tr.IoCID),
+				goerr.V("transition_id", tr.ID))
+		}
+	}
+
+	bulkWriter.Flush()
+	bulkWriter.End()
+
+	return nil
+}
+
+// ListStatusTransitions retrieves status transitions of an IoC, oldest first
+func (f *Firestore) ListStatusTransitions(ctx context.Context, iocID string) ([]*model.IoCStatusTransition, error) {
+	// Path: iocs/{iocID}/status_transitions
+	docs, err := f.client.Collection(collectionIoCs).
+		Doc(iocID).
+		Collection(subCollectionStatusTransitions).
+		OrderBy("CreatedAt", firestore.Asc).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list status transitions from firestore",
+			goerr.V("ioc_id", iocID))
+	}
+
+	transitions := make([]*model.IoCStatusTransition, 0, len(docs))
+	for _, doc := range docs {
+		var tr model.IoCStatusTransition
+		if err := doc.DataTo(&tr); err != nil {
+			return nil, goerr.Wrap(err, "failed to decode status transition",
+				goerr.V("doc_id", doc.Ref.ID),
+				goerr.V("ioc_id", iocID))
+		}
+		transitions = append(transitions, &tr)
+	}
+
+	return transitions, nil
+}
+
+// notificationRecord is the document stored for a claimed notification key
+type notificationRecord struct {
+	Key       string
+	CreatedAt time.Time
+}
+
+// notificationDocID derives a document ID from a notification key, which may
+// contain characters not allowed in document IDs
+func notificationDocID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ClaimNotification records a notification key, returning false if it was already recorded
+func (f *Firestore) ClaimNotification(ctx context.Context, key string, at time.Time) (bool, error) {
+	if key == "" {
+		return false, goerr.New("notification key cannot be empty")
+	}
+
+	docRef := f.client.Collection(collectionNotifications).Doc(notificationDocID(key))
+	if _, err := docRef.Create(ctx, &notificationRecord{Key: key, CreatedAt: at}); err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return false, nil
+		}
+		return false, goerr.Wrap(err, "failed to claim notification", goerr.V("key", key))
+	}
+
+	return true, nil
+}
+
+// ReleaseNotification removes a notification key
+func (f *Firestore) ReleaseNotification(ctx context.Context, key string) error {
+	docRef := f.client.Collection(collectionNotifications).Doc(notificationDocID(key))
+	if _, err := docRef.Delete(ctx); err != nil {
+		return goerr.Wrap(err, "failed to release notification", goerr.V("key", key))
+	}
+	return nil
+}
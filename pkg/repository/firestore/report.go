@@ -0,0 +1,138 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	firestorepb "cloud.google.com/go/firestore/apiv1/firestorepb"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const collectionReports = "reports"
+
+var _ interfaces.ReportRepository = &Firestore{}
+
+// PutReport creates or replaces a report, preserving CreatedAt of an existing report
+func (f *Firestore) PutReport(ctx context.Context, report *model.Report) error {
+	if report.ID == "" {
+		return goerr.New("report ID cannot be empty", goerr.V("source_id", report.SourceID))
+	}
+
+	docRef := f.client.Collection(collectionReports).Doc(report.ID)
+	now := time.Now()
+
+	doc, err := docRef.Get(ctx)
+	if err != nil {
+		if status.Code(err) != codes.NotFound {
+			return goerr.Wrap(err, "failed to check report existence", goerr.V("id", report.ID))
+		}
+		if report.CreatedAt.IsZero() {
+			report.CreatedAt = now
+		}
+	} else {
+		var existing model.Report
+		if err := doc.DataTo(&existing); err != nil {
+			return goerr.Wrap(err, "failed to decode existing report", goerr.V("id", report.ID))
+		}
+		report.CreatedAt = existing.CreatedAt
+	}
+	report.UpdatedAt = now
+
+	if _, err := docRef.Set(ctx, report); err != nil {
+		return goerr.Wrap(err, "failed to put report", goerr.V("id", report.ID))
+	}
+	return nil
+}
+
+// GetReport retrieves a report by ID
+func (f *Firestore) GetReport(ctx context.Context, id string) (*model.Report, error) {
+	doc, err := f.client.Collection(collectionReports).Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, goerr.Wrap(interfaces.ErrReportNotFound, "report not found", goerr.V("id", id))
+		}
+		return nil, goerr.Wrap(err, "failed to get report", goerr.V("id", id))
+	}
+
+	var report model.Report
+	if err := doc.DataTo(&report); err != nil {
+		return nil, goerr.Wrap(err, "failed to decode report", goerr.V("id", id))
+	}
+	return &report, nil
+}
+
+// ListReports retrieves reports, newest first
+func (f *Firestore) ListReports(ctx context.Context, opts *model.ReportListOptions) (*model.ReportConnection, error) {
+	if opts == nil {
+		opts = &model.ReportListOptions{}
+	}
+
+	query := f.client.Collection(collectionReports).Query
+	if opts.SourceID != "" {
+		query = query.Where("SourceID", "==", opts.SourceID)
+	}
+
+	aggregationResults, err := query.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to count reports")
+	}
+	pbValue, ok := aggregationResults["total"].(*firestorepb.Value)
+	if !ok {
+		return nil, goerr.New("total count has unexpected type",
+			goerr.V("type", fmt.Sprintf("%T", aggregationResults["total"])))
+	}
+
+	query = query.OrderBy("PublishedAt", firestore.Desc)
+	if opts.Offset > 0 {
+		query = query.Offset(opts.Offset)
+	}
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list reports")
+	}
+
+	items := make([]*model.Report, 0, len(docs))
+	for _, doc := range docs {
+		var report model.Report
+		if err := doc.DataTo(&report); err != nil {
+			return nil, goerr.Wrap(err, "failed to decode report", goerr.V("doc_id", doc.Ref.ID))
+		}
+		items = append(items, &report)
+	}
+
+	return &model.ReportConnection{
+		Items: items,
+		Total: int(pbValue.GetIntegerValue()),
+	}, nil
+}
+
+// ListIoCsByReport lists the IoCs extracted from a report
+func (f *Firestore) ListIoCsByReport(ctx context.Context, reportID string) ([]*model.IoC, error) {
+	docs, err := f.client.Collection(collectionIoCs).
+		Where("ReportID", "==", reportID).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list IoCs by report", goerr.V("report_id", reportID))
+	}
+
+	iocs := make([]*model.IoC, 0, len(docs))
+	for _, doc := range docs {
+		var ioc model.IoC
+		if err := doc.DataTo(&ioc); err != nil {
+			return nil, goerr.Wrap(err, "failed to decode IoC", goerr.V("doc_id", doc.Ref.ID))
+		}
+		iocs = append(iocs, &ioc)
+	}
+	return iocs, nil
+}
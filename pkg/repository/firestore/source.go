@@ -0,0 +1,133 @@
+package firestore
+
+import (
+	"context"
+	"sort"
+
+	"cloud.google.com/go/firestore"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// collectionSources holds the states of sources, so definitions are stored apart
+	collectionSourceDefinitions = "source_definitions"
+	collectionSourceChanges     = "source_changes"
+)
+
+var _ interfaces.SourceRepository = &Firestore{}
+
+// PutSource creates or replaces a source
+func (f *Firestore) PutSource(ctx context.Context, source *model.Source) error {
+	if source.ID == "" {
+		return goerr.New("source ID cannot be empty")
+	}
+
+	if _, err := f.client.Collection(collectionSourceDefinitions).Doc(source.ID).Set(ctx, source); err != nil {
+		return goerr.Wrap(err, "failed to put source", goerr.V("id", source.ID))
+	}
+	return nil
+}
+
+// GetSource retrieves a source by ID
+func (f *Firestore) GetSource(ctx context.Context, id string) (*model.Source, error) {
+	doc, err := f.client.Collection(collectionSourceDefinitions).Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, goerr.Wrap(interfaces.ErrSourceNotFound, "source not found", goerr.V("id", id))
+		}
+		return nil, goerr.Wrap(err, "failed to get source", goerr.V("id", id))
+	}
+
+	var s model.Source
+	if err := doc.DataTo(&s); err != nil {
+		return nil, goerr.Wrap(err, "failed to decode source", goerr.V("id", id))
+	}
+	return &s, nil
+}
+
+// ListSources retrieves all sources ordered by ID
+func (f *Firestore) ListSources(ctx context.Context) ([]*model.Source, error) {
+	docs, err := f.client.Collection(collectionSourceDefinitions).
+		OrderBy("ID", firestore.Asc).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list sources")
+	}
+
+	result := make([]*model.Source, 0, len(docs))
+	for _, doc := range docs {
+		var s model.Source
+		if err := doc.DataTo(&s); err != nil {
+			return nil, goerr.Wrap(err, "failed to decode source", goerr.V("doc_id", doc.Ref.ID))
+		}
+		result = append(result, &s)
+	}
+	return result, nil
+}
+
+// DeleteSource deletes a source, keeping its state, histories and change records
+func (f *Firestore) DeleteSource(ctx context.Context, id string) error {
+	docRef := f.client.Collection(collectionSourceDefinitions).Doc(id)
+	if _, err := docRef.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return goerr.Wrap(interfaces.ErrSourceNotFound, "source not found", goerr.V("id", id))
+		}
+		return goerr.Wrap(err, "failed to delete source", goerr.V("id", id))
+	}
+	return nil
+}
+
+// SaveSourceChange appends a source change record
+func (f *Firestore) SaveSourceChange(ctx context.Context, change *model.SourceChange) error {
+	if change.ID == "" {
+		return goerr.New("source change ID cannot be empty", goerr.V("source_id", change.SourceID))
+	}
+
+	if _, err := f.client.Collection(collectionSourceChanges).Doc(change.ID).Create(ctx, change); err != nil {
+		return goerr.Wrap(err, "failed to save source change",
+			goerr.V("id", change.ID),
+			goerr.V("source_id", change.SourceID))
+	}
+	return nil
+}
+
+// ListSourceChanges retrieves change records of a source, or of all sources if
+// sourceID is empty, newest first. The records of a source are sorted after
+// fetching so that no composite index is needed.
+func (f *Firestore) ListSourceChanges(ctx context.Context, sourceID string, limit int) ([]*model.SourceChange, error) {
+	query := f.client.Collection(collectionSourceChanges).Query
+	if sourceID != "" {
+		query = query.Where("SourceID", "==", sourceID)
+	} else {
+		query = query.OrderBy("CreatedAt", firestore.Desc)
+		if limit > 0 {
+			query = query.Limit(limit)
+		}
+	}
+
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list source changes", goerr.V("source_id", sourceID))
+	}
+
+	result := make([]*model.SourceChange, 0, len(docs))
+	for _, doc := range docs {
+		var c model.SourceChange
+		if err := doc.DataTo(&c); err != nil {
+			return nil, goerr.Wrap(err, "failed to decode source change", goerr.V("doc_id", doc.Ref.ID))
+		}
+		result = append(result, &c)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
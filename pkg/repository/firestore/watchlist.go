@@ -0,0 +1,241 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	firestorepb "cloud.google.com/go/firestore/apiv1/firestorepb"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	collectionWatchlists    = "watchlists"
+	collectionWatchlistHits = "watchlist_hits"
+)
+
+var _ interfaces.WatchlistRepository = &Firestore{}
+
+// PutWatchlist creates or replaces a watchlist
+func (f *Firestore) PutWatchlist(ctx context.Context, watchlist *model.Watchlist) error {
+	if watchlist.ID == "" {
+		return goerr.New("watchlist ID cannot be empty")
+	}
+
+	if _, err := f.client.Collection(collectionWatchlists).Doc(watchlist.ID).Set(ctx, watchlist); err != nil {
+		return goerr.Wrap(err, "failed to put watchlist", goerr.V("id", watchlist.ID))
+	}
+	return nil
+}
+
+// GetWatchlist retrieves a watchlist by ID
+func (f *Firestore) GetWatchlist(ctx context.Context, id string) (*model.Watchlist, error) {
+	doc, err := f.client.Collection(collectionWatchlists).Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, goerr.Wrap(interfaces.ErrWatchlistNotFound, "watchlist not found", goerr.V("id", id))
+		}
+		return nil, goerr.Wrap(err, "failed to get watchlist", goerr.V("id", id))
+	}
+
+	var w model.Watchlist
+	if err := doc.DataTo(&w); err != nil {
+		return nil, goerr.Wrap(err, "failed to decode watchlist", goerr.V("id", id))
+	}
+	return &w, nil
+}
+
+// ListWatchlists retrieves all watchlists ordered by name
+func (f *Firestore) ListWatchlists(ctx context.Context) ([]*model.Watchlist, error) {
+	docs, err := f.client.Collection(collectionWatchlists).
+		OrderBy("Name", firestore.Asc).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list watchlists")
+	}
+
+	result := make([]*model.Watchlist, 0, len(docs))
+	for _, doc := range docs {
+		var w model.Watchlist
+		if err := doc.DataTo(&w); err != nil {
+			return nil, goerr.Wrap(err, "failed to decode watchlist", goerr.V("doc_id", doc.Ref.ID))
+		}
+		result = append(result, &w)
+	}
+	return result, nil
+}
+
+// DeleteWatchlist deletes a watchlist, keeping its hits
+func (f *Firestore) DeleteWatchlist(ctx context.Context, id string) error {
+	docRef := f.client.Collection(collectionWatchlists).Doc(id)
+	if _, err := docRef.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return goerr.Wrap(interfaces.ErrWatchlistNotFound, "watchlist not found", goerr.V("id", id))
+		}
+		return goerr.Wrap(err, "failed to delete watchlist", goerr.V("id", id))
+	}
+	return nil
+}
+
+// SaveWatchlistHits stores hits that do not exist yet and returns them
+func (f *Firestore) SaveWatchlistHits(ctx context.Context, hits []*model.WatchlistHit) ([]*model.WatchlistHit, error) {
+	if len(hits) == 0 {
+		return nil, nil
+	}
+
+	bulkWriter := f.client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, len(hits))
+	for i, hit := range hits {
+		if hit.ID == "" {
+			bulkWriter.End()
+			return nil, goerr.New("watchlist hit ID cannot be empty", goerr.V("ioc_id", hit.IoCID))
+		}
+
+		docRef := f.client.Collection(collectionWatchlistHits).Doc(hit.ID)
+		job, err := bulkWriter.Create(docRef, hit)
+		if err != nil {
+			bulkWriter.End()
+			return nil, goerr.Wrap(err, "failed to add watchlist hit to bulk writer",
+				goerr.V("hit_id", hit.ID))
+		}
+		jobs[i] = job
+	}
+
+	bulkWriter.Flush()
+	bulkWriter.End()
+
+	var stored []*model.WatchlistHit
+	for i, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			if status.Code(err) == codes.AlreadyExists {
+				continue
+			}
+			return stored, goerr.Wrap(err, "failed to save watchlist hit", goerr.V("hit_id", hits[i].ID))
+		}
+		stored = append(stored, hits[i])
+	}
+	return stored, nil
+}
+
+// GetWatchlistHit retrieves a watchlist hit by ID
+func (f *Firestore) GetWatchlistHit(ctx context.Context, id string) (*model.WatchlistHit, error) {
+	doc, err := f.client.Collection(collectionWatchlistHits).Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, goerr.Wrap(interfaces.ErrWatchlistHitNotFound, "watchlist hit not found", goerr.V("id", id))
+		}
+		return nil, goerr.Wrap(err, "failed to get watchlist hit", goerr.V("id", id))
+	}
+
+	var hit model.WatchlistHit
+	if err := doc.DataTo(&hit); err != nil {
+		return nil, goerr.Wrap(err, "failed to decode watchlist hit", goerr.V("id", id))
+	}
+	return &hit, nil
+}
+
+// ListWatchlistHits retrieves watchlist hits, newest first
+func (f *Firestore) ListWatchlistHits(ctx context.Context, opts *model.WatchlistHitListOptions) (*model.WatchlistHitConnection, error) {
+	if opts == nil {
+		opts = &model.WatchlistHitListOptions{}
+	}
+
+	query := f.client.Collection(collectionWatchlistHits).Query
+	if opts.WatchlistID != "" {
+		query = query.Where("WatchlistID", "==", opts.WatchlistID)
+	}
+	if opts.Acknowledged != nil {
+		query = query.Where("Acknowledged", "==", *opts.Acknowledged)
+	}
+
+	aggregationResults, err := query.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to count watchlist hits")
+	}
+	pbValue, ok := aggregationResults["total"].(*firestorepb.Value)
+	if !ok {
+		return nil, goerr.New("total count has unexpected type",
+			goerr.V("type", fmt.Sprintf("%T", aggregationResults["total"])))
+	}
+
+	query = query.OrderBy("CreatedAt", firestore.Desc)
+	if opts.Offset > 0 {
+		query = query.Offset(opts.Offset)
+	}
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list watchlist hits")
+	}
+
+	items := make([]*model.WatchlistHit, 0, len(docs))
+	for _, doc := range docs {
+		var hit model.WatchlistHit
+		if err := doc.DataTo(&hit); err != nil {
+			return nil, goerr.Wrap(err, "failed to decode watchlist hit", goerr.V("doc_id", doc.Ref.ID))
+		}
+		items = append(items, &hit)
+	}
+
+	return &model.WatchlistHitConnection{
+		Items: items,
+		Total: int(pbValue.GetIntegerValue()),
+	}, nil
+}
+
+// AcknowledgeWatchlistHits marks hits as acknowledged in a single transaction
+func (f *Firestore) AcknowledgeWatchlistHits(ctx context.Context, ids []string, actor string, at time.Time) ([]*model.WatchlistHit, error) {
+	if len(ids) == 0 {
+		return []*model.WatchlistHit{}, nil
+	}
+
+	var result []*model.WatchlistHit
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		result = make([]*model.WatchlistHit, 0, len(ids))
+
+		docRefs := make([]*firestore.DocumentRef, len(ids))
+		for i, id := range ids {
+			docRefs[i] = f.client.Collection(collectionWatchlistHits).Doc(id)
+		}
+
+		docs, err := tx.GetAll(docRefs)
+		if err != nil {
+			return goerr.Wrap(err, "failed to get watchlist hits")
+		}
+
+		for i, doc := range docs {
+			if !doc.Exists() {
+				return goerr.Wrap(interfaces.ErrWatchlistHitNotFound, "watchlist hit not found", goerr.V("id", ids[i]))
+			}
+			var hit model.WatchlistHit
+			if err := doc.DataTo(&hit); err != nil {
+				return goerr.Wrap(err, "failed to decode watchlist hit", goerr.V("id", ids[i]))
+			}
+			hit.Acknowledged = true
+			hit.AcknowledgedBy = actor
+			hit.AcknowledgedAt = at
+			result = append(result, &hit)
+		}
+
+		for i, hit := range result {
+			if err := tx.Set(docRefs[i], hit); err != nil {
+				return goerr.Wrap(err, "failed to acknowledge watchlist hit", goerr.V("id", hit.ID))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to acknowledge watchlist hits")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,376 @@
+// Package instrumented wraps a repository to record the latency and errors of
+// its operations in metrics and traces
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/tracing"
+)
+
+// Repository records every call of the wrapped repository in metrics and as a
+// span. The operation label is the method name, so its values are bounded.
+type Repository struct {
+	repo    interfaces.Repository
+	metrics interfaces.Metrics
+}
+
+var _ interfaces.Repository = &Repository{}
+
+// New wraps repo to record its operations. metrics may be nil to only trace
+// the operations.
+func New(repo interfaces.Repository, metrics interfaces.Metrics) *Repository {
+	return &Repository{repo: repo, metrics: metrics}
+}
+
+// start starts a span of the operation. The returned function ends it and
+// records the operation in metrics.
+func (r *Repository) start(ctx context.Context, operation string) (context.Context, func(*error)) {
+	started := time.Now()
+	ctx, span := tracing.Start(ctx, "repository."+operation)
+	return ctx, func(err *error) {
+		tracing.End(span, *err)
+		if r.metrics != nil {
+			r.metrics.ObserveRepository(operation, time.Since(started), *err)
+		}
+	}
+}
+
+func (r *Repository) Ping(ctx context.Context) (err error) {
+	ctx, end := r.start(ctx, "Ping")
+	defer end(&err)
+	return r.repo.Ping(ctx)
+}
+
+func (r *Repository) GetIoC(ctx context.Context, id string) (_ *model.IoC, err error) {
+	ctx, end := r.start(ctx, "GetIoC")
+	defer end(&err)
+	return r.repo.GetIoC(ctx, id)
+}
+
+func (r *Repository) ListIoCsBySource(ctx context.Context, sourceID string) (_ []*model.IoC, err error) {
+	ctx, end := r.start(ctx, "ListIoCsBySource")
+	defer end(&err)
+	return r.repo.ListIoCsBySource(ctx, sourceID)
+}
+
+func (r *Repository) ListAllIoCs(ctx context.Context) (_ []*model.IoC, err error) {
+	ctx, end := r.start(ctx, "ListAllIoCs")
+	defer end(&err)
+	return r.repo.ListAllIoCs(ctx)
+}
+
+func (r *Repository) ListIoCs(ctx context.Context, opts *model.IoCListOptions) (_ *model.IoCConnection, err error) {
+	ctx, end := r.start(ctx, "ListIoCs")
+	defer end(&err)
+	return r.repo.ListIoCs(ctx, opts)
+}
+
+func (r *Repository) UpsertIoC(ctx context.Context, ioc *model.IoC) (err error) {
+	ctx, end := r.start(ctx, "UpsertIoC")
+	defer end(&err)
+	return r.repo.UpsertIoC(ctx, ioc)
+}
+
+func (r *Repository) CreateIoC(ctx context.Context, ioc *model.IoC) (err error) {
+	ctx, end := r.start(ctx, "CreateIoC")
+	defer end(&err)
+	return r.repo.CreateIoC(ctx, ioc)
+}
+
+func (r *Repository) PutIoC(ctx context.Context, ioc *model.IoC) (err error) {
+	ctx, end := r.start(ctx, "PutIoC")
+	defer end(&err)
+	return r.repo.PutIoC(ctx, ioc)
+}
+
+func (r *Repository) DeleteIoC(ctx context.Context, id string) (err error) {
+	ctx, end := r.start(ctx, "DeleteIoC")
+	defer end(&err)
+	return r.repo.DeleteIoC(ctx, id)
+}
+
+func (r *Repository) BatchUpsertIoCs(ctx context.Context, iocs []*model.IoC) (_ *interfaces.BatchUpsertResult, err error) {
+	ctx, end := r.start(ctx, "BatchUpsertIoCs")
+	defer end(&err)
+	return r.repo.BatchUpsertIoCs(ctx, iocs)
+}
+
+func (r *Repository) ListExpiredIoCs(ctx context.Context, now time.Time) (_ []*model.IoC, err error) {
+	ctx, end := r.start(ctx, "ListExpiredIoCs")
+	defer end(&err)
+	return r.repo.ListExpiredIoCs(ctx, now)
+}
+
+func (r *Repository) ListIoCsByTag(ctx context.Context, filter *model.IoCTagFilter) (_ []*model.IoC, err error) {
+	ctx, end := r.start(ctx, "ListIoCsByTag")
+	defer end(&err)
+	return r.repo.ListIoCsByTag(ctx, filter)
+}
+
+func (r *Repository) ListSourceIDsByValues(ctx context.Context, iocType model.IoCType, values []string) (_ map[string][]string, err error) {
+	ctx, end := r.start(ctx, "ListSourceIDsByValues")
+	defer end(&err)
+	return r.repo.ListSourceIDsByValues(ctx, iocType, values)
+}
+
+func (r *Repository) UpdateIoCConfidences(ctx context.Context, scores map[string]int) (err error) {
+	ctx, end := r.start(ctx, "UpdateIoCConfidences")
+	defer end(&err)
+	return r.repo.UpdateIoCConfidences(ctx, scores)
+}
+
+func (r *Repository) UpdateIoCEmbeddings(ctx context.Context, embeddings map[string]*model.IoCEmbedding) (err error) {
+	ctx, end := r.start(ctx, "UpdateIoCEmbeddings")
+	defer end(&err)
+	return r.repo.UpdateIoCEmbeddings(ctx, embeddings)
+}
+
+func (r *Repository) FindNearestIoCs(ctx context.Context, queryVector []float32, limit int) (_ []*model.IoC, err error) {
+	ctx, end := r.start(ctx, "FindNearestIoCs")
+	defer end(&err)
+	return r.repo.FindNearestIoCs(ctx, queryVector, limit)
+}
+
+func (r *Repository) SaveStatusTransitions(ctx context.Context, transitions []*model.IoCStatusTransition) (err error) {
+	ctx, end := r.start(ctx, "SaveStatusTransitions")
+	defer end(&err)
+	return r.repo.SaveStatusTransitions(ctx, transitions)
+}
+
+func (r *Repository) ListStatusTransitions(ctx context.Context, iocID string) (_ []*model.IoCStatusTransition, err error) {
+	ctx, end := r.start(ctx, "ListStatusTransitions")
+	defer end(&err)
+	return r.repo.ListStatusTransitions(ctx, iocID)
+}
+
+func (r *Repository) GetState(ctx context.Context, sourceID string) (_ *model.SourceState, err error) {
+	ctx, end := r.start(ctx, "GetState")
+	defer end(&err)
+	return r.repo.GetState(ctx, sourceID)
+}
+
+func (r *Repository) SaveState(ctx context.Context, state *model.SourceState) (err error) {
+	ctx, end := r.start(ctx, "SaveState")
+	defer end(&err)
+	return r.repo.SaveState(ctx, state)
+}
+
+func (r *Repository) BatchGetStates(ctx context.Context, sourceIDs []string) (_ map[string]*model.SourceState, err error) {
+	ctx, end := r.start(ctx, "BatchGetStates")
+	defer end(&err)
+	return r.repo.BatchGetStates(ctx, sourceIDs)
+}
+
+func (r *Repository) SaveHistory(ctx context.Context, history *model.History) (err error) {
+	ctx, end := r.start(ctx, "SaveHistory")
+	defer end(&err)
+	return r.repo.SaveHistory(ctx, history)
+}
+
+func (r *Repository) ListHistoriesBySource(ctx context.Context, sourceID string, limit, offset int) (_ []*model.History, _ int, err error) {
+	ctx, end := r.start(ctx, "ListHistoriesBySource")
+	defer end(&err)
+	return r.repo.ListHistoriesBySource(ctx, sourceID, limit, offset)
+}
+
+func (r *Repository) GetHistory(ctx context.Context, sourceID string, historyID string) (_ *model.History, err error) {
+	ctx, end := r.start(ctx, "GetHistory")
+	defer end(&err)
+	return r.repo.GetHistory(ctx, sourceID, historyID)
+}
+
+func (r *Repository) ClaimNotification(ctx context.Context, key string, at time.Time) (_ bool, err error) {
+	ctx, end := r.start(ctx, "ClaimNotification")
+	defer end(&err)
+	return r.repo.ClaimNotification(ctx, key, at)
+}
+
+func (r *Repository) ReleaseNotification(ctx context.Context, key string) (err error) {
+	ctx, end := r.start(ctx, "ReleaseNotification")
+	defer end(&err)
+	return r.repo.ReleaseNotification(ctx, key)
+}
+
+func (r *Repository) PutWatchlist(ctx context.Context, watchlist *model.Watchlist) (err error) {
+	ctx, end := r.start(ctx, "PutWatchlist")
+	defer end(&err)
+	return r.repo.PutWatchlist(ctx, watchlist)
+}
+
+func (r *Repository) GetWatchlist(ctx context.Context, id string) (_ *model.Watchlist, err error) {
+	ctx, end := r.start(ctx, "GetWatchlist")
+	defer end(&err)
+	return r.repo.GetWatchlist(ctx, id)
+}
+
+func (r *Repository) ListWatchlists(ctx context.Context) (_ []*model.Watchlist, err error) {
+	ctx, end := r.start(ctx, "ListWatchlists")
+	defer end(&err)
+	return r.repo.ListWatchlists(ctx)
+}
+
+func (r *Repository) DeleteWatchlist(ctx context.Context, id string) (err error) {
+	ctx, end := r.start(ctx, "DeleteWatchlist")
+	defer end(&err)
+	return r.repo.DeleteWatchlist(ctx, id)
+}
+
+func (r *Repository) SaveWatchlistHits(ctx context.Context, hits []*model.WatchlistHit) (_ []*model.WatchlistHit, err error) {
+	ctx, end := r.start(ctx, "SaveWatchlistHits")
+	defer end(&err)
+	return r.repo.SaveWatchlistHits(ctx, hits)
+}
+
+func (r *Repository) GetWatchlistHit(ctx context.Context, id string) (_ *model.WatchlistHit, err error) {
+	ctx, end := r.start(ctx, "GetWatchlistHit")
+	defer end(&err)
+	return r.repo.GetWatchlistHit(ctx, id)
+}
+
+func (r *Repository) ListWatchlistHits(ctx context.Context, opts *model.WatchlistHitListOptions) (_ *model.WatchlistHitConnection, err error) {
+	ctx, end := r.start(ctx, "ListWatchlistHits")
+	defer end(&err)
+	return r.repo.ListWatchlistHits(ctx, opts)
+}
+
+func (r *Repository) AcknowledgeWatchlistHits(ctx context.Context, ids []string, actor string, at time.Time) (_ []*model.WatchlistHit, err error) {
+	ctx, end := r.start(ctx, "AcknowledgeWatchlistHits")
+	defer end(&err)
+	return r.repo.AcknowledgeWatchlistHits(ctx, ids, actor, at)
+}
+
+func (r *Repository) SaveBrandMatches(ctx context.Context, matches []*model.BrandMatch) (_ []*model.BrandMatch, err error) {
+	ctx, end := r.start(ctx, "SaveBrandMatches")
+	defer end(&err)
+	return r.repo.SaveBrandMatches(ctx, matches)
+}
+
+func (r *Repository) ListBrandMatches(ctx context.Context, opts *model.BrandMatchListOptions) (_ *model.BrandMatchConnection, err error) {
+	ctx, end := r.start(ctx, "ListBrandMatches")
+	defer end(&err)
+	return r.repo.ListBrandMatches(ctx, opts)
+}
+
+func (r *Repository) PutReport(ctx context.Context, report *model.Report) (err error) {
+	ctx, end := r.start(ctx, "PutReport")
+	defer end(&err)
+	return r.repo.PutReport(ctx, report)
+}
+
+func (r *Repository) GetReport(ctx context.Context, id string) (_ *model.Report, err error) {
+	ctx, end := r.start(ctx, "GetReport")
+	defer end(&err)
+	return r.repo.GetReport(ctx, id)
+}
+
+func (r *Repository) ListReports(ctx context.Context, opts *model.ReportListOptions) (_ *model.ReportConnection, err error) {
+	ctx, end := r.start(ctx, "ListReports")
+	defer end(&err)
+	return r.repo.ListReports(ctx, opts)
+}
+
+func (r *Repository) ListIoCsByReport(ctx context.Context, reportID string) (_ []*model.IoC, err error) {
+	ctx, end := r.start(ctx, "ListIoCsByReport")
+	defer end(&err)
+	return r.repo.ListIoCsByReport(ctx, reportID)
+}
+
+func (r *Repository) GetExtractionCache(ctx context.Context, key string) (_ *model.ExtractionCacheEntry, err error) {
+	ctx, end := r.start(ctx, "GetExtractionCache")
+	defer end(&err)
+	return r.repo.GetExtractionCache(ctx, key)
+}
+
+func (r *Repository) PutExtractionCache(ctx context.Context, entry *model.ExtractionCacheEntry) (err error) {
+	ctx, end := r.start(ctx, "PutExtractionCache")
+	defer end(&err)
+	return r.repo.PutExtractionCache(ctx, entry)
+}
+
+func (r *Repository) PurgeExtractionCache(ctx context.Context, opts *model.ExtractionCachePurgeOptions) (_ int, err error) {
+	ctx, end := r.start(ctx, "PurgeExtractionCache")
+	defer end(&err)
+	return r.repo.PurgeExtractionCache(ctx, opts)
+}
+
+func (r *Repository) PutSource(ctx context.Context, source *model.Source) (err error) {
+	ctx, end := r.start(ctx, "PutSource")
+	defer end(&err)
+	return r.repo.PutSource(ctx, source)
+}
+
+func (r *Repository) GetSource(ctx context.Context, id string) (_ *model.Source, err error) {
+	ctx, end := r.start(ctx, "GetSource")
+	defer end(&err)
+	return r.repo.GetSource(ctx, id)
+}
+
+func (r *Repository) ListSources(ctx context.Context) (_ []*model.Source, err error) {
+	ctx, end := r.start(ctx, "ListSources")
+	defer end(&err)
+	return r.repo.ListSources(ctx)
+}
+
+func (r *Repository) DeleteSource(ctx context.Context, id string) (err error) {
+	ctx, end := r.start(ctx, "DeleteSource")
+	defer end(&err)
+	return r.repo.DeleteSource(ctx, id)
+}
+
+func (r *Repository) SaveSourceChange(ctx context.Context, change *model.SourceChange) (err error) {
+	ctx, end := r.start(ctx, "SaveSourceChange")
+	defer end(&err)
+	return r.repo.SaveSourceChange(ctx, change)
+}
+
+func (r *Repository) ListSourceChanges(ctx context.Context, sourceID string, limit int) (_ []*model.SourceChange, err error) {
+	ctx, end := r.start(ctx, "ListSourceChanges")
+	defer end(&err)
+	return r.repo.ListSourceChanges(ctx, sourceID, limit)
+}
+
+func (r *Repository) PutAPIToken(ctx context.Context, token *model.APIToken) (err error) {
+	ctx, end := r.start(ctx, "PutAPIToken")
+	defer end(&err)
+	return r.repo.PutAPIToken(ctx, token)
+}
+
+func (r *Repository) GetAPIToken(ctx context.Context, id string) (_ *model.APIToken, err error) {
+	ctx, end := r.start(ctx, "GetAPIToken")
+	defer end(&err)
+	return r.repo.GetAPIToken(ctx, id)
+}
+
+func (r *Repository) GetAPITokenByHash(ctx context.Context, hash string) (_ *model.APIToken, err error) {
+	ctx, end := r.start(ctx, "GetAPITokenByHash")
+	defer end(&err)
+	return r.repo.GetAPITokenByHash(ctx, hash)
+}
+
+func (r *Repository) ListAPITokens(ctx context.Context) (_ []*model.APIToken, err error) {
+	ctx, end := r.start(ctx, "ListAPITokens")
+	defer end(&err)
+	return r.repo.ListAPITokens(ctx)
+}
+
+func (r *Repository) TouchAPIToken(ctx context.Context, id string, at time.Time) (err error) {
+	ctx, end := r.start(ctx, "TouchAPIToken")
+	defer end(&err)
+	return r.repo.TouchAPIToken(ctx, id, at)
+}
+
+func (r *Repository) SaveAuditRecord(ctx context.Context, record *model.AuditRecord) (err error) {
+	ctx, end := r.start(ctx, "SaveAuditRecord")
+	defer end(&err)
+	return r.repo.SaveAuditRecord(ctx, record)
+}
+
+func (r *Repository) ListAuditRecords(ctx context.Context, filter *model.AuditFilter) (_ []*model.AuditRecord, err error) {
+	ctx, end := r.start(ctx, "ListAuditRecords")
+	defer end(&err)
+	return r.repo.ListAuditRecords(ctx, filter)
+}
@@ -0,0 +1,70 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/instrumented"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+)
+
+// repositoryMetrics counts repository operations and ignores other metrics
+type repositoryMetrics struct {
+	mu     sync.Mutex
+	calls  map[string]int
+	errors map[string]int
+}
+
+func newRepositoryMetrics() *repositoryMetrics {
+	return &repositoryMetrics{calls: map[string]int{}, errors: map[string]int{}}
+}
+
+func (m *repositoryMetrics) ObserveFetch(*model.History) {}
+
+func (m *repositoryMetrics) ObserveLLMCall(string, time.Duration, int, int, error) {}
+
+func (m *repositoryMetrics) ObserveGraphQLField(string, time.Duration, error) {}
+
+func (m *repositoryMetrics) ObserveRepository(operation string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[operation]++
+	if err != nil {
+		m.errors[operation]++
+	}
+}
+
+// The instrumented repository behaves like the wrapped one
+func TestInstrumentedRepository(t *testing.T) {
+	metrics := newRepositoryMetrics()
+	repo := instrumented.New(memory.New(), metrics)
+
+	runIoCRepositoryTest(t, repo)
+	runSourceRepositoryTest(t, repo)
+	runWatchlistRepositoryTest(t, repo)
+	runAuditRepositoryTest(t, repo)
+
+	gt.N(t, metrics.calls["BatchUpsertIoCs"]).Greater(0)
+	gt.N(t, metrics.calls["PutSource"]).Greater(0)
+	gt.N(t, metrics.calls["SaveAuditRecord"]).Greater(0)
+}
+
+func TestInstrumentedRepository_Errors(t *testing.T) {
+	ctx := context.Background()
+	metrics := newRepositoryMetrics()
+	repo := instrumented.New(memory.New(), metrics)
+
+	_, err := repo.GetIoC(ctx, "missing")
+	gt.True(t, errors.Is(err, interfaces.ErrIoCNotFound))
+	_, err = repo.ListIoCs(ctx, &model.IoCListOptions{})
+	gt.NoError(t, err)
+
+	gt.Equal(t, metrics.calls, map[string]int{"GetIoC": 1, "ListIoCs": 1})
+	gt.Equal(t, metrics.errors, map[string]int{"GetIoC": 1})
+}
@@ -0,0 +1,135 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+)
+
+type iocStatusRepository interface {
+	interfaces.IoCRepository
+	interfaces.IoCStatusRepository
+}
+
+func runIoCStatusRepositoryTest(t *testing.T, repo iocStatusRepository) {
+	ctx := context.Background()
+
+	newIoC := func(sourceID, value string) *model.IoC {
+		return &model.IoC{
+			ID:         model.GenerateID(sourceID, model.IoCTypeIPv4, value, ""),
+			SourceID:   sourceID,
+			SourceType: "feed",
+			Type:       model.IoCTypeIPv4,
+			Value:      value,
+			Status:     model.IoCStatusActive,
+		}
+	}
+
+	t.Run("batch upsert reports status changes", func(t *testing.T) {
+		sourceID := time.Now().Format("status-20060102-150405.000000")
+		ioc := newIoC(sourceID, "198.51.100.10")
+
+		result, err := repo.BatchUpsertIoCs(ctx, []*model.IoC{ioc})
+		gt.NoError(t, err)
+		gt.A(t, result.StatusChanges).Length(0)
+
+		changed := newIoC(sourceID, "198.51.100.10")
+		changed.Status = model.IoCStatusInactive
+		result, err = repo.BatchUpsertIoCs(ctx, []*model.IoC{changed})
+		gt.NoError(t, err)
+		gt.A(t, result.StatusChanges).Length(1).At(0, func(t testing.TB, tr *model.IoCStatusTransition) {
+			gt.Equal(t, tr.IoCID, ioc.ID)
+			gt.Equal(t, tr.From, model.IoCStatusActive)
+			gt.Equal(t, tr.To, model.IoCStatusInactive)
+		})
+	})
+
+	t.Run("save and list status transitions", func(t *testing.T) {
+		iocID := time.Now().Format("ioc-20060102-150405.000000")
+		base := time.Now().Truncate(time.Millisecond)
+
+		second := model.NewIoCStatusTransition(iocID, model.IoCStatusActive, model.IoCStatusExpired, base.Add(time.Hour))
+		second.Actor = model.ActorSweeper
+		second.Reason = "TTL expired"
+		first := model.NewIoCStatusTransition(iocID, "", model.IoCStatusActive, base)
+		first.Actor = model.FetchActor("history-1")
+
+		gt.NoError(t, repo.SaveStatusTransitions(ctx, []*model.IoCStatusTransition{second, first}))
+
+		got, err := repo.ListStatusTransitions(ctx, iocID)
+		gt.NoError(t, err)
+		gt.A(t, got).Length(2)
+		gt.Equal(t, got[0].ID, first.ID)
+		gt.Equal(t, got[0].Actor, "fetch:history-1")
+		gt.Equal(t, got[1].ID, second.ID)
+		gt.Equal(t, got[1].To, model.IoCStatusExpired)
+		gt.Equal(t, got[1].Reason, "TTL expired")
+
+		empty, err := repo.ListStatusTransitions(ctx, iocID+"-none")
+		gt.NoError(t, err)
+		gt.A(t, empty).Length(0)
+	})
+
+	t.Run("list expired IoCs", func(t *testing.T) {
+		sourceID := time.Now().Format("expiry-20060102-150405.000000")
+		now := time.Now()
+
+		expired := newIoC(sourceID, "198.51.100.20")
+		expired.ExpiresAt = now.Add(-time.Hour)
+		fresh := newIoC(sourceID, "198.51.100.21")
+		fresh.ExpiresAt = now.Add(time.Hour)
+		permanent := newIoC(sourceID, "198.51.100.22")
+		inactive := newIoC(sourceID, "198.51.100.23")
+		inactive.ExpiresAt = now.Add(-time.Hour)
+		inactive.Status = model.IoCStatusInactive
+
+		_, err := repo.BatchUpsertIoCs(ctx, []*model.IoC{expired, fresh, permanent, inactive})
+		gt.NoError(t, err)
+
+		got, err := repo.ListExpiredIoCs(ctx, now)
+		gt.NoError(t, err)
+
+		ids := make(map[string]bool)
+		for _, ioc := range got {
+			ids[ioc.ID] = true
+		}
+		gt.True(t, ids[expired.ID])
+		gt.False(t, ids[fresh.ID])
+		gt.False(t, ids[permanent.ID])
+		gt.False(t, ids[inactive.ID])
+	})
+}
+
+func TestIoCStatusRepository_Memory(t *testing.T) {
+	repo := memory.New()
+	runIoCStatusRepositoryTest(t, repo)
+}
+
+func TestIoCStatusRepository_Firestore(t *testing.T) {
+	projectID := os.Getenv("TEST_FIRESTORE_PROJECT_ID")
+	databaseID := os.Getenv("TEST_FIRESTORE_DATABASE_ID")
+
+	if projectID == "" || databaseID == "" {
+		t.Skip("TEST_FIRESTORE_PROJECT_ID and TEST_FIRESTORE_DATABASE_ID environment variables not set")
+	}
+
+	ctx := context.Background()
+	repo, err := firestoreRepo.New(ctx, projectID, firestoreRepo.WithDatabaseID(databaseID))
+	if err != nil {
+		t.Fatalf("failed to create Firestore repository: %v", err)
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	runIoCStatusRepositoryTest(t, repo)
+}
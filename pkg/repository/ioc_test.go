@@ -2,8 +2,11 @@ package repository_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -156,6 +159,216 @@ func runIoCRepositoryTest(t *testing.T, repo interfaces.IoCRepository) {
 		}
 	})
 
+	t.Run("upsert preserves analyst curation", func(t *testing.T) {
+		sourceID := time.Now().Format("source-20060102-150405.000000")
+		value := time.Now().Format("curated-20060102-150405.000000.com")
+		iocID := model.GenerateID(sourceID, model.IoCTypeDomain, value, "")
+
+		gt.NoError(t, repo.UpsertIoC(ctx, &model.IoC{
+			ID:          iocID,
+			SourceID:    sourceID,
+			SourceType:  "feed",
+			Type:        model.IoCTypeDomain,
+			Value:       value,
+			Description: "Feed description",
+			Status:      model.IoCStatusActive,
+		}))
+
+		// Analyst marks the IoC as false positive
+		curated, err := repo.GetIoC(ctx, iocID)
+		gt.NoError(t, err)
+		curated.Tags = []string{"reviewed"}
+		curated.Notes = "Sinkholed by vendor"
+		curated.Override = &model.IoCOverride{
+			Status:    model.IoCStatusFalsePositive,
+			UpdatedAt: time.Now(),
+		}
+		curated.ApplyOverride()
+		gt.NoError(t, repo.PutIoC(ctx, curated))
+
+		// Feed refresh with changed description must not reset curation
+		result, err := repo.BatchUpsertIoCs(ctx, []*model.IoC{{
+			ID:          iocID,
+			SourceID:    sourceID,
+			SourceType:  "feed",
+			Type:        model.IoCTypeDomain,
+			Value:       value,
+			Description: "Refreshed description",
+			Status:      model.IoCStatusActive,
+		}})
+		gt.NoError(t, err)
+		gt.Equal(t, result.Updated, 1)
+
+		got, err := repo.GetIoC(ctx, iocID)
+		gt.NoError(t, err)
+		gt.Equal(t, got.Status, model.IoCStatusFalsePositive)
+		gt.Equal(t, got.Description, "Refreshed description")
+		gt.Equal(t, got.Notes, "Sinkholed by vendor")
+		gt.A(t, got.Tags).Length(1).At(0, func(t testing.TB, v string) {
+			gt.Equal(t, v, "reviewed")
+		})
+	})
+
+	t.Run("put and delete IoC", func(t *testing.T) {
+		value := time.Now().Format("manual-20060102-150405.000000.com")
+		iocID := model.GenerateID(model.ManualSourceID, model.IoCTypeDomain, value, "")
+
+		gt.NoError(t, repo.PutIoC(ctx, &model.IoC{
+			ID:         iocID,
+			SourceID:   model.ManualSourceID,
+			SourceType: string(model.SourceTypeManual),
+			Type:       model.IoCTypeDomain,
+			Value:      value,
+			Status:     model.IoCStatusActive,
+			Notes:      "Seen in incident",
+		}))
+
+		got, err := repo.GetIoC(ctx, iocID)
+		gt.NoError(t, err)
+		gt.Equal(t, got.Notes, "Seen in incident")
+		gt.False(t, got.FirstSeenAt.IsZero())
+
+		gt.NoError(t, repo.DeleteIoC(ctx, iocID))
+
+		_, err = repo.GetIoC(ctx, iocID)
+		gt.True(t, errors.Is(err, interfaces.ErrIoCNotFound))
+
+		err = repo.DeleteIoC(ctx, iocID)
+		gt.True(t, errors.Is(err, interfaces.ErrIoCNotFound))
+	})
+
+	t.Run("list IoCs by tag", func(t *testing.T) {
+		suffix := time.Now().Format("20060102-150405.000000")
+		tag := "tag-" + suffix
+		newIoC := func(sourceID, value string, tags ...string) *model.IoC {
+			return &model.IoC{
+				ID:         model.GenerateID(sourceID, model.IoCTypeDomain, value, ""),
+				SourceID:   sourceID,
+				SourceType: string(model.SourceTypeFeed),
+				Type:       model.IoCTypeDomain,
+				Value:      value,
+				Status:     model.IoCStatusActive,
+				Tags:       tags,
+			}
+		}
+		tagged := newIoC("feed-untagged-"+suffix, "tagged-"+suffix+".com", tag)
+		fromSource := newIoC("feed-tagged-"+suffix, "source-"+suffix+".com", tag)
+		other := newIoC("feed-untagged-"+suffix, "other-"+suffix+".com")
+		tagged.Confidence = 80
+		fromSource.Confidence = 40
+		for _, ioc := range []*model.IoC{tagged, fromSource, other} {
+			gt.NoError(t, repo.PutIoC(ctx, ioc))
+		}
+		defer func() {
+			for _, ioc := range []*model.IoC{tagged, fromSource, other} {
+				_ = repo.DeleteIoC(ctx, ioc.ID)
+			}
+		}()
+
+		filter := &model.IoCTagFilter{Tag: tag, SourceIDs: []string{fromSource.SourceID}}
+		iocs, err := repo.ListIoCsByTag(ctx, filter)
+		gt.NoError(t, err)
+		ids := make([]string, len(iocs))
+		for i, ioc := range iocs {
+			ids[i] = ioc.ID
+		}
+		slices.Sort(ids)
+		want := []string{tagged.ID, fromSource.ID}
+		slices.Sort(want)
+		gt.A(t, ids).Equal(want)
+
+		active := &model.IoCTagFilter{Tag: tag, SourceIDs: filter.SourceIDs, Status: model.IoCStatusActive, MinConfidence: 50}
+		iocs, err = repo.ListIoCsByTag(ctx, active)
+		gt.NoError(t, err)
+		gt.A(t, iocs).Length(1).At(0, func(t testing.TB, ioc *model.IoC) {
+			gt.Equal(t, ioc.ID, tagged.ID)
+		})
+
+		active.Status = model.IoCStatusInactive
+		iocs, err = repo.ListIoCsByTag(ctx, active)
+		gt.NoError(t, err)
+		gt.A(t, iocs).Length(0)
+
+		filter.UpdatedSince = time.Now().Add(time.Hour)
+		iocs, err = repo.ListIoCsByTag(ctx, filter)
+		gt.NoError(t, err)
+		gt.A(t, iocs).Length(0)
+	})
+
+	t.Run("unchanged re-sighting refreshes expiry", func(t *testing.T) {
+		value := time.Now().Format("seen-20060102-150405.000000.com")
+		seenAt := time.Now().Add(-2 * time.Hour).Truncate(time.Millisecond)
+		newIoC := func(seenAt time.Time) *model.IoC {
+			return &model.IoC{
+				ID:         model.GenerateID("feed-seen", model.IoCTypeDomain, value, ""),
+				SourceID:   "feed-seen",
+				SourceType: string(model.SourceTypeFeed),
+				Type:       model.IoCTypeDomain,
+				Value:      value,
+				Status:     model.IoCStatusActive,
+				LastSeenAt: seenAt,
+				ExpiresAt:  seenAt.Add(time.Hour),
+			}
+		}
+		_, err := repo.BatchUpsertIoCs(ctx, []*model.IoC{newIoC(seenAt)})
+		gt.NoError(t, err)
+		first, err := repo.GetIoC(ctx, newIoC(seenAt).ID)
+		gt.NoError(t, err)
+
+		resighted := newIoC(seenAt.Add(2 * time.Hour))
+		result, err := repo.BatchUpsertIoCs(ctx, []*model.IoC{resighted})
+		gt.NoError(t, err)
+		gt.Equal(t, result.Unchanged, 1)
+
+		got, err := repo.GetIoC(ctx, resighted.ID)
+		gt.NoError(t, err)
+		gt.True(t, got.LastSeenAt.Equal(resighted.LastSeenAt))
+		gt.True(t, got.ExpiresAt.Equal(resighted.ExpiresAt))
+		gt.True(t, got.UpdatedAt.After(first.UpdatedAt))
+		gt.True(t, got.FirstSeenAt.Equal(first.FirstSeenAt))
+		gt.NoError(t, repo.DeleteIoC(ctx, got.ID))
+	})
+
+	t.Run("create IoC only once", func(t *testing.T) {
+		value := time.Now().Format("create-20060102-150405.000000.com")
+		newIoC := func() *model.IoC {
+			return &model.IoC{
+				ID:         model.GenerateID(model.ManualSourceID, model.IoCTypeDomain, value, ""),
+				SourceID:   model.ManualSourceID,
+				SourceType: string(model.SourceTypeManual),
+				Type:       model.IoCTypeDomain,
+				Value:      value,
+				Status:     model.IoCStatusActive,
+			}
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, 5)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = repo.CreateIoC(ctx, newIoC())
+			}()
+		}
+		wg.Wait()
+
+		created := 0
+		for _, err := range errs {
+			if err == nil {
+				created++
+			} else {
+				gt.True(t, errors.Is(err, interfaces.ErrIoCExists))
+			}
+		}
+		gt.Equal(t, created, 1)
+
+		got, err := repo.GetIoC(ctx, newIoC().ID)
+		gt.NoError(t, err)
+		gt.False(t, got.FirstSeenAt.IsZero())
+		gt.NoError(t, repo.DeleteIoC(ctx, got.ID))
+	})
+
 	t.Run("get non-existent IoC returns error", func(t *testing.T) {
 		_, err := repo.GetIoC(ctx, "non-existent-id")
 		gt.Error(t, err)
@@ -358,6 +571,112 @@ func runIoCRepositoryTest(t *testing.T, repo interfaces.IoCRepository) {
 		}
 	})
 
+	t.Run("confidence scores", func(t *testing.T) {
+		now := time.Now()
+		value := now.Format("conf-20060102-150405.000000.example")
+		sourceA := now.Format("source-a-20060102-150405.000000")
+		sourceB := now.Format("source-b-20060102-150405.000000")
+
+		low := &model.IoC{
+			ID:         model.GenerateID(sourceA, model.IoCTypeDomain, value, ""),
+			SourceID:   sourceA,
+			SourceType: "feed",
+			Type:       model.IoCTypeDomain,
+			Value:      value,
+			Status:     model.IoCStatusActive,
+			Confidence: 20,
+		}
+		high := &model.IoC{
+			ID:               model.GenerateID(sourceB, model.IoCTypeDomain, value, "entry-1"),
+			SourceID:         sourceB,
+			SourceType:       "feed",
+			Type:             model.IoCTypeDomain,
+			Value:            value,
+			Status:           model.IoCStatusActive,
+			Confidence:       97,
+			SourceConfidence: 90,
+		}
+		_, err := repo.BatchUpsertIoCs(ctx, []*model.IoC{low, high})
+		gt.NoError(t, err)
+
+		sources, err := repo.ListSourceIDsByValues(ctx, model.IoCTypeDomain, []string{value, "unknown.example"})
+		gt.NoError(t, err)
+		gt.A(t, sources[value]).Length(2).Has(sourceA).Has(sourceB)
+		_, ok := sources["unknown.example"]
+		gt.False(t, ok)
+
+		// Threshold filter
+		result, err := repo.ListIoCs(ctx, &model.IoCListOptions{MinConfidence: 96, Limit: 100})
+		gt.NoError(t, err)
+		var ids []string
+		for _, ioc := range result.Items {
+			gt.N(t, ioc.Confidence).GreaterOrEqual(96)
+			ids = append(ids, ioc.ID)
+		}
+		gt.A(t, ids).Has(high.ID)
+		gt.N(t, result.Total).Equal(len(result.Items))
+
+		// Sort by confidence
+		result, err = repo.ListIoCs(ctx, &model.IoCListOptions{
+			SortField: model.IoCSortByConfidence,
+			SortOrder: model.SortOrderDesc,
+			Limit:     100,
+		})
+		gt.NoError(t, err)
+		for i := 1; i < len(result.Items); i++ {
+			gt.N(t, result.Items[i].Confidence).LessOrEqual(result.Items[i-1].Confidence)
+		}
+
+		// Updating scores keeps UpdatedAt
+		before, err := repo.GetIoC(ctx, low.ID)
+		gt.NoError(t, err)
+		gt.NoError(t, repo.UpdateIoCConfidences(ctx, map[string]int{
+			low.ID:       55,
+			"unknown-id": 10,
+		}))
+		after, err := repo.GetIoC(ctx, low.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, after.Confidence, 55)
+		gt.True(t, after.UpdatedAt.Equal(before.UpdatedAt))
+
+		// A changed feed-native confidence is an update
+		changed := *high
+		changed.SourceConfidence = 50
+		upsert, err := repo.BatchUpsertIoCs(ctx, []*model.IoC{&changed})
+		gt.NoError(t, err)
+		gt.Equal(t, upsert.Updated, 1)
+	})
+
+	t.Run("update embeddings", func(t *testing.T) {
+		sourceID := time.Now().Format("source-20060102-150405.000000")
+		ioc := &model.IoC{
+			ID:        model.GenerateID(sourceID, model.IoCTypeDomain, "embed.example", ""),
+			SourceID:  sourceID,
+			Type:      model.IoCTypeDomain,
+			Value:     "embed.example",
+			Status:    model.IoCStatusActive,
+			Embedding: make(firestore.Vector32, model.EmbeddingDimension),
+		}
+		gt.NoError(t, repo.UpsertIoC(ctx, ioc))
+		before, err := repo.GetIoC(ctx, ioc.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, before.EmbeddingModel, "")
+
+		vector := []float32{0.6, 0.8, 0}
+		gt.NoError(t, repo.UpdateIoCEmbeddings(ctx, map[string]*model.IoCEmbedding{
+			ioc.ID:       {Vector: vector, Model: "local/nomic-embed-text"},
+			"unknown-id": {Vector: vector, Model: "local/nomic-embed-text"},
+		}))
+
+		after, err := repo.GetIoC(ctx, ioc.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, []float32(after.Embedding), vector)
+		gt.Equal(t, after.EmbeddingModel, "local/nomic-embed-text")
+		gt.Equal(t, after.EmbeddingDimension, 3)
+		gt.True(t, after.UpdatedAt.Equal(before.UpdatedAt))
+		gt.NoError(t, model.ValidateIoC(after))
+	})
+
 	t.Run("different IoC types", func(t *testing.T) {
 		sourceID := time.Now().Format("source-20060102-150405.000000")
 
@@ -417,6 +736,39 @@ func runIoCRepositoryTest(t *testing.T, repo interfaces.IoCRepository) {
 			})
 		}
 	})
+
+	t.Run("filter by type", func(t *testing.T) {
+		sourceID := time.Now().Format("source-types-20060102-150405.000000")
+		values := map[model.IoCType]string{
+			model.IoCTypeRegKey:    time.Now().Format(`HKEY_CURRENT_USER\Software\Run\20060102150405.000000`),
+			model.IoCTypeMutex:     time.Now().Format(`Global\Mtx_20060102150405.000000`),
+			model.IoCTypeUserAgent: time.Now().Format("Loader/20060102.150405.000000"),
+		}
+		for iocType, value := range values {
+			gt.NoError(t, repo.UpsertIoC(ctx, &model.IoC{
+				ID:         model.GenerateID(sourceID, iocType, value, ""),
+				SourceID:   sourceID,
+				SourceType: "test",
+				Type:       iocType,
+				Value:      value,
+				Status:     model.IoCStatusActive,
+			}))
+		}
+
+		result, err := repo.ListIoCs(ctx, &model.IoCListOptions{
+			Types: []model.IoCType{model.IoCTypeRegKey, model.IoCTypeMutex},
+			Limit: 1000,
+		})
+		gt.NoError(t, err)
+		found := make(map[string]bool)
+		for _, ioc := range result.Items {
+			gt.True(t, ioc.Type == model.IoCTypeRegKey || ioc.Type == model.IoCTypeMutex)
+			found[ioc.Value] = true
+		}
+		gt.True(t, found[values[model.IoCTypeRegKey]])
+		gt.True(t, found[values[model.IoCTypeMutex]])
+		gt.False(t, found[values[model.IoCTypeUserAgent]])
+	})
 }
 
 func TestIoCRepository_Memory(t *testing.T) {
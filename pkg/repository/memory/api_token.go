@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+var _ interfaces.APITokenRepository = &Memory{}
+
+func copyAPIToken(t *model.APIToken) *model.APIToken {
+	c := *t
+	c.Scopes = append([]model.TokenScope(nil), t.Scopes...)
+	return &c
+}
+
+// PutAPIToken creates or replaces an API token
+func (m *Memory) PutAPIToken(ctx context.Context, token *model.APIToken) error {
+	if token.ID == "" {
+		return goerr.New("API token ID cannot be empty")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.apiTokens[token.ID] = copyAPIToken(token)
+	return nil
+}
+
+// GetAPIToken retrieves an API token by ID
+func (m *Memory) GetAPIToken(ctx context.Context, id string) (*model.APIToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	t, ok := m.apiTokens[id]
+	if !ok {
+		return nil, goerr.Wrap(interfaces.ErrAPITokenNotFound, "API token not found", goerr.V("id", id))
+	}
+	return copyAPIToken(t), nil
+}
+
+// GetAPITokenByHash retrieves the API token with a hash
+func (m *Memory) GetAPITokenByHash(ctx context.Context, hash string) (*model.APIToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, t := range m.apiTokens {
+		if t.Hash == hash {
+			return copyAPIToken(t), nil
+		}
+	}
+	return nil, goerr.Wrap(interfaces.ErrAPITokenNotFound, "API token not found")
+}
+
+// ListAPITokens retrieves all API tokens, newest first
+func (m *Memory) ListAPITokens(ctx context.Context) ([]*model.APIToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]*model.APIToken, 0, len(m.apiTokens))
+	for _, t := range m.apiTokens {
+		result = append(result, copyAPIToken(t))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+	return result, nil
+}
+
+// TouchAPIToken sets the last-used time of an API token
+func (m *Memory) TouchAPIToken(ctx context.Context, id string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.apiTokens[id]
+	if !ok {
+		return goerr.Wrap(interfaces.ErrAPITokenNotFound, "API token not found", goerr.V("id", id))
+	}
+	t.LastUsedAt = at
+	return nil
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+var _ interfaces.AuditRepository = &Memory{}
+
+func copyAuditRecord(r *model.AuditRecord) *model.AuditRecord {
+	c := *r
+	c.Changes = append([]model.AuditChange(nil), r.Changes...)
+	return &c
+}
+
+// SaveAuditRecord appends an audit record
+func (m *Memory) SaveAuditRecord(ctx context.Context, record *model.AuditRecord) error {
+	if record.ID == "" {
+		return goerr.New("audit record ID cannot be empty", goerr.V("action", record.Action))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if slices.ContainsFunc(m.auditRecords, func(r *model.AuditRecord) bool { return r.ID == record.ID }) {
+		return goerr.New("audit record already exists", goerr.V("id", record.ID))
+	}
+	m.auditRecords = append(m.auditRecords, copyAuditRecord(record))
+	return nil
+}
+
+// ListAuditRecords retrieves the audit records matching filter, newest first
+func (m *Memory) ListAuditRecords(ctx context.Context, filter *model.AuditFilter) ([]*model.AuditRecord, error) {
+	if filter == nil {
+		filter = &model.AuditFilter{}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*model.AuditRecord
+	for _, r := range m.auditRecords {
+		if filter.Match(r) {
+			result = append(result, copyAuditRecord(r))
+		}
+	}
+	// Stable so that records of the same time are newest first by saving order
+	slices.Reverse(result)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+var _ interfaces.BrandMatchRepository = &Memory{}
+
+// SaveBrandMatches stores matches that do not exist yet and returns them
+func (m *Memory) SaveBrandMatches(ctx context.Context, matches []*model.BrandMatch) ([]*model.BrandMatch, error) {
+	for _, match := range matches {
+		if match.ID == "" {
+			return nil, goerr.New("brand match ID cannot be empty", goerr.V("ioc_id", match.IoCID))
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var stored []*model.BrandMatch
+	for _, match := range matches {
+		if _, ok := m.brandMatches[match.ID]; ok {
+			continue
+		}
+		matchCopy := *match
+		m.brandMatches[match.ID] = &matchCopy
+		stored = append(stored, match)
+	}
+	return stored, nil
+}
+
+// ListBrandMatches retrieves brand matches, newest first
+func (m *Memory) ListBrandMatches(ctx context.Context, opts *model.BrandMatchListOptions) (*model.BrandMatchConnection, error) {
+	if opts == nil {
+		opts = &model.BrandMatchListOptions{}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var filtered []*model.BrandMatch
+	for _, match := range m.brandMatches {
+		if opts.ProtectedDomain != "" && match.ProtectedDomain != opts.ProtectedDomain {
+			continue
+		}
+		if opts.Technique != "" && match.Technique != opts.Technique {
+			continue
+		}
+		matchCopy := *match
+		filtered = append(filtered, &matchCopy)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		if !filtered[i].CreatedAt.Equal(filtered[j].CreatedAt) {
+			return filtered[i].CreatedAt.After(filtered[j].CreatedAt)
+		}
+		return filtered[i].ID < filtered[j].ID
+	})
+
+	total := len(filtered)
+	start := min(max(opts.Offset, 0), total)
+	end := total
+	if opts.Limit > 0 {
+		end = min(start+opts.Limit, total)
+	}
+
+	return &model.BrandMatchConnection{
+		Items: filtered[start:end],
+		Total: total,
+	}, nil
+}
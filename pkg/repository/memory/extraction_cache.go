@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+var _ interfaces.ExtractionCacheRepository = &Memory{}
+
+// GetExtractionCache retrieves a cache entry by key
+func (m *Memory) GetExtractionCache(ctx context.Context, key string) (*model.ExtractionCacheEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, ok := m.extractionCache[key]
+	if !ok {
+		return nil, goerr.Wrap(interfaces.ErrExtractionCacheNotFound, "extraction cache entry not found", goerr.V("key", key))
+	}
+	entryCopy := *entry
+	return &entryCopy, nil
+}
+
+// PutExtractionCache creates or replaces a cache entry
+func (m *Memory) PutExtractionCache(ctx context.Context, entry *model.ExtractionCacheEntry) error {
+	if entry.Key == "" {
+		return goerr.New("extraction cache key cannot be empty", goerr.V("source_id", entry.SourceID))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	entryCopy := *entry
+	m.extractionCache[entry.Key] = &entryCopy
+	return nil
+}
+
+// PurgeExtractionCache deletes the cache entries selected by opts
+func (m *Memory) PurgeExtractionCache(ctx context.Context, opts *model.ExtractionCachePurgeOptions) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deleted := 0
+	for key, entry := range m.extractionCache {
+		if opts.Matches(entry) {
+			delete(m.extractionCache, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 	"math"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -13,22 +14,66 @@ import (
 )
 
 type Memory struct {
-	iocs         map[string]*model.IoC         // key: IoC ID
-	sourceStates map[string]*model.SourceState // key: Source ID
-	histories    map[string][]*model.History   // key: Source ID, sorted by StartedAt descending
-	mu           sync.RWMutex
+	iocs              map[string]*model.IoC                   // key: IoC ID
+	statusTransitions map[string][]*model.IoCStatusTransition // key: IoC ID, sorted by CreatedAt ascending
+	sourceStates      map[string]*model.SourceState           // key: Source ID
+	histories         map[string][]*model.History             // key: Source ID, sorted by StartedAt descending
+	notifications     map[string]time.Time                    // key: notification dedup key
+	watchlists        map[string]*model.Watchlist             // key: watchlist ID
+	watchlistHits     map[string]*model.WatchlistHit          // key: hit ID
+	brandMatches      map[string]*model.BrandMatch            // key: match ID
+	reports           map[string]*model.Report                // key: report ID
+	extractionCache   map[string]*model.ExtractionCacheEntry  // key: cache key
+	sources           map[string]*model.Source                // key: Source ID
+	sourceChanges     []*model.SourceChange                   // in order of saving
+	apiTokens         map[string]*model.APIToken              // key: token ID
+	auditRecords      []*model.AuditRecord                    // in order of saving
+	embeddingDim      int                                     // dimension of vector search queries
+	mu                sync.RWMutex
+}
+
+// Option configures Memory
+type Option func(*Memory)
+
+// WithEmbeddingDimension sets the embedding dimension of vector search (default: model.EmbeddingDimension)
+func WithEmbeddingDimension(dim int) Option {
+	return func(m *Memory) {
+		m.embeddingDim = dim
+	}
 }
 
 var _ interfaces.IoCRepository = &Memory{}
+var _ interfaces.IoCStatusRepository = &Memory{}
 var _ interfaces.SourceStateRepository = &Memory{}
 var _ interfaces.HistoryRepository = &Memory{}
+var _ interfaces.NotificationRepository = &Memory{}
+var _ interfaces.WatchlistRepository = &Memory{}
+
+func New(opts ...Option) *Memory {
+	m := &Memory{
+		iocs:              make(map[string]*model.IoC),
+		statusTransitions: make(map[string][]*model.IoCStatusTransition),
+		sourceStates:      make(map[string]*model.SourceState),
+		histories:         make(map[string][]*model.History),
+		notifications:     make(map[string]time.Time),
+		watchlists:        make(map[string]*model.Watchlist),
+		watchlistHits:     make(map[string]*model.WatchlistHit),
+		brandMatches:      make(map[string]*model.BrandMatch),
+		reports:           make(map[string]*model.Report),
+		extractionCache:   make(map[string]*model.ExtractionCacheEntry),
+		sources:           make(map[string]*model.Source),
+		apiTokens:         make(map[string]*model.APIToken),
+		embeddingDim:      model.EmbeddingDimension,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
 
-func New() *Memory {
-	return &Memory{
-		iocs:         make(map[string]*model.IoC),
-		sourceStates: make(map[string]*model.SourceState),
-		histories:    make(map[string][]*model.History),
-	}
+// Ping always succeeds as the memory is always reachable
+func (m *Memory) Ping(ctx context.Context) error {
+	return nil
 }
 
 // GetIoC retrieves an IoC by ID
@@ -76,6 +121,23 @@ func (m *Memory) ListAllIoCs(ctx context.Context) ([]*model.IoC, error) {
 	return result, nil
 }
 
+// ListIoCsByTag lists the IoCs matching a tag filter
+func (m *Memory) ListIoCsByTag(ctx context.Context, filter *model.IoCTagFilter) ([]*model.IoC, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var iocs []*model.IoC
+	for _, ioc := range m.iocs {
+		if !filter.Match(ioc) {
+			continue
+		}
+		iocCopy := *ioc
+		iocs = append(iocs, &iocCopy)
+	}
+
+	return iocs, nil
+}
+
 // ListIoCs lists IoCs with pagination and sorting
 func (m *Memory) ListIoCs(ctx context.Context, opts *model.IoCListOptions) (*model.IoCConnection, error) {
 	m.mu.RLock()
@@ -84,6 +146,12 @@ func (m *Memory) ListIoCs(ctx context.Context, opts *model.IoCListOptions) (*mod
 	// Get all IoCs
 	allIoCs := make([]*model.IoC, 0, len(m.iocs))
 	for _, ioc := range m.iocs {
+		if opts != nil && ioc.Confidence < opts.MinConfidence {
+			continue
+		}
+		if opts != nil && len(opts.Types) > 0 && !slices.Contains(opts.Types, ioc.Type) {
+			continue
+		}
 		iocCopy := *ioc
 		allIoCs = append(allIoCs, &iocCopy)
 	}
@@ -129,6 +197,69 @@ func (m *Memory) ListIoCs(ctx context.Context, opts *model.IoCListOptions) (*mod
 	}, nil
 }
 
+// ListExpiredIoCs lists active IoCs whose expiry time has passed
+func (m *Memory) ListExpiredIoCs(ctx context.Context, now time.Time) ([]*model.IoC, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*model.IoC
+	for _, ioc := range m.iocs {
+		if ioc.Status != model.IoCStatusActive || ioc.ExpiresAt.IsZero() || ioc.ExpiresAt.After(now) {
+			continue
+		}
+		iocCopy := *ioc
+		result = append(result, &iocCopy)
+	}
+
+	return result, nil
+}
+
+// ListSourceIDsByValues returns the distinct source IDs reporting each value
+func (m *Memory) ListSourceIDsByValues(ctx context.Context, iocType model.IoCType, values []string) (map[string][]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(values))
+	for _, v := range values {
+		wanted[v] = true
+	}
+
+	seen := make(map[string]map[string]bool)
+	for _, ioc := range m.iocs {
+		if ioc.Type != iocType || !wanted[ioc.Value] {
+			continue
+		}
+		if seen[ioc.Value] == nil {
+			seen[ioc.Value] = make(map[string]bool)
+		}
+		seen[ioc.Value][ioc.SourceID] = true
+	}
+
+	result := make(map[string][]string, len(seen))
+	for value, sources := range seen {
+		for sourceID := range sources {
+			result[value] = append(result[value], sourceID)
+		}
+		sort.Strings(result[value])
+	}
+
+	return result, nil
+}
+
+// UpdateIoCConfidences sets the confidence score of IoCs without changing UpdatedAt
+func (m *Memory) UpdateIoCConfidences(ctx context.Context, scores map[string]int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, score := range scores {
+		if ioc, ok := m.iocs[id]; ok {
+			ioc.Confidence = score
+		}
+	}
+
+	return nil
+}
+
 // UpsertIoC inserts or updates an IoC
 func (m *Memory) UpsertIoC(ctx context.Context, ioc *model.IoC) error {
 	if err := model.ValidateIoC(ioc); err != nil {
@@ -142,13 +273,20 @@ func (m *Memory) UpsertIoC(ctx context.Context, ioc *model.IoC) error {
 
 	// Check if IoC already exists
 	if existing, ok := m.iocs[ioc.ID]; ok {
+		// Keep analyst curation; sources never provide it
+		ioc.PreserveCuration(existing)
+		ioc.PreserveEmbedding(existing)
+
 		// Existing IoC - check if any field changed (for feed sources, update if anything changed)
-		needsUpdate := existing.Description != ioc.Description ||
-			existing.Status != ioc.Status ||
-			existing.SourceURL != ioc.SourceURL ||
-			existing.Context != ioc.Context
+		needsUpdate := ioc.SourceChanged(existing)
 		if !needsUpdate {
-			// Skip - no changes needed
+			// Only refresh the sighting and score of an unchanged IoC
+			if ioc.Resighted(existing) {
+				existing.LastSeenAt = ioc.LastSeenAt
+				existing.ExpiresAt = ioc.ExpiresAt
+				existing.UpdatedAt = now
+			}
+			existing.Confidence = ioc.Confidence
 			return nil
 		}
 		// Update: preserve FirstSeenAt, update UpdatedAt
@@ -167,6 +305,67 @@ func (m *Memory) UpsertIoC(ctx context.Context, ioc *model.IoC) error {
 	return nil
 }
 
+// CreateIoC stores a new IoC, failing if its ID is already stored
+func (m *Memory) CreateIoC(ctx context.Context, ioc *model.IoC) error {
+	if err := model.ValidateIoC(ioc); err != nil {
+		return goerr.Wrap(err, "invalid IoC")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.iocs[ioc.ID]; ok {
+		return goerr.Wrap(interfaces.ErrIoCExists, "IoC already exists", goerr.V("id", ioc.ID))
+	}
+
+	now := time.Now()
+	ioc.FirstSeenAt = now
+	ioc.UpdatedAt = now
+
+	// Store a copy to prevent external modification
+	iocCopy := *ioc
+	m.iocs[ioc.ID] = &iocCopy
+
+	return nil
+}
+
+// PutIoC stores the IoC as given, preserving FirstSeenAt of an existing record
+func (m *Memory) PutIoC(ctx context.Context, ioc *model.IoC) error {
+	if err := model.ValidateIoC(ioc); err != nil {
+		return goerr.Wrap(err, "invalid IoC")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if existing, ok := m.iocs[ioc.ID]; ok {
+		ioc.FirstSeenAt = existing.FirstSeenAt
+	} else {
+		ioc.FirstSeenAt = now
+	}
+	ioc.UpdatedAt = now
+
+	// Store a copy to prevent external modification
+	iocCopy := *ioc
+	m.iocs[ioc.ID] = &iocCopy
+
+	return nil
+}
+
+// DeleteIoC deletes an IoC by ID
+func (m *Memory) DeleteIoC(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.iocs[id]; !ok {
+		return goerr.Wrap(interfaces.ErrIoCNotFound, "IoC not found", goerr.V("id", id))
+	}
+	delete(m.iocs, id)
+
+	return nil
+}
+
 // BatchUpsertIoCs upserts multiple IoCs in a single operation
 func (m *Memory) BatchUpsertIoCs(ctx context.Context, iocs []*model.IoC) (*interfaces.BatchUpsertResult, error) {
 	result := &interfaces.BatchUpsertResult{}
@@ -187,13 +386,20 @@ func (m *Memory) BatchUpsertIoCs(ctx context.Context, iocs []*model.IoC) (*inter
 
 		// Check if IoC already exists
 		if existing, ok := m.iocs[ioc.ID]; ok {
+			// Keep analyst curation; sources never provide it
+			ioc.PreserveCuration(existing)
+			ioc.PreserveEmbedding(existing)
+
 			// Existing IoC - check if any field changed (for feed sources, update if anything changed)
-			needsUpdate := existing.Description != ioc.Description ||
-				existing.Status != ioc.Status ||
-				existing.SourceURL != ioc.SourceURL ||
-				existing.Context != ioc.Context
+			needsUpdate := ioc.SourceChanged(existing)
 			if !needsUpdate {
-				// Skip - no changes needed
+				// Only refresh the sighting and score of an unchanged IoC
+				if ioc.Resighted(existing) {
+					existing.LastSeenAt = ioc.LastSeenAt
+					existing.ExpiresAt = ioc.ExpiresAt
+					existing.UpdatedAt = now
+				}
+				existing.Confidence = ioc.Confidence
 				result.Unchanged++
 				continue
 			}
@@ -201,11 +407,16 @@ func (m *Memory) BatchUpsertIoCs(ctx context.Context, iocs []*model.IoC) (*inter
 			ioc.FirstSeenAt = existing.FirstSeenAt
 			ioc.UpdatedAt = now
 			result.Updated++
+			if existing.Status != ioc.Status {
+				result.StatusChanges = append(result.StatusChanges,
+					model.NewIoCStatusTransition(ioc.ID, existing.Status, ioc.Status, now))
+			}
 		} else {
 			// New IoC
 			ioc.FirstSeenAt = now
 			ioc.UpdatedAt = now
 			result.Created++
+			result.CreatedIDs = append(result.CreatedIDs, ioc.ID)
 		}
 
 		// Store a copy to prevent external modification
@@ -266,12 +477,26 @@ func (m *Memory) BatchGetStates(ctx context.Context, sourceIDs []string) (map[st
 	return result, nil
 }
 
+// UpdateIoCEmbeddings sets the embedding of IoCs without changing UpdatedAt
+func (m *Memory) UpdateIoCEmbeddings(ctx context.Context, embeddings map[string]*model.IoCEmbedding) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, embedding := range embeddings {
+		if ioc, ok := m.iocs[id]; ok {
+			ioc.SetEmbedding(embedding)
+		}
+	}
+
+	return nil
+}
+
 // FindNearestIoCs performs in-memory vector similarity search
 // This is a simple brute-force implementation for testing
 func (m *Memory) FindNearestIoCs(ctx context.Context, queryVector []float32, limit int) ([]*model.IoC, error) {
-	if len(queryVector) != model.EmbeddingDimension {
+	if len(queryVector) != m.embeddingDim {
 		return nil, goerr.New("invalid query vector dimension",
-			goerr.V("expected", model.EmbeddingDimension),
+			goerr.V("expected", m.embeddingDim),
 			goerr.V("actual", len(queryVector)))
 	}
 
@@ -291,8 +516,8 @@ func (m *Memory) FindNearestIoCs(ctx context.Context, queryVector []float32, lim
 	var candidates []iocWithSimilarity
 
 	for _, ioc := range m.iocs {
-		if len(ioc.Embedding) != model.EmbeddingDimension {
-			continue // Skip IoCs without valid embeddings
+		if len(ioc.Embedding) != len(queryVector) {
+			continue // Skip IoCs without embeddings of the dimension
 		}
 
 		// Calculate cosine similarity
@@ -468,3 +693,69 @@ func (m *Memory) GetHistory(ctx context.Context, sourceID string, historyID stri
 
 	return nil, interfaces.ErrHistoryNotFound
 }
+
+// SaveStatusTransitions appends IoC status transition records
+func (m *Memory) SaveStatusTransitions(ctx context.Context, transitions []*model.IoCStatusTransition) error {
+	for _, tr := range transitions {
+		if tr.IoCID == "" || tr.ID == "" {
+			return goerr.New("IoC ID and transition ID cannot be empty",
+				goerr.V("ioc_id", tr.IoCID),
+				goerr.V("transition_id", tr.ID))
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, tr := range transitions {
+		// Store a copy to prevent external modification
+		trCopy := *tr
+		list := append(m.statusTransitions[tr.IoCID], &trCopy)
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		})
+		m.statusTransitions[tr.IoCID] = list
+	}
+
+	return nil
+}
+
+// ListStatusTransitions retrieves status transitions of an IoC, oldest first
+func (m *Memory) ListStatusTransitions(ctx context.Context, iocID string) ([]*model.IoCStatusTransition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := m.statusTransitions[iocID]
+	result := make([]*model.IoCStatusTransition, len(list))
+	for i, tr := range list {
+		trCopy := *tr
+		result[i] = &trCopy
+	}
+
+	return result, nil
+}
+
+// ClaimNotification records a notification key, returning false if it was already recorded
+func (m *Memory) ClaimNotification(ctx context.Context, key string, at time.Time) (bool, error) {
+	if key == "" {
+		return false, goerr.New("notification key cannot be empty")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.notifications[key]; ok {
+		return false, nil
+	}
+	m.notifications[key] = at
+	return true, nil
+}
+
+// ReleaseNotification removes a notification key
+func (m *Memory) ReleaseNotification(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.notifications, key)
+	return nil
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+var _ interfaces.ReportRepository = &Memory{}
+
+// PutReport creates or replaces a report, preserving CreatedAt of an existing report
+func (m *Memory) PutReport(ctx context.Context, report *model.Report) error {
+	if report.ID == "" {
+		return goerr.New("report ID cannot be empty", goerr.V("source_id", report.SourceID))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if existing, ok := m.reports[report.ID]; ok {
+		report.CreatedAt = existing.CreatedAt
+	} else if report.CreatedAt.IsZero() {
+		report.CreatedAt = now
+	}
+	report.UpdatedAt = now
+
+	reportCopy := *report
+	m.reports[report.ID] = &reportCopy
+	return nil
+}
+
+// GetReport retrieves a report by ID
+func (m *Memory) GetReport(ctx context.Context, id string) (*model.Report, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	report, ok := m.reports[id]
+	if !ok {
+		return nil, goerr.Wrap(interfaces.ErrReportNotFound, "report not found", goerr.V("id", id))
+	}
+	reportCopy := *report
+	return &reportCopy, nil
+}
+
+// ListReports retrieves reports, newest first
+func (m *Memory) ListReports(ctx context.Context, opts *model.ReportListOptions) (*model.ReportConnection, error) {
+	if opts == nil {
+		opts = &model.ReportListOptions{}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var filtered []*model.Report
+	for _, report := range m.reports {
+		if opts.SourceID != "" && report.SourceID != opts.SourceID {
+			continue
+		}
+		reportCopy := *report
+		filtered = append(filtered, &reportCopy)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		if !filtered[i].PublishedAt.Equal(filtered[j].PublishedAt) {
+			return filtered[i].PublishedAt.After(filtered[j].PublishedAt)
+		}
+		return filtered[i].ID < filtered[j].ID
+	})
+
+	total := len(filtered)
+	start := min(max(opts.Offset, 0), total)
+	end := total
+	if opts.Limit > 0 {
+		end = min(start+opts.Limit, total)
+	}
+
+	return &model.ReportConnection{
+		Items: filtered[start:end],
+		Total: total,
+	}, nil
+}
+
+// ListIoCsByReport lists the IoCs extracted from a report
+func (m *Memory) ListIoCsByReport(ctx context.Context, reportID string) ([]*model.IoC, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*model.IoC
+	for _, ioc := range m.iocs {
+		if ioc.ReportID == reportID {
+			iocCopy := *ioc
+			result = append(result, &iocCopy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	return result, nil
+}
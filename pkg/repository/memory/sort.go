@@ -26,6 +26,8 @@ func sortIoCs(iocs []*model.IoC, sortField model.IoCSortField, sortOrder model.S
 			less = iocs[i].FirstSeenAt.Before(iocs[j].FirstSeenAt)
 		case model.IoCSortByUpdatedAt:
 			less = iocs[i].UpdatedAt.Before(iocs[j].UpdatedAt)
+		case model.IoCSortByConfidence:
+			less = iocs[i].Confidence < iocs[j].Confidence
 		default:
 			// Default sort by UpdatedAt descending
 			less = iocs[i].UpdatedAt.After(iocs[j].UpdatedAt)
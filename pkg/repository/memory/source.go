@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+var _ interfaces.SourceRepository = &Memory{}
+
+func copySource(s *model.Source) *model.Source {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.Tags = append([]string(nil), s.Tags...)
+	if s.TTL != nil {
+		ttl := *s.TTL
+		c.TTL = &ttl
+	}
+	if s.Reliability != nil {
+		r := *s.Reliability
+		c.Reliability = &r
+	}
+	if s.RSSConfig != nil {
+		rss := *s.RSSConfig
+		if rss.Prompt != nil {
+			prompt := *rss.Prompt
+			rss.Prompt = &prompt
+		}
+		c.RSSConfig = &rss
+	}
+	if s.FeedConfig != nil {
+		feed := *s.FeedConfig
+		c.FeedConfig = &feed
+	}
+	return &c
+}
+
+func copySourceChange(change *model.SourceChange) *model.SourceChange {
+	c := *change
+	c.Fields = append([]string(nil), change.Fields...)
+	c.Before = copySource(change.Before)
+	c.After = copySource(change.After)
+	return &c
+}
+
+// PutSource creates or replaces a source
+func (m *Memory) PutSource(ctx context.Context, source *model.Source) error {
+	if source.ID == "" {
+		return goerr.New("source ID cannot be empty")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sources[source.ID] = copySource(source)
+	return nil
+}
+
+// GetSource retrieves a source by ID
+func (m *Memory) GetSource(ctx context.Context, id string) (*model.Source, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s, ok := m.sources[id]
+	if !ok {
+		return nil, goerr.Wrap(interfaces.ErrSourceNotFound, "source not found", goerr.V("id", id))
+	}
+	return copySource(s), nil
+}
+
+// ListSources retrieves all sources ordered by ID
+func (m *Memory) ListSources(ctx context.Context) ([]*model.Source, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]*model.Source, 0, len(m.sources))
+	for _, s := range m.sources {
+		result = append(result, copySource(s))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// DeleteSource deletes a source, keeping its state, histories and change records
+func (m *Memory) DeleteSource(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sources[id]; !ok {
+		return goerr.Wrap(interfaces.ErrSourceNotFound, "source not found", goerr.V("id", id))
+	}
+	delete(m.sources, id)
+	return nil
+}
+
+// SaveSourceChange appends a source change record
+func (m *Memory) SaveSourceChange(ctx context.Context, change *model.SourceChange) error {
+	if change.ID == "" {
+		return goerr.New("source change ID cannot be empty", goerr.V("source_id", change.SourceID))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sourceChanges = append(m.sourceChanges, copySourceChange(change))
+	return nil
+}
+
+// ListSourceChanges retrieves change records of a source, or of all sources if
+// sourceID is empty, newest first
+func (m *Memory) ListSourceChanges(ctx context.Context, sourceID string, limit int) ([]*model.SourceChange, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*model.SourceChange
+	for _, c := range m.sourceChanges {
+		if sourceID != "" && c.SourceID != sourceID {
+			continue
+		}
+		result = append(result, copySourceChange(c))
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+func copyWatchlist(w *model.Watchlist) *model.Watchlist {
+	c := *w
+	c.Patterns = append([]model.WatchPattern(nil), w.Patterns...)
+	return &c
+}
+
+// PutWatchlist creates or replaces a watchlist
+func (m *Memory) PutWatchlist(ctx context.Context, watchlist *model.Watchlist) error {
+	if watchlist.ID == "" {
+		return goerr.New("watchlist ID cannot be empty")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.watchlists[watchlist.ID] = copyWatchlist(watchlist)
+	return nil
+}
+
+// GetWatchlist retrieves a watchlist by ID
+func (m *Memory) GetWatchlist(ctx context.Context, id string) (*model.Watchlist, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	w, ok := m.watchlists[id]
+	if !ok {
+		return nil, goerr.Wrap(interfaces.ErrWatchlistNotFound, "watchlist not found", goerr.V("id", id))
+	}
+	return copyWatchlist(w), nil
+}
+
+// ListWatchlists retrieves all watchlists ordered by name
+func (m *Memory) ListWatchlists(ctx context.Context) ([]*model.Watchlist, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]*model.Watchlist, 0, len(m.watchlists))
+	for _, w := range m.watchlists {
+		result = append(result, copyWatchlist(w))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// DeleteWatchlist deletes a watchlist, keeping its hits
+func (m *Memory) DeleteWatchlist(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.watchlists[id]; !ok {
+		return goerr.Wrap(interfaces.ErrWatchlistNotFound, "watchlist not found", goerr.V("id", id))
+	}
+	delete(m.watchlists, id)
+	return nil
+}
+
+// SaveWatchlistHits stores hits that do not exist yet and returns them
+func (m *Memory) SaveWatchlistHits(ctx context.Context, hits []*model.WatchlistHit) ([]*model.WatchlistHit, error) {
+	for _, hit := range hits {
+		if hit.ID == "" {
+			return nil, goerr.New("watchlist hit ID cannot be empty", goerr.V("ioc_id", hit.IoCID))
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var stored []*model.WatchlistHit
+	for _, hit := range hits {
+		if _, ok := m.watchlistHits[hit.ID]; ok {
+			continue
+		}
+		hitCopy := *hit
+		m.watchlistHits[hit.ID] = &hitCopy
+		stored = append(stored, hit)
+	}
+	return stored, nil
+}
+
+// GetWatchlistHit retrieves a watchlist hit by ID
+func (m *Memory) GetWatchlistHit(ctx context.Context, id string) (*model.WatchlistHit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	hit, ok := m.watchlistHits[id]
+	if !ok {
+		return nil, goerr.Wrap(interfaces.ErrWatchlistHitNotFound, "watchlist hit not found", goerr.V("id", id))
+	}
+	hitCopy := *hit
+	return &hitCopy, nil
+}
+
+// ListWatchlistHits retrieves watchlist hits, newest first
+func (m *Memory) ListWatchlistHits(ctx context.Context, opts *model.WatchlistHitListOptions) (*model.WatchlistHitConnection, error) {
+	if opts == nil {
+		opts = &model.WatchlistHitListOptions{}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var filtered []*model.WatchlistHit
+	for _, hit := range m.watchlistHits {
+		if opts.WatchlistID != "" && hit.WatchlistID != opts.WatchlistID {
+			continue
+		}
+		if opts.Acknowledged != nil && hit.Acknowledged != *opts.Acknowledged {
+			continue
+		}
+		hitCopy := *hit
+		filtered = append(filtered, &hitCopy)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		if !filtered[i].CreatedAt.Equal(filtered[j].CreatedAt) {
+			return filtered[i].CreatedAt.After(filtered[j].CreatedAt)
+		}
+		return filtered[i].ID < filtered[j].ID
+	})
+
+	total := len(filtered)
+	start := min(max(opts.Offset, 0), total)
+	end := total
+	if opts.Limit > 0 {
+		end = min(start+opts.Limit, total)
+	}
+
+	return &model.WatchlistHitConnection{
+		Items: filtered[start:end],
+		Total: total,
+	}, nil
+}
+
+// AcknowledgeWatchlistHits marks hits as acknowledged
+func (m *Memory) AcknowledgeWatchlistHits(ctx context.Context, ids []string, actor string, at time.Time) ([]*model.WatchlistHit, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range ids {
+		if _, ok := m.watchlistHits[id]; !ok {
+			return nil, goerr.Wrap(interfaces.ErrWatchlistHitNotFound, "watchlist hit not found", goerr.V("id", id))
+		}
+	}
+
+	result := make([]*model.WatchlistHit, 0, len(ids))
+	for _, id := range ids {
+		hit := m.watchlistHits[id]
+		hit.Acknowledged = true
+		hit.AcknowledgedBy = actor
+		hit.AcknowledgedAt = at
+		hitCopy := *hit
+		result = append(result, &hitCopy)
+	}
+	return result, nil
+}
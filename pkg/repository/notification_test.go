@@ -0,0 +1,74 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+)
+
+func runNotificationRepositoryTest(t *testing.T, repo interfaces.NotificationRepository) {
+	ctx := context.Background()
+
+	t.Run("claim is granted once", func(t *testing.T) {
+		key := time.Now().Format("watchlist:rule/a:20060102-150405.000000")
+
+		ok, err := repo.ClaimNotification(ctx, key, time.Now())
+		gt.NoError(t, err)
+		gt.True(t, ok)
+
+		ok, err = repo.ClaimNotification(ctx, key, time.Now())
+		gt.NoError(t, err)
+		gt.False(t, ok)
+	})
+
+	t.Run("released claim can be claimed again", func(t *testing.T) {
+		key := time.Now().Format("digest:daily:20060102-150405.000000")
+
+		ok, err := repo.ClaimNotification(ctx, key, time.Now())
+		gt.NoError(t, err)
+		gt.True(t, ok)
+
+		gt.NoError(t, repo.ReleaseNotification(ctx, key))
+
+		ok, err = repo.ClaimNotification(ctx, key, time.Now())
+		gt.NoError(t, err)
+		gt.True(t, ok)
+	})
+
+	t.Run("empty key is rejected", func(t *testing.T) {
+		_, err := repo.ClaimNotification(ctx, "", time.Now())
+		gt.Error(t, err)
+	})
+}
+
+func TestNotificationRepository_Memory(t *testing.T) {
+	runNotificationRepositoryTest(t, memory.New())
+}
+
+func TestNotificationRepository_Firestore(t *testing.T) {
+	projectID := os.Getenv("TEST_FIRESTORE_PROJECT_ID")
+	databaseID := os.Getenv("TEST_FIRESTORE_DATABASE_ID")
+
+	if projectID == "" || databaseID == "" {
+		t.Skip("TEST_FIRESTORE_PROJECT_ID and TEST_FIRESTORE_DATABASE_ID environment variables not set")
+	}
+
+	ctx := context.Background()
+	repo, err := firestoreRepo.New(ctx, projectID, firestoreRepo.WithDatabaseID(databaseID))
+	if err != nil {
+		t.Fatalf("failed to create Firestore repository: %v", err)
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	runNotificationRepositoryTest(t, repo)
+}
@@ -0,0 +1,159 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+)
+
+type reportTestRepository interface {
+	interfaces.ReportRepository
+	interfaces.IoCRepository
+}
+
+func runReportRepositoryTest(t *testing.T, repo reportTestRepository) {
+	ctx := context.Background()
+
+	// Unique per run so that Firestore tests do not see each other's data
+	sourceID := time.Now().Format("report-source-20060102-150405.000000")
+	base := time.Now().UTC().Truncate(time.Millisecond)
+
+	newReport := func(guid string, publishedAt time.Time) *model.Report {
+		return &model.Report{
+			ID:               model.GenerateReportID(sourceID, guid),
+			SourceID:         sourceID,
+			Title:            "Report " + guid,
+			URL:              "https://blog.example.com/" + guid,
+			GUID:             guid,
+			PublishedAt:      publishedAt,
+			Content:          "Article about " + guid,
+			Summary:          "Summary of " + guid,
+			ThreatActors:     []string{"APT29"},
+			MalwareFamilies:  []string{"Cobalt Strike"},
+			Sectors:          []string{"Government"},
+			CVEs:             []string{"CVE-2024-3400"},
+			AttackTechniques: []string{"T1566.001"},
+		}
+	}
+
+	old := newReport("old", base.Add(-2*time.Hour))
+	mid := newReport("mid", base.Add(-time.Hour))
+	latest := newReport("new", base)
+	for _, r := range []*model.Report{old, mid, latest} {
+		gt.NoError(t, repo.PutReport(ctx, r))
+	}
+
+	t.Run("get report", func(t *testing.T) {
+		got, err := repo.GetReport(ctx, mid.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, got.Title, "Report mid")
+		gt.Equal(t, got.ThreatActors, []string{"APT29"})
+		gt.Equal(t, got.AttackTechniques, []string{"T1566.001"})
+		gt.True(t, got.PublishedAt.Equal(mid.PublishedAt))
+		gt.False(t, got.CreatedAt.IsZero())
+	})
+
+	t.Run("put keeps CreatedAt", func(t *testing.T) {
+		before, err := repo.GetReport(ctx, old.ID)
+		gt.NoError(t, err)
+
+		updated := newReport("old", old.PublishedAt)
+		updated.Summary = "Updated summary"
+		gt.NoError(t, repo.PutReport(ctx, updated))
+
+		after, err := repo.GetReport(ctx, old.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, after.Summary, "Updated summary")
+		gt.True(t, after.CreatedAt.Equal(before.CreatedAt))
+	})
+
+	t.Run("unknown report", func(t *testing.T) {
+		_, err := repo.GetReport(ctx, "no-such-report")
+		gt.True(t, errors.Is(err, interfaces.ErrReportNotFound))
+	})
+
+	t.Run("list newest first with pagination", func(t *testing.T) {
+		conn, err := repo.ListReports(ctx, &model.ReportListOptions{SourceID: sourceID})
+		gt.NoError(t, err)
+		gt.Equal(t, conn.Total, 3)
+		gt.A(t, conn.Items).Length(3).
+			At(0, func(t testing.TB, r *model.Report) { gt.Equal(t, r.GUID, "new") }).
+			At(2, func(t testing.TB, r *model.Report) { gt.Equal(t, r.GUID, "old") })
+
+		conn, err = repo.ListReports(ctx, &model.ReportListOptions{SourceID: sourceID, Offset: 1, Limit: 1})
+		gt.NoError(t, err)
+		gt.Equal(t, conn.Total, 3)
+		gt.A(t, conn.Items).Length(1).At(0, func(t testing.TB, r *model.Report) {
+			gt.Equal(t, r.GUID, "mid")
+		})
+	})
+
+	t.Run("list IoCs of a report", func(t *testing.T) {
+		value := time.Now().Format("c2-20060102-150405.000000.example")
+		linked := &model.IoC{
+			ID:         model.GenerateID(sourceID, model.IoCTypeDomain, value, "new"),
+			SourceID:   sourceID,
+			SourceType: "rss",
+			Type:       model.IoCTypeDomain,
+			Value:      value,
+			Status:     model.IoCStatusActive,
+			ReportID:   latest.ID,
+		}
+		unlinked := &model.IoC{
+			ID:         model.GenerateID(sourceID, model.IoCTypeDomain, value, "mid"),
+			SourceID:   sourceID,
+			SourceType: "rss",
+			Type:       model.IoCTypeDomain,
+			Value:      value,
+			Status:     model.IoCStatusActive,
+			ReportID:   mid.ID,
+		}
+		_, err := repo.BatchUpsertIoCs(ctx, []*model.IoC{linked, unlinked})
+		gt.NoError(t, err)
+
+		iocs, err := repo.ListIoCsByReport(ctx, latest.ID)
+		gt.NoError(t, err)
+		gt.A(t, iocs).Length(1).At(0, func(t testing.TB, ioc *model.IoC) {
+			gt.Equal(t, ioc.ID, linked.ID)
+			gt.Equal(t, ioc.ReportID, latest.ID)
+		})
+	})
+
+	t.Run("empty ID is rejected", func(t *testing.T) {
+		gt.Error(t, repo.PutReport(ctx, &model.Report{SourceID: sourceID}))
+	})
+}
+
+func TestReportRepository_Memory(t *testing.T) {
+	runReportRepositoryTest(t, memory.New())
+}
+
+func TestReportRepository_Firestore(t *testing.T) {
+	projectID := os.Getenv("TEST_FIRESTORE_PROJECT_ID")
+	databaseID := os.Getenv("TEST_FIRESTORE_DATABASE_ID")
+
+	if projectID == "" || databaseID == "" {
+		t.Skip("TEST_FIRESTORE_PROJECT_ID and TEST_FIRESTORE_DATABASE_ID environment variables not set")
+	}
+
+	ctx := context.Background()
+	repo, err := firestoreRepo.New(ctx, projectID, firestoreRepo.WithDatabaseID(databaseID))
+	if err != nil {
+		t.Fatalf("failed to create Firestore repository: %v", err)
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	runReportRepositoryTest(t, repo)
+}
@@ -0,0 +1,184 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+)
+
+func runSourceRepositoryTest(t *testing.T, repo interfaces.SourceRepository) {
+	ctx := context.Background()
+
+	newSource := func(id string) *model.Source {
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		ttl := 48 * time.Hour
+		reliability := 0.8
+		return &model.Source{
+			ID:          id,
+			Type:        model.SourceTypeRSS,
+			URL:         "https://blog.example/feed",
+			Description: "Vendor blog",
+			Tags:        []string{"vendor", "blog"},
+			Enabled:     true,
+			TTL:         &ttl,
+			RSSConfig:   &model.RSSConfig{MaxArticles: 10, Extraction: model.ExtractionModeRegex},
+			Reliability: &reliability,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+	}
+
+	t.Run("put, get, list and delete sources", func(t *testing.T) {
+		prefix := time.Now().Format("z-source-20060102-150405.000000")
+		s1 := newSource(prefix + "-b")
+		s2 := newSource(prefix + "-a")
+		gt.NoError(t, repo.PutSource(ctx, s1))
+		gt.NoError(t, repo.PutSource(ctx, s2))
+
+		got, err := repo.GetSource(ctx, s1.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, got.Type, model.SourceTypeRSS)
+		gt.Equal(t, got.URL, s1.URL)
+		gt.Equal(t, got.Tags, []string{"vendor", "blog"})
+		gt.True(t, got.Enabled)
+		gt.Equal(t, *got.TTL, 48*time.Hour)
+		gt.Equal(t, *got.Reliability, 0.8)
+		gt.Equal(t, got.RSSConfig.Extraction, model.ExtractionModeRegex)
+		gt.Nil(t, got.FeedConfig)
+		gt.True(t, got.CreatedAt.Equal(s1.CreatedAt))
+
+		// Modifying the returned source does not change the stored one
+		got.Tags[0] = "changed"
+		got.RSSConfig.MaxArticles = 1
+		got, err = repo.GetSource(ctx, s1.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, got.Tags[0], "vendor")
+		gt.Equal(t, got.RSSConfig.MaxArticles, 10)
+
+		got.Enabled = false
+		gt.NoError(t, repo.PutSource(ctx, got))
+		got, err = repo.GetSource(ctx, s1.ID)
+		gt.NoError(t, err)
+		gt.False(t, got.Enabled)
+
+		list, err := repo.ListSources(ctx)
+		gt.NoError(t, err)
+		var ids []string
+		for _, s := range list {
+			if s.ID == s1.ID || s.ID == s2.ID {
+				ids = append(ids, s.ID)
+			}
+		}
+		gt.Equal(t, ids, []string{s2.ID, s1.ID})
+
+		gt.NoError(t, repo.DeleteSource(ctx, s1.ID))
+		gt.NoError(t, repo.DeleteSource(ctx, s2.ID))
+		_, err = repo.GetSource(ctx, s1.ID)
+		gt.True(t, errors.Is(err, interfaces.ErrSourceNotFound))
+		gt.True(t, errors.Is(repo.DeleteSource(ctx, s1.ID), interfaces.ErrSourceNotFound))
+	})
+
+	t.Run("feed source", func(t *testing.T) {
+		s := &model.Source{
+			ID:         time.Now().Format("z-feed-20060102-150405.000000"),
+			Type:       model.SourceTypeFeed,
+			URL:        "https://feed.example/list.txt",
+			Enabled:    true,
+			FeedConfig: &model.FeedConfig{Schema: "abuse_ch_urlhaus", MaxItems: 100},
+		}
+		gt.NoError(t, repo.PutSource(ctx, s))
+		defer func() { gt.NoError(t, repo.DeleteSource(ctx, s.ID)) }()
+
+		got, err := repo.GetSource(ctx, s.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, got.FeedConfig.Schema, "abuse_ch_urlhaus")
+		gt.Equal(t, got.FeedConfig.MaxItems, 100)
+		gt.Nil(t, got.RSSConfig)
+		gt.Nil(t, got.TTL)
+		gt.Nil(t, got.Reliability)
+	})
+
+	t.Run("empty source ID", func(t *testing.T) {
+		gt.Error(t, repo.PutSource(ctx, &model.Source{Type: model.SourceTypeRSS}))
+	})
+
+	t.Run("save and list source changes", func(t *testing.T) {
+		id := time.Now().Format("z-changes-20060102-150405.000000")
+		base := time.Now().UTC().Truncate(time.Millisecond)
+		created := newSource(id)
+		updated := newSource(id)
+		updated.URL = "https://blog.example/rss"
+
+		c1 := model.NewSourceChange(model.SourceChangeCreate, "alice", nil, created, base)
+		c2 := model.NewSourceChange(model.SourceChangeUpdate, "bob", created, updated, base.Add(time.Minute))
+		c3 := model.NewSourceChange(model.SourceChangeDelete, "alice", updated, nil, base.Add(2*time.Minute))
+		other := model.NewSourceChange(model.SourceChangeCreate, "alice", nil, newSource(id+"-other"), base.Add(3*time.Minute))
+		for _, c := range []*model.SourceChange{c1, c3, c2, other} {
+			gt.NoError(t, repo.SaveSourceChange(ctx, c))
+		}
+
+		changes, err := repo.ListSourceChanges(ctx, id, 0)
+		gt.NoError(t, err)
+		gt.A(t, changes).Length(3)
+		gt.Equal(t, changes[0].ID, c3.ID)
+		gt.Equal(t, changes[1].ID, c2.ID)
+		gt.Equal(t, changes[2].ID, c1.ID)
+
+		gt.Equal(t, changes[1].Action, model.SourceChangeUpdate)
+		gt.Equal(t, changes[1].Actor, "bob")
+		gt.Equal(t, changes[1].Fields, []string{"url"})
+		gt.Equal(t, changes[1].Before.URL, "https://blog.example/feed")
+		gt.Equal(t, changes[1].After.URL, "https://blog.example/rss")
+		gt.Nil(t, changes[0].After)
+		gt.Nil(t, changes[2].Before)
+
+		changes, err = repo.ListSourceChanges(ctx, id, 1)
+		gt.NoError(t, err)
+		gt.A(t, changes).Length(1)
+		gt.Equal(t, changes[0].ID, c3.ID)
+
+		changes, err = repo.ListSourceChanges(ctx, "", 0)
+		gt.NoError(t, err)
+		found := map[string]bool{}
+		for _, c := range changes {
+			found[c.ID] = true
+		}
+		gt.True(t, found[c1.ID] && found[c2.ID] && found[c3.ID] && found[other.ID])
+
+		gt.Error(t, repo.SaveSourceChange(ctx, &model.SourceChange{SourceID: id}))
+	})
+}
+
+func TestSourceRepository_Memory(t *testing.T) {
+	runSourceRepositoryTest(t, memory.New())
+}
+
+func TestSourceRepository_Firestore(t *testing.T) {
+	projectID := os.Getenv("TEST_FIRESTORE_PROJECT_ID")
+	databaseID := os.Getenv("TEST_FIRESTORE_DATABASE_ID")
+
+	if projectID == "" || databaseID == "" {
+		t.Skip("TEST_FIRESTORE_PROJECT_ID and TEST_FIRESTORE_DATABASE_ID environment variables not set")
+	}
+
+	ctx := context.Background()
+	repo, err := firestoreRepo.New(ctx, projectID, firestoreRepo.WithDatabaseID(databaseID))
+	if err != nil {
+		t.Fatalf("failed to create Firestore repository: %v", err)
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	runSourceRepositoryTest(t, repo)
+}
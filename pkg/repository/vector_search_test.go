@@ -167,6 +167,45 @@ func TestVectorSearch(t *testing.T) {
 	})
 }
 
+func TestVectorSearch_EmbeddingDimension(t *testing.T) {
+	ctx := context.Background()
+	v := vectorizer.NewNGramVectorizer(vectorizer.WithDimension(64))
+	repo := memory.New(memory.WithEmbeddingDimension(64))
+
+	for i, value := range []string{"malware.example", "phishing.example"} {
+		ioc := &model.IoC{
+			ID:       fmt.Sprintf("ioc-%d", i),
+			SourceID: "test-source",
+			Type:     model.IoCTypeDomain,
+			Value:    value,
+			Status:   model.IoCStatusActive,
+		}
+		vec, err := v.Vectorize(value)
+		gt.NoError(t, err)
+		ioc.SetEmbedding(&model.IoCEmbedding{Vector: vec, Model: v.Model()})
+		gt.NoError(t, repo.UpsertIoC(ctx, ioc))
+	}
+
+	// An IoC with an embedding of the previous dimension is not compared
+	legacy := &model.IoC{ID: "legacy", SourceID: "test-source", Type: model.IoCTypeDomain, Value: "malware.test", Status: model.IoCStatusActive}
+	vec, err := vectorizer.NewNGramVectorizer().Vectorize(legacy.Value)
+	gt.NoError(t, err)
+	legacy.Embedding = vec
+	gt.NoError(t, repo.UpsertIoC(ctx, legacy))
+
+	query, err := v.Vectorize("malware")
+	gt.NoError(t, err)
+	results, err := repo.FindNearestIoCs(ctx, query, 5)
+	gt.NoError(t, err)
+	gt.A(t, results).Length(2).At(0, func(t testing.TB, ioc *model.IoC) {
+		gt.Equal(t, ioc.Value, "malware.example")
+		gt.Equal(t, ioc.EmbeddingModel, "ngram/3")
+	})
+
+	_, err = repo.FindNearestIoCs(ctx, make([]float32, model.EmbeddingDimension), 5)
+	gt.Error(t, err)
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -0,0 +1,183 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	firestoreRepo "github.com/secmon-lab/beehive/pkg/repository/firestore"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+)
+
+func runWatchlistRepositoryTest(t *testing.T, repo interfaces.WatchlistRepository) {
+	ctx := context.Background()
+
+	newWatchlist := func(name string) *model.Watchlist {
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		return &model.Watchlist{
+			ID:        model.NewWatchlistID(),
+			Name:      name,
+			Patterns:  []model.WatchPattern{{Kind: model.WatchPatternDomain, Value: "acme.example"}},
+			Enabled:   true,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+
+	newHit := func(watchlistID, iocID string, createdAt time.Time) *model.WatchlistHit {
+		return &model.WatchlistHit{
+			ID:            model.WatchlistHitID(watchlistID, iocID),
+			WatchlistID:   watchlistID,
+			WatchlistName: "assets",
+			IoCID:         iocID,
+			IoCType:       model.IoCTypeDomain,
+			IoCValue:      iocID + ".acme.example",
+			SourceID:      "test-source",
+			PatternKind:   model.WatchPatternDomain,
+			Pattern:       "acme.example",
+			MatchedField:  model.WatchFieldValue,
+			CreatedAt:     createdAt.UTC().Truncate(time.Millisecond),
+		}
+	}
+
+	t.Run("put, get, list and delete watchlists", func(t *testing.T) {
+		w := newWatchlist(time.Now().Format("z-watchlist-20060102-150405.000000"))
+		gt.NoError(t, repo.PutWatchlist(ctx, w))
+
+		got, err := repo.GetWatchlist(ctx, w.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, got.Name, w.Name)
+		gt.A(t, got.Patterns).Length(1).At(0, func(t testing.TB, p model.WatchPattern) {
+			gt.Equal(t, p.Kind, model.WatchPatternDomain)
+			gt.Equal(t, p.Value, "acme.example")
+		})
+		gt.True(t, got.Enabled)
+
+		w.Enabled = false
+		gt.NoError(t, repo.PutWatchlist(ctx, w))
+		got, err = repo.GetWatchlist(ctx, w.ID)
+		gt.NoError(t, err)
+		gt.False(t, got.Enabled)
+
+		all, err := repo.ListWatchlists(ctx)
+		gt.NoError(t, err)
+		found := false
+		for _, x := range all {
+			if x.ID == w.ID {
+				found = true
+			}
+		}
+		gt.True(t, found)
+
+		gt.NoError(t, repo.DeleteWatchlist(ctx, w.ID))
+		_, err = repo.GetWatchlist(ctx, w.ID)
+		gt.True(t, errors.Is(err, interfaces.ErrWatchlistNotFound))
+		gt.True(t, errors.Is(repo.DeleteWatchlist(ctx, w.ID), interfaces.ErrWatchlistNotFound))
+	})
+
+	t.Run("hits are created once and listed newest first", func(t *testing.T) {
+		w := newWatchlist("hits")
+		base := time.Now()
+
+		stored, err := repo.SaveWatchlistHits(ctx, []*model.WatchlistHit{
+			newHit(w.ID, "old", base.Add(-2*time.Hour)),
+			newHit(w.ID, "new", base),
+		})
+		gt.NoError(t, err)
+		gt.A(t, stored).Length(2)
+
+		// Saving the same hits again stores nothing
+		stored, err = repo.SaveWatchlistHits(ctx, []*model.WatchlistHit{newHit(w.ID, "new", base.Add(time.Hour))})
+		gt.NoError(t, err)
+		gt.A(t, stored).Length(0)
+
+		conn, err := repo.ListWatchlistHits(ctx, &model.WatchlistHitListOptions{WatchlistID: w.ID})
+		gt.NoError(t, err)
+		gt.Equal(t, conn.Total, 2)
+		gt.A(t, conn.Items).Length(2).
+			At(0, func(t testing.TB, h *model.WatchlistHit) { gt.Equal(t, h.IoCID, "new") }).
+			At(1, func(t testing.TB, h *model.WatchlistHit) { gt.Equal(t, h.IoCID, "old") })
+
+		page, err := repo.ListWatchlistHits(ctx, &model.WatchlistHitListOptions{WatchlistID: w.ID, Offset: 1, Limit: 1})
+		gt.NoError(t, err)
+		gt.Equal(t, page.Total, 2)
+		gt.A(t, page.Items).Length(1).At(0, func(t testing.TB, h *model.WatchlistHit) {
+			gt.Equal(t, h.IoCID, "old")
+		})
+	})
+
+	t.Run("acknowledge hits", func(t *testing.T) {
+		w := newWatchlist("ack")
+		a := newHit(w.ID, "a", time.Now())
+		b := newHit(w.ID, "b", time.Now().Add(-time.Minute))
+		_, err := repo.SaveWatchlistHits(ctx, []*model.WatchlistHit{a, b})
+		gt.NoError(t, err)
+
+		// Unknown IDs fail the whole request
+		_, err = repo.AcknowledgeWatchlistHits(ctx, []string{a.ID, "missing"}, "analyst", time.Now())
+		gt.True(t, errors.Is(err, interfaces.ErrWatchlistHitNotFound))
+		got, err := repo.GetWatchlistHit(ctx, a.ID)
+		gt.NoError(t, err)
+		gt.False(t, got.Acknowledged)
+
+		at := time.Now().UTC().Truncate(time.Millisecond)
+		acked, err := repo.AcknowledgeWatchlistHits(ctx, []string{a.ID}, "analyst", at)
+		gt.NoError(t, err)
+		gt.A(t, acked).Length(1).At(0, func(t testing.TB, h *model.WatchlistHit) {
+			gt.True(t, h.Acknowledged)
+			gt.Equal(t, h.AcknowledgedBy, "analyst")
+			gt.True(t, h.AcknowledgedAt.Equal(at))
+		})
+
+		// Re-saving an acknowledged hit keeps the acknowledgement
+		_, err = repo.SaveWatchlistHits(ctx, []*model.WatchlistHit{newHit(w.ID, "a", time.Now())})
+		gt.NoError(t, err)
+		got, err = repo.GetWatchlistHit(ctx, a.ID)
+		gt.NoError(t, err)
+		gt.True(t, got.Acknowledged)
+
+		unacked := false
+		conn, err := repo.ListWatchlistHits(ctx, &model.WatchlistHitListOptions{WatchlistID: w.ID, Acknowledged: &unacked})
+		gt.NoError(t, err)
+		gt.Equal(t, conn.Total, 1)
+		gt.A(t, conn.Items).Length(1).At(0, func(t testing.TB, h *model.WatchlistHit) {
+			gt.Equal(t, h.ID, b.ID)
+		})
+	})
+
+	t.Run("missing hit", func(t *testing.T) {
+		_, err := repo.GetWatchlistHit(ctx, "missing")
+		gt.True(t, errors.Is(err, interfaces.ErrWatchlistHitNotFound))
+	})
+}
+
+func TestWatchlistRepository_Memory(t *testing.T) {
+	runWatchlistRepositoryTest(t, memory.New())
+}
+
+func TestWatchlistRepository_Firestore(t *testing.T) {
+	projectID := os.Getenv("TEST_FIRESTORE_PROJECT_ID")
+	databaseID := os.Getenv("TEST_FIRESTORE_DATABASE_ID")
+
+	if projectID == "" || databaseID == "" {
+		t.Skip("TEST_FIRESTORE_PROJECT_ID and TEST_FIRESTORE_DATABASE_ID environment variables not set")
+	}
+
+	ctx := context.Background()
+	repo, err := firestoreRepo.New(ctx, projectID, firestoreRepo.WithDatabaseID(databaseID))
+	if err != nil {
+		t.Fatalf("failed to create Firestore repository: %v", err)
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("failed to close repository: %v", err)
+		}
+	}()
+
+	runWatchlistRepositoryTest(t, repo)
+}
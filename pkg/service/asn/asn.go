@@ -0,0 +1,212 @@
+// Package asn maps IP addresses to autonomous systems with an offline table in
+// the IP-to-ASN TSV format of iptoasn.com (ip2asn-v4.tsv, ip2asn-combined.tsv):
+//
+//	range_start	range_end	AS_number	country_code	AS_description
+//
+// Domain names are optionally resolved with DNS first.
+package asn
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/utils/safe"
+)
+
+// DefaultResolveTimeout bounds the DNS resolution of a domain name
+const DefaultResolveTimeout = 3 * time.Second
+
+// Table maps address ranges to the ASN announcing them
+type Table struct {
+	ranges []asnRange // Sorted by start, not overlapping
+}
+
+type asnRange struct {
+	start, end netip.Addr
+	asn        uint32
+}
+
+// LoadTable loads a table file. Files ending with .gz are decompressed.
+func LoadTable(path string) (*Table, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to open ASN table", goerr.V("path", path))
+	}
+	defer safe.Close(context.Background(), f)
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to decompress ASN table", goerr.V("path", path))
+		}
+		defer safe.Close(context.Background(), gz)
+		r = gz
+	}
+
+	table, err := ParseTable(r)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to parse ASN table", goerr.V("path", path))
+	}
+	return table, nil
+}
+
+// ParseTable parses a table. Ranges of AS 0 (not routed) are skipped.
+func ParseTable(r io.Reader) (*Table, error) {
+	t := &Table{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) < 3 {
+			return nil, goerr.New("invalid ASN table line", goerr.V("line", line))
+		}
+		start, err := netip.ParseAddr(fields[0])
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid range start", goerr.V("line", line))
+		}
+		end, err := netip.ParseAddr(fields[1])
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid range end", goerr.V("line", line))
+		}
+		if start.Is4() != end.Is4() || end.Less(start) {
+			return nil, goerr.New("invalid address range", goerr.V("line", line))
+		}
+		asn, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid AS number", goerr.V("line", line))
+		}
+		if asn == 0 {
+			continue
+		}
+		t.ranges = append(t.ranges, asnRange{start: start, end: end, asn: uint32(asn)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, goerr.Wrap(err, "failed to read ASN table")
+	}
+
+	sort.Slice(t.ranges, func(i, j int) bool {
+		return t.ranges[i].start.Less(t.ranges[j].start)
+	})
+	return t, nil
+}
+
+// Lookup returns the ASN announcing addr
+func (t *Table) Lookup(addr netip.Addr) (uint32, bool) {
+	addr = addr.Unmap()
+	// The last range starting at or before addr is the only candidate
+	i := sort.Search(len(t.ranges), func(i int) bool {
+		return addr.Less(t.ranges[i].start)
+	}) - 1
+	if i < 0 {
+		return 0, false
+	}
+	r := t.ranges[i]
+	if r.start.Is4() != addr.Is4() || r.end.Less(addr) {
+		return 0, false
+	}
+	return r.asn, true
+}
+
+// Len returns the number of routed ranges of the table
+func (t *Table) Len() int {
+	return len(t.ranges)
+}
+
+// HostResolver resolves domain names, satisfied by *net.Resolver
+type HostResolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// Resolver looks up the ASNs of IoC hosts in a table
+type Resolver struct {
+	table   *Table
+	hosts   HostResolver
+	timeout time.Duration
+}
+
+var _ interfaces.ASNResolver = &Resolver{}
+
+// Option configures Resolver
+type Option func(*Resolver)
+
+// WithHostResolution resolves domain names with hosts before looking up their
+// addresses. Resolving queries the DNS for the names of reported IoCs, so it
+// is disabled by default.
+func WithHostResolution(hosts HostResolver) Option {
+	return func(r *Resolver) {
+		r.hosts = hosts
+	}
+}
+
+// WithResolveTimeout sets the timeout of resolving a domain name
+func WithResolveTimeout(d time.Duration) Option {
+	return func(r *Resolver) {
+		if d > 0 {
+			r.timeout = d
+		}
+	}
+}
+
+// New creates a resolver of table
+func New(table *Table, opts ...Option) *Resolver {
+	r := &Resolver{
+		table:   table,
+		timeout: DefaultResolveTimeout,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// LookupASNs returns the distinct ASNs announcing host, sorted
+func (r *Resolver) LookupASNs(ctx context.Context, host string) ([]uint32, error) {
+	if host == "" {
+		return nil, nil
+	}
+
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else if r.hosts != nil {
+		ctx, cancel := context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+		resolved, err := r.hosts.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			var dnsErr *net.DNSError
+			if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+				return nil, nil
+			}
+			return nil, goerr.Wrap(err, "failed to resolve host", goerr.V("host", host))
+		}
+		addrs = resolved
+	}
+
+	var asns []uint32
+	for _, addr := range addrs {
+		if asn, ok := r.table.Lookup(addr); ok && !slices.Contains(asns, asn) {
+			asns = append(asns, asn)
+		}
+	}
+	slices.Sort(asns)
+	return asns, nil
+}
@@ -0,0 +1,148 @@
+package asn_test
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/service/asn"
+)
+
+const table = `# range_start	range_end	AS_number	country_code	AS_description
+198.51.100.0	198.51.100.255	64500	US	EXAMPLE-HOSTING
+192.0.2.0	192.0.2.255	0	None	Not routed
+203.0.113.0	203.0.113.127	64501	JP	EXAMPLE-CLOUD
+2001:db8::	2001:db8::ffff	64502	DE	EXAMPLE-V6
+`
+
+type fakeHosts map[string][]netip.Addr
+
+func (f fakeHosts) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	addrs, ok := f[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	if addrs == nil {
+		return nil, errors.New("server misbehaving")
+	}
+	return addrs, nil
+}
+
+func TestTable_Lookup(t *testing.T) {
+	tbl, err := asn.ParseTable(strings.NewReader(table))
+	gt.NoError(t, err)
+	gt.Equal(t, tbl.Len(), 3)
+
+	testCases := []struct {
+		addr string
+		want uint32
+		ok   bool
+	}{
+		{addr: "198.51.100.0", want: 64500, ok: true},
+		{addr: "198.51.100.255", want: 64500, ok: true},
+		{addr: "203.0.113.64", want: 64501, ok: true},
+		{addr: "::ffff:203.0.113.64", want: 64501, ok: true},
+		{addr: "203.0.113.128"},
+		{addr: "192.0.2.1"},
+		{addr: "10.0.0.1"},
+		{addr: "2001:db8::10", want: 64502, ok: true},
+		{addr: "2001:db8::1:0"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.addr, func(t *testing.T) {
+			got, ok := tbl.Lookup(netip.MustParseAddr(tc.addr))
+			gt.Equal(t, ok, tc.ok)
+			gt.Equal(t, got, tc.want)
+		})
+	}
+}
+
+func TestParseTable_Invalid(t *testing.T) {
+	for _, line := range []string{
+		"198.51.100.0\t198.51.100.255",
+		"198.51.100.x\t198.51.100.255\t64500",
+		"198.51.100.255\t198.51.100.0\t64500",
+		"198.51.100.0\t2001:db8::\t64500",
+		"198.51.100.0\t198.51.100.255\tAS64500",
+	} {
+		_, err := asn.ParseTable(strings.NewReader(line + "\n"))
+		gt.Error(t, err)
+	}
+}
+
+func TestLoadTable(t *testing.T) {
+	dir := t.TempDir()
+
+	plain := filepath.Join(dir, "ip2asn.tsv")
+	gt.NoError(t, os.WriteFile(plain, []byte(table), 0o600))
+	tbl, err := asn.LoadTable(plain)
+	gt.NoError(t, err)
+	gt.Equal(t, tbl.Len(), 3)
+
+	compressed := filepath.Join(dir, "ip2asn.tsv.gz")
+	f, err := os.Create(compressed)
+	gt.NoError(t, err)
+	gz := gzip.NewWriter(f)
+	_, err = gz.Write([]byte(table))
+	gt.NoError(t, err)
+	gt.NoError(t, gz.Close())
+	gt.NoError(t, f.Close())
+	tbl, err = asn.LoadTable(compressed)
+	gt.NoError(t, err)
+	gt.Equal(t, tbl.Len(), 3)
+
+	_, err = asn.LoadTable(filepath.Join(dir, "missing.tsv"))
+	gt.Error(t, err)
+}
+
+func TestResolver_LookupASNs(t *testing.T) {
+	ctx := context.Background()
+	tbl, err := asn.ParseTable(strings.NewReader(table))
+	gt.NoError(t, err)
+
+	hosts := fakeHosts{
+		"cdn.example": {
+			netip.MustParseAddr("203.0.113.5"),
+			netip.MustParseAddr("198.51.100.7"),
+			netip.MustParseAddr("198.51.100.8"),
+			netip.MustParseAddr("10.0.0.1"),
+		},
+		"broken.example": nil,
+	}
+
+	t.Run("ip address", func(t *testing.T) {
+		asns, err := asn.New(tbl).LookupASNs(ctx, "198.51.100.20")
+		gt.NoError(t, err)
+		gt.A(t, asns).Equal([]uint32{64500})
+	})
+
+	t.Run("domain names are not resolved by default", func(t *testing.T) {
+		asns, err := asn.New(tbl).LookupASNs(ctx, "cdn.example")
+		gt.NoError(t, err)
+		gt.A(t, asns).Length(0)
+	})
+
+	t.Run("resolved domain name", func(t *testing.T) {
+		asns, err := asn.New(tbl, asn.WithHostResolution(hosts)).LookupASNs(ctx, "cdn.example")
+		gt.NoError(t, err)
+		gt.A(t, asns).Equal([]uint32{64500, 64501})
+	})
+
+	t.Run("unknown domain name", func(t *testing.T) {
+		asns, err := asn.New(tbl, asn.WithHostResolution(hosts)).LookupASNs(ctx, "nx.example")
+		gt.NoError(t, err)
+		gt.A(t, asns).Length(0)
+	})
+
+	t.Run("resolution failure", func(t *testing.T) {
+		_, err := asn.New(tbl, asn.WithHostResolution(hosts)).LookupASNs(ctx, "broken.example")
+		gt.Error(t, err)
+	})
+}
@@ -0,0 +1,96 @@
+// Package audit provides mirrors of the audit log outside the repository
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// JSONL appends audit records to a file, one JSON object per line
+type JSONL struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+var _ interfaces.AuditMirror = &JSONL{}
+
+// jsonlRecord is the line format of an audit record
+type jsonlRecord struct {
+	ID         string        `json:"id"`
+	Actor      string        `json:"actor"`
+	Action     string        `json:"action"`
+	TargetType string        `json:"target_type"`
+	TargetID   string        `json:"target_id"`
+	Changes    []jsonlChange `json:"changes,omitempty"`
+	RequestID  string        `json:"request_id,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type jsonlChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// NewJSONL opens path for appending, creating it if needed
+func NewJSONL(path string) (*JSONL, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to open audit log file", goerr.V("path", path))
+	}
+	return &JSONL{file: file}, nil
+}
+
+// WriteAuditRecord appends a record as a line
+func (j *JSONL) WriteAuditRecord(ctx context.Context, record *model.AuditRecord) error {
+	line := jsonlRecord{
+		ID:         record.ID,
+		Actor:      record.Actor,
+		Action:     string(record.Action),
+		TargetType: record.TargetType,
+		TargetID:   record.TargetID,
+		RequestID:  record.RequestID,
+		CreatedAt:  record.CreatedAt,
+	}
+	for _, c := range record.Changes {
+		change := jsonlChange{Field: c.Field}
+		// Values are JSON encoded already; embed them as they are
+		if c.Before != "" {
+			change.Before = json.RawMessage(c.Before)
+		}
+		if c.After != "" {
+			change.After = json.RawMessage(c.After)
+		}
+		line.Changes = append(line.Changes, change)
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return goerr.Wrap(err, "failed to encode audit record", goerr.V("id", record.ID))
+	}
+	data = append(data, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := j.file.Write(data); err != nil {
+		return goerr.Wrap(err, "failed to write audit record", goerr.V("id", record.ID))
+	}
+	return nil
+}
+
+// Close closes the file
+func (j *JSONL) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.file.Close(); err != nil {
+		return goerr.Wrap(err, "failed to close audit log file")
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package audit_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/service/audit"
+)
+
+func TestJSONL(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	write := func(record *model.AuditRecord) {
+		mirror, err := audit.NewJSONL(path)
+		gt.NoError(t, err)
+		gt.NoError(t, mirror.WriteAuditRecord(ctx, record))
+		gt.NoError(t, mirror.Close())
+	}
+	// Reopening the file appends to it
+	write(&model.AuditRecord{
+		ID:         "rec-1",
+		Actor:      "user:alice",
+		Action:     model.AuditIoCUpdate,
+		TargetType: model.AuditTargetIoC,
+		TargetID:   "ioc-1",
+		Changes:    []model.AuditChange{{Field: "Status", Before: `"active"`, After: `"false_positive"`}},
+		RequestID:  "req-1",
+		CreatedAt:  at,
+	})
+	write(&model.AuditRecord{
+		ID:         "rec-2",
+		Actor:      model.ActorFetcher,
+		Action:     model.AuditFetchRun,
+		TargetType: model.AuditTargetSource,
+		TargetID:   "feed",
+		Changes:    []model.AuditChange{{Field: "Status", After: `"success"`}},
+		CreatedAt:  at,
+	})
+
+	f, err := os.Open(path)
+	gt.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line map[string]any
+		gt.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	gt.NoError(t, scanner.Err())
+	gt.A(t, lines).Length(2)
+
+	gt.Equal(t, lines[0]["id"], any("rec-1"))
+	gt.Equal(t, lines[0]["action"], any("ioc.update"))
+	gt.Equal(t, lines[0]["target_id"], any("ioc-1"))
+	gt.Equal(t, lines[0]["request_id"], any("req-1"))
+	gt.Equal(t, lines[0]["created_at"], any("2026-01-02T03:04:05Z"))
+	gt.Equal(t, lines[0]["changes"], any([]any{
+		map[string]any{"field": "Status", "before": "active", "after": "false_positive"},
+	}))
+
+	_, ok := lines[1]["request_id"]
+	gt.False(t, ok)
+	gt.Equal(t, lines[1]["changes"], any([]any{
+		map[string]any{"field": "Status", "after": "success"},
+	}))
+}
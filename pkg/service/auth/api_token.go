@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/errutil"
+)
+
+// apiTokenTouchInterval limits writes of the last-used time of API tokens
+// used for many requests
+const apiTokenTouchInterval = time.Minute
+
+// APITokens authenticates API tokens minted by admins and stored as hashes in
+// the repository
+type APITokens struct {
+	repo interfaces.APITokenRepository
+}
+
+var _ interfaces.Authenticator = &APITokens{}
+
+// NewAPITokens creates an authenticator of the API tokens of repo
+func NewAPITokens(repo interfaces.APITokenRepository) *APITokens {
+	return &APITokens{
+		repo: repo,
+	}
+}
+
+// Authenticate returns the principal of a bearer API token and records its
+// use. Bearer tokens without the API token prefix are left to the next
+// authenticator.
+func (a *APITokens) Authenticate(r *http.Request) (*model.Principal, error) {
+	raw, ok := bearerToken(r)
+	if !ok || !strings.HasPrefix(raw, model.APITokenPrefix) {
+		return nil, interfaces.ErrNoCredentials
+	}
+
+	ctx := r.Context()
+	token, err := a.repo.GetAPITokenByHash(ctx, model.HashAPIToken(raw))
+	if errors.Is(err, interfaces.ErrAPITokenNotFound) {
+		return nil, goerr.Wrap(interfaces.ErrInvalidCredentials, "unknown API token")
+	}
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get API token")
+	}
+
+	now := time.Now()
+	if token.Revoked() {
+		return nil, goerr.Wrap(interfaces.ErrInvalidCredentials, "API token is revoked", goerr.V("name", token.Name))
+	}
+	if token.Expired(now) {
+		return nil, goerr.Wrap(interfaces.ErrInvalidCredentials, "API token is expired", goerr.V("name", token.Name))
+	}
+
+	if now.Sub(token.LastUsedAt) >= apiTokenTouchInterval {
+		// A failure to record the use must not fail the request
+		if err := a.repo.TouchAPIToken(ctx, token.ID, now); err != nil {
+			errutil.Handle(ctx, err, "failed to record API token use")
+		}
+	}
+
+	return token.Principal(), nil
+}
@@ -0,0 +1,77 @@
+// Package auth provides authenticators of HTTP requests: OIDC/JWT bearer
+// tokens validated against a JWKS, static API tokens stored as hashes and a
+// header set by a trusted authenticating proxy such as IAP.
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// Chain tries authenticators in order; the first one finding credentials
+// decides. Requests without credentials get the anonymous role if one is set.
+type Chain struct {
+	authenticators []interfaces.Authenticator
+	anonymous      model.Role
+}
+
+var _ interfaces.Authenticator = &Chain{}
+
+// NewChain creates a chain of authenticators. anonymous is the role of
+// requests without credentials; empty rejects them.
+func NewChain(anonymous model.Role, authenticators ...interfaces.Authenticator) *Chain {
+	return &Chain{
+		authenticators: authenticators,
+		anonymous:      anonymous,
+	}
+}
+
+// Authenticate returns the principal of the request
+func (c *Chain) Authenticate(r *http.Request) (*model.Principal, error) {
+	for _, a := range c.authenticators {
+		p, err := a.Authenticate(r)
+		if errors.Is(err, interfaces.ErrNoCredentials) {
+			continue
+		}
+		return p, err
+	}
+
+	// A token no authenticator accepts must not fall back to anonymous
+	if r.Header.Get("Authorization") != "" {
+		return nil, goerr.Wrap(interfaces.ErrInvalidCredentials, "unrecognized authorization")
+	}
+	if c.anonymous != "" {
+		return &model.Principal{
+			Subject: "anonymous",
+			Role:    c.anonymous,
+			Method:  model.AuthMethodAnonymous,
+		}, nil
+	}
+	return nil, goerr.Wrap(interfaces.ErrNoCredentials, "authentication required")
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// highestRole returns the highest of roles, empty if there is none
+func highestRole(roles []model.Role) model.Role {
+	var highest model.Role
+	for _, role := range roles {
+		if role.IsValid() && (highest == "" || role.Includes(highest)) {
+			highest = role
+		}
+	}
+	return highest
+}
@@ -0,0 +1,313 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/service/auth"
+)
+
+// issuer signs tokens with a local key published by a JWKS server
+type issuer struct {
+	t        *testing.T
+	key      jose.JSONWebKey
+	jwks     *httptest.Server
+	requests atomic.Int32
+}
+
+func newIssuer(t *testing.T, kid string) *issuer {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	gt.NoError(t, err)
+
+	iss := &issuer{
+		t:   t,
+		key: jose.JSONWebKey{Key: priv, KeyID: kid, Algorithm: string(jose.RS256), Use: "sig"},
+	}
+	iss.jwks = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		iss.requests.Add(1)
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{iss.key.Public()}})
+	}))
+	t.Cleanup(iss.jwks.Close)
+	return iss
+}
+
+func (iss *issuer) sign(claims map[string]any) string {
+	iss.t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: iss.key},
+		(&jose.SignerOptions{}).WithType("JWT"))
+	gt.NoError(iss.t, err)
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	gt.NoError(iss.t, err)
+	return token
+}
+
+func validClaims(extra map[string]any) map[string]any {
+	claims := map[string]any{
+		"iss": "https://idp.example",
+		"aud": "beehive",
+		"sub": "alice",
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"iat": time.Now().Unix(),
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	return claims
+}
+
+func bearerRequest(token string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r
+}
+
+func TestJWT(t *testing.T) {
+	iss := newIssuer(t, "key-1")
+	authn := auth.NewJWT(iss.jwks.URL,
+		auth.WithIssuer("https://idp.example"),
+		auth.WithAudience("beehive"),
+	)
+
+	t.Run("valid token", func(t *testing.T) {
+		p, err := authn.Authenticate(bearerRequest(iss.sign(validClaims(map[string]any{"role": "analyst"}))))
+		gt.NoError(t, err)
+		gt.Equal(t, p.Subject, "alice")
+		gt.Equal(t, p.Role, model.RoleAnalyst)
+		gt.Equal(t, p.Method, model.AuthMethodJWT)
+		gt.Equal(t, p.Actor(), "user:alice")
+	})
+
+	t.Run("highest role of a list", func(t *testing.T) {
+		p, err := authn.Authenticate(bearerRequest(iss.sign(validClaims(map[string]any{
+			"role": []string{"viewer", "admin", "unknown"},
+		}))))
+		gt.NoError(t, err)
+		gt.Equal(t, p.Role, model.RoleAdmin)
+	})
+
+	t.Run("keys are cached", func(t *testing.T) {
+		before := iss.requests.Load()
+		_, err := authn.Authenticate(bearerRequest(iss.sign(validClaims(map[string]any{"role": "viewer"}))))
+		gt.NoError(t, err)
+		gt.Equal(t, iss.requests.Load(), before)
+	})
+
+	invalid := map[string]string{
+		"expired":        iss.sign(validClaims(map[string]any{"role": "viewer", "exp": time.Now().Add(-time.Hour).Unix()})),
+		"no expiry":      iss.sign(map[string]any{"iss": "https://idp.example", "aud": "beehive", "sub": "alice", "role": "viewer"}),
+		"wrong issuer":   iss.sign(validClaims(map[string]any{"role": "viewer", "iss": "https://evil.example"})),
+		"wrong audience": iss.sign(validClaims(map[string]any{"role": "viewer", "aud": "other"})),
+		"no role":        iss.sign(validClaims(nil)),
+		"unknown key":    newIssuer(t, "key-2").sign(validClaims(map[string]any{"role": "viewer"})),
+		"malformed":      "a.b.c",
+	}
+	for name, token := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := authn.Authenticate(bearerRequest(token))
+			gt.True(t, errors.Is(err, interfaces.ErrInvalidCredentials))
+		})
+	}
+
+	t.Run("forged signature", func(t *testing.T) {
+		// Same key ID, different key
+		forger := newIssuer(t, "key-1")
+		_, err := authn.Authenticate(bearerRequest(forger.sign(validClaims(map[string]any{"role": "admin"}))))
+		gt.True(t, errors.Is(err, interfaces.ErrInvalidCredentials))
+	})
+
+	t.Run("not a JWT", func(t *testing.T) {
+		_, err := authn.Authenticate(bearerRequest("opaque-token"))
+		gt.True(t, errors.Is(err, interfaces.ErrNoCredentials))
+		_, err = authn.Authenticate(bearerRequest(""))
+		gt.True(t, errors.Is(err, interfaces.ErrNoCredentials))
+	})
+}
+
+func TestJWT_RoleMapping(t *testing.T) {
+	iss := newIssuer(t, "key-1")
+	authn := auth.NewJWT(iss.jwks.URL,
+		auth.WithSubjectClaim("email"),
+		auth.WithRoleClaim("groups"),
+		auth.WithRoleMapping(map[string]model.Role{"soc": model.RoleAnalyst}),
+		auth.WithJWTDefaultRole(model.RoleViewer),
+	)
+
+	p, err := authn.Authenticate(bearerRequest(iss.sign(validClaims(map[string]any{
+		"email":  "alice@example.com",
+		"groups": []string{"engineering", "soc"},
+	}))))
+	gt.NoError(t, err)
+	gt.Equal(t, p.Subject, "alice@example.com")
+	gt.Equal(t, p.Role, model.RoleAnalyst)
+
+	p, err = authn.Authenticate(bearerRequest(iss.sign(validClaims(map[string]any{
+		"email":  "bob@example.com",
+		"groups": []string{"engineering"},
+	}))))
+	gt.NoError(t, err)
+	gt.Equal(t, p.Role, model.RoleViewer)
+}
+
+func TestJWT_ECDSA(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	gt.NoError(t, err)
+	key := jose.JSONWebKey{Key: priv, KeyID: "ec", Algorithm: string(jose.ES256)}
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{key.Public()}})
+	}))
+	defer jwks.Close()
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, nil)
+	gt.NoError(t, err)
+	token, err := jwt.Signed(signer).Claims(validClaims(map[string]any{"role": "viewer"})).Serialize()
+	gt.NoError(t, err)
+
+	p, err := auth.NewJWT(jwks.URL).Authenticate(bearerRequest(token))
+	gt.NoError(t, err)
+	gt.Equal(t, p.Role, model.RoleViewer)
+}
+
+func TestStaticTokens(t *testing.T) {
+	authn, err := auth.NewStaticTokens([]auth.StaticToken{
+		{Name: "ci", Hash: auth.HashToken("s3cret"), Role: model.RoleAnalyst},
+	})
+	gt.NoError(t, err)
+
+	p, err := authn.Authenticate(bearerRequest("s3cret"))
+	gt.NoError(t, err)
+	gt.Equal(t, p.Subject, "ci")
+	gt.Equal(t, p.Role, model.RoleAnalyst)
+	gt.Equal(t, p.Actor(), "token:ci")
+
+	_, err = authn.Authenticate(bearerRequest("wrong"))
+	gt.True(t, errors.Is(err, interfaces.ErrNoCredentials))
+
+	_, err = auth.NewStaticTokens([]auth.StaticToken{{Name: "ci", Hash: "s3cret", Role: model.RoleAnalyst}})
+	gt.Error(t, err)
+	_, err = auth.NewStaticTokens([]auth.StaticToken{{Name: "ci", Hash: auth.HashToken("x"), Role: "root"}})
+	gt.Error(t, err)
+}
+
+func TestTrustedHeader(t *testing.T) {
+	authn := auth.NewTrustedHeader("X-Goog-Authenticated-User-Email",
+		auth.WithHeaderRoles(map[string]model.Role{"alice@example.com": model.RoleAdmin}))
+
+	r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	r.Header.Set("X-Goog-Authenticated-User-Email", "accounts.google.com:alice@example.com")
+	p, err := authn.Authenticate(r)
+	gt.NoError(t, err)
+	gt.Equal(t, p.Subject, "alice@example.com")
+	gt.Equal(t, p.Role, model.RoleAdmin)
+
+	r.Header.Set("X-Goog-Authenticated-User-Email", "accounts.google.com:bob@example.com")
+	_, err = authn.Authenticate(r)
+	gt.True(t, errors.Is(err, interfaces.ErrInvalidCredentials))
+
+	r.Header.Del("X-Goog-Authenticated-User-Email")
+	_, err = authn.Authenticate(r)
+	gt.True(t, errors.Is(err, interfaces.ErrNoCredentials))
+}
+
+func TestChain(t *testing.T) {
+	tokens, err := auth.NewStaticTokens([]auth.StaticToken{
+		{Name: "ci", Hash: auth.HashToken("s3cret"), Role: model.RoleAnalyst},
+	})
+	gt.NoError(t, err)
+	iss := newIssuer(t, "key-1")
+
+	t.Run("first authenticator with credentials decides", func(t *testing.T) {
+		chain := auth.NewChain("", tokens, auth.NewJWT(iss.jwks.URL))
+		p, err := chain.Authenticate(bearerRequest("s3cret"))
+		gt.NoError(t, err)
+		gt.Equal(t, p.Method, model.AuthMethodToken)
+
+		p, err = chain.Authenticate(bearerRequest(iss.sign(validClaims(map[string]any{"role": "viewer"}))))
+		gt.NoError(t, err)
+		gt.Equal(t, p.Method, model.AuthMethodJWT)
+
+		_, err = chain.Authenticate(bearerRequest(""))
+		gt.True(t, errors.Is(err, interfaces.ErrNoCredentials))
+	})
+
+	t.Run("anonymous role", func(t *testing.T) {
+		chain := auth.NewChain(model.RoleViewer, tokens)
+		p, err := chain.Authenticate(bearerRequest(""))
+		gt.NoError(t, err)
+		gt.Equal(t, p.Role, model.RoleViewer)
+		gt.Equal(t, p.Method, model.AuthMethodAnonymous)
+
+		// An unknown token is rejected rather than served anonymously
+		_, err = chain.Authenticate(bearerRequest("wrong"))
+		gt.True(t, errors.Is(err, interfaces.ErrInvalidCredentials))
+	})
+}
+
+func TestAPITokens(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	authn := auth.NewAPITokens(repo)
+
+	newToken := func(name string, expiresAt time.Time) (*model.APIToken, string) {
+		token, secret, err := model.NewAPIToken(name, []model.TokenScope{model.ScopeReadIoCs}, "user:alice", expiresAt, time.Now())
+		gt.NoError(t, err)
+		gt.NoError(t, repo.PutAPIToken(ctx, token))
+		return token, secret
+	}
+
+	t.Run("valid token records its use", func(t *testing.T) {
+		token, secret := newToken("soar", time.Now().Add(time.Hour))
+		p, err := authn.Authenticate(bearerRequest(secret))
+		gt.NoError(t, err)
+		gt.Equal(t, p.Subject, "soar")
+		gt.Equal(t, p.Method, model.AuthMethodAPIToken)
+		gt.Equal(t, p.Role, model.Role(""))
+		gt.Equal(t, p.Scopes, []model.TokenScope{model.ScopeReadIoCs})
+
+		got, err := repo.GetAPIToken(ctx, token.ID)
+		gt.NoError(t, err)
+		gt.False(t, got.LastUsedAt.IsZero())
+	})
+
+	t.Run("revoked and expired tokens are rejected", func(t *testing.T) {
+		revoked, secret := newToken("revoked", time.Time{})
+		revoked.RevokedAt = time.Now()
+		gt.NoError(t, repo.PutAPIToken(ctx, revoked))
+		_, err := authn.Authenticate(bearerRequest(secret))
+		gt.True(t, errors.Is(err, interfaces.ErrInvalidCredentials))
+
+		_, secret = newToken("expired", time.Now().Add(-time.Second))
+		_, err = authn.Authenticate(bearerRequest(secret))
+		gt.True(t, errors.Is(err, interfaces.ErrInvalidCredentials))
+	})
+
+	t.Run("unknown API token is rejected", func(t *testing.T) {
+		_, err := authn.Authenticate(bearerRequest(model.APITokenPrefix + "unknown"))
+		gt.True(t, errors.Is(err, interfaces.ErrInvalidCredentials))
+	})
+
+	t.Run("other tokens are left to the next authenticator", func(t *testing.T) {
+		_, err := authn.Authenticate(bearerRequest("s3cret"))
+		gt.True(t, errors.Is(err, interfaces.ErrNoCredentials))
+		_, err = authn.Authenticate(bearerRequest(""))
+		gt.True(t, errors.Is(err, interfaces.ErrNoCredentials))
+	})
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// iapPrefix is the prefix of the user headers set by Google Cloud IAP
+const iapPrefix = "accounts.google.com:"
+
+// TrustedHeader authenticates the user named in a header set by an
+// authenticating proxy, e.g. X-Goog-Authenticated-User-Email of IAP. It must
+// only be used when all requests pass the proxy, which must drop the header
+// from client requests.
+type TrustedHeader struct {
+	header      string
+	defaultRole model.Role
+	roles       map[string]model.Role
+}
+
+var _ interfaces.Authenticator = &TrustedHeader{}
+
+// TrustedHeaderOption configures TrustedHeader
+type TrustedHeaderOption func(*TrustedHeader)
+
+// WithHeaderDefaultRole sets the role of users without an explicit role.
+// Without it, such users are rejected.
+func WithHeaderDefaultRole(role model.Role) TrustedHeaderOption {
+	return func(a *TrustedHeader) {
+		a.defaultRole = role
+	}
+}
+
+// WithHeaderRoles sets the roles of users
+func WithHeaderRoles(roles map[string]model.Role) TrustedHeaderOption {
+	return func(a *TrustedHeader) {
+		a.roles = roles
+	}
+}
+
+// NewTrustedHeader creates an authenticator of the user in header
+func NewTrustedHeader(header string, opts ...TrustedHeaderOption) *TrustedHeader {
+	a := &TrustedHeader{header: header}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Authenticate returns the principal of the user named in the header
+func (a *TrustedHeader) Authenticate(r *http.Request) (*model.Principal, error) {
+	user := strings.TrimSpace(strings.TrimPrefix(r.Header.Get(a.header), iapPrefix))
+	if user == "" {
+		return nil, interfaces.ErrNoCredentials
+	}
+
+	role, ok := a.roles[user]
+	if !ok {
+		role = a.defaultRole
+	}
+	if role == "" {
+		return nil, goerr.Wrap(interfaces.ErrInvalidCredentials, "user has no role", goerr.V("user", user))
+	}
+
+	return &model.Principal{
+		Subject: user,
+		Role:    role,
+		Method:  model.AuthMethodHeader,
+	}, nil
+}
@@ -0,0 +1,250 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/httpclient"
+)
+
+const (
+	// jwksMaxAge is how long fetched keys are used before they are fetched again
+	jwksMaxAge = time.Hour
+	// jwksMinRefresh limits fetches of the JWKS for tokens with an unknown key
+	jwksMinRefresh = time.Minute
+)
+
+var jwtAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// JWT authenticates OIDC/JWT bearer tokens signed by a key of a JWKS
+type JWT struct {
+	jwksURL      string
+	issuer       string
+	audience     string
+	subjectClaim string
+	roleClaim    string
+	roles        map[string]model.Role
+	defaultRole  model.Role
+	leeway       time.Duration
+	httpClient   httpclient.HTTPClient
+
+	mu        sync.Mutex
+	keys      *jose.JSONWebKeySet
+	fetchedAt time.Time
+}
+
+var _ interfaces.Authenticator = &JWT{}
+
+// JWTOption configures JWT
+type JWTOption func(*JWT)
+
+// WithIssuer sets the required "iss" claim
+func WithIssuer(issuer string) JWTOption {
+	return func(a *JWT) {
+		a.issuer = issuer
+	}
+}
+
+// WithAudience sets the audience that the "aud" claim must include
+func WithAudience(audience string) JWTOption {
+	return func(a *JWT) {
+		a.audience = audience
+	}
+}
+
+// WithSubjectClaim sets the claim naming the user (default: "sub"), e.g. "email"
+func WithSubjectClaim(claim string) JWTOption {
+	return func(a *JWT) {
+		if claim != "" {
+			a.subjectClaim = claim
+		}
+	}
+}
+
+// WithRoleClaim sets the claim holding the role, a string or a list of
+// strings (default: "role"). The highest role found is used.
+func WithRoleClaim(claim string) JWTOption {
+	return func(a *JWT) {
+		if claim != "" {
+			a.roleClaim = claim
+		}
+	}
+}
+
+// WithRoleMapping maps values of the role claim, e.g. group names, to roles.
+// Without it, the values must be role names.
+func WithRoleMapping(roles map[string]model.Role) JWTOption {
+	return func(a *JWT) {
+		a.roles = roles
+	}
+}
+
+// WithJWTDefaultRole sets the role of tokens without a known role. Without
+// it, such tokens are rejected.
+func WithJWTDefaultRole(role model.Role) JWTOption {
+	return func(a *JWT) {
+		a.defaultRole = role
+	}
+}
+
+// WithLeeway sets the allowed clock skew for time claims (default: 1 minute)
+func WithLeeway(leeway time.Duration) JWTOption {
+	return func(a *JWT) {
+		a.leeway = leeway
+	}
+}
+
+// WithJWKSClient sets the HTTP client fetching the JWKS
+func WithJWKSClient(client httpclient.HTTPClient) JWTOption {
+	return func(a *JWT) {
+		a.httpClient = client
+	}
+}
+
+// NewJWT creates an authenticator of tokens signed by the keys at jwksURL
+func NewJWT(jwksURL string, opts ...JWTOption) *JWT {
+	a := &JWT{
+		jwksURL:      jwksURL,
+		subjectClaim: "sub",
+		roleClaim:    "role",
+		leeway:       jwt.DefaultLeeway,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Authenticate validates a bearer JWT and returns its principal. Bearer
+// tokens that are not JWTs are left to the next authenticator.
+func (a *JWT) Authenticate(r *http.Request) (*model.Principal, error) {
+	raw, ok := bearerToken(r)
+	if !ok || strings.Count(raw, ".") != 2 {
+		return nil, interfaces.ErrNoCredentials
+	}
+
+	token, err := jwt.ParseSigned(raw, jwtAlgorithms)
+	if err != nil {
+		return nil, goerr.Wrap(interfaces.ErrInvalidCredentials, "malformed JWT", goerr.V("error", err.Error()))
+	}
+
+	key, err := a.key(r.Context(), token.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims jwt.Claims
+	var custom map[string]any
+	if err := token.Claims(key, &claims, &custom); err != nil {
+		return nil, goerr.Wrap(interfaces.ErrInvalidCredentials, "invalid JWT signature", goerr.V("error", err.Error()))
+	}
+	if claims.Expiry == nil {
+		return nil, goerr.Wrap(interfaces.ErrInvalidCredentials, "JWT has no expiry")
+	}
+	expected := jwt.Expected{Issuer: a.issuer, Time: time.Now()}
+	if a.audience != "" {
+		expected.AnyAudience = jwt.Audience{a.audience}
+	}
+	if err := claims.ValidateWithLeeway(expected, a.leeway); err != nil {
+		return nil, goerr.Wrap(interfaces.ErrInvalidCredentials, "invalid JWT claims", goerr.V("error", err.Error()))
+	}
+
+	subject, _ := custom[a.subjectClaim].(string)
+	if subject == "" {
+		return nil, goerr.Wrap(interfaces.ErrInvalidCredentials, "JWT has no subject", goerr.V("claim", a.subjectClaim))
+	}
+
+	role := a.role(custom[a.roleClaim])
+	if role == "" {
+		return nil, goerr.Wrap(interfaces.ErrInvalidCredentials, "JWT has no role", goerr.V("subject", subject))
+	}
+
+	return &model.Principal{
+		Subject: subject,
+		Role:    role,
+		Method:  model.AuthMethodJWT,
+	}, nil
+}
+
+// role returns the highest role of the values of the role claim
+func (a *JWT) role(claim any) model.Role {
+	var values []string
+	switch v := claim.(type) {
+	case string:
+		values = []string{v}
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	roles := make([]model.Role, 0, len(values))
+	for _, v := range values {
+		if a.roles != nil {
+			roles = append(roles, a.roles[v])
+		} else {
+			roles = append(roles, model.Role(v))
+		}
+	}
+	if role := highestRole(roles); role != "" {
+		return role
+	}
+	return a.defaultRole
+}
+
+// key returns the verification key of kid, fetching the JWKS when it is
+// stale or does not have the key, e.g. after a key rotation
+func (a *JWT) key(ctx context.Context, kid string) (*jose.JSONWebKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	stale := a.keys == nil || now.Sub(a.fetchedAt) > jwksMaxAge
+	if !stale && len(a.keys.Key(kid)) == 0 && now.Sub(a.fetchedAt) > jwksMinRefresh {
+		stale = true
+	}
+	if stale {
+		if err := a.fetchKeys(ctx); err != nil && a.keys == nil {
+			return nil, err
+		}
+		a.fetchedAt = now
+	}
+
+	keys := a.keys.Key(kid)
+	if len(keys) == 0 {
+		return nil, goerr.Wrap(interfaces.ErrInvalidCredentials, "unknown JWT signing key", goerr.V("kid", kid))
+	}
+	return &keys[0], nil
+}
+
+// fetchKeys fetches the JWKS. Callers hold the lock.
+func (a *JWT) fetchKeys(ctx context.Context) error {
+	data, err := httpclient.FetchWithClient(ctx, a.httpClient, a.jwksURL)
+	if err != nil {
+		return goerr.Wrap(err, "failed to fetch JWKS", goerr.V("url", a.jwksURL))
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return goerr.Wrap(err, "invalid JWKS", goerr.V("url", a.jwksURL))
+	}
+	a.keys = &keys
+	return nil
+}
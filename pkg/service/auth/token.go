@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+const tokenHashPrefix = "sha256:"
+
+// StaticToken is an API token given in the configuration. Only the hash of
+// the token is stored.
+type StaticToken struct {
+	Name string     // Subject of requests with the token
+	Hash string     // "sha256:<hex>", see HashToken
+	Role model.Role // Role granted by the token
+}
+
+// HashToken returns the hash of a token in the format of StaticToken.Hash
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return tokenHashPrefix + hex.EncodeToString(sum[:])
+}
+
+// StaticTokens authenticates bearer tokens matching configured hashes
+type StaticTokens struct {
+	tokens []staticToken
+}
+
+type staticToken struct {
+	name string
+	hash []byte
+	role model.Role
+}
+
+var _ interfaces.Authenticator = &StaticTokens{}
+
+// NewStaticTokens creates an authenticator of the tokens
+func NewStaticTokens(tokens []StaticToken) (*StaticTokens, error) {
+	a := &StaticTokens{}
+	for _, t := range tokens {
+		if t.Name == "" {
+			return nil, goerr.New("token name is required")
+		}
+		if !t.Role.IsValid() {
+			return nil, goerr.New("invalid token role", goerr.V("name", t.Name), goerr.V("role", t.Role))
+		}
+		hexHash, ok := strings.CutPrefix(t.Hash, tokenHashPrefix)
+		if !ok {
+			return nil, goerr.New("token hash must start with "+tokenHashPrefix, goerr.V("name", t.Name))
+		}
+		hash, err := hex.DecodeString(hexHash)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, goerr.New("invalid token hash", goerr.V("name", t.Name))
+		}
+		a.tokens = append(a.tokens, staticToken{name: t.Name, hash: hash, role: t.Role})
+	}
+	return a, nil
+}
+
+// Authenticate returns the principal of a bearer token matching a configured
+// hash. Other tokens are left to the next authenticator.
+func (a *StaticTokens) Authenticate(r *http.Request) (*model.Principal, error) {
+	token, ok := bearerToken(r)
+	if !ok {
+		return nil, interfaces.ErrNoCredentials
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare(sum[:], t.hash) == 1 {
+			return &model.Principal{
+				Subject: t.name,
+				Role:    t.role,
+				Method:  model.AuthMethodToken,
+			}, nil
+		}
+	}
+	return nil, interfaces.ErrNoCredentials
+}
@@ -0,0 +1,79 @@
+// Package embedding provides embedders generating IoC embeddings with an LLM
+// provider or a local OpenAI-compatible endpoint. The n-gram embedder is
+// vectorizer.NGramVectorizer.
+package embedding
+
+import (
+	"context"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/gollem"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+)
+
+// ErrDimensionMismatch is returned when an embedding does not have the configured dimension
+var ErrDimensionMismatch = goerr.New("embedding dimension mismatch")
+
+// LLM generates embeddings with the embedding API of a gollem LLM client (OpenAI, Gemini)
+type LLM struct {
+	client    gollem.LLMClient
+	model     string
+	dimension int
+}
+
+var _ interfaces.Embedder = &LLM{}
+
+// NewLLM creates an embedder using client. model identifies the embedding model
+// of the client, e.g. "gemini/text-embedding-004". The provider is asked for
+// embeddings of dimension elements.
+func NewLLM(client gollem.LLMClient, model string, dimension int) *LLM {
+	return &LLM{
+		client:    client,
+		model:     model,
+		dimension: dimension,
+	}
+}
+
+// Embed generates the embeddings of texts
+func (e *LLM) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	vectors, err := e.client.GenerateEmbedding(ctx, e.dimension, texts)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to generate embeddings", goerr.V("model", e.model))
+	}
+	return toFloat32(vectors, len(texts), e.dimension, e.model)
+}
+
+// Model returns the embedding model
+func (e *LLM) Model() string {
+	return e.model
+}
+
+// Dimension returns the embedding dimension
+func (e *LLM) Dimension() int {
+	return e.dimension
+}
+
+// toFloat32 converts embeddings and checks their number and dimension
+func toFloat32(vectors [][]float64, count, dimension int, model string) ([][]float32, error) {
+	if len(vectors) != count {
+		return nil, goerr.New("number of embeddings does not match number of texts",
+			goerr.V("model", model), goerr.V("texts", count), goerr.V("embeddings", len(vectors)))
+	}
+
+	result := make([][]float32, len(vectors))
+	for i, vec := range vectors {
+		if len(vec) != dimension {
+			return nil, goerr.Wrap(ErrDimensionMismatch, "embedding has unexpected dimension",
+				goerr.V("model", model), goerr.V("expected", dimension), goerr.V("actual", len(vec)))
+		}
+		result[i] = make([]float32, len(vec))
+		for j, v := range vec {
+			result[i][j] = float32(v)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package embedding_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m-mizutani/gollem/mock"
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/service/embedding"
+)
+
+func TestLLM(t *testing.T) {
+	ctx := context.Background()
+	var requested int
+	client := &mock.LLMClientMock{
+		GenerateEmbeddingFunc: func(ctx context.Context, dimension int, input []string) ([][]float64, error) {
+			requested = dimension
+			vectors := make([][]float64, len(input))
+			for i := range input {
+				vectors[i] = []float64{float64(i), 0.5, 1}
+			}
+			return vectors, nil
+		},
+	}
+
+	e := embedding.NewLLM(client, "openai/text-embedding-3-small", 3)
+	gt.Equal(t, e.Model(), "openai/text-embedding-3-small")
+	gt.Equal(t, e.Dimension(), 3)
+
+	vectors, err := e.Embed(ctx, []string{"evil.example C2", "198.51.100.1 scanner"})
+	gt.NoError(t, err)
+	gt.Equal(t, requested, 3)
+	gt.Equal(t, vectors, [][]float32{{0, 0.5, 1}, {1, 0.5, 1}})
+
+	t.Run("dimension mismatch", func(t *testing.T) {
+		_, err := embedding.NewLLM(client, "m", 128).Embed(ctx, []string{"x"})
+		gt.True(t, errors.Is(err, embedding.ErrDimensionMismatch))
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		failing := &mock.LLMClientMock{
+			GenerateEmbeddingFunc: func(ctx context.Context, dimension int, input []string) ([][]float64, error) {
+				return nil, errors.New("quota exceeded")
+			},
+		}
+		_, err := embedding.NewLLM(failing, "m", 3).Embed(ctx, []string{"x"})
+		gt.Error(t, err)
+	})
+}
+
+func TestOpenAICompatible(t *testing.T) {
+	ctx := context.Background()
+
+	var received struct {
+		Model      string   `json:"model"`
+		Input      []string `json:"input"`
+		Dimensions int      `json:"dimensions"`
+	}
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gt.Equal(t, r.URL.Path, "/v1/embeddings")
+		auth = r.Header.Get("Authorization")
+		gt.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		// Respond in reverse order to check that the index is used
+		type item struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		}
+		var data []item
+		for i := len(received.Input) - 1; i >= 0; i-- {
+			data = append(data, item{Index: i, Embedding: []float64{float64(i), 1}})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		gt.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": data}))
+	}))
+	defer server.Close()
+
+	e := embedding.NewOpenAICompatible(server.URL+"/v1/", "nomic-embed-text", 2, embedding.WithAPIKey("secret"))
+	vectors, err := e.Embed(ctx, []string{"a", "b", "c"})
+	gt.NoError(t, err)
+	gt.Equal(t, vectors, [][]float32{{0, 1}, {1, 1}, {2, 1}})
+	gt.Equal(t, received.Model, "nomic-embed-text")
+	gt.Equal(t, received.Input, []string{"a", "b", "c"})
+	gt.Equal(t, received.Dimensions, 0)
+	gt.Equal(t, auth, "Bearer secret")
+
+	t.Run("dimensions parameter", func(t *testing.T) {
+		e := embedding.NewOpenAICompatible(server.URL+"/v1", "m", 2, embedding.WithDimensionsParameter())
+		_, err := e.Embed(ctx, []string{"a"})
+		gt.NoError(t, err)
+		gt.Equal(t, received.Dimensions, 2)
+	})
+
+	t.Run("dimension mismatch", func(t *testing.T) {
+		_, err := embedding.NewOpenAICompatible(server.URL+"/v1", "m", 768).Embed(ctx, []string{"a"})
+		gt.True(t, errors.Is(err, embedding.ErrDimensionMismatch))
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"error": "model not found"}`, http.StatusNotFound)
+		}))
+		defer failing.Close()
+
+		_, err := embedding.NewOpenAICompatible(failing.URL, "m", 2).Embed(ctx, []string{"a"})
+		gt.Error(t, err)
+	})
+}
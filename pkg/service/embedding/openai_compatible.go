@@ -0,0 +1,152 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/utils/httpclient"
+)
+
+const defaultTimeout = 60 * time.Second
+
+// OpenAICompatible generates embeddings with the /embeddings endpoint of an
+// OpenAI-compatible server such as Ollama, vLLM or LM Studio
+type OpenAICompatible struct {
+	baseURL    string
+	model      string
+	dimension  int
+	apiKey     string
+	sendDim    bool
+	httpClient httpclient.HTTPClient
+}
+
+var _ interfaces.Embedder = &OpenAICompatible{}
+
+// OpenAICompatibleOption configures OpenAICompatible
+type OpenAICompatibleOption func(*OpenAICompatible)
+
+// WithAPIKey sets the bearer token sent to the server
+func WithAPIKey(apiKey string) OpenAICompatibleOption {
+	return func(e *OpenAICompatible) {
+		e.apiKey = apiKey
+	}
+}
+
+// WithDimensionsParameter sends the dimension in the "dimensions" request
+// parameter, for servers and models that can shorten embeddings. Without it,
+// the model must produce embeddings of the configured dimension.
+func WithDimensionsParameter() OpenAICompatibleOption {
+	return func(e *OpenAICompatible) {
+		e.sendDim = true
+	}
+}
+
+// WithHTTPClient sets the HTTP client
+func WithHTTPClient(client httpclient.HTTPClient) OpenAICompatibleOption {
+	return func(e *OpenAICompatible) {
+		e.httpClient = client
+	}
+}
+
+// NewOpenAICompatible creates an embedder for the server at baseURL, e.g.
+// "http://localhost:11434/v1", generating embeddings of dimension elements with model
+func NewOpenAICompatible(baseURL, model string, dimension int, opts ...OpenAICompatibleOption) *OpenAICompatible {
+	e := &OpenAICompatible{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		model:      model,
+		dimension:  dimension,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+type embeddingRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed generates the embeddings of texts
+func (e *OpenAICompatible) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	body := embeddingRequest{Model: e.model, Input: texts}
+	if e.sendDim {
+		body.Dimensions = e.dimension
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to marshal embedding request")
+	}
+
+	url := e.baseURL + "/embeddings"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create embedding request", goerr.V("url", url))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, goerr.Wrap(err, "embedding request failed", goerr.V("url", url))
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read embedding response", goerr.V("url", url))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, goerr.New("embedding request returned an error status",
+			goerr.V("url", url),
+			goerr.V("status_code", resp.StatusCode),
+			goerr.V("body", string(respBody)))
+	}
+
+	var parsed embeddingResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, goerr.Wrap(err, "failed to parse embedding response", goerr.V("url", url))
+	}
+
+	// Servers may return the embeddings in any order
+	vectors := make([][]float64, len(parsed.Data))
+	for _, d := range parsed.Data {
+		if d.Index < 0 || d.Index >= len(vectors) || vectors[d.Index] != nil {
+			return nil, goerr.New("invalid index in embedding response", goerr.V("index", d.Index))
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return toFloat32(vectors, len(texts), e.dimension, e.model)
+}
+
+// Model returns the embedding model
+func (e *OpenAICompatible) Model() string {
+	return e.model
+}
+
+// Dimension returns the embedding dimension
+func (e *OpenAICompatible) Dimension() int {
+	return e.dimension
+}
@@ -5,12 +5,14 @@ import (
 	"encoding/csv"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/m-mizutani/goerr/v2"
 	"github.com/secmon-lab/beehive/pkg/domain/model"
 	"github.com/secmon-lab/beehive/pkg/utils/httpclient"
+	"github.com/secmon-lab/beehive/pkg/utils/tracing"
 )
 
 var (
@@ -98,6 +100,7 @@ type FeedEntry struct {
 	Tags        []string
 	FirstSeen   time.Time
 	LastSeen    time.Time
+	Confidence  int // Feed-native confidence 0-100 (0 = not provided)
 }
 
 // Service provides threat intelligence feed fetching and parsing
@@ -109,7 +112,8 @@ type Service struct {
 func New() *Service {
 	return &Service{
 		client: &http.Client{
-			Timeout: 60 * time.Second,
+			Timeout:   60 * time.Second,
+			Transport: tracing.Transport(http.DefaultTransport),
 		},
 	}
 }
@@ -215,6 +219,7 @@ func (s *Service) FetchAbuseCHThreatFox(ctx context.Context, feedURL string) ([]
 		threatType := record[4]
 		malware := record[7] // malware_printable
 		lastSeen := parseDate(record[8])
+		confidence := parseConfidence(record[9])
 		tags := parseTags(record[11])
 
 		// Map ThreatFox IOC type to our IOC type
@@ -233,6 +238,7 @@ func (s *Service) FetchAbuseCHThreatFox(ctx context.Context, feedURL string) ([]
 			Tags:        tags,
 			FirstSeen:   firstSeen,
 			LastSeen:    lastSeen,
+			Confidence:  confidence,
 		}
 
 		entries = append(entries, entry)
@@ -468,6 +474,18 @@ func parseTags(tagStr string) []string {
 	return result
 }
 
+// parseConfidence parses a 0-100 confidence value. Returns 0 if missing or invalid.
+func parseConfidence(s string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 {
+		return 0
+	}
+	if n > 100 {
+		return 100
+	}
+	return n
+}
+
 // mapThreatFoxType maps ThreatFox IOC type to our IOC type
 func mapThreatFoxType(tfType string) model.IoCType {
 	tfType = strings.ToLower(strings.TrimSpace(tfType))
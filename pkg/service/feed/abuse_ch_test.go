@@ -147,12 +147,14 @@ func TestService_FetchAbuseCHThreatFox(t *testing.T) {
 
 			expectedTime, _ := time.Parse("2006-01-02 15:04:05", "2025-12-24 07:24:23")
 			gt.V(t, first.FirstSeen).Equal(expectedTime).Describe("first entry timestamp")
+			gt.V(t, first.Confidence).Equal(80).Describe("first entry confidence level")
 		})
 
 		// Verify second entry (Cobalt Strike)
 		gt.Array(t, entries).At(1, func(t testing.TB, second *feed.FeedEntry) {
 			gt.V(t, second.ID).Equal("1685599").Describe("second entry IOC ID")
 			gt.V(t, second.Value).Equal("47.96.75.57:8081").Describe("second entry value")
+			gt.V(t, second.Confidence).Equal(100).Describe("second entry confidence level")
 			gt.S(t, second.Description).Contains("Cobalt Strike").Describe("second entry malware name")
 			gt.Array(t, second.Tags).Length(3).Describe("second entry should have 3 tags")
 			gt.Array(t, second.Tags).Has("AS37963").Describe("should have AS tag")
@@ -21,7 +21,8 @@ func (s *Service) FetchIPsumLevel3(ctx context.Context, feedURL string) ([]*Feed
 	if feedURL == "" {
 		feedURL = IPsumLevel3URL
 	}
-	return s.FetchSimpleIPList(ctx, feedURL, []string{"ipsum", "threat-level-3"})
+	entries, err := s.FetchSimpleIPList(ctx, feedURL, []string{"ipsum", "threat-level-3"})
+	return setConfidence(entries, ipsumConfidence(3)), err
 }
 
 // FetchIPsumLevel4 fetches IPsum threat level 4 feed
@@ -29,7 +30,8 @@ func (s *Service) FetchIPsumLevel4(ctx context.Context, feedURL string) ([]*Feed
 	if feedURL == "" {
 		feedURL = IPsumLevel4URL
 	}
-	return s.FetchSimpleIPList(ctx, feedURL, []string{"ipsum", "threat-level-4"})
+	entries, err := s.FetchSimpleIPList(ctx, feedURL, []string{"ipsum", "threat-level-4"})
+	return setConfidence(entries, ipsumConfidence(4)), err
 }
 
 // FetchIPsumLevel5 fetches IPsum threat level 5 feed
@@ -37,7 +39,8 @@ func (s *Service) FetchIPsumLevel5(ctx context.Context, feedURL string) ([]*Feed
 	if feedURL == "" {
 		feedURL = IPsumLevel5URL
 	}
-	return s.FetchSimpleIPList(ctx, feedURL, []string{"ipsum", "threat-level-5"})
+	entries, err := s.FetchSimpleIPList(ctx, feedURL, []string{"ipsum", "threat-level-5"})
+	return setConfidence(entries, ipsumConfidence(5)), err
 }
 
 // FetchIPsumLevel6 fetches IPsum threat level 6 feed
@@ -45,7 +48,8 @@ func (s *Service) FetchIPsumLevel6(ctx context.Context, feedURL string) ([]*Feed
 	if feedURL == "" {
 		feedURL = IPsumLevel6URL
 	}
-	return s.FetchSimpleIPList(ctx, feedURL, []string{"ipsum", "threat-level-6"})
+	entries, err := s.FetchSimpleIPList(ctx, feedURL, []string{"ipsum", "threat-level-6"})
+	return setConfidence(entries, ipsumConfidence(6)), err
 }
 
 // FetchIPsumLevel7 fetches IPsum threat level 7 feed
@@ -53,7 +57,8 @@ func (s *Service) FetchIPsumLevel7(ctx context.Context, feedURL string) ([]*Feed
 	if feedURL == "" {
 		feedURL = IPsumLevel7URL
 	}
-	return s.FetchSimpleIPList(ctx, feedURL, []string{"ipsum", "threat-level-7"})
+	entries, err := s.FetchSimpleIPList(ctx, feedURL, []string{"ipsum", "threat-level-7"})
+	return setConfidence(entries, ipsumConfidence(7)), err
 }
 
 // FetchIPsumLevel8 fetches IPsum threat level 8 feed
@@ -61,5 +66,20 @@ func (s *Service) FetchIPsumLevel8(ctx context.Context, feedURL string) ([]*Feed
 	if feedURL == "" {
 		feedURL = IPsumLevel8URL
 	}
-	return s.FetchSimpleIPList(ctx, feedURL, []string{"ipsum", "threat-level-8"})
+	entries, err := s.FetchSimpleIPList(ctx, feedURL, []string{"ipsum", "threat-level-8"})
+	return setConfidence(entries, ipsumConfidence(8)), err
+}
+
+// ipsumConfidence converts an IPsum level (number of blocklists an IP
+// appears on, 3-8) to a confidence value: level 3 = 50, +10 per level.
+func ipsumConfidence(level int) int {
+	return min(50+(level-3)*10, 100)
+}
+
+// setConfidence sets the feed-native confidence of all entries
+func setConfidence(entries []*FeedEntry, confidence int) []*FeedEntry {
+	for _, entry := range entries {
+		entry.Confidence = confidence
+	}
+	return entries
 }
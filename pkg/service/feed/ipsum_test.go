@@ -39,6 +39,7 @@ func TestService_FetchIPsumLevel3(t *testing.T) {
 			gt.A(t, first.Tags).Length(2).Describe("should have 2 tags")
 			gt.A(t, first.Tags).Has("ipsum").Describe("should have ipsum tag")
 			gt.A(t, first.Tags).Has("threat-level-3").Describe("should have threat-level-3 tag")
+			gt.V(t, first.Confidence).Equal(50).Describe("level 3 should map to confidence 50")
 		})
 
 		// Verify third entry
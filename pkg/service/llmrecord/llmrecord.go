@@ -0,0 +1,174 @@
+// Package llmrecord records LLM responses to a directory and replays them, so
+// that extraction can be evaluated repeatably and without network access.
+// Responses are keyed by a hash of the prompt: a changed prompt has no recording
+// and must be recorded again.
+package llmrecord
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/gollem"
+)
+
+var (
+	// ErrNoRecording is returned by the replayer for a prompt without recording
+	ErrNoRecording = goerr.New("no recorded LLM response for prompt")
+
+	errNotSupported = goerr.New("not supported in replay mode")
+)
+
+// recording is a recorded response, stored as <key>.json
+type recording struct {
+	Prompt string   `json:"prompt"`
+	Texts  []string `json:"texts"`
+}
+
+// Key returns the recording key of the prompt inputs
+func Key(input ...gollem.Input) string {
+	h := sha256.New()
+	for _, in := range input {
+		if text, ok := in.(gollem.Text); ok {
+			h.Write([]byte(text))
+		}
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func promptOf(input []gollem.Input) string {
+	var prompt string
+	for _, in := range input {
+		if text, ok := in.(gollem.Text); ok {
+			prompt += string(text)
+		}
+	}
+	return prompt
+}
+
+// Recorder is an LLM client that saves the responses of the wrapped client
+type Recorder struct {
+	client gollem.LLMClient
+	dir    string
+}
+
+var _ gollem.LLMClient = &Recorder{}
+
+// NewRecorder creates a recorder saving responses of client to dir
+func NewRecorder(client gollem.LLMClient, dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, goerr.Wrap(err, "failed to create recording directory", goerr.V("dir", dir))
+	}
+	return &Recorder{client: client, dir: dir}, nil
+}
+
+// NewSession creates a session of the wrapped client that records responses
+func (r *Recorder) NewSession(ctx context.Context, options ...gollem.SessionOption) (gollem.Session, error) {
+	session, err := r.client.NewSession(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingSession{Session: session, dir: r.dir}, nil
+}
+
+// GenerateEmbedding calls the wrapped client without recording
+func (r *Recorder) GenerateEmbedding(ctx context.Context, dimension int, input []string) ([][]float64, error) {
+	return r.client.GenerateEmbedding(ctx, dimension, input)
+}
+
+type recordingSession struct {
+	gollem.Session
+	dir string
+}
+
+func (s *recordingSession) GenerateContent(ctx context.Context, input ...gollem.Input) (*gollem.Response, error) {
+	resp, err := s.Session.GenerateContent(ctx, input...)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.MarshalIndent(recording{Prompt: promptOf(input), Texts: resp.Texts}, "", "  ")
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to encode LLM response recording")
+	}
+	path := filepath.Join(s.dir, Key(input...)+".json")
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		return nil, goerr.Wrap(err, "failed to write LLM response recording", goerr.V("path", path))
+	}
+	return resp, nil
+}
+
+// Replayer is an LLM client that returns recorded responses
+type Replayer struct {
+	dir string
+}
+
+var _ gollem.LLMClient = &Replayer{}
+
+// NewReplayer creates a replayer of the recordings in dir
+func NewReplayer(dir string) (*Replayer, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to open recording directory", goerr.V("dir", dir))
+	}
+	if !info.IsDir() {
+		return nil, goerr.New("recording path is not a directory", goerr.V("dir", dir))
+	}
+	return &Replayer{dir: dir}, nil
+}
+
+// NewSession creates a session returning recorded responses
+func (r *Replayer) NewSession(ctx context.Context, options ...gollem.SessionOption) (gollem.Session, error) {
+	return &replaySession{dir: r.dir}, nil
+}
+
+// GenerateEmbedding is not supported in replay mode
+func (r *Replayer) GenerateEmbedding(ctx context.Context, dimension int, input []string) ([][]float64, error) {
+	return nil, goerr.Wrap(errNotSupported, "failed to generate embedding")
+}
+
+type replaySession struct {
+	dir     string
+	history gollem.History
+}
+
+func (s *replaySession) GenerateContent(ctx context.Context, input ...gollem.Input) (*gollem.Response, error) {
+	key := Key(input...)
+	path := filepath.Join(s.dir, key+".json")
+
+	raw, err := os.ReadFile(path) // #nosec G304 -- path is built from a hash
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, goerr.Wrap(ErrNoRecording, "failed to replay LLM response", goerr.V("key", key))
+		}
+		return nil, goerr.Wrap(err, "failed to read LLM response recording", goerr.V("path", path))
+	}
+
+	var rec recording
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, goerr.Wrap(err, "failed to decode LLM response recording", goerr.V("path", path))
+	}
+	return &gollem.Response{Texts: rec.Texts}, nil
+}
+
+func (s *replaySession) GenerateStream(ctx context.Context, input ...gollem.Input) (<-chan *gollem.Response, error) {
+	return nil, goerr.Wrap(errNotSupported, "failed to generate stream")
+}
+
+func (s *replaySession) History() (*gollem.History, error) {
+	return &s.history, nil
+}
+
+func (s *replaySession) AppendHistory(h *gollem.History) error {
+	return goerr.Wrap(errNotSupported, "failed to append history")
+}
+
+func (s *replaySession) CountToken(ctx context.Context, input ...gollem.Input) (int, error) {
+	return 0, goerr.Wrap(errNotSupported, "failed to count tokens")
+}
@@ -0,0 +1,66 @@
+package llmrecord_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m-mizutani/gollem"
+	"github.com/m-mizutani/gollem/mock"
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/service/llmrecord"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "recordings")
+
+	calls := 0
+	client := &mock.LLMClientMock{
+		NewSessionFunc: func(ctx context.Context, options ...gollem.SessionOption) (gollem.Session, error) {
+			return &mock.SessionMock{
+				GenerateContentFunc: func(ctx context.Context, input ...gollem.Input) (*gollem.Response, error) {
+					calls++
+					return &gollem.Response{Texts: []string{"answer to " + string(input[0].(gollem.Text))}}, nil
+				},
+			}, nil
+		},
+	}
+
+	recorder, err := llmrecord.NewRecorder(client, dir)
+	gt.NoError(t, err)
+	for _, prompt := range []string{"first", "second"} {
+		session, err := recorder.NewSession(ctx)
+		gt.NoError(t, err)
+		resp, err := session.GenerateContent(ctx, gollem.Text(prompt))
+		gt.NoError(t, err)
+		gt.Equal(t, resp.Texts, []string{"answer to " + prompt})
+	}
+	gt.Equal(t, calls, 2)
+
+	files, err := os.ReadDir(dir)
+	gt.NoError(t, err)
+	gt.A(t, files).Length(2)
+
+	replayer, err := llmrecord.NewReplayer(dir)
+	gt.NoError(t, err)
+	session, err := replayer.NewSession(ctx)
+	gt.NoError(t, err)
+
+	resp, err := session.GenerateContent(ctx, gollem.Text("second"))
+	gt.NoError(t, err)
+	gt.Equal(t, resp.Texts, []string{"answer to second"})
+	gt.Equal(t, calls, 2)
+
+	t.Run("prompt without recording", func(t *testing.T) {
+		_, err := session.GenerateContent(ctx, gollem.Text("changed prompt"))
+		gt.True(t, errors.Is(err, llmrecord.ErrNoRecording))
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := llmrecord.NewReplayer(filepath.Join(t.TempDir(), "none"))
+		gt.Error(t, err)
+	})
+}
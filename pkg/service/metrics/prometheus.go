@@ -0,0 +1,225 @@
+// Package metrics exports operational metrics in the Prometheus format
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+const (
+	namespace = "beehive"
+
+	// DefaultMaxSources is the default number of distinct source labels
+	DefaultMaxSources = 500
+
+	// otherLabel replaces label values beyond the limit
+	otherLabel = "other"
+)
+
+// Prometheus records metrics in a Prometheus registry served by Handler
+type Prometheus struct {
+	registry *prometheus.Registry
+	sources  *boundedLabel
+
+	fetchDuration    *prometheus.HistogramVec
+	fetchRuns        *prometheus.CounterVec
+	fetchItems       *prometheus.CounterVec
+	fetchIoCs        *prometheus.CounterVec
+	fetchErrors      *prometheus.CounterVec
+	fetchLastSuccess *prometheus.GaugeVec
+
+	llmDuration *prometheus.HistogramVec
+	llmTokens   *prometheus.CounterVec
+
+	graphqlDuration *prometheus.HistogramVec
+	graphqlErrors   *prometheus.CounterVec
+
+	repositoryDuration *prometheus.HistogramVec
+	repositoryErrors   *prometheus.CounterVec
+}
+
+var _ interfaces.Metrics = &Prometheus{}
+
+// Option configures Prometheus
+type Option func(*Prometheus)
+
+// WithMaxSources sets the number of distinct source labels. Fetches of
+// further sources are recorded with the source label "other".
+func WithMaxSources(n int) Option {
+	return func(p *Prometheus) {
+		p.sources = newBoundedLabel(n)
+	}
+}
+
+// New creates a registry with the metrics of beehive and the Go runtime
+func New(opts ...Option) *Prometheus {
+	p := &Prometheus{
+		registry: prometheus.NewRegistry(),
+		sources:  newBoundedLabel(DefaultMaxSources),
+
+		fetchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "fetch_duration_seconds",
+			Help:      "Processing time of fetches of a source",
+			Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200},
+		}, []string{"source", "source_type"}),
+		fetchRuns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "fetch_runs_total",
+			Help:      "Completed fetches of a source by status",
+		}, []string{"source", "status"}),
+		fetchItems: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "fetch_items_total",
+			Help:      "Articles or feed entries fetched from a source",
+		}, []string{"source"}),
+		fetchIoCs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "fetch_iocs_total",
+			Help:      "IoCs saved by fetches of a source by result: created, updated or unchanged",
+		}, []string{"source", "result"}),
+		fetchErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "fetch_errors_total",
+			Help:      "Errors of fetches of a source",
+		}, []string{"source"}),
+		fetchLastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "fetch_last_success_timestamp_seconds",
+			Help:      "Completion time of the last successful fetch of a source",
+		}, []string{"source"}),
+
+		llmDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "llm_request_duration_seconds",
+			Help:      "Latency of LLM generations by extraction step and result",
+			Buckets:   []float64{0.5, 1, 2.5, 5, 10, 20, 40, 80, 160},
+		}, []string{"operation", "result"}),
+		llmTokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "llm_tokens_total",
+			Help:      "Tokens used by LLM generations by extraction step and direction: input or output",
+		}, []string{"operation", "direction"}),
+
+		graphqlDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "graphql_field_duration_seconds",
+			Help:      "Latency of GraphQL root fields",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"field"}),
+		graphqlErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "graphql_field_errors_total",
+			Help:      "GraphQL root fields resolved with an error",
+		}, []string{"field"}),
+
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_duration_seconds",
+			Help:      "Latency of repository operations",
+			Buckets:   []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"operation"}),
+		repositoryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_errors_total",
+			Help:      "Repository operations returning an error, including not found",
+		}, []string{"operation"}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		p.fetchDuration, p.fetchRuns, p.fetchItems, p.fetchIoCs, p.fetchErrors, p.fetchLastSuccess,
+		p.llmDuration, p.llmTokens,
+		p.graphqlDuration, p.graphqlErrors,
+		p.repositoryDuration, p.repositoryErrors,
+	)
+	return p
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{Registry: p.registry})
+}
+
+// ObserveFetch records the statistics of a completed fetch
+func (p *Prometheus) ObserveFetch(history *model.History) {
+	source := p.sources.value(history.SourceID)
+
+	p.fetchDuration.WithLabelValues(source, string(history.SourceType)).Observe(history.ProcessingTime.Seconds())
+	p.fetchRuns.WithLabelValues(source, string(history.Status)).Inc()
+	p.fetchItems.WithLabelValues(source).Add(float64(history.ItemsFetched))
+	p.fetchIoCs.WithLabelValues(source, "created").Add(float64(history.IoCsCreated))
+	p.fetchIoCs.WithLabelValues(source, "updated").Add(float64(history.IoCsUpdated))
+	p.fetchIoCs.WithLabelValues(source, "unchanged").Add(float64(history.IoCsUnchanged))
+	p.fetchErrors.WithLabelValues(source).Add(float64(history.ErrorCount))
+	if history.Status == model.FetchStatusSuccess {
+		p.fetchLastSuccess.WithLabelValues(source).Set(float64(history.CompletedAt.Unix()))
+	}
+}
+
+// ObserveLLMCall records the latency and token usage of an LLM generation
+func (p *Prometheus) ObserveLLMCall(operation string, duration time.Duration, inputTokens, outputTokens int, err error) {
+	p.llmDuration.WithLabelValues(operation, result(err)).Observe(duration.Seconds())
+	p.llmTokens.WithLabelValues(operation, "input").Add(float64(inputTokens))
+	p.llmTokens.WithLabelValues(operation, "output").Add(float64(outputTokens))
+}
+
+// ObserveGraphQLField records the latency of a GraphQL root field
+func (p *Prometheus) ObserveGraphQLField(field string, duration time.Duration, err error) {
+	p.graphqlDuration.WithLabelValues(field).Observe(duration.Seconds())
+	if err != nil {
+		p.graphqlErrors.WithLabelValues(field).Inc()
+	}
+}
+
+// ObserveRepository records the latency of a repository operation
+func (p *Prometheus) ObserveRepository(operation string, duration time.Duration, err error) {
+	p.repositoryDuration.WithLabelValues(operation).Observe(duration.Seconds())
+	if err != nil {
+		p.repositoryErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// boundedLabel limits the distinct values of a label. Sources can be created
+// through the API, so their number is only bounded by this limit.
+type boundedLabel struct {
+	mu    sync.Mutex
+	limit int
+	seen  map[string]struct{}
+}
+
+func newBoundedLabel(limit int) *boundedLabel {
+	return &boundedLabel{limit: limit, seen: map[string]struct{}{}}
+}
+
+// value returns v while fewer than limit values have been seen, otherLabel after
+func (b *boundedLabel) value(v string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.seen[v]; ok {
+		return v
+	}
+	if len(b.seen) >= b.limit {
+		return otherLabel
+	}
+	b.seen[v] = struct{}{}
+	return v
+}
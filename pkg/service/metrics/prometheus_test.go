@@ -0,0 +1,93 @@
+package metrics_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/service/metrics"
+)
+
+func scrape(t *testing.T, p *metrics.Prometheus) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	p.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	gt.N(t, w.Code).Equal(http.StatusOK)
+	body, err := io.ReadAll(w.Body)
+	gt.NoError(t, err)
+	return string(body)
+}
+
+func TestPrometheus(t *testing.T) {
+	p := metrics.New()
+	completed := time.Unix(1767225600, 0)
+	p.ObserveFetch(&model.History{
+		SourceID:       "urlhaus",
+		SourceType:     model.SourceTypeFeed,
+		Status:         model.FetchStatusSuccess,
+		CompletedAt:    completed,
+		ProcessingTime: 3 * time.Second,
+		ItemsFetched:   10,
+		IoCsCreated:    4,
+		IoCsUpdated:    2,
+		IoCsUnchanged:  3,
+		ErrorCount:     1,
+	})
+	p.ObserveFetch(&model.History{
+		SourceID:    "urlhaus",
+		SourceType:  model.SourceTypeFeed,
+		Status:      model.FetchStatusFailure,
+		CompletedAt: completed.Add(time.Hour),
+		ErrorCount:  1,
+	})
+	p.ObserveLLMCall("extract", 2*time.Second, 1000, 50, nil)
+	p.ObserveLLMCall("extract", time.Second, 0, 0, errors.New("timeout"))
+	p.ObserveGraphQLField("Query.listIoCs", 10*time.Millisecond, nil)
+	p.ObserveGraphQLField("Mutation.fetchSource", time.Millisecond, errors.New("forbidden"))
+	p.ObserveRepository("BatchUpsertIoCs", 20*time.Millisecond, nil)
+	p.ObserveRepository("GetIoC", time.Millisecond, errors.New("not found"))
+
+	out := scrape(t, p)
+	for _, line := range []string{
+		`beehive_fetch_duration_seconds_count{source="urlhaus",source_type="feed"} 2`,
+		`beehive_fetch_runs_total{source="urlhaus",status="success"} 1`,
+		`beehive_fetch_runs_total{source="urlhaus",status="failure"} 1`,
+		`beehive_fetch_items_total{source="urlhaus"} 10`,
+		`beehive_fetch_iocs_total{result="created",source="urlhaus"} 4`,
+		`beehive_fetch_iocs_total{result="updated",source="urlhaus"} 2`,
+		`beehive_fetch_iocs_total{result="unchanged",source="urlhaus"} 3`,
+		`beehive_fetch_errors_total{source="urlhaus"} 2`,
+		// A failed fetch does not move the last success
+		`beehive_fetch_last_success_timestamp_seconds{source="urlhaus"} 1.7672256e+09`,
+		`beehive_llm_request_duration_seconds_count{operation="extract",result="success"} 1`,
+		`beehive_llm_request_duration_seconds_count{operation="extract",result="error"} 1`,
+		`beehive_llm_tokens_total{direction="input",operation="extract"} 1000`,
+		`beehive_llm_tokens_total{direction="output",operation="extract"} 50`,
+		`beehive_graphql_field_duration_seconds_count{field="Query.listIoCs"} 1`,
+		`beehive_graphql_field_errors_total{field="Mutation.fetchSource"} 1`,
+		`beehive_repository_operation_duration_seconds_count{operation="BatchUpsertIoCs"} 1`,
+		`beehive_repository_operation_errors_total{operation="GetIoC"} 1`,
+		`go_goroutines `,
+	} {
+		gt.S(t, out).Contains(line)
+	}
+	gt.S(t, out).NotContains(`beehive_graphql_field_errors_total{field="Query.listIoCs"}`)
+}
+
+func TestPrometheus_MaxSources(t *testing.T) {
+	p := metrics.New(metrics.WithMaxSources(2))
+	for _, id := range []string{"a", "b", "c", "d", "a"} {
+		p.ObserveFetch(&model.History{SourceID: id, SourceType: model.SourceTypeRSS, Status: model.FetchStatusSuccess})
+	}
+
+	out := scrape(t, p)
+	gt.S(t, out).Contains(`beehive_fetch_runs_total{source="a",status="success"} 2`)
+	gt.S(t, out).Contains(`beehive_fetch_runs_total{source="b",status="success"} 1`)
+	gt.S(t, out).Contains(`beehive_fetch_runs_total{source="other",status="success"} 2`)
+	gt.S(t, out).NotContains(`source="c"`)
+}
@@ -0,0 +1,20 @@
+package notify
+
+import (
+	"net/smtp"
+	"time"
+
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// Export internal functions for testing
+
+// BuildMessage is exported for testing
+func BuildMessage(from string, to []string, n *model.Notification, now time.Time) []byte {
+	return buildMessage(from, to, n, now)
+}
+
+// SetSendMail replaces the SMTP send function for testing
+func (e *Email) SetSendMail(f func(addr string, a smtp.Auth, from string, to []string, msg []byte) error) {
+	e.sendMail = f
+}
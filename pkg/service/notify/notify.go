@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/utils/httpclient"
+)
+
+var (
+	// ErrDeliveryFailed is returned when a channel rejects a notification
+	ErrDeliveryFailed = goerr.New("failed to deliver notification")
+)
+
+const defaultTimeout = 30 * time.Second
+
+func newHTTPClient() httpclient.HTTPClient {
+	return &http.Client{Timeout: defaultTimeout}
+}
+
+// postJSON sends body as JSON to url and expects a 2xx response
+func postJSON(ctx context.Context, client httpclient.HTTPClient, url string, headers map[string]string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return goerr.Wrap(err, "failed to marshal notification payload")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return goerr.Wrap(err, "failed to create request", goerr.V("url", url))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return goerr.Wrap(ErrDeliveryFailed, "HTTP request failed",
+			goerr.V("url", url), goerr.V("error", err))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return goerr.Wrap(ErrDeliveryFailed, "non-2xx status code",
+			goerr.V("url", url),
+			goerr.V("status_code", resp.StatusCode))
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/service/notify"
+)
+
+func newNotification() *model.Notification {
+	return &model.Notification{
+		Key:     "watchlist:evil:ioc-1",
+		Rule:    "evil",
+		Kind:    model.NotificationRuleWatchlist,
+		Subject: "Watchlist match: evil",
+		Body:    "1 new IoC(s)\n- domain login.evil.example",
+		Event: &model.NotificationEvent{
+			Rule: "evil",
+			Kind: model.NotificationRuleWatchlist,
+			IoCs: []*model.IoC{{
+				ID:         "ioc-1",
+				SourceID:   "threatfox",
+				Type:       model.IoCTypeDomain,
+				Value:      "login.evil.example",
+				Confidence: 72,
+			}},
+			Total: 1,
+		},
+		CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestWebhook_Send(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("posts JSON payload with headers", func(t *testing.T) {
+		var got notify.WebhookPayload
+		var gotHeader string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotHeader = r.Header.Get("X-Token")
+			gt.Equal(t, r.Header.Get("Content-Type"), "application/json")
+			gt.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		ch := notify.NewWebhook(server.URL, map[string]string{"X-Token": "secret"})
+		gt.NoError(t, ch.Send(ctx, newNotification()))
+
+		gt.Equal(t, gotHeader, "secret")
+		gt.Equal(t, got.Key, "watchlist:evil:ioc-1")
+		gt.Equal(t, got.Kind, model.NotificationRuleWatchlist)
+		gt.Equal(t, got.Subject, "Watchlist match: evil")
+		gt.Equal(t, got.Total, 1)
+		gt.A(t, got.IoCs).Length(1).At(0, func(t testing.TB, ioc notify.WebhookIoC) {
+			gt.Equal(t, ioc.Value, "login.evil.example")
+			gt.Equal(t, ioc.Type, "domain")
+			gt.Equal(t, ioc.Confidence, 72)
+		})
+	})
+
+	t.Run("non-2xx response is an error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		err := notify.NewWebhook(server.URL, nil).Send(ctx, newNotification())
+		gt.Error(t, err)
+		gt.True(t, errors.Is(err, notify.ErrDeliveryFailed))
+	})
+}
+
+func TestSlack_Send(t *testing.T) {
+	ctx := context.Background()
+
+	var got notify.SlackPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		gt.NoError(t, err)
+		gt.NoError(t, json.Unmarshal(body, &got))
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	gt.NoError(t, notify.NewSlack(server.URL).Send(ctx, newNotification()))
+	gt.Equal(t, got.Text, "*Watchlist match: evil*\n1 new IoC(s)\n- domain login.evil.example")
+}
+
+func TestEmail_Send(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sends message to all recipients", func(t *testing.T) {
+		ch := notify.NewEmail(notify.SMTPConfig{
+			Host:     "smtp.example.com",
+			Username: "beehive",
+			Password: "pass",
+			From:     "beehive@example.com",
+			To:       []string{"soc@example.com", "ir@example.com"},
+		})
+
+		var gotAddr string
+		var gotTo []string
+		var gotMsg []byte
+		ch.SetSendMail(func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+			gotAddr = addr
+			gotTo = to
+			gotMsg = msg
+			gt.NotEqual(t, a, nil)
+			return nil
+		})
+
+		gt.NoError(t, ch.Send(ctx, newNotification()))
+		gt.Equal(t, gotAddr, "smtp.example.com:587")
+		gt.A(t, gotTo).Length(2)
+		gt.S(t, string(gotMsg)).Contains("Subject: Watchlist match: evil\r\n")
+		gt.S(t, string(gotMsg)).Contains("- domain login.evil.example\r\n")
+	})
+
+	t.Run("relay error is a delivery failure", func(t *testing.T) {
+		ch := notify.NewEmail(notify.SMTPConfig{Host: "smtp.example.com", From: "a@example.com", To: []string{"b@example.com"}})
+		ch.SetSendMail(func(string, smtp.Auth, string, []string, []byte) error {
+			return errors.New("421 service not available")
+		})
+
+		err := ch.Send(ctx, newNotification())
+		gt.True(t, errors.Is(err, notify.ErrDeliveryFailed))
+	})
+
+	t.Run("message headers", func(t *testing.T) {
+		n := newNotification()
+		n.Subject = "Überwachung"
+		msg := string(notify.BuildMessage("a@example.com", []string{"b@example.com", "c@example.com"}, n, n.CreatedAt))
+		gt.S(t, msg).Contains("To: b@example.com, c@example.com\r\n")
+		gt.S(t, msg).Contains("Subject: =?utf-8?q?")
+		gt.S(t, msg).Contains("Date: Thu, 02 Jan 2025 03:04:05 +0000\r\n")
+	})
+}
@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/httpclient"
+)
+
+// Slack posts notifications to a Slack-compatible incoming webhook
+type Slack struct {
+	url    string
+	client httpclient.HTTPClient
+}
+
+var _ interfaces.NotificationChannel = &Slack{}
+
+// SlackPayload is the JSON body sent to an incoming webhook
+type SlackPayload struct {
+	Text string `json:"text"`
+}
+
+// NewSlack creates a Slack incoming webhook channel
+func NewSlack(url string) *Slack {
+	return &Slack{
+		url:    url,
+		client: newHTTPClient(),
+	}
+}
+
+// Send posts the notification as a single mrkdwn text message
+func (s *Slack) Send(ctx context.Context, n *model.Notification) error {
+	text := n.Body
+	if n.Subject != "" {
+		text = "*" + n.Subject + "*\n" + n.Body
+	}
+	return postJSON(ctx, s.client, s.url, nil, SlackPayload{Text: text})
+}
@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// SMTPConfig represents an SMTP relay configuration
+type SMTPConfig struct {
+	Host     string
+	Port     int // Defaults to 587
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// Email sends notifications as plain-text mail through an SMTP relay.
+// STARTTLS is used when the relay supports it.
+type Email struct {
+	cfg      SMTPConfig
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+var _ interfaces.NotificationChannel = &Email{}
+
+// NewEmail creates an email channel
+func NewEmail(cfg SMTPConfig) *Email {
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	return &Email{
+		cfg:      cfg,
+		sendMail: smtp.SendMail,
+	}
+}
+
+// Send delivers the notification to all recipients
+func (e *Email) Send(ctx context.Context, n *model.Notification) error {
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(e.cfg.Port))
+
+	var auth smtp.Auth
+	if e.cfg.Username != "" {
+		auth = smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)
+	}
+
+	msg := buildMessage(e.cfg.From, e.cfg.To, n, time.Now())
+
+	// net/smtp has no context support; run in a goroutine so cancellation is honored
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- e.sendMail(addr, auth, e.cfg.From, e.cfg.To, msg)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return goerr.Wrap(ctx.Err(), "email delivery canceled", goerr.V("addr", addr))
+	case err := <-errCh:
+		if err != nil {
+			return goerr.Wrap(ErrDeliveryFailed, "SMTP delivery failed",
+				goerr.V("addr", addr), goerr.V("error", err))
+		}
+		return nil
+	}
+}
+
+// buildMessage builds an RFC 5322 plain-text message
+func buildMessage(from string, to []string, n *model.Notification, now time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", n.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(n.Body, "\n", "\r\n"))
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/httpclient"
+)
+
+// Webhook posts notifications as JSON to a generic HTTP endpoint
+type Webhook struct {
+	url     string
+	headers map[string]string
+	client  httpclient.HTTPClient
+}
+
+var _ interfaces.NotificationChannel = &Webhook{}
+
+// WebhookPayload is the JSON body sent by Webhook
+type WebhookPayload struct {
+	Key        string                     `json:"key"`
+	Rule       string                     `json:"rule"`
+	Kind       model.NotificationRuleKind `json:"kind"`
+	Subject    string                     `json:"subject"`
+	Body       string                     `json:"body"`
+	SourceID   string                     `json:"source_id,omitempty"`
+	Tag        string                     `json:"tag,omitempty"`
+	IoCs       []WebhookIoC               `json:"iocs,omitempty"`
+	Lookalikes []WebhookLookalike         `json:"lookalikes,omitempty"`
+	Total      int                        `json:"total,omitempty"`
+	CreatedAt  time.Time                  `json:"created_at"`
+}
+
+// WebhookIoC is an IoC entry in WebhookPayload
+type WebhookIoC struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Value      string `json:"value"`
+	SourceID   string `json:"source_id"`
+	Confidence int    `json:"confidence"`
+}
+
+// WebhookLookalike is a lookalike domain entry in WebhookPayload
+type WebhookLookalike struct {
+	IoCID           string  `json:"ioc_id"`
+	Domain          string  `json:"domain"`
+	ProtectedDomain string  `json:"protected_domain"`
+	Technique       string  `json:"technique"`
+	EditDistance    int     `json:"edit_distance"`
+	Score           float64 `json:"score"`
+	SourceID        string  `json:"source_id"`
+}
+
+// NewWebhook creates a webhook channel. headers are added to every request.
+func NewWebhook(url string, headers map[string]string) *Webhook {
+	return &Webhook{
+		url:     url,
+		headers: headers,
+		client:  newHTTPClient(),
+	}
+}
+
+// Send posts the notification to the webhook URL
+func (w *Webhook) Send(ctx context.Context, n *model.Notification) error {
+	payload := WebhookPayload{
+		Key:       n.Key,
+		Rule:      n.Rule,
+		Kind:      n.Kind,
+		Subject:   n.Subject,
+		Body:      n.Body,
+		CreatedAt: n.CreatedAt,
+	}
+	if ev := n.Event; ev != nil {
+		payload.SourceID = ev.SourceID
+		payload.Tag = ev.Tag
+		payload.Total = ev.Total
+		for _, ioc := range ev.IoCs {
+			payload.IoCs = append(payload.IoCs, WebhookIoC{
+				ID:         ioc.ID,
+				Type:       string(ioc.Type),
+				Value:      ioc.Value,
+				SourceID:   ioc.SourceID,
+				Confidence: ioc.Confidence,
+			})
+		}
+		for _, m := range ev.BrandMatches {
+			payload.Lookalikes = append(payload.Lookalikes, WebhookLookalike{
+				IoCID:           m.IoCID,
+				Domain:          m.Domain,
+				ProtectedDomain: m.ProtectedDomain,
+				Technique:       string(m.Technique),
+				EditDistance:    m.EditDistance,
+				Score:           m.Score,
+				SourceID:        m.SourceID,
+			})
+		}
+	}
+
+	return postJSON(ctx, w.client, w.url, w.headers, payload)
+}
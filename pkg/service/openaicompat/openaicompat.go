@@ -0,0 +1,404 @@
+// Package openaicompat provides a gollem LLM client for self-hosted servers
+// implementing the OpenAI chat completions API, such as Ollama, vLLM and the
+// llama.cpp server. Servers without JSON schema support in response_format can
+// be used with prompt-enforced JSON.
+package openaicompat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/gollem"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/service/embedding"
+	"github.com/secmon-lab/beehive/pkg/utils/httpclient"
+)
+
+// DefaultTimeout is the default timeout of a request. Local models on modest
+// hardware can take minutes for a long article chunk.
+const DefaultTimeout = 5 * time.Minute
+
+var (
+	errNotSupported = goerr.New("not supported by the OpenAI-compatible client")
+)
+
+// Client is an LLM client of an OpenAI-compatible server
+type Client struct {
+	baseURL        string
+	model          string
+	embeddingModel string
+	apiKey         string
+	headers        map[string]string
+	jsonSchema     bool
+	httpClient     httpclient.HTTPClient
+}
+
+var _ gollem.LLMClient = &Client{}
+
+// Option configures Client
+type Option func(*Client)
+
+// WithAPIKey sets the bearer token sent to the server
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithHeaders sets additional request headers, e.g. for an authenticating proxy
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		c.headers = headers
+	}
+}
+
+// WithJSONSchema sets whether the response schema is sent in response_format
+// (default: true). When disabled, the schema is given in the system prompt
+// and the JSON document is taken from the response text.
+func WithJSONSchema(enabled bool) Option {
+	return func(c *Client) {
+		c.jsonSchema = enabled
+	}
+}
+
+// WithEmbeddingModel sets the model of GenerateEmbedding
+func WithEmbeddingModel(model string) Option {
+	return func(c *Client) {
+		c.embeddingModel = model
+	}
+}
+
+// WithTimeout sets the timeout of a request (default: DefaultTimeout)
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient = &http.Client{Timeout: timeout}
+	}
+}
+
+// WithHTTPClient sets the HTTP client
+func WithHTTPClient(client httpclient.HTTPClient) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// New creates a client of the server at baseURL, e.g.
+// "http://localhost:11434/v1", generating content with model
+func New(baseURL, model string, opts ...Option) (*Client, error) {
+	if baseURL == "" {
+		return nil, goerr.New("base URL is required")
+	}
+	if model == "" {
+		return nil, goerr.New("model is required")
+	}
+
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		model:      model,
+		jsonSchema: true,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// NewSession creates a chat session. Tools are not supported.
+func (c *Client) NewSession(ctx context.Context, options ...gollem.SessionOption) (gollem.Session, error) {
+	cfg := gollem.NewSessionConfig(options...)
+	if len(cfg.Tools()) > 0 {
+		return nil, goerr.Wrap(errNotSupported, "failed to create session with tools")
+	}
+
+	s := &session{client: c, jsonMode: cfg.ContentType() == gollem.ContentTypeJSON}
+
+	system := cfg.SystemPrompt()
+	if s.jsonMode && cfg.ResponseSchema() != nil {
+		schema, err := json.Marshal(jsonSchema(cfg.ResponseSchema()))
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to encode response schema")
+		}
+		if c.jsonSchema {
+			s.responseFormat = &responseFormat{
+				Type: "json_schema",
+				JSONSchema: &responseJSONSchema{
+					Name:   "response",
+					Schema: schema,
+				},
+			}
+		} else {
+			if system != "" {
+				system += "\n\n"
+			}
+			system += "Respond with only a JSON document conforming to the following JSON schema, without any other text:\n" + string(schema)
+		}
+	} else if s.jsonMode && c.jsonSchema {
+		s.responseFormat = &responseFormat{Type: "json_object"}
+	}
+
+	if system != "" {
+		s.messages = append(s.messages, chatMessage{Role: "system", Content: system})
+	}
+	return s, nil
+}
+
+// GenerateEmbedding generates embeddings with the /embeddings endpoint of the server
+func (c *Client) GenerateEmbedding(ctx context.Context, dimension int, input []string) ([][]float64, error) {
+	if c.embeddingModel == "" {
+		return nil, goerr.Wrap(errNotSupported, "embedding model is not configured")
+	}
+
+	opts := []embedding.OpenAICompatibleOption{embedding.WithHTTPClient(c)}
+	vectors, err := embedding.NewOpenAICompatible(c.baseURL, c.embeddingModel, dimension, opts...).Embed(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]float64, len(vectors))
+	for i, vec := range vectors {
+		result[i] = make([]float64, len(vec))
+		for j, v := range vec {
+			result[i][j] = float64(v)
+		}
+	}
+	return result, nil
+}
+
+// Do sends req with the API key and the configured headers. It lets the
+// embedding client share the authentication of the client.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	return c.httpClient.Do(req)
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type responseJSONSchema struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+type responseFormat struct {
+	Type       string              `json:"type"`
+	JSONSchema *responseJSONSchema `json:"json_schema,omitempty"`
+}
+
+type chatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []chatMessage   `json:"messages"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+// finishReasonLength is the finish reason of a response cut off at the output token limit
+const finishReasonLength = "length"
+
+type chatResponse struct {
+	Choices []struct {
+		Message      chatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// chat sends the messages to the chat completions endpoint
+func (c *Client) chat(ctx context.Context, req chatRequest) (*chatResponse, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to marshal chat request")
+	}
+
+	url := c.baseURL + "/chat/completions"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create chat request", goerr.V("url", url))
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.Do(httpReq)
+	if err != nil {
+		return nil, goerr.Wrap(err, "chat request failed", goerr.V("url", url))
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read chat response", goerr.V("url", url))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, goerr.New("chat request returned an error status",
+			goerr.V("url", url),
+			goerr.V("model", c.model),
+			goerr.V("status_code", resp.StatusCode),
+			goerr.V("body", string(body)))
+	}
+
+	var parsed chatResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, goerr.Wrap(err, "failed to parse chat response", goerr.V("url", url))
+	}
+	if len(parsed.Choices) == 0 {
+		return nil, goerr.New("chat response has no choices", goerr.V("url", url))
+	}
+	return &parsed, nil
+}
+
+type session struct {
+	client         *Client
+	messages       []chatMessage
+	responseFormat *responseFormat
+	jsonMode       bool
+}
+
+// GenerateContent sends the text inputs as a user message. The conversation
+// is kept in the session. A response cut off at the output token limit is
+// returned with interfaces.ErrLLMResponseTruncated.
+func (s *session) GenerateContent(ctx context.Context, input ...gollem.Input) (*gollem.Response, error) {
+	var prompt strings.Builder
+	for _, in := range input {
+		text, ok := in.(gollem.Text)
+		if !ok {
+			return nil, goerr.Wrap(errNotSupported, "only text input is supported")
+		}
+		prompt.WriteString(string(text))
+	}
+
+	messages := append(s.messages, chatMessage{Role: "user", Content: prompt.String()})
+	resp, err := s.client.chat(ctx, chatRequest{
+		Model:          s.client.model,
+		Messages:       messages,
+		ResponseFormat: s.responseFormat,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reply := resp.Choices[0].Message
+	s.messages = append(messages, chatMessage{Role: "assistant", Content: reply.Content})
+
+	text := reply.Content
+	if s.jsonMode {
+		text = extractJSON(text)
+	}
+	result := &gollem.Response{
+		Texts:       []string{text},
+		InputToken:  resp.Usage.PromptTokens,
+		OutputToken: resp.Usage.CompletionTokens,
+	}
+	if resp.Choices[0].FinishReason == finishReasonLength {
+		return result, goerr.Wrap(interfaces.ErrLLMResponseTruncated, "chat response hit the output token limit",
+			goerr.V("model", s.client.model),
+			goerr.V("completion_tokens", resp.Usage.CompletionTokens))
+	}
+	return result, nil
+}
+
+func (s *session) GenerateStream(ctx context.Context, input ...gollem.Input) (<-chan *gollem.Response, error) {
+	return nil, goerr.Wrap(errNotSupported, "failed to generate stream")
+}
+
+func (s *session) History() (*gollem.History, error) {
+	return &gollem.History{}, nil
+}
+
+func (s *session) AppendHistory(h *gollem.History) error {
+	return goerr.Wrap(errNotSupported, "failed to append history")
+}
+
+func (s *session) CountToken(ctx context.Context, input ...gollem.Input) (int, error) {
+	return 0, goerr.Wrap(errNotSupported, "failed to count tokens")
+}
+
+// extractJSON returns the JSON document of a response, removing a Markdown
+// code fence and text around the document that models often add without
+// schema-constrained decoding
+func extractJSON(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if strings.HasPrefix(trimmed, "```") {
+		trimmed = strings.TrimPrefix(trimmed, "```")
+		if nl := strings.IndexByte(trimmed, '\n'); nl >= 0 {
+			trimmed = trimmed[nl+1:]
+		}
+		trimmed = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(trimmed), "```"))
+	}
+
+	start := strings.IndexAny(trimmed, "{[")
+	if start < 0 {
+		return trimmed
+	}
+	end := strings.LastIndexAny(trimmed, "}]")
+	if end < start {
+		// Truncated document: keep it so that the caller can detect truncation
+		return trimmed[start:]
+	}
+	return trimmed[start : end+1]
+}
+
+// jsonSchema converts a gollem parameter to a JSON schema
+func jsonSchema(p *gollem.Parameter) map[string]any {
+	schema := map[string]any{"type": string(p.Type)}
+	if p.Title != "" {
+		schema["title"] = p.Title
+	}
+	if p.Description != "" {
+		schema["description"] = p.Description
+	}
+	if len(p.Enum) > 0 {
+		schema["enum"] = p.Enum
+	}
+	if len(p.Properties) > 0 {
+		props := make(map[string]any, len(p.Properties))
+		for name, prop := range p.Properties {
+			props[name] = jsonSchema(prop)
+		}
+		schema["properties"] = props
+	}
+	if len(p.Required) > 0 {
+		schema["required"] = p.Required
+	}
+	if p.Items != nil {
+		schema["items"] = jsonSchema(p.Items)
+	}
+	if p.Minimum != nil {
+		schema["minimum"] = *p.Minimum
+	}
+	if p.Maximum != nil {
+		schema["maximum"] = *p.Maximum
+	}
+	if p.MinLength != nil {
+		schema["minLength"] = *p.MinLength
+	}
+	if p.MaxLength != nil {
+		schema["maxLength"] = *p.MaxLength
+	}
+	if p.Pattern != "" {
+		schema["pattern"] = p.Pattern
+	}
+	if p.MinItems != nil {
+		schema["minItems"] = *p.MinItems
+	}
+	if p.MaxItems != nil {
+		schema["maxItems"] = *p.MaxItems
+	}
+	if p.Default != nil {
+		schema["default"] = p.Default
+	}
+	return schema
+}
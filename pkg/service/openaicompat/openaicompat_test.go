@@ -0,0 +1,283 @@
+package openaicompat_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gollem"
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/service/openaicompat"
+)
+
+type chatRequest struct {
+	Model    string `json:"model"`
+	Messages []struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	} `json:"messages"`
+	ResponseFormat *struct {
+		Type       string `json:"type"`
+		JSONSchema *struct {
+			Name   string         `json:"name"`
+			Schema map[string]any `json:"schema"`
+		} `json:"json_schema"`
+	} `json:"response_format"`
+}
+
+// chatServer is a stand-in for an OpenAI-compatible server answering every
+// chat completion with reply
+type chatServer struct {
+	*httptest.Server
+	requests []chatRequest
+	headers  []http.Header
+}
+
+func newChatServer(t *testing.T, reply string) *chatServer {
+	s := &chatServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gt.Equal(t, r.URL.Path, "/v1/chat/completions")
+		gt.Equal(t, r.Method, http.MethodPost)
+
+		var req chatRequest
+		gt.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		s.requests = append(s.requests, req)
+		s.headers = append(s.headers, r.Header.Clone())
+
+		w.Header().Set("Content-Type", "application/json")
+		gt.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]any{"role": "assistant", "content": reply}, "finish_reason": "stop"},
+			},
+			"usage": map[string]any{"prompt_tokens": 120, "completion_tokens": 30},
+		}))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+const extractionReply = `{"iocs": [{"type": "domain", "value": "evil.example", "description": "C2 server"}]}`
+
+func TestClient_JSONSchema(t *testing.T) {
+	ctx := context.Background()
+	server := newChatServer(t, extractionReply)
+
+	client, err := openaicompat.New(server.URL+"/v1/", "qwen2.5:14b",
+		openaicompat.WithAPIKey("secret"),
+		openaicompat.WithHeaders(map[string]string{"X-Tenant": "soc"}),
+	)
+	gt.NoError(t, err)
+
+	result, err := extractor.New(client).Extract(ctx, model.ExtractionModeLLM, "Campaign", "C2 at evil[.]example")
+	gt.NoError(t, err)
+	gt.A(t, result.IoCs).Length(1).At(0, func(t testing.TB, ioc *extractor.ExtractedIoC) {
+		gt.Equal(t, ioc.Value, "evil.example")
+		gt.Equal(t, ioc.Type, "domain")
+	})
+
+	gt.A(t, server.requests).Length(1)
+	req := server.requests[0]
+	gt.Equal(t, req.Model, "qwen2.5:14b")
+	gt.A(t, req.Messages).Length(1)
+	gt.Equal(t, req.Messages[0].Role, "user")
+	gt.S(t, req.Messages[0].Content).Contains("evil[.]example")
+
+	gt.NotNil(t, req.ResponseFormat)
+	gt.Equal(t, req.ResponseFormat.Type, "json_schema")
+	gt.NotNil(t, req.ResponseFormat.JSONSchema)
+	gt.Equal(t, req.ResponseFormat.JSONSchema.Schema["type"], any("object"))
+	gt.NotNil(t, req.ResponseFormat.JSONSchema.Schema["properties"].(map[string]any)["iocs"])
+
+	gt.Equal(t, server.headers[0].Get("Authorization"), "Bearer secret")
+	gt.Equal(t, server.headers[0].Get("X-Tenant"), "soc")
+}
+
+func TestClient_PromptEnforcedJSON(t *testing.T) {
+	ctx := context.Background()
+	server := newChatServer(t, "Here are the IoCs:\n```json\n"+extractionReply+"\n```\n")
+
+	client, err := openaicompat.New(server.URL+"/v1", "llama3.1:8b", openaicompat.WithJSONSchema(false))
+	gt.NoError(t, err)
+
+	result, err := extractor.New(client).Extract(ctx, model.ExtractionModeLLM, "Campaign", "C2 at evil[.]example")
+	gt.NoError(t, err)
+	gt.A(t, result.IoCs).Length(1)
+
+	req := server.requests[0]
+	gt.Nil(t, req.ResponseFormat)
+	gt.A(t, req.Messages).Length(2)
+	gt.Equal(t, req.Messages[0].Role, "system")
+	gt.S(t, req.Messages[0].Content).Contains("JSON schema")
+	gt.S(t, req.Messages[0].Content).Contains(`"iocs"`)
+	gt.Equal(t, server.headers[0].Get("Authorization"), "")
+}
+
+func TestClient_Session(t *testing.T) {
+	ctx := context.Background()
+	server := newChatServer(t, "pong")
+
+	client, err := openaicompat.New(server.URL+"/v1", "m")
+	gt.NoError(t, err)
+
+	session, err := client.NewSession(ctx, gollem.WithSessionSystemPrompt("be brief"))
+	gt.NoError(t, err)
+
+	resp, err := session.GenerateContent(ctx, gollem.Text("ping"))
+	gt.NoError(t, err)
+	gt.Equal(t, resp.Texts, []string{"pong"})
+	gt.Equal(t, resp.InputToken, 120)
+	gt.Equal(t, resp.OutputToken, 30)
+	gt.Nil(t, server.requests[0].ResponseFormat)
+
+	// The conversation is kept in the session
+	_, err = session.GenerateContent(ctx, gollem.Text("again"))
+	gt.NoError(t, err)
+	roles := []string{}
+	for _, m := range server.requests[1].Messages {
+		roles = append(roles, m.Role)
+	}
+	gt.Equal(t, roles, []string{"system", "user", "assistant", "user"})
+}
+
+func TestClient_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("missing settings", func(t *testing.T) {
+		_, err := openaicompat.New("", "m")
+		gt.Error(t, err)
+		_, err = openaicompat.New("http://localhost:11434/v1", "")
+		gt.Error(t, err)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"error": "model not found"}`, http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		client, err := openaicompat.New(server.URL, "m")
+		gt.NoError(t, err)
+		session, err := client.NewSession(ctx)
+		gt.NoError(t, err)
+		_, err = session.GenerateContent(ctx, gollem.Text("ping"))
+		gt.Error(t, err)
+		gt.S(t, err.Error()).Contains("error status")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(300 * time.Millisecond):
+			}
+		}))
+		defer server.Close()
+
+		client, err := openaicompat.New(server.URL, "m", openaicompat.WithTimeout(50*time.Millisecond))
+		gt.NoError(t, err)
+		session, err := client.NewSession(ctx)
+		gt.NoError(t, err)
+		_, err = session.GenerateContent(ctx, gollem.Text("ping"))
+		gt.Error(t, err)
+	})
+
+	t.Run("embedding model not configured", func(t *testing.T) {
+		client, err := openaicompat.New("http://localhost:11434/v1", "m")
+		gt.NoError(t, err)
+		_, err = client.GenerateEmbedding(ctx, 3, []string{"x"})
+		gt.Error(t, err)
+	})
+}
+
+func TestClient_GenerateEmbedding(t *testing.T) {
+	ctx := context.Background()
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gt.Equal(t, r.URL.Path, "/v1/embeddings")
+		header = r.Header.Clone()
+		var req struct {
+			Input []string `json:"input"`
+		}
+		gt.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		data := make([]map[string]any, len(req.Input))
+		for i := range req.Input {
+			data[i] = map[string]any{"index": i, "embedding": []float64{float64(i), 1}}
+		}
+		gt.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": data}))
+	}))
+	defer server.Close()
+
+	client, err := openaicompat.New(server.URL+"/v1", "m",
+		openaicompat.WithEmbeddingModel("nomic-embed-text"),
+		openaicompat.WithHeaders(map[string]string{"X-Tenant": "soc"}),
+	)
+	gt.NoError(t, err)
+
+	vectors, err := client.GenerateEmbedding(ctx, 2, []string{"a", "b"})
+	gt.NoError(t, err)
+	gt.Equal(t, vectors, [][]float64{{0, 1}, {1, 1}})
+	gt.Equal(t, header.Get("X-Tenant"), "soc")
+	gt.True(t, strings.HasPrefix(header.Get("Content-Type"), "application/json"))
+}
+
+func TestClient_TruncatedResponse(t *testing.T) {
+	ctx := context.Background()
+
+	// Prompts with a long article hit the output limit; shorter ones do not
+	var prompts []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		gt.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		size := len(req.Messages[len(req.Messages)-1].Content)
+		prompts = append(prompts, size)
+
+		finishReason := "stop"
+		if size > 20000 {
+			finishReason = "length"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		gt.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]any{"role": "assistant", "content": `{"iocs": []}`}, "finish_reason": finishReason},
+			},
+		}))
+	}))
+	defer server.Close()
+
+	client, err := openaicompat.New(server.URL, "m")
+	gt.NoError(t, err)
+
+	t.Run("session returns the truncation error", func(t *testing.T) {
+		session, err := client.NewSession(ctx)
+		gt.NoError(t, err)
+		resp, err := session.GenerateContent(ctx, gollem.Text(strings.Repeat("x", 21000)))
+		gt.True(t, errors.Is(err, interfaces.ErrLLMResponseTruncated))
+		gt.A(t, resp.Texts).Length(1)
+	})
+
+	t.Run("extractor splits the truncated chunk", func(t *testing.T) {
+		prompts = nil
+		var article strings.Builder
+		for i := range 200 {
+			fmt.Fprintf(&article, "| %d | %064x |\n", i, i)
+		}
+
+		ext := extractor.New(client, extractor.WithChunking(8000, 100), extractor.WithParallelism(1))
+		_, err := ext.ExtractFromArticle(ctx, "title", article.String())
+		gt.NoError(t, err)
+		gt.N(t, len(prompts)).Greater(1)
+		gt.N(t, prompts[0]).Greater(20000)
+		for _, size := range prompts[1:] {
+			gt.N(t, size).LessOrEqual(20000)
+		}
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"github.com/m-mizutani/goerr/v2"
 	"github.com/mmcdole/gofeed"
+	"github.com/secmon-lab/beehive/pkg/utils/tracing"
 )
 
 var (
@@ -36,7 +37,8 @@ type Service struct {
 func New() *Service {
 	return &Service{
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.Transport(http.DefaultTransport),
 		},
 		parser: gofeed.NewParser(),
 	}
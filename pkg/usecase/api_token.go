@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+)
+
+var (
+	// ErrAPITokenExists is returned when an active API token has the same name
+	ErrAPITokenExists = goerr.New("API token already exists")
+
+	// ErrAPITokensDisabled is returned when minting a token on a server
+	// without authentication, which would not check it
+	ErrAPITokensDisabled = goerr.New("API tokens require authentication to be configured")
+)
+
+// CreateAPITokenInput represents a request to mint an API token
+type CreateAPITokenInput struct {
+	Name      string
+	Scopes    []string  // See model.ParseTokenScope
+	ExpiresAt time.Time // Zero = never expires
+}
+
+// CreateAPIToken mints an API token. The returned token is not stored and
+// cannot be retrieved again.
+func (uc *UseCases) CreateAPIToken(ctx context.Context, input *CreateAPITokenInput) (*model.APIToken, string, error) {
+	if uc.openAccess {
+		return nil, "", goerr.Wrap(ErrAPITokensDisabled, "failed to create API token", goerr.V("name", input.Name))
+	}
+	if input.Name == "" {
+		return nil, "", goerr.New("API token name is required")
+	}
+	if len(input.Scopes) == 0 {
+		return nil, "", goerr.New("API token needs at least one scope", goerr.V("name", input.Name))
+	}
+	scopes := make([]model.TokenScope, 0, len(input.Scopes))
+	for _, s := range input.Scopes {
+		scope, err := model.ParseTokenScope(s)
+		if err != nil {
+			return nil, "", goerr.Wrap(err, "invalid API token scope", goerr.V("name", input.Name))
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	now := time.Now()
+	if !input.ExpiresAt.IsZero() && !input.ExpiresAt.After(now) {
+		return nil, "", goerr.New("API token expiry must be in the future",
+			goerr.V("name", input.Name),
+			goerr.V("expires_at", input.ExpiresAt))
+	}
+
+	tokens, err := uc.repo.ListAPITokens(ctx)
+	if err != nil {
+		return nil, "", goerr.Wrap(err, "failed to list API tokens")
+	}
+	for _, t := range tokens {
+		if t.Name == input.Name && t.Active(now) {
+			return nil, "", goerr.Wrap(ErrAPITokenExists, "failed to create API token", goerr.V("name", input.Name))
+		}
+	}
+
+	token, secret, err := model.NewAPIToken(input.Name, scopes, model.ActorFrom(ctx), input.ExpiresAt, now)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := uc.repo.PutAPIToken(ctx, token); err != nil {
+		return nil, "", goerr.Wrap(err, "failed to save API token", goerr.V("name", input.Name))
+	}
+
+	uc.audit.Record(ctx, token.CreatedBy, model.AuditAPITokenCreate, model.AuditTargetAPIToken, token.ID, nil, auditAPIToken(token))
+	logging.From(ctx).Info("API token created",
+		"id", token.ID,
+		"name", token.Name,
+		"scopes", token.Scopes,
+		"expires_at", token.ExpiresAt,
+		"actor", token.CreatedBy)
+	return token, secret, nil
+}
+
+// ListAPITokens returns all API tokens, newest first
+func (uc *UseCases) ListAPITokens(ctx context.Context) ([]*model.APIToken, error) {
+	tokens, err := uc.repo.ListAPITokens(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list API tokens")
+	}
+	return tokens, nil
+}
+
+// RevokeAPIToken revokes an API token. Revoked tokens are kept for the record;
+// revoking one again does nothing.
+func (uc *UseCases) RevokeAPIToken(ctx context.Context, id string) (*model.APIToken, error) {
+	token, err := uc.repo.GetAPIToken(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get API token", goerr.V("id", id))
+	}
+	if token.Revoked() {
+		return token, nil
+	}
+
+	before := auditAPIToken(token)
+	token.RevokedAt = time.Now()
+	if err := uc.repo.PutAPIToken(ctx, token); err != nil {
+		return nil, goerr.Wrap(err, "failed to revoke API token", goerr.V("id", id))
+	}
+	uc.audit.Record(ctx, model.ActorFrom(ctx), model.AuditAPITokenRevoke, model.AuditTargetAPIToken, id, before, auditAPIToken(token))
+
+	logging.From(ctx).Info("API token revoked",
+		"id", token.ID,
+		"name", token.Name,
+		"actor", model.ActorFrom(ctx))
+	return token, nil
+}
+
+// ExportIoCs returns the active IoCs with a tag, sorted by type and value. As
+// in digests, a tag matches IoCs from sources with the tag and IoCs tagged by
+// analysts. A value reported by several sources is returned once, by the IoC
+// with the highest confidence. types restricts the IoC types if not empty and
+// IoCs with a confidence below minConfidence are omitted.
+func (uc *UseCases) ExportIoCs(ctx context.Context, tag string, types []model.IoCType, minConfidence int) ([]*model.IoC, error) {
+	sources, err := uc.SourcesMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	iocs, err := uc.repo.ListIoCsByTag(ctx, &model.IoCTagFilter{
+		Tag:           tag,
+		SourceIDs:     model.SourceIDsWithTag(sources, tag),
+		Status:        model.IoCStatusActive,
+		MinConfidence: minConfidence,
+	})
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list IoCs by tag", goerr.V("tag", tag))
+	}
+
+	type key struct {
+		typ   model.IoCType
+		value string
+	}
+	matched := make(map[key]*model.IoC)
+	for _, ioc := range iocs {
+		if len(types) > 0 && !slices.Contains(types, ioc.Type) {
+			continue
+		}
+		k := key{typ: ioc.Type, value: ioc.Value}
+		if prev, ok := matched[k]; !ok || ioc.Confidence > prev.Confidence {
+			matched[k] = ioc
+		}
+	}
+
+	result := make([]*model.IoC, 0, len(matched))
+	for _, ioc := range matched {
+		result = append(result, ioc)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Type != result[j].Type {
+			return result[i].Type < result[j].Type
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result, nil
+}
@@ -0,0 +1,152 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+func TestAPIToken_Lifecycle(t *testing.T) {
+	repo := memory.New()
+	uc := usecase.New(repo)
+	ctx := model.WithPrincipal(context.Background(),
+		&model.Principal{Subject: "root", Role: model.RoleAdmin, Method: model.AuthMethodJWT})
+
+	expiresAt := time.Now().Add(90 * 24 * time.Hour)
+	token, secret, err := uc.CreateAPIToken(ctx, &usecase.CreateAPITokenInput{
+		Name:      "firewall",
+		Scopes:    []string{"export:blocklist", "read:iocs", "export:blocklist"},
+		ExpiresAt: expiresAt,
+	})
+	gt.NoError(t, err)
+	gt.Equal(t, token.Scopes, []model.TokenScope{model.ExportScope("blocklist"), model.ScopeReadIoCs})
+	gt.Equal(t, token.CreatedBy, "user:root")
+	gt.True(t, token.ExpiresAt.Equal(expiresAt))
+
+	// Only the hash is stored
+	stored, err := repo.GetAPIToken(ctx, token.ID)
+	gt.NoError(t, err)
+	gt.Equal(t, stored.Hash, model.HashAPIToken(secret))
+
+	t.Run("names of active tokens are unique", func(t *testing.T) {
+		_, _, err := uc.CreateAPIToken(ctx, &usecase.CreateAPITokenInput{Name: "firewall", Scopes: []string{"read:iocs"}})
+		gt.True(t, errors.Is(err, usecase.ErrAPITokenExists))
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, input := range []*usecase.CreateAPITokenInput{
+			{Scopes: []string{"read:iocs"}},
+			{Name: "no-scope"},
+			{Name: "bad-scope", Scopes: []string{"write:iocs"}},
+			{Name: "expired", Scopes: []string{"read:iocs"}, ExpiresAt: time.Now().Add(-time.Hour)},
+		} {
+			_, _, err := uc.CreateAPIToken(ctx, input)
+			gt.Error(t, err).Describef("input %+v should be rejected", input)
+		}
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		revoked, err := uc.RevokeAPIToken(ctx, token.ID)
+		gt.NoError(t, err)
+		gt.True(t, revoked.Revoked())
+
+		again, err := uc.RevokeAPIToken(ctx, token.ID)
+		gt.NoError(t, err)
+		gt.True(t, again.RevokedAt.Equal(revoked.RevokedAt))
+
+		_, err = uc.RevokeAPIToken(ctx, "missing")
+		gt.True(t, errors.Is(err, interfaces.ErrAPITokenNotFound))
+
+		// The name can be reused once the token is revoked
+		_, _, err = uc.CreateAPIToken(ctx, &usecase.CreateAPITokenInput{Name: "firewall", Scopes: []string{"read:iocs"}})
+		gt.NoError(t, err)
+
+		tokens, err := uc.ListAPITokens(ctx)
+		gt.NoError(t, err)
+		gt.A(t, tokens).Length(2)
+		gt.False(t, tokens[0].Revoked())
+		gt.True(t, tokens[1].Revoked())
+	})
+}
+
+func TestAPIToken_OpenAccess(t *testing.T) {
+	repo := memory.New()
+	uc := usecase.New(repo, usecase.WithOpenAccess())
+
+	_, _, err := uc.CreateAPIToken(context.Background(), &usecase.CreateAPITokenInput{
+		Name:   "firewall",
+		Scopes: []string{"export:blocklist"},
+	})
+	gt.True(t, errors.Is(err, usecase.ErrAPITokensDisabled))
+
+	tokens, err := repo.ListAPITokens(context.Background())
+	gt.NoError(t, err)
+	gt.A(t, tokens).Length(0)
+}
+
+func TestExportIoCs(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	uc := usecase.New(repo)
+
+	tagged := newRSSSource("tagged", "https://tagged.example/feed")
+	tagged.Tags = []string{"blocklist"}
+	other := newRSSSource("other", "https://other.example/feed")
+	other.Tags = nil
+	gt.NoError(t, repo.PutSource(ctx, tagged))
+	gt.NoError(t, repo.PutSource(ctx, other))
+
+	newIoC := func(sourceID string, iocType model.IoCType, value string, confidence int) *model.IoC {
+		return &model.IoC{
+			ID:         model.GenerateID(sourceID, iocType, value, ""),
+			SourceID:   sourceID,
+			Type:       iocType,
+			Value:      value,
+			Status:     model.IoCStatusActive,
+			Confidence: confidence,
+		}
+	}
+	inactive := newIoC("tagged", model.IoCTypeDomain, "old.example", 50)
+	inactive.Status = model.IoCStatusInactive
+	curated := newIoC("other", model.IoCTypeIPv4, "192.0.2.1", 40)
+	curated.Tags = []string{"blocklist"}
+	for _, ioc := range []*model.IoC{
+		newIoC("tagged", model.IoCTypeDomain, "evil.example", 50),
+		newIoC("tagged", model.IoCTypeIPv4, "192.0.2.1", 80),
+		newIoC("other", model.IoCTypeDomain, "benign.example", 50),
+		inactive,
+		curated,
+	} {
+		gt.NoError(t, repo.PutIoC(ctx, ioc))
+	}
+
+	iocs, err := uc.ExportIoCs(ctx, "blocklist", nil, 0)
+	gt.NoError(t, err)
+	gt.A(t, iocs).Length(2)
+	gt.Equal(t, iocs[0].Value, "evil.example")
+	gt.Equal(t, iocs[1].Value, "192.0.2.1")
+	// A value of several sources is exported once, with the highest confidence
+	gt.Equal(t, iocs[1].Confidence, 80)
+
+	iocs, err = uc.ExportIoCs(ctx, "blocklist", []model.IoCType{model.IoCTypeIPv4}, 0)
+	gt.NoError(t, err)
+	gt.A(t, iocs).Length(1)
+	gt.Equal(t, iocs[0].Value, "192.0.2.1")
+
+	iocs, err = uc.ExportIoCs(ctx, "blocklist", nil, 60)
+	gt.NoError(t, err)
+	gt.A(t, iocs).Length(1)
+	gt.Equal(t, iocs[0].Value, "192.0.2.1")
+	gt.Equal(t, iocs[0].Confidence, 80)
+
+	iocs, err = uc.ExportIoCs(ctx, "unknown", nil, 0)
+	gt.NoError(t, err)
+	gt.A(t, iocs).Length(0)
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/errutil"
+)
+
+// Auditor appends records of state-changing operations to the audit log in
+// the repository and an optional mirror
+type Auditor struct {
+	repo   interfaces.AuditRepository
+	mirror interfaces.AuditMirror
+}
+
+// AuditorOption configures Auditor
+type AuditorOption func(*Auditor)
+
+// WithAuditMirror sets a mirror receiving a copy of every record
+func WithAuditMirror(mirror interfaces.AuditMirror) AuditorOption {
+	return func(a *Auditor) {
+		a.mirror = mirror
+	}
+}
+
+// NewAuditor creates an auditor writing to repo
+func NewAuditor(repo interfaces.AuditRepository, opts ...AuditorOption) *Auditor {
+	a := &Auditor{repo: repo}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Record appends a record of an operation on a target with the diff of the
+// target before and after it. The operation has already been made, so
+// failures are logged and not returned.
+func (a *Auditor) Record(ctx context.Context, actor string, action model.AuditAction, targetType, targetID string, before, after any) {
+	if a == nil {
+		return
+	}
+
+	record, err := model.NewAuditRecord(ctx, actor, action, targetType, targetID, before, after)
+	if err != nil {
+		errutil.Handle(ctx, err, "failed to create audit record")
+		return
+	}
+	if err := a.repo.SaveAuditRecord(ctx, record); err != nil {
+		errutil.Handle(ctx, err, "failed to save audit record")
+	}
+	if a.mirror != nil {
+		if err := a.mirror.WriteAuditRecord(ctx, record); err != nil {
+			errutil.Handle(ctx, err, "failed to mirror audit record")
+		}
+	}
+}
+
+// List returns the audit records matching filter, newest first
+func (a *Auditor) List(ctx context.Context, filter *model.AuditFilter) ([]*model.AuditRecord, error) {
+	records, err := a.repo.ListAuditRecords(ctx, filter)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list audit records")
+	}
+	return records, nil
+}
+
+// ListAuditRecords returns the audit records matching filter, newest first
+func (uc *UseCases) ListAuditRecords(ctx context.Context, filter *model.AuditFilter) ([]*model.AuditRecord, error) {
+	return uc.audit.List(ctx, filter)
+}
+
+// auditIoC returns a copy of an IoC for audit records without its embedding
+func auditIoC(ioc *model.IoC) *model.IoC {
+	if ioc == nil {
+		return nil
+	}
+	c := *ioc
+	c.Embedding = nil
+	return &c
+}
+
+// auditAPIToken returns a copy of an API token for audit records without its hash
+func auditAPIToken(token *model.APIToken) *model.APIToken {
+	c := *token
+	c.Hash = ""
+	return &c
+}
+
+// fetchRunSummary is the audit target of a completed fetch
+type fetchRunSummary struct {
+	HistoryID     string
+	Status        model.FetchStatus
+	ItemsFetched  int
+	IoCsExtracted int
+	IoCsCreated   int
+	IoCsUpdated   int
+	IoCsUnchanged int
+	ErrorCount    int
+}
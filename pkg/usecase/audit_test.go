@@ -0,0 +1,133 @@
+package usecase_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+type recordingMirror struct {
+	mu      sync.Mutex
+	records []*model.AuditRecord
+}
+
+func (m *recordingMirror) WriteAuditRecord(ctx context.Context, record *model.AuditRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, record)
+	return nil
+}
+
+func TestAudit_IoC(t *testing.T) {
+	ctx := model.WithPrincipal(context.Background(), &model.Principal{Subject: "alice", Role: model.RoleAnalyst, Method: model.AuthMethodJWT})
+	ctx = model.WithRequestID(ctx, "host/req-000001")
+	repo := memory.New()
+	mirror := &recordingMirror{}
+	uc := usecase.New(repo, usecase.WithAuditor(usecase.NewAuditor(repo, usecase.WithAuditMirror(mirror))))
+
+	created, err := uc.CreateIoC(ctx, &usecase.CreateIoCInput{Type: model.IoCTypeIPv4, Value: "198.51.100.10"})
+	gt.NoError(t, err)
+	status := model.IoCStatusFalsePositive
+	_, err = uc.UpdateIoC(ctx, created.ID, &usecase.UpdateIoCInput{Status: &status})
+	gt.NoError(t, err)
+	gt.NoError(t, uc.DeleteIoC(ctx, created.ID))
+
+	records, err := uc.ListAuditRecords(ctx, &model.AuditFilter{TargetID: created.ID})
+	gt.NoError(t, err)
+	gt.A(t, records).Length(3)
+	gt.Equal(t, records[0].Action, model.AuditIoCDelete)
+	gt.Equal(t, records[2].Action, model.AuditIoCCreate)
+
+	update := records[1]
+	gt.Equal(t, update.Action, model.AuditIoCUpdate)
+	gt.Equal(t, update.Actor, "user:alice")
+	gt.Equal(t, update.TargetType, model.AuditTargetIoC)
+	gt.Equal(t, update.RequestID, "host/req-000001")
+	var statusChange *model.AuditChange
+	for i, c := range update.Changes {
+		gt.NotEqual(t, c.Field, "Embedding")
+		if c.Field == "Status" {
+			statusChange = &update.Changes[i]
+		}
+	}
+	gt.V(t, statusChange).NotNil()
+	gt.Equal(t, statusChange.Before, `"active"`)
+	gt.Equal(t, statusChange.After, `"false_positive"`)
+
+	// Every record is mirrored
+	gt.A(t, mirror.records).Length(3)
+	gt.Equal(t, mirror.records[1].ID, update.ID)
+}
+
+func TestAudit_Source(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	uc := usecase.New(repo)
+
+	_, err := uc.CreateSource(ctx, newRSSSource("vendor-blog", "https://blog.example/feed"), "alice")
+	gt.NoError(t, err)
+	_, err = uc.SetSourceEnabled(ctx, "vendor-blog", false, "bob")
+	gt.NoError(t, err)
+
+	records, err := uc.ListAuditRecords(ctx, &model.AuditFilter{TargetType: model.AuditTargetSource})
+	gt.NoError(t, err)
+	gt.A(t, records).Length(2)
+	gt.Equal(t, records[0].Action, model.AuditSourceAction(model.SourceChangeDisable))
+	gt.Equal(t, records[0].Actor, "bob")
+	gt.Equal(t, records[0].TargetID, "vendor-blog")
+	gt.Equal(t, records[0].RequestID, "")
+	gt.Equal(t, records[1].Action, model.AuditSourceAction(model.SourceChangeCreate))
+	gt.Equal(t, records[1].Actor, "alice")
+}
+
+func TestAudit_FetchRun(t *testing.T) {
+	sources := map[string]model.Source{
+		"bad-feed": {
+			Type:    model.SourceTypeFeed,
+			URL:     "http://example.com/feed",
+			Enabled: true,
+		},
+	}
+
+	t.Run("scheduled fetch", func(t *testing.T) {
+		ctx := context.Background()
+		repo := memory.New()
+		uc := usecase.NewFetchUseCase(repo, nil)
+
+		histories, err := uc.FetchAllSources(ctx, sources, nil)
+		gt.NoError(t, err)
+		gt.A(t, histories).Length(1)
+
+		records, err := repo.ListAuditRecords(ctx, &model.AuditFilter{Action: model.AuditFetchRun})
+		gt.NoError(t, err)
+		gt.A(t, records).Length(1)
+		gt.Equal(t, records[0].Actor, model.ActorFetcher)
+		gt.Equal(t, records[0].TargetType, model.AuditTargetSource)
+		gt.Equal(t, records[0].TargetID, "bad-feed")
+		changes := map[string]string{}
+		for _, c := range records[0].Changes {
+			changes[c.Field] = c.After
+		}
+		gt.Equal(t, changes["HistoryID"], `"`+histories[0].ID+`"`)
+		gt.Equal(t, changes["Status"], `"`+string(histories[0].Status)+`"`)
+	})
+
+	t.Run("requested fetch", func(t *testing.T) {
+		ctx := model.WithPrincipal(context.Background(), &model.Principal{Subject: "alice", Role: model.RoleAnalyst, Method: model.AuthMethodJWT})
+		repo := memory.New()
+		uc := usecase.NewFetchUseCase(repo, nil)
+
+		_, err := uc.FetchAllSources(ctx, sources, nil)
+		gt.NoError(t, err)
+
+		records, err := repo.ListAuditRecords(ctx, &model.AuditFilter{Action: model.AuditFetchRun})
+		gt.NoError(t, err)
+		gt.A(t, records).Length(1)
+		gt.Equal(t, records[0].Actor, "user:alice")
+	})
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+)
+
+// ProtectedDomains returns the domains checked for lookalikes, if brand detection is enabled
+func (uc *FetchUseCase) ProtectedDomains() []string {
+	if uc.brand == nil {
+		return nil
+	}
+	return uc.brand.Domains()
+}
+
+// detectLookalikes flags newly ingested domain and URL IoCs imitating protected
+// domains and stores the matches. Failures are logged and yield no matches.
+func (uc *FetchUseCase) detectLookalikes(ctx context.Context, sourceID string, iocs []*model.IoC) []*model.BrandMatch {
+	if uc.brand == nil || uc.brand.Empty() || len(iocs) == 0 {
+		return nil
+	}
+	logger := logging.From(ctx)
+
+	now := time.Now()
+	var matches []*model.BrandMatch
+	for _, ioc := range iocs {
+		matches = append(matches, uc.brand.Detect(ioc, now)...)
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	stored, err := uc.repo.SaveBrandMatches(ctx, matches)
+	if err != nil {
+		logger.Error("failed to save brand matches",
+			"source_id", sourceID,
+			"matches", len(matches),
+			"error", err)
+	}
+
+	logger.Info("lookalike domains found",
+		"source_id", sourceID,
+		"matches", len(stored))
+	return stored
+}
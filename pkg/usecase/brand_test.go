@@ -0,0 +1,87 @@
+package usecase_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/brand"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/service/notify"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+const threatFoxLookalikeCSV = `# ThreatFox IOCs
+"2025-12-24 07:24:23", "1685591", "examlpe.com", "domain", "payload_delivery", "js.fakeupdates", "", "FakeUpdates", "", "90", "None", "phishing", "0", "reporter"
+"2025-12-24 07:25:23", "1685592", "login.example.com", "domain", "payload_delivery", "js.fakeupdates", "", "FakeUpdates", "", "90", "None", "phishing", "0", "reporter"
+"2025-12-24 07:26:23", "1685593", "cdn.unrelated-host.org", "domain", "payload_delivery", "js.fakeupdates", "", "FakeUpdates", "", "90", "None", "phishing", "0", "reporter"
+`
+
+func TestFetchUseCase_LookalikeDetection(t *testing.T) {
+	ctx := context.Background()
+
+	feedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(threatFoxLookalikeCSV))
+	}))
+	defer feedServer.Close()
+
+	detector, err := brand.New([]string{"example.com"})
+	gt.NoError(t, err)
+
+	repo := memory.New()
+	recv := newReceiver(t)
+	rule := &model.NotificationRule{
+		Name:       "brand",
+		Kind:       model.NotificationRuleLookalike,
+		Channels:   []string{"hook"},
+		Techniques: []model.LookalikeTechnique{model.LookalikeTyposquat},
+	}
+	gt.NoError(t, rule.Validate())
+	fetchUC := usecase.NewFetchUseCase(repo, nil,
+		usecase.WithBrandDetector(detector),
+		usecase.WithNotifier(newNotifier(repo, recv, rule)))
+	gt.A(t, fetchUC.ProtectedDomains()).Length(1)
+
+	sources := map[string]model.Source{
+		"threatfox": {
+			Type:       model.SourceTypeFeed,
+			URL:        feedServer.URL,
+			Enabled:    true,
+			FeedConfig: &model.FeedConfig{Schema: "abuse_ch_threatfox"},
+		},
+	}
+
+	history, err := fetchUC.FetchSourceByID(ctx, sources, "threatfox")
+	gt.NoError(t, err)
+	gt.Equal(t, history.IoCsCreated, 3)
+	// login.example.com is under the protected domain and cdn.unrelated-host.org does not resemble it
+	gt.Equal(t, history.BrandMatches, 1)
+
+	conn, err := repo.ListBrandMatches(ctx, &model.BrandMatchListOptions{ProtectedDomain: "example.com"})
+	gt.NoError(t, err)
+	gt.A(t, conn.Items).Length(1).At(0, func(t testing.TB, m *model.BrandMatch) {
+		gt.Equal(t, m.Domain, "examlpe.com")
+		gt.Equal(t, m.Technique, model.LookalikeTyposquat)
+		gt.Equal(t, m.EditDistance, 1)
+		gt.Equal(t, m.SourceID, "threatfox")
+	})
+
+	gt.A(t, recv.received()).Length(1).At(0, func(t testing.TB, p notify.WebhookPayload) {
+		gt.Equal(t, p.Kind, model.NotificationRuleLookalike)
+		gt.Equal(t, p.Subject, "[beehive] Lookalike domains brand: 1 new match(es)")
+		gt.A(t, p.Lookalikes).Length(1).At(0, func(t testing.TB, l notify.WebhookLookalike) {
+			gt.Equal(t, l.Domain, "examlpe.com")
+			gt.Equal(t, l.ProtectedDomain, "example.com")
+			gt.Equal(t, l.Technique, "typosquat")
+		})
+	})
+
+	// Re-observed IoCs are not new and produce no further matches
+	history, err = fetchUC.FetchSourceByID(ctx, sources, "threatfox")
+	gt.NoError(t, err)
+	gt.Equal(t, history.BrandMatches, 0)
+	gt.A(t, recv.received()).Length(1)
+}
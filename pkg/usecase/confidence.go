@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+)
+
+// scoreIoCs sets the confidence score of each IoC in place. Corroboration is
+// counted from stored IoCs with the same type and value; the IoC's own source
+// always counts even if the IoC is not stored yet.
+func scoreIoCs(ctx context.Context, repo interfaces.IoCRepository, policy *model.ConfidencePolicy, iocs []*model.IoC, now time.Time) error {
+	valuesByType := make(map[model.IoCType][]string)
+	for _, ioc := range iocs {
+		valuesByType[ioc.Type] = append(valuesByType[ioc.Type], ioc.Value)
+	}
+
+	sourcesByType := make(map[model.IoCType]map[string][]string, len(valuesByType))
+	for iocType, values := range valuesByType {
+		sources, err := repo.ListSourceIDsByValues(ctx, iocType, values)
+		if err != nil {
+			return goerr.Wrap(err, "failed to list sources by value",
+				goerr.V("type", iocType),
+				goerr.V("values", len(values)))
+		}
+		sourcesByType[iocType] = sources
+	}
+
+	for _, ioc := range iocs {
+		count := 1
+		for _, sourceID := range sourcesByType[ioc.Type][ioc.Value] {
+			if sourceID != ioc.SourceID {
+				count++
+			}
+		}
+		ioc.Confidence = policy.Score(ioc, count, now)
+	}
+
+	return nil
+}
+
+// RescoreIoCs recomputes the confidence score of all stored IoCs so that
+// recency decay and corroboration by newer sources are reflected. Only changed
+// scores are written. Returns the number of updated IoCs.
+func (uc *UseCases) RescoreIoCs(ctx context.Context, now time.Time) (int, error) {
+	iocs, err := uc.repo.ListAllIoCs(ctx)
+	if err != nil {
+		return 0, goerr.Wrap(err, "failed to list IoCs")
+	}
+
+	previous := make(map[string]int, len(iocs))
+	for _, ioc := range iocs {
+		previous[ioc.ID] = ioc.Confidence
+	}
+
+	if err := scoreIoCs(ctx, uc.repo, uc.confidencePolicy, iocs, now); err != nil {
+		return 0, goerr.Wrap(err, "failed to score IoCs")
+	}
+
+	changed := make(map[string]int)
+	for _, ioc := range iocs {
+		if ioc.Confidence != previous[ioc.ID] {
+			changed[ioc.ID] = ioc.Confidence
+		}
+	}
+
+	if err := uc.repo.UpdateIoCConfidences(ctx, changed); err != nil {
+		return 0, goerr.Wrap(err, "failed to update confidence scores",
+			goerr.V("count", len(changed)))
+	}
+
+	logging.From(ctx).Info("rescored IoCs",
+		"total", len(iocs),
+		"changed", len(changed))
+
+	return len(changed), nil
+}
@@ -0,0 +1,153 @@
+package usecase_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+const threatFoxCSV = `# ThreatFox IOCs
+"2025-12-24 07:24:23", "1685590", "198.51.100.50:51515", "ip:port", "botnet_cc", "elf.mirai", "Katana", "Mirai", "", "80", "None", "mirai", "0", "reporter"
+`
+
+func TestFetchUseCase_ConfidenceScoring(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(threatFoxCSV))
+	}))
+	defer server.Close()
+
+	repo := memory.New()
+	policy := model.NewConfidencePolicy(map[string]float64{"feed-a": 0.8})
+	fetchUC := usecase.NewFetchUseCase(repo, nil, usecase.WithFetchConfidencePolicy(policy))
+
+	newSource := func() model.Source {
+		return model.Source{
+			Type:       model.SourceTypeFeed,
+			URL:        server.URL,
+			Enabled:    true,
+			FeedConfig: &model.FeedConfig{Schema: "abuse_ch_threatfox"},
+		}
+	}
+
+	_, err := fetchUC.FetchSourceByID(ctx, map[string]model.Source{"feed-a": newSource()}, "feed-a")
+	gt.NoError(t, err)
+
+	iocsA, err := repo.ListIoCsBySource(ctx, "feed-a")
+	gt.NoError(t, err)
+	gt.A(t, iocsA).Length(1)
+	scoreAlone := iocsA[0].Confidence
+	gt.N(t, scoreAlone).Greater(0)
+	gt.Equal(t, iocsA[0].SourceConfidence, 80)
+	gt.False(t, iocsA[0].ExtractedByLLM)
+
+	// A second source reporting the same value is scored higher by corroboration
+	_, err = fetchUC.FetchSourceByID(ctx, map[string]model.Source{"feed-b": newSource()}, "feed-b")
+	gt.NoError(t, err)
+
+	iocsB, err := repo.ListIoCsBySource(ctx, "feed-b")
+	gt.NoError(t, err)
+	gt.A(t, iocsB).Length(1)
+	gt.Equal(t, iocsB[0].Value, iocsA[0].Value)
+
+	uncorroborated := policy.Score(iocsB[0], 1, time.Now())
+	gt.N(t, iocsB[0].Confidence).Greater(uncorroborated)
+
+	// Rescoring reflects the new corroboration on the first source
+	uc := usecase.New(repo, usecase.WithConfidencePolicy(policy))
+	changed, err := uc.RescoreIoCs(ctx, time.Now())
+	gt.NoError(t, err)
+	gt.N(t, changed).GreaterOrEqual(1)
+
+	rescored, err := repo.GetIoC(ctx, iocsA[0].ID)
+	gt.NoError(t, err)
+	gt.N(t, rescored.Confidence).Greater(scoreAlone)
+	gt.True(t, rescored.UpdatedAt.Equal(iocsA[0].UpdatedAt))
+
+	// Threshold filter
+	result, err := repo.ListIoCs(ctx, &model.IoCListOptions{MinConfidence: 101})
+	gt.NoError(t, err)
+	gt.A(t, result.Items).Length(0)
+}
+
+func TestFetchUseCase_ConfidencePersistedOnRefetch(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(threatFoxCSV))
+	}))
+	defer server.Close()
+
+	repo := memory.New()
+	fetchUC := usecase.NewFetchUseCase(repo, nil, usecase.WithFetchConfidencePolicy(model.NewConfidencePolicy(nil)))
+	sources := map[string]model.Source{}
+	for _, id := range []string{"feed-a", "feed-b"} {
+		sources[id] = model.Source{
+			Type:       model.SourceTypeFeed,
+			URL:        server.URL,
+			Enabled:    true,
+			FeedConfig: &model.FeedConfig{Schema: "abuse_ch_threatfox"},
+		}
+	}
+
+	_, err := fetchUC.FetchSourceByID(ctx, sources, "feed-a")
+	gt.NoError(t, err)
+	before, err := repo.ListIoCsBySource(ctx, "feed-a")
+	gt.NoError(t, err)
+	gt.A(t, before).Length(1)
+
+	_, err = fetchUC.FetchSourceByID(ctx, sources, "feed-b")
+	gt.NoError(t, err)
+
+	// Refetching the unchanged entry persists the corroborated score
+	history, err := fetchUC.FetchSourceByID(ctx, sources, "feed-a")
+	gt.NoError(t, err)
+	gt.Equal(t, history.IoCsUnchanged, 1)
+
+	after, err := repo.GetIoC(ctx, before[0].ID)
+	gt.NoError(t, err)
+	gt.N(t, after.Confidence).Greater(before[0].Confidence)
+}
+
+func TestFetchUseCase_SourceReliability(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(threatFoxCSV))
+	}))
+	defer server.Close()
+
+	repo := memory.New()
+	policy := model.NewConfidencePolicy(nil)
+	fetchUC := usecase.NewFetchUseCase(repo, nil, usecase.WithFetchConfidencePolicy(policy))
+
+	// The reliability of the source definition overrides the policy
+	reliability := 0.1
+	sources := map[string]model.Source{
+		"feed-a": {
+			Type:        model.SourceTypeFeed,
+			URL:         server.URL,
+			Enabled:     true,
+			FeedConfig:  &model.FeedConfig{Schema: "abuse_ch_threatfox"},
+			Reliability: &reliability,
+		},
+	}
+	_, err := fetchUC.FetchSourceByID(ctx, sources, "feed-a")
+	gt.NoError(t, err)
+
+	iocs, err := repo.ListIoCsBySource(ctx, "feed-a")
+	gt.NoError(t, err)
+	gt.A(t, iocs).Length(1)
+	gt.N(t, iocs[0].Confidence).Less(policy.Score(iocs[0], 1, time.Now()))
+
+	// The policy is not modified
+	gt.Equal(t, policy.Reliability("feed-a"), model.DefaultSourceReliability)
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+)
+
+// SourceConfigLoader loads and validates the sources of a config file
+type SourceConfigLoader func(path string) (map[string]model.Source, error)
+
+// SourceConfigReloader keeps the stored sources in sync with a config file.
+// Every load imports the file: sources imported from the config take its
+// definition, while sources changed through the API are kept and reported as
+// drifted. A reload also overwrites the sources changed in the file since the
+// previous load and deletes the removed ones. An invalid file is rejected and
+// the stored sources stay as they are.
+type SourceConfigReloader struct {
+	uc   *UseCases
+	path string
+	load SourceConfigLoader
+
+	mu      sync.Mutex              // serializes loads
+	sources map[string]model.Source // sources of the last loaded file
+	content []byte                  // content of the last checked file
+	status  atomic.Pointer[model.ConfigStatus]
+}
+
+// NewSourceConfigReloader creates a reloader of the config file at path
+func NewSourceConfigReloader(uc *UseCases, path string, load SourceConfigLoader) *SourceConfigReloader {
+	return &SourceConfigReloader{
+		uc:   uc,
+		path: path,
+		load: load,
+	}
+}
+
+// Status returns the result of the last load, nil before the first load
+func (r *SourceConfigReloader) Status() *model.ConfigStatus {
+	return r.status.Load()
+}
+
+// Load imports the sources of the config file
+func (r *SourceConfigReloader) Load(ctx context.Context) (*ImportSourcesResult, error) {
+	return r.reload(ctx, model.ConfigReloadStartup)
+}
+
+// Reload applies the changes of the config file since the previous load
+func (r *SourceConfigReloader) Reload(ctx context.Context, trigger model.ConfigReloadTrigger) (*ImportSourcesResult, error) {
+	return r.reload(ctx, trigger)
+}
+
+// Run reloads the config file when its content changes, checked every
+// interval (0 disables the check), or when signaled through hup
+func (r *SourceConfigReloader) Run(ctx context.Context, interval time.Duration, hup <-chan struct{}) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.reloadAndLog(ctx, model.ConfigReloadSignal)
+		case <-tick:
+			changed, err := r.changed()
+			if err != nil {
+				logging.From(ctx).Warn("failed to check config file", "path", r.path, "error", err)
+				continue
+			}
+			if changed {
+				r.reloadAndLog(ctx, model.ConfigReloadFile)
+			}
+		}
+	}
+}
+
+func (r *SourceConfigReloader) reloadAndLog(ctx context.Context, trigger model.ConfigReloadTrigger) {
+	logger := logging.From(ctx)
+	result, err := r.Reload(ctx, trigger)
+	if err != nil {
+		logger.Error("failed to reload config, keeping the current sources",
+			"path", r.path,
+			"trigger", trigger,
+			"error", err)
+		return
+	}
+	logger.Info("reloaded config",
+		"path", r.path,
+		"trigger", trigger,
+		"created", result.Created,
+		"updated", result.Updated,
+		"deleted", result.Deleted,
+		"drifted", result.Skipped)
+}
+
+// changed reports whether the content of the config file differs from the
+// last checked content. Content is compared rather than the modification time
+// so that files replaced through a symlink or with an old time are detected.
+func (r *SourceConfigReloader) changed() (bool, error) {
+	data, err := os.ReadFile(filepath.Clean(r.path))
+	if err != nil {
+		return false, goerr.Wrap(err, "failed to read config file", goerr.V("path", r.path))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !bytes.Equal(data, r.content), nil
+}
+
+func (r *SourceConfigReloader) reload(ctx context.Context, trigger model.ConfigReloadTrigger) (*ImportSourcesResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	status := &model.ConfigStatus{
+		Path:      r.path,
+		Trigger:   trigger,
+		CheckedAt: now,
+	}
+	if prev := r.status.Load(); prev != nil {
+		status.LoadedAt = prev.LoadedAt
+		status.Sources = prev.Sources
+	}
+
+	// Remember the checked content even if it is invalid so that a broken
+	// file is reported once, not on every check
+	if data, err := os.ReadFile(filepath.Clean(r.path)); err == nil {
+		r.content = data
+	}
+
+	result, sources, err := r.apply(ctx, trigger)
+	if err != nil {
+		status.Error = err.Error()
+		r.status.Store(status)
+		return nil, err
+	}
+
+	r.sources = sources
+	status.LoadedAt = now
+	status.Sources = len(sources)
+	status.Created = result.Created
+	status.Updated = result.Updated
+	status.Deleted = result.Deleted
+	status.Drifted = result.Skipped
+	r.status.Store(status)
+	return result, nil
+}
+
+func (r *SourceConfigReloader) apply(ctx context.Context, trigger model.ConfigReloadTrigger) (*ImportSourcesResult, map[string]model.Source, error) {
+	sources, err := r.load(r.path)
+	if err != nil {
+		return nil, nil, goerr.Wrap(err, "failed to load config", goerr.V("path", r.path))
+	}
+
+	if trigger == model.ConfigReloadStartup || r.sources == nil {
+		result, err := r.uc.ImportSources(ctx, sources, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result, sources, nil
+	}
+
+	// The sources changed in the file overwrite the stored ones; the others
+	// are imported as on startup to report the drift of API changes
+	changed := make(map[string]model.Source)
+	unchanged := make(map[string]model.Source)
+	for id, src := range sources {
+		prev, ok := r.sources[id]
+		if !ok || len(model.DiffSources(&prev, &src)) > 0 {
+			changed[id] = src
+		} else {
+			unchanged[id] = src
+		}
+	}
+	result, err := r.uc.ImportSources(ctx, changed, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	kept, err := r.uc.ImportSources(ctx, unchanged, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.merge(kept)
+
+	for _, id := range slices.Sorted(maps.Keys(r.sources)) {
+		if _, ok := sources[id]; ok {
+			continue
+		}
+		if err := r.uc.DeleteSource(ctx, id, model.ActorConfig); err != nil {
+			if errors.Is(err, interfaces.ErrSourceNotFound) {
+				continue // Already deleted through the API
+			}
+			return result, nil, err
+		}
+		result.Deleted = append(result.Deleted, id)
+	}
+	return result, sources, nil
+}
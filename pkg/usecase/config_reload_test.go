@@ -0,0 +1,195 @@
+package usecase_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/cli/config"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+func loadSources(path string) (map[string]model.Source, error) {
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Sources(), nil
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	gt.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
+
+const reloadConfigV1 = `
+[rss.blog]
+url = "https://blog.example/feed"
+extraction = "regex"
+
+[rss.news]
+url = "https://news.example/feed"
+extraction = "regex"
+
+[rss.old]
+url = "https://old.example/feed"
+extraction = "regex"
+`
+
+const reloadConfigV2 = `
+[rss.blog]
+url = "https://blog.example/feed"
+extraction = "regex"
+
+[rss.news]
+url = "https://news.example/rss"
+extraction = "regex"
+
+[rss.added]
+url = "https://added.example/feed"
+extraction = "regex"
+`
+
+func TestSourceConfigReloader(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	writeConfig(t, path, reloadConfigV1)
+
+	repo := memory.New()
+	uc := usecase.New(repo)
+	reloader := usecase.NewSourceConfigReloader(uc, path, loadSources)
+	gt.Value(t, reloader.Status()).Nil()
+
+	result, err := reloader.Load(ctx)
+	gt.NoError(t, err)
+	gt.A(t, result.Created).Equal([]string{"blog", "news", "old"})
+
+	status := reloader.Status()
+	gt.Value(t, status).NotNil()
+	gt.Equal(t, status.Trigger, model.ConfigReloadStartup)
+	gt.Equal(t, status.Sources, 3)
+	gt.Equal(t, status.Error, "")
+
+	// Changed through the API but not in the file: kept on reload
+	blog, err := uc.GetSource(ctx, "blog")
+	gt.NoError(t, err)
+	blog.Tags = []string{"api"}
+	_, err = uc.UpdateSource(ctx, blog, "alice")
+	gt.NoError(t, err)
+
+	t.Run("applies the changes of the file", func(t *testing.T) {
+		writeConfig(t, path, reloadConfigV2)
+		result, err := reloader.Reload(ctx, model.ConfigReloadSignal)
+		gt.NoError(t, err)
+		gt.A(t, result.Created).Equal([]string{"added"})
+		gt.A(t, result.Updated).Equal([]string{"news"})
+		gt.A(t, result.Deleted).Equal([]string{"old"})
+
+		news, err := uc.GetSource(ctx, "news")
+		gt.NoError(t, err)
+		gt.Equal(t, news.URL, "https://news.example/rss")
+
+		blog, err := uc.GetSource(ctx, "blog")
+		gt.NoError(t, err)
+		gt.A(t, blog.Tags).Equal([]string{"api"})
+
+		sources, err := uc.SourcesMap(ctx)
+		gt.NoError(t, err)
+		gt.Equal(t, len(sources), 3)
+
+		status := reloader.Status()
+		gt.Equal(t, status.Trigger, model.ConfigReloadSignal)
+		gt.Equal(t, status.Sources, 3)
+		gt.A(t, status.Deleted).Equal([]string{"old"})
+		// The source kept with its API changes is not in sync with the file
+		gt.A(t, status.Drifted).Equal([]string{"blog"})
+		gt.False(t, status.InSync())
+	})
+
+	t.Run("rejects an invalid file", func(t *testing.T) {
+		prev := reloader.Status()
+		writeConfig(t, path, "[rss.broken]\nextraction = \"regex\"\n")
+		_, err := reloader.Reload(ctx, model.ConfigReloadFile)
+		gt.Error(t, err)
+
+		status := reloader.Status()
+		gt.NotEqual(t, status.Error, "")
+		gt.True(t, status.LoadedAt.Equal(prev.LoadedAt))
+		gt.Equal(t, status.Sources, 3)
+
+		sources, err := uc.SourcesMap(ctx)
+		gt.NoError(t, err)
+		gt.Equal(t, len(sources), 3)
+	})
+
+	t.Run("applies the file edited while stopped on startup", func(t *testing.T) {
+		writeConfig(t, path, reloadConfigV1)
+		restarted := usecase.NewSourceConfigReloader(uc, path, loadSources)
+		result, err := restarted.Load(ctx)
+		gt.NoError(t, err)
+		gt.A(t, result.Created).Equal([]string{"old"})
+		gt.A(t, result.Updated).Equal([]string{"news"})
+
+		news, err := uc.GetSource(ctx, "news")
+		gt.NoError(t, err)
+		gt.Equal(t, news.URL, "https://news.example/feed")
+
+		status := restarted.Status()
+		gt.A(t, status.Drifted).Equal([]string{"blog"})
+		gt.False(t, status.InSync())
+	})
+}
+
+func TestSourceConfigReloader_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	writeConfig(t, path, reloadConfigV1)
+
+	uc := usecase.New(memory.New())
+	reloader := usecase.NewSourceConfigReloader(uc, path, loadSources)
+	_, err := reloader.Load(ctx)
+	gt.NoError(t, err)
+
+	hup := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		reloader.Run(ctx, 10*time.Millisecond, hup)
+	}()
+
+	waitTrigger := func(t *testing.T, trigger model.ConfigReloadTrigger) *model.ConfigStatus {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if status := reloader.Status(); status.Trigger == trigger {
+				return status
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("config was not reloaded by %s", trigger)
+		return nil
+	}
+
+	t.Run("file change", func(t *testing.T) {
+		writeConfig(t, path, reloadConfigV2)
+		status := waitTrigger(t, model.ConfigReloadFile)
+		gt.A(t, status.Created).Equal([]string{"added"})
+	})
+
+	t.Run("signal", func(t *testing.T) {
+		hup <- struct{}{}
+		status := waitTrigger(t, model.ConfigReloadSignal)
+		gt.Equal(t, status.Error, "")
+		gt.Equal(t, len(status.Created), 0)
+	})
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+	"github.com/secmon-lab/beehive/pkg/utils/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// embeddingBatchSize is the number of IoCs embedded per embedder call
+const embeddingBatchSize = 100
+
+// WithFetchEmbedder sets the embedder generating embeddings of fetched IoCs
+// (default: n-gram vectorizer)
+func WithFetchEmbedder(embedder interfaces.Embedder) FetchOption {
+	return func(uc *FetchUseCase) {
+		uc.embedder = embedder
+	}
+}
+
+// WithEmbedder sets the embedder used by ReembedIoCs
+func WithEmbedder(embedder interfaces.Embedder) Option {
+	return func(uc *UseCases) {
+		uc.embedder = embedder
+	}
+}
+
+// embedIoCs sets the embedding of iocs with embedder, in batches. On error,
+// the IoCs of the failed and following batches are left without embedding.
+func embedIoCs(ctx context.Context, embedder interfaces.Embedder, iocs []*model.IoC) error {
+	for start := 0; start < len(iocs); start += embeddingBatchSize {
+		batch := iocs[start:min(start+embeddingBatchSize, len(iocs))]
+
+		texts := make([]string, len(batch))
+		for i, ioc := range batch {
+			texts[i] = ioc.EmbeddingText()
+		}
+		embedCtx, span := tracing.Start(ctx, "embedding.embed",
+			attribute.String("embedding.model", embedder.Model()),
+			attribute.Int("embedding.count", len(batch)))
+		vectors, err := embedder.Embed(embedCtx, texts)
+		tracing.End(span, err)
+		if err != nil {
+			return goerr.Wrap(err, "failed to embed IoCs",
+				goerr.V("model", embedder.Model()),
+				goerr.V("count", len(batch)))
+		}
+
+		for i, ioc := range batch {
+			ioc.SetEmbedding(&model.IoCEmbedding{Vector: vectors[i], Model: embedder.Model()})
+		}
+	}
+	return nil
+}
+
+// ReembedIoCs embeds stored IoCs with the configured embedder, e.g. after
+// switching the embedding model or dimension. Unless all is true, only IoCs
+// without an embedding of the embedder's model and dimension are embedded.
+// Embeddings are written per batch, so an interrupted run can be resumed.
+// Returns the number of embedded IoCs.
+func (uc *UseCases) ReembedIoCs(ctx context.Context, all bool) (int, error) {
+	if uc.embedder == nil {
+		return 0, goerr.New("embedder not configured")
+	}
+	logger := logging.From(ctx)
+
+	iocs, err := uc.repo.ListAllIoCs(ctx)
+	if err != nil {
+		return 0, goerr.Wrap(err, "failed to list IoCs")
+	}
+
+	var targets []*model.IoC
+	for _, ioc := range iocs {
+		if all || !ioc.EmbeddedWith(uc.embedder.Model(), uc.embedder.Dimension()) {
+			targets = append(targets, ioc)
+		}
+	}
+
+	embedded := 0
+	for start := 0; start < len(targets); start += embeddingBatchSize {
+		batch := targets[start:min(start+embeddingBatchSize, len(targets))]
+		if err := embedIoCs(ctx, uc.embedder, batch); err != nil {
+			return embedded, err
+		}
+
+		embeddings := make(map[string]*model.IoCEmbedding, len(batch))
+		for _, ioc := range batch {
+			embeddings[ioc.ID] = &model.IoCEmbedding{Vector: ioc.Embedding, Model: ioc.EmbeddingModel}
+		}
+		if err := uc.repo.UpdateIoCEmbeddings(ctx, embeddings); err != nil {
+			return embedded, goerr.Wrap(err, "failed to update embeddings", goerr.V("count", len(batch)))
+		}
+		embedded += len(batch)
+
+		logger.Info("re-embedded IoCs",
+			"model", uc.embedder.Model(),
+			"progress", embedded,
+			"total", len(targets))
+	}
+
+	return embedded, nil
+}
@@ -0,0 +1,138 @@
+package usecase_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+// fixedEmbedder returns the same embedding for every text and records the calls
+type fixedEmbedder struct {
+	model  string
+	vector []float32
+	calls  [][]string
+}
+
+func (e *fixedEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.calls = append(e.calls, texts)
+	vectors := make([][]float32, len(texts))
+	for i := range texts {
+		vectors[i] = e.vector
+	}
+	return vectors, nil
+}
+
+func (e *fixedEmbedder) Model() string  { return e.model }
+func (e *fixedEmbedder) Dimension() int { return len(e.vector) }
+
+func TestReembedIoCs(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(threatFoxCSV))
+	}))
+	defer server.Close()
+
+	repo := memory.New()
+	fetchUC := usecase.NewFetchUseCase(repo, nil)
+	sources := map[string]model.Source{
+		"feed-a": {
+			Type:       model.SourceTypeFeed,
+			URL:        server.URL,
+			Enabled:    true,
+			FeedConfig: &model.FeedConfig{Schema: "abuse_ch_threatfox"},
+		},
+	}
+	_, err := fetchUC.FetchSourceByID(ctx, sources, "feed-a")
+	gt.NoError(t, err)
+
+	// Fetched IoCs are embedded with the default n-gram vectorizer
+	iocs, err := repo.ListIoCsBySource(ctx, "feed-a")
+	gt.NoError(t, err)
+	gt.A(t, iocs).Length(1)
+	gt.Equal(t, iocs[0].EmbeddingModel, "ngram/3")
+	gt.Equal(t, iocs[0].EmbeddingDimension, model.EmbeddingDimension)
+	gt.A(t, iocs[0].Embedding).Length(model.EmbeddingDimension)
+
+	t.Run("without embedder", func(t *testing.T) {
+		_, err := usecase.New(repo).ReembedIoCs(ctx, false)
+		gt.Error(t, err)
+	})
+
+	embedder := &fixedEmbedder{model: "local/nomic-embed-text", vector: []float32{0, 0.6, 0.8}}
+	uc := usecase.New(repo, usecase.WithEmbedder(embedder))
+
+	n, err := uc.ReembedIoCs(ctx, false)
+	gt.NoError(t, err)
+	gt.Equal(t, n, 1)
+	gt.Equal(t, embedder.calls, [][]string{{iocs[0].EmbeddingText()}})
+
+	reembedded, err := repo.GetIoC(ctx, iocs[0].ID)
+	gt.NoError(t, err)
+	gt.Equal(t, reembedded.EmbeddingModel, "local/nomic-embed-text")
+	gt.Equal(t, reembedded.EmbeddingDimension, 3)
+	gt.Equal(t, []float32(reembedded.Embedding), embedder.vector)
+	gt.True(t, reembedded.UpdatedAt.Equal(iocs[0].UpdatedAt))
+
+	// IoCs already embedded by the model are skipped unless all is set
+	n, err = uc.ReembedIoCs(ctx, false)
+	gt.NoError(t, err)
+	gt.Equal(t, n, 0)
+
+	n, err = uc.ReembedIoCs(ctx, true)
+	gt.NoError(t, err)
+	gt.Equal(t, n, 1)
+}
+
+func TestFetchUseCase_EmbedsOnlyNewOrRemodelledIoCs(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(threatFoxCSV))
+	}))
+	defer server.Close()
+
+	sources := map[string]model.Source{
+		"feed-a": {
+			Type:       model.SourceTypeFeed,
+			URL:        server.URL,
+			Enabled:    true,
+			FeedConfig: &model.FeedConfig{Schema: "abuse_ch_threatfox"},
+		},
+	}
+	repo := memory.New()
+
+	embedder := &fixedEmbedder{model: "model-a", vector: []float32{0, 0.6, 0.8}}
+	fetchUC := usecase.NewFetchUseCase(repo, nil, usecase.WithFetchEmbedder(embedder))
+	_, err := fetchUC.FetchSourceByID(ctx, sources, "feed-a")
+	gt.NoError(t, err)
+	gt.A(t, embedder.calls).Length(1)
+
+	// Refetching unchanged IoCs keeps the stored embedding
+	_, err = fetchUC.FetchSourceByID(ctx, sources, "feed-a")
+	gt.NoError(t, err)
+	gt.A(t, embedder.calls).Length(1)
+
+	iocs, err := repo.ListIoCsBySource(ctx, "feed-a")
+	gt.NoError(t, err)
+	gt.A(t, iocs).Length(1)
+	gt.Equal(t, iocs[0].EmbeddingModel, "model-a")
+	gt.Equal(t, []float32(iocs[0].Embedding), embedder.vector)
+
+	// IoCs embedded by another model are embedded again
+	other := &fixedEmbedder{model: "model-b", vector: []float32{1, 0}}
+	_, err = usecase.NewFetchUseCase(repo, nil, usecase.WithFetchEmbedder(other)).FetchSourceByID(ctx, sources, "feed-a")
+	gt.NoError(t, err)
+	gt.A(t, other.calls).Length(1)
+
+	got, err := repo.GetIoC(ctx, iocs[0].ID)
+	gt.NoError(t, err)
+	gt.Equal(t, got.EmbeddingModel, "model-b")
+	gt.Equal(t, got.EmbeddingDimension, 2)
+}
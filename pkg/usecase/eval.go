@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/m-mizutani/gollem"
+	"github.com/secmon-lab/beehive/pkg/domain/eval"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+)
+
+// EvalUseCase evaluates IoC extraction against golden articles
+type EvalUseCase struct {
+	extractor *extractor.Extractor
+	llmModel  string
+	mode      model.ExtractionMode
+
+	extractorOpts []extractor.Option
+}
+
+// EvalOption configures EvalUseCase
+type EvalOption func(*EvalUseCase)
+
+// WithEvalExtractorOptions configures the IoC extractor as in fetch
+func WithEvalExtractorOptions(opts ...extractor.Option) EvalOption {
+	return func(uc *EvalUseCase) {
+		uc.extractorOpts = append(uc.extractorOpts, opts...)
+	}
+}
+
+// WithEvalModel sets the model name recorded in the report
+func WithEvalModel(llmModel string) EvalOption {
+	return func(uc *EvalUseCase) {
+		uc.llmModel = llmModel
+	}
+}
+
+// WithEvalMode extracts all articles with mode instead of the mode of each article
+func WithEvalMode(mode model.ExtractionMode) EvalOption {
+	return func(uc *EvalUseCase) {
+		uc.mode = mode
+	}
+}
+
+// NewEvalUseCase creates a new evaluation use case
+func NewEvalUseCase(llmClient gollem.LLMClient, opts ...EvalOption) *EvalUseCase {
+	uc := &EvalUseCase{}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	uc.extractor = extractor.New(llmClient, uc.extractorOpts...)
+	return uc
+}
+
+// Run extracts IoCs from each golden article and scores the results. A failed
+// extraction is recorded in the case result and does not stop the run.
+func (uc *EvalUseCase) Run(ctx context.Context, cases []*eval.Case) *eval.Report {
+	logger := logging.From(ctx)
+
+	results := make([]*eval.CaseResult, 0, len(cases))
+	for _, c := range cases {
+		mode := c.Mode
+		if uc.mode != "" {
+			mode = uc.mode
+		}
+		if mode == "" {
+			mode = model.ExtractionModeLLM
+		}
+
+		var extracted []*extractor.ExtractedIoC
+		result, err := uc.extractor.Extract(ctx, mode, c.Title, c.Content)
+		if err != nil {
+			logger.Warn("failed to extract IoCs from golden article", "case", c.Name, "error", err)
+		} else {
+			extracted = result.IoCs
+		}
+
+		scored := eval.Score(c, mode, extracted, err)
+		logger.Debug("evaluated golden article",
+			"case", c.Name,
+			"mode", mode,
+			"true_positives", scored.TruePositives,
+			"false_positives", scored.FalsePositives,
+			"false_negatives", scored.FalseNegatives)
+		results = append(results, scored)
+	}
+
+	return eval.NewReport(uc.llmModel, extractor.PromptVersion(model.ExtractionModeLLM), results)
+}
@@ -0,0 +1,116 @@
+package usecase_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m-mizutani/gollem"
+	"github.com/m-mizutani/gollem/mock"
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/eval"
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/service/llmrecord"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+func writeGoldenArticle(t *testing.T, dir, name, article, toml string) {
+	t.Helper()
+	gt.NoError(t, os.WriteFile(filepath.Join(dir, name+".md"), []byte(article), 0o600))
+	gt.NoError(t, os.WriteFile(filepath.Join(dir, name+".toml"), []byte(toml), 0o600))
+}
+
+func TestEvalUseCase(t *testing.T) {
+	ctx := context.Background()
+	goldenDir := t.TempDir()
+	writeGoldenArticle(t, goldenDir, "campaign",
+		"The loader beacons to c2.example[.]com. See https://github.com/org/tool for the detection rule.",
+		`title = "Campaign"
+
+[[iocs]]
+type = "domain"
+value = "c2.example.com"
+label = "malicious"
+
+[[iocs]]
+type = "url"
+value = "https://github.com/org/tool"
+label = "benign"
+`)
+	writeGoldenArticle(t, goldenDir, "dropper",
+		"The dropper writes to C:\\Users\\Public\\svc.exe and connects to 198.51.100.7.",
+		`title = "Dropper"
+mode = "regex"
+
+[[iocs]]
+type = "ipv4"
+value = "198.51.100.7"
+label = "malicious"
+`)
+
+	cases, err := eval.LoadCases(goldenDir)
+	gt.NoError(t, err)
+
+	var prompts []string
+	llm := &mock.LLMClientMock{
+		NewSessionFunc: func(ctx context.Context, options ...gollem.SessionOption) (gollem.Session, error) {
+			return &mock.SessionMock{
+				GenerateContentFunc: func(ctx context.Context, input ...gollem.Input) (*gollem.Response, error) {
+					prompts = append(prompts, string(input[0].(gollem.Text)))
+					return &gollem.Response{Texts: []string{`{"iocs": [
+						{"type": "domain", "value": "c2.example[.]com", "description": "C2"},
+						{"type": "url", "value": "https://github.com/org/tool", "description": "Tool"}
+					]}`}}, nil
+				},
+			}, nil
+		},
+	}
+
+	recordDir := t.TempDir()
+	recorder, err := llmrecord.NewRecorder(llm, recordDir)
+	gt.NoError(t, err)
+
+	report := usecase.NewEvalUseCase(recorder, usecase.WithEvalModel("mock")).Run(ctx, cases)
+	gt.A(t, prompts).Length(1) // The regex article does not call the LLM
+	gt.Equal(t, report.Model, "mock")
+	gt.Equal(t, report.PromptVersion, extractor.PromptVersion(model.ExtractionModeLLM))
+	gt.Equal(t, report.Total, eval.Counts{TruePositives: 2, FalsePositives: 1})
+	gt.A(t, report.Cases).Length(2).
+		At(0, func(t testing.TB, c *eval.CaseResult) {
+			gt.Equal(t, c.Name, "campaign")
+			gt.Equal(t, c.Mode, model.ExtractionModeLLM)
+			gt.Equal(t, c.FalsePositives[0].Reason, eval.ReasonBenign)
+		}).
+		At(1, func(t testing.TB, c *eval.CaseResult) {
+			gt.Equal(t, c.Mode, model.ExtractionModeRegex)
+		})
+
+	t.Run("replay gives the same results without the LLM", func(t *testing.T) {
+		replayer, err := llmrecord.NewReplayer(recordDir)
+		gt.NoError(t, err)
+
+		replayed := usecase.NewEvalUseCase(replayer).Run(ctx, cases)
+		gt.A(t, prompts).Length(1)
+		gt.Equal(t, replayed.Total, report.Total)
+		gt.False(t, eval.Compare(report, replayed).Regressed())
+	})
+
+	t.Run("mode override", func(t *testing.T) {
+		regex := usecase.NewEvalUseCase(nil, usecase.WithEvalMode(model.ExtractionModeRegex)).Run(ctx, cases)
+		gt.A(t, prompts).Length(1)
+		for _, c := range regex.Cases {
+			gt.Equal(t, c.Mode, model.ExtractionModeRegex)
+			gt.Equal(t, c.Error, "")
+		}
+	})
+
+	t.Run("extraction failures are scored as missed", func(t *testing.T) {
+		failing := newReportLLM("not json", "")
+		report := usecase.NewEvalUseCase(failing).Run(ctx, cases)
+		gt.S(t, report.Cases[0].Error).NotEqual("")
+		gt.Equal(t, report.Cases[0].Counts, eval.Counts{FalseNegatives: 1})
+		gt.Equal(t, report.Cases[1].Counts, eval.Counts{TruePositives: 1})
+	})
+}
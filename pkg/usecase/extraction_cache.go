@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/secmon-lab/beehive/pkg/domain/extractor"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+)
+
+// WithExtractionCache caches LLM extraction results of articles in cache, so
+// that an article fetched again (after a state reset, or by another source) is
+// not sent to the LLM twice. llmModel identifies the LLM in cache keys. Entries
+// expire after ttl; 0 keeps them until purged.
+func WithExtractionCache(cache interfaces.ExtractionCacheRepository, llmModel string, ttl time.Duration) FetchOption {
+	return func(uc *FetchUseCase) {
+		uc.extractionCache = cache
+		uc.cacheModel = llmModel
+		uc.cacheTTL = ttl
+	}
+}
+
+// WithCacheRefresh makes the sources ignore cached extraction results. Their
+// articles are extracted again and the results replace the cached entries.
+func WithCacheRefresh(sourceIDs ...string) FetchOption {
+	return func(uc *FetchUseCase) {
+		if uc.cacheRefresh == nil {
+			uc.cacheRefresh = make(map[string]bool)
+		}
+		for _, id := range sourceIDs {
+			uc.cacheRefresh[id] = true
+		}
+	}
+}
+
+// extract extracts IoCs from an article, using the extraction cache for modes
+// calling the LLM. ex is the extractor of the source, which may have a prompt
+// customization. Cache failures are logged and do not fail the extraction.
+func (uc *FetchUseCase) extract(ctx context.Context, ex *extractor.Extractor, sourceID string, mode model.ExtractionMode, title, content string) (*extractor.Result, error) {
+	if uc.extractionCache == nil || !mode.UsesLLM() {
+		return ex.Extract(ctx, mode, title, content)
+	}
+
+	logger := logging.From(ctx)
+	key := ex.CacheKey(mode, uc.cacheModel, title, content)
+	now := time.Now()
+
+	if !uc.cacheRefresh[sourceID] {
+		entry, err := uc.extractionCache.GetExtractionCache(ctx, key)
+		switch {
+		case err == nil && !entry.Expired(now):
+			logger.Debug("using cached extraction result",
+				"source_id", sourceID,
+				"title", title,
+				"cached_by", entry.SourceID,
+				"cached_at", entry.CreatedAt)
+			return &extractor.Result{
+				IoCs:       fromCachedIoCs(entry.IoCs),
+				Unverified: fromCachedIoCs(entry.Unverified),
+			}, nil
+		case err != nil && !errors.Is(err, interfaces.ErrExtractionCacheNotFound):
+			logger.Warn("failed to get cached extraction result",
+				"source_id", sourceID,
+				"error", err)
+		}
+	}
+
+	result, err := ex.Extract(ctx, mode, title, content)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &model.ExtractionCacheEntry{
+		Key:           key,
+		SourceID:      sourceID,
+		Mode:          mode,
+		PromptVersion: ex.PromptVersion(mode),
+		Model:         uc.cacheModel,
+		IoCs:          toCachedIoCs(result.IoCs),
+		Unverified:    toCachedIoCs(result.Unverified),
+		CreatedAt:     now,
+	}
+	if uc.cacheTTL > 0 {
+		entry.ExpiresAt = now.Add(uc.cacheTTL)
+	}
+	if err := uc.extractionCache.PutExtractionCache(ctx, entry); err != nil {
+		logger.Warn("failed to cache extraction result",
+			"source_id", sourceID,
+			"error", err)
+	}
+
+	return result, nil
+}
+
+func toCachedIoCs(iocs []*extractor.ExtractedIoC) []*model.CachedIoC {
+	cached := make([]*model.CachedIoC, 0, len(iocs))
+	for _, ioc := range iocs {
+		cached = append(cached, &model.CachedIoC{
+			Type:        ioc.Type,
+			Value:       ioc.Value,
+			Description: ioc.Description,
+		})
+	}
+	return cached
+}
+
+func fromCachedIoCs(cached []*model.CachedIoC) []*extractor.ExtractedIoC {
+	iocs := make([]*extractor.ExtractedIoC, 0, len(cached))
+	for _, c := range cached {
+		iocs = append(iocs, &extractor.ExtractedIoC{
+			Type:        c.Type,
+			Value:       c.Value,
+			Description: c.Description,
+		})
+	}
+	return iocs
+}
@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gollem"
+	"github.com/m-mizutani/gollem/mock"
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+// newCountingLLM returns a mock LLM that counts IoC extraction prompts
+func newCountingLLM(calls *atomic.Int32) *mock.LLMClientMock {
+	return &mock.LLMClientMock{
+		NewSessionFunc: func(ctx context.Context, options ...gollem.SessionOption) (gollem.Session, error) {
+			return &mock.SessionMock{
+				GenerateContentFunc: func(ctx context.Context, input ...gollem.Input) (*gollem.Response, error) {
+					if strings.Contains(string(input[0].(gollem.Text)), "Threat Report Analysis") {
+						return &gollem.Response{Texts: []string{`{"summary": "", "threat_actors": [], "malware_families": [],
+							"sectors": [], "cves": [], "attack_techniques": []}`}}, nil
+					}
+					calls.Add(1)
+					return &gollem.Response{Texts: []string{
+						`{"iocs": [{"type": "domain", "value": "c2.example[.]com", "description": "C2 server"}]}`,
+					}}, nil
+				},
+			}, nil
+		},
+	}
+}
+
+func TestFetchUseCase_ExtractionCache(t *testing.T) {
+	ctx := context.Background()
+	server := newBlogServer(t, `The implant beacons to c2.example[.]com every hour.`)
+	sources := map[string]model.Source{
+		"blog":   {Type: model.SourceTypeRSS, URL: server.URL + "/feed", Enabled: true},
+		"mirror": {Type: model.SourceTypeRSS, URL: server.URL + "/feed", Enabled: true},
+	}
+
+	cache := memory.New()
+	var calls atomic.Int32
+	llm := newCountingLLM(&calls)
+
+	// fetch runs a source against a fresh repository, as after a state reset
+	fetch := func(t *testing.T, sourceID string, opts ...usecase.FetchOption) *model.History {
+		t.Helper()
+		repo := memory.New()
+		history, err := usecase.NewFetchUseCase(repo, llm, opts...).FetchSourceByID(ctx, sources, sourceID)
+		gt.NoError(t, err)
+		gt.Equal(t, history.ErrorCount, 0)
+		gt.Equal(t, history.IoCsCreated, 1)
+
+		iocs, err := repo.ListIoCsBySource(ctx, sourceID)
+		gt.NoError(t, err)
+		gt.A(t, iocs).Length(1).At(0, func(t testing.TB, ioc *model.IoC) {
+			gt.Equal(t, ioc.Value, "c2.example.com")
+			gt.Equal(t, ioc.Description, "C2 server")
+			gt.True(t, ioc.ExtractedByLLM)
+		})
+		return history
+	}
+	withCache := usecase.WithExtractionCache(cache, "gemini/default", time.Hour)
+
+	fetch(t, "blog", withCache)
+	gt.Equal(t, calls.Load(), int32(1))
+
+	t.Run("refetched article is not sent to the LLM", func(t *testing.T) {
+		fetch(t, "blog", withCache)
+		gt.Equal(t, calls.Load(), int32(1))
+	})
+
+	t.Run("same article of another source is not sent to the LLM", func(t *testing.T) {
+		fetch(t, "mirror", withCache)
+		gt.Equal(t, calls.Load(), int32(1))
+	})
+
+	t.Run("refresh ignores cached results of the source", func(t *testing.T) {
+		fetch(t, "blog", withCache, usecase.WithCacheRefresh("blog"))
+		gt.Equal(t, calls.Load(), int32(2))
+
+		// Other sources keep using the cache
+		fetch(t, "mirror", withCache, usecase.WithCacheRefresh("blog"))
+		gt.Equal(t, calls.Load(), int32(2))
+	})
+
+	t.Run("another model does not use the cached results", func(t *testing.T) {
+		fetch(t, "blog", usecase.WithExtractionCache(cache, "claude/default", time.Hour))
+		gt.Equal(t, calls.Load(), int32(3))
+	})
+
+	t.Run("another extraction mode does not use the cached results", func(t *testing.T) {
+		crossCheck := map[string]model.Source{
+			"blog": {
+				Type:      model.SourceTypeRSS,
+				URL:       server.URL + "/feed",
+				Enabled:   true,
+				RSSConfig: &model.RSSConfig{Extraction: model.ExtractionModeCrossCheck},
+			},
+		}
+		repo := memory.New()
+		_, err := usecase.NewFetchUseCase(repo, llm, withCache).FetchSourceByID(ctx, crossCheck, "blog")
+		gt.NoError(t, err)
+		gt.Equal(t, calls.Load(), int32(4))
+	})
+
+	t.Run("a customized prompt does not use the cached results", func(t *testing.T) {
+		customized := map[string]model.Source{
+			"blog": {
+				Type:    model.SourceTypeRSS,
+				URL:     server.URL + "/feed",
+				Enabled: true,
+				RSSConfig: &model.RSSConfig{Prompt: &model.ExtractionPrompt{
+					Types: []model.IoCType{model.IoCTypeIPv4},
+				}},
+			},
+		}
+		repo := memory.New()
+		before := calls.Load()
+		history, err := usecase.NewFetchUseCase(repo, llm, withCache).FetchSourceByID(ctx, customized, "blog")
+		gt.NoError(t, err)
+		gt.Equal(t, calls.Load(), before+1)
+		// The extracted domain is not in the allowed types
+		gt.Equal(t, history.IoCsCreated, 0)
+	})
+
+	t.Run("expired results are extracted again", func(t *testing.T) {
+		shortLived := memory.New()
+		withShortTTL := usecase.WithExtractionCache(shortLived, "gemini/default", time.Millisecond)
+		before := calls.Load()
+		fetch(t, "blog", withShortTTL)
+		time.Sleep(5 * time.Millisecond)
+		fetch(t, "blog", withShortTTL)
+		gt.Equal(t, calls.Load(), before+2)
+	})
+
+	t.Run("purged results are extracted again", func(t *testing.T) {
+		deleted, err := cache.PurgeExtractionCache(ctx, &model.ExtractionCachePurgeOptions{})
+		gt.NoError(t, err)
+		gt.N(t, deleted).Greater(0)
+
+		before := calls.Load()
+		fetch(t, "mirror", withCache)
+		gt.Equal(t, calls.Load(), before+1)
+	})
+}
@@ -0,0 +1,125 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+// newBlogServer serves an RSS feed with a single article containing articleBody
+func newBlogServer(t *testing.T, articleBody string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/feed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = fmt.Fprintf(w, `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Blog</title>
+<item><title>Campaign analysis</title><link>%s/post/1</link><guid>post-1</guid>
+<pubDate>Wed, 24 Dec 2025 07:24:23 GMT</pubDate></item>
+</channel></rss>`, server.URL)
+	})
+	mux.HandleFunc("/post/1", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `<html><body><article>%s</article></body></html>`, articleBody)
+	})
+
+	return server
+}
+
+func TestFetchUseCase_RegexExtraction(t *testing.T) {
+	ctx := context.Background()
+	server := newBlogServer(t, `The loader downloads its payload from hxxp://cdn.evil-domain[.]ru/malware.exe
+		and beacons to 198.51.100[.]42. The dropper hash is 5d41402abc4b2a76b9719d911017c592.`)
+
+	repo := memory.New()
+	// No LLM client is needed in regex mode
+	fetchUC := usecase.NewFetchUseCase(repo, nil)
+
+	sources := map[string]model.Source{
+		"blog": {
+			Type:      model.SourceTypeRSS,
+			URL:       server.URL + "/feed",
+			Enabled:   true,
+			RSSConfig: &model.RSSConfig{Extraction: model.ExtractionModeRegex},
+		},
+	}
+
+	history, err := fetchUC.FetchSourceByID(ctx, sources, "blog")
+	gt.NoError(t, err)
+	gt.Equal(t, history.Status, model.FetchStatusSuccess)
+	gt.Equal(t, history.IoCsExtracted, 3)
+	gt.Equal(t, history.IoCsCreated, 3)
+
+	conn, err := repo.ListIoCs(ctx, &model.IoCListOptions{})
+	gt.NoError(t, err)
+	values := make(map[string]model.IoCType)
+	for _, ioc := range conn.Items {
+		values[ioc.Value] = ioc.Type
+		gt.False(t, ioc.ExtractedByLLM)
+		gt.Equal(t, ioc.SourceURL, server.URL+"/post/1")
+	}
+	gt.Equal(t, values, map[string]model.IoCType{
+		"http://cdn.evil-domain.ru/malware.exe": model.IoCTypeURL,
+		"198.51.100.42":                         model.IoCTypeIPv4,
+		"5d41402abc4b2a76b9719d911017c592":      model.IoCTypeMD5,
+	})
+}
+
+func TestFetchUseCase_LLMExtractionWithoutClient(t *testing.T) {
+	ctx := context.Background()
+	server := newBlogServer(t, `The loader beacons to 198.51.100[.]42.`)
+
+	sources := map[string]model.Source{
+		"blog": {
+			Type:    model.SourceTypeRSS,
+			URL:     server.URL + "/feed",
+			Enabled: true,
+		},
+	}
+
+	t.Run("skipped by default", func(t *testing.T) {
+		repo := memory.New()
+		fetchUC := usecase.NewFetchUseCase(repo, nil)
+
+		history, err := fetchUC.FetchSourceByID(ctx, sources, "blog")
+		gt.NoError(t, err)
+		gt.Equal(t, history.Status, model.FetchStatusFailure)
+		gt.Equal(t, history.ItemsFetched, 0)
+		gt.Equal(t, history.IoCsCreated, 0)
+		gt.Equal(t, history.ErrorCount, 1)
+		gt.S(t, history.Errors[0].Message).Contains("LLM is not configured")
+		gt.Equal(t, history.Errors[0].Values["source_id"], "blog")
+
+		// The source is not fetched, so it is fetched from the start once an LLM is configured
+		_, err = repo.GetState(ctx, "blog")
+		gt.True(t, errors.Is(err, interfaces.ErrSourceStateNotFound))
+	})
+
+	t.Run("regex fallback", func(t *testing.T) {
+		repo := memory.New()
+		fetchUC := usecase.NewFetchUseCase(repo, nil, usecase.WithLLMFallback(model.LLMFallbackRegex))
+
+		history, err := fetchUC.FetchSourceByID(ctx, sources, "blog")
+		gt.NoError(t, err)
+		gt.Equal(t, history.Status, model.FetchStatusSuccess)
+		gt.Equal(t, history.IoCsCreated, 1)
+
+		iocs, err := repo.ListIoCsBySource(ctx, "blog")
+		gt.NoError(t, err)
+		gt.A(t, iocs).Length(1).At(0, func(t testing.TB, ioc *model.IoC) {
+			gt.Equal(t, ioc.Value, "198.51.100.42")
+			gt.False(t, ioc.ExtractedByLLM)
+		})
+	})
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/m-mizutani/goerr/v2"
 	"github.com/m-mizutani/gollem"
+	"github.com/secmon-lab/beehive/pkg/domain/brand"
 	"github.com/secmon-lab/beehive/pkg/domain/extractor"
 	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
 	"github.com/secmon-lab/beehive/pkg/domain/model"
@@ -14,13 +15,25 @@ import (
 	"github.com/secmon-lab/beehive/pkg/service/feed"
 	"github.com/secmon-lab/beehive/pkg/service/rss"
 	"github.com/secmon-lab/beehive/pkg/utils/logging"
+	"github.com/secmon-lab/beehive/pkg/utils/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrLLMNotConfigured is recorded in the history of an RSS source that requires
+// an LLM when no LLM is configured
+var ErrLLMNotConfigured = goerr.New("LLM is not configured")
+
 // fetchRepository defines the repository methods required by FetchUseCase
 type fetchRepository interface {
 	interfaces.IoCRepository
+	interfaces.IoCStatusRepository
 	interfaces.SourceStateRepository
 	interfaces.HistoryRepository
+	interfaces.WatchlistRepository
+	interfaces.BrandMatchRepository
+	interfaces.ReportRepository
+	interfaces.AuditRepository
 }
 
 // FetchUseCase orchestrates the fetching of IoCs from various sources
@@ -30,6 +43,83 @@ type FetchUseCase struct {
 	rssService  *rss.Service
 	feedService *feed.Service
 	extractor   *extractor.Extractor
+	ttlPolicy   model.TTLPolicy
+	confidence  *model.ConfidencePolicy
+	notifier    *NotificationUseCase
+	brand       *brand.Detector
+	embedder    interfaces.Embedder
+	asn         interfaces.ASNResolver
+	llmFallback model.LLMFallback
+	audit       *Auditor
+	metrics     interfaces.Metrics
+
+	extractorOpts []extractor.Option
+
+	extractionCache interfaces.ExtractionCacheRepository
+	cacheModel      string
+	cacheTTL        time.Duration
+	cacheRefresh    map[string]bool // Source IDs ignoring cached extraction results
+}
+
+// FetchOption configures FetchUseCase
+type FetchOption func(*FetchUseCase)
+
+// WithTTLPolicy sets the per-type TTL policy used to compute IoC expiry
+func WithTTLPolicy(policy model.TTLPolicy) FetchOption {
+	return func(uc *FetchUseCase) {
+		uc.ttlPolicy = policy
+	}
+}
+
+// WithFetchConfidencePolicy sets the policy used to score fetched IoCs
+func WithFetchConfidencePolicy(policy *model.ConfidencePolicy) FetchOption {
+	return func(uc *FetchUseCase) {
+		uc.confidence = policy
+	}
+}
+
+// WithBrandDetector sets the detector flagging newly ingested lookalikes of protected domains
+func WithBrandDetector(detector *brand.Detector) FetchOption {
+	return func(uc *FetchUseCase) {
+		uc.brand = detector
+	}
+}
+
+// WithExtractorOptions configures the IoC extractor, e.g. chunking of long articles
+func WithExtractorOptions(opts ...extractor.Option) FetchOption {
+	return func(uc *FetchUseCase) {
+		uc.extractorOpts = append(uc.extractorOpts, opts...)
+	}
+}
+
+// WithNotifier sets the notification use case evaluated after each fetch
+func WithNotifier(notifier *NotificationUseCase) FetchOption {
+	return func(uc *FetchUseCase) {
+		uc.notifier = notifier
+	}
+}
+
+// WithLLMFallback sets how RSS sources requiring an LLM are fetched when the
+// use case has no LLM client (default: model.LLMFallbackSkip)
+func WithLLMFallback(fallback model.LLMFallback) FetchOption {
+	return func(uc *FetchUseCase) {
+		uc.llmFallback = fallback
+	}
+}
+
+// WithFetchAuditor sets the auditor of completed fetches (default: records in
+// the repository only)
+func WithFetchAuditor(auditor *Auditor) FetchOption {
+	return func(uc *FetchUseCase) {
+		uc.audit = auditor
+	}
+}
+
+// WithFetchMetrics records completed fetches and LLM calls of the extractor in metrics
+func WithFetchMetrics(metrics interfaces.Metrics) FetchOption {
+	return func(uc *FetchUseCase) {
+		uc.metrics = metrics
+	}
 }
 
 // FetchStats represents statistics from a fetch operation
@@ -38,10 +128,13 @@ type FetchStats struct {
 	SourceType     string
 	ItemsFetched   int
 	IoCsExtracted  int
+	IoCsUnverified int // LLM-extracted values not found in the article (cross-check mode)
 	IoCsCreated    int // New IoCs created
 	IoCsUpdated    int // Existing IoCs updated (description/status changed)
 	IoCsUnchanged  int // Existing IoCs unchanged (skipped)
 	IoCsGeneric    int // Generic IoCs skipped
+	WatchlistHits  int // New watchlist hits
+	BrandMatches   int // New lookalike domains of protected domains
 	ErrorCount     int
 	ProcessingTime time.Duration
 }
@@ -50,21 +143,38 @@ type FetchStats struct {
 func NewFetchUseCase(
 	repo fetchRepository,
 	llmClient gollem.LLMClient,
+	opts ...FetchOption,
 ) *FetchUseCase {
-	// Initialize n-gram vectorizer for embedding generation
-	vec := vectorizer.NewNGramVectorizer()
-
-	return &FetchUseCase{
+	uc := &FetchUseCase{
 		repo:        repo,
 		llmClient:   llmClient,
 		rssService:  rss.New(),
 		feedService: feed.New(),
-		extractor:   extractor.New(llmClient, extractor.WithNGramVectorizer(vec)),
+		ttlPolicy:   model.NewTTLPolicy(nil),
+		confidence:  model.NewConfidencePolicy(nil),
+		embedder:    vectorizer.NewNGramVectorizer(),
+		llmFallback: model.LLMFallbackSkip,
+		audit:       NewAuditor(repo),
 	}
+
+	for _, opt := range opts {
+		opt(uc)
+	}
+
+	extractorOpts := append([]extractor.Option{extractor.WithEmbedder(uc.embedder)}, uc.extractorOpts...)
+	if uc.metrics != nil {
+		extractorOpts = append(extractorOpts, extractor.WithMetrics(uc.metrics))
+	}
+	uc.extractor = extractor.New(llmClient, extractorOpts...)
+
+	return uc
 }
 
 // FetchAllSources fetches IoCs from all enabled sources, optionally filtered by tags
 func (uc *FetchUseCase) FetchAllSources(ctx context.Context, sources map[string]model.Source, tags []string) ([]*model.History, error) {
+	ctx, span := tracing.Start(ctx, "fetch.all_sources", attribute.StringSlice("fetch.tags", tags))
+	defer span.End()
+
 	logger := logging.From(ctx)
 	var allHistories []*model.History
 
@@ -86,16 +196,19 @@ func (uc *FetchUseCase) FetchAllSources(ctx context.Context, sources map[string]
 
 		logger.Info("fetching from source", "source_id", sourceID, "type", source.Type)
 
+		sourceCtx, sourceSpan := startSourceSpan(ctx, sourceID, &source)
+
 		var history *model.History
 		var err error
 
 		switch source.Type {
 		case model.SourceTypeRSS:
-			history, err = uc.fetchRSS(ctx, sourceID, &source)
+			history, err = uc.fetchRSS(sourceCtx, sourceID, &source)
 		case model.SourceTypeFeed:
-			history, err = uc.fetchFeed(ctx, sourceID, &source)
+			history, err = uc.fetchFeed(sourceCtx, sourceID, &source)
 		default:
 			logger.Warn("unknown source type", "source_id", sourceID, "type", source.Type)
+			sourceSpan.End()
 			continue
 		}
 
@@ -121,26 +234,38 @@ func (uc *FetchUseCase) FetchAllSources(ctx context.Context, sources map[string]
 				IoCsUnchanged:  0,
 				ErrorCount:     1,
 				Errors:         []*model.FetchError{model.ExtractErrorInfo(err)},
+				TraceID:        tracing.TraceID(sourceCtx),
 				CreatedAt:      time.Now(),
 			}
-			if histErr := uc.repo.SaveHistory(ctx, history); histErr != nil {
+			if histErr := uc.repo.SaveHistory(sourceCtx, history); histErr != nil {
 				logger.Error("failed to save fetch history",
 					"source_id", sourceID,
 					"history_id", history.ID,
 					"error", histErr)
 			}
+			uc.notify(sourceCtx, history, nil)
 		}
 
+		uc.recordFetch(sourceCtx, history)
+		tracing.End(sourceSpan, err)
 		allHistories = append(allHistories, history)
 	}
 
 	return allHistories, nil
 }
 
+// startSourceSpan starts the span covering the fetch of a source
+func startSourceSpan(ctx context.Context, sourceID string, source *model.Source) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "fetch.source",
+		attribute.String("source.id", sourceID),
+		attribute.String("source.type", string(source.Type)))
+}
+
 // fetchRSS fetches and processes IoCs from an RSS source
 func (uc *FetchUseCase) fetchRSS(ctx context.Context, sourceID string, source *model.Source) (*model.History, error) {
 	logger := logging.From(ctx)
 	startTime := time.Now()
+	historyID := model.GenerateHistoryID()
 	stats := &FetchStats{
 		SourceID:   sourceID,
 		SourceType: string(model.SourceTypeRSS),
@@ -149,6 +274,22 @@ func (uc *FetchUseCase) fetchRSS(ctx context.Context, sourceID string, source *m
 	// Track errors with context for history
 	var fetchErrors []*model.FetchError
 
+	extractionMode := model.ExtractionModeLLM
+	if source.RSSConfig != nil && source.RSSConfig.Extraction != "" {
+		extractionMode = source.RSSConfig.Extraction
+	}
+	if extractionMode.UsesLLM() && uc.llmClient == nil {
+		if uc.llmFallback != model.LLMFallbackRegex {
+			return nil, goerr.Wrap(ErrLLMNotConfigured, "RSS source requires an LLM, skipped",
+				goerr.V("source_id", sourceID),
+				goerr.V("extraction", extractionMode))
+		}
+		logger.Info("LLM is not configured, extracting IoCs deterministically",
+			"source_id", sourceID,
+			"extraction", extractionMode)
+		extractionMode = model.ExtractionModeRegex
+	}
+
 	// Get previous state
 	state, err := uc.repo.GetState(ctx, sourceID)
 	if err != nil {
@@ -165,6 +306,7 @@ func (uc *FetchUseCase) fetchRSS(ctx context.Context, sourceID string, source *m
 	}
 
 	// Fetch RSS feed
+	reportPhase(ctx, model.JobPhaseFetch)
 	articles, err := uc.rssService.FetchFeed(ctx, source.URL)
 	if err != nil {
 		return nil, goerr.Wrap(err, "failed to fetch RSS feed",
@@ -191,13 +333,32 @@ func (uc *FetchUseCase) fetchRSS(ctx context.Context, sourceID string, source *m
 		"source_id", sourceID,
 		"new_articles", len(newArticles))
 
+	ex := uc.extractor
+	if source.RSSConfig != nil && source.RSSConfig.Prompt != nil {
+		prompt, err := extractor.NewPrompt(source.RSSConfig.Prompt)
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid extraction prompt", goerr.V("source_id", sourceID))
+		}
+		ex = ex.WithPrompt(prompt)
+	}
+
 	// Accumulate IoCs for batch writing
 	var iocsToSave []*model.IoC
 
 	// Process each article
-	for _, article := range newArticles {
+	reportPhase(ctx, model.JobPhaseExtract)
+	for i, article := range newArticles {
+		// Stop without saving anything so that the articles are processed
+		// again by the next fetch
+		if err := ctx.Err(); err != nil {
+			return nil, goerr.Wrap(err, "fetch canceled", goerr.V("source_id", sourceID))
+		}
+		reportProgress(ctx, len(newArticles), i, stats.IoCsExtracted)
+
+		articleCtx, articleSpan := tracing.Start(ctx, "fetch.article", attribute.String("article.url", article.Link))
+
 		// Fetch article content
-		content, err := uc.rssService.FetchArticleContent(ctx, article.Link)
+		content, err := uc.rssService.FetchArticleContent(articleCtx, article.Link)
 		if err != nil {
 			logger.Warn("failed to fetch article content",
 				"source_id", sourceID,
@@ -205,11 +366,12 @@ func (uc *FetchUseCase) fetchRSS(ctx context.Context, sourceID string, source *m
 				"error", err)
 			stats.ErrorCount++
 			fetchErrors = append(fetchErrors, model.ExtractErrorInfo(err))
+			tracing.End(articleSpan, err)
 			continue
 		}
 
-		// Extract IoCs from article using LLM
-		extracted, err := uc.extractor.ExtractFromArticle(ctx, article.Title, content)
+		// Extract IoCs from article
+		result, err := uc.extract(articleCtx, ex, sourceID, extractionMode, article.Title, content)
 		if err != nil {
 			logger.Warn("failed to extract IoCs from article",
 				"source_id", sourceID,
@@ -217,10 +379,26 @@ func (uc *FetchUseCase) fetchRSS(ctx context.Context, sourceID string, source *m
 				"error", err)
 			stats.ErrorCount++
 			fetchErrors = append(fetchErrors, model.ExtractErrorInfo(err))
+			tracing.End(articleSpan, err)
 			continue
 		}
 
+		extracted := result.IoCs
 		stats.IoCsExtracted += len(extracted)
+		if len(result.Unverified) > 0 {
+			values := make([]string, 0, len(result.Unverified))
+			for _, ext := range result.Unverified {
+				values = append(values, ext.Value)
+			}
+			logger.Warn("dropped extracted IoCs not found in article",
+				"source_id", sourceID,
+				"url", article.Link,
+				"values", values)
+			stats.IoCsUnverified += len(result.Unverified)
+		}
+
+		// Store the article as a report that its IoCs link to
+		reportID := uc.saveReport(articleCtx, sourceID, article, content, extractionMode, stats, &fetchErrors)
 
 		// Track IoCs for this article
 		var articleIoCs []*model.IoC
@@ -244,23 +422,18 @@ func (uc *FetchUseCase) fetchRSS(ctx context.Context, sourceID string, source *m
 				continue
 			}
 
-			// Generate embedding
-			embedText := ioc.Value + " " + ioc.Description
-			embedding, err := uc.extractor.GenerateEmbedding(ctx, embedText)
-			if err != nil {
-				logger.Warn("failed to generate embedding",
-					"source_id", sourceID,
-					"ioc_id", ioc.ID,
-					"error", err)
-				// Continue without embedding
-			} else {
-				copy(ioc.Embedding, embedding)
-			}
+			ioc.LastSeenAt = startTime
+			ioc.ExpiresAt = uc.ttlPolicy.ExpiresAt(source.TTL, ioc.Type, startTime)
+			ioc.ExtractedByLLM = extractionMode.UsesLLM()
+			ioc.ReportID = reportID
+			ioc.Context = article.Title
 
 			articleIoCs = append(articleIoCs, ioc)
 			iocsToSave = append(iocsToSave, ioc)
 		}
 
+		articleSpan.SetAttributes(attribute.Int("ioc.count", len(articleIoCs)))
+
 		// Log extracted IoCs for this article at Debug level
 		if len(articleIoCs) > 0 {
 			iocSummary := make([]map[string]string, 0, len(articleIoCs))
@@ -278,11 +451,23 @@ func (uc *FetchUseCase) fetchRSS(ctx context.Context, sourceID string, source *m
 				"ioc_count", len(articleIoCs),
 				"iocs", iocSummary)
 		}
+		articleSpan.End()
+	}
+
+	reportProgress(ctx, len(newArticles), len(newArticles), stats.IoCsExtracted)
+
+	if err := ctx.Err(); err != nil {
+		return nil, goerr.Wrap(err, "fetch canceled", goerr.V("source_id", sourceID))
 	}
 
 	// Batch save all IoCs
+	reportPhase(ctx, model.JobPhaseSave)
+	var findings FetchFindings
 	if len(iocsToSave) > 0 {
+		uc.scoreIoCs(ctx, sourceID, source, iocsToSave, startTime)
+
 		result, err := uc.repo.BatchUpsertIoCs(ctx, iocsToSave)
+		findings.Created = selectIoCs(iocsToSave, result.CreatedIDs)
 		stats.IoCsCreated += result.Created
 		stats.IoCsUpdated += result.Updated
 		stats.IoCsUnchanged += result.Unchanged
@@ -295,6 +480,8 @@ func (uc *FetchUseCase) fetchRSS(ctx context.Context, sourceID string, source *m
 			stats.ErrorCount++
 			fetchErrors = append(fetchErrors, model.ExtractErrorInfo(err))
 		}
+		uc.recordStatusChanges(ctx, result.StatusChanges, model.FetchActor(historyID), "updated by source")
+		uc.embedSaved(ctx, sourceID, iocsToSave)
 
 		logger.Info("batch saved IoCs",
 			"source_id", sourceID,
@@ -302,6 +489,12 @@ func (uc *FetchUseCase) fetchRSS(ctx context.Context, sourceID string, source *m
 			"updated", result.Updated,
 			"unchanged", result.Unchanged,
 			"total", len(iocsToSave))
+
+		// Flag newly ingested IoCs matching watchlists or imitating protected domains
+		findings.WatchlistHits = uc.evaluateWatchlists(ctx, sourceID, findings.Created)
+		stats.WatchlistHits = len(findings.WatchlistHits)
+		findings.BrandMatches = uc.detectLookalikes(ctx, sourceID, findings.Created)
+		stats.BrandMatches = len(findings.BrandMatches)
 	}
 
 	// Update source state
@@ -339,7 +532,7 @@ func (uc *FetchUseCase) fetchRSS(ctx context.Context, sourceID string, source *m
 
 	// Save ingestion history
 	history := &model.History{
-		ID:             model.GenerateHistoryID(),
+		ID:             historyID,
 		SourceID:       sourceID,
 		SourceType:     model.SourceTypeRSS,
 		Status:         model.DetermineFetchStatus(stats.ErrorCount, stats.ItemsFetched),
@@ -349,11 +542,15 @@ func (uc *FetchUseCase) fetchRSS(ctx context.Context, sourceID string, source *m
 		URLs:           []string{}, // TODO: track accessed URLs in usecase
 		ItemsFetched:   stats.ItemsFetched,
 		IoCsExtracted:  stats.IoCsExtracted,
+		IoCsUnverified: stats.IoCsUnverified,
 		IoCsCreated:    stats.IoCsCreated,
 		IoCsUpdated:    stats.IoCsUpdated,
 		IoCsUnchanged:  stats.IoCsUnchanged,
+		WatchlistHits:  stats.WatchlistHits,
+		BrandMatches:   stats.BrandMatches,
 		ErrorCount:     stats.ErrorCount,
 		Errors:         fetchErrors,
+		TraceID:        tracing.TraceID(ctx),
 		CreatedAt:      time.Now(),
 	}
 
@@ -371,13 +568,69 @@ func (uc *FetchUseCase) fetchRSS(ctx context.Context, sourceID string, source *m
 			"error", err)
 	}
 
+	uc.notify(ctx, history, &findings)
+
 	return history, nil
 }
 
+// saveReport analyzes an article and stores it as a report. If the analysis fails,
+// the report is stored with the CVE and ATT&CK IDs found in the text only.
+// Returns the report ID, or an empty string if the report could not be stored.
+func (uc *FetchUseCase) saveReport(ctx context.Context, sourceID string, article *rss.Article, content string, mode model.ExtractionMode, stats *FetchStats, fetchErrors *[]*model.FetchError) string {
+	logger := logging.From(ctx)
+
+	analysis, err := uc.extractor.AnalyzeReport(ctx, mode, article.Title, content)
+	if err != nil {
+		logger.Warn("failed to analyze report",
+			"source_id", sourceID,
+			"url", article.Link,
+			"error", err)
+		stats.ErrorCount++
+		*fetchErrors = append(*fetchErrors, model.ExtractErrorInfo(err))
+
+		if analysis, err = uc.extractor.AnalyzeReport(ctx, model.ExtractionModeRegex, article.Title, content); err != nil {
+			return ""
+		}
+	}
+
+	guid := article.GUID
+	if guid == "" {
+		guid = article.Link
+	}
+	report := &model.Report{
+		ID:               model.GenerateReportID(sourceID, guid),
+		SourceID:         sourceID,
+		Title:            article.Title,
+		URL:              article.Link,
+		GUID:             article.GUID,
+		PublishedAt:      article.PublishedAt,
+		Content:          model.TruncateReportContent(content),
+		Summary:          analysis.Summary,
+		ThreatActors:     analysis.ThreatActors,
+		MalwareFamilies:  analysis.MalwareFamilies,
+		Sectors:          analysis.Sectors,
+		CVEs:             analysis.CVEs,
+		AttackTechniques: analysis.AttackTechniques,
+	}
+
+	if err := uc.repo.PutReport(ctx, report); err != nil {
+		logger.Error("failed to save report",
+			"source_id", sourceID,
+			"url", article.Link,
+			"error", err)
+		stats.ErrorCount++
+		*fetchErrors = append(*fetchErrors, model.ExtractErrorInfo(err))
+		return ""
+	}
+
+	return report.ID
+}
+
 // fetchFeed fetches and processes IoCs from a threat intelligence feed
 func (uc *FetchUseCase) fetchFeed(ctx context.Context, sourceID string, source *model.Source) (*model.History, error) {
 	logger := logging.From(ctx)
 	startTime := time.Now()
+	historyID := model.GenerateHistoryID()
 	stats := &FetchStats{
 		SourceID:   sourceID,
 		SourceType: string(model.SourceTypeFeed),
@@ -406,6 +659,7 @@ func (uc *FetchUseCase) fetchFeed(ctx context.Context, sourceID string, source *
 	}
 
 	// Fetch feed entries
+	reportPhase(ctx, model.JobPhaseFetch)
 	entries, err := uc.feedService.FetchFeed(ctx, source.URL, source.FeedConfig.Schema)
 	if err != nil {
 		return nil, goerr.Wrap(err, "failed to fetch feed",
@@ -448,6 +702,8 @@ func (uc *FetchUseCase) fetchFeed(ctx context.Context, sourceID string, source *
 	var iocsToSave []*model.IoC
 
 	// Process each feed entry
+	reportPhase(ctx, model.JobPhaseExtract)
+	reportProgress(ctx, len(entries), 0, 0)
 	for _, entry := range entries {
 		// Prepare context parameters for threat feeds
 		// Use entry ID as primary context for deduplication
@@ -469,29 +725,31 @@ func (uc *FetchUseCase) fetchFeed(ctx context.Context, sourceID string, source *
 			Description: entry.Description,
 			SourceURL:   source.URL,
 			Context:     "", // Feeds don't have context
-			Embedding:   make([]float32, model.EmbeddingDimension),
 			Status:      model.IoCStatusActive,
-		}
+			LastSeenAt:  startTime,
+			ExpiresAt:   uc.ttlPolicy.ExpiresAt(source.TTL, entry.Type, startTime),
 
-		// Generate embedding
-		embedText := ioc.Value + " " + ioc.Description
-		embedding, err := uc.extractor.GenerateEmbedding(ctx, embedText)
-		if err != nil {
-			logger.Warn("failed to generate embedding",
-				"source_id", sourceID,
-				"ioc_id", ioc.ID,
-				"error", err)
-		} else {
-			copy(ioc.Embedding, embedding)
+			SourceConfidence: entry.Confidence,
 		}
 
 		iocsToSave = append(iocsToSave, ioc)
 		stats.IoCsExtracted++
 	}
 
+	reportProgress(ctx, len(entries), len(entries), stats.IoCsExtracted)
+
+	if err := ctx.Err(); err != nil {
+		return nil, goerr.Wrap(err, "fetch canceled", goerr.V("source_id", sourceID))
+	}
+
 	// Batch save all active IoCs
+	reportPhase(ctx, model.JobPhaseSave)
+	var findings FetchFindings
 	if len(iocsToSave) > 0 {
+		uc.scoreIoCs(ctx, sourceID, source, iocsToSave, startTime)
+
 		result, err := uc.repo.BatchUpsertIoCs(ctx, iocsToSave)
+		findings.Created = selectIoCs(iocsToSave, result.CreatedIDs)
 		stats.IoCsCreated += result.Created
 		stats.IoCsUpdated += result.Updated
 		stats.IoCsUnchanged += result.Unchanged
@@ -504,6 +762,8 @@ func (uc *FetchUseCase) fetchFeed(ctx context.Context, sourceID string, source *
 			stats.ErrorCount++
 			fetchErrors = append(fetchErrors, model.ExtractErrorInfo(err))
 		}
+		uc.recordStatusChanges(ctx, result.StatusChanges, model.FetchActor(historyID), "updated by source")
+		uc.embedSaved(ctx, sourceID, iocsToSave)
 
 		logger.Info("batch saved IoCs",
 			"source_id", sourceID,
@@ -511,6 +771,12 @@ func (uc *FetchUseCase) fetchFeed(ctx context.Context, sourceID string, source *
 			"updated", result.Updated,
 			"unchanged", result.Unchanged,
 			"total", len(iocsToSave))
+
+		// Flag newly ingested IoCs matching watchlists or imitating protected domains
+		findings.WatchlistHits = uc.evaluateWatchlists(ctx, sourceID, findings.Created)
+		stats.WatchlistHits = len(findings.WatchlistHits)
+		findings.BrandMatches = uc.detectLookalikes(ctx, sourceID, findings.Created)
+		stats.BrandMatches = len(findings.BrandMatches)
 	}
 
 	// Mark IoCs no longer in feed as inactive
@@ -534,6 +800,7 @@ func (uc *FetchUseCase) fetchFeed(ctx context.Context, sourceID string, source *
 			stats.ErrorCount++
 			fetchErrors = append(fetchErrors, model.ExtractErrorInfo(err))
 		}
+		uc.recordStatusChanges(ctx, inactiveCount.StatusChanges, model.FetchActor(historyID), "no longer present in source")
 
 		logger.Info("batch marked IoCs as inactive",
 			"source_id", sourceID,
@@ -571,7 +838,7 @@ func (uc *FetchUseCase) fetchFeed(ctx context.Context, sourceID string, source *
 
 	// Save ingestion history
 	history := &model.History{
-		ID:             model.GenerateHistoryID(),
+		ID:             historyID,
 		SourceID:       sourceID,
 		SourceType:     model.SourceTypeFeed,
 		Status:         model.DetermineFetchStatus(stats.ErrorCount, stats.ItemsFetched),
@@ -584,8 +851,11 @@ func (uc *FetchUseCase) fetchFeed(ctx context.Context, sourceID string, source *
 		IoCsCreated:    stats.IoCsCreated,
 		IoCsUpdated:    stats.IoCsUpdated,
 		IoCsUnchanged:  stats.IoCsUnchanged,
+		WatchlistHits:  stats.WatchlistHits,
+		BrandMatches:   stats.BrandMatches,
 		ErrorCount:     stats.ErrorCount,
 		Errors:         fetchErrors,
+		TraceID:        tracing.TraceID(ctx),
 		CreatedAt:      time.Now(),
 	}
 
@@ -603,6 +873,8 @@ func (uc *FetchUseCase) fetchFeed(ctx context.Context, sourceID string, source *
 			"error", err)
 	}
 
+	uc.notify(ctx, history, &findings)
+
 	return history, nil
 }
 
@@ -618,8 +890,10 @@ func (uc *FetchUseCase) FetchSourceByID(ctx context.Context, sourcesMap map[stri
 
 	logger.Info("fetching from source", "source_id", sourceID, "type", source.Type)
 
+	ctx, span := startSourceSpan(ctx, sourceID, &source)
 	var history *model.History
 	var err error
+	defer func() { tracing.End(span, err) }()
 
 	switch source.Type {
 	case model.SourceTypeRSS:
@@ -627,12 +901,18 @@ func (uc *FetchUseCase) FetchSourceByID(ctx context.Context, sourcesMap map[stri
 	case model.SourceTypeFeed:
 		history, err = uc.fetchFeed(ctx, sourceID, &source)
 	default:
-		return nil, goerr.New("unknown source type",
+		err = goerr.New("unknown source type",
 			goerr.V("source_id", sourceID),
 			goerr.V("type", source.Type))
+		return nil, err
 	}
 
 	if err != nil {
+		// A canceled fetch is not a failure of the source
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
 		logger.Error("failed to fetch from source",
 			"source_id", sourceID,
 			"error", err)
@@ -654,6 +934,7 @@ func (uc *FetchUseCase) FetchSourceByID(ctx context.Context, sourcesMap map[stri
 			IoCsUnchanged:  0,
 			ErrorCount:     1,
 			Errors:         []*model.FetchError{model.ExtractErrorInfo(err)},
+			TraceID:        tracing.TraceID(ctx),
 			CreatedAt:      time.Now(),
 		}
 
@@ -664,14 +945,135 @@ func (uc *FetchUseCase) FetchSourceByID(ctx context.Context, sourcesMap map[stri
 				"error", histErr)
 			return nil, goerr.Wrap(histErr, "failed to save fetch history")
 		}
+		uc.notify(ctx, failedHistory, nil)
+		uc.recordFetch(ctx, failedHistory)
 
 		return failedHistory, nil
 	}
 
+	uc.recordFetch(ctx, history)
 	// Return the history created by fetchRSS or fetchFeed
 	return history, nil
 }
 
+// scoreIoCs sets the confidence score of fetched IoCs before they are saved.
+// The reliability of the source definition overrides the policy. Failures are
+// logged and leave the scores unset.
+func (uc *FetchUseCase) scoreIoCs(ctx context.Context, sourceID string, source *model.Source, iocs []*model.IoC, now time.Time) {
+	if err := scoreIoCs(ctx, uc.repo, uc.confidence.WithSourceReliability(sourceID, source.Reliability), iocs, now); err != nil {
+		logging.From(ctx).Warn("failed to score IoCs",
+			"source_id", sourceID,
+			"count", len(iocs),
+			"error", err)
+	}
+}
+
+// embedSaved embeds the saved IoCs without an embedding of the embedder's
+// model and dimension, i.e. new IoCs and IoCs embedded by another model, and
+// stores the embeddings. Unchanged IoCs keep their stored embedding. Failures
+// are logged and leave the IoCs without embedding.
+func (uc *FetchUseCase) embedSaved(ctx context.Context, sourceID string, iocs []*model.IoC) {
+	var targets []*model.IoC
+	for _, ioc := range iocs {
+		if !ioc.EmbeddedWith(uc.embedder.Model(), uc.embedder.Dimension()) {
+			targets = append(targets, ioc)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	if err := embedIoCs(ctx, uc.embedder, targets); err != nil {
+		logging.From(ctx).Warn("failed to generate embeddings",
+			"source_id", sourceID,
+			"error", err)
+		// Continue with the IoCs embedded before the failure
+	}
+
+	embeddings := make(map[string]*model.IoCEmbedding, len(targets))
+	for _, ioc := range targets {
+		if ioc.EmbeddedWith(uc.embedder.Model(), uc.embedder.Dimension()) {
+			embeddings[ioc.ID] = &model.IoCEmbedding{Vector: ioc.Embedding, Model: ioc.EmbeddingModel}
+		}
+	}
+	if err := uc.repo.UpdateIoCEmbeddings(ctx, embeddings); err != nil {
+		logging.From(ctx).Warn("failed to save embeddings",
+			"source_id", sourceID,
+			"count", len(embeddings),
+			"error", err)
+	}
+}
+
+// recordFetch appends the audit record of a completed fetch of a source and
+// records its statistics in metrics. The actor is the user who requested the
+// fetch, or ActorFetcher for scheduled runs.
+func (uc *FetchUseCase) recordFetch(ctx context.Context, history *model.History) {
+	if uc.metrics != nil {
+		uc.metrics.ObserveFetch(history)
+	}
+
+	actor := model.ActorFetcher
+	if p := model.PrincipalFrom(ctx); p != nil {
+		actor = p.Actor()
+	}
+	uc.audit.Record(ctx, actor, model.AuditFetchRun, model.AuditTargetSource, history.SourceID, nil, &fetchRunSummary{
+		HistoryID:     history.ID,
+		Status:        history.Status,
+		ItemsFetched:  history.ItemsFetched,
+		IoCsExtracted: history.IoCsExtracted,
+		IoCsCreated:   history.IoCsCreated,
+		IoCsUpdated:   history.IoCsUpdated,
+		IoCsUnchanged: history.IoCsUnchanged,
+		ErrorCount:    history.ErrorCount,
+	})
+}
+
+// recordStatusChanges saves status changes reported by a batch upsert as transitions.
+// Failures are logged and do not fail the fetch.
+func (uc *FetchUseCase) recordStatusChanges(ctx context.Context, changes []*model.IoCStatusTransition, actor, reason string) {
+	if len(changes) == 0 {
+		return
+	}
+
+	for _, tr := range changes {
+		tr.Actor = actor
+		tr.Reason = reason
+	}
+
+	if err := uc.repo.SaveStatusTransitions(ctx, changes); err != nil {
+		logging.From(ctx).Error("failed to save IoC status transitions",
+			"actor", actor,
+			"count", len(changes),
+			"error", err)
+	}
+}
+
+// notify evaluates notification rules for a completed fetch, if a notifier is set
+func (uc *FetchUseCase) notify(ctx context.Context, history *model.History, findings *FetchFindings) {
+	if uc.notifier == nil {
+		return
+	}
+	uc.notifier.NotifyFetchResult(ctx, history, findings)
+}
+
+// selectIoCs returns the IoCs whose IDs are in ids
+func selectIoCs(iocs []*model.IoC, ids []string) []*model.IoC {
+	if len(ids) == 0 {
+		return nil
+	}
+	idSet := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		idSet[id] = true
+	}
+	var selected []*model.IoC
+	for _, ioc := range iocs {
+		if idSet[ioc.ID] {
+			selected = append(selected, ioc)
+		}
+	}
+	return selected
+}
+
 // hasAnyTag checks if slice a contains any element from slice b
 func hasAnyTag(sourceTags, filterTags []string) bool {
 	for _, filterTag := range filterTags {
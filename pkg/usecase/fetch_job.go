@@ -0,0 +1,340 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+)
+
+// ErrJobNotFound is returned for unknown or already pruned job IDs
+var ErrJobNotFound = goerr.New("job not found")
+
+const (
+	defaultJobConcurrency = 2
+	defaultJobRetention   = time.Hour
+)
+
+// FetchJobUseCase runs fetches of single sources in the background. Jobs are
+// kept in memory by the process running them; finished jobs are pruned after
+// the retention period.
+type FetchJobUseCase struct {
+	fetch     *FetchUseCase
+	retention time.Duration
+	slots     chan struct{} // Limits the number of running jobs
+
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	jobs map[string]*fetchJob
+}
+
+type fetchJob struct {
+	job         model.Job
+	cancel      context.CancelFunc
+	canceled    bool // Cancel requested, the job finishes soon
+	subscribers map[chan *model.Job]struct{}
+}
+
+// FetchJobOption configures FetchJobUseCase
+type FetchJobOption func(*FetchJobUseCase)
+
+// WithJobConcurrency sets the number of jobs running at the same time;
+// further jobs are queued
+func WithJobConcurrency(n int) FetchJobOption {
+	return func(uc *FetchJobUseCase) {
+		if n > 0 {
+			uc.slots = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithJobRetention sets how long finished jobs can be queried
+func WithJobRetention(d time.Duration) FetchJobOption {
+	return func(uc *FetchJobUseCase) {
+		if d > 0 {
+			uc.retention = d
+		}
+	}
+}
+
+// NewFetchJobUseCase creates a job runner of fetch
+func NewFetchJobUseCase(fetch *FetchUseCase, opts ...FetchJobOption) *FetchJobUseCase {
+	uc := &FetchJobUseCase{
+		fetch:     fetch,
+		retention: defaultJobRetention,
+		slots:     make(chan struct{}, defaultJobConcurrency),
+		jobs:      make(map[string]*fetchJob),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Start queues a fetch of the source and returns the job without waiting for
+// it. If the source already has a queued or running job, that job is returned
+// instead of starting another one. The job outlives ctx; it is stopped by
+// Cancel or Shutdown.
+func (uc *FetchJobUseCase) Start(ctx context.Context, sources map[string]model.Source, sourceID string) (*model.Job, error) {
+	if _, ok := sources[sourceID]; !ok {
+		return nil, goerr.Wrap(interfaces.ErrSourceNotFound, "failed to start fetch job", goerr.V("source_id", sourceID))
+	}
+
+	now := time.Now()
+	uc.mu.Lock()
+	uc.prune(now)
+	if active := uc.activeJob(sourceID); active != nil {
+		uc.mu.Unlock()
+		logging.From(ctx).Info("fetch job already active", "job_id", active.ID, "source_id", sourceID)
+		return active, nil
+	}
+
+	job := model.NewJob(sourceID, now)
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	jobCtx = withFetchProgress(jobCtx, func(update func(job *model.Job)) {
+		uc.update(job.ID, update)
+	})
+	uc.jobs[job.ID] = &fetchJob{
+		job:         *job,
+		cancel:      cancel,
+		subscribers: make(map[chan *model.Job]struct{}),
+	}
+	uc.mu.Unlock()
+
+	uc.fetch.audit.Record(ctx, model.ActorFrom(ctx), model.AuditFetchStart, model.AuditTargetJob, job.ID, nil, job)
+
+	uc.wg.Add(1)
+	go func() {
+		defer uc.wg.Done()
+		defer cancel()
+		uc.run(jobCtx, job.ID, sources, sourceID)
+	}()
+
+	return job, nil
+}
+
+func (uc *FetchJobUseCase) run(ctx context.Context, id string, sources map[string]model.Source, sourceID string) {
+	select {
+	case uc.slots <- struct{}{}:
+		defer func() { <-uc.slots }()
+	case <-ctx.Done():
+		uc.finish(ctx, id, nil, ctx.Err())
+		return
+	}
+
+	uc.update(id, func(job *model.Job) {
+		job.Status = model.JobStatusRunning
+		job.StartedAt = time.Now()
+	})
+
+	history, err := uc.fetch.FetchSourceByID(ctx, sources, sourceID)
+	uc.finish(ctx, id, history, err)
+}
+
+func (uc *FetchJobUseCase) finish(ctx context.Context, id string, history *model.History, err error) {
+	logger := logging.From(ctx)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	fj, ok := uc.jobs[id]
+	if !ok {
+		return
+	}
+	job := &fj.job
+	job.FinishedAt = time.Now()
+	if history != nil {
+		job.HistoryID = history.ID
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		job.Status = model.JobStatusCanceled
+	case err != nil:
+		job.Status = model.JobStatusFailed
+		job.Error = err.Error()
+	case history.Status == model.FetchStatusFailure && len(history.Errors) > 0:
+		job.Status = model.JobStatusFailed
+		job.Error = history.Errors[0].Message
+	default:
+		job.Status = model.JobStatusSucceeded
+	}
+
+	logger.Info("fetch job finished",
+		"job_id", job.ID,
+		"source_id", job.SourceID,
+		"status", job.Status,
+		"history_id", job.HistoryID,
+		"error", job.Error)
+
+	snapshot := *job
+	for ch := range fj.subscribers {
+		publishJob(ch, &snapshot)
+		close(ch)
+	}
+	fj.subscribers = nil
+}
+
+// update applies a change to a job and notifies its subscribers
+func (uc *FetchJobUseCase) update(id string, update func(job *model.Job)) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	fj, ok := uc.jobs[id]
+	if !ok || fj.job.Status.Done() {
+		return
+	}
+	update(&fj.job)
+
+	snapshot := fj.job
+	for ch := range fj.subscribers {
+		publishJob(ch, &snapshot)
+	}
+}
+
+// publishJob sends the latest state of a job to a subscriber. A subscriber
+// lagging behind gets only the latest state. Callers hold the lock, so the
+// send never blocks.
+func publishJob(ch chan *model.Job, job *model.Job) {
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- job
+}
+
+// activeJob returns the queued or running job of the source that is not being
+// canceled, or nil. Callers hold the lock.
+func (uc *FetchJobUseCase) activeJob(sourceID string) *model.Job {
+	for _, fj := range uc.jobs {
+		if fj.job.SourceID == sourceID && !fj.job.Status.Done() && !fj.canceled {
+			job := fj.job
+			return &job
+		}
+	}
+	return nil
+}
+
+// prune removes finished jobs older than the retention period. Callers hold
+// the lock.
+func (uc *FetchJobUseCase) prune(now time.Time) {
+	for id, fj := range uc.jobs {
+		if fj.job.Status.Done() && now.Sub(fj.job.FinishedAt) > uc.retention {
+			delete(uc.jobs, id)
+		}
+	}
+}
+
+// GetJob returns the current state of a job
+func (uc *FetchJobUseCase) GetJob(ctx context.Context, id string) (*model.Job, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	fj, ok := uc.jobs[id]
+	if !ok {
+		return nil, goerr.Wrap(ErrJobNotFound, "failed to get job", goerr.V("job_id", id))
+	}
+	job := fj.job
+	return &job, nil
+}
+
+// CancelJob stops a queued or running job. The job becomes canceled once its
+// fetch returns; canceling a finished job has no effect.
+func (uc *FetchJobUseCase) CancelJob(ctx context.Context, id string) (*model.Job, error) {
+	uc.mu.Lock()
+	fj, ok := uc.jobs[id]
+	if !ok {
+		uc.mu.Unlock()
+		return nil, goerr.Wrap(ErrJobNotFound, "failed to cancel job", goerr.V("job_id", id))
+	}
+	job := fj.job
+	if !job.Status.Done() {
+		fj.canceled = true
+	}
+	uc.mu.Unlock()
+
+	if !job.Status.Done() {
+		logging.From(ctx).Info("canceling fetch job", "job_id", id, "source_id", job.SourceID)
+		fj.cancel()
+		uc.fetch.audit.Record(ctx, model.ActorFrom(ctx), model.AuditFetchCancel, model.AuditTargetJob, id, nil, &job)
+	}
+	return &job, nil
+}
+
+// SubscribeJob streams the states of a job, starting with the current one,
+// until the job finishes or ctx is done. Slow readers skip intermediate
+// states but always receive the final one.
+func (uc *FetchJobUseCase) SubscribeJob(ctx context.Context, id string) (<-chan *model.Job, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	fj, ok := uc.jobs[id]
+	if !ok {
+		return nil, goerr.Wrap(ErrJobNotFound, "failed to subscribe job", goerr.V("job_id", id))
+	}
+
+	ch := make(chan *model.Job, 1)
+	snapshot := fj.job
+	ch <- &snapshot
+	if snapshot.Status.Done() {
+		close(ch)
+		return ch, nil
+	}
+
+	fj.subscribers[ch] = struct{}{}
+	go func() {
+		<-ctx.Done()
+		uc.mu.Lock()
+		defer uc.mu.Unlock()
+		if _, ok := fj.subscribers[ch]; ok {
+			delete(fj.subscribers, ch)
+			close(ch)
+		}
+	}()
+
+	return ch, nil
+}
+
+// Shutdown cancels all jobs and waits for them to stop
+func (uc *FetchJobUseCase) Shutdown() {
+	uc.mu.Lock()
+	for _, fj := range uc.jobs {
+		fj.cancel()
+	}
+	uc.mu.Unlock()
+
+	uc.wg.Wait()
+}
+
+type fetchProgressKey struct{}
+
+// fetchProgress applies progress of a fetch to its job
+type fetchProgress func(update func(job *model.Job))
+
+func withFetchProgress(ctx context.Context, progress fetchProgress) context.Context {
+	return context.WithValue(ctx, fetchProgressKey{}, progress)
+}
+
+// reportPhase records the phase of a fetch run as a job
+func reportPhase(ctx context.Context, phase model.JobPhase) {
+	if progress, ok := ctx.Value(fetchProgressKey{}).(fetchProgress); ok {
+		progress(func(job *model.Job) {
+			job.Phase = phase
+		})
+	}
+}
+
+// reportProgress records the items processed by a fetch run as a job
+func reportProgress(ctx context.Context, total, processed, extracted int) {
+	if progress, ok := ctx.Value(fetchProgressKey{}).(fetchProgress); ok {
+		progress(func(job *model.Job) {
+			job.ItemsTotal = total
+			job.ItemsProcessed = processed
+			job.IoCsExtracted = extracted
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+// newBlockingBlogServer serves a blog whose article is returned only after
+// release is called; requested receives a value when the article is requested
+func newBlockingBlogServer(t *testing.T) (server *httptest.Server, requested <-chan struct{}, release func()) {
+	t.Helper()
+	mux := http.NewServeMux()
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	req := make(chan struct{}, 10)
+	released := make(chan struct{})
+	var once sync.Once
+	release = func() { once.Do(func() { close(released) }) }
+	t.Cleanup(release)
+
+	mux.HandleFunc("/feed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = fmt.Fprintf(w, `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Blog</title>
+<item><title>Campaign analysis</title><link>%s/post/1</link><guid>post-1</guid>
+<pubDate>Wed, 24 Dec 2025 07:24:23 GMT</pubDate></item>
+</channel></rss>`, server.URL)
+	})
+	mux.HandleFunc("/post/1", func(w http.ResponseWriter, r *http.Request) {
+		req <- struct{}{}
+		select {
+		case <-released:
+		case <-r.Context().Done():
+			return
+		}
+		_, _ = fmt.Fprint(w, `<html><body><article>The payload beacons to 198.51.100[.]42.</article></body></html>`)
+	})
+
+	return server, req, release
+}
+
+func blogSources(url string) map[string]model.Source {
+	return map[string]model.Source{
+		"blog": *newRSSSource("blog", url+"/feed"),
+	}
+}
+
+// waitJob reads job states until cond holds
+func waitJob(t *testing.T, ch <-chan *model.Job, cond func(job *model.Job) bool) *model.Job {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case job, ok := <-ch:
+			if !ok {
+				t.Fatal("job subscription closed")
+			}
+			if cond(job) {
+				return job
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for job")
+		}
+	}
+}
+
+func waitRequested(t *testing.T, requested <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-requested:
+	case <-time.After(5 * time.Second):
+		t.Fatal("article was not requested")
+	}
+}
+
+func TestFetchJobUseCase(t *testing.T) {
+	ctx := context.Background()
+	server, requested, release := newBlockingBlogServer(t)
+	sources := blogSources(server.URL)
+
+	repo := memory.New()
+	jobs := usecase.NewFetchJobUseCase(usecase.NewFetchUseCase(repo, nil), usecase.WithJobConcurrency(1))
+	defer jobs.Shutdown()
+
+	job, err := jobs.Start(ctx, sources, "blog")
+	gt.NoError(t, err)
+	gt.NotEqual(t, job.ID, "")
+	gt.Equal(t, job.SourceID, "blog")
+
+	progress, err := jobs.SubscribeJob(ctx, job.ID)
+	gt.NoError(t, err)
+
+	waitRequested(t, requested)
+	running := waitJob(t, progress, func(job *model.Job) bool {
+		return job.Phase == model.JobPhaseExtract
+	})
+	gt.Equal(t, running.Status, model.JobStatusRunning)
+	gt.Equal(t, running.ItemsTotal, 1)
+	gt.Equal(t, running.ItemsProcessed, 0)
+	gt.False(t, running.StartedAt.IsZero())
+
+	t.Run("running source is not fetched twice", func(t *testing.T) {
+		again, err := jobs.Start(ctx, sources, "blog")
+		gt.NoError(t, err)
+		gt.Equal(t, again.ID, job.ID)
+		gt.Equal(t, again.Status, model.JobStatusRunning)
+	})
+
+	t.Run("jobs over the concurrency limit are queued", func(t *testing.T) {
+		sources := blogSources(server.URL)
+		sources["mirror"] = *newRSSSource("mirror", server.URL+"/feed")
+		queued, err := jobs.Start(ctx, sources, "mirror")
+		gt.NoError(t, err)
+
+		// The queued job is returned while it waits
+		again, err := jobs.Start(ctx, sources, "mirror")
+		gt.NoError(t, err)
+		gt.Equal(t, again.ID, queued.ID)
+
+		got, err := jobs.GetJob(ctx, queued.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, got.Status, model.JobStatusQueued)
+
+		canceled, err := jobs.CancelJob(ctx, queued.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, canceled.ID, queued.ID)
+
+		ch, err := jobs.SubscribeJob(ctx, queued.ID)
+		gt.NoError(t, err)
+		final := waitJob(t, ch, func(job *model.Job) bool { return job.Status.Done() })
+		gt.Equal(t, final.Status, model.JobStatusCanceled)
+		gt.True(t, final.StartedAt.IsZero())
+	})
+
+	release()
+	final := waitJob(t, progress, func(job *model.Job) bool { return job.Status.Done() })
+	gt.Equal(t, final.Status, model.JobStatusSucceeded)
+	gt.Equal(t, final.ItemsProcessed, 1)
+	gt.Equal(t, final.IoCsExtracted, 1)
+	gt.False(t, final.FinishedAt.IsZero())
+
+	history, err := repo.GetHistory(ctx, "blog", final.HistoryID)
+	gt.NoError(t, err)
+	gt.Equal(t, history.IoCsCreated, 1)
+
+	// The subscription ends with the job
+	_, ok := <-progress
+	gt.False(t, ok)
+
+	t.Run("finished jobs stay queryable", func(t *testing.T) {
+		got, err := jobs.GetJob(ctx, job.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, got.Status, model.JobStatusSucceeded)
+
+		ch, err := jobs.SubscribeJob(ctx, job.ID)
+		gt.NoError(t, err)
+		got = <-ch
+		gt.Equal(t, got.Status, model.JobStatusSucceeded)
+		_, ok := <-ch
+		gt.False(t, ok)
+	})
+}
+
+func TestFetchJobUseCase_Cancel(t *testing.T) {
+	ctx := context.Background()
+	server, requested, _ := newBlockingBlogServer(t)
+
+	repo := memory.New()
+	jobs := usecase.NewFetchJobUseCase(usecase.NewFetchUseCase(repo, nil))
+	defer jobs.Shutdown()
+
+	job, err := jobs.Start(ctx, blogSources(server.URL), "blog")
+	gt.NoError(t, err)
+	progress, err := jobs.SubscribeJob(ctx, job.ID)
+	gt.NoError(t, err)
+
+	waitRequested(t, requested)
+	_, err = jobs.CancelJob(ctx, job.ID)
+	gt.NoError(t, err)
+
+	final := waitJob(t, progress, func(job *model.Job) bool { return job.Status.Done() })
+	gt.Equal(t, final.Status, model.JobStatusCanceled)
+	gt.Equal(t, final.HistoryID, "")
+
+	// Nothing is recorded for a canceled fetch, so the article is fetched again
+	_, err = repo.GetState(ctx, "blog")
+	gt.True(t, errors.Is(err, interfaces.ErrSourceStateNotFound))
+}
+
+func TestFetchJobUseCase_NotFound(t *testing.T) {
+	ctx := context.Background()
+	jobs := usecase.NewFetchJobUseCase(usecase.NewFetchUseCase(memory.New(), nil))
+	defer jobs.Shutdown()
+
+	_, err := jobs.Start(ctx, map[string]model.Source{}, "missing")
+	gt.True(t, errors.Is(err, interfaces.ErrSourceNotFound))
+
+	_, err = jobs.GetJob(ctx, "missing")
+	gt.True(t, errors.Is(err, usecase.ErrJobNotFound))
+	_, err = jobs.CancelJob(ctx, "missing")
+	gt.True(t, errors.Is(err, usecase.ErrJobNotFound))
+	_, err = jobs.SubscribeJob(ctx, "missing")
+	gt.True(t, errors.Is(err, usecase.ErrJobNotFound))
+}
@@ -416,3 +416,35 @@ func TestFetchUseCase_HistorySaving(t *testing.T) {
 		gt.A(t, histories2).Length(1).Describe("source2 should have exactly 1 history entry")
 	})
 }
+
+// fetchMetrics records fetches and ignores other metrics
+type fetchMetrics struct {
+	histories []*model.History
+}
+
+func (m *fetchMetrics) ObserveFetch(history *model.History) {
+	m.histories = append(m.histories, history)
+}
+
+func (m *fetchMetrics) ObserveLLMCall(string, time.Duration, int, int, error) {}
+
+func (m *fetchMetrics) ObserveGraphQLField(string, time.Duration, error) {}
+
+func (m *fetchMetrics) ObserveRepository(string, time.Duration, error) {}
+
+func TestFetchUseCase_Metrics(t *testing.T) {
+	ctx := context.Background()
+	metrics := &fetchMetrics{}
+	uc := usecase.NewFetchUseCase(memory.New(), nil, usecase.WithFetchMetrics(metrics))
+
+	histories, err := uc.FetchAllSources(ctx, map[string]model.Source{
+		"bad-feed": {Type: model.SourceTypeFeed, URL: "http://example.com/feed", Enabled: true},
+	}, nil)
+	gt.NoError(t, err)
+	gt.A(t, histories).Length(1)
+
+	gt.A(t, metrics.histories).Length(1)
+	gt.Equal(t, metrics.histories[0].SourceID, "bad-feed")
+	gt.Equal(t, metrics.histories[0].Status, model.FetchStatusFailure)
+	gt.N(t, metrics.histories[0].ErrorCount).Greater(0)
+}
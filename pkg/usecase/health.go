@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+)
+
+// CheckRepository checks that the repository is reachable
+func (uc *UseCases) CheckRepository(ctx context.Context) error {
+	if err := uc.repo.Ping(ctx); err != nil {
+		return goerr.Wrap(err, "repository is not reachable")
+	}
+	return nil
+}
+
+// SourceStatuses reports the ingestion status of the enabled sources ordered
+// by ID. interval is the expected interval between fetches of a source.
+func (uc *UseCases) SourceStatuses(ctx context.Context, interval time.Duration) ([]*model.SourceStatus, error) {
+	sources, err := uc.ListSources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var enabled []*model.Source
+	var ids []string
+	for _, s := range sources {
+		if s.Enabled {
+			enabled = append(enabled, s)
+			ids = append(ids, s.ID)
+		}
+	}
+	if len(enabled) == 0 {
+		return []*model.SourceStatus{}, nil
+	}
+
+	states, err := uc.repo.BatchGetStates(ctx, ids)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get source states")
+	}
+
+	now := time.Now()
+	statuses := make([]*model.SourceStatus, len(enabled))
+	for i, s := range enabled {
+		statuses[i] = model.NewSourceStatus(s, states[s.ID], interval, now)
+	}
+	return statuses, nil
+}
+
+// CheckLLM returns ErrLLMNotConfigured if enabled RSS sources require an LLM
+// that is not configured and would be skipped
+func (uc *FetchUseCase) CheckLLM(sources map[string]model.Source) error {
+	if uc.llmClient != nil || uc.llmFallback == model.LLMFallbackRegex {
+		return nil
+	}
+
+	var ids []string
+	for id, s := range sources {
+		if !s.Enabled || s.Type != model.SourceTypeRSS {
+			continue
+		}
+		mode := model.ExtractionModeLLM
+		if s.RSSConfig != nil && s.RSSConfig.Extraction != "" {
+			mode = s.RSSConfig.Extraction
+		}
+		if mode.UsesLLM() {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	sort.Strings(ids)
+	return goerr.Wrap(ErrLLMNotConfigured, "RSS sources requiring an LLM are skipped",
+		goerr.V("sources", ids))
+}
@@ -0,0 +1,78 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+func TestSourceStatuses(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	uc := usecase.New(repo)
+
+	for _, id := range []string{"fresh", "failing", "new", "disabled"} {
+		_, err := uc.CreateSource(ctx, newRSSSource(id, "https://"+id+".example/feed"), "alice")
+		gt.NoError(t, err)
+	}
+	_, err := uc.SetSourceEnabled(ctx, "disabled", false, "alice")
+	gt.NoError(t, err)
+
+	gt.NoError(t, repo.SaveState(ctx, &model.SourceState{
+		SourceID: "fresh", LastFetchedAt: time.Now(), LastStatus: string(model.FetchStatusSuccess),
+	}))
+	gt.NoError(t, repo.SaveState(ctx, &model.SourceState{
+		SourceID: "failing", LastFetchedAt: time.Now(), LastStatus: string(model.FetchStatusFailure),
+	}))
+
+	statuses, err := uc.SourceStatuses(ctx, time.Hour)
+	gt.NoError(t, err)
+	gt.A(t, statuses).Length(3)
+	gt.Equal(t, statuses[0].SourceID, "failing")
+	gt.True(t, statuses[0].Stale)
+	gt.Equal(t, statuses[1].SourceID, "fresh")
+	gt.False(t, statuses[1].Stale)
+	// Created just now, so not expected to be fetched yet
+	gt.Equal(t, statuses[2].SourceID, "new")
+	gt.False(t, statuses[2].Stale)
+
+	t.Run("no enabled sources", func(t *testing.T) {
+		statuses, err := usecase.New(memory.New()).SourceStatuses(ctx, time.Hour)
+		gt.NoError(t, err)
+		gt.A(t, statuses).Length(0)
+	})
+}
+
+func TestCheckRepository(t *testing.T) {
+	gt.NoError(t, usecase.New(memory.New()).CheckRepository(context.Background()))
+}
+
+func TestFetchUseCase_CheckLLM(t *testing.T) {
+	sources := map[string]model.Source{
+		"regex": *newRSSSource("regex", "https://regex.example/feed"),
+		"llm":   {Type: model.SourceTypeRSS, URL: "https://llm.example/feed", Enabled: true},
+		"off":   {Type: model.SourceTypeRSS, URL: "https://off.example/feed"},
+		"feed":  {Type: model.SourceTypeFeed, URL: "https://feed.example/csv", Enabled: true},
+	}
+
+	t.Run("skipped sources without LLM", func(t *testing.T) {
+		err := usecase.NewFetchUseCase(memory.New(), nil).CheckLLM(sources)
+		gt.True(t, errors.Is(err, usecase.ErrLLMNotConfigured))
+	})
+
+	t.Run("regex fallback", func(t *testing.T) {
+		uc := usecase.NewFetchUseCase(memory.New(), nil, usecase.WithLLMFallback(model.LLMFallbackRegex))
+		gt.NoError(t, uc.CheckLLM(sources))
+	})
+
+	t.Run("no source requires LLM", func(t *testing.T) {
+		uc := usecase.NewFetchUseCase(memory.New(), nil)
+		gt.NoError(t, uc.CheckLLM(map[string]model.Source{"regex": sources["regex"], "off": sources["off"]}))
+	})
+}
@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/domain/types"
+)
+
+var (
+	// ErrNotManualIoC is returned when an operation is only allowed for analyst-managed IoCs
+	ErrNotManualIoC = goerr.New("operation is only allowed for manual IoCs")
+)
+
+// CreateIoCInput represents an analyst-registered IoC
+type CreateIoCInput struct {
+	Type        model.IoCType
+	Value       string
+	Description string
+	Status      model.IoCStatus // Defaults to active
+	SourceURL   string
+	Context     string
+	Notes       string
+	Tags        []string
+}
+
+// UpdateIoCInput represents analyst changes to an IoC. Nil fields are left unchanged.
+type UpdateIoCInput struct {
+	Description *string
+	Status      *model.IoCStatus
+	Notes       *string
+	Tags        []string // nil = unchanged, empty = clear
+	Reason      string   // Reason recorded with a status change
+
+	// ClearOverride removes the override of an IoC from a source before
+	// Description and Status are applied, restoring the source values
+	ClearOverride bool
+}
+
+// CreateIoC registers an IoC managed by an analyst under the manual source
+func (uc *UseCases) CreateIoC(ctx context.Context, input *CreateIoCInput) (*model.IoC, error) {
+	tags, err := types.NewTags(input.Tags)
+	if err != nil {
+		return nil, goerr.Wrap(err, "invalid tags")
+	}
+
+	status := input.Status
+	if status == "" {
+		status = model.IoCStatusActive
+	}
+
+	value := model.NormalizeValue(input.Type, input.Value)
+	ioc := &model.IoC{
+		ID:          model.GenerateID(model.ManualSourceID, input.Type, value, ""),
+		SourceID:    model.ManualSourceID,
+		SourceType:  string(model.SourceTypeManual),
+		Type:        input.Type,
+		Value:       value,
+		Description: input.Description,
+		SourceURL:   input.SourceURL,
+		Context:     input.Context,
+		Status:      status,
+		Tags:        tags.Strings(),
+		Notes:       input.Notes,
+	}
+
+	if err := model.ValidateIoC(ioc); err != nil {
+		return nil, goerr.Wrap(err, "invalid IoC",
+			goerr.V("type", input.Type),
+			goerr.V("value", input.Value))
+	}
+	if err := model.ValidateValue(ioc.Type, ioc.Value); err != nil {
+		return nil, goerr.Wrap(err, "invalid IoC value")
+	}
+
+	if err := scoreIoCs(ctx, uc.repo, uc.confidencePolicy, []*model.IoC{ioc}, time.Now()); err != nil {
+		return nil, goerr.Wrap(err, "failed to score IoC", goerr.V("id", ioc.ID))
+	}
+
+	// Concurrent creates of the same value are resolved by the repository
+	if err := uc.repo.CreateIoC(ctx, ioc); err != nil {
+		return nil, goerr.Wrap(err, "failed to create IoC",
+			goerr.V("id", ioc.ID),
+			goerr.V("type", ioc.Type),
+			goerr.V("value", ioc.Value))
+	}
+
+	tr := model.NewIoCStatusTransition(ioc.ID, "", ioc.Status, ioc.UpdatedAt)
+	tr.Actor = model.ActorFrom(ctx)
+	tr.Reason = "created by analyst"
+	if err := uc.repo.SaveStatusTransitions(ctx, []*model.IoCStatusTransition{tr}); err != nil {
+		return nil, goerr.Wrap(err, "failed to save status transition", goerr.V("id", ioc.ID))
+	}
+	uc.audit.Record(ctx, tr.Actor, model.AuditIoCCreate, model.AuditTargetIoC, ioc.ID, nil, auditIoC(ioc))
+
+	return ioc, nil
+}
+
+// UpdateIoC applies analyst changes to an IoC.
+// For manual IoCs the fields are changed directly. For IoCs from other sources
+// description and status are recorded as an override so that they survive
+// later refreshes of the source.
+func (uc *UseCases) UpdateIoC(ctx context.Context, id string, input *UpdateIoCInput) (*model.IoC, error) {
+	ioc, err := uc.repo.GetIoC(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get IoC", goerr.V("id", id))
+	}
+	previousStatus := ioc.Status
+	before := auditIoC(ioc)
+
+	if input.Tags != nil {
+		tags, err := types.NewTags(input.Tags)
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid tags", goerr.V("id", id))
+		}
+		ioc.Tags = tags.Strings()
+	}
+	if input.Notes != nil {
+		ioc.Notes = *input.Notes
+	}
+
+	if input.ClearOverride {
+		ioc.ClearOverride()
+	}
+
+	if ioc.SourceType == string(model.SourceTypeManual) {
+		if input.Description != nil {
+			ioc.Description = *input.Description
+		}
+		if input.Status != nil {
+			ioc.Status = *input.Status
+		}
+	} else if input.Description != nil || input.Status != nil {
+		// Copy to avoid mutating the override shared with the stored record
+		override := &model.IoCOverride{
+			SourceDescription: ioc.Description,
+			SourceStatus:      ioc.Status,
+		}
+		if ioc.Override != nil {
+			*override = *ioc.Override
+		}
+		if input.Description != nil {
+			override.Description = *input.Description
+		}
+		if input.Status != nil {
+			override.Status = *input.Status
+		}
+		override.UpdatedAt = time.Now()
+		ioc.Override = override
+		ioc.ApplyOverride()
+	}
+
+	if err := model.ValidateIoC(ioc); err != nil {
+		return nil, goerr.Wrap(err, "invalid IoC", goerr.V("id", id))
+	}
+
+	if err := uc.repo.PutIoC(ctx, ioc); err != nil {
+		return nil, goerr.Wrap(err, "failed to save IoC", goerr.V("id", id))
+	}
+
+	if ioc.Status != previousStatus {
+		tr := model.NewIoCStatusTransition(ioc.ID, previousStatus, ioc.Status, ioc.UpdatedAt)
+		tr.Actor = model.ActorFrom(ctx)
+		tr.Reason = input.Reason
+		if tr.Reason == "" {
+			tr.Reason = "updated by analyst"
+		}
+		if err := uc.repo.SaveStatusTransitions(ctx, []*model.IoCStatusTransition{tr}); err != nil {
+			return nil, goerr.Wrap(err, "failed to save status transition", goerr.V("id", id))
+		}
+	}
+	uc.audit.Record(ctx, model.ActorFrom(ctx), model.AuditIoCUpdate, model.AuditTargetIoC, id, before, auditIoC(ioc))
+
+	return ioc, nil
+}
+
+// DeleteIoC deletes an analyst-managed IoC. IoCs from other sources would be
+// recreated by the next fetch and must be curated through their status instead.
+func (uc *UseCases) DeleteIoC(ctx context.Context, id string) error {
+	ioc, err := uc.repo.GetIoC(ctx, id)
+	if err != nil {
+		return goerr.Wrap(err, "failed to get IoC", goerr.V("id", id))
+	}
+
+	if ioc.SourceType != string(model.SourceTypeManual) {
+		return goerr.Wrap(ErrNotManualIoC, "cannot delete IoC from a source",
+			goerr.V("id", id),
+			goerr.V("source_id", ioc.SourceID))
+	}
+
+	if err := uc.repo.DeleteIoC(ctx, id); err != nil {
+		return goerr.Wrap(err, "failed to delete IoC", goerr.V("id", id))
+	}
+	uc.audit.Record(ctx, model.ActorFrom(ctx), model.AuditIoCDelete, model.AuditTargetIoC, id, auditIoC(ioc), nil)
+
+	return nil
+}
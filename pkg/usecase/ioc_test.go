@@ -0,0 +1,197 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+func TestUseCases_CreateIoC(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("create manual IoC", func(t *testing.T) {
+		repo := memory.New()
+		uc := usecase.New(repo)
+
+		ioc, err := uc.CreateIoC(ctx, &usecase.CreateIoCInput{
+			Type:        model.IoCTypeDomain,
+			Value:       "Evil.Example.COM.",
+			Description: "Phishing domain from incident",
+			Notes:       "INC-1234",
+			Tags:        []string{"incident"},
+		})
+		gt.NoError(t, err)
+		gt.Equal(t, ioc.SourceID, model.ManualSourceID)
+		gt.Equal(t, ioc.SourceType, string(model.SourceTypeManual))
+		gt.Equal(t, ioc.Value, "evil.example.com")
+		gt.Equal(t, ioc.Status, model.IoCStatusActive)
+
+		stored, err := repo.GetIoC(ctx, ioc.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, stored.Notes, "INC-1234")
+		gt.A(t, stored.Tags).Length(1)
+	})
+
+	t.Run("reject duplicate", func(t *testing.T) {
+		repo := memory.New()
+		uc := usecase.New(repo)
+
+		input := &usecase.CreateIoCInput{Type: model.IoCTypeIPv4, Value: "198.51.100.1"}
+		_, err := uc.CreateIoC(ctx, input)
+		gt.NoError(t, err)
+		_, err = uc.CreateIoC(ctx, input)
+		gt.True(t, errors.Is(err, interfaces.ErrIoCExists))
+	})
+
+	t.Run("reject invalid input", func(t *testing.T) {
+		repo := memory.New()
+		uc := usecase.New(repo)
+
+		_, err := uc.CreateIoC(ctx, &usecase.CreateIoCInput{Type: "unknown", Value: "x"})
+		gt.True(t, errors.Is(err, model.ErrInvalidIoCType))
+
+		_, err = uc.CreateIoC(ctx, &usecase.CreateIoCInput{Type: model.IoCTypeIPv4, Value: "198.51.100.1", Status: "bogus"})
+		gt.True(t, errors.Is(err, model.ErrInvalidIoCValue))
+
+		_, err = uc.CreateIoC(ctx, &usecase.CreateIoCInput{Type: model.IoCTypeIPv4, Value: "198.51.100.1", Tags: []string{"-bad"}})
+		gt.Error(t, err)
+	})
+}
+
+func TestUseCases_UpdateIoC(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("override survives feed refresh", func(t *testing.T) {
+		repo := memory.New()
+		uc := usecase.New(repo)
+
+		feedIoC := func() *model.IoC {
+			return &model.IoC{
+				ID:          model.GenerateID("urlhaus", model.IoCTypeURL, "http://evil.example.com/a", "1"),
+				SourceID:    "urlhaus",
+				SourceType:  string(model.SourceTypeFeed),
+				Type:        model.IoCTypeURL,
+				Value:       "http://evil.example.com/a",
+				Description: "Malware download",
+				Status:      model.IoCStatusActive,
+			}
+		}
+		original := feedIoC()
+		_, err := repo.BatchUpsertIoCs(ctx, []*model.IoC{original})
+		gt.NoError(t, err)
+
+		status := model.IoCStatusAllowlisted
+		description := "Internal test server"
+		notes := "Confirmed with infra team"
+		updated, err := uc.UpdateIoC(ctx, original.ID, &usecase.UpdateIoCInput{
+			Status:      &status,
+			Description: &description,
+			Notes:       &notes,
+			Tags:        []string{"internal"},
+		})
+		gt.NoError(t, err)
+		gt.Equal(t, updated.Status, model.IoCStatusAllowlisted)
+		gt.Equal(t, updated.Description, description)
+		gt.NotEqual(t, updated.Override, nil)
+
+		result, err := repo.BatchUpsertIoCs(ctx, []*model.IoC{feedIoC()})
+		gt.NoError(t, err)
+		gt.Equal(t, result.Unchanged, 1)
+
+		stored, err := repo.GetIoC(ctx, original.ID)
+		gt.NoError(t, err)
+		gt.Equal(t, stored.Status, model.IoCStatusAllowlisted)
+		gt.Equal(t, stored.Description, description)
+		gt.Equal(t, stored.Notes, notes)
+		gt.A(t, stored.Tags).Length(1)
+	})
+
+	t.Run("clear override restores source values", func(t *testing.T) {
+		repo := memory.New()
+		uc := usecase.New(repo)
+
+		original := &model.IoC{
+			ID:          model.GenerateID("urlhaus", model.IoCTypeURL, "http://evil.example.com/b", "1"),
+			SourceID:    "urlhaus",
+			SourceType:  string(model.SourceTypeFeed),
+			Type:        model.IoCTypeURL,
+			Value:       "http://evil.example.com/b",
+			Description: "Malware download",
+			Status:      model.IoCStatusActive,
+		}
+		_, err := repo.BatchUpsertIoCs(ctx, []*model.IoC{original})
+		gt.NoError(t, err)
+
+		status := model.IoCStatusFalsePositive
+		_, err = uc.UpdateIoC(ctx, original.ID, &usecase.UpdateIoCInput{Status: &status})
+		gt.NoError(t, err)
+
+		cleared, err := uc.UpdateIoC(ctx, original.ID, &usecase.UpdateIoCInput{ClearOverride: true})
+		gt.NoError(t, err)
+		gt.V(t, cleared.Override).Nil()
+		gt.Equal(t, cleared.Status, model.IoCStatusActive)
+		gt.Equal(t, cleared.Description, "Malware download")
+
+		transitions, err := repo.ListStatusTransitions(ctx, original.ID)
+		gt.NoError(t, err)
+		gt.A(t, transitions).Length(2)
+	})
+
+	t.Run("manual IoC is updated directly", func(t *testing.T) {
+		repo := memory.New()
+		uc := usecase.New(repo)
+
+		created, err := uc.CreateIoC(ctx, &usecase.CreateIoCInput{Type: model.IoCTypeIPv4, Value: "198.51.100.2"})
+		gt.NoError(t, err)
+
+		status := model.IoCStatusRevoked
+		updated, err := uc.UpdateIoC(ctx, created.ID, &usecase.UpdateIoCInput{Status: &status})
+		gt.NoError(t, err)
+		gt.Equal(t, updated.Status, model.IoCStatusRevoked)
+		gt.Equal(t, updated.Override, nil)
+	})
+
+	t.Run("reject invalid status", func(t *testing.T) {
+		repo := memory.New()
+		uc := usecase.New(repo)
+
+		created, err := uc.CreateIoC(ctx, &usecase.CreateIoCInput{Type: model.IoCTypeIPv4, Value: "198.51.100.3"})
+		gt.NoError(t, err)
+
+		status := model.IoCStatus("bogus")
+		_, err = uc.UpdateIoC(ctx, created.ID, &usecase.UpdateIoCInput{Status: &status})
+		gt.Error(t, err)
+	})
+}
+
+func TestUseCases_DeleteIoC(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	uc := usecase.New(repo)
+
+	created, err := uc.CreateIoC(ctx, &usecase.CreateIoCInput{Type: model.IoCTypeSHA256,
+		Value: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"})
+	gt.NoError(t, err)
+
+	gt.NoError(t, uc.DeleteIoC(ctx, created.ID))
+	_, err = repo.GetIoC(ctx, created.ID)
+	gt.True(t, errors.Is(err, interfaces.ErrIoCNotFound))
+
+	feedIoC := &model.IoC{
+		ID:         "feed-ioc",
+		SourceID:   "urlhaus",
+		SourceType: string(model.SourceTypeFeed),
+		Type:       model.IoCTypeIPv4,
+		Value:      "198.51.100.4",
+		Status:     model.IoCStatusActive,
+	}
+	gt.NoError(t, repo.UpsertIoC(ctx, feedIoC))
+	err = uc.DeleteIoC(ctx, feedIoC.ID)
+	gt.True(t, errors.Is(err, usecase.ErrNotManualIoC))
+}
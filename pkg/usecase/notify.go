@@ -0,0 +1,548 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+)
+
+const (
+	defaultNotifyAttempts = 3
+	defaultNotifyBackoff  = 2 * time.Second
+)
+
+// notificationRepository defines the repository methods required by NotificationUseCase
+type notificationRepository interface {
+	interfaces.IoCRepository
+	interfaces.HistoryRepository
+	interfaces.NotificationRepository
+}
+
+// Default templates per rule kind, executed with a model.NotificationEvent
+var defaultNotificationTemplates = map[model.NotificationRuleKind][2]string{
+	model.NotificationRuleSourceFailure: {
+		`[beehive] Source {{.SourceID}} failed {{.ConsecutiveFailures}} times in a row`,
+		`Source {{.SourceID}} failed {{.ConsecutiveFailures}} consecutive fetches.
+{{- with .History}}
+Last attempt: {{.StartedAt.UTC.Format "2006-01-02T15:04:05Z07:00"}}
+{{- range .Errors}}
+- {{.Message}}
+{{- end}}
+{{- end}}`,
+	},
+	model.NotificationRuleWatchlist: {
+		`[beehive] Watchlist {{.Rule}}: {{.Total}} new IoC(s)`,
+		`{{.Total}} new IoC(s) matched watchlist {{.Rule}}:
+{{- range .IoCs}}
+- {{.Type}} {{.Value}} (source: {{.SourceID}}, confidence: {{.Confidence}})
+{{- end}}`,
+	},
+	model.NotificationRuleLookalike: {
+		`[beehive] Lookalike domains {{.Rule}}: {{.Total}} new match(es)`,
+		`{{.Total}} new domain(s) imitate protected domains:
+{{- range .BrandMatches}}
+- {{.Domain}} imitates {{.ProtectedDomain}} ({{.Technique}}, distance {{.EditDistance}}, score {{printf "%.2f" .Score}}, source: {{.SourceID}})
+{{- end}}`,
+	},
+	model.NotificationRuleDigest: {
+		`[beehive] Daily digest for {{.Tag}}: {{.Total}} new IoC(s)`,
+		`{{.Total}} new IoC(s) tagged {{.Tag}} between {{.Since.Format "2006-01-02 15:04"}} and {{.Until.Format "2006-01-02 15:04"}} UTC:
+{{- range .IoCs}}
+- {{.Type}} {{.Value}} (source: {{.SourceID}}, confidence: {{.Confidence}})
+{{- end}}`,
+	},
+}
+
+// FetchFindings is what a fetch found, evaluated by notification rules
+type FetchFindings struct {
+	Created       []*model.IoC          // IoCs newly created by the fetch
+	WatchlistHits []*model.WatchlistHit // Watchlist hits recorded for Created
+	BrandMatches  []*model.BrandMatch   // Lookalike domains found in Created
+}
+
+// NotificationUseCase evaluates notification rules and delivers messages to channels
+type NotificationUseCase struct {
+	repo     notificationRepository
+	rules    []*model.NotificationRule
+	channels map[string]interfaces.NotificationChannel
+	attempts int
+	backoff  time.Duration
+}
+
+// NotificationOption configures NotificationUseCase
+type NotificationOption func(*NotificationUseCase)
+
+// WithNotificationRetry sets the number of delivery attempts per channel and the
+// initial backoff between attempts, which doubles after each failure
+func WithNotificationRetry(attempts int, backoff time.Duration) NotificationOption {
+	return func(uc *NotificationUseCase) {
+		uc.attempts = max(attempts, 1)
+		uc.backoff = backoff
+	}
+}
+
+// NewNotificationUseCase creates a notification use case. Rules must be validated
+// and refer to channels in the channels map.
+func NewNotificationUseCase(
+	repo notificationRepository,
+	channels map[string]interfaces.NotificationChannel,
+	rules []*model.NotificationRule,
+	opts ...NotificationOption,
+) *NotificationUseCase {
+	uc := &NotificationUseCase{
+		repo:     repo,
+		rules:    rules,
+		channels: channels,
+		attempts: defaultNotifyAttempts,
+		backoff:  defaultNotifyBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(uc)
+	}
+
+	return uc
+}
+
+// HasDigestRules returns true if any digest rule is configured
+func (uc *NotificationUseCase) HasDigestRules() bool {
+	for _, rule := range uc.rules {
+		if rule.Kind == model.NotificationRuleDigest {
+			return true
+		}
+	}
+	return false
+}
+
+// NotifyFetchResult evaluates source_failure, watchlist and lookalike rules for a
+// completed fetch. findings may be nil for failed fetches. Failures are logged.
+func (uc *NotificationUseCase) NotifyFetchResult(ctx context.Context, history *model.History, findings *FetchFindings) {
+	logger := logging.From(ctx)
+	if findings == nil {
+		findings = &FetchFindings{}
+	}
+
+	for _, rule := range uc.rules {
+		var err error
+		switch rule.Kind {
+		case model.NotificationRuleSourceFailure:
+			err = uc.notifySourceFailure(ctx, rule, history)
+		case model.NotificationRuleWatchlist:
+			err = uc.notifyWatchlist(ctx, rule, findings.Created, findings.WatchlistHits)
+		case model.NotificationRuleLookalike:
+			err = uc.notifyLookalike(ctx, rule, findings.BrandMatches)
+		}
+		if err != nil {
+			logger.Error("failed to send notification",
+				"rule", rule.Name,
+				"source_id", history.SourceID,
+				"error", err)
+		}
+	}
+}
+
+// notifySourceFailure notifies once when a source reaches the threshold of consecutive failures
+func (uc *NotificationUseCase) notifySourceFailure(ctx context.Context, rule *model.NotificationRule, history *model.History) error {
+	if history.Status != model.FetchStatusFailure || !rule.MatchSource(history.SourceID) {
+		return nil
+	}
+
+	// SourceState.ErrorCount is cumulative, so the streak is counted from recent histories.
+	// One extra record tells whether the streak just reached the threshold.
+	histories, _, err := uc.repo.ListHistoriesBySource(ctx, history.SourceID, rule.Threshold+1, 0)
+	if err != nil {
+		return goerr.Wrap(err, "failed to list histories", goerr.V("source_id", history.SourceID))
+	}
+
+	streak := 0
+	for _, h := range histories {
+		if h.Status != model.FetchStatusFailure {
+			break
+		}
+		streak++
+	}
+	if streak != rule.Threshold {
+		return nil
+	}
+
+	// The oldest failure of the streak identifies it
+	first := histories[streak-1]
+	event := &model.NotificationEvent{
+		Rule:                rule.Name,
+		Kind:                rule.Kind,
+		SourceID:            history.SourceID,
+		History:             history,
+		ConsecutiveFailures: streak,
+	}
+	key := strings.Join([]string{string(rule.Kind), rule.Name, history.SourceID, first.ID}, ":")
+
+	_, err = uc.dispatch(ctx, rule, key, event)
+	return err
+}
+
+// notifyWatchlist notifies about created IoCs matching the rule's patterns or
+// watchlists, each IoC at most once
+func (uc *NotificationUseCase) notifyWatchlist(ctx context.Context, rule *model.NotificationRule, created []*model.IoC, hits []*model.WatchlistHit) error {
+	hitsByIoC := make(map[string][]*model.WatchlistHit)
+	for _, hit := range hits {
+		hitsByIoC[hit.IoCID] = append(hitsByIoC[hit.IoCID], hit)
+	}
+
+	var matched []*model.IoC
+	for _, ioc := range created {
+		if rule.MatchIoC(ioc) {
+			matched = append(matched, ioc)
+			continue
+		}
+		for _, hit := range hitsByIoC[ioc.ID] {
+			if rule.MatchWatchlistHit(hit, ioc) {
+				matched = append(matched, ioc)
+				break
+			}
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	// Each IoC is notified at most once per rule
+	keys := make([]string, len(matched))
+	for i, ioc := range matched {
+		keys[i] = strings.Join([]string{string(rule.Kind), rule.Name, ioc.ID}, ":")
+	}
+
+	now := time.Now()
+	claimed, err := uc.claim(ctx, keys, now)
+	if err != nil {
+		return err
+	}
+
+	var claimedKeys []string
+	var iocs []*model.IoC
+	for i, ok := range claimed {
+		if ok {
+			claimedKeys = append(claimedKeys, keys[i])
+			iocs = append(iocs, matched[i])
+		}
+	}
+	if len(iocs) == 0 {
+		return nil
+	}
+
+	event := &model.NotificationEvent{
+		Rule:  rule.Name,
+		Kind:  rule.Kind,
+		IoCs:  iocs,
+		Total: len(iocs),
+	}
+
+	_, err = uc.deliver(ctx, rule, claimedKeys, event, now)
+	return err
+}
+
+// notifyLookalike notifies about new lookalike domains matching the rule, each match at most once
+func (uc *NotificationUseCase) notifyLookalike(ctx context.Context, rule *model.NotificationRule, matches []*model.BrandMatch) error {
+	var matched []*model.BrandMatch
+	var keys []string
+	for _, m := range matches {
+		if rule.MatchBrandMatch(m) {
+			matched = append(matched, m)
+			keys = append(keys, strings.Join([]string{string(rule.Kind), rule.Name, m.ID}, ":"))
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	claimed, err := uc.claim(ctx, keys, now)
+	if err != nil {
+		return err
+	}
+
+	var claimedKeys []string
+	var claimedMatches []*model.BrandMatch
+	for i, ok := range claimed {
+		if ok {
+			claimedKeys = append(claimedKeys, keys[i])
+			claimedMatches = append(claimedMatches, matched[i])
+		}
+	}
+	if len(claimedMatches) == 0 {
+		return nil
+	}
+
+	event := &model.NotificationEvent{
+		Rule:         rule.Name,
+		Kind:         rule.Kind,
+		BrandMatches: claimedMatches,
+		Total:        len(claimedMatches),
+	}
+
+	_, err = uc.deliver(ctx, rule, claimedKeys, event, now)
+	return err
+}
+
+// SendDigests sends the most recent completed daily digest of each digest rule
+// and tag that has not been sent yet. A tag matches IoCs from sources with the
+// tag and IoCs tagged by analysts. Returns the number of digests sent.
+func (uc *NotificationUseCase) SendDigests(ctx context.Context, sources map[string]model.Source, now time.Time) (int, error) {
+	var digestRules []*model.NotificationRule
+	for _, rule := range uc.rules {
+		if rule.Kind == model.NotificationRuleDigest {
+			digestRules = append(digestRules, rule)
+		}
+	}
+	if len(digestRules) == 0 {
+		return 0, nil
+	}
+
+	sent := 0
+	for _, rule := range digestRules {
+		since, until := rule.DigestWindow(now)
+
+		for _, tag := range rule.Tags {
+			// IoCs first seen in the window were updated in it or later
+			iocs, err := uc.repo.ListIoCsByTag(ctx, &model.IoCTagFilter{
+				Tag:          tag,
+				SourceIDs:    model.SourceIDsWithTag(sources, tag),
+				UpdatedSince: since,
+			})
+			if err != nil {
+				return sent, goerr.Wrap(err, "failed to list IoCs by tag",
+					goerr.V("rule", rule.Name), goerr.V("tag", tag))
+			}
+
+			var matched []*model.IoC
+			for _, ioc := range iocs {
+				if !ioc.FirstSeenAt.Before(since) && ioc.FirstSeenAt.Before(until) {
+					matched = append(matched, ioc)
+				}
+			}
+			// Empty digests are not sent
+			if len(matched) == 0 {
+				continue
+			}
+
+			sort.Slice(matched, func(i, j int) bool {
+				if matched[i].Confidence != matched[j].Confidence {
+					return matched[i].Confidence > matched[j].Confidence
+				}
+				return matched[i].Value < matched[j].Value
+			})
+
+			event := &model.NotificationEvent{
+				Rule:  rule.Name,
+				Kind:  rule.Kind,
+				IoCs:  matched,
+				Total: len(matched),
+				Tag:   tag,
+				Since: since,
+				Until: until,
+			}
+			key := strings.Join([]string{string(rule.Kind), rule.Name, tag, until.Format("2006-01-02T15")}, ":")
+
+			ok, err := uc.dispatch(ctx, rule, key, event)
+			if err != nil {
+				return sent, goerr.Wrap(err, "failed to send digest",
+					goerr.V("rule", rule.Name), goerr.V("tag", tag))
+			}
+			if ok {
+				sent++
+			}
+		}
+	}
+
+	return sent, nil
+}
+
+// RunDigestScheduler calls SendDigests every interval until ctx is canceled.
+// sources returns the current source configuration.
+func (uc *NotificationUseCase) RunDigestScheduler(ctx context.Context, interval time.Duration, sources func() map[string]model.Source) {
+	logger := logging.From(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := uc.SendDigests(ctx, sources(), time.Now()); err != nil {
+				logger.Error("failed to send notification digests", "error", err)
+			}
+		}
+	}
+}
+
+// claim records dedup keys and returns which of them were newly claimed
+func (uc *NotificationUseCase) claim(ctx context.Context, keys []string, now time.Time) ([]bool, error) {
+	claimed := make([]bool, len(keys))
+	for i, key := range keys {
+		ok, err := uc.repo.ClaimNotification(ctx, key, now)
+		if err != nil {
+			uc.releaseClaimed(ctx, keys, claimed)
+			return nil, goerr.Wrap(err, "failed to claim notification", goerr.V("key", key))
+		}
+		claimed[i] = ok
+	}
+	return claimed, nil
+}
+
+// deliver renders the notification and sends it to the rule's channels. keys
+// must already be claimed; if delivery fails on every channel they are released
+// so that a later event can retry. Returns true if at least one channel received it.
+func (uc *NotificationUseCase) deliver(ctx context.Context, rule *model.NotificationRule, keys []string, event *model.NotificationEvent, now time.Time) (bool, error) {
+	logger := logging.From(ctx)
+
+	if len(event.IoCs) > model.MaxNotificationIoCs {
+		event.IoCs = event.IoCs[:model.MaxNotificationIoCs]
+	}
+	if len(event.BrandMatches) > model.MaxNotificationIoCs {
+		event.BrandMatches = event.BrandMatches[:model.MaxNotificationIoCs]
+	}
+
+	n, err := renderNotification(rule, event, keys[0], now)
+	if err != nil {
+		uc.release(ctx, keys)
+		return false, err
+	}
+
+	delivered := 0
+	var lastErr error
+	for _, name := range rule.Channels {
+		ch, ok := uc.channels[name]
+		if !ok {
+			lastErr = goerr.New("notification channel not found", goerr.V("channel", name))
+			logger.Error("notification channel not found", "rule", rule.Name, "channel", name)
+			continue
+		}
+		if err := uc.send(ctx, ch, n); err != nil {
+			lastErr = goerr.Wrap(err, "failed to deliver notification", goerr.V("channel", name))
+			logger.Error("failed to deliver notification",
+				"rule", rule.Name, "channel", name, "error", err)
+			continue
+		}
+		delivered++
+	}
+
+	if delivered == 0 {
+		uc.release(ctx, keys)
+		return false, lastErr
+	}
+
+	logger.Info("sent notification",
+		"rule", rule.Name,
+		"kind", rule.Kind,
+		"channels", delivered,
+		"key", n.Key)
+	return true, nil
+}
+
+// dispatch sends a notification identified by a single dedup key unless it was already sent
+func (uc *NotificationUseCase) dispatch(ctx context.Context, rule *model.NotificationRule, key string, event *model.NotificationEvent) (bool, error) {
+	now := time.Now()
+	claimed, err := uc.claim(ctx, []string{key}, now)
+	if err != nil {
+		return false, err
+	}
+	if !claimed[0] {
+		logging.From(ctx).Debug("notification already sent", "rule", rule.Name, "key", key)
+		return false, nil
+	}
+	return uc.deliver(ctx, rule, []string{key}, event, now)
+}
+
+// send delivers to a channel with exponential backoff between attempts
+func (uc *NotificationUseCase) send(ctx context.Context, ch interfaces.NotificationChannel, n *model.Notification) error {
+	backoff := uc.backoff
+	var err error
+	for attempt := 1; attempt <= uc.attempts; attempt++ {
+		if err = ch.Send(ctx, n); err == nil {
+			return nil
+		}
+		if attempt == uc.attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return goerr.Wrap(ctx.Err(), "notification delivery canceled")
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return goerr.Wrap(err, "notification delivery failed", goerr.V("attempts", uc.attempts))
+}
+
+func (uc *NotificationUseCase) releaseClaimed(ctx context.Context, keys []string, claimed []bool) {
+	var toRelease []string
+	for i, ok := range claimed {
+		if ok {
+			toRelease = append(toRelease, keys[i])
+		}
+	}
+	uc.release(ctx, toRelease)
+}
+
+func (uc *NotificationUseCase) release(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := uc.repo.ReleaseNotification(ctx, key); err != nil {
+			logging.From(ctx).Error("failed to release notification claim", "key", key, "error", err)
+		}
+	}
+}
+
+// renderNotification executes the subject and body templates of a rule
+func renderNotification(rule *model.NotificationRule, event *model.NotificationEvent, key string, now time.Time) (*model.Notification, error) {
+	defaults := defaultNotificationTemplates[rule.Kind]
+	subjectText, bodyText := defaults[0], defaults[1]
+	if rule.Subject != "" {
+		subjectText = rule.Subject
+	}
+	if rule.Body != "" {
+		bodyText = rule.Body
+	}
+
+	subject, err := executeTemplate("subject", subjectText, event)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to render notification subject", goerr.V("rule", rule.Name))
+	}
+	body, err := executeTemplate("body", bodyText, event)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to render notification body", goerr.V("rule", rule.Name))
+	}
+	if event.Total > len(event.IoCs)+len(event.BrandMatches) {
+		body += "\n... and more"
+	}
+
+	return &model.Notification{
+		Key:       key,
+		Rule:      rule.Name,
+		Kind:      rule.Kind,
+		Subject:   strings.TrimSpace(subject),
+		Body:      body,
+		Event:     event,
+		CreatedAt: now,
+	}, nil
+}
+
+func executeTemplate(name, text string, data any) (string, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", goerr.Wrap(err, "failed to parse template")
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", goerr.Wrap(err, "failed to execute template")
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,256 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/service/notify"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+// receiver records webhook payloads; the first failures requests are rejected
+type receiver struct {
+	mu       sync.Mutex
+	payloads []notify.WebhookPayload
+	failures atomic.Int32
+	server   *httptest.Server
+}
+
+func newReceiver(t *testing.T) *receiver {
+	r := &receiver{}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if r.failures.Load() > 0 {
+			r.failures.Add(-1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var p notify.WebhookPayload
+		gt.NoError(t, json.NewDecoder(req.Body).Decode(&p))
+		r.mu.Lock()
+		r.payloads = append(r.payloads, p)
+		r.mu.Unlock()
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *receiver) received() []notify.WebhookPayload {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]notify.WebhookPayload(nil), r.payloads...)
+}
+
+func newNotifier(repo *memory.Memory, r *receiver, rules ...*model.NotificationRule) *usecase.NotificationUseCase {
+	return usecase.NewNotificationUseCase(repo,
+		map[string]interfaces.NotificationChannel{"hook": notify.NewWebhook(r.server.URL, nil)},
+		rules,
+		usecase.WithNotificationRetry(3, 0))
+}
+
+func TestNotification_SourceFailure(t *testing.T) {
+	ctx := context.Background()
+
+	var healthy atomic.Bool
+	feedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(threatFoxCSV))
+	}))
+	defer feedServer.Close()
+
+	repo := memory.New()
+	recv := newReceiver(t)
+	rule := &model.NotificationRule{Name: "broken-sources", Kind: model.NotificationRuleSourceFailure, Channels: []string{"hook"}, Threshold: 2}
+	gt.NoError(t, rule.Validate())
+	fetchUC := usecase.NewFetchUseCase(repo, nil, usecase.WithNotifier(newNotifier(repo, recv, rule)))
+
+	sources := map[string]model.Source{
+		"threatfox": {
+			Type:       model.SourceTypeFeed,
+			URL:        feedServer.URL,
+			Enabled:    true,
+			FeedConfig: &model.FeedConfig{Schema: "abuse_ch_threatfox"},
+		},
+	}
+	fetch := func() {
+		_, err := fetchUC.FetchSourceByID(ctx, sources, "threatfox")
+		gt.NoError(t, err)
+	}
+
+	fetch()
+	gt.A(t, recv.received()).Length(0)
+
+	fetch()
+	gt.A(t, recv.received()).Length(1).At(0, func(t testing.TB, p notify.WebhookPayload) {
+		gt.Equal(t, p.Kind, model.NotificationRuleSourceFailure)
+		gt.Equal(t, p.SourceID, "threatfox")
+		gt.Equal(t, p.Subject, "[beehive] Source threatfox failed 2 times in a row")
+		gt.S(t, p.Body).Contains("non-200 status code")
+	})
+
+	// Further failures of the same streak are not notified again
+	fetch()
+	gt.A(t, recv.received()).Length(1)
+
+	// A success ends the streak; a new streak is notified again
+	healthy.Store(true)
+	fetch()
+	healthy.Store(false)
+	fetch()
+	fetch()
+	gt.A(t, recv.received()).Length(2)
+}
+
+func TestNotification_Watchlist(t *testing.T) {
+	ctx := context.Background()
+
+	feedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(threatFoxCSV))
+	}))
+	defer feedServer.Close()
+
+	repo := memory.New()
+	recv := newReceiver(t)
+	matching := &model.NotificationRule{
+		Name:     "test-net",
+		Kind:     model.NotificationRuleWatchlist,
+		Channels: []string{"hook"},
+		Patterns: []string{"198.51.100.*"},
+		Body:     "{{range .IoCs}}{{.Value}}{{end}}",
+	}
+	other := &model.NotificationRule{
+		Name:     "other",
+		Kind:     model.NotificationRuleWatchlist,
+		Channels: []string{"hook"},
+		Patterns: []string{"*.example.com"},
+	}
+	gt.NoError(t, matching.Validate())
+	gt.NoError(t, other.Validate())
+	fetchUC := usecase.NewFetchUseCase(repo, nil, usecase.WithNotifier(newNotifier(repo, recv, matching, other)))
+
+	sources := map[string]model.Source{
+		"threatfox": {
+			Type:       model.SourceTypeFeed,
+			URL:        feedServer.URL,
+			Enabled:    true,
+			FeedConfig: &model.FeedConfig{Schema: "abuse_ch_threatfox"},
+		},
+	}
+
+	// The first delivery attempt fails and is retried
+	recv.failures.Store(1)
+	_, err := fetchUC.FetchSourceByID(ctx, sources, "threatfox")
+	gt.NoError(t, err)
+
+	gt.A(t, recv.received()).Length(1).At(0, func(t testing.TB, p notify.WebhookPayload) {
+		gt.Equal(t, p.Rule, "test-net")
+		gt.Equal(t, p.Subject, "[beehive] Watchlist test-net: 1 new IoC(s)")
+		gt.Equal(t, p.Body, "198.51.100.50:51515")
+		gt.A(t, p.IoCs).Length(1)
+	})
+
+	// Re-observed IoCs are not new and are not notified again
+	_, err = fetchUC.FetchSourceByID(ctx, sources, "threatfox")
+	gt.NoError(t, err)
+	gt.A(t, recv.received()).Length(1)
+}
+
+func TestNotification_Digest(t *testing.T) {
+	ctx := context.Background()
+
+	repo := memory.New()
+	recv := newReceiver(t)
+	rule := &model.NotificationRule{Name: "daily", Kind: model.NotificationRuleDigest, Channels: []string{"hook"}, Tags: []string{"malware"}}
+	gt.NoError(t, rule.Validate())
+	uc := newNotifier(repo, recv, rule)
+
+	newIoC := func(sourceID, value string) *model.IoC {
+		return &model.IoC{
+			ID:         model.GenerateID(sourceID, model.IoCTypeDomain, value, ""),
+			SourceID:   sourceID,
+			SourceType: string(model.SourceTypeFeed),
+			Type:       model.IoCTypeDomain,
+			Value:      value,
+			Status:     model.IoCStatusActive,
+		}
+	}
+	_, err := repo.BatchUpsertIoCs(ctx, []*model.IoC{
+		newIoC("tagged-feed", "a.example.com"),
+		newIoC("tagged-feed", "b.example.com"),
+		newIoC("other-feed", "c.example.com"),
+	})
+	gt.NoError(t, err)
+
+	sources := map[string]model.Source{
+		"tagged-feed": {Type: model.SourceTypeFeed, Tags: []string{"malware"}},
+		"other-feed":  {Type: model.SourceTypeFeed, Tags: []string{"phishing"}},
+	}
+
+	// The window containing today is not complete yet
+	sent, err := uc.SendDigests(ctx, sources, time.Now())
+	gt.NoError(t, err)
+	gt.Equal(t, sent, 0)
+
+	tomorrow := time.Now().Add(24 * time.Hour)
+	sent, err = uc.SendDigests(ctx, sources, tomorrow)
+	gt.NoError(t, err)
+	gt.Equal(t, sent, 1)
+	gt.A(t, recv.received()).Length(1).At(0, func(t testing.TB, p notify.WebhookPayload) {
+		gt.Equal(t, p.Tag, "malware")
+		gt.Equal(t, p.Total, 2)
+		gt.S(t, p.Body).Contains("a.example.com")
+		gt.S(t, p.Body).NotContains("c.example.com")
+	})
+
+	// The same digest is sent only once
+	sent, err = uc.SendDigests(ctx, sources, tomorrow.Add(time.Hour))
+	gt.NoError(t, err)
+	gt.Equal(t, sent, 0)
+	gt.A(t, recv.received()).Length(1)
+}
+
+func TestNotification_FailedDeliveryIsRetriedLater(t *testing.T) {
+	ctx := context.Background()
+
+	repo := memory.New()
+	recv := newReceiver(t)
+	rule := &model.NotificationRule{Name: "daily", Kind: model.NotificationRuleDigest, Channels: []string{"hook"}, Tags: []string{"malware"}}
+	gt.NoError(t, rule.Validate())
+	uc := newNotifier(repo, recv, rule)
+
+	_, err := repo.BatchUpsertIoCs(ctx, []*model.IoC{{
+		ID:         "ioc-1",
+		SourceID:   "feed",
+		SourceType: string(model.SourceTypeFeed),
+		Type:       model.IoCTypeDomain,
+		Value:      "a.example.com",
+		Status:     model.IoCStatusActive,
+		Tags:       []string{"malware"},
+	}})
+	gt.NoError(t, err)
+
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	// All attempts fail: the digest is not recorded as sent
+	recv.failures.Store(3)
+	_, err = uc.SendDigests(ctx, nil, tomorrow)
+	gt.Error(t, err)
+	gt.A(t, recv.received()).Length(0)
+
+	sent, err := uc.SendDigests(ctx, nil, tomorrow)
+	gt.NoError(t, err)
+	gt.Equal(t, sent, 1)
+	gt.A(t, recv.received()).Length(1)
+}
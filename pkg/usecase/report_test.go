@@ -0,0 +1,108 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/gollem"
+	"github.com/m-mizutani/gollem/mock"
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+// newReportLLM returns a mock LLM answering IoC extraction and report analysis prompts
+func newReportLLM(iocResponse, reportResponse string) *mock.LLMClientMock {
+	return &mock.LLMClientMock{
+		NewSessionFunc: func(ctx context.Context, options ...gollem.SessionOption) (gollem.Session, error) {
+			return &mock.SessionMock{
+				GenerateContentFunc: func(ctx context.Context, input ...gollem.Input) (*gollem.Response, error) {
+					for _, in := range input {
+						if text, ok := in.(gollem.Text); ok && strings.Contains(string(text), "Threat Report Analysis") {
+							return &gollem.Response{Texts: []string{reportResponse}}, nil
+						}
+					}
+					return &gollem.Response{Texts: []string{iocResponse}}, nil
+				},
+			}, nil
+		},
+	}
+}
+
+func TestFetchUseCase_StoresReports(t *testing.T) {
+	ctx := context.Background()
+	server := newBlogServer(t, `APT29 exploited CVE-2024-3400 and beacons to 198.51.100[.]42.`)
+
+	repo := memory.New()
+	llm := newReportLLM(
+		`{"iocs": [{"type": "ipv4", "value": "198.51.100[.]42", "description": "C2 server"}]}`,
+		`{"summary": "APT29 exploited a firewall vulnerability.", "threat_actors": ["APT29"],
+		  "malware_families": [], "sectors": ["Government"], "cves": ["CVE-2024-3400"],
+		  "attack_techniques": ["T1190"]}`,
+	)
+	fetchUC := usecase.NewFetchUseCase(repo, llm)
+
+	sources := map[string]model.Source{
+		"blog": {Type: model.SourceTypeRSS, URL: server.URL + "/feed", Enabled: true},
+	}
+
+	history, err := fetchUC.FetchSourceByID(ctx, sources, "blog")
+	gt.NoError(t, err)
+	gt.Equal(t, history.ErrorCount, 0)
+	gt.Equal(t, history.IoCsCreated, 1)
+
+	conn, err := repo.ListReports(ctx, &model.ReportListOptions{SourceID: "blog"})
+	gt.NoError(t, err)
+	gt.A(t, conn.Items).Length(1)
+	report := conn.Items[0]
+	gt.Equal(t, report.ID, model.GenerateReportID("blog", "post-1"))
+	gt.Equal(t, report.Title, "Campaign analysis")
+	gt.Equal(t, report.URL, server.URL+"/post/1")
+	gt.Equal(t, report.Summary, "APT29 exploited a firewall vulnerability.")
+	gt.Equal(t, report.ThreatActors, []string{"APT29"})
+	gt.Equal(t, report.Sectors, []string{"Government"})
+	gt.Equal(t, report.CVEs, []string{"CVE-2024-3400"})
+	gt.Equal(t, report.AttackTechniques, []string{"T1190"})
+	gt.S(t, report.Content).Contains("APT29 exploited CVE-2024-3400")
+	gt.False(t, report.PublishedAt.IsZero())
+
+	iocs, err := repo.ListIoCsByReport(ctx, report.ID)
+	gt.NoError(t, err)
+	gt.A(t, iocs).Length(1).At(0, func(t testing.TB, ioc *model.IoC) {
+		gt.Equal(t, ioc.Value, "198.51.100.42")
+		gt.Equal(t, ioc.Context, "Campaign analysis")
+	})
+}
+
+func TestFetchUseCase_ReportAnalysisFailure(t *testing.T) {
+	ctx := context.Background()
+	server := newBlogServer(t, `The loader exploits CVE-2024-3400 and beacons to 198.51.100[.]42.`)
+
+	repo := memory.New()
+	llm := newReportLLM(
+		`{"iocs": [{"type": "ipv4", "value": "198.51.100.42", "description": "C2 server"}]}`,
+		`not json`,
+	)
+	fetchUC := usecase.NewFetchUseCase(repo, llm)
+
+	sources := map[string]model.Source{
+		"blog": {Type: model.SourceTypeRSS, URL: server.URL + "/feed", Enabled: true},
+	}
+
+	history, err := fetchUC.FetchSourceByID(ctx, sources, "blog")
+	gt.NoError(t, err)
+	gt.Equal(t, history.ErrorCount, 1)
+	gt.Equal(t, history.IoCsCreated, 1)
+
+	// The report is kept with the IDs found in the text
+	report, err := repo.GetReport(ctx, model.GenerateReportID("blog", "post-1"))
+	gt.NoError(t, err)
+	gt.Equal(t, report.Summary, "")
+	gt.Equal(t, report.CVEs, []string{"CVE-2024-3400"})
+
+	iocs, err := repo.ListIoCsByReport(ctx, report.ID)
+	gt.NoError(t, err)
+	gt.A(t, iocs).Length(1)
+}
@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+)
+
+var (
+	// ErrSourceExists is returned when a source with the same ID is stored
+	ErrSourceExists = goerr.New("source already exists")
+)
+
+// ImportSourcesResult summarizes an import of source definitions
+type ImportSourcesResult struct {
+	Created   []string // IDs of the created sources
+	Updated   []string // IDs of the replaced sources
+	Unchanged []string // IDs of the stored sources equal to the imported definition
+	Skipped   []string // IDs of the stored sources that differ but were not overwritten
+	Deleted   []string // IDs of the sources deleted because they were removed from the config
+
+	// Drift holds the settings in which each skipped source differs from the config
+	Drift map[string][]string
+}
+
+// merge adds the sources of other to the result, keeping the IDs sorted
+func (r *ImportSourcesResult) merge(other *ImportSourcesResult) {
+	r.Created = slices.Sorted(slices.Values(append(r.Created, other.Created...)))
+	r.Updated = slices.Sorted(slices.Values(append(r.Updated, other.Updated...)))
+	r.Unchanged = slices.Sorted(slices.Values(append(r.Unchanged, other.Unchanged...)))
+	r.Skipped = slices.Sorted(slices.Values(append(r.Skipped, other.Skipped...)))
+	for id, fields := range other.Drift {
+		if r.Drift == nil {
+			r.Drift = make(map[string][]string)
+		}
+		r.Drift[id] = fields
+	}
+}
+
+// ListSources returns the stored sources ordered by ID
+func (uc *UseCases) ListSources(ctx context.Context) ([]*model.Source, error) {
+	sources, err := uc.repo.ListSources(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list sources")
+	}
+	return sources, nil
+}
+
+// SourcesMap returns the stored sources keyed by ID for fetching
+func (uc *UseCases) SourcesMap(ctx context.Context) (map[string]model.Source, error) {
+	sources, err := uc.ListSources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]model.Source, len(sources))
+	for _, s := range sources {
+		m[s.ID] = *s
+	}
+	return m, nil
+}
+
+// GetSource returns a stored source
+func (uc *UseCases) GetSource(ctx context.Context, id string) (*model.Source, error) {
+	s, err := uc.repo.GetSource(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get source", goerr.V("id", id))
+	}
+	return s, nil
+}
+
+// CreateSource stores a new source. The source settings must have been
+// validated by the config rules of its type.
+func (uc *UseCases) CreateSource(ctx context.Context, source *model.Source, actor string) (*model.Source, error) {
+	if err := source.Validate(); err != nil {
+		return nil, goerr.Wrap(err, "invalid source")
+	}
+
+	if _, err := uc.repo.GetSource(ctx, source.ID); err == nil {
+		return nil, goerr.Wrap(ErrSourceExists, "failed to create source", goerr.V("id", source.ID))
+	} else if !errors.Is(err, interfaces.ErrSourceNotFound) {
+		return nil, goerr.Wrap(err, "failed to get source", goerr.V("id", source.ID))
+	}
+
+	now := time.Now()
+	created := *source
+	created.Origin = model.SourceOriginAPI
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	if err := uc.repo.PutSource(ctx, &created); err != nil {
+		return nil, goerr.Wrap(err, "failed to save source", goerr.V("id", source.ID))
+	}
+	uc.recordSourceChange(ctx, model.NewSourceChange(model.SourceChangeCreate, actor, nil, &created, now))
+	return &created, nil
+}
+
+// UpdateSource replaces the settings of a stored source. The type of a source
+// cannot be changed. The source settings must have been validated by the
+// config rules of its type.
+func (uc *UseCases) UpdateSource(ctx context.Context, source *model.Source, actor string) (*model.Source, error) {
+	if err := source.Validate(); err != nil {
+		return nil, goerr.Wrap(err, "invalid source")
+	}
+
+	before, err := uc.repo.GetSource(ctx, source.ID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get source", goerr.V("id", source.ID))
+	}
+	if before.Type != source.Type {
+		return nil, goerr.New("source type cannot be changed",
+			goerr.V("id", source.ID),
+			goerr.V("type", before.Type),
+			goerr.V("new_type", source.Type))
+	}
+
+	after := *source
+	after.Origin = model.SourceOriginAPI
+	return uc.putSource(ctx, before, &after, model.SourceChangeUpdate, actor)
+}
+
+// SetSourceEnabled enables or disables a stored source
+func (uc *UseCases) SetSourceEnabled(ctx context.Context, id string, enabled bool, actor string) (*model.Source, error) {
+	before, err := uc.repo.GetSource(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get source", goerr.V("id", id))
+	}
+
+	after := *before
+	after.Enabled = enabled
+	after.Origin = model.SourceOriginAPI
+	action := model.SourceChangeDisable
+	if enabled {
+		action = model.SourceChangeEnable
+	}
+	return uc.putSource(ctx, before, &after, action, actor)
+}
+
+// DeleteSource deletes a stored source. Its state, histories and IoCs are kept.
+func (uc *UseCases) DeleteSource(ctx context.Context, id string, actor string) error {
+	before, err := uc.repo.GetSource(ctx, id)
+	if err != nil {
+		return goerr.Wrap(err, "failed to get source", goerr.V("id", id))
+	}
+
+	if err := uc.repo.DeleteSource(ctx, id); err != nil {
+		return goerr.Wrap(err, "failed to delete source", goerr.V("id", id))
+	}
+	uc.recordSourceChange(ctx, model.NewSourceChange(model.SourceChangeDelete, actor, before, nil, time.Now()))
+	return nil
+}
+
+// ImportSources stores source definitions loaded from the TOML config. Sources
+// that are not stored yet are created. Stored sources that differ are replaced
+// if they were imported from the config or if overwrite is true, so that edits
+// of the file take effect while changes made through the API survive a restart
+// with the config as seed. The differences of the kept sources are reported
+// as drift.
+func (uc *UseCases) ImportSources(ctx context.Context, sources map[string]model.Source, overwrite bool) (*ImportSourcesResult, error) {
+	for id, src := range sources {
+		src.ID = id
+		if err := src.Validate(); err != nil {
+			return nil, goerr.Wrap(err, "invalid source")
+		}
+	}
+
+	stored, err := uc.SourcesMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportSourcesResult{}
+	for _, id := range slices.Sorted(maps.Keys(sources)) {
+		src := sources[id]
+		src.ID = id
+		src.Origin = model.SourceOriginConfig
+
+		before, ok := stored[id]
+		diff := model.DiffSources(&before, &src)
+		switch {
+		case !ok:
+			now := time.Now()
+			src.CreatedAt = now
+			src.UpdatedAt = now
+			if err := uc.repo.PutSource(ctx, &src); err != nil {
+				return result, goerr.Wrap(err, "failed to save source", goerr.V("id", id))
+			}
+			uc.recordSourceChange(ctx, model.NewSourceChange(model.SourceChangeImport, model.ActorConfig, nil, &src, now))
+			result.Created = append(result.Created, id)
+
+		case len(diff) == 0:
+			// Sources stored before the origin was recorded and equal to the
+			// config are managed by the config from now on
+			if before.Origin == "" {
+				before.Origin = model.SourceOriginConfig
+				if err := uc.repo.PutSource(ctx, &before); err != nil {
+					return result, goerr.Wrap(err, "failed to save source", goerr.V("id", id))
+				}
+			}
+			result.Unchanged = append(result.Unchanged, id)
+
+		case !overwrite && before.Origin != model.SourceOriginConfig:
+			result.Skipped = append(result.Skipped, id)
+			if result.Drift == nil {
+				result.Drift = make(map[string][]string)
+			}
+			result.Drift[id] = diff
+
+		default:
+			if _, err := uc.putSource(ctx, &before, &src, model.SourceChangeImport, model.ActorConfig); err != nil {
+				return result, err
+			}
+			result.Updated = append(result.Updated, id)
+		}
+	}
+	return result, nil
+}
+
+// ListSourceChanges returns the change records of a source, or of all sources
+// if sourceID is empty, newest first
+func (uc *UseCases) ListSourceChanges(ctx context.Context, sourceID string, limit int) ([]*model.SourceChange, error) {
+	changes, err := uc.repo.ListSourceChanges(ctx, sourceID, limit)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list source changes", goerr.V("source_id", sourceID))
+	}
+	return changes, nil
+}
+
+// putSource replaces a stored source and records the change. Nothing is
+// written if no setting changed.
+func (uc *UseCases) putSource(ctx context.Context, before, source *model.Source, action model.SourceChangeAction, actor string) (*model.Source, error) {
+	now := time.Now()
+	after := *source
+	after.CreatedAt = before.CreatedAt
+	after.UpdatedAt = before.UpdatedAt
+
+	change := model.NewSourceChange(action, actor, before, &after, now)
+	if len(change.Fields) == 0 {
+		return &after, nil
+	}
+
+	after.UpdatedAt = now
+	if err := uc.repo.PutSource(ctx, &after); err != nil {
+		return nil, goerr.Wrap(err, "failed to save source", goerr.V("id", after.ID))
+	}
+	uc.recordSourceChange(ctx, change)
+	return &after, nil
+}
+
+// recordSourceChange saves a change record and its audit record. Failures are
+// logged and do not fail the change.
+func (uc *UseCases) recordSourceChange(ctx context.Context, change *model.SourceChange) {
+	uc.audit.Record(ctx, change.Actor, model.AuditSourceAction(change.Action), model.AuditTargetSource, change.SourceID, change.Before, change.After)
+
+	if err := uc.repo.SaveSourceChange(ctx, change); err != nil {
+		logging.From(ctx).Error("failed to save source change",
+			"source_id", change.SourceID,
+			"action", change.Action,
+			"actor", change.Actor,
+			"error", err)
+	}
+}
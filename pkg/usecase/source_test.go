@@ -0,0 +1,229 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+func newRSSSource(id, url string) *model.Source {
+	return &model.Source{
+		ID:        id,
+		Type:      model.SourceTypeRSS,
+		URL:       url,
+		Tags:      []string{"blog"},
+		Enabled:   true,
+		RSSConfig: &model.RSSConfig{Extraction: model.ExtractionModeRegex},
+	}
+}
+
+func TestSource_CRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	uc := usecase.New(repo)
+
+	created, err := uc.CreateSource(ctx, newRSSSource("vendor-blog", "https://blog.example/feed"), "alice")
+	gt.NoError(t, err)
+	gt.False(t, created.CreatedAt.IsZero())
+	gt.True(t, created.UpdatedAt.Equal(created.CreatedAt))
+
+	t.Run("IDs are unique", func(t *testing.T) {
+		_, err := uc.CreateSource(ctx, newRSSSource("vendor-blog", "https://other.example/feed"), "alice")
+		gt.True(t, errors.Is(err, usecase.ErrSourceExists))
+	})
+
+	t.Run("invalid sources are rejected", func(t *testing.T) {
+		_, err := uc.CreateSource(ctx, newRSSSource("manual", "https://blog.example/feed"), "alice")
+		gt.Error(t, err)
+		_, err = uc.CreateSource(ctx, newRSSSource("no url", ""), "alice")
+		gt.Error(t, err)
+	})
+
+	t.Run("update records the changed fields", func(t *testing.T) {
+		src := newRSSSource("vendor-blog", "https://blog.example/rss")
+		src.Description = "Vendor research"
+		updated, err := uc.UpdateSource(ctx, src, "bob")
+		gt.NoError(t, err)
+		gt.Equal(t, updated.URL, "https://blog.example/rss")
+		gt.True(t, updated.CreatedAt.Equal(created.CreatedAt))
+		gt.True(t, updated.UpdatedAt.After(created.UpdatedAt) || updated.UpdatedAt.Equal(created.UpdatedAt))
+
+		changes, err := uc.ListSourceChanges(ctx, "vendor-blog", 1)
+		gt.NoError(t, err)
+		gt.A(t, changes).Length(1).At(0, func(t testing.TB, c *model.SourceChange) {
+			gt.Equal(t, c.Action, model.SourceChangeUpdate)
+			gt.Equal(t, c.Actor, "bob")
+			gt.Equal(t, c.Fields, []string{"url", "description"})
+		})
+	})
+
+	t.Run("type cannot be changed", func(t *testing.T) {
+		src := &model.Source{
+			ID:         "vendor-blog",
+			Type:       model.SourceTypeFeed,
+			Enabled:    true,
+			FeedConfig: &model.FeedConfig{Schema: "abuse_ch_urlhaus"},
+		}
+		_, err := uc.UpdateSource(ctx, src, "bob")
+		gt.Error(t, err)
+	})
+
+	t.Run("disable and enable", func(t *testing.T) {
+		disabled, err := uc.SetSourceEnabled(ctx, "vendor-blog", false, "alice")
+		gt.NoError(t, err)
+		gt.False(t, disabled.Enabled)
+
+		sources, err := uc.SourcesMap(ctx)
+		gt.NoError(t, err)
+		gt.False(t, sources["vendor-blog"].Enabled)
+
+		// Setting the same value is not recorded
+		_, err = uc.SetSourceEnabled(ctx, "vendor-blog", false, "alice")
+		gt.NoError(t, err)
+
+		_, err = uc.SetSourceEnabled(ctx, "vendor-blog", true, "alice")
+		gt.NoError(t, err)
+
+		changes, err := uc.ListSourceChanges(ctx, "vendor-blog", 0)
+		gt.NoError(t, err)
+		actions := make([]model.SourceChangeAction, len(changes))
+		for i, c := range changes {
+			actions[i] = c.Action
+		}
+		gt.Equal(t, actions, []model.SourceChangeAction{
+			model.SourceChangeEnable,
+			model.SourceChangeDisable,
+			model.SourceChangeUpdate,
+			model.SourceChangeCreate,
+		})
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		gt.NoError(t, uc.DeleteSource(ctx, "vendor-blog", "alice"))
+		_, err := uc.GetSource(ctx, "vendor-blog")
+		gt.True(t, errors.Is(err, interfaces.ErrSourceNotFound))
+		gt.True(t, errors.Is(uc.DeleteSource(ctx, "vendor-blog", "alice"), interfaces.ErrSourceNotFound))
+
+		changes, err := uc.ListSourceChanges(ctx, "vendor-blog", 1)
+		gt.NoError(t, err)
+		gt.A(t, changes).Length(1).At(0, func(t testing.TB, c *model.SourceChange) {
+			gt.Equal(t, c.Action, model.SourceChangeDelete)
+			gt.Nil(t, c.After)
+			gt.Equal(t, c.Before.URL, "https://blog.example/rss")
+		})
+	})
+}
+
+func TestSource_Import(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	uc := usecase.New(repo)
+
+	config := map[string]model.Source{
+		"vendor-blog": *newRSSSource("", "https://blog.example/feed"),
+		"urlhaus": {
+			Type:       model.SourceTypeFeed,
+			URL:        "https://urlhaus.example/csv",
+			Enabled:    true,
+			FeedConfig: &model.FeedConfig{Schema: "abuse_ch_urlhaus"},
+		},
+	}
+
+	result, err := uc.ImportSources(ctx, config, false)
+	gt.NoError(t, err)
+	gt.Equal(t, result.Created, []string{"urlhaus", "vendor-blog"})
+
+	stored, err := uc.GetSource(ctx, "urlhaus")
+	gt.NoError(t, err)
+	gt.Equal(t, stored.ID, "urlhaus")
+	gt.Equal(t, stored.FeedConfig.Schema, "abuse_ch_urlhaus")
+
+	changes, err := uc.ListSourceChanges(ctx, "urlhaus", 0)
+	gt.NoError(t, err)
+	gt.A(t, changes).Length(1).At(0, func(t testing.TB, c *model.SourceChange) {
+		gt.Equal(t, c.Action, model.SourceChangeImport)
+		gt.Equal(t, c.Actor, model.ActorConfig)
+	})
+
+	// A source changed through the API survives the import on restart
+	_, err = uc.SetSourceEnabled(ctx, "vendor-blog", false, "alice")
+	gt.NoError(t, err)
+
+	result, err = uc.ImportSources(ctx, config, false)
+	gt.NoError(t, err)
+	gt.A(t, result.Created).Length(0)
+	gt.Equal(t, result.Unchanged, []string{"urlhaus"})
+	gt.Equal(t, result.Skipped, []string{"vendor-blog"})
+	gt.Equal(t, result.Drift["vendor-blog"], []string{"enabled"})
+
+	src, err := uc.GetSource(ctx, "vendor-blog")
+	gt.NoError(t, err)
+	gt.False(t, src.Enabled)
+
+	// Overwrite replaces it with the config definition
+	result, err = uc.ImportSources(ctx, config, true)
+	gt.NoError(t, err)
+	gt.Equal(t, result.Updated, []string{"vendor-blog"})
+
+	src, err = uc.GetSource(ctx, "vendor-blog")
+	gt.NoError(t, err)
+	gt.True(t, src.Enabled)
+
+	t.Run("edited config is applied on the next import", func(t *testing.T) {
+		// As on each fetch run: the config is edited and imported again
+		edited := map[string]model.Source{"urlhaus": config["urlhaus"]}
+		feed := *config["urlhaus"].FeedConfig
+		feed.MaxItems = 100
+		src := edited["urlhaus"]
+		src.FeedConfig = &feed
+		src.Tags = []string{"blocklist"}
+		edited["urlhaus"] = src
+
+		result, err := uc.ImportSources(ctx, edited, false)
+		gt.NoError(t, err)
+		gt.Equal(t, result.Updated, []string{"urlhaus"})
+		gt.A(t, result.Skipped).Length(0)
+
+		stored, err := uc.GetSource(ctx, "urlhaus")
+		gt.NoError(t, err)
+		gt.Equal(t, stored.FeedConfig.MaxItems, 100)
+		gt.Equal(t, stored.Tags, []string{"blocklist"})
+		gt.Equal(t, stored.Origin, model.SourceOriginConfig)
+
+		// Once edited through the API, the stored source is kept
+		stored.Description = "edited by alice"
+		_, err = uc.UpdateSource(ctx, stored, "alice")
+		gt.NoError(t, err)
+
+		result, err = uc.ImportSources(ctx, edited, false)
+		gt.NoError(t, err)
+		gt.Equal(t, result.Skipped, []string{"urlhaus"})
+		gt.Equal(t, result.Drift["urlhaus"], []string{"description"})
+	})
+
+	t.Run("sources stored without origin are adopted when equal to the config", func(t *testing.T) {
+		legacy := newRSSSource("legacy", "https://legacy.example/feed")
+		gt.NoError(t, repo.PutSource(ctx, legacy))
+
+		imported := *newRSSSource("", "https://legacy.example/feed")
+		result, err := uc.ImportSources(ctx, map[string]model.Source{"legacy": imported}, false)
+		gt.NoError(t, err)
+		gt.Equal(t, result.Unchanged, []string{"legacy"})
+
+		imported.URL = "https://legacy.example/rss"
+		result, err = uc.ImportSources(ctx, map[string]model.Source{"legacy": imported}, false)
+		gt.NoError(t, err)
+		gt.Equal(t, result.Updated, []string{"legacy"})
+	})
+
+	t.Run("invalid sources are rejected", func(t *testing.T) {
+		_, err := uc.ImportSources(ctx, map[string]model.Source{"broken": {Type: "ftp"}}, false)
+		gt.Error(t, err)
+	})
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+)
+
+// SweepExpiredIoCs marks active IoCs whose TTL has elapsed as expired and
+// records the transitions. IoCs whose status is pinned by an analyst override
+// are left untouched. Returns the number of expired IoCs.
+func (uc *UseCases) SweepExpiredIoCs(ctx context.Context, now time.Time) (int, error) {
+	logger := logging.From(ctx)
+
+	expired, err := uc.repo.ListExpiredIoCs(ctx, now)
+	if err != nil {
+		return 0, goerr.Wrap(err, "failed to list expired IoCs")
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	for _, ioc := range expired {
+		ioc.Status = model.IoCStatusExpired
+	}
+
+	result, err := uc.repo.BatchUpsertIoCs(ctx, expired)
+	if err != nil {
+		return 0, goerr.Wrap(err, "failed to mark IoCs as expired",
+			goerr.V("count", len(expired)))
+	}
+
+	for _, tr := range result.StatusChanges {
+		tr.Actor = model.ActorSweeper
+		tr.Reason = "TTL expired"
+	}
+	if err := uc.repo.SaveStatusTransitions(ctx, result.StatusChanges); err != nil {
+		return 0, goerr.Wrap(err, "failed to save status transitions",
+			goerr.V("count", len(result.StatusChanges)))
+	}
+
+	logger.Info("swept expired IoCs",
+		"candidates", len(expired),
+		"expired", len(result.StatusChanges))
+
+	return len(result.StatusChanges), nil
+}
+
+// RunSweeper runs SweepExpiredIoCs and RescoreIoCs every interval until ctx is canceled
+func (uc *UseCases) RunSweeper(ctx context.Context, interval time.Duration) {
+	logger := logging.From(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now()
+			if _, err := uc.SweepExpiredIoCs(ctx, now); err != nil {
+				logger.Error("failed to sweep expired IoCs", "error", err)
+			}
+			if _, err := uc.RescoreIoCs(ctx, now); err != nil {
+				logger.Error("failed to rescore IoCs", "error", err)
+			}
+		}
+	}
+}
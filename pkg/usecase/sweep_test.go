@@ -0,0 +1,126 @@
+package usecase_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+func TestUseCases_SweepExpiredIoCs(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	uc := usecase.New(repo)
+	now := time.Now()
+
+	newIoC := func(value string, expiresAt time.Time) *model.IoC {
+		return &model.IoC{
+			ID:         model.GenerateID("feed-a", model.IoCTypeIPv4, value, ""),
+			SourceID:   "feed-a",
+			SourceType: string(model.SourceTypeFeed),
+			Type:       model.IoCTypeIPv4,
+			Value:      value,
+			Status:     model.IoCStatusActive,
+			ExpiresAt:  expiresAt,
+		}
+	}
+	expired := newIoC("198.51.100.30", now.Add(-time.Minute))
+	fresh := newIoC("198.51.100.31", now.Add(time.Hour))
+	pinned := newIoC("198.51.100.32", now.Add(-time.Minute))
+	_, err := repo.BatchUpsertIoCs(ctx, []*model.IoC{expired, fresh, pinned})
+	gt.NoError(t, err)
+
+	// Analyst pins the status of one IoC as active
+	status := model.IoCStatusActive
+	_, err = uc.UpdateIoC(ctx, pinned.ID, &usecase.UpdateIoCInput{Status: &status})
+	gt.NoError(t, err)
+
+	count, err := uc.SweepExpiredIoCs(ctx, now)
+	gt.NoError(t, err)
+	gt.Equal(t, count, 1)
+
+	got, err := repo.GetIoC(ctx, expired.ID)
+	gt.NoError(t, err)
+	gt.Equal(t, got.Status, model.IoCStatusExpired)
+
+	got, err = repo.GetIoC(ctx, fresh.ID)
+	gt.NoError(t, err)
+	gt.Equal(t, got.Status, model.IoCStatusActive)
+
+	got, err = repo.GetIoC(ctx, pinned.ID)
+	gt.NoError(t, err)
+	gt.Equal(t, got.Status, model.IoCStatusActive)
+
+	transitions, err := repo.ListStatusTransitions(ctx, expired.ID)
+	gt.NoError(t, err)
+	gt.A(t, transitions).Length(1).At(0, func(t testing.TB, tr *model.IoCStatusTransition) {
+		gt.Equal(t, tr.From, model.IoCStatusActive)
+		gt.Equal(t, tr.To, model.IoCStatusExpired)
+		gt.Equal(t, tr.Actor, model.ActorSweeper)
+	})
+
+	// Second sweep finds nothing new
+	count, err = uc.SweepExpiredIoCs(ctx, now)
+	gt.NoError(t, err)
+	gt.Equal(t, count, 0)
+}
+
+func TestUseCases_SweepKeepsResightedIoCs(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(threatFoxCSV))
+	}))
+	defer server.Close()
+
+	repo := memory.New()
+	uc := usecase.New(repo)
+	fetchUC := usecase.NewFetchUseCase(repo, nil)
+
+	ttl := time.Hour
+	sources := map[string]model.Source{
+		"feed-a": {
+			Type:       model.SourceTypeFeed,
+			URL:        server.URL,
+			Enabled:    true,
+			FeedConfig: &model.FeedConfig{Schema: "abuse_ch_threatfox"},
+			TTL:        &ttl,
+		},
+	}
+
+	_, err := fetchUC.FetchSourceByID(ctx, sources, "feed-a")
+	gt.NoError(t, err)
+
+	// Pretend the first fetch happened long enough ago for the IoC to expire
+	iocs, err := repo.ListIoCsBySource(ctx, "feed-a")
+	gt.NoError(t, err)
+	gt.A(t, iocs).Length(1)
+	stale := iocs[0]
+	stale.LastSeenAt = time.Now().Add(-2 * ttl)
+	stale.ExpiresAt = stale.LastSeenAt.Add(ttl)
+	gt.NoError(t, repo.PutIoC(ctx, stale))
+
+	// The feed still reports the IoC unchanged
+	_, err = fetchUC.FetchSourceByID(ctx, sources, "feed-a")
+	gt.NoError(t, err)
+
+	got, err := repo.GetIoC(ctx, stale.ID)
+	gt.NoError(t, err)
+	gt.True(t, got.ExpiresAt.After(time.Now()))
+	gt.True(t, got.LastSeenAt.After(stale.LastSeenAt))
+	gt.True(t, got.UpdatedAt.After(stale.LastSeenAt))
+
+	count, err := uc.SweepExpiredIoCs(ctx, time.Now())
+	gt.NoError(t, err)
+	gt.Equal(t, count, 0)
+
+	got, err = repo.GetIoC(ctx, stale.ID)
+	gt.NoError(t, err)
+	gt.Equal(t, got.Status, model.IoCStatusActive)
+}
@@ -0,0 +1,89 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/instrumented"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider recording spans until the test ends
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		_ = provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func TestFetchUseCase_Tracing(t *testing.T) {
+	exporter := recordSpans(t)
+	ctx := context.Background()
+	server := newBlogServer(t, `The payload is downloaded from hxxp://cdn.evil-domain[.]ru/malware.exe`)
+
+	repo := memory.New()
+	fetchUC := usecase.NewFetchUseCase(instrumented.New(repo, nil), nil)
+
+	histories, err := fetchUC.FetchAllSources(ctx, map[string]model.Source{
+		"blog": {
+			Type:      model.SourceTypeRSS,
+			URL:       server.URL + "/feed",
+			Enabled:   true,
+			RSSConfig: &model.RSSConfig{Extraction: model.ExtractionModeRegex},
+		},
+	}, nil)
+	gt.NoError(t, err)
+	gt.A(t, histories).Length(1)
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	root, ok := spans["fetch.all_sources"]
+	gt.True(t, ok)
+	gt.False(t, root.Parent.IsValid())
+
+	childOf := func(name, parent string) {
+		t.Helper()
+		span, ok := spans[name]
+		gt.True(t, ok).Describef("span %s not recorded", name)
+		gt.Equal(t, span.Parent.SpanID(), spans[parent].SpanContext.SpanID())
+		gt.Equal(t, span.SpanContext.TraceID(), root.SpanContext.TraceID())
+	}
+	childOf("fetch.source", "fetch.all_sources")
+	childOf("fetch.article", "fetch.source")
+	childOf("embedding.embed", "fetch.source")
+	childOf("repository.BatchUpsertIoCs", "fetch.source")
+	childOf("repository.SaveHistory", "fetch.source")
+
+	// The history links to the trace of the fetch
+	gt.Equal(t, histories[0].TraceID, root.SpanContext.TraceID().String())
+	saved, err := repo.GetHistory(ctx, "blog", histories[0].ID)
+	gt.NoError(t, err)
+	gt.Equal(t, saved.TraceID, histories[0].TraceID)
+}
+
+func TestFetchUseCase_TracingDisabled(t *testing.T) {
+	ctx := context.Background()
+	uc := usecase.NewFetchUseCase(memory.New(), nil)
+
+	histories, err := uc.FetchAllSources(ctx, map[string]model.Source{
+		"bad-feed": {Type: model.SourceTypeFeed, URL: "http://example.com/feed", Enabled: true},
+	}, nil)
+	gt.NoError(t, err)
+	gt.A(t, histories).Length(1)
+	gt.Equal(t, histories[0].TraceID, "")
+}
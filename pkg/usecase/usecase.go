@@ -2,14 +2,52 @@ package usecase
 
 import (
 	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
 )
 
 type UseCases struct {
-	repo interfaces.Repository
+	repo             interfaces.Repository
+	confidencePolicy *model.ConfidencePolicy
+	embedder         interfaces.Embedder
+	audit            *Auditor
+	openAccess       bool
 }
 
-func New(repo interfaces.Repository) *UseCases {
-	return &UseCases{
-		repo: repo,
+// Option configures UseCases
+type Option func(*UseCases)
+
+// WithConfidencePolicy sets the policy used to score IoCs
+func WithConfidencePolicy(policy *model.ConfidencePolicy) Option {
+	return func(uc *UseCases) {
+		uc.confidencePolicy = policy
+	}
+}
+
+// WithAuditor sets the auditor of changes (default: records in the repository only)
+func WithAuditor(auditor *Auditor) Option {
+	return func(uc *UseCases) {
+		uc.audit = auditor
+	}
+}
+
+// WithOpenAccess tells that the server serves every request without
+// authentication. API tokens are not checked then, so none can be minted.
+func WithOpenAccess() Option {
+	return func(uc *UseCases) {
+		uc.openAccess = true
 	}
 }
+
+func New(repo interfaces.Repository, opts ...Option) *UseCases {
+	uc := &UseCases{
+		repo:             repo,
+		confidencePolicy: model.NewConfidencePolicy(nil),
+		audit:            NewAuditor(repo),
+	}
+
+	for _, opt := range opts {
+		opt(uc)
+	}
+
+	return uc
+}
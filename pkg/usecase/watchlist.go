@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/secmon-lab/beehive/pkg/domain/interfaces"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/utils/logging"
+)
+
+var (
+	// ErrWatchlistNameConflict is returned when a watchlist with the same name exists
+	ErrWatchlistNameConflict = goerr.New("watchlist name already exists")
+)
+
+// WithASNResolver sets the resolver mapping IoC hosts to ASNs, so that ASN
+// watch patterns match IPs, domains and URLs (default: none)
+func WithASNResolver(resolver interfaces.ASNResolver) FetchOption {
+	return func(uc *FetchUseCase) {
+		uc.asn = resolver
+	}
+}
+
+// CreateWatchlistInput represents a new watchlist
+type CreateWatchlistInput struct {
+	Name        string
+	Description string
+	Patterns    []model.WatchPattern
+	Enabled     *bool // Defaults to true
+}
+
+// UpdateWatchlistInput represents changes to a watchlist. Nil fields are left unchanged.
+type UpdateWatchlistInput struct {
+	Name        *string
+	Description *string
+	Patterns    []model.WatchPattern // nil = unchanged
+	Enabled     *bool
+}
+
+// CreateWatchlist registers a watchlist
+func (uc *UseCases) CreateWatchlist(ctx context.Context, input *CreateWatchlistInput) (*model.Watchlist, error) {
+	now := time.Now()
+	w := &model.Watchlist{
+		ID:          model.NewWatchlistID(),
+		Name:        strings.TrimSpace(input.Name),
+		Description: input.Description,
+		Patterns:    append([]model.WatchPattern(nil), input.Patterns...),
+		Enabled:     input.Enabled == nil || *input.Enabled,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := w.Validate(); err != nil {
+		return nil, goerr.Wrap(err, "invalid watchlist")
+	}
+	if err := uc.checkWatchlistName(ctx, w); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.PutWatchlist(ctx, w); err != nil {
+		return nil, goerr.Wrap(err, "failed to save watchlist", goerr.V("id", w.ID))
+	}
+	uc.audit.Record(ctx, model.ActorFrom(ctx), model.AuditWatchlistCreate, model.AuditTargetWatchlist, w.ID, nil, w)
+	return w, nil
+}
+
+// UpdateWatchlist changes a watchlist. Existing hits are kept.
+func (uc *UseCases) UpdateWatchlist(ctx context.Context, id string, input *UpdateWatchlistInput) (*model.Watchlist, error) {
+	w, err := uc.repo.GetWatchlist(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get watchlist", goerr.V("id", id))
+	}
+	before := *w
+
+	if input.Name != nil {
+		w.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Description != nil {
+		w.Description = *input.Description
+	}
+	if input.Patterns != nil {
+		w.Patterns = append([]model.WatchPattern(nil), input.Patterns...)
+	}
+	if input.Enabled != nil {
+		w.Enabled = *input.Enabled
+	}
+	w.UpdatedAt = time.Now()
+
+	if err := w.Validate(); err != nil {
+		return nil, goerr.Wrap(err, "invalid watchlist", goerr.V("id", id))
+	}
+	if err := uc.checkWatchlistName(ctx, w); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.PutWatchlist(ctx, w); err != nil {
+		return nil, goerr.Wrap(err, "failed to save watchlist", goerr.V("id", id))
+	}
+	uc.audit.Record(ctx, model.ActorFrom(ctx), model.AuditWatchlistUpdate, model.AuditTargetWatchlist, id, &before, w)
+	return w, nil
+}
+
+// DeleteWatchlist deletes a watchlist. Its hits are kept.
+func (uc *UseCases) DeleteWatchlist(ctx context.Context, id string) error {
+	before, err := uc.repo.GetWatchlist(ctx, id)
+	if err != nil {
+		return goerr.Wrap(err, "failed to get watchlist", goerr.V("id", id))
+	}
+	if err := uc.repo.DeleteWatchlist(ctx, id); err != nil {
+		return goerr.Wrap(err, "failed to delete watchlist", goerr.V("id", id))
+	}
+	uc.audit.Record(ctx, model.ActorFrom(ctx), model.AuditWatchlistDelete, model.AuditTargetWatchlist, id, before, nil)
+	return nil
+}
+
+// AcknowledgeWatchlistHits marks watchlist hits as reviewed
+func (uc *UseCases) AcknowledgeWatchlistHits(ctx context.Context, ids []string, actor string) ([]*model.WatchlistHit, error) {
+	if len(ids) == 0 {
+		return nil, goerr.New("no watchlist hit IDs given")
+	}
+
+	hits, err := uc.repo.AcknowledgeWatchlistHits(ctx, ids, actor, time.Now())
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to acknowledge watchlist hits", goerr.V("ids", ids))
+	}
+	for _, hit := range hits {
+		uc.audit.Record(ctx, actor, model.AuditWatchlistHitAcknowledge, model.AuditTargetWatchlistHit, hit.ID, nil, hit)
+	}
+	return hits, nil
+}
+
+// checkWatchlistName ensures no other watchlist has the same name
+func (uc *UseCases) checkWatchlistName(ctx context.Context, w *model.Watchlist) error {
+	existing, err := uc.repo.ListWatchlists(ctx)
+	if err != nil {
+		return goerr.Wrap(err, "failed to list watchlists")
+	}
+	for _, other := range existing {
+		if other.ID != w.ID && strings.EqualFold(other.Name, w.Name) {
+			return goerr.Wrap(ErrWatchlistNameConflict, "watchlist name already exists",
+				goerr.V("name", w.Name),
+				goerr.V("existing_id", other.ID))
+		}
+	}
+	return nil
+}
+
+// evaluateWatchlists matches newly ingested IoCs against the enabled watchlists
+// and stores the hits. Failures are logged and yield no hits.
+func (uc *FetchUseCase) evaluateWatchlists(ctx context.Context, sourceID string, iocs []*model.IoC) []*model.WatchlistHit {
+	if len(iocs) == 0 {
+		return nil
+	}
+	logger := logging.From(ctx)
+
+	watchlists, err := uc.repo.ListWatchlists(ctx)
+	if err != nil {
+		logger.Error("failed to list watchlists", "source_id", sourceID, "error", err)
+		return nil
+	}
+	matcher, err := model.NewWatchlistMatcher(watchlists)
+	if err != nil {
+		logger.Error("failed to compile watchlists", "source_id", sourceID, "error", err)
+		return nil
+	}
+	if matcher.Empty() {
+		return nil
+	}
+
+	lookupASNs := uc.hostASNLookup(ctx, sourceID, matcher)
+	now := time.Now()
+	var hits []*model.WatchlistHit
+	for _, ioc := range iocs {
+		hits = append(hits, matcher.Match(ioc, lookupASNs(ioc), now)...)
+	}
+	if len(hits) == 0 {
+		return nil
+	}
+
+	stored, err := uc.repo.SaveWatchlistHits(ctx, hits)
+	if err != nil {
+		logger.Error("failed to save watchlist hits",
+			"source_id", sourceID,
+			"hits", len(hits),
+			"error", err)
+	}
+
+	logger.Info("watchlist hits found",
+		"source_id", sourceID,
+		"hits", len(stored))
+	return stored
+}
+
+// hostASNLookup returns a function looking up the ASNs of IoC hosts, cached per
+// host for a single evaluation. It returns nil ASNs if no resolver is set or no
+// watchlist has an ASN pattern. Lookup failures are logged and yield no ASNs.
+func (uc *FetchUseCase) hostASNLookup(ctx context.Context, sourceID string, matcher *model.WatchlistMatcher) func(*model.IoC) []uint32 {
+	if uc.asn == nil || !matcher.HasASNPatterns() {
+		return func(*model.IoC) []uint32 { return nil }
+	}
+
+	cache := make(map[string][]uint32)
+	return func(ioc *model.IoC) []uint32 {
+		host := ioc.Host()
+		if host == "" {
+			return nil
+		}
+		if asns, ok := cache[host]; ok {
+			return asns
+		}
+		asns, err := uc.asn.LookupASNs(ctx, host)
+		if err != nil {
+			logging.From(ctx).Warn("failed to look up ASNs of IoC host",
+				"source_id", sourceID,
+				"host", host,
+				"error", err)
+		}
+		cache[host] = asns
+		return asns
+	}
+}
@@ -0,0 +1,216 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/secmon-lab/beehive/pkg/domain/model"
+	"github.com/secmon-lab/beehive/pkg/repository/memory"
+	"github.com/secmon-lab/beehive/pkg/service/notify"
+	"github.com/secmon-lab/beehive/pkg/usecase"
+)
+
+func TestWatchlist_CRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	uc := usecase.New(repo)
+
+	created, err := uc.CreateWatchlist(ctx, &usecase.CreateWatchlistInput{
+		Name:     "corp-network",
+		Patterns: []model.WatchPattern{{Kind: model.WatchPatternCIDR, Value: "198.51.100.0/24"}},
+	})
+	gt.NoError(t, err)
+	gt.True(t, created.Enabled)
+
+	t.Run("names are unique regardless of case", func(t *testing.T) {
+		_, err := uc.CreateWatchlist(ctx, &usecase.CreateWatchlistInput{
+			Name:     "Corp-Network",
+			Patterns: []model.WatchPattern{{Kind: model.WatchPatternKeyword, Value: "acme"}},
+		})
+		gt.True(t, errors.Is(err, usecase.ErrWatchlistNameConflict))
+	})
+
+	t.Run("invalid patterns are rejected", func(t *testing.T) {
+		_, err := uc.CreateWatchlist(ctx, &usecase.CreateWatchlistInput{
+			Name:     "broken",
+			Patterns: []model.WatchPattern{{Kind: model.WatchPatternCIDR, Value: "198.51.100.0/99"}},
+		})
+		gt.Error(t, err)
+	})
+
+	t.Run("update keeps unspecified fields", func(t *testing.T) {
+		disabled := false
+		desc := "office ranges"
+		updated, err := uc.UpdateWatchlist(ctx, created.ID, &usecase.UpdateWatchlistInput{
+			Description: &desc,
+			Enabled:     &disabled,
+		})
+		gt.NoError(t, err)
+		gt.Equal(t, updated.Name, "corp-network")
+		gt.Equal(t, updated.Description, "office ranges")
+		gt.False(t, updated.Enabled)
+		gt.A(t, updated.Patterns).Length(1)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		gt.NoError(t, uc.DeleteWatchlist(ctx, created.ID))
+		gt.Error(t, uc.DeleteWatchlist(ctx, created.ID))
+	})
+}
+
+func TestWatchlist_HitsOnFetch(t *testing.T) {
+	ctx := context.Background()
+
+	feedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(threatFoxCSV))
+	}))
+	defer feedServer.Close()
+
+	repo := memory.New()
+	uc := usecase.New(repo)
+	watchlist, err := uc.CreateWatchlist(ctx, &usecase.CreateWatchlistInput{
+		Name:     "corp-network",
+		Patterns: []model.WatchPattern{{Kind: model.WatchPatternCIDR, Value: "198.51.100.0/24"}},
+	})
+	gt.NoError(t, err)
+
+	recv := newReceiver(t)
+	rule := &model.NotificationRule{
+		Name:       "assets",
+		Kind:       model.NotificationRuleWatchlist,
+		Channels:   []string{"hook"},
+		Watchlists: []string{"corp-network"},
+	}
+	gt.NoError(t, rule.Validate())
+	fetchUC := usecase.NewFetchUseCase(repo, nil, usecase.WithNotifier(newNotifier(repo, recv, rule)))
+
+	sources := map[string]model.Source{
+		"threatfox": {
+			Type:       model.SourceTypeFeed,
+			URL:        feedServer.URL,
+			Enabled:    true,
+			FeedConfig: &model.FeedConfig{Schema: "abuse_ch_threatfox"},
+		},
+	}
+
+	history, err := fetchUC.FetchSourceByID(ctx, sources, "threatfox")
+	gt.NoError(t, err)
+	gt.Equal(t, history.WatchlistHits, 1)
+
+	conn, err := repo.ListWatchlistHits(ctx, &model.WatchlistHitListOptions{WatchlistID: watchlist.ID})
+	gt.NoError(t, err)
+	gt.Equal(t, conn.Total, 1)
+	hit := conn.Items[0]
+	gt.Equal(t, hit.IoCValue, "198.51.100.50:51515")
+	gt.Equal(t, hit.Pattern, "198.51.100.0/24")
+	gt.Equal(t, hit.SourceID, "threatfox")
+	gt.False(t, hit.Acknowledged)
+
+	gt.A(t, recv.received()).Length(1).At(0, func(t testing.TB, p notify.WebhookPayload) {
+		gt.Equal(t, p.Rule, "assets")
+		gt.A(t, p.IoCs).Length(1)
+	})
+
+	// Re-observed IoCs do not produce new hits or notifications
+	history, err = fetchUC.FetchSourceByID(ctx, sources, "threatfox")
+	gt.NoError(t, err)
+	gt.Equal(t, history.WatchlistHits, 0)
+	gt.A(t, recv.received()).Length(1)
+
+	acked, err := uc.AcknowledgeWatchlistHits(ctx, []string{hit.ID}, model.ActorAnalyst)
+	gt.NoError(t, err)
+	gt.A(t, acked).Length(1).At(0, func(t testing.TB, h *model.WatchlistHit) {
+		gt.True(t, h.Acknowledged)
+		gt.Equal(t, h.AcknowledgedBy, model.ActorAnalyst)
+	})
+}
+
+type fakeASNResolver struct {
+	asns    map[string][]uint32
+	lookups []string
+}
+
+func (f *fakeASNResolver) LookupASNs(_ context.Context, host string) ([]uint32, error) {
+	f.lookups = append(f.lookups, host)
+	if asns, ok := f.asns[host]; ok {
+		return asns, nil
+	}
+	return nil, errors.New("lookup failed")
+}
+
+func TestWatchlist_HostASNOnFetch(t *testing.T) {
+	ctx := context.Background()
+
+	feedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(threatFoxCSV))
+	}))
+	defer feedServer.Close()
+
+	sources := map[string]model.Source{
+		"threatfox": {
+			Type:       model.SourceTypeFeed,
+			URL:        feedServer.URL,
+			Enabled:    true,
+			FeedConfig: &model.FeedConfig{Schema: "abuse_ch_threatfox"},
+		},
+	}
+
+	t.Run("ip announced by a watched ASN", func(t *testing.T) {
+		repo := memory.New()
+		uc := usecase.New(repo)
+		watchlist, err := uc.CreateWatchlist(ctx, &usecase.CreateWatchlistInput{
+			Name:     "hosting",
+			Patterns: []model.WatchPattern{{Kind: model.WatchPatternASN, Value: "AS64500"}},
+		})
+		gt.NoError(t, err)
+
+		resolver := &fakeASNResolver{asns: map[string][]uint32{"198.51.100.50": {64500}}}
+		fetchUC := usecase.NewFetchUseCase(repo, nil, usecase.WithASNResolver(resolver))
+		history, err := fetchUC.FetchSourceByID(ctx, sources, "threatfox")
+		gt.NoError(t, err)
+		gt.Equal(t, history.WatchlistHits, 1)
+		gt.A(t, resolver.lookups).Equal([]string{"198.51.100.50"})
+
+		conn, err := repo.ListWatchlistHits(ctx, &model.WatchlistHitListOptions{WatchlistID: watchlist.ID})
+		gt.NoError(t, err)
+		gt.Equal(t, conn.Total, 1)
+		gt.Equal(t, conn.Items[0].MatchedField, model.WatchFieldHostASN)
+		gt.Equal(t, conn.Items[0].Pattern, "64500")
+	})
+
+	t.Run("lookup failure yields no hit", func(t *testing.T) {
+		repo := memory.New()
+		uc := usecase.New(repo)
+		_, err := uc.CreateWatchlist(ctx, &usecase.CreateWatchlistInput{
+			Name:     "hosting",
+			Patterns: []model.WatchPattern{{Kind: model.WatchPatternASN, Value: "AS64500"}},
+		})
+		gt.NoError(t, err)
+
+		resolver := &fakeASNResolver{}
+		fetchUC := usecase.NewFetchUseCase(repo, nil, usecase.WithASNResolver(resolver))
+		history, err := fetchUC.FetchSourceByID(ctx, sources, "threatfox")
+		gt.NoError(t, err)
+		gt.Equal(t, history.WatchlistHits, 0)
+	})
+
+	t.Run("no lookup without ASN patterns", func(t *testing.T) {
+		repo := memory.New()
+		uc := usecase.New(repo)
+		_, err := uc.CreateWatchlist(ctx, &usecase.CreateWatchlistInput{
+			Name:     "keywords",
+			Patterns: []model.WatchPattern{{Kind: model.WatchPatternKeyword, Value: "acme"}},
+		})
+		gt.NoError(t, err)
+
+		resolver := &fakeASNResolver{}
+		fetchUC := usecase.NewFetchUseCase(repo, nil, usecase.WithASNResolver(resolver))
+		_, err = fetchUC.FetchSourceByID(ctx, sources, "threatfox")
+		gt.NoError(t, err)
+		gt.A(t, resolver.lookups).Length(0)
+	})
+}